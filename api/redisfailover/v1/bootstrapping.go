@@ -5,8 +5,17 @@ func (r *RedisFailover) Bootstrapping() bool {
 	return r.Spec.BootstrapNode != nil
 }
 
-// SentinelsAllowed returns true if not Bootstrapping orif BootstrapNode settings allow sentinels to exist
+// Replicating returns true when the RedisFailover replicates another RedisFailover and has not been promoted yet
+func (r *RedisFailover) Replicating() bool {
+	return r.Spec.ReplicaOf != nil && !r.Spec.ReplicaOf.Promote
+}
+
+// SentinelsAllowed returns true if not Bootstrapping orif BootstrapNode settings allow sentinels to exist.
+// A replicating RedisFailover has no sentinels until it is promoted
 func (r *RedisFailover) SentinelsAllowed() bool {
+	if r.Replicating() {
+		return false
+	}
 	bootstrapping := r.Bootstrapping()
 	return !bootstrapping || (bootstrapping && r.Spec.BootstrapNode.AllowSentinels)
 }
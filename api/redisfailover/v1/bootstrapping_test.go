@@ -83,3 +83,39 @@ func TestSentinelsAllowed(t *testing.T) {
 		})
 	}
 }
+
+func TestReplicating(t *testing.T) {
+	tests := []struct {
+		name              string
+		replicaOf         *ReplicaOfSettings
+		expectReplicating bool
+		expectSentinels   bool
+	}{
+		{
+			name:              "without ReplicaOfSettings",
+			expectReplicating: false,
+			expectSentinels:   true,
+		},
+		{
+			name:              "with ReplicaOfSettings",
+			replicaOf:         &ReplicaOfSettings{RedisFailoverRef: RedisFailoverReference{Name: "source"}},
+			expectReplicating: true,
+			expectSentinels:   false,
+		},
+		{
+			name:              "with promoted ReplicaOfSettings",
+			replicaOf:         &ReplicaOfSettings{RedisFailoverRef: RedisFailoverReference{Name: "source"}, Promote: true},
+			expectReplicating: false,
+			expectSentinels:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rf := generateRedisFailover("test", nil)
+			rf.Spec.ReplicaOf = test.replicaOf
+			assert.Equal(t, test.expectReplicating, rf.Replicating())
+			assert.Equal(t, test.expectSentinels, rf.SentinelsAllowed())
+		})
+	}
+}
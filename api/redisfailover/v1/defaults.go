@@ -7,6 +7,22 @@ const (
 	defaultExporterImage         = "quay.io/oliver006/redis_exporter:v1.43.0"
 	defaultImage                 = "redis:6.2.6-alpine"
 	defaultRedisPort             = 6379
+	defaultSentinelPort          = 26379
+	defaultSentinelMasterName    = "master0"
+	defaultProxyType             = ProxyTypePredixy
+	defaultEnvoyImage            = "envoyproxy/envoy:v1.24.1"
+	defaultEnvoyNumber           = 2
+	defaultBootstrapMasterName   = "mymaster"
+	defaultMonitorType           = MonitorTypeServiceMonitor
+	defaultNoMasterFor           = "1m"
+	defaultReplicaLinkDownFor    = "5m"
+	defaultSentinelQuorumLostFor = "5m"
+	defaultMemoryUsagePercent    = 90
+	defaultMemoryUsageFor        = "5m"
+	defaultClusterErrorFor       = "10m"
+	defaultOperatorLabelKey      = "app"
+	defaultOperatorLabelValue    = "redisoperator"
+	defaultZoneTopologyKey       = "topology.kubernetes.io/zone"
 )
 
 var (
@@ -0,0 +1,10 @@
+package v1
+
+// LogLevelAnnotation is the annotation that sets the level of the lines the operator logs for a RedisFailover,
+// overriding the level of the operator to debug a single one
+const LogLevelAnnotation = "redisfailovers.databases.spotahome.com/log-level"
+
+// LogLevel returns the level the operator logs at for the RedisFailover, empty for the level of the operator
+func (r *RedisFailover) LogLevel() string {
+	return r.Annotations[LogLevelAnnotation]
+}
@@ -0,0 +1,10 @@
+package v1
+
+// PausedAnnotation is the annotation that, set to "true" on a RedisFailover, makes the operator leave it untouched
+// until it is removed, for maintenance done by hand
+const PausedAnnotation = "redisfailovers.databases.spotahome.com/paused"
+
+// Paused returns true when the operator has to leave the RedisFailover untouched
+func (r *RedisFailover) Paused() bool {
+	return r.Annotations[PausedAnnotation] == "true"
+}
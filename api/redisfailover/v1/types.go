@@ -3,6 +3,7 @@ package v1
 import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // +genclient
@@ -14,10 +15,12 @@ import (
 // +kubebuilder:printcolumn:name="SENTINELS",type="integer",JSONPath=".spec.sentinel.replicas"
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:resource:singular=redisfailover,path=redisfailovers,shortName=rf,scope=Namespaced
+// +kubebuilder:subresource:status
 type RedisFailover struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
-	Spec              RedisFailoverSpec `json:"spec"`
+	Spec              RedisFailoverSpec   `json:"spec"`
+	Status            RedisFailoverStatus `json:"status,omitempty"`
 }
 
 // RedisFailoverSpec represents a Redis failover spec
@@ -29,6 +32,51 @@ type RedisFailoverSpec struct {
 	LabelWhitelist []string           `json:"labelWhitelist,omitempty"`
 	BootstrapNode  *BootstrapSettings `json:"bootstrapNode,omitempty"`
 	Predixy        PredixySettings    `json:"predixy,omitempty"`
+	Proxy          ProxySettings      `json:"proxy,omitempty"`
+	// AnnounceHostnames makes redis and sentinel announce and monitor the stable DNS names the
+	// headless services give to their pods instead of the pod IPs
+	AnnounceHostnames bool                    `json:"announceHostnames,omitempty"`
+	ExternalAccess    *ExternalAccessSettings `json:"externalAccess,omitempty"`
+	ReplicaOf         *ReplicaOfSettings      `json:"replicaOf,omitempty"`
+	Monitoring        *MonitoringSettings     `json:"monitoring,omitempty"`
+	NetworkPolicy     *NetworkPolicySettings  `json:"networkPolicy,omitempty"`
+}
+
+// RedisFailoverStatus represents the observed state of a Redis failover
+type RedisFailoverStatus struct {
+	PasswordRotation *PasswordRotationStatus `json:"passwordRotation,omitempty"`
+	// ExternalMaster is the host:port of the master outside the failover the redises were last set to replicate by
+	// bootstrapNode or replicaOf. It is cleared once the redises are detached from it after leaving these modes
+	ExternalMaster string `json:"externalMaster,omitempty"`
+	// Switchover is the switchover of the master in flight, the master and its replicas are left to sentinel
+	// until it ends
+	Switchover *SwitchoverStatus `json:"switchover,omitempty"`
+}
+
+// SwitchoverStatus records a switchover of the master, so the replicas kept from being promoted get their
+// replica-priority back even if the operator restarts before it ends
+type SwitchoverStatus struct {
+	// ReplicaPriorities are the replica-priority the replicas kept from being promoted had, by address
+	ReplicaPriorities map[string]string `json:"replicaPriorities,omitempty"`
+	StartTime         metav1.Time       `json:"startTime,omitempty"`
+}
+
+// PasswordRotationPhase is the step a rotation of the auth password is at
+type PasswordRotationPhase string
+
+const (
+	// PasswordRotationInProgress means the redises accept both the previous and the new password
+	PasswordRotationInProgress PasswordRotationPhase = "InProgress"
+	// PasswordRotationCompleted means the previous password was dropped
+	PasswordRotationCompleted PasswordRotationPhase = "Completed"
+)
+
+// PasswordRotationStatus reports the progress of the rotation of the auth password, started by adding
+// the current password to the auth secret as previousPassword along with the new one
+type PasswordRotationStatus struct {
+	Phase              PasswordRotationPhase `json:"phase,omitempty"`
+	Message            string                `json:"message,omitempty"`
+	LastTransitionTime metav1.Time           `json:"lastTransitionTime,omitempty"`
 }
 
 // RedisCommandRename defines the specification of a "rename-command" configuration option
@@ -63,6 +111,8 @@ type RedisSettings struct {
 	NodeSelector                  map[string]string                 `json:"nodeSelector,omitempty"`
 	PodAnnotations                map[string]string                 `json:"podAnnotations,omitempty"`
 	ServiceAnnotations            map[string]string                 `json:"serviceAnnotations,omitempty"`
+	MasterService                 RedisRoleServiceSettings          `json:"masterService,omitempty"`
+	ReplicasService               RedisRoleServiceSettings          `json:"replicasService,omitempty"`
 	HostNetwork                   bool                              `json:"hostNetwork,omitempty"`
 	DNSPolicy                     corev1.DNSPolicy                  `json:"dnsPolicy,omitempty"`
 	PriorityClassName             string                            `json:"priorityClassName,omitempty"`
@@ -71,6 +121,34 @@ type RedisSettings struct {
 	ExtraVolumes                  []corev1.Volume                   `json:"extraVolumes,omitempty"`
 	ExtraVolumeMounts             []corev1.VolumeMount              `json:"extraVolumeMounts,omitempty"`
 	StoragePath                   string                            `json:"storagePath,omitempty"` // stroage path on the host
+	PodDisruptionBudget           PodDisruptionBudgetSettings       `json:"pdb,omitempty"`
+	Zones                         *ZonesSettings                    `json:"zones,omitempty"`
+}
+
+// ZonesSettings places the redises in one StatefulSet per zone, spreading the replicas evenly across them, and
+// the sentinels across the same zones. The zones can't be set, unset or changed on an existing RedisFailover
+type ZonesSettings struct {
+	// Names are the values the TopologyKey label of the nodes of the zones takes
+	Names []string `json:"names"`
+	// TopologyKey is the label of the nodes holding their zone, topology.kubernetes.io/zone when empty
+	TopologyKey string `json:"topologyKey,omitempty"`
+	// Primary is the zone the replicas promoted on a failover are preferred from, the first one when empty
+	Primary string `json:"primary,omitempty"`
+}
+
+// PodDisruptionBudgetSettings defines the PodDisruptionBudget of a component. MinAvailable and MaxUnavailable
+// can't be set together, when none is the minAvailable is derived from the replicas of the component
+type PodDisruptionBudgetSettings struct {
+	// Disabled removes the PodDisruptionBudget of the component
+	Disabled       bool                `json:"disabled,omitempty"`
+	MinAvailable   *intstr.IntOrString `json:"minAvailable,omitempty"`
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
+// RedisRoleServiceSettings defines the service pointing to the redis nodes with a given role
+type RedisRoleServiceSettings struct {
+	Type        corev1.ServiceType `json:"type,omitempty"`
+	Annotations map[string]string  `json:"annotations,omitempty"`
 }
 
 // SentinelSettings defines the specification of the sentinel cluster
@@ -78,6 +156,9 @@ type SentinelSettings struct {
 	Image                     string                            `json:"image,omitempty"`
 	ImagePullPolicy           corev1.PullPolicy                 `json:"imagePullPolicy,omitempty"`
 	Replicas                  int32                             `json:"replicas,omitempty"`
+	Port                      int32                             `json:"port,omitempty"`
+	MasterName                string                            `json:"masterName,omitempty"`
+	Auth                      AuthSettings                      `json:"auth,omitempty"`
 	Resources                 corev1.ResourceRequirements       `json:"resources,omitempty"`
 	CustomConfig              []string                          `json:"customConfig,omitempty"`
 	Command                   []string                          `json:"command,omitempty"`
@@ -102,6 +183,100 @@ type SentinelSettings struct {
 	ExtraVolumes              []corev1.Volume                   `json:"extraVolumes,omitempty"`
 	ExtraVolumeMounts         []corev1.VolumeMount              `json:"extraVolumeMounts,omitempty"`
 	StoragePath               string                            `json:"storagePath,omitempty"` // stroage path on the host
+	PodDisruptionBudget       PodDisruptionBudgetSettings       `json:"pdb,omitempty"`
+}
+
+// ExternalAccessSettings exposes every redis and sentinel pod through its own service, and makes
+// them announce the external address so clients outside the cluster can use sentinel
+type ExternalAccessSettings struct {
+	// Type is the type of the per pod services, LoadBalancer or NodePort
+	Type corev1.ServiceType `json:"type,omitempty"`
+	// HostAddress is the address the nodes are reached by from outside the cluster, required with NodePort
+	HostAddress string            `json:"hostAddress,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// ReplicaOfSettings makes the redises replicate the current master of another RedisFailover, following its failovers
+type ReplicaOfSettings struct {
+	RedisFailoverRef RedisFailoverReference `json:"redisFailoverRef"`
+	// MasterAuth is the secret with the password of the source RedisFailover, used as masterauth
+	MasterAuth AuthSettings `json:"masterAuth,omitempty"`
+	// Promote detaches the RedisFailover from its source, enabling its sentinels and electing a local master
+	Promote bool `json:"promote,omitempty"`
+}
+
+// RedisFailoverReference points to a RedisFailover, in the namespace of the referrer when no namespace is given
+type RedisFailoverReference struct {
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+}
+
+// MonitorType is the kind of the Prometheus Operator objects the exporters are scraped through
+type MonitorType string
+
+// Supported monitor kinds
+const (
+	MonitorTypeServiceMonitor MonitorType = "ServiceMonitor"
+	MonitorTypePodMonitor     MonitorType = "PodMonitor"
+)
+
+// MonitoringSettings makes the operator create the Prometheus Operator objects scraping the enabled exporters
+// and alerting on the RedisFailover. They are skipped while the Prometheus Operator CRDs are not installed
+type MonitoringSettings struct {
+	// Type is the kind of the objects scraping the exporters, ServiceMonitor or PodMonitor
+	Type MonitorType `json:"type,omitempty"`
+	// Interval is the scrape interval of the exporters, the one of Prometheus when empty
+	Interval string `json:"interval,omitempty"`
+	// Labels are added to the created objects, as the ones the Prometheus resources select them by
+	Labels map[string]string `json:"labels,omitempty"`
+	Alerts AlertsSettings    `json:"alerts,omitempty"`
+}
+
+// AlertsSettings defines the PrometheusRule alerting on the RedisFailover. The durations are the time a
+// condition must last for its alert to fire, in the Prometheus format
+type AlertsSettings struct {
+	// Disabled skips the creation of the PrometheusRule
+	Disabled bool `json:"disabled,omitempty"`
+	// NoMasterFor is the time without a master before alerting
+	NoMasterFor string `json:"noMasterFor,omitempty"`
+	// ReplicaLinkDownFor is the time a replica is disconnected from its master before alerting
+	ReplicaLinkDownFor string `json:"replicaLinkDownFor,omitempty"`
+	// SentinelQuorumLostFor is the time the sentinels can't reach the quorum before alerting, needs the sentinel exporter
+	SentinelQuorumLostFor string `json:"sentinelQuorumLostFor,omitempty"`
+	// MemoryUsagePercent is the share of maxmemory a redis alerts from, needs the redis exporter
+	MemoryUsagePercent int32 `json:"memoryUsagePercent,omitempty"`
+	// MemoryUsageFor is the time the memory usage stays above MemoryUsagePercent before alerting
+	MemoryUsageFor string `json:"memoryUsageFor,omitempty"`
+	// ClusterErrorFor is the time the operator fails to reconcile the RedisFailover before alerting
+	ClusterErrorFor string `json:"clusterErrorFor,omitempty"`
+	// Labels are added to every alert, overriding their severity
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// NetworkPolicySettings makes the operator create a NetworkPolicy for redis, sentinel and the proxy, only letting
+// in the traffic between them, from the operator, from the scrapers of the exporters and from the clients
+type NetworkPolicySettings struct {
+	// Operator selects the operator pods, the ones labeled app=redisoperator in any namespace when empty
+	Operator NetworkPolicyPeerSettings `json:"operator,omitempty"`
+	// Metrics selects the pods allowed to scrape the exporters, the ones of any namespace when empty
+	Metrics NetworkPolicyPeerSettings `json:"metrics,omitempty"`
+	// Clients selects the pods allowed to reach the proxy and the sentinels, the ones of the namespace of the
+	// RedisFailover when empty
+	Clients NetworkPolicyClientsSettings `json:"clients,omitempty"`
+}
+
+// NetworkPolicyPeerSettings selects the pods of the namespaces matching NamespaceSelector, or of the namespace of
+// the RedisFailover when it is not set, matching PodSelector, or all of them when it is not set
+type NetworkPolicyPeerSettings struct {
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+	PodSelector       *metav1.LabelSelector `json:"podSelector,omitempty"`
+}
+
+// NetworkPolicyClientsSettings selects the clients of the RedisFailover
+type NetworkPolicyClientsSettings struct {
+	NetworkPolicyPeerSettings `json:",inline"`
+	// Redis lets the clients reach the redises too, as the ones asking the sentinels for the master need
+	Redis bool `json:"redis,omitempty"`
 }
 
 // AuthSettings contains settings about auth
@@ -114,19 +289,64 @@ type BootstrapSettings struct {
 	Host           string `json:"host,omitempty"`
 	Port           string `json:"port,omitempty"`
 	AllowSentinels bool   `json:"allowSentinels,omitempty"`
+	// Sentinels are the host:port addresses of the sentinels managing the external master. When given, they are
+	// asked for the current master instead of using Host and Port, so the redises follow its failovers
+	Sentinels []string `json:"sentinels,omitempty"`
+	// MasterName is the name the external sentinels monitor the master by
+	MasterName string `json:"masterName,omitempty"`
+	// MasterAuth is the secret with the password of the external master, the one of auth is used when empty
+	MasterAuth AuthSettings `json:"masterAuth,omitempty"`
+	// SentinelAuth is the secret with the password of the external sentinels, left empty when they require none
+	SentinelAuth AuthSettings          `json:"sentinelAuth,omitempty"`
+	TLS          *BootstrapTLSSettings `json:"tls,omitempty"`
+}
+
+// BootstrapTLSSettings enables TLS on the replication from the external master and on the connections to its sentinels
+type BootstrapTLSSettings struct {
+	// SecretName is the secret with the tls.crt, tls.key and ca.crt files to connect with
+	SecretName string `json:"secretName"`
 }
 
 // PredixySettings defines the specification of the predixy cluster
 type PredixySettings struct {
-	Image            string                        `json:"image,omitempty"`
-	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
-	ImagePullPolicy  corev1.PullPolicy             `json:"imagePullPolicy,omitempty"`
-	Resources        corev1.ResourceRequirements   `json:"resources,omitempty"`
-	Replicas         int32                         `json:"replicas,omitempty"`
-	Exporter         Exporter                      `json:"exporter,omitempty"`
-	PodAnnotations   map[string]string             `json:"podAnnotations,omitempty"` // Realize fixed ip through annotations in kubeovn environment
-	StoragePath      string                        `json:"storagePath,omitempty"`    // stroage path on the host
-	NodeSelector     map[string]string             `json:"nodeSelector,omitempty"`
+	Image               string                        `json:"image,omitempty"`
+	ImagePullSecrets    []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+	ImagePullPolicy     corev1.PullPolicy             `json:"imagePullPolicy,omitempty"`
+	Resources           corev1.ResourceRequirements   `json:"resources,omitempty"`
+	Replicas            int32                         `json:"replicas,omitempty"`
+	Exporter            Exporter                      `json:"exporter,omitempty"`
+	PodAnnotations      map[string]string             `json:"podAnnotations,omitempty"` // Realize fixed ip through annotations in kubeovn environment
+	StoragePath         string                        `json:"storagePath,omitempty"`    // stroage path on the host
+	NodeSelector        map[string]string             `json:"nodeSelector,omitempty"`
+	PodDisruptionBudget PodDisruptionBudgetSettings   `json:"pdb,omitempty"`
+}
+
+// ProxyType is the name of a proxy implementation placed in front of the redis failover
+type ProxyType string
+
+// Supported proxy implementations
+const (
+	ProxyTypePredixy ProxyType = "predixy"
+	ProxyTypeEnvoy   ProxyType = "envoy"
+)
+
+// ProxySettings selects the proxy implementation and holds the settings of those
+// that are not configured at the top level of the spec
+type ProxySettings struct {
+	Type  ProxyType     `json:"type,omitempty"`
+	Envoy EnvoySettings `json:"envoy,omitempty"`
+}
+
+// EnvoySettings defines the specification of the envoy redis_proxy deployment
+type EnvoySettings struct {
+	Image               string                        `json:"image,omitempty"`
+	ImagePullSecrets    []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+	ImagePullPolicy     corev1.PullPolicy             `json:"imagePullPolicy,omitempty"`
+	Resources           corev1.ResourceRequirements   `json:"resources,omitempty"`
+	Replicas            int32                         `json:"replicas,omitempty"`
+	PodAnnotations      map[string]string             `json:"podAnnotations,omitempty"`
+	NodeSelector        map[string]string             `json:"nodeSelector,omitempty"`
+	PodDisruptionBudget PodDisruptionBudgetSettings   `json:"pdb,omitempty"`
 }
 
 // Exporter defines the specification for the redis/sentinel exporter
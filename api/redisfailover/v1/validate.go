@@ -3,13 +3,22 @@ package v1
 import (
 	"errors"
 	"fmt"
+	"regexp"
 	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
 const (
 	maxNameLength = 48
 )
 
+// prometheusDuration matches the durations of the Prometheus Operator objects, e.g. 30s or 1h30m
+var prometheusDuration = regexp.MustCompile(`^(0|(([0-9]+)y)?(([0-9]+)w)?(([0-9]+)d)?(([0-9]+)h)?(([0-9]+)m)?(([0-9]+)s)?(([0-9]+)ms)?)$`)
+
 // Validate set the values by default if not defined and checks if the values given are valid
 func (r *RedisFailover) Validate() error {
 	if len(r.Name) > maxNameLength {
@@ -20,14 +29,42 @@ func (r *RedisFailover) Validate() error {
 		return fmt.Errorf("in multi-sharding, redis replicas must be even number(every sharding must be 1 master 1 slave)")
 	}
 
+	if r.Spec.ReplicaOf != nil {
+		if r.Bootstrapping() {
+			return errors.New("replicaOf and bootstrapNode can't be used together")
+		}
+		ref := &r.Spec.ReplicaOf.RedisFailoverRef
+		if ref.Name == "" {
+			return errors.New("replicaOf must include the name of the source redisFailoverRef")
+		}
+		if ref.Namespace == "" {
+			ref.Namespace = r.Namespace
+		}
+		if ref.Namespace == r.Namespace && ref.Name == r.Name {
+			return errors.New("replicaOf can't reference the RedisFailover itself")
+		}
+	}
+
 	if r.Bootstrapping() {
-		if r.Spec.BootstrapNode.Host == "" {
-			return errors.New("BootstrapNode must include a host when provided")
+		if r.Spec.BootstrapNode.Host == "" && len(r.Spec.BootstrapNode.Sentinels) == 0 {
+			return errors.New("BootstrapNode must include a host or sentinels when provided")
+		}
+
+		if len(r.Spec.BootstrapNode.Sentinels) > 0 && r.Spec.BootstrapNode.MasterName == "" {
+			r.Spec.BootstrapNode.MasterName = defaultBootstrapMasterName
+		}
+
+		if r.Spec.BootstrapNode.TLS != nil && r.Spec.BootstrapNode.TLS.SecretName == "" {
+			return errors.New("BootstrapNode tls must include a secretName")
 		}
 
 		if r.Spec.BootstrapNode.Port == "" {
 			r.Spec.BootstrapNode.Port = strconv.Itoa(defaultRedisPort)
 		}
+	}
+
+	// The redises of a bootstrapping or replicating RedisFailover must never be promoted by sentinel
+	if r.Bootstrapping() || r.Replicating() {
 		r.Spec.Redis.CustomConfig = deduplicateStr(append(bootstrappingRedisCustomConfig, r.Spec.Redis.CustomConfig...))
 	} else {
 		r.Spec.Redis.CustomConfig = deduplicateStr(append(defaultRedisCustomConfig, r.Spec.Redis.CustomConfig...))
@@ -53,6 +90,26 @@ func (r *RedisFailover) Validate() error {
 		r.Spec.Sentinel.Replicas = defaultSentinelNumber
 	}
 
+	if r.Spec.Sentinel.Port <= 0 {
+		r.Spec.Sentinel.Port = defaultSentinelPort
+	}
+
+	if r.Spec.Sentinel.MasterName == "" {
+		r.Spec.Sentinel.MasterName = defaultSentinelMasterName
+	} else if strings.ContainsAny(r.Spec.Sentinel.MasterName, " \t\n") {
+		return errors.New("sentinel masterName can't contain whitespaces")
+	}
+
+	if r.Spec.Sentinel.HostNetwork && r.Spec.Redis.HostNetwork && r.Spec.Sentinel.Port == r.Spec.Redis.Port {
+		return errors.New("sentinel and redis can't use the same port on the host network")
+	}
+
+	if r.Spec.Redis.Zones != nil {
+		if err := r.Spec.Redis.Zones.validate(r.Name, r.Spec.Redis.Replicas); err != nil {
+			return err
+		}
+	}
+
 	if r.Spec.Redis.Exporter.Image == "" {
 		r.Spec.Redis.Exporter.Image = defaultExporterImage
 	}
@@ -65,6 +122,171 @@ func (r *RedisFailover) Validate() error {
 		r.Spec.Sentinel.CustomConfig = defaultSentinelCustomConfig
 	}
 
+	if r.Spec.ExternalAccess != nil {
+		switch r.Spec.ExternalAccess.Type {
+		case "":
+			r.Spec.ExternalAccess.Type = corev1.ServiceTypeLoadBalancer
+		case corev1.ServiceTypeLoadBalancer:
+		case corev1.ServiceTypeNodePort:
+			if r.Spec.ExternalAccess.HostAddress == "" {
+				return errors.New("externalAccess of type NodePort must include a hostAddress")
+			}
+		default:
+			return fmt.Errorf("externalAccess type %q is not supported, must be one of %q or %q", r.Spec.ExternalAccess.Type, corev1.ServiceTypeLoadBalancer, corev1.ServiceTypeNodePort)
+		}
+	}
+
+	switch r.Spec.Proxy.Type {
+	case "":
+		r.Spec.Proxy.Type = defaultProxyType
+	case ProxyTypePredixy:
+	case ProxyTypeEnvoy:
+		if r.Spec.Proxy.Envoy.Image == "" {
+			r.Spec.Proxy.Envoy.Image = defaultEnvoyImage
+		}
+		if r.Spec.Proxy.Envoy.Replicas <= 0 {
+			r.Spec.Proxy.Envoy.Replicas = defaultEnvoyNumber
+		}
+	default:
+		return fmt.Errorf("proxy type %q is not supported, must be one of %q or %q", r.Spec.Proxy.Type, ProxyTypePredixy, ProxyTypeEnvoy)
+	}
+
+	pdbs := []struct {
+		component string
+		settings  PodDisruptionBudgetSettings
+	}{
+		{"redis", r.Spec.Redis.PodDisruptionBudget},
+		{"sentinel", r.Spec.Sentinel.PodDisruptionBudget},
+		{"predixy", r.Spec.Predixy.PodDisruptionBudget},
+		{"envoy", r.Spec.Proxy.Envoy.PodDisruptionBudget},
+	}
+	for _, pdb := range pdbs {
+		if pdb.settings.MinAvailable != nil && pdb.settings.MaxUnavailable != nil {
+			return fmt.Errorf("%s pdb can't set both minAvailable and maxUnavailable", pdb.component)
+		}
+	}
+
+	if r.Spec.Monitoring != nil {
+		if err := r.Spec.Monitoring.validate(); err != nil {
+			return err
+		}
+	}
+
+	if r.Spec.NetworkPolicy != nil {
+		if err := r.Spec.NetworkPolicy.validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validate sets the default topology key and primary zone and checks the zones can hold the given redises
+func (z *ZonesSettings) validate(name string, replicas int32) error {
+	if len(z.Names) == 0 {
+		return errors.New("redis zones must include the names of the zones")
+	}
+	if int(replicas) < len(z.Names) {
+		return fmt.Errorf("redis replicas can't be fewer than the %d zones", len(z.Names))
+	}
+
+	seen := map[string]bool{}
+	for _, zone := range z.Names {
+		if errs := validation.IsDNS1123Label(zone); len(errs) > 0 {
+			return fmt.Errorf("redis zone %q is not valid: %s", zone, strings.Join(errs, ", "))
+		}
+		// The zone is part of the name of its statefulset
+		if len(name)+len(zone)+1 > maxNameLength {
+			return fmt.Errorf("name and redis zone %q together can't be longer than %d", zone, maxNameLength-1)
+		}
+		if seen[zone] {
+			return fmt.Errorf("redis zone %q is repeated", zone)
+		}
+		seen[zone] = true
+	}
+
+	if z.Primary == "" {
+		z.Primary = z.Names[0]
+	} else if !seen[z.Primary] {
+		return fmt.Errorf("redis primary zone %q is not one of the zones", z.Primary)
+	}
+	if z.TopologyKey == "" {
+		z.TopologyKey = defaultZoneTopologyKey
+	}
+	return nil
+}
+
+// validate sets the default peers of the network policies and checks the given selectors
+func (n *NetworkPolicySettings) validate() error {
+	if n.Operator.NamespaceSelector == nil && n.Operator.PodSelector == nil {
+		n.Operator.NamespaceSelector = &metav1.LabelSelector{}
+		n.Operator.PodSelector = &metav1.LabelSelector{MatchLabels: map[string]string{defaultOperatorLabelKey: defaultOperatorLabelValue}}
+	}
+	if n.Metrics.NamespaceSelector == nil && n.Metrics.PodSelector == nil {
+		n.Metrics.NamespaceSelector = &metav1.LabelSelector{}
+	}
+	if n.Clients.NamespaceSelector == nil && n.Clients.PodSelector == nil {
+		n.Clients.PodSelector = &metav1.LabelSelector{}
+	}
+
+	peers := []struct {
+		name     string
+		settings NetworkPolicyPeerSettings
+	}{
+		{"operator", n.Operator},
+		{"metrics", n.Metrics},
+		{"clients", n.Clients.NetworkPolicyPeerSettings},
+	}
+	for _, peer := range peers {
+		if _, err := metav1.LabelSelectorAsSelector(peer.settings.NamespaceSelector); err != nil {
+			return fmt.Errorf("networkPolicy %s namespaceSelector is not valid: %w", peer.name, err)
+		}
+		if _, err := metav1.LabelSelectorAsSelector(peer.settings.PodSelector); err != nil {
+			return fmt.Errorf("networkPolicy %s podSelector is not valid: %w", peer.name, err)
+		}
+	}
+	return nil
+}
+
+// validate sets the default monitor type and alert thresholds and checks the given ones
+func (m *MonitoringSettings) validate() error {
+	switch m.Type {
+	case "":
+		m.Type = defaultMonitorType
+	case MonitorTypeServiceMonitor, MonitorTypePodMonitor:
+	default:
+		return fmt.Errorf("monitoring type %q is not supported, must be one of %q or %q", m.Type, MonitorTypeServiceMonitor, MonitorTypePodMonitor)
+	}
+
+	if m.Interval != "" && !prometheusDuration.MatchString(m.Interval) {
+		return fmt.Errorf("monitoring interval %q is not a valid duration", m.Interval)
+	}
+
+	alerts := &m.Alerts
+	if alerts.MemoryUsagePercent == 0 {
+		alerts.MemoryUsagePercent = defaultMemoryUsagePercent
+	} else if alerts.MemoryUsagePercent < 0 || alerts.MemoryUsagePercent > 100 {
+		return errors.New("monitoring alerts memoryUsagePercent must be between 1 and 100")
+	}
+
+	durations := []struct {
+		name     string
+		value    *string
+		fallback string
+	}{
+		{"noMasterFor", &alerts.NoMasterFor, defaultNoMasterFor},
+		{"replicaLinkDownFor", &alerts.ReplicaLinkDownFor, defaultReplicaLinkDownFor},
+		{"sentinelQuorumLostFor", &alerts.SentinelQuorumLostFor, defaultSentinelQuorumLostFor},
+		{"memoryUsageFor", &alerts.MemoryUsageFor, defaultMemoryUsageFor},
+		{"clusterErrorFor", &alerts.ClusterErrorFor, defaultClusterErrorFor},
+	}
+	for _, d := range durations {
+		if *d.value == "" {
+			*d.value = d.fallback
+		} else if !prometheusDuration.MatchString(*d.value) {
+			return fmt.Errorf("monitoring alerts %s %q is not a valid duration", d.name, *d.value)
+		}
+	}
 	return nil
 }
 
@@ -4,7 +4,9 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 func TestValidate(t *testing.T) {
@@ -16,6 +18,22 @@ func TestValidate(t *testing.T) {
 		rfSentinelCustomConfig []string
 		expectedError          string
 		expectedBootstrapNode  *BootstrapSettings
+		rfProxy                ProxySettings
+		expectedProxy          *ProxySettings
+		rfExternalAccess       *ExternalAccessSettings
+		expectedExternalAccess *ExternalAccessSettings
+		rfReplicaOf            *ReplicaOfSettings
+		expectedReplicaOf      *ReplicaOfSettings
+		rfMonitoring           *MonitoringSettings
+		rfSentinelPDB          PodDisruptionBudgetSettings
+		expectedMonitoring     *MonitoringSettings
+		rfNetworkPolicy        *NetworkPolicySettings
+		expectedNetworkPolicy  *NetworkPolicySettings
+		rfZones                *ZonesSettings
+		expectedZones          *ZonesSettings
+		rfSentinelPort         int32
+		rfSentinelMasterName   string
+		rfHostNetwork          bool
 	}{
 		{
 			name:   "populates default values",
@@ -35,7 +53,7 @@ func TestValidate(t *testing.T) {
 			name:            "BootstrapNode provided without a host",
 			rfName:          "test",
 			rfBootstrapNode: &BootstrapSettings{},
-			expectedError:   "BootstrapNode must include a host when provided",
+			expectedError:   "BootstrapNode must include a host or sentinels when provided",
 		},
 		{
 			name:   "SentinelCustomConfig provided",
@@ -53,6 +71,18 @@ func TestValidate(t *testing.T) {
 			rfBootstrapNode:       &BootstrapSettings{Host: "127.0.0.1", Port: "6380"},
 			expectedBootstrapNode: &BootstrapSettings{Host: "127.0.0.1", Port: "6380"},
 		},
+		{
+			name:                  "Populates default master name when bootstrapping from sentinels",
+			rfName:                "test",
+			rfBootstrapNode:       &BootstrapSettings{Sentinels: []string{"10.0.0.1:26379"}},
+			expectedBootstrapNode: &BootstrapSettings{Sentinels: []string{"10.0.0.1:26379"}, MasterName: "mymaster", Port: "6379"},
+		},
+		{
+			name:            "BootstrapNode tls provided without a secret",
+			rfName:          "test",
+			rfBootstrapNode: &BootstrapSettings{Host: "127.0.0.1", TLS: &BootstrapTLSSettings{}},
+			expectedError:   "BootstrapNode tls must include a secretName",
+		},
 		{
 			name:                "Appends applied custom config to default initial values",
 			rfName:              "test",
@@ -65,6 +95,237 @@ func TestValidate(t *testing.T) {
 			rfBootstrapNode:       &BootstrapSettings{Host: "127.0.0.1"},
 			expectedBootstrapNode: &BootstrapSettings{Host: "127.0.0.1", Port: "6379"},
 		},
+		{
+			name:          "Populates envoy defaults when envoy proxy is selected",
+			rfName:        "test",
+			rfProxy:       ProxySettings{Type: ProxyTypeEnvoy},
+			expectedProxy: &ProxySettings{Type: ProxyTypeEnvoy, Envoy: EnvoySettings{Image: defaultEnvoyImage, Replicas: defaultEnvoyNumber}},
+		},
+		{
+			name:          "Errors on unknown proxy type",
+			rfName:        "test",
+			rfProxy:       ProxySettings{Type: "twemproxy"},
+			expectedError: `proxy type "twemproxy" is not supported, must be one of "predixy" or "envoy"`,
+		},
+		{
+			name:                   "Defaults external access to LoadBalancer",
+			rfName:                 "test",
+			rfExternalAccess:       &ExternalAccessSettings{},
+			expectedExternalAccess: &ExternalAccessSettings{Type: corev1.ServiceTypeLoadBalancer},
+		},
+		{
+			name:                   "Allows NodePort external access with a host address",
+			rfName:                 "test",
+			rfExternalAccess:       &ExternalAccessSettings{Type: corev1.ServiceTypeNodePort, HostAddress: "10.0.0.1"},
+			expectedExternalAccess: &ExternalAccessSettings{Type: corev1.ServiceTypeNodePort, HostAddress: "10.0.0.1"},
+		},
+		{
+			name:             "Errors on NodePort external access without a host address",
+			rfName:           "test",
+			rfExternalAccess: &ExternalAccessSettings{Type: corev1.ServiceTypeNodePort},
+			expectedError:    "externalAccess of type NodePort must include a hostAddress",
+		},
+		{
+			name:             "Errors on unknown external access type",
+			rfName:           "test",
+			rfExternalAccess: &ExternalAccessSettings{Type: corev1.ServiceTypeClusterIP},
+			expectedError:    `externalAccess type "ClusterIP" is not supported, must be one of "LoadBalancer" or "NodePort"`,
+		},
+		{
+			name:              "Populates the namespace of the source RedisFailover",
+			rfName:            "test",
+			rfReplicaOf:       &ReplicaOfSettings{RedisFailoverRef: RedisFailoverReference{Name: "source"}},
+			expectedReplicaOf: &ReplicaOfSettings{RedisFailoverRef: RedisFailoverReference{Namespace: "namespace", Name: "source"}},
+		},
+		{
+			name:              "Keeps the default custom config once promoted",
+			rfName:            "test",
+			rfReplicaOf:       &ReplicaOfSettings{RedisFailoverRef: RedisFailoverReference{Namespace: "other", Name: "source"}, Promote: true},
+			expectedReplicaOf: &ReplicaOfSettings{RedisFailoverRef: RedisFailoverReference{Namespace: "other", Name: "source"}, Promote: true},
+		},
+		{
+			name:          "Errors on replicaOf without a source name",
+			rfName:        "test",
+			rfReplicaOf:   &ReplicaOfSettings{},
+			expectedError: "replicaOf must include the name of the source redisFailoverRef",
+		},
+		{
+			name:          "Errors on replicaOf referencing itself",
+			rfName:        "test",
+			rfReplicaOf:   &ReplicaOfSettings{RedisFailoverRef: RedisFailoverReference{Name: "test"}},
+			expectedError: "replicaOf can't reference the RedisFailover itself",
+		},
+		{
+			name:            "Errors on replicaOf with a BootstrapNode",
+			rfName:          "test",
+			rfBootstrapNode: &BootstrapSettings{Host: "127.0.0.1"},
+			rfReplicaOf:     &ReplicaOfSettings{RedisFailoverRef: RedisFailoverReference{Name: "source"}},
+			expectedError:   "replicaOf and bootstrapNode can't be used together",
+		},
+		{
+			name:                 "Allows for specifying the sentinel port and master name",
+			rfName:               "test",
+			rfSentinelPort:       26380,
+			rfSentinelMasterName: "mymaster",
+		},
+		{
+			name:                 "Errors on a sentinel master name with whitespaces",
+			rfName:               "test",
+			rfSentinelMasterName: "my master",
+			expectedError:        "sentinel masterName can't contain whitespaces",
+		},
+		{
+			name:         "Populates the monitoring defaults",
+			rfName:       "test",
+			rfMonitoring: &MonitoringSettings{},
+			expectedMonitoring: &MonitoringSettings{
+				Type: MonitorTypeServiceMonitor,
+				Alerts: AlertsSettings{
+					NoMasterFor:           "1m",
+					ReplicaLinkDownFor:    "5m",
+					SentinelQuorumLostFor: "5m",
+					MemoryUsagePercent:    90,
+					MemoryUsageFor:        "5m",
+					ClusterErrorFor:       "10m",
+				},
+			},
+		},
+		{
+			name:   "Keeps the given monitoring settings",
+			rfName: "test",
+			rfMonitoring: &MonitoringSettings{
+				Type:     MonitorTypePodMonitor,
+				Interval: "15s",
+				Alerts:   AlertsSettings{NoMasterFor: "30s", ReplicaLinkDownFor: "1h30m", SentinelQuorumLostFor: "2m", MemoryUsagePercent: 80, MemoryUsageFor: "0", ClusterErrorFor: "1d"},
+			},
+			expectedMonitoring: &MonitoringSettings{
+				Type:     MonitorTypePodMonitor,
+				Interval: "15s",
+				Alerts:   AlertsSettings{NoMasterFor: "30s", ReplicaLinkDownFor: "1h30m", SentinelQuorumLostFor: "2m", MemoryUsagePercent: 80, MemoryUsageFor: "0", ClusterErrorFor: "1d"},
+			},
+		},
+		{
+			name:          "Errors on unknown monitoring type",
+			rfName:        "test",
+			rfMonitoring:  &MonitoringSettings{Type: "Probe"},
+			expectedError: `monitoring type "Probe" is not supported, must be one of "ServiceMonitor" or "PodMonitor"`,
+		},
+		{
+			name:          "Errors on an invalid monitoring interval",
+			rfName:        "test",
+			rfMonitoring:  &MonitoringSettings{Interval: "30 seconds"},
+			expectedError: `monitoring interval "30 seconds" is not a valid duration`,
+		},
+		{
+			name:          "Errors on an invalid alert duration",
+			rfName:        "test",
+			rfMonitoring:  &MonitoringSettings{Alerts: AlertsSettings{NoMasterFor: "1.5m"}},
+			expectedError: `monitoring alerts noMasterFor "1.5m" is not a valid duration`,
+		},
+		{
+			name:          "Errors on a memory usage percent above 100",
+			rfName:        "test",
+			rfMonitoring:  &MonitoringSettings{Alerts: AlertsSettings{MemoryUsagePercent: 120}},
+			expectedError: "monitoring alerts memoryUsagePercent must be between 1 and 100",
+		},
+		{
+			name:            "Populates the network policy peers",
+			rfName:          "test",
+			rfNetworkPolicy: &NetworkPolicySettings{},
+			expectedNetworkPolicy: &NetworkPolicySettings{
+				Operator: NetworkPolicyPeerSettings{
+					NamespaceSelector: &metav1.LabelSelector{},
+					PodSelector:       &metav1.LabelSelector{MatchLabels: map[string]string{"app": "redisoperator"}},
+				},
+				Metrics: NetworkPolicyPeerSettings{NamespaceSelector: &metav1.LabelSelector{}},
+				Clients: NetworkPolicyClientsSettings{NetworkPolicyPeerSettings: NetworkPolicyPeerSettings{PodSelector: &metav1.LabelSelector{}}},
+			},
+		},
+		{
+			name:   "Keeps the given network policy peers",
+			rfName: "test",
+			rfNetworkPolicy: &NetworkPolicySettings{
+				Operator: NetworkPolicyPeerSettings{NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"name": "operators"}}},
+				Metrics:  NetworkPolicyPeerSettings{PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "prometheus"}}},
+				Clients: NetworkPolicyClientsSettings{
+					NetworkPolicyPeerSettings: NetworkPolicyPeerSettings{NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}}},
+					Redis:                     true,
+				},
+			},
+			expectedNetworkPolicy: &NetworkPolicySettings{
+				Operator: NetworkPolicyPeerSettings{NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"name": "operators"}}},
+				Metrics:  NetworkPolicyPeerSettings{PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "prometheus"}}},
+				Clients: NetworkPolicyClientsSettings{
+					NetworkPolicyPeerSettings: NetworkPolicyPeerSettings{NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}}},
+					Redis:                     true,
+				},
+			},
+		},
+		{
+			name:   "Errors on an invalid network policy selector",
+			rfName: "test",
+			rfNetworkPolicy: &NetworkPolicySettings{
+				Clients: NetworkPolicyClientsSettings{NetworkPolicyPeerSettings: NetworkPolicyPeerSettings{
+					PodSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "app", Operator: "Like"}}},
+				}},
+			},
+			expectedError: `networkPolicy clients podSelector is not valid: "Like" is not a valid label selector operator`,
+		},
+		{
+			name:          "Errors on a pdb with both minAvailable and maxUnavailable",
+			rfName:        "test",
+			rfSentinelPDB: PodDisruptionBudgetSettings{MinAvailable: &intstr.IntOrString{IntVal: 2}, MaxUnavailable: &intstr.IntOrString{IntVal: 1}},
+			expectedError: "sentinel pdb can't set both minAvailable and maxUnavailable",
+		},
+		{
+			name:           "Errors on sentinel and redis sharing the port on the host network",
+			rfName:         "test",
+			rfSentinelPort: 6379,
+			rfHostNetwork:  true,
+			expectedError:  "sentinel and redis can't use the same port on the host network",
+		},
+		{
+			name:          "Defaults the primary zone and the topology key of the zones",
+			rfName:        "test",
+			rfZones:       &ZonesSettings{Names: []string{"a", "b"}},
+			expectedZones: &ZonesSettings{Names: []string{"a", "b"}, Primary: "a", TopologyKey: "topology.kubernetes.io/zone"},
+		},
+		{
+			name:          "Allows setting the primary zone and the topology key of the zones",
+			rfName:        "test",
+			rfZones:       &ZonesSettings{Names: []string{"a", "b"}, Primary: "b", TopologyKey: "example.com/zone"},
+			expectedZones: &ZonesSettings{Names: []string{"a", "b"}, Primary: "b", TopologyKey: "example.com/zone"},
+		},
+		{
+			name:          "Errors on zones without names",
+			rfName:        "test",
+			rfZones:       &ZonesSettings{},
+			expectedError: "redis zones must include the names of the zones",
+		},
+		{
+			name:          "Errors on more zones than redis replicas",
+			rfName:        "test",
+			rfZones:       &ZonesSettings{Names: []string{"a", "b", "c", "d"}},
+			expectedError: "redis replicas can't be fewer than the 4 zones",
+		},
+		{
+			name:          "Errors on a repeated zone",
+			rfName:        "test",
+			rfZones:       &ZonesSettings{Names: []string{"a", "a"}},
+			expectedError: `redis zone "a" is repeated`,
+		},
+		{
+			name:          "Errors on a zone that can't be part of a name",
+			rfName:        "test",
+			rfZones:       &ZonesSettings{Names: []string{"eu_west"}},
+			expectedError: `redis zone "eu_west" is not valid: a lowercase RFC 1123 label must consist of lower case alphanumeric characters or '-', and must start and end with an alphanumeric character (e.g. 'my-name',  or '123-abc', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?')`,
+		},
+		{
+			name:          "Errors on a primary zone out of the zones",
+			rfName:        "test",
+			rfZones:       &ZonesSettings{Names: []string{"a", "b"}, Primary: "c"},
+			expectedError: `redis primary zone "c" is not one of the zones`,
+		},
 	}
 
 	for _, test := range tests {
@@ -73,6 +334,17 @@ func TestValidate(t *testing.T) {
 			rf := generateRedisFailover(test.rfName, test.rfBootstrapNode)
 			rf.Spec.Redis.CustomConfig = test.rfRedisCustomConfig
 			rf.Spec.Sentinel.CustomConfig = test.rfSentinelCustomConfig
+			rf.Spec.Proxy = test.rfProxy
+			rf.Spec.ExternalAccess = test.rfExternalAccess
+			rf.Spec.ReplicaOf = test.rfReplicaOf
+			rf.Spec.Monitoring = test.rfMonitoring
+			rf.Spec.NetworkPolicy = test.rfNetworkPolicy
+			rf.Spec.Sentinel.PodDisruptionBudget = test.rfSentinelPDB
+			rf.Spec.Sentinel.Port = test.rfSentinelPort
+			rf.Spec.Sentinel.MasterName = test.rfSentinelMasterName
+			rf.Spec.Redis.HostNetwork = test.rfHostNetwork
+			rf.Spec.Redis.Zones = test.rfZones
+			rf.Spec.Sentinel.HostNetwork = test.rfHostNetwork
 
 			err := rf.Validate()
 
@@ -83,7 +355,7 @@ func TestValidate(t *testing.T) {
 					"replica-priority 100",
 				}
 
-				if test.rfBootstrapNode != nil {
+				if test.rfBootstrapNode != nil || (test.rfReplicaOf != nil && !test.rfReplicaOf.Promote) {
 					expectedRedisCustomConfig = []string{
 						"replica-priority 0",
 					}
@@ -94,6 +366,18 @@ func TestValidate(t *testing.T) {
 				if len(test.rfSentinelCustomConfig) > 0 {
 					expectedSentinelCustomConfig = test.rfSentinelCustomConfig
 				}
+				expectedSentinelPort := int32(defaultSentinelPort)
+				if test.rfSentinelPort > 0 {
+					expectedSentinelPort = test.rfSentinelPort
+				}
+				expectedSentinelMasterName := defaultSentinelMasterName
+				if test.rfSentinelMasterName != "" {
+					expectedSentinelMasterName = test.rfSentinelMasterName
+				}
+				expectedProxy := ProxySettings{Type: ProxyTypePredixy}
+				if test.expectedProxy != nil {
+					expectedProxy = *test.expectedProxy
+				}
 
 				expectedRF := &RedisFailover{
 					ObjectMeta: metav1.ObjectMeta{
@@ -109,16 +393,24 @@ func TestValidate(t *testing.T) {
 								Image: defaultExporterImage,
 							},
 							CustomConfig: expectedRedisCustomConfig,
+							Zones:        test.expectedZones,
 						},
 						Sentinel: SentinelSettings{
 							Image:        defaultImage,
 							Replicas:     defaultSentinelNumber,
+							Port:         expectedSentinelPort,
+							MasterName:   expectedSentinelMasterName,
 							CustomConfig: expectedSentinelCustomConfig,
 							Exporter: Exporter{
 								Image: defaultSentinelExporterImage,
 							},
 						},
-						BootstrapNode: test.expectedBootstrapNode,
+						BootstrapNode:  test.expectedBootstrapNode,
+						Proxy:          expectedProxy,
+						ExternalAccess: test.expectedExternalAccess,
+						ReplicaOf:      test.expectedReplicaOf,
+						Monitoring:     test.expectedMonitoring,
+						NetworkPolicy:  test.expectedNetworkPolicy,
 					},
 				}
 				assert.Equal(expectedRF, rf)
@@ -7,9 +7,34 @@ package v1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	intstr "k8s.io/apimachinery/pkg/util/intstr"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertsSettings) DeepCopyInto(out *AlertsSettings) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertsSettings.
+func (in *AlertsSettings) DeepCopy() *AlertsSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertsSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AuthSettings) DeepCopyInto(out *AuthSettings) {
 	*out = *in
@@ -29,6 +54,18 @@ func (in *AuthSettings) DeepCopy() *AuthSettings {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BootstrapSettings) DeepCopyInto(out *BootstrapSettings) {
 	*out = *in
+	if in.Sentinels != nil {
+		in, out := &in.Sentinels, &out.Sentinels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	out.MasterAuth = in.MasterAuth
+	out.SentinelAuth = in.SentinelAuth
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(BootstrapTLSSettings)
+		**out = **in
+	}
 	return
 }
 
@@ -42,6 +79,22 @@ func (in *BootstrapSettings) DeepCopy() *BootstrapSettings {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BootstrapTLSSettings) DeepCopyInto(out *BootstrapTLSSettings) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BootstrapTLSSettings.
+func (in *BootstrapTLSSettings) DeepCopy() *BootstrapTLSSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(BootstrapTLSSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EmbeddedObjectMetadata) DeepCopyInto(out *EmbeddedObjectMetadata) {
 	*out = *in
@@ -92,6 +145,43 @@ func (in *EmbeddedPersistentVolumeClaim) DeepCopy() *EmbeddedPersistentVolumeCla
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnvoySettings) DeepCopyInto(out *EnvoySettings) {
+	*out = *in
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]corev1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.PodAnnotations != nil {
+		in, out := &in.PodAnnotations, &out.PodAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.PodDisruptionBudget.DeepCopyInto(&out.PodDisruptionBudget)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvoySettings.
+func (in *EnvoySettings) DeepCopy() *EnvoySettings {
+	if in == nil {
+		return nil
+	}
+	out := new(EnvoySettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Exporter) DeepCopyInto(out *Exporter) {
 	*out = *in
@@ -130,6 +220,213 @@ func (in *Exporter) DeepCopy() *Exporter {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalAccessSettings) DeepCopyInto(out *ExternalAccessSettings) {
+	*out = *in
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalAccessSettings.
+func (in *ExternalAccessSettings) DeepCopy() *ExternalAccessSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalAccessSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonitoringSettings) DeepCopyInto(out *MonitoringSettings) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.Alerts.DeepCopyInto(&out.Alerts)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitoringSettings.
+func (in *MonitoringSettings) DeepCopy() *MonitoringSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(MonitoringSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkPolicyClientsSettings) DeepCopyInto(out *NetworkPolicyClientsSettings) {
+	*out = *in
+	in.NetworkPolicyPeerSettings.DeepCopyInto(&out.NetworkPolicyPeerSettings)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkPolicyClientsSettings.
+func (in *NetworkPolicyClientsSettings) DeepCopy() *NetworkPolicyClientsSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkPolicyClientsSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkPolicyPeerSettings) DeepCopyInto(out *NetworkPolicyPeerSettings) {
+	*out = *in
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodSelector != nil {
+		in, out := &in.PodSelector, &out.PodSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkPolicyPeerSettings.
+func (in *NetworkPolicyPeerSettings) DeepCopy() *NetworkPolicyPeerSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkPolicyPeerSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkPolicySettings) DeepCopyInto(out *NetworkPolicySettings) {
+	*out = *in
+	in.Operator.DeepCopyInto(&out.Operator)
+	in.Metrics.DeepCopyInto(&out.Metrics)
+	in.Clients.DeepCopyInto(&out.Clients)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkPolicySettings.
+func (in *NetworkPolicySettings) DeepCopy() *NetworkPolicySettings {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkPolicySettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PasswordRotationStatus) DeepCopyInto(out *PasswordRotationStatus) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PasswordRotationStatus.
+func (in *PasswordRotationStatus) DeepCopy() *PasswordRotationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PasswordRotationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodDisruptionBudgetSettings) DeepCopyInto(out *PodDisruptionBudgetSettings) {
+	*out = *in
+	if in.MinAvailable != nil {
+		in, out := &in.MinAvailable, &out.MinAvailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodDisruptionBudgetSettings.
+func (in *PodDisruptionBudgetSettings) DeepCopy() *PodDisruptionBudgetSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(PodDisruptionBudgetSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PredixySettings) DeepCopyInto(out *PredixySettings) {
+	*out = *in
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]corev1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	in.Resources.DeepCopyInto(&out.Resources)
+	in.Exporter.DeepCopyInto(&out.Exporter)
+	if in.PodAnnotations != nil {
+		in, out := &in.PodAnnotations, &out.PodAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.PodDisruptionBudget.DeepCopyInto(&out.PodDisruptionBudget)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PredixySettings.
+func (in *PredixySettings) DeepCopy() *PredixySettings {
+	if in == nil {
+		return nil
+	}
+	out := new(PredixySettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProxySettings) DeepCopyInto(out *ProxySettings) {
+	*out = *in
+	in.Envoy.DeepCopyInto(&out.Envoy)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProxySettings.
+func (in *ProxySettings) DeepCopy() *ProxySettings {
+	if in == nil {
+		return nil
+	}
+	out := new(ProxySettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RedisCommandRename) DeepCopyInto(out *RedisCommandRename) {
 	*out = *in
@@ -152,6 +449,7 @@ func (in *RedisFailover) DeepCopyInto(out *RedisFailover) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
@@ -206,6 +504,22 @@ func (in *RedisFailoverList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedisFailoverReference) DeepCopyInto(out *RedisFailoverReference) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisFailoverReference.
+func (in *RedisFailoverReference) DeepCopy() *RedisFailoverReference {
+	if in == nil {
+		return nil
+	}
+	out := new(RedisFailoverReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RedisFailoverSpec) DeepCopyInto(out *RedisFailoverSpec) {
 	*out = *in
@@ -220,8 +534,30 @@ func (in *RedisFailoverSpec) DeepCopyInto(out *RedisFailoverSpec) {
 	if in.BootstrapNode != nil {
 		in, out := &in.BootstrapNode, &out.BootstrapNode
 		*out = new(BootstrapSettings)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Predixy.DeepCopyInto(&out.Predixy)
+	in.Proxy.DeepCopyInto(&out.Proxy)
+	if in.ExternalAccess != nil {
+		in, out := &in.ExternalAccess, &out.ExternalAccess
+		*out = new(ExternalAccessSettings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReplicaOf != nil {
+		in, out := &in.ReplicaOf, &out.ReplicaOf
+		*out = new(ReplicaOfSettings)
 		**out = **in
 	}
+	if in.Monitoring != nil {
+		in, out := &in.Monitoring, &out.Monitoring
+		*out = new(MonitoringSettings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NetworkPolicy != nil {
+		in, out := &in.NetworkPolicy, &out.NetworkPolicy
+		*out = new(NetworkPolicySettings)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -235,6 +571,55 @@ func (in *RedisFailoverSpec) DeepCopy() *RedisFailoverSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedisFailoverStatus) DeepCopyInto(out *RedisFailoverStatus) {
+	*out = *in
+	if in.PasswordRotation != nil {
+		in, out := &in.PasswordRotation, &out.PasswordRotation
+		*out = new(PasswordRotationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Switchover != nil {
+		in, out := &in.Switchover, &out.Switchover
+		*out = new(SwitchoverStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisFailoverStatus.
+func (in *RedisFailoverStatus) DeepCopy() *RedisFailoverStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RedisFailoverStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedisRoleServiceSettings) DeepCopyInto(out *RedisRoleServiceSettings) {
+	*out = *in
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisRoleServiceSettings.
+func (in *RedisRoleServiceSettings) DeepCopy() *RedisRoleServiceSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(RedisRoleServiceSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RedisSettings) DeepCopyInto(out *RedisSettings) {
 	*out = *in
@@ -325,6 +710,8 @@ func (in *RedisSettings) DeepCopyInto(out *RedisSettings) {
 			(*out)[key] = val
 		}
 	}
+	in.MasterService.DeepCopyInto(&out.MasterService)
+	in.ReplicasService.DeepCopyInto(&out.ReplicasService)
 	if in.ExtraVolumes != nil {
 		in, out := &in.ExtraVolumes, &out.ExtraVolumes
 		*out = make([]corev1.Volume, len(*in))
@@ -339,6 +726,12 @@ func (in *RedisSettings) DeepCopyInto(out *RedisSettings) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	in.PodDisruptionBudget.DeepCopyInto(&out.PodDisruptionBudget)
+	if in.Zones != nil {
+		in, out := &in.Zones, &out.Zones
+		*out = new(ZonesSettings)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -378,6 +771,24 @@ func (in *RedisStorage) DeepCopy() *RedisStorage {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicaOfSettings) DeepCopyInto(out *ReplicaOfSettings) {
+	*out = *in
+	out.RedisFailoverRef = in.RedisFailoverRef
+	out.MasterAuth = in.MasterAuth
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicaOfSettings.
+func (in *ReplicaOfSettings) DeepCopy() *ReplicaOfSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplicaOfSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SentinelConfigCopy) DeepCopyInto(out *SentinelConfigCopy) {
 	*out = *in
@@ -498,6 +909,7 @@ func (in *SentinelSettings) DeepCopyInto(out *SentinelSettings) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	in.PodDisruptionBudget.DeepCopyInto(&out.PodDisruptionBudget)
 	return
 }
 
@@ -510,3 +922,48 @@ func (in *SentinelSettings) DeepCopy() *SentinelSettings {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwitchoverStatus) DeepCopyInto(out *SwitchoverStatus) {
+	*out = *in
+	if in.ReplicaPriorities != nil {
+		in, out := &in.ReplicaPriorities, &out.ReplicaPriorities
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwitchoverStatus.
+func (in *SwitchoverStatus) DeepCopy() *SwitchoverStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SwitchoverStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ZonesSettings) DeepCopyInto(out *ZonesSettings) {
+	*out = *in
+	if in.Names != nil {
+		in, out := &in.Names, &out.Names
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ZonesSettings.
+func (in *ZonesSettings) DeepCopy() *ZonesSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(ZonesSettings)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spotahome/redis-operator/service/k8s"
+)
+
+// memoryRE matches the memory units redis accepts in its configuration, CONFIG GET returns bytes
+var memoryRE = regexp.MustCompile(`^(?i)([0-9]+)(k|kb|m|mb|g|gb)$`)
+
+var memoryUnits = map[string]int64{
+	"k":  1000,
+	"kb": 1024,
+	"m":  1000 * 1000,
+	"mb": 1024 * 1024,
+	"g":  1000 * 1000 * 1000,
+	"gb": 1024 * 1024 * 1024,
+}
+
+// configDifference is a configuration parameter of a redis whose value isn't the desired one
+type configDifference struct {
+	pod       string
+	parameter string
+	desired   string
+	live      string
+}
+
+func runConfig(e *env, args []string) error {
+	if len(args) == 0 || args[0] != "diff" {
+		return fmt.Errorf("unknown config command, expected: config diff <name>")
+	}
+	positional, err := parseArgs(flag.NewFlagSet("config diff", flag.ContinueOnError), args[1:], 1)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), e.timeout)
+	defer cancel()
+	rf, err := e.getRedisFailover(ctx, positional[0])
+	if err != nil {
+		return err
+	}
+	topology, err := e.checker.GetTopology(ctx, rf)
+	if err != nil {
+		return err
+	}
+	password, err := k8s.GetRedisPassword(ctx, e.k8sService, rf)
+	if err != nil {
+		return err
+	}
+	port := strconv.Itoa(int(rf.Spec.Redis.Port))
+
+	desired := parseCustomConfig(rf.Spec.Redis.CustomConfig)
+	parameters := []string{}
+	for parameter := range desired {
+		parameters = append(parameters, parameter)
+	}
+	sort.Strings(parameters)
+
+	differences := []configDifference{}
+	for _, redis := range topology.Redises {
+		if redis.Error != "" {
+			fmt.Fprintf(e.out, "Skipping %s: %s\n", redis.Pod, redis.Error)
+			continue
+		}
+		live, err := e.redisClient.GetRedisConfig(ctx, redis.Address, port, parameters, password)
+		if err != nil {
+			return fmt.Errorf("getting the configuration of %s: %w", redis.Pod, err)
+		}
+		differences = append(differences, diffConfig(redis.Pod, parameters, desired, live)...)
+	}
+
+	if len(differences) == 0 {
+		fmt.Fprintf(e.out, "The configuration of the redises is the desired one\n")
+		return nil
+	}
+	w := tabwriter.NewWriter(e.out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "REDIS\tPARAMETER\tDESIRED\tLIVE")
+	for _, d := range differences {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", d.pod, d.parameter, d.desired, d.live)
+	}
+	return w.Flush()
+}
+
+// parseCustomConfig returns the parameters and values of the given custom config, the last one wins
+func parseCustomConfig(customConfig []string) map[string]string {
+	config := map[string]string{}
+	for _, c := range customConfig {
+		parameter, value, _ := strings.Cut(strings.TrimSpace(c), " ")
+		if parameter == "" {
+			continue
+		}
+		if value == `""` {
+			value = ""
+		}
+		config[strings.ToLower(parameter)] = value
+	}
+	return config
+}
+
+// diffConfig returns the parameters whose live value isn't the desired one
+func diffConfig(pod string, parameters []string, desired, live map[string]string) []configDifference {
+	differences := []configDifference{}
+	for _, parameter := range parameters {
+		liveValue, ok := live[parameter]
+		if !ok {
+			liveValue = "<unknown>"
+		}
+		if ok && normalizeConfigValue(desired[parameter]) == normalizeConfigValue(liveValue) {
+			continue
+		}
+		differences = append(differences, configDifference{pod: pod, parameter: parameter, desired: desired[parameter], live: liveValue})
+	}
+	return differences
+}
+
+// normalizeConfigValue returns the value the way CONFIG GET returns it
+func normalizeConfigValue(value string) string {
+	value = strings.ToLower(strings.Join(strings.Fields(value), " "))
+	if match := memoryRE.FindStringSubmatch(value); match != nil {
+		n, err := strconv.ParseInt(match[1], 10, 64)
+		if err == nil {
+			return strconv.FormatInt(n*memoryUnits[strings.ToLower(match[2])], 10)
+		}
+	}
+	return value
+}
@@ -0,0 +1,385 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"text/tabwriter"
+
+	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
+	rfservice "github.com/spotahome/redis-operator/operator/redisfailover/service"
+	"github.com/spotahome/redis-operator/service/k8s"
+)
+
+const (
+	severityOK      = "ok"
+	severityWarning = "warning"
+	severityError   = "error"
+)
+
+var severityLevels = map[string]int{
+	severityOK:      0,
+	severityWarning: 1,
+	severityError:   2,
+}
+
+// finding is the result of a check of the doctor on a RedisFailover, or on one of its pods or objects
+type finding struct {
+	Check    string `json:"check"`
+	Subject  string `json:"subject,omitempty"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// report holds the findings of the doctor on a RedisFailover
+type report struct {
+	Namespace string    `json:"namespace"`
+	Name      string    `json:"name"`
+	Findings  []finding `json:"findings"`
+}
+
+// doctor runs the read-only checks on a RedisFailover. It only holds the checker, never the healer, so it can't
+// change anything
+type doctor struct {
+	ctx      context.Context
+	e        *env
+	rf       *redisfailoverv1.RedisFailover
+	topology *rfservice.Topology
+	findings []finding
+}
+
+func runDoctor(e *env, args []string) error {
+	flags := flag.NewFlagSet("doctor", flag.ContinueOnError)
+	output := flags.String("o", "text", "output format, text, json or junit")
+	failOn := flags.String("fail-on", severityError, "severity of the findings making the command fail, warning or error")
+	positional, err := parseArgs(flags, args, 1)
+	if err != nil {
+		return err
+	}
+	failLevel, ok := severityLevels[*failOn]
+	if !ok || failLevel == 0 {
+		return fmt.Errorf("unknown severity %q", *failOn)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), e.timeout)
+	defer cancel()
+	rf, err := e.getRedisFailover(ctx, positional[0])
+	if err != nil {
+		return err
+	}
+	topology, err := e.checker.GetTopology(ctx, rf)
+	if err != nil {
+		return err
+	}
+
+	d := &doctor{ctx: ctx, e: e, rf: rf, topology: topology}
+	d.checkRedises()
+	d.checkRedisPods()
+	if rf.SentinelsAllowed() {
+		d.checkSentinels()
+	}
+	d.checkRedisConfig()
+	d.add(renameFindings(rf.Spec.Redis.CustomCommandRenames)...)
+	if rf.SentinelsAllowed() {
+		d.add(quorumFindings(rf.Spec.Sentinel.Replicas, topology.Sentinels)...)
+	}
+	d.checkPodDisruptionBudgets()
+
+	r := report{Namespace: rf.Namespace, Name: rf.Name, Findings: d.findings}
+	switch *output {
+	case "json":
+		encoder := json.NewEncoder(e.out)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(r)
+	case "junit":
+		err = writeJUnit(e.out, r, failLevel)
+	case "text":
+		err = writeText(e.out, r)
+	default:
+		return fmt.Errorf("unknown output format %q", *output)
+	}
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, f := range r.Findings {
+		if severityLevels[f.Severity] >= failLevel {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d findings with severity %s or higher", failed, *failOn)
+	}
+	return nil
+}
+
+func (d *doctor) add(findings ...finding) {
+	d.findings = append(d.findings, findings...)
+}
+
+// result adds an error finding when the given error isn't nil, an ok finding with the given message otherwise
+func (d *doctor) result(check, subject string, err error, message string) {
+	if err != nil {
+		d.add(finding{Check: check, Subject: subject, Severity: severityError, Message: err.Error()})
+		return
+	}
+	d.add(finding{Check: check, Subject: subject, Severity: severityOK, Message: message})
+}
+
+// checkRedises runs the checks the operator runs on the redises before healing them
+func (d *doctor) checkRedises() {
+	rf := d.rf
+	d.result("redis-number", rfservice.GetRedisName(rf), d.e.checker.CheckRedisNumber(d.ctx, rf), fmt.Sprintf("%d redises", rf.Spec.Redis.Replicas))
+	if d.e.checker.IsRedisRunning(d.ctx, rf) {
+		d.add(finding{Check: "redis-running", Severity: severityOK, Message: "every redis is running"})
+	} else {
+		d.add(finding{Check: "redis-running", Severity: severityError, Message: "not every redis is running"})
+	}
+
+	rotating, err := d.e.checker.IsPasswordRotating(d.ctx, rf)
+	switch {
+	case err != nil:
+		d.result("password-rotation", "", err, "")
+	case rotating:
+		d.add(finding{Check: "password-rotation", Severity: severityWarning, Message: "the redises still accept the previous password"})
+	}
+
+	nMasters, err := d.e.checker.GetNumberMasters(d.ctx, rf)
+	if err != nil {
+		d.result("masters", "", err, "")
+		return
+	}
+
+	// Bootstrapping and replicating failovers replicate a master outside of them
+	var master string
+	if rf.Bootstrapping() || rf.Replicating() {
+		if nMasters != 0 {
+			d.add(finding{Check: "masters", Severity: severityError, Message: fmt.Sprintf("%d redises are masters while replicating an external master", nMasters)})
+			return
+		}
+		if rf.Bootstrapping() {
+			master, _, err = d.e.checker.GetBootstrapMaster(d.ctx, rf)
+		} else {
+			master, _, err = d.e.checker.GetReplicaOfMaster(d.ctx, rf)
+		}
+		if err != nil {
+			d.result("masters", "", err, "")
+			return
+		}
+		d.add(finding{Check: "masters", Severity: severityOK, Message: fmt.Sprintf("replicating the external master %s", master)})
+	} else {
+		switch nMasters {
+		case 0:
+			message := "no redis is master"
+			if localhost, err := d.e.checker.CheckIfMasterLocalhost(d.ctx, rf); err == nil && localhost {
+				message = "no redis is master, every redis replicates localhost as after a first boot"
+			}
+			d.add(finding{Check: "masters", Severity: severityError, Message: message})
+			return
+		case 1:
+		default:
+			d.add(finding{Check: "masters", Severity: severityError, Message: fmt.Sprintf("%d redises are masters", nMasters)})
+			return
+		}
+		if master, err = d.e.checker.GetMasterIP(d.ctx, rf); err != nil {
+			d.result("masters", "", err, "")
+			return
+		}
+		d.add(finding{Check: "masters", Severity: severityOK, Message: fmt.Sprintf("%s is the only master", master)})
+	}
+	d.result("replication", "", d.e.checker.CheckAllSlavesFromMaster(d.ctx, master, rf), fmt.Sprintf("every replica follows %s", master))
+}
+
+// checkRedisPods checks every redis is reachable, in sync with its master and up to date with its statefulset
+func (d *doctor) checkRedisPods() {
+	rf := d.rf
+	updateRevisions, err := d.e.checker.GetStatefulSetUpdateRevisions(d.ctx, rf)
+	if err != nil {
+		d.result("revision", "", err, "")
+	}
+	for _, redis := range d.topology.Redises {
+		if redis.Error != "" {
+			d.add(finding{Check: "redis-reachable", Subject: redis.Pod, Severity: severityError, Message: redis.Error})
+			continue
+		}
+		if redis.Role != "master" {
+			ready, err := d.e.checker.CheckRedisSlavesReady(d.ctx, redis.Address, rf)
+			if err == nil && !ready {
+				err = fmt.Errorf("replica not in sync with its master, link %s", redis.LinkStatus)
+			}
+			d.result("replica-sync", redis.Pod, err, "in sync with its master")
+		}
+		updateRevision := updateRevisions[rfservice.GetPodStatefulSetName(redis.Pod)]
+		if updateRevision != "" && redis.RevisionHash != updateRevision {
+			d.add(finding{Check: "revision", Subject: redis.Pod, Severity: severityWarning, Message: fmt.Sprintf("revision %s isn't the one of the statefulset, %s", redis.RevisionHash, updateRevision)})
+		}
+	}
+}
+
+// checkSentinels runs the checks the operator runs on the sentinels before healing them
+func (d *doctor) checkSentinels() {
+	rf := d.rf
+	d.result("sentinel-number", rfservice.GetSentinelName(rf), d.e.checker.CheckSentinelNumber(d.ctx, rf), fmt.Sprintf("%d sentinels", rf.Spec.Sentinel.Replicas))
+	if d.e.checker.IsSentinelRunning(d.ctx, rf) {
+		d.add(finding{Check: "sentinel-running", Severity: severityOK, Message: "every sentinel is running"})
+	} else {
+		d.add(finding{Check: "sentinel-running", Severity: severityError, Message: "not every sentinel is running"})
+	}
+	unhealthy, err := d.e.checker.CheckSentinelQuorum(d.ctx, rf)
+	if err != nil {
+		err = fmt.Errorf("%d sentinels can't authorize a failover: %w", unhealthy, err)
+	}
+	d.result("sentinel-quorum", "", err, "the sentinels can authorize a failover")
+
+	// The sentinels are expected to monitor the master the redises agree on, by the address it announces
+	var master, port string
+	if rf.Bootstrapping() {
+		master, port, err = d.e.checker.GetBootstrapMaster(d.ctx, rf)
+	} else {
+		master, err = d.e.checker.GetMasterIP(d.ctx, rf)
+		port = strconv.Itoa(int(rf.Spec.Redis.Port))
+	}
+	if err != nil {
+		// already reported by the checks of the redises
+		return
+	}
+	monitor := master
+	if rf.Spec.ExternalAccess != nil && !rf.Bootstrapping() {
+		external, err := d.e.checker.GetRedisesExternalAddresses(d.ctx, rf)
+		if err != nil {
+			d.result("sentinel-monitor", "", err, "")
+			return
+		}
+		if monitor, port, err = net.SplitHostPort(external[master]); err != nil {
+			d.add(finding{Check: "sentinel-monitor", Severity: severityError, Message: fmt.Sprintf("master %s has no external address", master)})
+			return
+		}
+	}
+
+	for _, sentinel := range d.topology.Sentinels {
+		if sentinel.Error != "" {
+			d.add(finding{Check: "sentinel-reachable", Subject: sentinel.Pod, Severity: severityError, Message: sentinel.Error})
+			continue
+		}
+		d.result("sentinel-monitor", sentinel.Pod, d.e.checker.CheckSentinelMonitor(d.ctx, sentinel.Address, rf, monitor, port), fmt.Sprintf("monitors %s", net.JoinHostPort(monitor, port)))
+		d.result("sentinel-sentinels", sentinel.Pod, d.e.checker.CheckSentinelNumberInMemory(d.ctx, sentinel.Address, rf), "knows every other sentinel")
+		d.result("sentinel-replicas", sentinel.Pod, d.e.checker.CheckSentinelSlavesNumberInMemory(d.ctx, sentinel.Address, rf), "knows every replica")
+	}
+}
+
+// checkRedisConfig checks the memory and persistence settings the redises run with
+func (d *doctor) checkRedisConfig() {
+	password, err := k8s.GetRedisPassword(d.ctx, d.e.k8sService, d.rf)
+	if err != nil {
+		d.result("redis-config", "", err, "")
+		return
+	}
+	port := strconv.Itoa(int(d.rf.Spec.Redis.Port))
+
+	persistence := []redisPersistence{}
+	for _, redis := range d.topology.Redises {
+		if redis.Error != "" {
+			continue
+		}
+		config, err := d.e.redisClient.GetRedisConfig(d.ctx, redis.Address, port, []string{"maxmemory", "save", "appendonly"}, password)
+		if err != nil {
+			d.result("redis-config", redis.Pod, err, "")
+			continue
+		}
+		d.add(memoryFinding(redis.Pod, config["maxmemory"], d.rf.Spec.Redis.Resources.Limits.Memory()))
+		persistence = append(persistence, redisPersistence{pod: redis.Pod, role: redis.Role, save: config["save"], appendonly: config["appendonly"]})
+	}
+	if len(persistence) > 0 {
+		d.add(persistenceFindings(persistence, d.rf.Spec.Redis.Storage.PersistentVolumeClaim != nil)...)
+	}
+}
+
+// checkPodDisruptionBudgets checks the budgets the operator manages leave enough redises, sentinels and proxies during
+// the voluntary disruptions
+func (d *doctor) checkPodDisruptionBudgets() {
+	rf := d.rf
+	for _, budget := range rfservice.GetPodDisruptionBudgets(rf) {
+		if budget.Disabled {
+			d.add(finding{Check: "pdb", Subject: budget.Name, Severity: severityOK, Message: "disabled by the spec"})
+			continue
+		}
+		// a majority of sentinels elects the one failing the master over, a single pod serves the others
+		minRunning := int32(1)
+		if budget.Name == rfservice.GetSentinelName(rf) {
+			minRunning = majority(budget.Replicas)
+		}
+		pdb, err := d.e.k8sService.GetPodDisruptionBudget(d.ctx, rf.Namespace, budget.Name)
+		d.add(pdbFinding(budget.Name, pdb, err, budget.Replicas, minRunning))
+	}
+}
+
+func writeText(out io.Writer, r report) error {
+	counts := map[string]int{}
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "SEVERITY\tCHECK\tSUBJECT\tMESSAGE")
+	for _, f := range r.Findings {
+		counts[f.Severity]++
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", f.Severity, f.Check, dash(f.Subject), f.Message)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(out, "\n%d checks: %d ok, %d warnings, %d errors\n", len(r.Findings), counts[severityOK], counts[severityWarning], counts[severityError])
+	return err
+}
+
+type junitTestSuite struct {
+	XMLName   xml.Name        `xml:"testsuite"`
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+}
+
+// writeJUnit writes the findings as a JUnit test suite, failing the test cases of the findings of the given level
+// or higher
+func writeJUnit(out io.Writer, r report, failLevel int) error {
+	suite := junitTestSuite{Name: fmt.Sprintf("%s/%s", r.Namespace, r.Name), Tests: len(r.Findings)}
+	for _, f := range r.Findings {
+		testCase := junitTestCase{Name: f.Check, ClassName: suite.Name}
+		if f.Subject != "" {
+			testCase.Name = fmt.Sprintf("%s/%s", f.Check, f.Subject)
+		}
+		if severityLevels[f.Severity] >= failLevel {
+			testCase.Failure = &junitFailure{Message: f.Message, Type: f.Severity}
+			suite.Failures++
+		} else {
+			testCase.SystemOut = fmt.Sprintf("%s: %s", f.Severity, f.Message)
+		}
+		suite.TestCases = append(suite.TestCases, testCase)
+	}
+
+	if _, err := io.WriteString(out, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(out)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(suite); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintln(out)
+	return err
+}
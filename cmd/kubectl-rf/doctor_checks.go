@@ -0,0 +1,215 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
+	rfservice "github.com/spotahome/redis-operator/operator/redisfailover/service"
+)
+
+// maxMemoryRatio is the share of the memory limit maxmemory shouldn't exceed, the rest is left to the replication
+// buffers and to the copy-on-write of the persistence forks
+const maxMemoryRatio = 0.75
+
+// requiredCommands are the commands the operator and sentinel send to the redises
+var requiredCommands = map[string]bool{
+	"acl": true, "client": true, "config": true, "exec": true, "info": true, "multi": true, "ping": true,
+	"publish": true, "replicaof": true, "role": true, "script": true, "slaveof": true, "subscribe": true,
+}
+
+// disabledCommands are the commands the generated redis configuration renames to "" before the custom renames
+var disabledCommands = map[string]bool{
+	"debug": true, "flushall": true, "flushdb": true, "keys": true, "shutdown": true,
+}
+
+// redisPersistence is the persistence configuration a redis runs with
+type redisPersistence struct {
+	pod        string
+	role       string
+	save       string
+	appendonly string
+}
+
+func (p redisPersistence) persists() bool {
+	return p.save != "" || p.appendonly == "yes"
+}
+
+func (p redisPersistence) String() string {
+	return fmt.Sprintf("save %q, appendonly %s", p.save, p.appendonly)
+}
+
+// majority returns the number of sentinels that have to agree for one of them to lead a failover
+func majority(sentinels int32) int32 {
+	return sentinels/2 + 1
+}
+
+// renameFindings checks the custom renames don't break the operator nor prevent redis from starting
+func renameFindings(renames []redisfailoverv1.RedisCommandRename) []finding {
+	findings := []finding{}
+	renamed := map[string]bool{}
+	newNames := map[string]bool{}
+	for _, rename := range renames {
+		from, to := strings.ToLower(rename.From), strings.ToLower(rename.To)
+		message := ""
+		switch {
+		case requiredCommands[from]:
+			message = fmt.Sprintf("renaming %s breaks the operator and sentinel, which send it to the redises", from)
+		case disabledCommands[from]:
+			message = fmt.Sprintf("%s is already disabled by the operator, redis refuses to start renaming it again", from)
+		case renamed[from]:
+			message = fmt.Sprintf("%s is renamed more than once, redis refuses to start", from)
+		case to != "" && (newNames[to] || requiredCommands[to]):
+			message = fmt.Sprintf("%s is renamed to %s, a name already taken, redis refuses to start", from, to)
+		}
+		if message != "" {
+			findings = append(findings, finding{Check: "rename-command", Subject: from, Severity: severityError, Message: message})
+		}
+		renamed[from] = true
+		if to != "" {
+			newNames[to] = true
+		}
+	}
+	if len(findings) == 0 {
+		findings = append(findings, finding{Check: "rename-command", Severity: severityOK, Message: fmt.Sprintf("%d custom renames, none conflicting", len(renames))})
+	}
+	return findings
+}
+
+// memoryFinding checks the maxmemory of a redis, in bytes, leaves room within its memory limit
+func memoryFinding(pod, maxmemory string, limit *resource.Quantity) finding {
+	f := finding{Check: "maxmemory", Subject: pod, Severity: severityOK}
+	bytes, err := strconv.ParseInt(maxmemory, 10, 64)
+	if err != nil {
+		f.Severity, f.Message = severityError, fmt.Sprintf("unexpected maxmemory %q", maxmemory)
+		return f
+	}
+
+	switch {
+	case limit.IsZero() && bytes == 0:
+		f.Severity, f.Message = severityWarning, "neither maxmemory nor a memory limit are set"
+	case limit.IsZero():
+		f.Message = fmt.Sprintf("maxmemory %d bytes, no memory limit", bytes)
+	case bytes == 0:
+		f.Severity, f.Message = severityWarning, fmt.Sprintf("maxmemory isn't set, redis grows until it is killed for exceeding the %s memory limit", limit)
+	case bytes > limit.Value():
+		f.Severity, f.Message = severityError, fmt.Sprintf("maxmemory %d bytes exceeds the %s memory limit", bytes, limit)
+	case float64(bytes) > maxMemoryRatio*float64(limit.Value()):
+		f.Severity, f.Message = severityWarning, fmt.Sprintf("maxmemory %d bytes leaves less than %.0f%% of the %s memory limit to the replication buffers and the persistence forks", bytes, (1-maxMemoryRatio)*100, limit)
+	default:
+		f.Message = fmt.Sprintf("maxmemory %d bytes within the %s memory limit", bytes, limit)
+	}
+	return f
+}
+
+// persistenceFindings checks the persistence of the redises given their role, and that any of them can be promoted
+// without changing the persistence of the master
+func persistenceFindings(redises []redisPersistence, persistentVolume bool) []finding {
+	findings := []finding{}
+	settings := map[string][]string{}
+	for _, redis := range redises {
+		switch {
+		case redis.role == "master" && !redis.persists():
+			findings = append(findings, finding{Check: "persistence", Subject: redis.pod, Severity: severityWarning, Message: "the master persists nothing, restarted before sentinel fails it over it wipes the data of its replicas"})
+		case redis.persists() && !persistentVolume:
+			findings = append(findings, finding{Check: "persistence", Subject: redis.pod, Severity: severityWarning, Message: "persisted to a volume lost with the pod, set a persistentVolumeClaim"})
+		}
+		settings[redis.String()] = append(settings[redis.String()], redis.pod)
+	}
+
+	if len(settings) > 1 {
+		differences := []string{}
+		for setting, pods := range settings {
+			differences = append(differences, fmt.Sprintf("%s: %s", strings.Join(pods, ","), setting))
+		}
+		sort.Strings(differences)
+		findings = append(findings, finding{Check: "persistence", Severity: severityWarning, Message: fmt.Sprintf("the redises persist differently, promoting a replica changes the persistence of the master (%s)", strings.Join(differences, "; "))})
+	}
+	if len(findings) == 0 {
+		findings = append(findings, finding{Check: "persistence", Severity: severityOK, Message: fmt.Sprintf("every redis runs with %s", redises[0])})
+	}
+	return findings
+}
+
+// quorumFindings checks the given number of sentinels, and the quorum they run with, tolerate losing some of them
+func quorumFindings(replicas int32, sentinels []rfservice.SentinelTopology) []finding {
+	findings := []finding{}
+	switch {
+	case replicas < 3:
+		findings = append(findings, finding{Check: "quorum-math", Severity: severityWarning, Message: fmt.Sprintf("%d sentinels can't fail the master over after losing one, at least 3 are needed", replicas)})
+	case replicas%2 == 0:
+		findings = append(findings, finding{Check: "quorum-math", Severity: severityWarning, Message: fmt.Sprintf("%d sentinels tolerate the loss of as many as %d, an odd number is advised", replicas, replicas-1)})
+	}
+
+	quorum := majority(replicas)
+	quorums := map[int]bool{}
+	for _, sentinel := range sentinels {
+		if sentinel.Error != "" {
+			continue
+		}
+		quorums[sentinel.Quorum] = true
+		switch {
+		case int32(sentinel.Quorum) > replicas:
+			findings = append(findings, finding{Check: "quorum-math", Subject: sentinel.Pod, Severity: severityError, Message: fmt.Sprintf("quorum %d can't be reached with %d sentinels, the master is never failed over", sentinel.Quorum, replicas)})
+		case int32(sentinel.Quorum) < majority(replicas):
+			findings = append(findings, finding{Check: "quorum-math", Subject: sentinel.Pod, Severity: severityWarning, Message: fmt.Sprintf("quorum %d is less than a majority, a minority of sentinels agrees the master is down without being able to fail it over", sentinel.Quorum)})
+		default:
+			quorum = int32(sentinel.Quorum)
+		}
+	}
+	if len(quorums) > 1 {
+		findings = append(findings, finding{Check: "quorum-math", Severity: severityWarning, Message: "the sentinels run with different quorums"})
+	}
+	if len(findings) == 0 {
+		findings = append(findings, finding{Check: "quorum-math", Severity: severityOK, Message: fmt.Sprintf("%d sentinels with quorum %d tolerate the loss of %d", replicas, quorum, replicas-quorum)})
+	}
+	return findings
+}
+
+// pdbFinding checks the given budget of the given number of pods lets node drains go on, and leaves at least the
+// given number of pods running during them
+func pdbFinding(name string, pdb *policyv1.PodDisruptionBudget, err error, replicas, minRunning int32) finding {
+	f := finding{Check: "pdb", Subject: name, Severity: severityOK}
+	if errors.IsNotFound(err) {
+		f.Severity, f.Message = severityWarning, "no PodDisruptionBudget, a node drain can evict every pod at once"
+		return f
+	}
+	if err != nil {
+		f.Severity, f.Message = severityError, err.Error()
+		return f
+	}
+
+	allowed := int(replicas)
+	switch {
+	case pdb.Spec.MaxUnavailable != nil:
+		allowed, err = intstr.GetScaledValueFromIntOrPercent(pdb.Spec.MaxUnavailable, int(replicas), true)
+	case pdb.Spec.MinAvailable != nil:
+		var minAvailable int
+		minAvailable, err = intstr.GetScaledValueFromIntOrPercent(pdb.Spec.MinAvailable, int(replicas), true)
+		allowed = int(replicas) - minAvailable
+	}
+	if err != nil {
+		f.Severity, f.Message = severityError, err.Error()
+		return f
+	}
+	if allowed < 0 {
+		allowed = 0
+	}
+
+	switch running := int(replicas) - allowed; {
+	case running < int(minRunning):
+		f.Severity, f.Message = severityError, fmt.Sprintf("allows evicting %d of %d pods at once, leaving %d while %d are needed", allowed, replicas, running, minRunning)
+	case allowed == 0:
+		f.Severity, f.Message = severityWarning, fmt.Sprintf("allows evicting none of %d pods, node drains block", replicas)
+	default:
+		f.Message = fmt.Sprintf("allows evicting %d of %d pods at once", allowed, replicas)
+	}
+	return f
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
+	mK8SService "github.com/spotahome/redis-operator/mocks/service/k8s"
+	rfservice "github.com/spotahome/redis-operator/operator/redisfailover/service"
+)
+
+func severities(findings []finding) []string {
+	s := []string{}
+	for _, f := range findings {
+		s = append(s, f.Severity)
+	}
+	return s
+}
+
+func TestRenameFindings(t *testing.T) {
+	tests := []struct {
+		name    string
+		renames []redisfailoverv1.RedisCommandRename
+		exp     []string
+	}{
+		{name: "no conflict", renames: []redisfailoverv1.RedisCommandRename{{From: "flushall2", To: ""}, {From: "eval", To: "myeval"}}, exp: []string{severityOK}},
+		{name: "required command", renames: []redisfailoverv1.RedisCommandRename{{From: "CONFIG", To: "myconfig"}}, exp: []string{severityError}},
+		{name: "already disabled", renames: []redisfailoverv1.RedisCommandRename{{From: "keys", To: "mykeys"}}, exp: []string{severityError}},
+		{name: "renamed twice", renames: []redisfailoverv1.RedisCommandRename{{From: "eval", To: "a"}, {From: "eval", To: "b"}}, exp: []string{severityError}},
+		{name: "same new name", renames: []redisfailoverv1.RedisCommandRename{{From: "eval", To: "a"}, {From: "evalsha", To: "a"}}, exp: []string{severityError}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.exp, severities(renameFindings(test.renames)))
+		})
+	}
+}
+
+func TestMemoryFinding(t *testing.T) {
+	limit := resource.MustParse("1Gi")
+	tests := []struct {
+		name      string
+		maxmemory string
+		limit     *resource.Quantity
+		exp       string
+	}{
+		{name: "nothing set", maxmemory: "0", limit: &resource.Quantity{}, exp: severityWarning},
+		{name: "no limit", maxmemory: "1000", limit: &resource.Quantity{}, exp: severityOK},
+		{name: "no maxmemory", maxmemory: "0", limit: &limit, exp: severityWarning},
+		{name: "above the limit", maxmemory: "2147483648", limit: &limit, exp: severityError},
+		{name: "no room left", maxmemory: "1000000000", limit: &limit, exp: severityWarning},
+		{name: "within the limit", maxmemory: "536870912", limit: &limit, exp: severityOK},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.exp, memoryFinding("rfr-test-0", test.maxmemory, test.limit).Severity)
+		})
+	}
+}
+
+func TestPersistenceFindings(t *testing.T) {
+	assert := assert.New(t)
+
+	redises := []redisPersistence{
+		{pod: "rfr-test-0", role: "master", save: "900 1", appendonly: "no"},
+		{pod: "rfr-test-1", role: "slave", save: "900 1", appendonly: "no"},
+	}
+	assert.Equal([]string{severityOK}, severities(persistenceFindings(redises, true)))
+	assert.Equal([]string{severityWarning, severityWarning}, severities(persistenceFindings(redises, false)))
+
+	redises[0].save = ""
+	findings := persistenceFindings(redises, true)
+	assert.Equal([]string{severityWarning, severityWarning}, severities(findings))
+	assert.Equal("rfr-test-0", findings[0].Subject)
+}
+
+func TestQuorumFindings(t *testing.T) {
+	tests := []struct {
+		name      string
+		replicas  int32
+		sentinels []rfservice.SentinelTopology
+		exp       []string
+	}{
+		{name: "majority", replicas: 3, sentinels: []rfservice.SentinelTopology{{Pod: "rfs-test-0", Quorum: 2}, {Pod: "rfs-test-1", Quorum: 2}}, exp: []string{severityOK}},
+		{name: "too few sentinels", replicas: 1, sentinels: []rfservice.SentinelTopology{{Pod: "rfs-test-0", Quorum: 1}}, exp: []string{severityWarning}},
+		{name: "even sentinels", replicas: 4, sentinels: []rfservice.SentinelTopology{{Pod: "rfs-test-0", Quorum: 3}}, exp: []string{severityWarning}},
+		{name: "unreachable quorum", replicas: 3, sentinels: []rfservice.SentinelTopology{{Pod: "rfs-test-0", Quorum: 4}}, exp: []string{severityError}},
+		{name: "minority quorum", replicas: 5, sentinels: []rfservice.SentinelTopology{{Pod: "rfs-test-0", Quorum: 3}, {Pod: "rfs-test-1", Quorum: 2}}, exp: []string{severityWarning, severityWarning}},
+		{name: "unreachable sentinel", replicas: 3, sentinels: []rfservice.SentinelTopology{{Pod: "rfs-test-0", Error: "timeout"}}, exp: []string{severityOK}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.exp, severities(quorumFindings(test.replicas, test.sentinels)))
+		})
+	}
+}
+
+func TestPDBFinding(t *testing.T) {
+	pdb := func(minAvailable, maxUnavailable *intstr.IntOrString) *policyv1.PodDisruptionBudget {
+		return &policyv1.PodDisruptionBudget{Spec: policyv1.PodDisruptionBudgetSpec{MinAvailable: minAvailable, MaxUnavailable: maxUnavailable}}
+	}
+	two := intstr.FromInt(2)
+	three := intstr.FromInt(3)
+	half := intstr.FromString("50%")
+	notFound := errors.NewNotFound(schema.GroupResource{Group: "policy", Resource: "poddisruptionbudgets"}, "rfs-test")
+
+	tests := []struct {
+		name       string
+		pdb        *policyv1.PodDisruptionBudget
+		err        error
+		replicas   int32
+		minRunning int32
+		exp        string
+	}{
+		{name: "missing", err: notFound, replicas: 3, minRunning: 2, exp: severityWarning},
+		{name: "adequate", pdb: pdb(&two, nil), replicas: 3, minRunning: 2, exp: severityOK},
+		{name: "blocking drains", pdb: pdb(&three, nil), replicas: 3, minRunning: 2, exp: severityWarning},
+		{name: "breaking the majority", pdb: pdb(nil, &half), replicas: 3, minRunning: 2, exp: severityError},
+		{name: "everything evictable", pdb: pdb(nil, nil), replicas: 3, minRunning: 1, exp: severityError},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.exp, pdbFinding("rfs-test", test.pdb, test.err, test.replicas, test.minRunning).Severity)
+		})
+	}
+}
+
+func TestCheckPodDisruptionBudgets(t *testing.T) {
+	assert := assert.New(t)
+
+	two := intstr.FromInt(2)
+	rf := &redisfailoverv1.RedisFailover{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "testns"}}
+	rf.Spec.Redis.Replicas = 3
+	rf.Spec.Sentinel.Replicas = 3
+	rf.Spec.Proxy.Type = redisfailoverv1.ProxyTypeEnvoy
+	rf.Spec.Proxy.Envoy.Replicas = 2
+	rf.Spec.Proxy.Envoy.PodDisruptionBudget.Disabled = true
+
+	ms := &mK8SService.Services{}
+	ms.On("GetPodDisruptionBudget", mock.Anything, "testns", "rfr-test").Once().Return(&policyv1.PodDisruptionBudget{Spec: policyv1.PodDisruptionBudgetSpec{MinAvailable: &two}}, nil)
+	ms.On("GetPodDisruptionBudget", mock.Anything, "testns", "rfs-test").Once().Return(&policyv1.PodDisruptionBudget{Spec: policyv1.PodDisruptionBudgetSpec{MinAvailable: &two}}, nil)
+
+	d := &doctor{ctx: context.Background(), e: &env{k8sService: ms}, rf: rf}
+	d.checkPodDisruptionBudgets()
+
+	subjects := []string{}
+	for _, f := range d.findings {
+		subjects = append(subjects, f.Subject)
+	}
+	assert.Equal([]string{"rfr-test", "rfs-test", rfservice.GetEnvoyName(rf)}, subjects)
+	assert.Equal([]string{severityOK, severityOK, severityOK}, severities(d.findings), "a disabled budget is no finding to fix")
+	ms.AssertExpectations(t)
+}
+
+func TestWriteJUnit(t *testing.T) {
+	assert := assert.New(t)
+
+	r := report{Namespace: "testns", Name: "test", Findings: []finding{
+		{Check: "masters", Severity: severityOK, Message: "1.1.1.1 is the only master"},
+		{Check: "pdb", Subject: "rfs-test", Severity: severityWarning, Message: "node drains block"},
+		{Check: "sentinel-monitor", Subject: "rfs-test-0", Severity: severityError, Message: "sentinel monitoring 0.0.0.0"},
+	}}
+	out := &bytes.Buffer{}
+	assert.NoError(writeJUnit(out, r, severityLevels[severityWarning]))
+
+	assert.Contains(out.String(), `<testsuite name="testns/test" tests="3" failures="2">`)
+	assert.Contains(out.String(), `<testcase name="pdb/rfs-test" classname="testns/test">`)
+	assert.Contains(out.String(), `<failure message="sentinel monitoring 0.0.0.0" type="error"></failure>`)
+	assert.Contains(out.String(), `<system-out>ok: 1.1.1.1 is the only master</system-out>`)
+}
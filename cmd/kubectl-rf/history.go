@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+
+	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
+)
+
+// failoverEventReasonPrefix is the prefix of the reasons of the events the operator records on the failovers
+const failoverEventReasonPrefix = "Failover"
+
+func runFailoverHistory(e *env, args []string) error {
+	positional, err := parseArgs(flag.NewFlagSet("failover-history", flag.ContinueOnError), args, 1)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), e.timeout)
+	defer cancel()
+	selector := fields.Set{
+		"involvedObject.kind": redisfailoverv1.RFKind,
+		"involvedObject.name": positional[0],
+	}.AsSelector().String()
+	events, err := e.k8sClient.CoreV1().Events(e.namespace).List(ctx, metav1.ListOptions{FieldSelector: selector})
+	if err != nil {
+		return err
+	}
+
+	failovers := []corev1.Event{}
+	for _, event := range events.Items {
+		if strings.HasPrefix(event.Reason, failoverEventReasonPrefix) {
+			failovers = append(failovers, event)
+		}
+	}
+	if len(failovers) == 0 {
+		fmt.Fprintf(e.out, "No failover recorded, events are kept by Kubernetes for a limited time\n")
+		return nil
+	}
+	sort.Slice(failovers, func(i, j int) bool {
+		return eventTime(failovers[i]).Before(eventTime(failovers[j]))
+	})
+
+	w := tabwriter.NewWriter(e.out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "TIME\tTYPE\tREASON\tMESSAGE")
+	for _, event := range failovers {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", eventTime(event).Format(time.RFC3339), event.Type, event.Reason, event.Message)
+	}
+	return w.Flush()
+}
+
+func eventTime(event corev1.Event) time.Time {
+	if !event.LastTimestamp.IsZero() {
+		return event.LastTimestamp.Time
+	}
+	return event.EventTime.Time
+}
@@ -0,0 +1,178 @@
+// kubectl-rf is a kubectl plugin for the day-2 operations of the RedisFailovers, run as `kubectl rf <command>`.
+// The redises and sentinels are reached through port forwarding, so it runs from outside of the cluster.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	apiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+
+	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
+	redisfailoverclientset "github.com/spotahome/redis-operator/client/k8s/clientset/versioned"
+	"github.com/spotahome/redis-operator/log"
+	"github.com/spotahome/redis-operator/metrics"
+	rfservice "github.com/spotahome/redis-operator/operator/redisfailover/service"
+	"github.com/spotahome/redis-operator/service/k8s"
+	"github.com/spotahome/redis-operator/service/redis"
+)
+
+// command is a subcommand of the plugin, given the environment and its arguments
+type command struct {
+	usage string
+	run   func(e *env, args []string) error
+}
+
+var commands = map[string]command{
+	"status":           {usage: "status <name> [-o json]: the topology of the redises and sentinels", run: runStatus},
+	"switchover":       {usage: "switchover <name> [--to pod]: fail the master over, to the given replica if any", run: runSwitchover},
+	"pause":            {usage: "pause <name>: make the operator leave the redisfailover untouched", run: runPause},
+	"resume":           {usage: "resume <name>: give the redisfailover back to the operator", run: runResume},
+	"reset-sentinels":  {usage: "reset-sentinels <name>: reset the sentinels one at a time", run: runResetSentinels},
+	"failover-history": {usage: "failover-history <name>: the failovers recorded by the operator", run: runFailoverHistory},
+	"config":           {usage: "config diff <name>: the redis configurations differing from the spec", run: runConfig},
+	"doctor":           {usage: "doctor <name> [-o text|json|junit] [-fail-on warning|error]: read-only checks reporting findings", run: runDoctor},
+}
+
+// env holds the clients the commands work with
+type env struct {
+	namespace    string
+	timeout      time.Duration
+	out          io.Writer
+	k8sClient    kubernetes.Interface
+	customClient redisfailoverclientset.Interface
+	k8sService   k8s.Services
+	redisClient  redis.Client
+	checker      rfservice.RedisFailoverCheck
+	healer       rfservice.RedisFailoverHeal
+}
+
+// getRedisFailover returns the given RedisFailover, with the defaults the operator applies
+func (e *env) getRedisFailover(ctx context.Context, name string) (*redisfailoverv1.RedisFailover, error) {
+	rf, err := e.k8sService.GetRedisFailover(ctx, e.namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	if err := rf.Validate(); err != nil {
+		return nil, err
+	}
+	return rf, nil
+}
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("kubectl-rf", flag.ContinueOnError)
+	flags.SetOutput(out)
+	kubeContext := flags.String("context", "", "kubeconfig context to use")
+	namespace := flags.String("n", "", "namespace of the redisfailover, the one of the context by default")
+	direct := flags.Bool("direct", false, "reach the pods directly instead of through port forwarding, when running inside the cluster")
+	timeout := flags.Duration("timeout", time.Minute, "maximum time to wait for the operations to complete")
+	flags.Usage = func() {
+		fmt.Fprintf(out, "Usage: kubectl rf [flags] <command>\n\nCommands:\n")
+		names := []string{}
+		for name := range commands {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Fprintf(out, "  %s\n", commands[name].usage)
+		}
+		fmt.Fprintf(out, "\nFlags:\n")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return fmt.Errorf("missing command")
+	}
+	cmd, ok := commands[flags.Arg(0)]
+	if !ok {
+		flags.Usage()
+		return fmt.Errorf("unknown command %q", flags.Arg(0))
+	}
+
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(clientcmd.NewDefaultClientConfigLoadingRules(), &clientcmd.ConfigOverrides{CurrentContext: *kubeContext})
+	config, err := clientConfig.ClientConfig()
+	if err != nil {
+		return fmt.Errorf("could not load configuration: %s", err)
+	}
+	if *namespace == "" {
+		if *namespace, _, err = clientConfig.Namespace(); err != nil {
+			return err
+		}
+	}
+	k8sClient, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+	customClient, err := redisfailoverclientset.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+	aeClientset, err := apiextensionsclientset.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	redisConfig := redis.DefaultConfig
+	if !*direct {
+		dialer := k8s.NewPortForwardDialer(config, k8sClient, *namespace)
+		defer dialer.Close()
+		redisConfig.Dialer = dialer.DialContext
+	}
+	redisClient := redis.New(metrics.Dummy, redisConfig)
+	k8sService := k8s.New(k8sClient, customClient, aeClientset, dynamicClient, log.Dummy, metrics.Dummy)
+
+	e := &env{
+		namespace:    *namespace,
+		timeout:      *timeout,
+		out:          out,
+		k8sClient:    k8sClient,
+		customClient: customClient,
+		k8sService:   k8sService,
+		redisClient:  redisClient,
+		checker:      rfservice.NewRedisFailoverChecker(k8sService, redisClient, log.Dummy, metrics.Dummy),
+		healer:       rfservice.NewRedisFailoverHealer(k8sService, redisClient, log.Dummy),
+	}
+	return cmd.run(e, flags.Args()[1:])
+}
+
+// parseArgs parses the flags of a command, given before or after its arguments, and returns the arguments
+func parseArgs(flags *flag.FlagSet, args []string, nArgs int) ([]string, error) {
+	positional := []string{}
+	for {
+		if err := flags.Parse(args); err != nil {
+			return nil, err
+		}
+		if flags.NArg() == 0 {
+			break
+		}
+		positional = append(positional, flags.Arg(0))
+		args = flags.Args()[1:]
+	}
+	if len(positional) != nArgs {
+		return nil, fmt.Errorf("expected %d arguments, got %q", nArgs, strings.Join(positional, " "))
+	}
+	return positional, nil
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
+	customfake "github.com/spotahome/redis-operator/client/k8s/clientset/versioned/fake"
+	mRFService "github.com/spotahome/redis-operator/mocks/operator/redisfailover/service"
+	mK8SService "github.com/spotahome/redis-operator/mocks/service/k8s"
+	mRedisService "github.com/spotahome/redis-operator/mocks/service/redis"
+	rfservice "github.com/spotahome/redis-operator/operator/redisfailover/service"
+)
+
+func TestParseArgs(t *testing.T) {
+	assert := assert.New(t)
+
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	to := flags.String("to", "", "")
+	args, err := parseArgs(flags, []string{"myrf", "--to", "rfr-myrf-1"}, 1)
+	assert.NoError(err)
+	assert.Equal([]string{"myrf"}, args)
+	assert.Equal("rfr-myrf-1", *to)
+
+	_, err = parseArgs(flag.NewFlagSet("test", flag.ContinueOnError), []string{}, 1)
+	assert.Error(err)
+}
+
+func TestDiffConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	desired := parseCustomConfig([]string{"maxmemory 100mb", "replica-priority 100", `save ""`, "maxmemory-policy allkeys-lru", "appendonly yes"})
+	live := map[string]string{
+		"maxmemory":        "104857600",
+		"replica-priority": "100",
+		"save":             "",
+		"maxmemory-policy": "noeviction",
+	}
+	differences := diffConfig("rfr-myrf-0", []string{"appendonly", "maxmemory", "maxmemory-policy", "replica-priority", "save"}, desired, live)
+	assert.Equal([]configDifference{
+		{pod: "rfr-myrf-0", parameter: "appendonly", desired: "yes", live: "<unknown>"},
+		{pod: "rfr-myrf-0", parameter: "maxmemory-policy", desired: "allkeys-lru", live: "noeviction"},
+	}, differences)
+}
+
+func TestSwitchoverTo(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := &redisfailoverv1.RedisFailover{
+		ObjectMeta: metav1.ObjectMeta{Name: "myrf", Namespace: "testns"},
+		Spec: redisfailoverv1.RedisFailoverSpec{
+			Sentinel: redisfailoverv1.SentinelSettings{MasterName: "mymaster"},
+		},
+	}
+	topology := &rfservice.Topology{
+		Redises: []rfservice.RedisTopology{
+			{Pod: "rfr-myrf-0", Address: "0.0.0.0", Role: "master"},
+			{Pod: "rfr-myrf-1", Address: "1.1.1.1", Role: "slave", LinkStatus: "up"},
+			{Pod: "rfr-myrf-2", Address: "2.2.2.2", Role: "slave", LinkStatus: "up"},
+		},
+		Sentinels: []rfservice.SentinelTopology{{Pod: "rfs-myrf-0", Address: "3.3.3.3"}},
+	}
+
+	switched := &rfservice.Topology{
+		Redises: []rfservice.RedisTopology{
+			{Pod: "rfr-myrf-0", Address: "0.0.0.0", Role: "slave", LinkStatus: "up"},
+			{Pod: "rfr-myrf-1", Address: "1.1.1.1", Role: "slave", LinkStatus: "up"},
+			{Pod: "rfr-myrf-2", Address: "2.2.2.2", Role: "master"},
+		},
+	}
+
+	ms := &mK8SService.Services{}
+	ms.On("GetRedisFailover", mock.Anything, "testns", "myrf").Return(rf, nil)
+	mrfc := &mRFService.RedisFailoverCheck{}
+	mrfc.On("GetTopology", mock.Anything, rf).Once().Return(topology, nil)
+	mrfc.On("GetTopology", mock.Anything, rf).Once().Return(switched, nil)
+	mr := &mRedisService.Client{}
+	mr.On("GetRedisConfig", mock.Anything, "2.2.2.2", "6379", []string{"replica-priority"}, "").Once().Return(map[string]string{"replica-priority": "100"}, nil)
+	mrfh := &mRFService.RedisFailoverHeal{}
+	mrfh.On("SwitchoverMaster", mock.Anything, "3.3.3.3", []string{"1.1.1.1"}, rf).Once().Return(nil)
+
+	out := &bytes.Buffer{}
+	e := &env{namespace: "testns", timeout: time.Second, out: out, k8sService: ms, redisClient: mr, checker: mrfc, healer: mrfh}
+	assert.NoError(runSwitchover(e, []string{"myrf", "--to", "rfr-myrf-2"}))
+	assert.Contains(out.String(), "Master switched to rfr-myrf-2 (2.2.2.2)")
+	mr.AssertExpectations(t)
+	mrfh.AssertExpectations(t)
+}
+
+func TestSwitchoverToUnpromotable(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := &redisfailoverv1.RedisFailover{
+		ObjectMeta: metav1.ObjectMeta{Name: "myrf", Namespace: "testns"},
+		Spec: redisfailoverv1.RedisFailoverSpec{
+			Sentinel: redisfailoverv1.SentinelSettings{MasterName: "mymaster"},
+		},
+	}
+	topology := &rfservice.Topology{
+		Redises: []rfservice.RedisTopology{
+			{Pod: "rfr-myrf-0", Address: "0.0.0.0", Role: "master"},
+			{Pod: "rfr-myrf-1", Address: "1.1.1.1", Role: "slave", LinkStatus: "up"},
+		},
+		Sentinels: []rfservice.SentinelTopology{{Pod: "rfs-myrf-0", Address: "3.3.3.3"}},
+	}
+
+	ms := &mK8SService.Services{}
+	ms.On("GetRedisFailover", mock.Anything, "testns", "myrf").Return(rf, nil)
+	mrfc := &mRFService.RedisFailoverCheck{}
+	mrfc.On("GetTopology", mock.Anything, rf).Once().Return(topology, nil)
+	mr := &mRedisService.Client{}
+	mr.On("GetRedisConfig", mock.Anything, "1.1.1.1", "6379", []string{"replica-priority"}, "").Once().Return(map[string]string{"replica-priority": "0"}, nil)
+	// the healer mock fails on any call, nothing is failed over
+	mrfh := &mRFService.RedisFailoverHeal{}
+
+	e := &env{namespace: "testns", timeout: time.Second, out: &bytes.Buffer{}, k8sService: ms, redisClient: mr, checker: mrfc, healer: mrfh}
+	assert.Error(runSwitchover(e, []string{"myrf", "--to", "rfr-myrf-1"}))
+	mr.AssertExpectations(t)
+}
+
+func TestPauseResume(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	rf := &redisfailoverv1.RedisFailover{ObjectMeta: metav1.ObjectMeta{Name: "myrf", Namespace: "testns"}}
+	customClient := customfake.NewSimpleClientset(rf)
+	e := &env{namespace: "testns", timeout: time.Second, out: &bytes.Buffer{}, customClient: customClient}
+
+	require.NoError(runPause(e, []string{"myrf"}))
+	got, err := customClient.DatabasesV1().RedisFailovers("testns").Get(context.TODO(), "myrf", metav1.GetOptions{})
+	require.NoError(err)
+	assert.True(got.Paused())
+
+	require.NoError(runResume(e, []string{"myrf"}))
+	got, err = customClient.DatabasesV1().RedisFailovers("testns").Get(context.TODO(), "myrf", metav1.GetOptions{})
+	require.NoError(err)
+	assert.False(got.Paused())
+}
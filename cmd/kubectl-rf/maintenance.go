@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"strconv"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
+	"github.com/spotahome/redis-operator/service/k8s"
+)
+
+func runPause(e *env, args []string) error {
+	return setPaused(e, args, true)
+}
+
+func runResume(e *env, args []string) error {
+	return setPaused(e, args, false)
+}
+
+func setPaused(e *env, args []string, paused bool) error {
+	positional, err := parseArgs(flag.NewFlagSet("pause", flag.ContinueOnError), args, 1)
+	if err != nil {
+		return err
+	}
+
+	// a null value removes the annotation
+	var value interface{}
+	if paused {
+		value = "true"
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{redisfailoverv1.PausedAnnotation: value},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), e.timeout)
+	defer cancel()
+	if _, err := e.customClient.DatabasesV1().RedisFailovers(e.namespace).Patch(ctx, positional[0], types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		return err
+	}
+
+	if paused {
+		fmt.Fprintf(e.out, "redisfailover %s paused\n", positional[0])
+	} else {
+		fmt.Fprintf(e.out, "redisfailover %s resumed\n", positional[0])
+	}
+	return nil
+}
+
+func runResetSentinels(e *env, args []string) error {
+	flags := flag.NewFlagSet("reset-sentinels", flag.ContinueOnError)
+	positional, err := parseArgs(flags, args, 1)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), e.timeout)
+	defer cancel()
+	rf, err := e.getRedisFailover(ctx, positional[0])
+	if err != nil {
+		return err
+	}
+	topology, err := e.checker.GetTopology(ctx, rf)
+	if err != nil {
+		return err
+	}
+	password, err := k8s.GetSentinelPassword(ctx, e.k8sService, rf)
+	if err != nil {
+		return err
+	}
+	port := strconv.Itoa(int(rf.Spec.Sentinel.Port))
+
+	// sentinels are reset one at a time, once the previous one found the others again, so the quorum is never lost
+	for _, sentinel := range topology.Sentinels {
+		if sentinel.Error != "" {
+			fmt.Fprintf(e.out, "Skipping %s: %s\n", sentinel.Pod, sentinel.Error)
+			continue
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), e.timeout)
+		err := resetSentinel(ctx, e, rf, sentinel.Address, port, password, len(topology.Sentinels)-1)
+		cancel()
+		if err != nil {
+			return fmt.Errorf("resetting %s: %w", sentinel.Pod, err)
+		}
+		fmt.Fprintf(e.out, "Sentinel %s reset\n", sentinel.Pod)
+	}
+	return nil
+}
+
+// resetSentinel resets the given sentinel and waits until it knows the given number of other sentinels again
+func resetSentinel(ctx context.Context, e *env, rf *redisfailoverv1.RedisFailover, address, port, password string, others int) error {
+	if err := e.redisClient.ResetSentinel(ctx, address, port, password); err != nil {
+		return err
+	}
+	for {
+		info, err := e.redisClient.GetSentinelMasterInfo(ctx, address, port, rf.Spec.Sentinel.MasterName, password)
+		if err == nil && len(info.Sentinels) >= others {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("the other sentinels weren't found again")
+		case <-time.After(pollInterval):
+		}
+	}
+}
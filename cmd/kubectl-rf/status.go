@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	rfservice "github.com/spotahome/redis-operator/operator/redisfailover/service"
+)
+
+func runStatus(e *env, args []string) error {
+	flags := flag.NewFlagSet("status", flag.ContinueOnError)
+	output := flags.String("o", "table", "output format, table or json")
+	positional, err := parseArgs(flags, args, 1)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), e.timeout)
+	defer cancel()
+	rf, err := e.getRedisFailover(ctx, positional[0])
+	if err != nil {
+		return err
+	}
+	topology, err := e.checker.GetTopology(ctx, rf)
+	if err != nil {
+		return err
+	}
+
+	switch *output {
+	case "json":
+		encoder := json.NewEncoder(e.out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(topology)
+	case "table":
+		if rf.Paused() {
+			fmt.Fprintf(e.out, "Paused, the operator leaves it untouched\n\n")
+		}
+		printTopology(e.out, topology)
+		return nil
+	}
+	return fmt.Errorf("unknown output format %q", *output)
+}
+
+func printTopology(out io.Writer, topology *rfservice.Topology) {
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "REDIS\tADDRESS\tROLE\tMASTER\tLINK\tOFFSET\tLAG\tREVISION\tERROR")
+	for _, r := range topology.Redises {
+		lag := "-"
+		if r.Lag != nil {
+			lag = fmt.Sprint(*r.Lag)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%d\t%s\t%s\t%s\n", r.Pod, r.Address, dash(r.Role), dash(r.Master), dash(r.LinkStatus), r.Offset, lag, dash(r.RevisionHash), r.Error)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "SENTINEL\tADDRESS\tMASTER\tSENTINELS\tREPLICAS\tQUORUM\tERROR")
+	for _, s := range topology.Sentinels {
+		quorum := "-"
+		if s.Error == "" {
+			quorum = fmt.Sprintf("%d (reachable: %t)", s.Quorum, s.QuorumReachable)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", s.Pod, s.Address, dash(s.Master), dash(strings.Join(s.Sentinels, ",")), dash(strings.Join(s.Replicas, ",")), quorum, s.Error)
+	}
+	w.Flush()
+}
+
+func dash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
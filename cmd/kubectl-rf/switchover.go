@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strconv"
+	"time"
+
+	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
+	rfservice "github.com/spotahome/redis-operator/operator/redisfailover/service"
+	"github.com/spotahome/redis-operator/service/k8s"
+)
+
+// pollInterval is the time waited between the checks of an ongoing operation
+var pollInterval = time.Second
+
+func runSwitchover(e *env, args []string) error {
+	flags := flag.NewFlagSet("switchover", flag.ContinueOnError)
+	to := flags.String("to", "", "replica pod to promote, the one sentinel picks by default")
+	positional, err := parseArgs(flags, args, 1)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), e.timeout)
+	defer cancel()
+	rf, err := e.getRedisFailover(ctx, positional[0])
+	if err != nil {
+		return err
+	}
+	topology, err := e.checker.GetTopology(ctx, rf)
+	if err != nil {
+		return err
+	}
+	master, replicas, err := getRoles(topology)
+	if err != nil {
+		return err
+	}
+	sentinel := ""
+	for _, s := range topology.Sentinels {
+		if s.Error == "" {
+			sentinel = s.Address
+			break
+		}
+	}
+	if sentinel == "" {
+		return fmt.Errorf("no sentinel reachable")
+	}
+
+	excluded := []string{}
+	if *to != "" {
+		target := false
+		for _, r := range replicas {
+			if r.Pod != *to {
+				excluded = append(excluded, r.Address)
+				continue
+			}
+			target = true
+			if r.LinkStatus != "up" {
+				return fmt.Errorf("replica %s isn't in sync with the master", *to)
+			}
+			if err := checkPromotable(ctx, e, rf, r); err != nil {
+				return err
+			}
+		}
+		if !target {
+			return fmt.Errorf("%s isn't a replica of the master", *to)
+		}
+	}
+
+	// the other replicas can't be promoted until sentinel sees it, their priorities are restored once switched
+	fmt.Fprintf(e.out, "Failing master %s (%s) over\n", master.Pod, master.Address)
+	if err := e.healer.SwitchoverMaster(ctx, sentinel, excluded, rf); err != nil {
+		return err
+	}
+
+	// the new master is told by its pod, sentinel reports the address it is announced with
+	topology, err = e.checker.GetTopology(ctx, rf)
+	if err != nil {
+		return err
+	}
+	current, _, err := getRoles(topology)
+	if err != nil {
+		return err
+	}
+	if current.Pod == master.Pod {
+		return fmt.Errorf("master not switched, sentinel may be failing it over already")
+	}
+	if *to != "" && current.Pod != *to {
+		return fmt.Errorf("sentinel promoted %s instead of %s", current.Pod, *to)
+	}
+	fmt.Fprintf(e.out, "Master switched to %s (%s)\n", current.Pod, current.Address)
+	return nil
+}
+
+// checkPromotable returns an error when the given replica has a replica-priority of 0, sentinel never promotes it
+func checkPromotable(ctx context.Context, e *env, rf *redisfailoverv1.RedisFailover, replica rfservice.RedisTopology) error {
+	password, err := k8s.GetRedisPassword(ctx, e.k8sService, rf)
+	if err != nil {
+		return err
+	}
+	config, err := e.redisClient.GetRedisConfig(ctx, replica.Address, strconv.Itoa(int(rf.Spec.Redis.Port)), []string{"replica-priority"}, password)
+	if err != nil {
+		return err
+	}
+	if config["replica-priority"] == "0" {
+		return fmt.Errorf("replica %s has a replica-priority of 0, sentinel never promotes it", replica.Pod)
+	}
+	return nil
+}
+
+// getRoles returns the master of the topology and its replicas
+func getRoles(topology *rfservice.Topology) (rfservice.RedisTopology, []rfservice.RedisTopology, error) {
+	masters := []rfservice.RedisTopology{}
+	replicas := []rfservice.RedisTopology{}
+	for _, r := range topology.Redises {
+		switch r.Role {
+		case "master":
+			masters = append(masters, r)
+		case "slave":
+			replicas = append(replicas, r)
+		}
+	}
+	if len(masters) != 1 {
+		return rfservice.RedisTopology{}, nil, fmt.Errorf("expected a single master, found %d", len(masters))
+	}
+	return masters[0], replicas, nil
+}
@@ -4,7 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
-	_ "net/http/pprof"
+	"net/http/pprof"
 	"os"
 	"os/signal"
 	"strings"
@@ -21,11 +21,14 @@ import (
 	"github.com/spotahome/redis-operator/operator/redisfailover"
 	"github.com/spotahome/redis-operator/service/k8s"
 	"github.com/spotahome/redis-operator/service/redis"
+	"github.com/spotahome/redis-operator/tracing"
 )
 
 const (
 	gracePeriod      = 5 * time.Second
 	metricsNamespace = "redis_operator"
+	// configReloadInterval is the interval the configuration file is checked for changes at
+	configReloadInterval = 10 * time.Second
 )
 
 // Main is the  main runner.
@@ -53,8 +56,24 @@ func (m *Main) Run() error {
 	m.stopC = make(chan struct{})
 	errC := make(chan error)
 
+	// Load the configuration file, reloaded while running.
+	settings := redisfailover.NewSettingsStore(redisfailover.Settings{})
+	if m.flags.ConfigPath != "" {
+		var err error
+		if settings, err = redisfailover.LoadSettingsStore(m.flags.ConfigPath); err != nil {
+			return fmt.Errorf("could not load the configuration: %w", err)
+		}
+		go settings.Watch(context.Background(), m.flags.ConfigPath, configReloadInterval, m.settingsChanged, m.logger)
+	}
+
 	// Set correct logging.
-	err := m.logger.Set(log.Level(strings.ToLower(m.flags.LogLevel)))
+	err := m.applyLogSettings(settings.Get())
+	if err != nil {
+		return err
+	}
+
+	// Trace the reconciles.
+	shutdownTracing, err := tracing.Setup(context.Background(), m.flags.ToTracingConfig())
 	if err != nil {
 		return err
 	}
@@ -62,33 +81,35 @@ func (m *Main) Run() error {
 	// Create the metrics client.
 	metricsRecorder := metrics.NewRecorder(metricsNamespace, prometheus.DefaultRegisterer)
 
-	// Serve metrics.
+	// Kubernetes clients.
+	k8sClient, customClient, aeClientset, dynamicClient, err := utils.CreateKubernetesClients(m.flags)
+	if err != nil {
+		return err
+	}
+
+	// Get lease lock resource namespace
+	lockNamespace := getNamespace()
+
+	// Serve metrics, health and leadership.
+	status := redisfailover.NewStatus(k8sClient, lockNamespace)
 	go func() {
 		log.Infof("Listening on %s for metrics exposure on URL %s", m.flags.ListenAddr, m.flags.MetricsPath)
-		http.Handle(m.flags.MetricsPath, promhttp.Handler())
-		err := http.ListenAndServe(m.flags.ListenAddr, nil)
+		err := http.ListenAndServe(m.flags.ListenAddr, m.createMux(status))
 		if err != nil {
 			log.Fatal(err)
 		}
 	}()
 
-	// Kubernetes clients.
-	k8sClient, customClient, aeClientset, err := utils.CreateKubernetesClients(m.flags)
-	if err != nil {
-		return err
-	}
-
 	// Create kubernetes service.
-	k8sservice := k8s.New(k8sClient, customClient, aeClientset, m.logger, metricsRecorder)
+	k8sservice := k8s.New(k8sClient, customClient, aeClientset, dynamicClient, m.logger, metricsRecorder)
 
 	// Create the redis clients
-	redisClient := redis.New(metricsRecorder)
-
-	// Get lease lock resource namespace
-	lockNamespace := getNamespace()
+	redisClient := redis.New(metricsRecorder, m.flags.ToRedisConfig())
 
 	// Create operator and run.
-	redisfailoverOperator, err := redisfailover.New(m.flags.ToRedisOperatorConfig(), k8sservice, k8sClient, lockNamespace, redisClient, metricsRecorder, m.logger)
+	operatorConfig := m.flags.ToRedisOperatorConfig()
+	operatorConfig.Settings = settings
+	redisfailoverOperator, err := redisfailover.New(operatorConfig, k8sservice, k8sClient, lockNamespace, redisClient, metricsRecorder, status, m.logger)
 	if err != nil {
 		return err
 	}
@@ -109,9 +130,54 @@ func (m *Main) Run() error {
 	}
 
 	m.stop(m.stopC)
+	ctx, cancel := context.WithTimeout(context.Background(), gracePeriod)
+	defer cancel()
+	if err := shutdownTracing(ctx); err != nil {
+		m.logger.Errorf("Unable to export the last spans: %s", err)
+	}
 	return finalErr
 }
 
+// applyLogSettings sets the log level and format of the configuration file, or the ones of the flags when unset
+func (m *Main) applyLogSettings(settings redisfailover.Settings) error {
+	level := m.flags.LogLevel
+	if settings.LogLevel != "" {
+		level = settings.LogLevel
+	}
+	if err := m.logger.Set(log.Level(strings.ToLower(level))); err != nil {
+		return err
+	}
+	format := log.Format(m.flags.LogFormat)
+	if settings.LogFormat != "" {
+		format = settings.LogFormat
+	}
+	return log.SetFormat(format)
+}
+
+// settingsChanged applies the settings of the configuration file the operator doesn't read on every reconcile
+func (m *Main) settingsChanged(old, new redisfailover.Settings) {
+	if err := m.applyLogSettings(new); err != nil {
+		m.logger.Errorf("Unable to apply the log settings: %s", err)
+	}
+}
+
+func (m *Main) createMux(status *redisfailover.Status) *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.Handle(m.flags.MetricsPath, promhttp.Handler())
+	mux.HandleFunc("/healthz", status.HealthzHandler)
+	mux.HandleFunc("/readyz", status.ReadyzHandler)
+	mux.HandleFunc("/leader", status.LeaderHandler)
+	mux.HandleFunc(redisfailover.TopologyPathPrefix, status.TopologyHandler)
+	if m.flags.EnablePprof {
+		mux.HandleFunc("/debug/pprof/", pprof.Index)
+		mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+		mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+		mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+		mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	}
+	return mux
+}
+
 func (m *Main) createSignalCapturer() <-chan os.Signal {
 	sigC := make(chan os.Signal, 1)
 	signal.Notify(sigC, syscall.SIGTERM, syscall.SIGINT)
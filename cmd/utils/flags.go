@@ -3,22 +3,39 @@ package utils
 import (
 	"flag"
 	"path/filepath"
+	"time"
 
+	"github.com/spotahome/redis-operator/log"
 	"github.com/spotahome/redis-operator/operator/redisfailover"
+	"github.com/spotahome/redis-operator/service/redis"
+	"github.com/spotahome/redis-operator/tracing"
 	"k8s.io/client-go/util/homedir"
 )
 
 // CMDFlags are the flags used by the cmd
 // TODO: improve flags.
 type CMDFlags struct {
-	KubeConfig          string
-	Development         bool
-	ListenAddr          string
-	MetricsPath         string
-	K8sQueriesPerSecond int
-	K8sQueriesBurstable int
-	Concurrency         int
-	LogLevel            string
+	KubeConfig           string
+	ConfigPath           string
+	Development          bool
+	ListenAddr           string
+	MetricsPath          string
+	EnablePprof          bool
+	K8sQueriesPerSecond  int
+	K8sQueriesBurstable  int
+	Concurrency          int
+	ResyncInterval       time.Duration
+	ReconcileTimeout     time.Duration
+	LogLevel             string
+	LogFormat            string
+	RedisDialTimeout     time.Duration
+	RedisReadTimeout     time.Duration
+	RedisWriteTimeout    time.Duration
+	RedisPoolSize        int
+	RedisPoolIdleTimeout time.Duration
+	TracingExporter      string
+	TracingEndpoint      string
+	TracingInsecure      bool
 }
 
 // Init initializes and parse the flags
@@ -26,15 +43,28 @@ func (c *CMDFlags) Init() {
 	kubehome := filepath.Join(homedir.HomeDir(), ".kube", "config")
 	// register flags
 	flag.StringVar(&c.KubeConfig, "kubeconfig", kubehome, "kubernetes configuration path, only used when development mode enabled")
+	flag.StringVar(&c.ConfigPath, "config", "", "Path of the operator configuration file, reloaded when it changes")
 	flag.BoolVar(&c.Development, "development", false, "development flag will allow to run the operator outside a kubernetes cluster")
 	flag.StringVar(&c.ListenAddr, "listen-address", ":9710", "Address to listen on for metrics.")
 	flag.StringVar(&c.MetricsPath, "metrics-path", "/metrics", "Path to serve the metrics.")
+	flag.BoolVar(&c.EnablePprof, "enable-pprof", false, "Serve the pprof profiles on /debug/pprof/ of the listen address.")
 	flag.IntVar(&c.K8sQueriesPerSecond, "k8s-cli-qps-limit", 100, "Number of allowed queries per second by kubernetes client without client side throttling")
 	flag.IntVar(&c.K8sQueriesBurstable, "k8s-cli-burstable-limit", 100, "Number of allowed burst requests by kubernetes client without client side throttling")
 	// default is 3 for conccurency because kooper also defines 3 as default
 	// reference: https://github.com/spotahome/kooper/blob/master/controller/controller.go#L89
 	flag.IntVar(&c.Concurrency, "concurrency", 3, "Number of conccurent workers meant to process events")
+	flag.DurationVar(&c.ResyncInterval, "resync-interval", 30*time.Second, "Interval every redisfailover is reconciled at, changes on their pods, statefulsets and deployments trigger a reconcile right away")
+	flag.DurationVar(&c.ReconcileTimeout, "reconcile-timeout", 2*time.Minute, "Maximum time a reconcile of a redisfailover runs for, the calls to redis and sentinel still running then are cancelled. 0 disables it")
 	flag.StringVar(&c.LogLevel, "log-level", "info", "set log level")
+	flag.StringVar(&c.LogFormat, "log-format", string(log.TextFormat), "Format of the log lines, text or json")
+	flag.DurationVar(&c.RedisDialTimeout, "redis-dial-timeout", redis.DefaultConfig.DialTimeout, "Maximum time to establish a connection to redis and sentinel")
+	flag.DurationVar(&c.RedisReadTimeout, "redis-read-timeout", redis.DefaultConfig.ReadTimeout, "Maximum time to wait for the reply of redis and sentinel to a command")
+	flag.DurationVar(&c.RedisWriteTimeout, "redis-write-timeout", redis.DefaultConfig.WriteTimeout, "Maximum time to send a command to redis and sentinel")
+	flag.IntVar(&c.RedisPoolSize, "redis-pool-size", redis.DefaultConfig.PoolSize, "Maximum number of connections kept for every redis and sentinel")
+	flag.DurationVar(&c.RedisPoolIdleTimeout, "redis-pool-idle-timeout", redis.DefaultConfig.PoolIdleTimeout, "Time after which unused connections to redis and sentinel are closed")
+	flag.StringVar(&c.TracingExporter, "tracing-exporter", string(tracing.NoExporter), "Exporter of the traces of the reconciles and the calls to kubernetes and redis: none, stdout or otlp")
+	flag.StringVar(&c.TracingEndpoint, "tracing-endpoint", "", "Address of the OpenTelemetry collector of the otlp exporter, the OTEL_EXPORTER_OTLP_ENDPOINT environment variable or localhost:4317 when empty")
+	flag.BoolVar(&c.TracingInsecure, "tracing-insecure", false, "Connect to the OpenTelemetry collector without TLS")
 	// Parse flags
 	flag.Parse()
 }
@@ -42,8 +72,30 @@ func (c *CMDFlags) Init() {
 // ToRedisOperatorConfig convert the flags to redisfailover config
 func (c *CMDFlags) ToRedisOperatorConfig() redisfailover.Config {
 	return redisfailover.Config{
-		ListenAddress: c.ListenAddr,
-		MetricsPath:   c.MetricsPath,
-		Concurrency:   c.Concurrency,
+		ListenAddress:    c.ListenAddr,
+		MetricsPath:      c.MetricsPath,
+		Concurrency:      c.Concurrency,
+		ResyncInterval:   c.ResyncInterval,
+		ReconcileTimeout: c.ReconcileTimeout,
+	}
+}
+
+// ToRedisConfig convert the flags to the configuration of the connections to redis and sentinel
+func (c *CMDFlags) ToRedisConfig() redis.Config {
+	return redis.Config{
+		DialTimeout:     c.RedisDialTimeout,
+		ReadTimeout:     c.RedisReadTimeout,
+		WriteTimeout:    c.RedisWriteTimeout,
+		PoolSize:        c.RedisPoolSize,
+		PoolIdleTimeout: c.RedisPoolIdleTimeout,
+	}
+}
+
+// ToTracingConfig convert the flags to the configuration of the tracing
+func (c *CMDFlags) ToTracingConfig() tracing.Config {
+	return tracing.Config{
+		Exporter: tracing.Exporter(c.TracingExporter),
+		Endpoint: c.TracingEndpoint,
+		Insecure: c.TracingInsecure,
 	}
 }
@@ -1,9 +1,12 @@
 package log
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"runtime"
 	"strings"
+	"sync"
 
 	"github.com/sirupsen/logrus"
 )
@@ -255,6 +258,126 @@ func Set(level Level) error {
 	return baseLogger.Set(level)
 }
 
+// Format is the format of the log lines
+type Format string
+
+const (
+	// TextFormat logs the lines as key=value pairs
+	TextFormat Format = "text"
+	// JSONFormat logs the lines as JSON objects
+	JSONFormat Format = "json"
+)
+
+// SetFormat will set the format of the lines of every logger
+func SetFormat(format Format) error {
+	switch format {
+	case TextFormat:
+		baseLogger.entry.Logger.SetFormatter(&logrus.TextFormatter{})
+	case JSONFormat:
+		baseLogger.entry.Logger.SetFormatter(&logrus.JSONFormatter{})
+	default:
+		return fmt.Errorf("unknown log format %q", format)
+	}
+	return nil
+}
+
+// WithLevel returns the given logger logging at the given level, leaving the level of the other loggers untouched
+func WithLevel(l Logger, level Level) (Logger, error) {
+	leLev, err := logrus.ParseLevel(string(level))
+	if err != nil {
+		return nil, err
+	}
+	ll, ok := l.(*logger)
+	if !ok {
+		return l, nil
+	}
+
+	return &logger{entry: leveledLogger(ll.entry.Logger, leLev).WithFields(ll.entry.Data)}, nil
+}
+
+// leveledLoggers are the loggers writing as a base logger does at another level, one per base logger and level
+var leveledLoggers = struct {
+	sync.Mutex
+	byBase map[*logrus.Logger]map[logrus.Level]*logrus.Logger
+}{byBase: map[*logrus.Logger]map[logrus.Level]*logrus.Logger{}}
+
+// lockedWriter serializes the lines of a base logger and of its leveled loggers, each logrus logger only holds its own
+// lock while writing
+type lockedWriter struct {
+	mutex sync.Mutex
+	out   io.Writer
+}
+
+func (w *lockedWriter) Write(p []byte) (int, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.out.Write(p)
+}
+
+// leveledLogger returns the logger writing as the given base logger does at the given level
+func leveledLogger(base *logrus.Logger, level logrus.Level) *logrus.Logger {
+	leveledLoggers.Lock()
+	defer leveledLoggers.Unlock()
+
+	out, ok := base.Out.(*lockedWriter)
+	if !ok {
+		out = &lockedWriter{out: base.Out}
+		base.SetOutput(out)
+	}
+	byLevel, ok := leveledLoggers.byBase[base]
+	if !ok {
+		byLevel = map[logrus.Level]*logrus.Logger{}
+		leveledLoggers.byBase[base] = byLevel
+	}
+	leveled, ok := byLevel[level]
+	if !ok || leveled.Out != out {
+		leveled = &logrus.Logger{
+			Out:          out,
+			Hooks:        base.Hooks,
+			Formatter:    base.Formatter,
+			ReportCaller: base.ReportCaller,
+			ExitFunc:     base.ExitFunc,
+			Level:        level,
+		}
+		byLevel[level] = leveled
+	}
+	// follows SetFormat
+	if leveled.Formatter != base.Formatter {
+		leveled.SetFormatter(base.Formatter)
+	}
+	return leveled
+}
+
+// fieldsKey is the key of the fields carried by a context
+type fieldsKey struct{}
+
+// NewContext returns a copy of the given context carrying the given fields, along with the ones it already carries
+func NewContext(ctx context.Context, fields map[string]interface{}) context.Context {
+	all := map[string]interface{}{}
+	for k, v := range FieldsFromContext(ctx) {
+		all[k] = v
+	}
+	for k, v := range fields {
+		all[k] = v
+	}
+	return context.WithValue(ctx, fieldsKey{}, all)
+}
+
+// FieldsFromContext returns the fields carried by the given context
+func FieldsFromContext(ctx context.Context) map[string]interface{} {
+	fields, _ := ctx.Value(fieldsKey{}).(map[string]interface{})
+	return fields
+}
+
+// FromContext returns the given logger with the fields carried by the given context
+func FromContext(ctx context.Context, l Logger) Logger {
+	fields := FieldsFromContext(ctx)
+	if len(fields) == 0 {
+		return l
+	}
+	return l.WithFields(fields)
+}
+
 // Panic logs panic message
 func Panic(args ...interface{}) {
 	baseLogger.Panic(args...)
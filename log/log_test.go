@@ -0,0 +1,64 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithLevel(t *testing.T) {
+	assert := assert.New(t)
+
+	out := &bytes.Buffer{}
+	base := logrus.New()
+	base.SetOutput(out)
+	base.SetLevel(logrus.InfoLevel)
+	l := (&logger{entry: logrus.NewEntry(base)}).WithField("redisfailover", "test")
+
+	debug, err := WithLevel(l, "debug")
+	assert.NoError(err)
+	debug.Debugf("shown")
+	l.Debugf("hidden")
+	assert.Contains(out.String(), "shown")
+	assert.Contains(out.String(), "redisfailover=test")
+	assert.NotContains(out.String(), "hidden")
+	assert.Equal(logrus.InfoLevel, base.Level)
+
+	again, err := WithLevel(l.WithField("namespace", "testns"), "debug")
+	assert.NoError(err)
+	assert.Same(debug.(*logger).entry.Logger, again.(*logger).entry.Logger, "a logger per level must be kept")
+
+	base.SetFormatter(&logrus.JSONFormatter{})
+	again, err = WithLevel(l, "debug")
+	assert.NoError(err)
+	again.Debugf("formatted")
+	assert.Contains(out.String(), `"msg":"formatted"`)
+
+	_, err = WithLevel(l, "verbose")
+	assert.Error(err)
+
+	dummy, err := WithLevel(Dummy, "debug")
+	assert.NoError(err)
+	assert.Equal(Dummy, dummy)
+}
+
+func TestFromContext(t *testing.T) {
+	assert := assert.New(t)
+
+	out := &bytes.Buffer{}
+	base := logrus.New()
+	base.SetOutput(out)
+	l := &logger{entry: logrus.NewEntry(base)}
+
+	assert.Equal(l, FromContext(context.Background(), l))
+
+	ctx := NewContext(context.Background(), map[string]interface{}{"redisfailover": "test", "namespace": "testns"})
+	ctx = NewContext(ctx, map[string]interface{}{"reconcile": "1234"})
+	FromContext(ctx, l).Infof("message")
+	assert.Contains(out.String(), "redisfailover=test")
+	assert.Contains(out.String(), "namespace=testns")
+	assert.Contains(out.String(), "reconcile=1234")
+}
@@ -1,6 +1,8 @@
 package metrics
 
 import (
+	"time"
+
 	koopercontroller "github.com/spotahome/kooper/v2/controller"
 )
 
@@ -29,3 +31,14 @@ func (d dummy) RecordK8sOperation(namespace string, kind string, object string,
 }
 func (d dummy) RecordRedisOperation(kind string, IP string, operation string, status string, err string) {
 }
+func (d dummy) SetRedisPoolStats(IP string, port string, hits uint32, misses uint32, timeouts uint32, totalConns uint32, idleConns uint32, staleConns uint32) {
+}
+func (d dummy) DeleteRedisPoolStats(IP string, port string) {}
+func (d dummy) RecordSentinelFailover(namespace string, name string, result string, duration time.Duration) {
+}
+func (d dummy) SetRedisNodes(namespace string, name string, nodes []RedisNode) {}
+func (d dummy) RecordFailover(namespace string, name string, initiator string) {}
+func (d dummy) RecordMasterRecovery(namespace string, name string, duration time.Duration) {
+}
+func (d dummy) RecordReconcilePhase(namespace string, name string, phase string, duration time.Duration) {
+}
@@ -69,8 +69,45 @@ const (
 	GET_SENTINEL_MONITOR        = "SENTINEL_GET_MASTER_INSTANCE"
 	CHECK_SENTINEL_QUORUM       = "SENTINEL_CKQUORUM"
 	SLAVE_IS_READY              = "CHECK_IF_SLAVE_IS_READY"
+	GET_REPLICATION_OFFSET      = "GET_REPLICATION_OFFSET"
+	GET_MASTER_ADDR_BY_NAME     = "SENTINEL_GET_MASTER_ADDR_BY_NAME"
+	DISABLE_REPLICATION_TLS     = "DISABLE_REPLICATION_TLS"
+	SET_DEFAULT_USER_PASSWORDS  = "SET_DEFAULT_USER_PASSWORDS"
+	SUBSCRIBE_SENTINEL          = "SENTINEL_SUBSCRIBE_TO_EVENTS"
+	GET_REPLICATION_INFO        = "GET_REPLICATION_INFO"
+	GET_SENTINEL_MASTER_INFO    = "SENTINEL_GET_MASTER_INFO"
+	SENTINEL_FAILOVER           = "SENTINEL_FAILOVER"
+	GET_SENTINEL_REPLICAS       = "SENTINEL_GET_REPLICAS"
+	GET_REDIS_CONFIG            = "GET_REDIS_CONFIG"
+
+	FAILOVER_SUCCEEDED = "SUCCEEDED"
+	FAILOVER_ABORTED   = "ABORTED"
+
+	FAILOVER_BY_OPERATOR = "OPERATOR"
+	FAILOVER_BY_SENTINEL = "SENTINEL"
 )
 
+// redisRoles are the roles a redis reports
+var redisRoles = []string{"master", "slave"}
+
+// RedisNode is the state of a redis of a RedisFailover, as observed by the operator
+type RedisNode struct {
+	Pod string
+	// Role is empty when the redis can't be reached
+	Role string
+	// Lag is the replication offset the replica is behind its master by, nil when unknown
+	Lag              *int64
+	LinkUp           bool
+	UsedMemory       int64
+	ConnectedClients int
+}
+
+// redisNodes keeps the pods of every RedisFailover last reported, to remove the series of the pods gone since
+type redisNodes struct {
+	mutex sync.Mutex
+	pods  map[string][]string
+}
+
 var ( // used for grabage collection of metrics
 	mutex                     sync.Mutex
 	recorders                 = []recorder{}
@@ -95,17 +132,45 @@ type Recorder interface {
 
 	RecordK8sOperation(namespace string, kind string, name string, operation string, status string, err string)
 	RecordRedisOperation(kind string, IP string, operation string, status string, err string)
+
+	// Connection pools to redis and sentinel instances
+	SetRedisPoolStats(IP string, port string, hits uint32, misses uint32, timeouts uint32, totalConns uint32, idleConns uint32, staleConns uint32)
+	DeleteRedisPoolStats(IP string, port string)
+
+	// Failovers performed by sentinel
+	RecordSentinelFailover(namespace string, name string, result string, duration time.Duration)
+
+	// State of the redises, replacing the one of the pods no longer reported
+	SetRedisNodes(namespace string, name string, nodes []RedisNode)
+	// Failovers performed by the operator or sentinel, and the time taken to get a healthy master again
+	RecordFailover(namespace string, name string, initiator string)
+	RecordMasterRecovery(namespace string, name string, duration time.Duration)
+
+	// Duration of every phase of the reconcile of a failover cluster
+	RecordReconcilePhase(namespace string, name string, phase string, duration time.Duration)
 }
 
 // PromMetrics implements the instrumenter so the metrics can be managed by Prometheus.
 type recorder struct {
 	// Metrics fields.
-	clusterOK            *prometheus.GaugeVec   // clusterOk is the status of a cluster
-	ensureResource       *prometheus.CounterVec // number of successful "ensure" operators performed by the controller.
-	redisCheck           *prometheus.CounterVec // indicates any error encountered in managed redis instance(s)
-	sentinelCheck        *prometheus.CounterVec // indicates any error encountered in managed sentinel instance(s)
-	k8sServiceOperations *prometheus.CounterVec // number of operations performed on k8s
-	redisOperations      *prometheus.CounterVec // number of operations performed on redis/sentinel instances
+	clusterOK            *prometheus.GaugeVec     // clusterOk is the status of a cluster
+	ensureResource       *prometheus.CounterVec   // number of successful "ensure" operators performed by the controller.
+	redisCheck           *prometheus.CounterVec   // indicates any error encountered in managed redis instance(s)
+	sentinelCheck        *prometheus.CounterVec   // indicates any error encountered in managed sentinel instance(s)
+	k8sServiceOperations *prometheus.CounterVec   // number of operations performed on k8s
+	redisOperations      *prometheus.CounterVec   // number of operations performed on redis/sentinel instances
+	redisPoolConnections *prometheus.GaugeVec     // number of connections in the pool of a redis/sentinel instance
+	redisPoolRequests    *prometheus.GaugeVec     // number of connections requested to the pool of a redis/sentinel instance
+	sentinelFailover     *prometheus.HistogramVec // duration of the failovers performed by sentinel
+	redisNodeRole        *prometheus.GaugeVec     // role of every redis
+	redisNodeLag         *prometheus.GaugeVec     // replication lag of every replica
+	redisNodeLinkUp      *prometheus.GaugeVec     // status of the link of every replica to its master
+	redisNodeMemory      *prometheus.GaugeVec     // memory used by every redis
+	redisNodeClients     *prometheus.GaugeVec     // clients connected to every redis
+	failovers            *prometheus.CounterVec   // number of failovers by initiator
+	masterRecovery       *prometheus.HistogramVec // duration from the loss of the master to a healthy one
+	reconcilePhase       *prometheus.HistogramVec // duration of every phase of the reconcile
+	redisNodes           *redisNodes
 	koopercontroller.MetricsRecorder
 }
 
@@ -156,6 +221,97 @@ func NewRecorder(namespace string, reg prometheus.Registerer) Recorder {
 			Help:      "number of operations performed on k8s",
 		}, []string{"namespace", "kind", "name", "operation", "status", "err"})
 
+	redisPoolConnections := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: promControllerSubsystem,
+			Name:      "redis_pool_connections",
+			Help:      "number of connections in the pool of a redis/sentinel instance by state (total, idle, stale)",
+		}, []string{"IP", "port", "state"})
+
+	redisPoolRequests := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: promControllerSubsystem,
+			Name:      "redis_pool_requests",
+			Help:      "number of connections requested to the pool of a redis/sentinel instance since it was created by result (hit, miss, timeout)",
+		}, []string{"IP", "port", "result"})
+
+	sentinelFailover := prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: promControllerSubsystem,
+			Name:      "sentinel_failover_duration_seconds",
+			Help:      "duration of the failovers performed by sentinel, from the master being objectively down to its switch or the failover abort",
+			Buckets:   prometheus.ExponentialBuckets(1, 2, 8),
+		}, []string{"namespace", "name", "result"})
+
+	redisNodeRole := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: promControllerSubsystem,
+			Name:      "redis_node_role",
+			Help:      "role of a redis of a failover, 1 for its current role, 0 for the others and for every role while it can't be reached",
+		}, []string{"namespace", "name", "pod", "role"})
+
+	redisNodeLag := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: promControllerSubsystem,
+			Name:      "redis_node_replication_lag_bytes",
+			Help:      "replication offset a replica is behind its master by",
+		}, []string{"namespace", "name", "pod"})
+
+	redisNodeLinkUp := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: promControllerSubsystem,
+			Name:      "redis_node_master_link_up",
+			Help:      "whether the link of a replica to its master is up",
+		}, []string{"namespace", "name", "pod"})
+
+	redisNodeMemory := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: promControllerSubsystem,
+			Name:      "redis_node_used_memory_bytes",
+			Help:      "memory allocated by a redis",
+		}, []string{"namespace", "name", "pod"})
+
+	redisNodeClients := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: promControllerSubsystem,
+			Name:      "redis_node_connected_clients",
+			Help:      "number of clients connected to a redis",
+		}, []string{"namespace", "name", "pod"})
+
+	failovers := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: promControllerSubsystem,
+			Name:      "failovers_total",
+			Help:      "number of failovers of a failover cluster by initiator, the operator promoting a redis or sentinel switching the master",
+		}, []string{"namespace", "name", "initiator"})
+
+	masterRecovery := prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: promControllerSubsystem,
+			Name:      "master_recovery_duration_seconds",
+			Help:      "duration from the loss of the master, seen by sentinel or the operator, to the operator finding a single master again",
+			Buckets:   prometheus.ExponentialBuckets(1, 2, 10),
+		}, []string{"namespace", "name"})
+
+	reconcilePhase := prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: promControllerSubsystem,
+			Name:      "reconcile_phase_duration_seconds",
+			Help:      "duration of a phase of the reconcile of a failover cluster: Validate, Ensure and CheckAndHeal, each of their steps and the waits within them",
+			Buckets:   prometheus.ExponentialBuckets(0.005, 2, 14),
+		}, []string{"namespace", "name", "phase"})
+
 	// Create the instance.
 	r := recorder{
 		clusterOK:            clusterOK,
@@ -164,6 +320,18 @@ func NewRecorder(namespace string, reg prometheus.Registerer) Recorder {
 		sentinelCheck:        sentinelCheck,
 		k8sServiceOperations: k8sServiceOperations,
 		redisOperations:      redisOperations,
+		redisPoolConnections: redisPoolConnections,
+		redisPoolRequests:    redisPoolRequests,
+		sentinelFailover:     sentinelFailover,
+		redisNodeRole:        redisNodeRole,
+		redisNodeLag:         redisNodeLag,
+		redisNodeLinkUp:      redisNodeLinkUp,
+		redisNodeMemory:      redisNodeMemory,
+		redisNodeClients:     redisNodeClients,
+		failovers:            failovers,
+		masterRecovery:       masterRecovery,
+		reconcilePhase:       reconcilePhase,
+		redisNodes:           &redisNodes{pods: map[string][]string{}},
 		MetricsRecorder: kooperprometheus.New(kooperprometheus.Config{
 			Registerer: reg,
 		}),
@@ -177,6 +345,17 @@ func NewRecorder(namespace string, reg prometheus.Registerer) Recorder {
 		r.sentinelCheck,
 		r.k8sServiceOperations,
 		r.redisOperations,
+		r.redisPoolConnections,
+		r.redisPoolRequests,
+		r.sentinelFailover,
+		r.redisNodeRole,
+		r.redisNodeLag,
+		r.redisNodeLinkUp,
+		r.redisNodeMemory,
+		r.redisNodeClients,
+		r.failovers,
+		r.masterRecovery,
+		r.reconcilePhase,
 	)
 	recorders = append(recorders, r)
 	return r
@@ -222,6 +401,100 @@ func (r recorder) RecordRedisOperation(kind /*redis/sentinel? */ string, IP stri
 	updateInstanceMetricLastUpdatedTracker(IP)
 }
 
+func (r recorder) SetRedisPoolStats(IP string, port string, hits uint32, misses uint32, timeouts uint32, totalConns uint32, idleConns uint32, staleConns uint32) {
+	r.redisPoolRequests.WithLabelValues(IP, port, "hit").Set(float64(hits))
+	r.redisPoolRequests.WithLabelValues(IP, port, "miss").Set(float64(misses))
+	r.redisPoolRequests.WithLabelValues(IP, port, "timeout").Set(float64(timeouts))
+	r.redisPoolConnections.WithLabelValues(IP, port, "total").Set(float64(totalConns))
+	r.redisPoolConnections.WithLabelValues(IP, port, "idle").Set(float64(idleConns))
+	r.redisPoolConnections.WithLabelValues(IP, port, "stale").Set(float64(staleConns))
+	updateInstanceMetricLastUpdatedTracker(IP)
+}
+
+func (r recorder) DeleteRedisPoolStats(IP string, port string) {
+	r.redisPoolRequests.DeletePartialMatch(prometheus.Labels{"IP": IP, "port": port})
+	r.redisPoolConnections.DeletePartialMatch(prometheus.Labels{"IP": IP, "port": port})
+}
+
+func (r recorder) RecordSentinelFailover(namespace string, name string, result string, duration time.Duration) {
+	r.sentinelFailover.WithLabelValues(namespace, name, result).Observe(duration.Seconds())
+}
+
+func (r recorder) SetRedisNodes(namespace string, name string, nodes []RedisNode) {
+	pods := map[string]bool{}
+	for _, node := range nodes {
+		pods[node.Pod] = true
+		for _, role := range redisRoles {
+			value := 0.0
+			if role == node.Role {
+				value = 1
+			}
+			r.redisNodeRole.WithLabelValues(namespace, name, node.Pod, role).Set(value)
+		}
+		if node.Role == "" {
+			r.deleteRedisNode(namespace, name, node.Pod, false)
+			continue
+		}
+
+		if node.Lag != nil {
+			r.redisNodeLag.WithLabelValues(namespace, name, node.Pod).Set(float64(*node.Lag))
+		} else {
+			r.redisNodeLag.DeleteLabelValues(namespace, name, node.Pod)
+		}
+		if node.Role == "slave" {
+			linkUp := 0.0
+			if node.LinkUp {
+				linkUp = 1
+			}
+			r.redisNodeLinkUp.WithLabelValues(namespace, name, node.Pod).Set(linkUp)
+		} else {
+			r.redisNodeLinkUp.DeleteLabelValues(namespace, name, node.Pod)
+		}
+		r.redisNodeMemory.WithLabelValues(namespace, name, node.Pod).Set(float64(node.UsedMemory))
+		r.redisNodeClients.WithLabelValues(namespace, name, node.Pod).Set(float64(node.ConnectedClients))
+	}
+
+	key := fmt.Sprintf("%v/%v", namespace, name)
+	r.redisNodes.mutex.Lock()
+	previous := r.redisNodes.pods[key]
+	r.redisNodes.pods[key] = []string{}
+	for pod := range pods {
+		r.redisNodes.pods[key] = append(r.redisNodes.pods[key], pod)
+	}
+	r.redisNodes.mutex.Unlock()
+	for _, pod := range previous {
+		if !pods[pod] {
+			r.deleteRedisNode(namespace, name, pod, true)
+		}
+	}
+	updateResourceMetricLastUpdatedTracker(namespace, "redisfailover", name)
+}
+
+// deleteRedisNode removes the series of the given redis, but its role when it is still reported
+func (r recorder) deleteRedisNode(namespace string, name string, pod string, role bool) {
+	labels := prometheus.Labels{"namespace": namespace, "name": name, "pod": pod}
+	if role {
+		r.redisNodeRole.DeletePartialMatch(labels)
+	}
+	r.redisNodeLag.Delete(labels)
+	r.redisNodeLinkUp.Delete(labels)
+	r.redisNodeMemory.Delete(labels)
+	r.redisNodeClients.Delete(labels)
+}
+
+func (r recorder) RecordFailover(namespace string, name string, initiator string) {
+	r.failovers.WithLabelValues(namespace, name, initiator).Inc()
+}
+
+func (r recorder) RecordMasterRecovery(namespace string, name string, duration time.Duration) {
+	r.masterRecovery.WithLabelValues(namespace, name).Observe(duration.Seconds())
+}
+
+func (r recorder) RecordReconcilePhase(namespace string, name string, phase string, duration time.Duration) {
+	r.reconcilePhase.WithLabelValues(namespace, name, phase).Observe(duration.Seconds())
+	updateResourceMetricLastUpdatedTracker(namespace, "redisfailover", name)
+}
+
 func updateResourceMetricLastUpdatedTracker(namespace string, kind string, name string) {
 	mutex.Lock()
 	resourceMetricLastUpdated[fmt.Sprintf("%v/%v/%v", namespace, kind, name)] = time.Now()
@@ -253,9 +526,18 @@ func removeStaleMetrics() {
 				labelWithName["name"] = labelWithName["resource"]
 				delete(labelWithName, "resource")
 				metricsDeletedCount += recorder.clusterOK.DeletePartialMatch(label)
+				for _, nodeMetric := range []*prometheus.GaugeVec{recorder.redisNodeRole, recorder.redisNodeLag, recorder.redisNodeLinkUp, recorder.redisNodeMemory, recorder.redisNodeClients} {
+					metricsDeletedCount += nodeMetric.DeletePartialMatch(labelWithName)
+				}
+				metricsDeletedCount += recorder.reconcilePhase.DeletePartialMatch(labelWithName)
+				recorder.redisNodes.mutex.Lock()
+				delete(recorder.redisNodes.pods, fmt.Sprintf("%v/%v", labelWithName["namespace"], labelWithName["name"]))
+				recorder.redisNodes.mutex.Unlock()
 			}
 			for _, label := range ipBasedLabels {
 				metricsDeletedCount += recorder.redisOperations.DeletePartialMatch(label)
+				metricsDeletedCount += recorder.redisPoolConnections.DeletePartialMatch(label)
+				metricsDeletedCount += recorder.redisPoolRequests.DeletePartialMatch(label)
 			}
 		}
 		log.Debugf("delete %v stale metrics", metricsDeletedCount)
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -17,10 +18,11 @@ import (
 func TestPrometheusMetrics(t *testing.T) {
 
 	tests := []struct {
-		name       string
-		addMetrics func(rec metrics.Recorder)
-		expMetrics []string
-		expCode    int
+		name          string
+		addMetrics    func(rec metrics.Recorder)
+		expMetrics    []string
+		notExpMetrics []string
+		expCode       int
 	}{
 		{
 			name: "Setting OK should give an OK",
@@ -109,6 +111,101 @@ func TestPrometheusMetrics(t *testing.T) {
 			},
 			expCode: http.StatusOK,
 		},
+		{
+			name: "Setting the pool stats of an instance should appear",
+			addMetrics: func(rec metrics.Recorder) {
+				rec.SetRedisPoolStats("0.0.0.0", "6379", 5, 2, 1, 2, 1, 0)
+			},
+			expMetrics: []string{
+				`my_metrics_controller_redis_pool_requests{IP="0.0.0.0",port="6379",result="hit"} 5`,
+				`my_metrics_controller_redis_pool_requests{IP="0.0.0.0",port="6379",result="miss"} 2`,
+				`my_metrics_controller_redis_pool_requests{IP="0.0.0.0",port="6379",result="timeout"} 1`,
+				`my_metrics_controller_redis_pool_connections{IP="0.0.0.0",port="6379",state="total"} 2`,
+				`my_metrics_controller_redis_pool_connections{IP="0.0.0.0",port="6379",state="idle"} 1`,
+				`my_metrics_controller_redis_pool_connections{IP="0.0.0.0",port="6379",state="stale"} 0`,
+			},
+			expCode: http.StatusOK,
+		},
+		{
+			name: "Deleting the pool stats of an instance should remove only the desired one",
+			addMetrics: func(rec metrics.Recorder) {
+				rec.SetRedisPoolStats("0.0.0.0", "6379", 5, 2, 1, 2, 1, 0)
+				rec.SetRedisPoolStats("0.0.0.0", "26379", 3, 1, 0, 1, 1, 0)
+				rec.DeleteRedisPoolStats("0.0.0.0", "6379")
+			},
+			expMetrics: []string{
+				`my_metrics_controller_redis_pool_connections{IP="0.0.0.0",port="26379",state="total"} 1`,
+			},
+			expCode: http.StatusOK,
+		},
+		{
+			name: "Setting the redis nodes should appear by pod",
+			addMetrics: func(rec metrics.Recorder) {
+				lag := int64(10)
+				rec.SetRedisNodes("testns", "test", []metrics.RedisNode{
+					{Pod: "rfr-test-0", Role: "master", UsedMemory: 1024, ConnectedClients: 5},
+					{Pod: "rfr-test-1", Role: "slave", Lag: &lag, LinkUp: true, UsedMemory: 512, ConnectedClients: 1},
+					{Pod: "rfr-test-2"},
+				})
+			},
+			expMetrics: []string{
+				`my_metrics_controller_redis_node_role{name="test",namespace="testns",pod="rfr-test-0",role="master"} 1`,
+				`my_metrics_controller_redis_node_role{name="test",namespace="testns",pod="rfr-test-0",role="slave"} 0`,
+				`my_metrics_controller_redis_node_role{name="test",namespace="testns",pod="rfr-test-2",role="master"} 0`,
+				`my_metrics_controller_redis_node_used_memory_bytes{name="test",namespace="testns",pod="rfr-test-0"} 1024`,
+				`my_metrics_controller_redis_node_connected_clients{name="test",namespace="testns",pod="rfr-test-0"} 5`,
+				`my_metrics_controller_redis_node_replication_lag_bytes{name="test",namespace="testns",pod="rfr-test-1"} 10`,
+				`my_metrics_controller_redis_node_master_link_up{name="test",namespace="testns",pod="rfr-test-1"} 1`,
+			},
+			notExpMetrics: []string{
+				`my_metrics_controller_redis_node_master_link_up{name="test",namespace="testns",pod="rfr-test-0"}`,
+				`my_metrics_controller_redis_node_used_memory_bytes{name="test",namespace="testns",pod="rfr-test-2"}`,
+			},
+			expCode: http.StatusOK,
+		},
+		{
+			name: "Setting the redis nodes again should remove the pods gone",
+			addMetrics: func(rec metrics.Recorder) {
+				rec.SetRedisNodes("testns", "test", []metrics.RedisNode{{Pod: "rfr-test-0", Role: "master"}, {Pod: "rfr-test-1", Role: "slave"}})
+				rec.SetRedisNodes("testns", "test", []metrics.RedisNode{{Pod: "rfr-test-1", Role: "master"}})
+			},
+			expMetrics: []string{
+				`my_metrics_controller_redis_node_role{name="test",namespace="testns",pod="rfr-test-1",role="master"} 1`,
+			},
+			notExpMetrics: []string{
+				`pod="rfr-test-0"`,
+				`my_metrics_controller_redis_node_master_link_up{name="test",namespace="testns",pod="rfr-test-1"}`,
+			},
+			expCode: http.StatusOK,
+		},
+		{
+			name: "Recording failovers should count them by initiator",
+			addMetrics: func(rec metrics.Recorder) {
+				rec.RecordFailover("testns", "test", metrics.FAILOVER_BY_SENTINEL)
+				rec.RecordFailover("testns", "test", metrics.FAILOVER_BY_SENTINEL)
+				rec.RecordFailover("testns", "test", metrics.FAILOVER_BY_OPERATOR)
+				rec.RecordMasterRecovery("testns", "test", 3*time.Second)
+			},
+			expMetrics: []string{
+				`my_metrics_controller_failovers_total{initiator="SENTINEL",name="test",namespace="testns"} 2`,
+				`my_metrics_controller_failovers_total{initiator="OPERATOR",name="test",namespace="testns"} 1`,
+				`my_metrics_controller_master_recovery_duration_seconds_bucket{name="test",namespace="testns",le="4"} 1`,
+			},
+			expCode: http.StatusOK,
+		},
+		{
+			name: "Recording reconcile phases should observe them by phase",
+			addMetrics: func(rec metrics.Recorder) {
+				rec.RecordReconcilePhase("testns", "test", "Ensure", 100*time.Millisecond)
+				rec.RecordReconcilePhase("testns", "test", "EnsureRedisStatefulset", 30*time.Millisecond)
+			},
+			expMetrics: []string{
+				`my_metrics_controller_reconcile_phase_duration_seconds_bucket{name="test",namespace="testns",phase="Ensure",le="0.08"} 0`,
+				`my_metrics_controller_reconcile_phase_duration_seconds_bucket{name="test",namespace="testns",phase="Ensure",le="0.16"} 1`,
+				`my_metrics_controller_reconcile_phase_duration_seconds_count{name="test",namespace="testns",phase="EnsureRedisStatefulset"} 1`,
+			},
+			expCode: http.StatusOK,
+		},
 	}
 
 	for _, test := range tests {
@@ -134,6 +231,9 @@ func TestPrometheusMetrics(t *testing.T) {
 				for _, expMetric := range test.expMetrics {
 					assert.Contains(string(body), expMetric)
 				}
+				for _, notExpMetric := range test.notExpMetrics {
+					assert.NotContains(string(body), notExpMetric)
+				}
 			}
 		})
 	}
@@ -7,11 +7,11 @@ import (
 
 	mock "github.com/stretchr/testify/mock"
 
-	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
+	watch "k8s.io/apimachinery/pkg/watch"
 
-	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
-	watch "k8s.io/apimachinery/pkg/watch"
+	v1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
 )
 
 // RedisFailover is an autogenerated mock type for the RedisFailover type
@@ -19,21 +19,44 @@ type RedisFailover struct {
 	mock.Mock
 }
 
+// GetRedisFailover provides a mock function with given fields: ctx, namespace, name
+func (_m *RedisFailover) GetRedisFailover(ctx context.Context, namespace string, name string) (*v1.RedisFailover, error) {
+	ret := _m.Called(ctx, namespace, name)
+
+	var r0 *v1.RedisFailover
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *v1.RedisFailover); ok {
+		r0 = rf(ctx, namespace, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.RedisFailover)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, namespace, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ListRedisFailovers provides a mock function with given fields: ctx, namespace, opts
-func (_m *RedisFailover) ListRedisFailovers(ctx context.Context, namespace string, opts v1.ListOptions) (*redisfailoverv1.RedisFailoverList, error) {
+func (_m *RedisFailover) ListRedisFailovers(ctx context.Context, namespace string, opts metav1.ListOptions) (*v1.RedisFailoverList, error) {
 	ret := _m.Called(ctx, namespace, opts)
 
-	var r0 *redisfailoverv1.RedisFailoverList
-	if rf, ok := ret.Get(0).(func(context.Context, string, v1.ListOptions) *redisfailoverv1.RedisFailoverList); ok {
+	var r0 *v1.RedisFailoverList
+	if rf, ok := ret.Get(0).(func(context.Context, string, metav1.ListOptions) *v1.RedisFailoverList); ok {
 		r0 = rf(ctx, namespace, opts)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*redisfailoverv1.RedisFailoverList)
+			r0 = ret.Get(0).(*v1.RedisFailoverList)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, string, v1.ListOptions) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, string, metav1.ListOptions) error); ok {
 		r1 = rf(ctx, namespace, opts)
 	} else {
 		r1 = ret.Error(1)
@@ -42,12 +65,26 @@ func (_m *RedisFailover) ListRedisFailovers(ctx context.Context, namespace strin
 	return r0, r1
 }
 
+// UpdateRedisFailoverStatus provides a mock function with given fields: ctx, rFailover
+func (_m *RedisFailover) UpdateRedisFailoverStatus(ctx context.Context, rFailover *v1.RedisFailover) error {
+	ret := _m.Called(ctx, rFailover)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) error); ok {
+		r0 = rf(ctx, rFailover)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // WatchRedisFailovers provides a mock function with given fields: ctx, namespace, opts
-func (_m *RedisFailover) WatchRedisFailovers(ctx context.Context, namespace string, opts v1.ListOptions) (watch.Interface, error) {
+func (_m *RedisFailover) WatchRedisFailovers(ctx context.Context, namespace string, opts metav1.ListOptions) (watch.Interface, error) {
 	ret := _m.Called(ctx, namespace, opts)
 
 	var r0 watch.Interface
-	if rf, ok := ret.Get(0).(func(context.Context, string, v1.ListOptions) watch.Interface); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string, metav1.ListOptions) watch.Interface); ok {
 		r0 = rf(ctx, namespace, opts)
 	} else {
 		if ret.Get(0) != nil {
@@ -56,7 +93,7 @@ func (_m *RedisFailover) WatchRedisFailovers(ctx context.Context, namespace stri
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, string, v1.ListOptions) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, string, metav1.ListOptions) error); ok {
 		r1 = rf(ctx, namespace, opts)
 	} else {
 		r1 = ret.Error(1)
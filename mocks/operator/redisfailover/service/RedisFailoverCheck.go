@@ -8,6 +8,10 @@ import (
 	time "time"
 
 	v1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
+
+	service "github.com/spotahome/redis-operator/operator/redisfailover/service"
+
+	context "context"
 )
 
 // RedisFailoverCheck is an autogenerated mock type for the RedisFailoverCheck type
@@ -15,13 +19,13 @@ type RedisFailoverCheck struct {
 	mock.Mock
 }
 
-// CheckAllSlavesFromMaster provides a mock function with given fields: master, rFailover
-func (_m *RedisFailoverCheck) CheckAllSlavesFromMaster(master string, rFailover *v1.RedisFailover) error {
-	ret := _m.Called(master, rFailover)
+// CheckAllSlavesFromMaster provides a mock function with given fields: ctx, master, rFailover
+func (_m *RedisFailoverCheck) CheckAllSlavesFromMaster(ctx context.Context, master string, rFailover *v1.RedisFailover) error {
+	ret := _m.Called(ctx, master, rFailover)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, *v1.RedisFailover) error); ok {
-		r0 = rf(master, rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.RedisFailover) error); ok {
+		r0 = rf(ctx, master, rFailover)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -29,20 +33,20 @@ func (_m *RedisFailoverCheck) CheckAllSlavesFromMaster(master string, rFailover
 	return r0
 }
 
-// CheckIfMasterLocalhost provides a mock function with given fields: rFailover
-func (_m *RedisFailoverCheck) CheckIfMasterLocalhost(rFailover *v1.RedisFailover) (bool, error) {
-	ret := _m.Called(rFailover)
+// CheckIfMasterLocalhost provides a mock function with given fields: ctx, rFailover
+func (_m *RedisFailoverCheck) CheckIfMasterLocalhost(ctx context.Context, rFailover *v1.RedisFailover) (bool, error) {
+	ret := _m.Called(ctx, rFailover)
 
 	var r0 bool
-	if rf, ok := ret.Get(0).(func(*v1.RedisFailover) bool); ok {
-		r0 = rf(rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) bool); ok {
+		r0 = rf(ctx, rFailover)
 	} else {
 		r0 = ret.Get(0).(bool)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(*v1.RedisFailover) error); ok {
-		r1 = rf(rFailover)
+	if rf, ok := ret.Get(1).(func(context.Context, *v1.RedisFailover) error); ok {
+		r1 = rf(ctx, rFailover)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -50,13 +54,13 @@ func (_m *RedisFailoverCheck) CheckIfMasterLocalhost(rFailover *v1.RedisFailover
 	return r0, r1
 }
 
-// CheckRedisNumber provides a mock function with given fields: rFailover
-func (_m *RedisFailoverCheck) CheckRedisNumber(rFailover *v1.RedisFailover) error {
-	ret := _m.Called(rFailover)
+// CheckRedisNumber provides a mock function with given fields: ctx, rFailover
+func (_m *RedisFailoverCheck) CheckRedisNumber(ctx context.Context, rFailover *v1.RedisFailover) error {
+	ret := _m.Called(ctx, rFailover)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*v1.RedisFailover) error); ok {
-		r0 = rf(rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) error); ok {
+		r0 = rf(ctx, rFailover)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -64,20 +68,41 @@ func (_m *RedisFailoverCheck) CheckRedisNumber(rFailover *v1.RedisFailover) erro
 	return r0
 }
 
-// CheckRedisSlavesReady provides a mock function with given fields: slaveIP, rFailover
-func (_m *RedisFailoverCheck) CheckRedisSlavesReady(slaveIP string, rFailover *v1.RedisFailover) (bool, error) {
-	ret := _m.Called(slaveIP, rFailover)
+// CheckRedisSlaveInSync provides a mock function with given fields: ctx, slaveIP, masterIP, rFailover
+func (_m *RedisFailoverCheck) CheckRedisSlaveInSync(ctx context.Context, slaveIP string, masterIP string, rFailover *v1.RedisFailover) (bool, error) {
+	ret := _m.Called(ctx, slaveIP, masterIP, rFailover)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *v1.RedisFailover) bool); ok {
+		r0 = rf(ctx, slaveIP, masterIP, rFailover)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, *v1.RedisFailover) error); ok {
+		r1 = rf(ctx, slaveIP, masterIP, rFailover)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CheckRedisSlavesReady provides a mock function with given fields: ctx, slaveIP, rFailover
+func (_m *RedisFailoverCheck) CheckRedisSlavesReady(ctx context.Context, slaveIP string, rFailover *v1.RedisFailover) (bool, error) {
+	ret := _m.Called(ctx, slaveIP, rFailover)
 
 	var r0 bool
-	if rf, ok := ret.Get(0).(func(string, *v1.RedisFailover) bool); ok {
-		r0 = rf(slaveIP, rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.RedisFailover) bool); ok {
+		r0 = rf(ctx, slaveIP, rFailover)
 	} else {
 		r0 = ret.Get(0).(bool)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string, *v1.RedisFailover) error); ok {
-		r1 = rf(slaveIP, rFailover)
+	if rf, ok := ret.Get(1).(func(context.Context, string, *v1.RedisFailover) error); ok {
+		r1 = rf(ctx, slaveIP, rFailover)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -85,20 +110,22 @@ func (_m *RedisFailoverCheck) CheckRedisSlavesReady(slaveIP string, rFailover *v
 	return r0, r1
 }
 
-// CheckSentinelMonitor provides a mock function with given fields: sentinel, monitor
-func (_m *RedisFailoverCheck) CheckSentinelMonitor(sentinel string, monitor ...string) error {
+// CheckSentinelMonitor provides a mock function with given fields: ctx, sentinel, rFailover, monitor
+func (_m *RedisFailoverCheck) CheckSentinelMonitor(ctx context.Context, sentinel string, rFailover *v1.RedisFailover, monitor ...string) error {
 	_va := make([]interface{}, len(monitor))
 	for _i := range monitor {
 		_va[_i] = monitor[_i]
 	}
 	var _ca []interface{}
+	_ca = append(_ca, ctx)
 	_ca = append(_ca, sentinel)
+	_ca = append(_ca, rFailover)
 	_ca = append(_ca, _va...)
 	ret := _m.Called(_ca...)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, ...string) error); ok {
-		r0 = rf(sentinel, monitor...)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.RedisFailover, ...string) error); ok {
+		r0 = rf(ctx, sentinel, rFailover, monitor...)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -106,13 +133,13 @@ func (_m *RedisFailoverCheck) CheckSentinelMonitor(sentinel string, monitor ...s
 	return r0
 }
 
-// CheckSentinelNumber provides a mock function with given fields: rFailover
-func (_m *RedisFailoverCheck) CheckSentinelNumber(rFailover *v1.RedisFailover) error {
-	ret := _m.Called(rFailover)
+// CheckSentinelNumber provides a mock function with given fields: ctx, rFailover
+func (_m *RedisFailoverCheck) CheckSentinelNumber(ctx context.Context, rFailover *v1.RedisFailover) error {
+	ret := _m.Called(ctx, rFailover)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*v1.RedisFailover) error); ok {
-		r0 = rf(rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) error); ok {
+		r0 = rf(ctx, rFailover)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -120,13 +147,13 @@ func (_m *RedisFailoverCheck) CheckSentinelNumber(rFailover *v1.RedisFailover) e
 	return r0
 }
 
-// CheckSentinelNumberInMemory provides a mock function with given fields: sentinel, rFailover
-func (_m *RedisFailoverCheck) CheckSentinelNumberInMemory(sentinel string, rFailover *v1.RedisFailover) error {
-	ret := _m.Called(sentinel, rFailover)
+// CheckSentinelNumberInMemory provides a mock function with given fields: ctx, sentinel, rFailover
+func (_m *RedisFailoverCheck) CheckSentinelNumberInMemory(ctx context.Context, sentinel string, rFailover *v1.RedisFailover) error {
+	ret := _m.Called(ctx, sentinel, rFailover)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, *v1.RedisFailover) error); ok {
-		r0 = rf(sentinel, rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.RedisFailover) error); ok {
+		r0 = rf(ctx, sentinel, rFailover)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -134,20 +161,20 @@ func (_m *RedisFailoverCheck) CheckSentinelNumberInMemory(sentinel string, rFail
 	return r0
 }
 
-// CheckSentinelQuorum provides a mock function with given fields: rFailover
-func (_m *RedisFailoverCheck) CheckSentinelQuorum(rFailover *v1.RedisFailover) (int, error) {
-	ret := _m.Called(rFailover)
+// CheckSentinelQuorum provides a mock function with given fields: ctx, rFailover
+func (_m *RedisFailoverCheck) CheckSentinelQuorum(ctx context.Context, rFailover *v1.RedisFailover) (int, error) {
+	ret := _m.Called(ctx, rFailover)
 
 	var r0 int
-	if rf, ok := ret.Get(0).(func(*v1.RedisFailover) int); ok {
-		r0 = rf(rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) int); ok {
+		r0 = rf(ctx, rFailover)
 	} else {
 		r0 = ret.Get(0).(int)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(*v1.RedisFailover) error); ok {
-		r1 = rf(rFailover)
+	if rf, ok := ret.Get(1).(func(context.Context, *v1.RedisFailover) error); ok {
+		r1 = rf(ctx, rFailover)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -155,13 +182,13 @@ func (_m *RedisFailoverCheck) CheckSentinelQuorum(rFailover *v1.RedisFailover) (
 	return r0, r1
 }
 
-// CheckSentinelSlavesNumberInMemory provides a mock function with given fields: sentinel, rFailover
-func (_m *RedisFailoverCheck) CheckSentinelSlavesNumberInMemory(sentinel string, rFailover *v1.RedisFailover) error {
-	ret := _m.Called(sentinel, rFailover)
+// CheckSentinelSlavesNumberInMemory provides a mock function with given fields: ctx, sentinel, rFailover
+func (_m *RedisFailoverCheck) CheckSentinelSlavesNumberInMemory(ctx context.Context, sentinel string, rFailover *v1.RedisFailover) error {
+	ret := _m.Called(ctx, sentinel, rFailover)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, *v1.RedisFailover) error); ok {
-		r0 = rf(sentinel, rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.RedisFailover) error); ok {
+		r0 = rf(ctx, sentinel, rFailover)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -169,20 +196,48 @@ func (_m *RedisFailoverCheck) CheckSentinelSlavesNumberInMemory(sentinel string,
 	return r0
 }
 
-// GetMasterIP provides a mock function with given fields: rFailover
-func (_m *RedisFailoverCheck) GetMasterIP(rFailover *v1.RedisFailover) (string, error) {
-	ret := _m.Called(rFailover)
+// GetBootstrapMaster provides a mock function with given fields: ctx, rFailover
+func (_m *RedisFailoverCheck) GetBootstrapMaster(ctx context.Context, rFailover *v1.RedisFailover) (string, string, error) {
+	ret := _m.Called(ctx, rFailover)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) string); ok {
+		r0 = rf(ctx, rFailover)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 string
+	if rf, ok := ret.Get(1).(func(context.Context, *v1.RedisFailover) string); ok {
+		r1 = rf(ctx, rFailover)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, *v1.RedisFailover) error); ok {
+		r2 = rf(ctx, rFailover)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetMasterIP provides a mock function with given fields: ctx, rFailover
+func (_m *RedisFailoverCheck) GetMasterIP(ctx context.Context, rFailover *v1.RedisFailover) (string, error) {
+	ret := _m.Called(ctx, rFailover)
 
 	var r0 string
-	if rf, ok := ret.Get(0).(func(*v1.RedisFailover) string); ok {
-		r0 = rf(rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) string); ok {
+		r0 = rf(ctx, rFailover)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(*v1.RedisFailover) error); ok {
-		r1 = rf(rFailover)
+	if rf, ok := ret.Get(1).(func(context.Context, *v1.RedisFailover) error); ok {
+		r1 = rf(ctx, rFailover)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -190,20 +245,20 @@ func (_m *RedisFailoverCheck) GetMasterIP(rFailover *v1.RedisFailover) (string,
 	return r0, r1
 }
 
-// GetMaxRedisPodTime provides a mock function with given fields: rFailover
-func (_m *RedisFailoverCheck) GetMaxRedisPodTime(rFailover *v1.RedisFailover) (time.Duration, error) {
-	ret := _m.Called(rFailover)
+// GetMaxRedisPodTime provides a mock function with given fields: ctx, rFailover
+func (_m *RedisFailoverCheck) GetMaxRedisPodTime(ctx context.Context, rFailover *v1.RedisFailover) (time.Duration, error) {
+	ret := _m.Called(ctx, rFailover)
 
 	var r0 time.Duration
-	if rf, ok := ret.Get(0).(func(*v1.RedisFailover) time.Duration); ok {
-		r0 = rf(rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) time.Duration); ok {
+		r0 = rf(ctx, rFailover)
 	} else {
 		r0 = ret.Get(0).(time.Duration)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(*v1.RedisFailover) error); ok {
-		r1 = rf(rFailover)
+	if rf, ok := ret.Get(1).(func(context.Context, *v1.RedisFailover) error); ok {
+		r1 = rf(ctx, rFailover)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -211,20 +266,20 @@ func (_m *RedisFailoverCheck) GetMaxRedisPodTime(rFailover *v1.RedisFailover) (t
 	return r0, r1
 }
 
-// GetNumberMasters provides a mock function with given fields: rFailover
-func (_m *RedisFailoverCheck) GetNumberMasters(rFailover *v1.RedisFailover) (int, error) {
-	ret := _m.Called(rFailover)
+// GetNumberMasters provides a mock function with given fields: ctx, rFailover
+func (_m *RedisFailoverCheck) GetNumberMasters(ctx context.Context, rFailover *v1.RedisFailover) (int, error) {
+	ret := _m.Called(ctx, rFailover)
 
 	var r0 int
-	if rf, ok := ret.Get(0).(func(*v1.RedisFailover) int); ok {
-		r0 = rf(rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) int); ok {
+		r0 = rf(ctx, rFailover)
 	} else {
 		r0 = ret.Get(0).(int)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(*v1.RedisFailover) error); ok {
-		r1 = rf(rFailover)
+	if rf, ok := ret.Get(1).(func(context.Context, *v1.RedisFailover) error); ok {
+		r1 = rf(ctx, rFailover)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -232,20 +287,20 @@ func (_m *RedisFailoverCheck) GetNumberMasters(rFailover *v1.RedisFailover) (int
 	return r0, r1
 }
 
-// GetRedisRevisionHash provides a mock function with given fields: podName, rFailover
-func (_m *RedisFailoverCheck) GetRedisRevisionHash(podName string, rFailover *v1.RedisFailover) (string, error) {
-	ret := _m.Called(podName, rFailover)
+// GetRedisRevisionHash provides a mock function with given fields: ctx, podName, rFailover
+func (_m *RedisFailoverCheck) GetRedisRevisionHash(ctx context.Context, podName string, rFailover *v1.RedisFailover) (string, error) {
+	ret := _m.Called(ctx, podName, rFailover)
 
 	var r0 string
-	if rf, ok := ret.Get(0).(func(string, *v1.RedisFailover) string); ok {
-		r0 = rf(podName, rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.RedisFailover) string); ok {
+		r0 = rf(ctx, podName, rFailover)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string, *v1.RedisFailover) error); ok {
-		r1 = rf(podName, rFailover)
+	if rf, ok := ret.Get(1).(func(context.Context, string, *v1.RedisFailover) error); ok {
+		r1 = rf(ctx, podName, rFailover)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -253,13 +308,36 @@ func (_m *RedisFailoverCheck) GetRedisRevisionHash(podName string, rFailover *v1
 	return r0, r1
 }
 
-// GetRedisesIPs provides a mock function with given fields: rFailover
-func (_m *RedisFailoverCheck) GetRedisesIPs(rFailover *v1.RedisFailover) ([]string, error) {
-	ret := _m.Called(rFailover)
+// GetRedisesExternalAddresses provides a mock function with given fields: ctx, rFailover
+func (_m *RedisFailoverCheck) GetRedisesExternalAddresses(ctx context.Context, rFailover *v1.RedisFailover) (map[string]string, error) {
+	ret := _m.Called(ctx, rFailover)
+
+	var r0 map[string]string
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) map[string]string); ok {
+		r0 = rf(ctx, rFailover)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *v1.RedisFailover) error); ok {
+		r1 = rf(ctx, rFailover)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRedisesIPs provides a mock function with given fields: ctx, rFailover
+func (_m *RedisFailoverCheck) GetRedisesIPs(ctx context.Context, rFailover *v1.RedisFailover) ([]string, error) {
+	ret := _m.Called(ctx, rFailover)
 
 	var r0 []string
-	if rf, ok := ret.Get(0).(func(*v1.RedisFailover) []string); ok {
-		r0 = rf(rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) []string); ok {
+		r0 = rf(ctx, rFailover)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]string)
@@ -267,8 +345,8 @@ func (_m *RedisFailoverCheck) GetRedisesIPs(rFailover *v1.RedisFailover) ([]stri
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(*v1.RedisFailover) error); ok {
-		r1 = rf(rFailover)
+	if rf, ok := ret.Get(1).(func(context.Context, *v1.RedisFailover) error); ok {
+		r1 = rf(ctx, rFailover)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -276,20 +354,20 @@ func (_m *RedisFailoverCheck) GetRedisesIPs(rFailover *v1.RedisFailover) ([]stri
 	return r0, r1
 }
 
-// GetRedisesMasterPod provides a mock function with given fields: rFailover
-func (_m *RedisFailoverCheck) GetRedisesMasterPod(rFailover *v1.RedisFailover) (string, error) {
-	ret := _m.Called(rFailover)
+// GetRedisesMasterPod provides a mock function with given fields: ctx, rFailover
+func (_m *RedisFailoverCheck) GetRedisesMasterPod(ctx context.Context, rFailover *v1.RedisFailover) (string, error) {
+	ret := _m.Called(ctx, rFailover)
 
 	var r0 string
-	if rf, ok := ret.Get(0).(func(*v1.RedisFailover) string); ok {
-		r0 = rf(rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) string); ok {
+		r0 = rf(ctx, rFailover)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(*v1.RedisFailover) error); ok {
-		r1 = rf(rFailover)
+	if rf, ok := ret.Get(1).(func(context.Context, *v1.RedisFailover) error); ok {
+		r1 = rf(ctx, rFailover)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -297,22 +375,22 @@ func (_m *RedisFailoverCheck) GetRedisesMasterPod(rFailover *v1.RedisFailover) (
 	return r0, r1
 }
 
-// GetRedisesSlavesPods provides a mock function with given fields: rFailover
-func (_m *RedisFailoverCheck) GetRedisesSlavesPods(rFailover *v1.RedisFailover) ([]string, error) {
-	ret := _m.Called(rFailover)
+// GetRedisesOnDrainingNodes provides a mock function with given fields: ctx, rFailover
+func (_m *RedisFailoverCheck) GetRedisesOnDrainingNodes(ctx context.Context, rFailover *v1.RedisFailover) (map[string]bool, error) {
+	ret := _m.Called(ctx, rFailover)
 
-	var r0 []string
-	if rf, ok := ret.Get(0).(func(*v1.RedisFailover) []string); ok {
-		r0 = rf(rFailover)
+	var r0 map[string]bool
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) map[string]bool); ok {
+		r0 = rf(ctx, rFailover)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]string)
+			r0 = ret.Get(0).(map[string]bool)
 		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(*v1.RedisFailover) error); ok {
-		r1 = rf(rFailover)
+	if rf, ok := ret.Get(1).(func(context.Context, *v1.RedisFailover) error); ok {
+		r1 = rf(ctx, rFailover)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -320,13 +398,13 @@ func (_m *RedisFailoverCheck) GetRedisesSlavesPods(rFailover *v1.RedisFailover)
 	return r0, r1
 }
 
-// GetSentinelsIPs provides a mock function with given fields: rFailover
-func (_m *RedisFailoverCheck) GetSentinelsIPs(rFailover *v1.RedisFailover) ([]string, error) {
-	ret := _m.Called(rFailover)
+// GetRedisesSlavesPods provides a mock function with given fields: ctx, rFailover
+func (_m *RedisFailoverCheck) GetRedisesSlavesPods(ctx context.Context, rFailover *v1.RedisFailover) ([]string, error) {
+	ret := _m.Called(ctx, rFailover)
 
 	var r0 []string
-	if rf, ok := ret.Get(0).(func(*v1.RedisFailover) []string); ok {
-		r0 = rf(rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) []string); ok {
+		r0 = rf(ctx, rFailover)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]string)
@@ -334,8 +412,8 @@ func (_m *RedisFailoverCheck) GetSentinelsIPs(rFailover *v1.RedisFailover) ([]st
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(*v1.RedisFailover) error); ok {
-		r1 = rf(rFailover)
+	if rf, ok := ret.Get(1).(func(context.Context, *v1.RedisFailover) error); ok {
+		r1 = rf(ctx, rFailover)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -343,20 +421,73 @@ func (_m *RedisFailoverCheck) GetSentinelsIPs(rFailover *v1.RedisFailover) ([]st
 	return r0, r1
 }
 
-// GetStatefulSetUpdateRevision provides a mock function with given fields: rFailover
-func (_m *RedisFailoverCheck) GetStatefulSetUpdateRevision(rFailover *v1.RedisFailover) (string, error) {
-	ret := _m.Called(rFailover)
+// GetReplicaOfMaster provides a mock function with given fields: ctx, rFailover
+func (_m *RedisFailoverCheck) GetReplicaOfMaster(ctx context.Context, rFailover *v1.RedisFailover) (string, string, error) {
+	ret := _m.Called(ctx, rFailover)
 
 	var r0 string
-	if rf, ok := ret.Get(0).(func(*v1.RedisFailover) string); ok {
-		r0 = rf(rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) string); ok {
+		r0 = rf(ctx, rFailover)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
+	var r1 string
+	if rf, ok := ret.Get(1).(func(context.Context, *v1.RedisFailover) string); ok {
+		r1 = rf(ctx, rFailover)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, *v1.RedisFailover) error); ok {
+		r2 = rf(ctx, rFailover)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetSentinelsExternalAddresses provides a mock function with given fields: ctx, rFailover
+func (_m *RedisFailoverCheck) GetSentinelsExternalAddresses(ctx context.Context, rFailover *v1.RedisFailover) (map[string]string, error) {
+	ret := _m.Called(ctx, rFailover)
+
+	var r0 map[string]string
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) map[string]string); ok {
+		r0 = rf(ctx, rFailover)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *v1.RedisFailover) error); ok {
+		r1 = rf(ctx, rFailover)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSentinelsIPs provides a mock function with given fields: ctx, rFailover
+func (_m *RedisFailoverCheck) GetSentinelsIPs(ctx context.Context, rFailover *v1.RedisFailover) ([]string, error) {
+	ret := _m.Called(ctx, rFailover)
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) []string); ok {
+		r0 = rf(ctx, rFailover)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
 	var r1 error
-	if rf, ok := ret.Get(1).(func(*v1.RedisFailover) error); ok {
-		r1 = rf(rFailover)
+	if rf, ok := ret.Get(1).(func(context.Context, *v1.RedisFailover) error); ok {
+		r1 = rf(ctx, rFailover)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -364,13 +495,59 @@ func (_m *RedisFailoverCheck) GetStatefulSetUpdateRevision(rFailover *v1.RedisFa
 	return r0, r1
 }
 
-// IsClusterRunning provides a mock function with given fields: rFailover
-func (_m *RedisFailoverCheck) IsClusterRunning(rFailover *v1.RedisFailover) bool {
-	ret := _m.Called(rFailover)
+// GetStatefulSetUpdateRevisions provides a mock function with given fields: ctx, rFailover
+func (_m *RedisFailoverCheck) GetStatefulSetUpdateRevisions(ctx context.Context, rFailover *v1.RedisFailover) (map[string]string, error) {
+	ret := _m.Called(ctx, rFailover)
+
+	var r0 map[string]string
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) map[string]string); ok {
+		r0 = rf(ctx, rFailover)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *v1.RedisFailover) error); ok {
+		r1 = rf(ctx, rFailover)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTopology provides a mock function with given fields: ctx, rFailover
+func (_m *RedisFailoverCheck) GetTopology(ctx context.Context, rFailover *v1.RedisFailover) (*service.Topology, error) {
+	ret := _m.Called(ctx, rFailover)
+
+	var r0 *service.Topology
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) *service.Topology); ok {
+		r0 = rf(ctx, rFailover)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*service.Topology)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *v1.RedisFailover) error); ok {
+		r1 = rf(ctx, rFailover)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IsClusterRunning provides a mock function with given fields: ctx, rFailover
+func (_m *RedisFailoverCheck) IsClusterRunning(ctx context.Context, rFailover *v1.RedisFailover) bool {
+	ret := _m.Called(ctx, rFailover)
 
 	var r0 bool
-	if rf, ok := ret.Get(0).(func(*v1.RedisFailover) bool); ok {
-		r0 = rf(rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) bool); ok {
+		r0 = rf(ctx, rFailover)
 	} else {
 		r0 = ret.Get(0).(bool)
 	}
@@ -378,13 +555,34 @@ func (_m *RedisFailoverCheck) IsClusterRunning(rFailover *v1.RedisFailover) bool
 	return r0
 }
 
-// IsRedisRunning provides a mock function with given fields: rFailover
-func (_m *RedisFailoverCheck) IsRedisRunning(rFailover *v1.RedisFailover) bool {
-	ret := _m.Called(rFailover)
+// IsPasswordRotating provides a mock function with given fields: ctx, rFailover
+func (_m *RedisFailoverCheck) IsPasswordRotating(ctx context.Context, rFailover *v1.RedisFailover) (bool, error) {
+	ret := _m.Called(ctx, rFailover)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) bool); ok {
+		r0 = rf(ctx, rFailover)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *v1.RedisFailover) error); ok {
+		r1 = rf(ctx, rFailover)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IsRedisRunning provides a mock function with given fields: ctx, rFailover
+func (_m *RedisFailoverCheck) IsRedisRunning(ctx context.Context, rFailover *v1.RedisFailover) bool {
+	ret := _m.Called(ctx, rFailover)
 
 	var r0 bool
-	if rf, ok := ret.Get(0).(func(*v1.RedisFailover) bool); ok {
-		r0 = rf(rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) bool); ok {
+		r0 = rf(ctx, rFailover)
 	} else {
 		r0 = ret.Get(0).(bool)
 	}
@@ -392,13 +590,13 @@ func (_m *RedisFailoverCheck) IsRedisRunning(rFailover *v1.RedisFailover) bool {
 	return r0
 }
 
-// IsSentinelRunning provides a mock function with given fields: rFailover
-func (_m *RedisFailoverCheck) IsSentinelRunning(rFailover *v1.RedisFailover) bool {
-	ret := _m.Called(rFailover)
+// IsSentinelRunning provides a mock function with given fields: ctx, rFailover
+func (_m *RedisFailoverCheck) IsSentinelRunning(ctx context.Context, rFailover *v1.RedisFailover) bool {
+	ret := _m.Called(ctx, rFailover)
 
 	var r0 bool
-	if rf, ok := ret.Get(0).(func(*v1.RedisFailover) bool); ok {
-		r0 = rf(rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) bool); ok {
+		r0 = rf(ctx, rFailover)
 	} else {
 		r0 = ret.Get(0).(bool)
 	}
@@ -4,9 +4,14 @@ package mocks
 
 import (
 	mock "github.com/stretchr/testify/mock"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	v1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
+
+	service "github.com/spotahome/redis-operator/operator/redisfailover/service"
+
+	context "context"
 )
 
 // RedisFailoverClient is an autogenerated mock type for the RedisFailoverClient type
@@ -14,13 +19,139 @@ type RedisFailoverClient struct {
 	mock.Mock
 }
 
-// EnsureNotPresentRedisService provides a mock function with given fields: rFailover
-func (_m *RedisFailoverClient) EnsureNotPresentRedisService(rFailover *v1.RedisFailover) error {
-	ret := _m.Called(rFailover)
+// EnsureExternalAccessServices provides a mock function with given fields: ctx, rFailover, labels, ownerRefs
+func (_m *RedisFailoverClient) EnsureExternalAccessServices(ctx context.Context, rFailover *v1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error {
+	ret := _m.Called(ctx, rFailover, labels, ownerRefs)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover, map[string]string, []metav1.OwnerReference) error); ok {
+		r0 = rf(ctx, rFailover, labels, ownerRefs)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// EnsureMonitoring provides a mock function with given fields: ctx, rFailover, labels, ownerRefs
+func (_m *RedisFailoverClient) EnsureMonitoring(ctx context.Context, rFailover *v1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error {
+	ret := _m.Called(ctx, rFailover, labels, ownerRefs)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover, map[string]string, []metav1.OwnerReference) error); ok {
+		r0 = rf(ctx, rFailover, labels, ownerRefs)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// EnsureNetworkPolicies provides a mock function with given fields: ctx, rFailover, labels, ownerRefs
+func (_m *RedisFailoverClient) EnsureNetworkPolicies(ctx context.Context, rFailover *v1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error {
+	ret := _m.Called(ctx, rFailover, labels, ownerRefs)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover, map[string]string, []metav1.OwnerReference) error); ok {
+		r0 = rf(ctx, rFailover, labels, ownerRefs)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// EnsureNotPresentRedisService provides a mock function with given fields: ctx, rFailover
+func (_m *RedisFailoverClient) EnsureNotPresentRedisService(ctx context.Context, rFailover *v1.RedisFailover) error {
+	ret := _m.Called(ctx, rFailover)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) error); ok {
+		r0 = rf(ctx, rFailover)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// EnsureProxyAllResources provides a mock function with given fields: ctx, rFailover, labels, ownerRefs
+func (_m *RedisFailoverClient) EnsureProxyAllResources(ctx context.Context, rFailover *v1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error {
+	ret := _m.Called(ctx, rFailover, labels, ownerRefs)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover, map[string]string, []metav1.OwnerReference) error); ok {
+		r0 = rf(ctx, rFailover, labels, ownerRefs)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// EnsureProxyConfigMap provides a mock function with given fields: ctx, rFailover, labels, ownerRefs, backends
+func (_m *RedisFailoverClient) EnsureProxyConfigMap(ctx context.Context, rFailover *v1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference, backends service.ProxyBackends) error {
+	ret := _m.Called(ctx, rFailover, labels, ownerRefs, backends)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover, map[string]string, []metav1.OwnerReference, service.ProxyBackends) error); ok {
+		r0 = rf(ctx, rFailover, labels, ownerRefs, backends)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// EnsureProxyDeployment provides a mock function with given fields: ctx, rFailover, labels, ownerRefs
+func (_m *RedisFailoverClient) EnsureProxyDeployment(ctx context.Context, rFailover *v1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error {
+	ret := _m.Called(ctx, rFailover, labels, ownerRefs)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover, map[string]string, []metav1.OwnerReference) error); ok {
+		r0 = rf(ctx, rFailover, labels, ownerRefs)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// EnsureRedisConfigMap provides a mock function with given fields: ctx, rFailover, labels, ownerRefs
+func (_m *RedisFailoverClient) EnsureRedisConfigMap(ctx context.Context, rFailover *v1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error {
+	ret := _m.Called(ctx, rFailover, labels, ownerRefs)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover, map[string]string, []metav1.OwnerReference) error); ok {
+		r0 = rf(ctx, rFailover, labels, ownerRefs)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// EnsureRedisMasterService provides a mock function with given fields: ctx, rFailover, labels, ownerRefs
+func (_m *RedisFailoverClient) EnsureRedisMasterService(ctx context.Context, rFailover *v1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error {
+	ret := _m.Called(ctx, rFailover, labels, ownerRefs)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover, map[string]string, []metav1.OwnerReference) error); ok {
+		r0 = rf(ctx, rFailover, labels, ownerRefs)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// EnsureRedisReadinessConfigMap provides a mock function with given fields: ctx, rFailover, labels, ownerRefs
+func (_m *RedisFailoverClient) EnsureRedisReadinessConfigMap(ctx context.Context, rFailover *v1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error {
+	ret := _m.Called(ctx, rFailover, labels, ownerRefs)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*v1.RedisFailover) error); ok {
-		r0 = rf(rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover, map[string]string, []metav1.OwnerReference) error); ok {
+		r0 = rf(ctx, rFailover, labels, ownerRefs)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -28,13 +159,13 @@ func (_m *RedisFailoverClient) EnsureNotPresentRedisService(rFailover *v1.RedisF
 	return r0
 }
 
-// EnsureRedisConfigMap provides a mock function with given fields: rFailover, labels, ownerRefs
-func (_m *RedisFailoverClient) EnsureRedisConfigMap(rFailover *v1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error {
-	ret := _m.Called(rFailover, labels, ownerRefs)
+// EnsureRedisReplicasService provides a mock function with given fields: ctx, rFailover, labels, ownerRefs
+func (_m *RedisFailoverClient) EnsureRedisReplicasService(ctx context.Context, rFailover *v1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error {
+	ret := _m.Called(ctx, rFailover, labels, ownerRefs)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*v1.RedisFailover, map[string]string, []metav1.OwnerReference) error); ok {
-		r0 = rf(rFailover, labels, ownerRefs)
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover, map[string]string, []metav1.OwnerReference) error); ok {
+		r0 = rf(ctx, rFailover, labels, ownerRefs)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -42,13 +173,13 @@ func (_m *RedisFailoverClient) EnsureRedisConfigMap(rFailover *v1.RedisFailover,
 	return r0
 }
 
-// EnsureRedisReadinessConfigMap provides a mock function with given fields: rFailover, labels, ownerRefs
-func (_m *RedisFailoverClient) EnsureRedisReadinessConfigMap(rFailover *v1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error {
-	ret := _m.Called(rFailover, labels, ownerRefs)
+// EnsureRedisService provides a mock function with given fields: ctx, rFailover, labels, ownerRefs
+func (_m *RedisFailoverClient) EnsureRedisService(ctx context.Context, rFailover *v1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error {
+	ret := _m.Called(ctx, rFailover, labels, ownerRefs)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*v1.RedisFailover, map[string]string, []metav1.OwnerReference) error); ok {
-		r0 = rf(rFailover, labels, ownerRefs)
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover, map[string]string, []metav1.OwnerReference) error); ok {
+		r0 = rf(ctx, rFailover, labels, ownerRefs)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -56,13 +187,13 @@ func (_m *RedisFailoverClient) EnsureRedisReadinessConfigMap(rFailover *v1.Redis
 	return r0
 }
 
-// EnsureRedisService provides a mock function with given fields: rFailover, labels, ownerRefs
-func (_m *RedisFailoverClient) EnsureRedisService(rFailover *v1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error {
-	ret := _m.Called(rFailover, labels, ownerRefs)
+// EnsureRedisShutdownConfigMap provides a mock function with given fields: ctx, rFailover, labels, ownerRefs
+func (_m *RedisFailoverClient) EnsureRedisShutdownConfigMap(ctx context.Context, rFailover *v1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error {
+	ret := _m.Called(ctx, rFailover, labels, ownerRefs)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*v1.RedisFailover, map[string]string, []metav1.OwnerReference) error); ok {
-		r0 = rf(rFailover, labels, ownerRefs)
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover, map[string]string, []metav1.OwnerReference) error); ok {
+		r0 = rf(ctx, rFailover, labels, ownerRefs)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -70,13 +201,13 @@ func (_m *RedisFailoverClient) EnsureRedisService(rFailover *v1.RedisFailover, l
 	return r0
 }
 
-// EnsureRedisShutdownConfigMap provides a mock function with given fields: rFailover, labels, ownerRefs
-func (_m *RedisFailoverClient) EnsureRedisShutdownConfigMap(rFailover *v1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error {
-	ret := _m.Called(rFailover, labels, ownerRefs)
+// EnsureRedisStatefulset provides a mock function with given fields: ctx, rFailover, labels, ownerRefs
+func (_m *RedisFailoverClient) EnsureRedisStatefulset(ctx context.Context, rFailover *v1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error {
+	ret := _m.Called(ctx, rFailover, labels, ownerRefs)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*v1.RedisFailover, map[string]string, []metav1.OwnerReference) error); ok {
-		r0 = rf(rFailover, labels, ownerRefs)
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover, map[string]string, []metav1.OwnerReference) error); ok {
+		r0 = rf(ctx, rFailover, labels, ownerRefs)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -84,13 +215,13 @@ func (_m *RedisFailoverClient) EnsureRedisShutdownConfigMap(rFailover *v1.RedisF
 	return r0
 }
 
-// EnsureRedisStatefulset provides a mock function with given fields: rFailover, labels, ownerRefs
-func (_m *RedisFailoverClient) EnsureRedisStatefulset(rFailover *v1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error {
-	ret := _m.Called(rFailover, labels, ownerRefs)
+// EnsureRedisZonesUnchanged provides a mock function with given fields: ctx, rFailover
+func (_m *RedisFailoverClient) EnsureRedisZonesUnchanged(ctx context.Context, rFailover *v1.RedisFailover) error {
+	ret := _m.Called(ctx, rFailover)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*v1.RedisFailover, map[string]string, []metav1.OwnerReference) error); ok {
-		r0 = rf(rFailover, labels, ownerRefs)
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) error); ok {
+		r0 = rf(ctx, rFailover)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -98,13 +229,13 @@ func (_m *RedisFailoverClient) EnsureRedisStatefulset(rFailover *v1.RedisFailove
 	return r0
 }
 
-// EnsureSentinelConfigMap provides a mock function with given fields: rFailover, labels, ownerRefs
-func (_m *RedisFailoverClient) EnsureSentinelConfigMap(rFailover *v1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error {
-	ret := _m.Called(rFailover, labels, ownerRefs)
+// EnsureSentinelConfigMap provides a mock function with given fields: ctx, rFailover, labels, ownerRefs
+func (_m *RedisFailoverClient) EnsureSentinelConfigMap(ctx context.Context, rFailover *v1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error {
+	ret := _m.Called(ctx, rFailover, labels, ownerRefs)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*v1.RedisFailover, map[string]string, []metav1.OwnerReference) error); ok {
-		r0 = rf(rFailover, labels, ownerRefs)
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover, map[string]string, []metav1.OwnerReference) error); ok {
+		r0 = rf(ctx, rFailover, labels, ownerRefs)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -112,13 +243,13 @@ func (_m *RedisFailoverClient) EnsureSentinelConfigMap(rFailover *v1.RedisFailov
 	return r0
 }
 
-// EnsureSentinelStatefulset provides a mock function with given fields: rFailover, labels, ownerRefs
-func (_m *RedisFailoverClient) EnsureSentinelStatefulset(rFailover *v1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error {
-	ret := _m.Called(rFailover, labels, ownerRefs)
+// EnsureSentinelService provides a mock function with given fields: ctx, rFailover, labels, ownerRefs
+func (_m *RedisFailoverClient) EnsureSentinelService(ctx context.Context, rFailover *v1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error {
+	ret := _m.Called(ctx, rFailover, labels, ownerRefs)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*v1.RedisFailover, map[string]string, []metav1.OwnerReference) error); ok {
-		r0 = rf(rFailover, labels, ownerRefs)
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover, map[string]string, []metav1.OwnerReference) error); ok {
+		r0 = rf(ctx, rFailover, labels, ownerRefs)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -126,13 +257,13 @@ func (_m *RedisFailoverClient) EnsureSentinelStatefulset(rFailover *v1.RedisFail
 	return r0
 }
 
-// EnsureSentinelService provides a mock function with given fields: rFailover, labels, ownerRefs
-func (_m *RedisFailoverClient) EnsureSentinelService(rFailover *v1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error {
-	ret := _m.Called(rFailover, labels, ownerRefs)
+// EnsureSentinelStatefulset provides a mock function with given fields: ctx, rFailover, labels, ownerRefs
+func (_m *RedisFailoverClient) EnsureSentinelStatefulset(ctx context.Context, rFailover *v1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error {
+	ret := _m.Called(ctx, rFailover, labels, ownerRefs)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*v1.RedisFailover, map[string]string, []metav1.OwnerReference) error); ok {
-		r0 = rf(rFailover, labels, ownerRefs)
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover, map[string]string, []metav1.OwnerReference) error); ok {
+		r0 = rf(ctx, rFailover, labels, ownerRefs)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -6,6 +6,8 @@ import (
 	mock "github.com/stretchr/testify/mock"
 
 	v1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
+
+	context "context"
 )
 
 // RedisFailoverHeal is an autogenerated mock type for the RedisFailoverHeal type
@@ -13,13 +15,139 @@ type RedisFailoverHeal struct {
 	mock.Mock
 }
 
-// DeletePod provides a mock function with given fields: podName, rFailover
-func (_m *RedisFailoverHeal) DeletePod(podName string, rFailover *v1.RedisFailover) error {
-	ret := _m.Called(podName, rFailover)
+// DeletePod provides a mock function with given fields: ctx, podName, rFailover
+func (_m *RedisFailoverHeal) DeletePod(ctx context.Context, podName string, rFailover *v1.RedisFailover) error {
+	ret := _m.Called(ctx, podName, rFailover)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.RedisFailover) error); ok {
+		r0 = rf(ctx, podName, rFailover)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// EndSwitchover provides a mock function with given fields: ctx, rFailover
+func (_m *RedisFailoverHeal) EndSwitchover(ctx context.Context, rFailover *v1.RedisFailover) error {
+	ret := _m.Called(ctx, rFailover)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) error); ok {
+		r0 = rf(ctx, rFailover)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MakeMaster provides a mock function with given fields: ctx, ip, rFailover
+func (_m *RedisFailoverHeal) MakeMaster(ctx context.Context, ip string, rFailover *v1.RedisFailover) error {
+	ret := _m.Called(ctx, ip, rFailover)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.RedisFailover) error); ok {
+		r0 = rf(ctx, ip, rFailover)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewSentinelMonitor provides a mock function with given fields: ctx, ip, monitor, rFailover
+func (_m *RedisFailoverHeal) NewSentinelMonitor(ctx context.Context, ip string, monitor string, rFailover *v1.RedisFailover) error {
+	ret := _m.Called(ctx, ip, monitor, rFailover)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *v1.RedisFailover) error); ok {
+		r0 = rf(ctx, ip, monitor, rFailover)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewSentinelMonitorWithPort provides a mock function with given fields: ctx, ip, monitor, port, rFailover
+func (_m *RedisFailoverHeal) NewSentinelMonitorWithPort(ctx context.Context, ip string, monitor string, port string, rFailover *v1.RedisFailover) error {
+	ret := _m.Called(ctx, ip, monitor, port, rFailover)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, *v1.RedisFailover) error); ok {
+		r0 = rf(ctx, ip, monitor, port, rFailover)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RestoreSentinel provides a mock function with given fields: ctx, ip, rFailover
+func (_m *RedisFailoverHeal) RestoreSentinel(ctx context.Context, ip string, rFailover *v1.RedisFailover) error {
+	ret := _m.Called(ctx, ip, rFailover)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.RedisFailover) error); ok {
+		r0 = rf(ctx, ip, rFailover)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetExternalMasterOnAll provides a mock function with given fields: ctx, masterIP, masterPort, rFailover
+func (_m *RedisFailoverHeal) SetExternalMasterOnAll(ctx context.Context, masterIP string, masterPort string, rFailover *v1.RedisFailover) error {
+	ret := _m.Called(ctx, masterIP, masterPort, rFailover)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *v1.RedisFailover) error); ok {
+		r0 = rf(ctx, masterIP, masterPort, rFailover)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetExternalMasterStatus provides a mock function with given fields: ctx, masterIP, masterPort, rFailover
+func (_m *RedisFailoverHeal) SetExternalMasterStatus(ctx context.Context, masterIP string, masterPort string, rFailover *v1.RedisFailover) error {
+	ret := _m.Called(ctx, masterIP, masterPort, rFailover)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *v1.RedisFailover) error); ok {
+		r0 = rf(ctx, masterIP, masterPort, rFailover)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetMasterOnAll provides a mock function with given fields: ctx, masterIP, rFailover
+func (_m *RedisFailoverHeal) SetMasterOnAll(ctx context.Context, masterIP string, rFailover *v1.RedisFailover) error {
+	ret := _m.Called(ctx, masterIP, rFailover)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.RedisFailover) error); ok {
+		r0 = rf(ctx, masterIP, rFailover)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetMostUpToDateAsMaster provides a mock function with given fields: ctx, rFailover
+func (_m *RedisFailoverHeal) SetMostUpToDateAsMaster(ctx context.Context, rFailover *v1.RedisFailover) error {
+	ret := _m.Called(ctx, rFailover)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, *v1.RedisFailover) error); ok {
-		r0 = rf(podName, rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) error); ok {
+		r0 = rf(ctx, rFailover)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -27,13 +155,13 @@ func (_m *RedisFailoverHeal) DeletePod(podName string, rFailover *v1.RedisFailov
 	return r0
 }
 
-// MakeMaster provides a mock function with given fields: ip, rFailover
-func (_m *RedisFailoverHeal) MakeMaster(ip string, rFailover *v1.RedisFailover) error {
-	ret := _m.Called(ip, rFailover)
+// SetOldestAsMaster provides a mock function with given fields: ctx, rFailover
+func (_m *RedisFailoverHeal) SetOldestAsMaster(ctx context.Context, rFailover *v1.RedisFailover) error {
+	ret := _m.Called(ctx, rFailover)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, *v1.RedisFailover) error); ok {
-		r0 = rf(ip, rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) error); ok {
+		r0 = rf(ctx, rFailover)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -41,13 +169,13 @@ func (_m *RedisFailoverHeal) MakeMaster(ip string, rFailover *v1.RedisFailover)
 	return r0
 }
 
-// NewSentinelMonitor provides a mock function with given fields: ip, monitor, rFailover
-func (_m *RedisFailoverHeal) NewSentinelMonitor(ip string, monitor string, rFailover *v1.RedisFailover) error {
-	ret := _m.Called(ip, monitor, rFailover)
+// SetPasswordRotationStatus provides a mock function with given fields: ctx, phase, message, rFailover
+func (_m *RedisFailoverHeal) SetPasswordRotationStatus(ctx context.Context, phase v1.PasswordRotationPhase, message string, rFailover *v1.RedisFailover) error {
+	ret := _m.Called(ctx, phase, message, rFailover)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string, *v1.RedisFailover) error); ok {
-		r0 = rf(ip, monitor, rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, v1.PasswordRotationPhase, string, *v1.RedisFailover) error); ok {
+		r0 = rf(ctx, phase, message, rFailover)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -55,13 +183,13 @@ func (_m *RedisFailoverHeal) NewSentinelMonitor(ip string, monitor string, rFail
 	return r0
 }
 
-// NewSentinelMonitorWithPort provides a mock function with given fields: ip, monitor, port, rFailover
-func (_m *RedisFailoverHeal) NewSentinelMonitorWithPort(ip string, monitor string, port string, rFailover *v1.RedisFailover) error {
-	ret := _m.Called(ip, monitor, port, rFailover)
+// SetRedisAnnounce provides a mock function with given fields: ctx, ip, announceIP, announcePort, rFailover
+func (_m *RedisFailoverHeal) SetRedisAnnounce(ctx context.Context, ip string, announceIP string, announcePort string, rFailover *v1.RedisFailover) error {
+	ret := _m.Called(ctx, ip, announceIP, announcePort, rFailover)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string, string, *v1.RedisFailover) error); ok {
-		r0 = rf(ip, monitor, port, rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, *v1.RedisFailover) error); ok {
+		r0 = rf(ctx, ip, announceIP, announcePort, rFailover)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -69,13 +197,13 @@ func (_m *RedisFailoverHeal) NewSentinelMonitorWithPort(ip string, monitor strin
 	return r0
 }
 
-// RestoreSentinel provides a mock function with given fields: ip
-func (_m *RedisFailoverHeal) RestoreSentinel(ip string) error {
-	ret := _m.Called(ip)
+// SetRedisCustomConfig provides a mock function with given fields: ctx, ip, rFailover
+func (_m *RedisFailoverHeal) SetRedisCustomConfig(ctx context.Context, ip string, rFailover *v1.RedisFailover) error {
+	ret := _m.Called(ctx, ip, rFailover)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string) error); ok {
-		r0 = rf(ip)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.RedisFailover) error); ok {
+		r0 = rf(ctx, ip, rFailover)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -83,13 +211,13 @@ func (_m *RedisFailoverHeal) RestoreSentinel(ip string) error {
 	return r0
 }
 
-// SetExternalMasterOnAll provides a mock function with given fields: masterIP, masterPort, rFailover
-func (_m *RedisFailoverHeal) SetExternalMasterOnAll(masterIP string, masterPort string, rFailover *v1.RedisFailover) error {
-	ret := _m.Called(masterIP, masterPort, rFailover)
+// SetRedisPasswords provides a mock function with given fields: ctx, rFailover
+func (_m *RedisFailoverHeal) SetRedisPasswords(ctx context.Context, rFailover *v1.RedisFailover) error {
+	ret := _m.Called(ctx, rFailover)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string, *v1.RedisFailover) error); ok {
-		r0 = rf(masterIP, masterPort, rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.RedisFailover) error); ok {
+		r0 = rf(ctx, rFailover)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -97,13 +225,13 @@ func (_m *RedisFailoverHeal) SetExternalMasterOnAll(masterIP string, masterPort
 	return r0
 }
 
-// SetMasterOnAll provides a mock function with given fields: masterIP, rFailover
-func (_m *RedisFailoverHeal) SetMasterOnAll(masterIP string, rFailover *v1.RedisFailover) error {
-	ret := _m.Called(masterIP, rFailover)
+// SetRedisRoleLabels provides a mock function with given fields: ctx, master, rFailover
+func (_m *RedisFailoverHeal) SetRedisRoleLabels(ctx context.Context, master string, rFailover *v1.RedisFailover) error {
+	ret := _m.Called(ctx, master, rFailover)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, *v1.RedisFailover) error); ok {
-		r0 = rf(masterIP, rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.RedisFailover) error); ok {
+		r0 = rf(ctx, master, rFailover)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -111,13 +239,13 @@ func (_m *RedisFailoverHeal) SetMasterOnAll(masterIP string, rFailover *v1.Redis
 	return r0
 }
 
-// SetOldestAsMaster provides a mock function with given fields: rFailover
-func (_m *RedisFailoverHeal) SetOldestAsMaster(rFailover *v1.RedisFailover) error {
-	ret := _m.Called(rFailover)
+// SetSentinelAnnounce provides a mock function with given fields: ctx, ip, announceIP, announcePort, rFailover
+func (_m *RedisFailoverHeal) SetSentinelAnnounce(ctx context.Context, ip string, announceIP string, announcePort string, rFailover *v1.RedisFailover) error {
+	ret := _m.Called(ctx, ip, announceIP, announcePort, rFailover)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*v1.RedisFailover) error); ok {
-		r0 = rf(rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, *v1.RedisFailover) error); ok {
+		r0 = rf(ctx, ip, announceIP, announcePort, rFailover)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -125,13 +253,13 @@ func (_m *RedisFailoverHeal) SetOldestAsMaster(rFailover *v1.RedisFailover) erro
 	return r0
 }
 
-// SetRedisCustomConfig provides a mock function with given fields: ip, rFailover
-func (_m *RedisFailoverHeal) SetRedisCustomConfig(ip string, rFailover *v1.RedisFailover) error {
-	ret := _m.Called(ip, rFailover)
+// SetSentinelCustomConfig provides a mock function with given fields: ctx, ip, rFailover
+func (_m *RedisFailoverHeal) SetSentinelCustomConfig(ctx context.Context, ip string, rFailover *v1.RedisFailover) error {
+	ret := _m.Called(ctx, ip, rFailover)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, *v1.RedisFailover) error); ok {
-		r0 = rf(ip, rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.RedisFailover) error); ok {
+		r0 = rf(ctx, ip, rFailover)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -139,13 +267,13 @@ func (_m *RedisFailoverHeal) SetRedisCustomConfig(ip string, rFailover *v1.Redis
 	return r0
 }
 
-// SetSentinelCustomConfig provides a mock function with given fields: ip, rFailover
-func (_m *RedisFailoverHeal) SetSentinelCustomConfig(ip string, rFailover *v1.RedisFailover) error {
-	ret := _m.Called(ip, rFailover)
+// SwitchoverMaster provides a mock function with given fields: ctx, sentinel, excluded, rFailover
+func (_m *RedisFailoverHeal) SwitchoverMaster(ctx context.Context, sentinel string, excluded []string, rFailover *v1.RedisFailover) error {
+	ret := _m.Called(ctx, sentinel, excluded, rFailover)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, *v1.RedisFailover) error); ok {
-		r0 = rf(ip, rFailover)
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string, *v1.RedisFailover) error); ok {
+		r0 = rf(ctx, sentinel, excluded, rFailover)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -20,6 +20,12 @@ import (
 	v1 "k8s.io/api/core/v1"
 
 	watch "k8s.io/apimachinery/pkg/watch"
+
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+
+	unstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	networkingv1 "k8s.io/api/networking/v1"
 )
 
 // Services is an autogenerated mock type for the Services type
@@ -27,13 +33,83 @@ type Services struct {
 	mock.Mock
 }
 
-// CreateConfigMap provides a mock function with given fields: namespace, configMap
-func (_m *Services) CreateConfigMap(namespace string, configMap *v1.ConfigMap) error {
-	ret := _m.Called(namespace, configMap)
+// CreateConfigMap provides a mock function with given fields: ctx, namespace, configMap
+func (_m *Services) CreateConfigMap(ctx context.Context, namespace string, configMap *v1.ConfigMap) error {
+	ret := _m.Called(ctx, namespace, configMap)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.ConfigMap) error); ok {
+		r0 = rf(ctx, namespace, configMap)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateDeployment provides a mock function with given fields: ctx, namespace, deployment
+func (_m *Services) CreateDeployment(ctx context.Context, namespace string, deployment *appsv1.Deployment) error {
+	ret := _m.Called(ctx, namespace, deployment)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *appsv1.Deployment) error); ok {
+		r0 = rf(ctx, namespace, deployment)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateEvent provides a mock function with given fields: ctx, namespace, event
+func (_m *Services) CreateEvent(ctx context.Context, namespace string, event *v1.Event) error {
+	ret := _m.Called(ctx, namespace, event)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.Event) error); ok {
+		r0 = rf(ctx, namespace, event)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateIfNotExistsService provides a mock function with given fields: ctx, namespace, service
+func (_m *Services) CreateIfNotExistsService(ctx context.Context, namespace string, service *v1.Service) error {
+	ret := _m.Called(ctx, namespace, service)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.Service) error); ok {
+		r0 = rf(ctx, namespace, service)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateNetworkPolicy provides a mock function with given fields: ctx, namespace, networkPolicy
+func (_m *Services) CreateNetworkPolicy(ctx context.Context, namespace string, networkPolicy *networkingv1.NetworkPolicy) error {
+	ret := _m.Called(ctx, namespace, networkPolicy)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *networkingv1.NetworkPolicy) error); ok {
+		r0 = rf(ctx, namespace, networkPolicy)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateOrUpdateConfigMap provides a mock function with given fields: ctx, namespace, np
+func (_m *Services) CreateOrUpdateConfigMap(ctx context.Context, namespace string, np *v1.ConfigMap) error {
+	ret := _m.Called(ctx, namespace, np)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, *v1.ConfigMap) error); ok {
-		r0 = rf(namespace, configMap)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.ConfigMap) error); ok {
+		r0 = rf(ctx, namespace, np)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -41,13 +117,13 @@ func (_m *Services) CreateConfigMap(namespace string, configMap *v1.ConfigMap) e
 	return r0
 }
 
-// CreateDeployment provides a mock function with given fields: namespace, deployment
-func (_m *Services) CreateDeployment(namespace string, deployment *appsv1.Deployment) error {
-	ret := _m.Called(namespace, deployment)
+// CreateOrUpdateDeployment provides a mock function with given fields: ctx, namespace, deployment
+func (_m *Services) CreateOrUpdateDeployment(ctx context.Context, namespace string, deployment *appsv1.Deployment) error {
+	ret := _m.Called(ctx, namespace, deployment)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, *appsv1.Deployment) error); ok {
-		r0 = rf(namespace, deployment)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *appsv1.Deployment) error); ok {
+		r0 = rf(ctx, namespace, deployment)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -55,13 +131,13 @@ func (_m *Services) CreateDeployment(namespace string, deployment *appsv1.Deploy
 	return r0
 }
 
-// CreateIfNotExistsService provides a mock function with given fields: namespace, service
-func (_m *Services) CreateIfNotExistsService(namespace string, service *v1.Service) error {
-	ret := _m.Called(namespace, service)
+// CreateOrUpdateNetworkPolicy provides a mock function with given fields: ctx, namespace, networkPolicy
+func (_m *Services) CreateOrUpdateNetworkPolicy(ctx context.Context, namespace string, networkPolicy *networkingv1.NetworkPolicy) error {
+	ret := _m.Called(ctx, namespace, networkPolicy)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, *v1.Service) error); ok {
-		r0 = rf(namespace, service)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *networkingv1.NetworkPolicy) error); ok {
+		r0 = rf(ctx, namespace, networkPolicy)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -69,13 +145,13 @@ func (_m *Services) CreateIfNotExistsService(namespace string, service *v1.Servi
 	return r0
 }
 
-// CreateOrUpdateConfigMap provides a mock function with given fields: namespace, np
-func (_m *Services) CreateOrUpdateConfigMap(namespace string, np *v1.ConfigMap) error {
-	ret := _m.Called(namespace, np)
+// CreateOrUpdatePod provides a mock function with given fields: ctx, namespace, pod
+func (_m *Services) CreateOrUpdatePod(ctx context.Context, namespace string, pod *v1.Pod) error {
+	ret := _m.Called(ctx, namespace, pod)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, *v1.ConfigMap) error); ok {
-		r0 = rf(namespace, np)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.Pod) error); ok {
+		r0 = rf(ctx, namespace, pod)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -83,13 +159,13 @@ func (_m *Services) CreateOrUpdateConfigMap(namespace string, np *v1.ConfigMap)
 	return r0
 }
 
-// CreateOrUpdateDeployment provides a mock function with given fields: namespace, deployment
-func (_m *Services) CreateOrUpdateDeployment(namespace string, deployment *appsv1.Deployment) error {
-	ret := _m.Called(namespace, deployment)
+// CreateOrUpdatePodDisruptionBudget provides a mock function with given fields: ctx, namespace, podDisruptionBudget
+func (_m *Services) CreateOrUpdatePodDisruptionBudget(ctx context.Context, namespace string, podDisruptionBudget *policyv1.PodDisruptionBudget) error {
+	ret := _m.Called(ctx, namespace, podDisruptionBudget)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, *appsv1.Deployment) error); ok {
-		r0 = rf(namespace, deployment)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *policyv1.PodDisruptionBudget) error); ok {
+		r0 = rf(ctx, namespace, podDisruptionBudget)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -97,13 +173,13 @@ func (_m *Services) CreateOrUpdateDeployment(namespace string, deployment *appsv
 	return r0
 }
 
-// CreateOrUpdatePod provides a mock function with given fields: namespace, pod
-func (_m *Services) CreateOrUpdatePod(namespace string, pod *v1.Pod) error {
-	ret := _m.Called(namespace, pod)
+// CreateOrUpdateRole provides a mock function with given fields: ctx, namespace, binding
+func (_m *Services) CreateOrUpdateRole(ctx context.Context, namespace string, binding *rbacv1.Role) error {
+	ret := _m.Called(ctx, namespace, binding)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, *v1.Pod) error); ok {
-		r0 = rf(namespace, pod)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *rbacv1.Role) error); ok {
+		r0 = rf(ctx, namespace, binding)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -111,13 +187,13 @@ func (_m *Services) CreateOrUpdatePod(namespace string, pod *v1.Pod) error {
 	return r0
 }
 
-// CreateOrUpdatePodDisruptionBudget provides a mock function with given fields: namespace, podDisruptionBudget
-func (_m *Services) CreateOrUpdatePodDisruptionBudget(namespace string, podDisruptionBudget *policyv1.PodDisruptionBudget) error {
-	ret := _m.Called(namespace, podDisruptionBudget)
+// CreateOrUpdateRoleBinding provides a mock function with given fields: ctx, namespace, binding
+func (_m *Services) CreateOrUpdateRoleBinding(ctx context.Context, namespace string, binding *rbacv1.RoleBinding) error {
+	ret := _m.Called(ctx, namespace, binding)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, *policyv1.PodDisruptionBudget) error); ok {
-		r0 = rf(namespace, podDisruptionBudget)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *rbacv1.RoleBinding) error); ok {
+		r0 = rf(ctx, namespace, binding)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -125,13 +201,13 @@ func (_m *Services) CreateOrUpdatePodDisruptionBudget(namespace string, podDisru
 	return r0
 }
 
-// CreateOrUpdateRole provides a mock function with given fields: namespace, binding
-func (_m *Services) CreateOrUpdateRole(namespace string, binding *rbacv1.Role) error {
-	ret := _m.Called(namespace, binding)
+// CreateOrUpdateService provides a mock function with given fields: ctx, namespace, service
+func (_m *Services) CreateOrUpdateService(ctx context.Context, namespace string, service *v1.Service) error {
+	ret := _m.Called(ctx, namespace, service)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, *rbacv1.Role) error); ok {
-		r0 = rf(namespace, binding)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.Service) error); ok {
+		r0 = rf(ctx, namespace, service)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -139,13 +215,13 @@ func (_m *Services) CreateOrUpdateRole(namespace string, binding *rbacv1.Role) e
 	return r0
 }
 
-// CreateOrUpdateRoleBinding provides a mock function with given fields: namespace, binding
-func (_m *Services) CreateOrUpdateRoleBinding(namespace string, binding *rbacv1.RoleBinding) error {
-	ret := _m.Called(namespace, binding)
+// CreateOrUpdateStatefulSet provides a mock function with given fields: ctx, namespace, statefulSet
+func (_m *Services) CreateOrUpdateStatefulSet(ctx context.Context, namespace string, statefulSet *appsv1.StatefulSet) error {
+	ret := _m.Called(ctx, namespace, statefulSet)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, *rbacv1.RoleBinding) error); ok {
-		r0 = rf(namespace, binding)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *appsv1.StatefulSet) error); ok {
+		r0 = rf(ctx, namespace, statefulSet)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -153,13 +229,13 @@ func (_m *Services) CreateOrUpdateRoleBinding(namespace string, binding *rbacv1.
 	return r0
 }
 
-// CreateOrUpdateService provides a mock function with given fields: namespace, service
-func (_m *Services) CreateOrUpdateService(namespace string, service *v1.Service) error {
-	ret := _m.Called(namespace, service)
+// CreateOrUpdateUnstructured provides a mock function with given fields: ctx, namespace, object
+func (_m *Services) CreateOrUpdateUnstructured(ctx context.Context, namespace string, object *unstructured.Unstructured) error {
+	ret := _m.Called(ctx, namespace, object)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, *v1.Service) error); ok {
-		r0 = rf(namespace, service)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *unstructured.Unstructured) error); ok {
+		r0 = rf(ctx, namespace, object)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -167,13 +243,13 @@ func (_m *Services) CreateOrUpdateService(namespace string, service *v1.Service)
 	return r0
 }
 
-// CreateOrUpdateStatefulSet provides a mock function with given fields: namespace, statefulSet
-func (_m *Services) CreateOrUpdateStatefulSet(namespace string, statefulSet *appsv1.StatefulSet) error {
-	ret := _m.Called(namespace, statefulSet)
+// CreatePod provides a mock function with given fields: ctx, namespace, pod
+func (_m *Services) CreatePod(ctx context.Context, namespace string, pod *v1.Pod) error {
+	ret := _m.Called(ctx, namespace, pod)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, *appsv1.StatefulSet) error); ok {
-		r0 = rf(namespace, statefulSet)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.Pod) error); ok {
+		r0 = rf(ctx, namespace, pod)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -181,13 +257,13 @@ func (_m *Services) CreateOrUpdateStatefulSet(namespace string, statefulSet *app
 	return r0
 }
 
-// CreatePod provides a mock function with given fields: namespace, pod
-func (_m *Services) CreatePod(namespace string, pod *v1.Pod) error {
-	ret := _m.Called(namespace, pod)
+// CreatePodDisruptionBudget provides a mock function with given fields: ctx, namespace, podDisruptionBudget
+func (_m *Services) CreatePodDisruptionBudget(ctx context.Context, namespace string, podDisruptionBudget *policyv1.PodDisruptionBudget) error {
+	ret := _m.Called(ctx, namespace, podDisruptionBudget)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, *v1.Pod) error); ok {
-		r0 = rf(namespace, pod)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *policyv1.PodDisruptionBudget) error); ok {
+		r0 = rf(ctx, namespace, podDisruptionBudget)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -195,13 +271,13 @@ func (_m *Services) CreatePod(namespace string, pod *v1.Pod) error {
 	return r0
 }
 
-// CreatePodDisruptionBudget provides a mock function with given fields: namespace, podDisruptionBudget
-func (_m *Services) CreatePodDisruptionBudget(namespace string, podDisruptionBudget *policyv1.PodDisruptionBudget) error {
-	ret := _m.Called(namespace, podDisruptionBudget)
+// CreateRole provides a mock function with given fields: ctx, namespace, role
+func (_m *Services) CreateRole(ctx context.Context, namespace string, role *rbacv1.Role) error {
+	ret := _m.Called(ctx, namespace, role)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, *policyv1.PodDisruptionBudget) error); ok {
-		r0 = rf(namespace, podDisruptionBudget)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *rbacv1.Role) error); ok {
+		r0 = rf(ctx, namespace, role)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -209,13 +285,13 @@ func (_m *Services) CreatePodDisruptionBudget(namespace string, podDisruptionBud
 	return r0
 }
 
-// CreateRole provides a mock function with given fields: namespace, role
-func (_m *Services) CreateRole(namespace string, role *rbacv1.Role) error {
-	ret := _m.Called(namespace, role)
+// CreateRoleBinding provides a mock function with given fields: ctx, namespace, binding
+func (_m *Services) CreateRoleBinding(ctx context.Context, namespace string, binding *rbacv1.RoleBinding) error {
+	ret := _m.Called(ctx, namespace, binding)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, *rbacv1.Role) error); ok {
-		r0 = rf(namespace, role)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *rbacv1.RoleBinding) error); ok {
+		r0 = rf(ctx, namespace, binding)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -223,13 +299,13 @@ func (_m *Services) CreateRole(namespace string, role *rbacv1.Role) error {
 	return r0
 }
 
-// CreateRoleBinding provides a mock function with given fields: namespace, binding
-func (_m *Services) CreateRoleBinding(namespace string, binding *rbacv1.RoleBinding) error {
-	ret := _m.Called(namespace, binding)
+// CreateService provides a mock function with given fields: ctx, namespace, service
+func (_m *Services) CreateService(ctx context.Context, namespace string, service *v1.Service) error {
+	ret := _m.Called(ctx, namespace, service)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, *rbacv1.RoleBinding) error); ok {
-		r0 = rf(namespace, binding)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.Service) error); ok {
+		r0 = rf(ctx, namespace, service)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -237,13 +313,13 @@ func (_m *Services) CreateRoleBinding(namespace string, binding *rbacv1.RoleBind
 	return r0
 }
 
-// CreateService provides a mock function with given fields: namespace, service
-func (_m *Services) CreateService(namespace string, service *v1.Service) error {
-	ret := _m.Called(namespace, service)
+// CreateStatefulSet provides a mock function with given fields: ctx, namespace, statefulSet
+func (_m *Services) CreateStatefulSet(ctx context.Context, namespace string, statefulSet *appsv1.StatefulSet) error {
+	ret := _m.Called(ctx, namespace, statefulSet)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, *v1.Service) error); ok {
-		r0 = rf(namespace, service)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *appsv1.StatefulSet) error); ok {
+		r0 = rf(ctx, namespace, statefulSet)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -251,13 +327,13 @@ func (_m *Services) CreateService(namespace string, service *v1.Service) error {
 	return r0
 }
 
-// CreateStatefulSet provides a mock function with given fields: namespace, statefulSet
-func (_m *Services) CreateStatefulSet(namespace string, statefulSet *appsv1.StatefulSet) error {
-	ret := _m.Called(namespace, statefulSet)
+// CreateUnstructured provides a mock function with given fields: ctx, namespace, object
+func (_m *Services) CreateUnstructured(ctx context.Context, namespace string, object *unstructured.Unstructured) error {
+	ret := _m.Called(ctx, namespace, object)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, *appsv1.StatefulSet) error); ok {
-		r0 = rf(namespace, statefulSet)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *unstructured.Unstructured) error); ok {
+		r0 = rf(ctx, namespace, object)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -265,13 +341,13 @@ func (_m *Services) CreateStatefulSet(namespace string, statefulSet *appsv1.Stat
 	return r0
 }
 
-// DeleteConfigMap provides a mock function with given fields: namespace, name
-func (_m *Services) DeleteConfigMap(namespace string, name string) error {
-	ret := _m.Called(namespace, name)
+// DeleteConfigMap provides a mock function with given fields: ctx, namespace, name
+func (_m *Services) DeleteConfigMap(ctx context.Context, namespace string, name string) error {
+	ret := _m.Called(ctx, namespace, name)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string) error); ok {
-		r0 = rf(namespace, name)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, namespace, name)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -279,13 +355,13 @@ func (_m *Services) DeleteConfigMap(namespace string, name string) error {
 	return r0
 }
 
-// DeleteDeployment provides a mock function with given fields: namespace, name
-func (_m *Services) DeleteDeployment(namespace string, name string) error {
-	ret := _m.Called(namespace, name)
+// DeleteDeployment provides a mock function with given fields: ctx, namespace, name
+func (_m *Services) DeleteDeployment(ctx context.Context, namespace string, name string) error {
+	ret := _m.Called(ctx, namespace, name)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string) error); ok {
-		r0 = rf(namespace, name)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, namespace, name)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -293,13 +369,13 @@ func (_m *Services) DeleteDeployment(namespace string, name string) error {
 	return r0
 }
 
-// DeletePod provides a mock function with given fields: namespace, name
-func (_m *Services) DeletePod(namespace string, name string) error {
-	ret := _m.Called(namespace, name)
+// DeleteNetworkPolicy provides a mock function with given fields: ctx, namespace, name
+func (_m *Services) DeleteNetworkPolicy(ctx context.Context, namespace string, name string) error {
+	ret := _m.Called(ctx, namespace, name)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string) error); ok {
-		r0 = rf(namespace, name)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, namespace, name)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -307,13 +383,13 @@ func (_m *Services) DeletePod(namespace string, name string) error {
 	return r0
 }
 
-// DeletePodDisruptionBudget provides a mock function with given fields: namespace, name
-func (_m *Services) DeletePodDisruptionBudget(namespace string, name string) error {
-	ret := _m.Called(namespace, name)
+// DeletePod provides a mock function with given fields: ctx, namespace, name
+func (_m *Services) DeletePod(ctx context.Context, namespace string, name string) error {
+	ret := _m.Called(ctx, namespace, name)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string) error); ok {
-		r0 = rf(namespace, name)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, namespace, name)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -321,13 +397,13 @@ func (_m *Services) DeletePodDisruptionBudget(namespace string, name string) err
 	return r0
 }
 
-// DeleteService provides a mock function with given fields: namespace, name
-func (_m *Services) DeleteService(namespace string, name string) error {
-	ret := _m.Called(namespace, name)
+// DeletePodDisruptionBudget provides a mock function with given fields: ctx, namespace, name
+func (_m *Services) DeletePodDisruptionBudget(ctx context.Context, namespace string, name string) error {
+	ret := _m.Called(ctx, namespace, name)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string) error); ok {
-		r0 = rf(namespace, name)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, namespace, name)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -335,13 +411,13 @@ func (_m *Services) DeleteService(namespace string, name string) error {
 	return r0
 }
 
-// DeleteStatefulSet provides a mock function with given fields: namespace, name
-func (_m *Services) DeleteStatefulSet(namespace string, name string) error {
-	ret := _m.Called(namespace, name)
+// DeleteService provides a mock function with given fields: ctx, namespace, name
+func (_m *Services) DeleteService(ctx context.Context, namespace string, name string) error {
+	ret := _m.Called(ctx, namespace, name)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string) error); ok {
-		r0 = rf(namespace, name)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, namespace, name)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -349,13 +425,41 @@ func (_m *Services) DeleteStatefulSet(namespace string, name string) error {
 	return r0
 }
 
-// GetClusterRole provides a mock function with given fields: name
-func (_m *Services) GetClusterRole(name string) (*rbacv1.ClusterRole, error) {
-	ret := _m.Called(name)
+// DeleteStatefulSet provides a mock function with given fields: ctx, namespace, name
+func (_m *Services) DeleteStatefulSet(ctx context.Context, namespace string, name string) error {
+	ret := _m.Called(ctx, namespace, name)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, namespace, name)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteUnstructured provides a mock function with given fields: ctx, gvk, namespace, name
+func (_m *Services) DeleteUnstructured(ctx context.Context, gvk schema.GroupVersionKind, namespace string, name string) error {
+	ret := _m.Called(ctx, gvk, namespace, name)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, schema.GroupVersionKind, string, string) error); ok {
+		r0 = rf(ctx, gvk, namespace, name)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetClusterRole provides a mock function with given fields: ctx, name
+func (_m *Services) GetClusterRole(ctx context.Context, name string) (*rbacv1.ClusterRole, error) {
+	ret := _m.Called(ctx, name)
 
 	var r0 *rbacv1.ClusterRole
-	if rf, ok := ret.Get(0).(func(string) *rbacv1.ClusterRole); ok {
-		r0 = rf(name)
+	if rf, ok := ret.Get(0).(func(context.Context, string) *rbacv1.ClusterRole); ok {
+		r0 = rf(ctx, name)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*rbacv1.ClusterRole)
@@ -363,8 +467,8 @@ func (_m *Services) GetClusterRole(name string) (*rbacv1.ClusterRole, error) {
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(name)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, name)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -372,13 +476,13 @@ func (_m *Services) GetClusterRole(name string) (*rbacv1.ClusterRole, error) {
 	return r0, r1
 }
 
-// GetConfigMap provides a mock function with given fields: namespace, name
-func (_m *Services) GetConfigMap(namespace string, name string) (*v1.ConfigMap, error) {
-	ret := _m.Called(namespace, name)
+// GetConfigMap provides a mock function with given fields: ctx, namespace, name
+func (_m *Services) GetConfigMap(ctx context.Context, namespace string, name string) (*v1.ConfigMap, error) {
+	ret := _m.Called(ctx, namespace, name)
 
 	var r0 *v1.ConfigMap
-	if rf, ok := ret.Get(0).(func(string, string) *v1.ConfigMap); ok {
-		r0 = rf(namespace, name)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *v1.ConfigMap); ok {
+		r0 = rf(ctx, namespace, name)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*v1.ConfigMap)
@@ -386,8 +490,8 @@ func (_m *Services) GetConfigMap(namespace string, name string) (*v1.ConfigMap,
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string, string) error); ok {
-		r1 = rf(namespace, name)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, namespace, name)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -395,13 +499,13 @@ func (_m *Services) GetConfigMap(namespace string, name string) (*v1.ConfigMap,
 	return r0, r1
 }
 
-// GetDeployment provides a mock function with given fields: namespace, name
-func (_m *Services) GetDeployment(namespace string, name string) (*appsv1.Deployment, error) {
-	ret := _m.Called(namespace, name)
+// GetDeployment provides a mock function with given fields: ctx, namespace, name
+func (_m *Services) GetDeployment(ctx context.Context, namespace string, name string) (*appsv1.Deployment, error) {
+	ret := _m.Called(ctx, namespace, name)
 
 	var r0 *appsv1.Deployment
-	if rf, ok := ret.Get(0).(func(string, string) *appsv1.Deployment); ok {
-		r0 = rf(namespace, name)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *appsv1.Deployment); ok {
+		r0 = rf(ctx, namespace, name)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*appsv1.Deployment)
@@ -409,8 +513,8 @@ func (_m *Services) GetDeployment(namespace string, name string) (*appsv1.Deploy
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string, string) error); ok {
-		r1 = rf(namespace, name)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, namespace, name)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -418,13 +522,13 @@ func (_m *Services) GetDeployment(namespace string, name string) (*appsv1.Deploy
 	return r0, r1
 }
 
-// GetDeploymentPods provides a mock function with given fields: namespace, name
-func (_m *Services) GetDeploymentPods(namespace string, name string) (*v1.PodList, error) {
-	ret := _m.Called(namespace, name)
+// GetDeploymentPods provides a mock function with given fields: ctx, namespace, name
+func (_m *Services) GetDeploymentPods(ctx context.Context, namespace string, name string) (*v1.PodList, error) {
+	ret := _m.Called(ctx, namespace, name)
 
 	var r0 *v1.PodList
-	if rf, ok := ret.Get(0).(func(string, string) *v1.PodList); ok {
-		r0 = rf(namespace, name)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *v1.PodList); ok {
+		r0 = rf(ctx, namespace, name)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*v1.PodList)
@@ -432,8 +536,31 @@ func (_m *Services) GetDeploymentPods(namespace string, name string) (*v1.PodLis
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string, string) error); ok {
-		r1 = rf(namespace, name)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, namespace, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetNetworkPolicy provides a mock function with given fields: ctx, namespace, name
+func (_m *Services) GetNetworkPolicy(ctx context.Context, namespace string, name string) (*networkingv1.NetworkPolicy, error) {
+	ret := _m.Called(ctx, namespace, name)
+
+	var r0 *networkingv1.NetworkPolicy
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *networkingv1.NetworkPolicy); ok {
+		r0 = rf(ctx, namespace, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*networkingv1.NetworkPolicy)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, namespace, name)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -441,13 +568,36 @@ func (_m *Services) GetDeploymentPods(namespace string, name string) (*v1.PodLis
 	return r0, r1
 }
 
-// GetPod provides a mock function with given fields: namespace, name
-func (_m *Services) GetPod(namespace string, name string) (*v1.Pod, error) {
-	ret := _m.Called(namespace, name)
+// GetNode provides a mock function with given fields: ctx, name
+func (_m *Services) GetNode(ctx context.Context, name string) (*v1.Node, error) {
+	ret := _m.Called(ctx, name)
+
+	var r0 *v1.Node
+	if rf, ok := ret.Get(0).(func(context.Context, string) *v1.Node); ok {
+		r0 = rf(ctx, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.Node)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPod provides a mock function with given fields: ctx, namespace, name
+func (_m *Services) GetPod(ctx context.Context, namespace string, name string) (*v1.Pod, error) {
+	ret := _m.Called(ctx, namespace, name)
 
 	var r0 *v1.Pod
-	if rf, ok := ret.Get(0).(func(string, string) *v1.Pod); ok {
-		r0 = rf(namespace, name)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *v1.Pod); ok {
+		r0 = rf(ctx, namespace, name)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*v1.Pod)
@@ -455,8 +605,8 @@ func (_m *Services) GetPod(namespace string, name string) (*v1.Pod, error) {
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string, string) error); ok {
-		r1 = rf(namespace, name)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, namespace, name)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -464,13 +614,13 @@ func (_m *Services) GetPod(namespace string, name string) (*v1.Pod, error) {
 	return r0, r1
 }
 
-// GetPodDisruptionBudget provides a mock function with given fields: namespace, name
-func (_m *Services) GetPodDisruptionBudget(namespace string, name string) (*policyv1.PodDisruptionBudget, error) {
-	ret := _m.Called(namespace, name)
+// GetPodDisruptionBudget provides a mock function with given fields: ctx, namespace, name
+func (_m *Services) GetPodDisruptionBudget(ctx context.Context, namespace string, name string) (*policyv1.PodDisruptionBudget, error) {
+	ret := _m.Called(ctx, namespace, name)
 
 	var r0 *policyv1.PodDisruptionBudget
-	if rf, ok := ret.Get(0).(func(string, string) *policyv1.PodDisruptionBudget); ok {
-		r0 = rf(namespace, name)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *policyv1.PodDisruptionBudget); ok {
+		r0 = rf(ctx, namespace, name)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*policyv1.PodDisruptionBudget)
@@ -478,8 +628,8 @@ func (_m *Services) GetPodDisruptionBudget(namespace string, name string) (*poli
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string, string) error); ok {
-		r1 = rf(namespace, name)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, namespace, name)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -487,13 +637,36 @@ func (_m *Services) GetPodDisruptionBudget(namespace string, name string) (*poli
 	return r0, r1
 }
 
-// GetRole provides a mock function with given fields: namespace, name
-func (_m *Services) GetRole(namespace string, name string) (*rbacv1.Role, error) {
-	ret := _m.Called(namespace, name)
+// GetRedisFailover provides a mock function with given fields: ctx, namespace, name
+func (_m *Services) GetRedisFailover(ctx context.Context, namespace string, name string) (*redisfailoverv1.RedisFailover, error) {
+	ret := _m.Called(ctx, namespace, name)
+
+	var r0 *redisfailoverv1.RedisFailover
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *redisfailoverv1.RedisFailover); ok {
+		r0 = rf(ctx, namespace, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*redisfailoverv1.RedisFailover)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, namespace, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRole provides a mock function with given fields: ctx, namespace, name
+func (_m *Services) GetRole(ctx context.Context, namespace string, name string) (*rbacv1.Role, error) {
+	ret := _m.Called(ctx, namespace, name)
 
 	var r0 *rbacv1.Role
-	if rf, ok := ret.Get(0).(func(string, string) *rbacv1.Role); ok {
-		r0 = rf(namespace, name)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *rbacv1.Role); ok {
+		r0 = rf(ctx, namespace, name)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*rbacv1.Role)
@@ -501,8 +674,8 @@ func (_m *Services) GetRole(namespace string, name string) (*rbacv1.Role, error)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string, string) error); ok {
-		r1 = rf(namespace, name)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, namespace, name)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -510,13 +683,13 @@ func (_m *Services) GetRole(namespace string, name string) (*rbacv1.Role, error)
 	return r0, r1
 }
 
-// GetRoleBinding provides a mock function with given fields: namespace, name
-func (_m *Services) GetRoleBinding(namespace string, name string) (*rbacv1.RoleBinding, error) {
-	ret := _m.Called(namespace, name)
+// GetRoleBinding provides a mock function with given fields: ctx, namespace, name
+func (_m *Services) GetRoleBinding(ctx context.Context, namespace string, name string) (*rbacv1.RoleBinding, error) {
+	ret := _m.Called(ctx, namespace, name)
 
 	var r0 *rbacv1.RoleBinding
-	if rf, ok := ret.Get(0).(func(string, string) *rbacv1.RoleBinding); ok {
-		r0 = rf(namespace, name)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *rbacv1.RoleBinding); ok {
+		r0 = rf(ctx, namespace, name)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*rbacv1.RoleBinding)
@@ -524,8 +697,8 @@ func (_m *Services) GetRoleBinding(namespace string, name string) (*rbacv1.RoleB
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string, string) error); ok {
-		r1 = rf(namespace, name)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, namespace, name)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -533,13 +706,13 @@ func (_m *Services) GetRoleBinding(namespace string, name string) (*rbacv1.RoleB
 	return r0, r1
 }
 
-// GetSecret provides a mock function with given fields: namespace, name
-func (_m *Services) GetSecret(namespace string, name string) (*v1.Secret, error) {
-	ret := _m.Called(namespace, name)
+// GetSecret provides a mock function with given fields: ctx, namespace, name
+func (_m *Services) GetSecret(ctx context.Context, namespace string, name string) (*v1.Secret, error) {
+	ret := _m.Called(ctx, namespace, name)
 
 	var r0 *v1.Secret
-	if rf, ok := ret.Get(0).(func(string, string) *v1.Secret); ok {
-		r0 = rf(namespace, name)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *v1.Secret); ok {
+		r0 = rf(ctx, namespace, name)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*v1.Secret)
@@ -547,8 +720,8 @@ func (_m *Services) GetSecret(namespace string, name string) (*v1.Secret, error)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string, string) error); ok {
-		r1 = rf(namespace, name)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, namespace, name)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -556,13 +729,13 @@ func (_m *Services) GetSecret(namespace string, name string) (*v1.Secret, error)
 	return r0, r1
 }
 
-// GetService provides a mock function with given fields: namespace, name
-func (_m *Services) GetService(namespace string, name string) (*v1.Service, error) {
-	ret := _m.Called(namespace, name)
+// GetService provides a mock function with given fields: ctx, namespace, name
+func (_m *Services) GetService(ctx context.Context, namespace string, name string) (*v1.Service, error) {
+	ret := _m.Called(ctx, namespace, name)
 
 	var r0 *v1.Service
-	if rf, ok := ret.Get(0).(func(string, string) *v1.Service); ok {
-		r0 = rf(namespace, name)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *v1.Service); ok {
+		r0 = rf(ctx, namespace, name)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*v1.Service)
@@ -570,8 +743,8 @@ func (_m *Services) GetService(namespace string, name string) (*v1.Service, erro
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string, string) error); ok {
-		r1 = rf(namespace, name)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, namespace, name)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -579,13 +752,13 @@ func (_m *Services) GetService(namespace string, name string) (*v1.Service, erro
 	return r0, r1
 }
 
-// GetStatefulSet provides a mock function with given fields: namespace, name
-func (_m *Services) GetStatefulSet(namespace string, name string) (*appsv1.StatefulSet, error) {
-	ret := _m.Called(namespace, name)
+// GetStatefulSet provides a mock function with given fields: ctx, namespace, name
+func (_m *Services) GetStatefulSet(ctx context.Context, namespace string, name string) (*appsv1.StatefulSet, error) {
+	ret := _m.Called(ctx, namespace, name)
 
 	var r0 *appsv1.StatefulSet
-	if rf, ok := ret.Get(0).(func(string, string) *appsv1.StatefulSet); ok {
-		r0 = rf(namespace, name)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *appsv1.StatefulSet); ok {
+		r0 = rf(ctx, namespace, name)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*appsv1.StatefulSet)
@@ -593,8 +766,8 @@ func (_m *Services) GetStatefulSet(namespace string, name string) (*appsv1.State
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string, string) error); ok {
-		r1 = rf(namespace, name)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, namespace, name)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -602,13 +775,13 @@ func (_m *Services) GetStatefulSet(namespace string, name string) (*appsv1.State
 	return r0, r1
 }
 
-// GetStatefulSetPods provides a mock function with given fields: namespace, name
-func (_m *Services) GetStatefulSetPods(namespace string, name string) (*v1.PodList, error) {
-	ret := _m.Called(namespace, name)
+// GetStatefulSetPods provides a mock function with given fields: ctx, namespace, name
+func (_m *Services) GetStatefulSetPods(ctx context.Context, namespace string, name string) (*v1.PodList, error) {
+	ret := _m.Called(ctx, namespace, name)
 
 	var r0 *v1.PodList
-	if rf, ok := ret.Get(0).(func(string, string) *v1.PodList); ok {
-		r0 = rf(namespace, name)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *v1.PodList); ok {
+		r0 = rf(ctx, namespace, name)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*v1.PodList)
@@ -616,8 +789,31 @@ func (_m *Services) GetStatefulSetPods(namespace string, name string) (*v1.PodLi
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string, string) error); ok {
-		r1 = rf(namespace, name)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, namespace, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUnstructured provides a mock function with given fields: ctx, gvk, namespace, name
+func (_m *Services) GetUnstructured(ctx context.Context, gvk schema.GroupVersionKind, namespace string, name string) (*unstructured.Unstructured, error) {
+	ret := _m.Called(ctx, gvk, namespace, name)
+
+	var r0 *unstructured.Unstructured
+	if rf, ok := ret.Get(0).(func(context.Context, schema.GroupVersionKind, string, string) *unstructured.Unstructured); ok {
+		r0 = rf(ctx, gvk, namespace, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*unstructured.Unstructured)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, schema.GroupVersionKind, string, string) error); ok {
+		r1 = rf(ctx, gvk, namespace, name)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -625,13 +821,13 @@ func (_m *Services) GetStatefulSetPods(namespace string, name string) (*v1.PodLi
 	return r0, r1
 }
 
-// ListConfigMaps provides a mock function with given fields: namespace
-func (_m *Services) ListConfigMaps(namespace string) (*v1.ConfigMapList, error) {
-	ret := _m.Called(namespace)
+// ListConfigMaps provides a mock function with given fields: ctx, namespace
+func (_m *Services) ListConfigMaps(ctx context.Context, namespace string) (*v1.ConfigMapList, error) {
+	ret := _m.Called(ctx, namespace)
 
 	var r0 *v1.ConfigMapList
-	if rf, ok := ret.Get(0).(func(string) *v1.ConfigMapList); ok {
-		r0 = rf(namespace)
+	if rf, ok := ret.Get(0).(func(context.Context, string) *v1.ConfigMapList); ok {
+		r0 = rf(ctx, namespace)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*v1.ConfigMapList)
@@ -639,8 +835,8 @@ func (_m *Services) ListConfigMaps(namespace string) (*v1.ConfigMapList, error)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(namespace)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, namespace)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -648,13 +844,13 @@ func (_m *Services) ListConfigMaps(namespace string) (*v1.ConfigMapList, error)
 	return r0, r1
 }
 
-// ListDeployments provides a mock function with given fields: namespace
-func (_m *Services) ListDeployments(namespace string) (*appsv1.DeploymentList, error) {
-	ret := _m.Called(namespace)
+// ListDeployments provides a mock function with given fields: ctx, namespace
+func (_m *Services) ListDeployments(ctx context.Context, namespace string) (*appsv1.DeploymentList, error) {
+	ret := _m.Called(ctx, namespace)
 
 	var r0 *appsv1.DeploymentList
-	if rf, ok := ret.Get(0).(func(string) *appsv1.DeploymentList); ok {
-		r0 = rf(namespace)
+	if rf, ok := ret.Get(0).(func(context.Context, string) *appsv1.DeploymentList); ok {
+		r0 = rf(ctx, namespace)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*appsv1.DeploymentList)
@@ -662,8 +858,8 @@ func (_m *Services) ListDeployments(namespace string) (*appsv1.DeploymentList, e
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(namespace)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, namespace)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -671,13 +867,13 @@ func (_m *Services) ListDeployments(namespace string) (*appsv1.DeploymentList, e
 	return r0, r1
 }
 
-// ListPods provides a mock function with given fields: namespace
-func (_m *Services) ListPods(namespace string) (*v1.PodList, error) {
-	ret := _m.Called(namespace)
+// ListPods provides a mock function with given fields: ctx, namespace
+func (_m *Services) ListPods(ctx context.Context, namespace string) (*v1.PodList, error) {
+	ret := _m.Called(ctx, namespace)
 
 	var r0 *v1.PodList
-	if rf, ok := ret.Get(0).(func(string) *v1.PodList); ok {
-		r0 = rf(namespace)
+	if rf, ok := ret.Get(0).(func(context.Context, string) *v1.PodList); ok {
+		r0 = rf(ctx, namespace)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*v1.PodList)
@@ -685,8 +881,8 @@ func (_m *Services) ListPods(namespace string) (*v1.PodList, error) {
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(namespace)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, namespace)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -717,13 +913,13 @@ func (_m *Services) ListRedisFailovers(ctx context.Context, namespace string, op
 	return r0, r1
 }
 
-// ListServices provides a mock function with given fields: namespace
-func (_m *Services) ListServices(namespace string) (*v1.ServiceList, error) {
-	ret := _m.Called(namespace)
+// ListServices provides a mock function with given fields: ctx, namespace
+func (_m *Services) ListServices(ctx context.Context, namespace string) (*v1.ServiceList, error) {
+	ret := _m.Called(ctx, namespace)
 
 	var r0 *v1.ServiceList
-	if rf, ok := ret.Get(0).(func(string) *v1.ServiceList); ok {
-		r0 = rf(namespace)
+	if rf, ok := ret.Get(0).(func(context.Context, string) *v1.ServiceList); ok {
+		r0 = rf(ctx, namespace)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*v1.ServiceList)
@@ -731,8 +927,8 @@ func (_m *Services) ListServices(namespace string) (*v1.ServiceList, error) {
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(namespace)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, namespace)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -740,13 +936,13 @@ func (_m *Services) ListServices(namespace string) (*v1.ServiceList, error) {
 	return r0, r1
 }
 
-// ListStatefulSets provides a mock function with given fields: namespace
-func (_m *Services) ListStatefulSets(namespace string) (*appsv1.StatefulSetList, error) {
-	ret := _m.Called(namespace)
+// ListStatefulSets provides a mock function with given fields: ctx, namespace
+func (_m *Services) ListStatefulSets(ctx context.Context, namespace string) (*appsv1.StatefulSetList, error) {
+	ret := _m.Called(ctx, namespace)
 
 	var r0 *appsv1.StatefulSetList
-	if rf, ok := ret.Get(0).(func(string) *appsv1.StatefulSetList); ok {
-		r0 = rf(namespace)
+	if rf, ok := ret.Get(0).(func(context.Context, string) *appsv1.StatefulSetList); ok {
+		r0 = rf(ctx, namespace)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*appsv1.StatefulSetList)
@@ -754,8 +950,8 @@ func (_m *Services) ListStatefulSets(namespace string) (*appsv1.StatefulSetList,
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(namespace)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, namespace)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -763,13 +959,78 @@ func (_m *Services) ListStatefulSets(namespace string) (*appsv1.StatefulSetList,
 	return r0, r1
 }
 
-// UpdateConfigMap provides a mock function with given fields: namespace, configMap
-func (_m *Services) UpdateConfigMap(namespace string, configMap *v1.ConfigMap) error {
-	ret := _m.Called(namespace, configMap)
+// ListUnstructured provides a mock function with given fields: ctx, gvk, namespace, labelSelector
+func (_m *Services) ListUnstructured(ctx context.Context, gvk schema.GroupVersionKind, namespace string, labelSelector string) (*unstructured.UnstructuredList, error) {
+	ret := _m.Called(ctx, gvk, namespace, labelSelector)
+
+	var r0 *unstructured.UnstructuredList
+	if rf, ok := ret.Get(0).(func(context.Context, schema.GroupVersionKind, string, string) *unstructured.UnstructuredList); ok {
+		r0 = rf(ctx, gvk, namespace, labelSelector)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*unstructured.UnstructuredList)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, schema.GroupVersionKind, string, string) error); ok {
+		r1 = rf(ctx, gvk, namespace, labelSelector)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateConfigMap provides a mock function with given fields: ctx, namespace, configMap
+func (_m *Services) UpdateConfigMap(ctx context.Context, namespace string, configMap *v1.ConfigMap) error {
+	ret := _m.Called(ctx, namespace, configMap)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.ConfigMap) error); ok {
+		r0 = rf(ctx, namespace, configMap)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateDeployment provides a mock function with given fields: ctx, namespace, deployment
+func (_m *Services) UpdateDeployment(ctx context.Context, namespace string, deployment *appsv1.Deployment) error {
+	ret := _m.Called(ctx, namespace, deployment)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *appsv1.Deployment) error); ok {
+		r0 = rf(ctx, namespace, deployment)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateNetworkPolicy provides a mock function with given fields: ctx, namespace, networkPolicy
+func (_m *Services) UpdateNetworkPolicy(ctx context.Context, namespace string, networkPolicy *networkingv1.NetworkPolicy) error {
+	ret := _m.Called(ctx, namespace, networkPolicy)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *networkingv1.NetworkPolicy) error); ok {
+		r0 = rf(ctx, namespace, networkPolicy)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdatePod provides a mock function with given fields: ctx, namespace, pod
+func (_m *Services) UpdatePod(ctx context.Context, namespace string, pod *v1.Pod) error {
+	ret := _m.Called(ctx, namespace, pod)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, *v1.ConfigMap) error); ok {
-		r0 = rf(namespace, configMap)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.Pod) error); ok {
+		r0 = rf(ctx, namespace, pod)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -777,13 +1038,13 @@ func (_m *Services) UpdateConfigMap(namespace string, configMap *v1.ConfigMap) e
 	return r0
 }
 
-// UpdateDeployment provides a mock function with given fields: namespace, deployment
-func (_m *Services) UpdateDeployment(namespace string, deployment *appsv1.Deployment) error {
-	ret := _m.Called(namespace, deployment)
+// UpdatePodDisruptionBudget provides a mock function with given fields: ctx, namespace, podDisruptionBudget
+func (_m *Services) UpdatePodDisruptionBudget(ctx context.Context, namespace string, podDisruptionBudget *policyv1.PodDisruptionBudget) error {
+	ret := _m.Called(ctx, namespace, podDisruptionBudget)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, *appsv1.Deployment) error); ok {
-		r0 = rf(namespace, deployment)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *policyv1.PodDisruptionBudget) error); ok {
+		r0 = rf(ctx, namespace, podDisruptionBudget)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -791,13 +1052,13 @@ func (_m *Services) UpdateDeployment(namespace string, deployment *appsv1.Deploy
 	return r0
 }
 
-// UpdatePod provides a mock function with given fields: namespace, pod
-func (_m *Services) UpdatePod(namespace string, pod *v1.Pod) error {
-	ret := _m.Called(namespace, pod)
+// UpdatePodLabels provides a mock function with given fields: ctx, namespace, podName, labels
+func (_m *Services) UpdatePodLabels(ctx context.Context, namespace string, podName string, labels map[string]string) error {
+	ret := _m.Called(ctx, namespace, podName, labels)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, *v1.Pod) error); ok {
-		r0 = rf(namespace, pod)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, map[string]string) error); ok {
+		r0 = rf(ctx, namespace, podName, labels)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -805,13 +1066,13 @@ func (_m *Services) UpdatePod(namespace string, pod *v1.Pod) error {
 	return r0
 }
 
-// UpdatePodDisruptionBudget provides a mock function with given fields: namespace, podDisruptionBudget
-func (_m *Services) UpdatePodDisruptionBudget(namespace string, podDisruptionBudget *policyv1.PodDisruptionBudget) error {
-	ret := _m.Called(namespace, podDisruptionBudget)
+// UpdateRedisFailoverStatus provides a mock function with given fields: ctx, rFailover
+func (_m *Services) UpdateRedisFailoverStatus(ctx context.Context, rFailover *redisfailoverv1.RedisFailover) error {
+	ret := _m.Called(ctx, rFailover)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, *policyv1.PodDisruptionBudget) error); ok {
-		r0 = rf(namespace, podDisruptionBudget)
+	if rf, ok := ret.Get(0).(func(context.Context, *redisfailoverv1.RedisFailover) error); ok {
+		r0 = rf(ctx, rFailover)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -819,13 +1080,13 @@ func (_m *Services) UpdatePodDisruptionBudget(namespace string, podDisruptionBud
 	return r0
 }
 
-// UpdatePodLabels provides a mock function with given fields: namespace, podName, labels
-func (_m *Services) UpdatePodLabels(namespace string, podName string, labels map[string]string) error {
-	ret := _m.Called(namespace, podName, labels)
+// UpdateRole provides a mock function with given fields: ctx, namespace, role
+func (_m *Services) UpdateRole(ctx context.Context, namespace string, role *rbacv1.Role) error {
+	ret := _m.Called(ctx, namespace, role)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string, map[string]string) error); ok {
-		r0 = rf(namespace, podName, labels)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *rbacv1.Role) error); ok {
+		r0 = rf(ctx, namespace, role)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -833,13 +1094,13 @@ func (_m *Services) UpdatePodLabels(namespace string, podName string, labels map
 	return r0
 }
 
-// UpdateRole provides a mock function with given fields: namespace, role
-func (_m *Services) UpdateRole(namespace string, role *rbacv1.Role) error {
-	ret := _m.Called(namespace, role)
+// UpdateRoleBinding provides a mock function with given fields: ctx, namespace, binding
+func (_m *Services) UpdateRoleBinding(ctx context.Context, namespace string, binding *rbacv1.RoleBinding) error {
+	ret := _m.Called(ctx, namespace, binding)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, *rbacv1.Role) error); ok {
-		r0 = rf(namespace, role)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *rbacv1.RoleBinding) error); ok {
+		r0 = rf(ctx, namespace, binding)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -847,13 +1108,13 @@ func (_m *Services) UpdateRole(namespace string, role *rbacv1.Role) error {
 	return r0
 }
 
-// UpdateRoleBinding provides a mock function with given fields: namespace, binding
-func (_m *Services) UpdateRoleBinding(namespace string, binding *rbacv1.RoleBinding) error {
-	ret := _m.Called(namespace, binding)
+// UpdateService provides a mock function with given fields: ctx, namespace, service
+func (_m *Services) UpdateService(ctx context.Context, namespace string, service *v1.Service) error {
+	ret := _m.Called(ctx, namespace, service)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, *rbacv1.RoleBinding) error); ok {
-		r0 = rf(namespace, binding)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *v1.Service) error); ok {
+		r0 = rf(ctx, namespace, service)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -861,13 +1122,13 @@ func (_m *Services) UpdateRoleBinding(namespace string, binding *rbacv1.RoleBind
 	return r0
 }
 
-// UpdateService provides a mock function with given fields: namespace, service
-func (_m *Services) UpdateService(namespace string, service *v1.Service) error {
-	ret := _m.Called(namespace, service)
+// UpdateStatefulSet provides a mock function with given fields: ctx, namespace, statefulSet
+func (_m *Services) UpdateStatefulSet(ctx context.Context, namespace string, statefulSet *appsv1.StatefulSet) error {
+	ret := _m.Called(ctx, namespace, statefulSet)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, *v1.Service) error); ok {
-		r0 = rf(namespace, service)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *appsv1.StatefulSet) error); ok {
+		r0 = rf(ctx, namespace, statefulSet)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -875,13 +1136,13 @@ func (_m *Services) UpdateService(namespace string, service *v1.Service) error {
 	return r0
 }
 
-// UpdateStatefulSet provides a mock function with given fields: namespace, statefulSet
-func (_m *Services) UpdateStatefulSet(namespace string, statefulSet *appsv1.StatefulSet) error {
-	ret := _m.Called(namespace, statefulSet)
+// UpdateUnstructured provides a mock function with given fields: ctx, namespace, object
+func (_m *Services) UpdateUnstructured(ctx context.Context, namespace string, object *unstructured.Unstructured) error {
+	ret := _m.Called(ctx, namespace, object)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, *appsv1.StatefulSet) error); ok {
-		r0 = rf(namespace, statefulSet)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *unstructured.Unstructured) error); ok {
+		r0 = rf(ctx, namespace, object)
 	} else {
 		r0 = ret.Error(0)
 	}
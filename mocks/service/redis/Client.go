@@ -2,27 +2,77 @@
 
 package mocks
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	mock "github.com/stretchr/testify/mock"
+
+	tls "crypto/tls"
+
+	context "context"
+
+	redis "github.com/spotahome/redis-operator/service/redis"
+)
 
 // Client is an autogenerated mock type for the Client type
 type Client struct {
 	mock.Mock
 }
 
-// GetNumberSentinelSlavesInMemory provides a mock function with given fields: ip
-func (_m *Client) GetNumberSentinelSlavesInMemory(ip string) (int32, error) {
-	ret := _m.Called(ip)
+// DisableReplicationTLS provides a mock function with given fields: ctx, ip, port, password
+func (_m *Client) DisableReplicationTLS(ctx context.Context, ip string, port string, password string) error {
+	ret := _m.Called(ctx, ip, port, password)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) error); ok {
+		r0 = rf(ctx, ip, port, password)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetMasterAddrByName provides a mock function with given fields: ctx, sentinel, masterName, password, tlsConfig
+func (_m *Client) GetMasterAddrByName(ctx context.Context, sentinel string, masterName string, password string, tlsConfig *tls.Config) (string, string, error) {
+	ret := _m.Called(ctx, sentinel, masterName, password, tlsConfig)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, *tls.Config) string); ok {
+		r0 = rf(ctx, sentinel, masterName, password, tlsConfig)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 string
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, *tls.Config) string); ok {
+		r1 = rf(ctx, sentinel, masterName, password, tlsConfig)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, string, string, string, *tls.Config) error); ok {
+		r2 = rf(ctx, sentinel, masterName, password, tlsConfig)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetNumberSentinelSlavesInMemory provides a mock function with given fields: ctx, ip, port, password
+func (_m *Client) GetNumberSentinelSlavesInMemory(ctx context.Context, ip string, port string, password string) (int32, error) {
+	ret := _m.Called(ctx, ip, port, password)
 
 	var r0 int32
-	if rf, ok := ret.Get(0).(func(string) int32); ok {
-		r0 = rf(ip)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) int32); ok {
+		r0 = rf(ctx, ip, port, password)
 	} else {
 		r0 = ret.Get(0).(int32)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(ip)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, ip, port, password)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -30,20 +80,106 @@ func (_m *Client) GetNumberSentinelSlavesInMemory(ip string) (int32, error) {
 	return r0, r1
 }
 
-// GetNumberSentinelsInMemory provides a mock function with given fields: ip
-func (_m *Client) GetNumberSentinelsInMemory(ip string) (int32, error) {
-	ret := _m.Called(ip)
+// GetNumberSentinelsInMemory provides a mock function with given fields: ctx, ip, port, password
+func (_m *Client) GetNumberSentinelsInMemory(ctx context.Context, ip string, port string, password string) (int32, error) {
+	ret := _m.Called(ctx, ip, port, password)
 
 	var r0 int32
-	if rf, ok := ret.Get(0).(func(string) int32); ok {
-		r0 = rf(ip)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) int32); ok {
+		r0 = rf(ctx, ip, port, password)
 	} else {
 		r0 = ret.Get(0).(int32)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(ip)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, ip, port, password)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRedisConfig provides a mock function with given fields: ctx, ip, port, parameters, password
+func (_m *Client) GetRedisConfig(ctx context.Context, ip string, port string, parameters []string, password string) (map[string]string, error) {
+	ret := _m.Called(ctx, ip, port, parameters, password)
+
+	var r0 map[string]string
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, []string, string) map[string]string); ok {
+		r0 = rf(ctx, ip, port, parameters, password)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]string)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, []string, string) error); ok {
+		r1 = rf(ctx, ip, port, parameters, password)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetReplicationInfo provides a mock function with given fields: ctx, ip, port, password
+func (_m *Client) GetReplicationInfo(ctx context.Context, ip string, port string, password string) (redis.ReplicationInfo, error) {
+	ret := _m.Called(ctx, ip, port, password)
+
+	var r0 redis.ReplicationInfo
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) redis.ReplicationInfo); ok {
+		r0 = rf(ctx, ip, port, password)
+	} else {
+		r0 = ret.Get(0).(redis.ReplicationInfo)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, ip, port, password)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetReplicationOffset provides a mock function with given fields: ctx, ip, port, password
+func (_m *Client) GetReplicationOffset(ctx context.Context, ip string, port string, password string) (int64, error) {
+	ret := _m.Called(ctx, ip, port, password)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) int64); ok {
+		r0 = rf(ctx, ip, port, password)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, ip, port, password)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSentinelMasterInfo provides a mock function with given fields: ctx, ip, port, masterName, password
+func (_m *Client) GetSentinelMasterInfo(ctx context.Context, ip string, port string, masterName string, password string) (redis.SentinelMasterInfo, error) {
+	ret := _m.Called(ctx, ip, port, masterName, password)
+
+	var r0 redis.SentinelMasterInfo
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string) redis.SentinelMasterInfo); ok {
+		r0 = rf(ctx, ip, port, masterName, password)
+	} else {
+		r0 = ret.Get(0).(redis.SentinelMasterInfo)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, string) error); ok {
+		r1 = rf(ctx, ip, port, masterName, password)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -51,27 +187,27 @@ func (_m *Client) GetNumberSentinelsInMemory(ip string) (int32, error) {
 	return r0, r1
 }
 
-// GetSentinelMonitor provides a mock function with given fields: ip
-func (_m *Client) GetSentinelMonitor(ip string) (string, string, error) {
-	ret := _m.Called(ip)
+// GetSentinelMonitor provides a mock function with given fields: ctx, ip, port, masterName, password
+func (_m *Client) GetSentinelMonitor(ctx context.Context, ip string, port string, masterName string, password string) (string, string, error) {
+	ret := _m.Called(ctx, ip, port, masterName, password)
 
 	var r0 string
-	if rf, ok := ret.Get(0).(func(string) string); ok {
-		r0 = rf(ip)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string) string); ok {
+		r0 = rf(ctx, ip, port, masterName, password)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
 	var r1 string
-	if rf, ok := ret.Get(1).(func(string) string); ok {
-		r1 = rf(ip)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, string) string); ok {
+		r1 = rf(ctx, ip, port, masterName, password)
 	} else {
 		r1 = ret.Get(1).(string)
 	}
 
 	var r2 error
-	if rf, ok := ret.Get(2).(func(string) error); ok {
-		r2 = rf(ip)
+	if rf, ok := ret.Get(2).(func(context.Context, string, string, string, string) error); ok {
+		r2 = rf(ctx, ip, port, masterName, password)
 	} else {
 		r2 = ret.Error(2)
 	}
@@ -79,20 +215,43 @@ func (_m *Client) GetSentinelMonitor(ip string) (string, string, error) {
 	return r0, r1, r2
 }
 
-// GetSlaveOf provides a mock function with given fields: ip, port, password
-func (_m *Client) GetSlaveOf(ip string, port string, password string) (string, error) {
-	ret := _m.Called(ip, port, password)
+// GetSentinelReplicas provides a mock function with given fields: ctx, ip, port, masterName, password
+func (_m *Client) GetSentinelReplicas(ctx context.Context, ip string, port string, masterName string, password string) ([]redis.SentinelReplica, error) {
+	ret := _m.Called(ctx, ip, port, masterName, password)
+
+	var r0 []redis.SentinelReplica
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string) []redis.SentinelReplica); ok {
+		r0 = rf(ctx, ip, port, masterName, password)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]redis.SentinelReplica)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, string) error); ok {
+		r1 = rf(ctx, ip, port, masterName, password)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSlaveOf provides a mock function with given fields: ctx, ip, port, password
+func (_m *Client) GetSlaveOf(ctx context.Context, ip string, port string, password string) (string, error) {
+	ret := _m.Called(ctx, ip, port, password)
 
 	var r0 string
-	if rf, ok := ret.Get(0).(func(string, string, string) string); ok {
-		r0 = rf(ip, port, password)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) string); ok {
+		r0 = rf(ctx, ip, port, password)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string, string, string) error); ok {
-		r1 = rf(ip, port, password)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, ip, port, password)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -100,20 +259,20 @@ func (_m *Client) GetSlaveOf(ip string, port string, password string) (string, e
 	return r0, r1
 }
 
-// IsMaster provides a mock function with given fields: ip, port, password
-func (_m *Client) IsMaster(ip string, port string, password string) (bool, error) {
-	ret := _m.Called(ip, port, password)
+// IsMaster provides a mock function with given fields: ctx, ip, port, password
+func (_m *Client) IsMaster(ctx context.Context, ip string, port string, password string) (bool, error) {
+	ret := _m.Called(ctx, ip, port, password)
 
 	var r0 bool
-	if rf, ok := ret.Get(0).(func(string, string, string) bool); ok {
-		r0 = rf(ip, port, password)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) bool); ok {
+		r0 = rf(ctx, ip, port, password)
 	} else {
 		r0 = ret.Get(0).(bool)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string, string, string) error); ok {
-		r1 = rf(ip, port, password)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, ip, port, password)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -121,13 +280,27 @@ func (_m *Client) IsMaster(ip string, port string, password string) (bool, error
 	return r0, r1
 }
 
-// MakeMaster provides a mock function with given fields: ip, port, password
-func (_m *Client) MakeMaster(ip string, port string, password string) error {
-	ret := _m.Called(ip, port, password)
+// MakeMaster provides a mock function with given fields: ctx, ip, port, password
+func (_m *Client) MakeMaster(ctx context.Context, ip string, port string, password string) error {
+	ret := _m.Called(ctx, ip, port, password)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) error); ok {
+		r0 = rf(ctx, ip, port, password)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MakeSlaveOfWithPort provides a mock function with given fields: ctx, ip, masterIP, masterPort, password
+func (_m *Client) MakeSlaveOfWithPort(ctx context.Context, ip string, masterIP string, masterPort string, password string) error {
+	ret := _m.Called(ctx, ip, masterIP, masterPort, password)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string, string) error); ok {
-		r0 = rf(ip, port, password)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string) error); ok {
+		r0 = rf(ctx, ip, masterIP, masterPort, password)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -135,13 +308,13 @@ func (_m *Client) MakeMaster(ip string, port string, password string) error {
 	return r0
 }
 
-// MakeSlaveOf provides a mock function with given fields: ip, masterIP, password
-func (_m *Client) MakeSlaveOf(ip string, masterIP string, password string) error {
-	ret := _m.Called(ip, masterIP, password)
+// MonitorRedisWithPort provides a mock function with given fields: ctx, ip, port, masterName, monitor, password
+func (_m *Client) MonitorRedisWithPort(ctx context.Context, ip string, port string, masterName string, monitor redis.MonitorOptions, password string) error {
+	ret := _m.Called(ctx, ip, port, masterName, monitor, password)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string, string) error); ok {
-		r0 = rf(ip, masterIP, password)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, redis.MonitorOptions, string) error); ok {
+		r0 = rf(ctx, ip, port, masterName, monitor, password)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -149,13 +322,13 @@ func (_m *Client) MakeSlaveOf(ip string, masterIP string, password string) error
 	return r0
 }
 
-// MakeSlaveOfWithPort provides a mock function with given fields: ip, masterIP, masterPort, password
-func (_m *Client) MakeSlaveOfWithPort(ip string, masterIP string, masterPort string, password string) error {
-	ret := _m.Called(ip, masterIP, masterPort, password)
+// ResetSentinel provides a mock function with given fields: ctx, ip, port, password
+func (_m *Client) ResetSentinel(ctx context.Context, ip string, port string, password string) error {
+	ret := _m.Called(ctx, ip, port, password)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string, string, string) error); ok {
-		r0 = rf(ip, masterIP, masterPort, password)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) error); ok {
+		r0 = rf(ctx, ip, port, password)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -163,13 +336,13 @@ func (_m *Client) MakeSlaveOfWithPort(ip string, masterIP string, masterPort str
 	return r0
 }
 
-// MonitorRedis provides a mock function with given fields: ip, monitor, quorum, password
-func (_m *Client) MonitorRedis(ip string, monitor string, quorum string, password string) error {
-	ret := _m.Called(ip, monitor, quorum, password)
+// SentinelCheckQuorum provides a mock function with given fields: ctx, ip, port, masterName, password
+func (_m *Client) SentinelCheckQuorum(ctx context.Context, ip string, port string, masterName string, password string) error {
+	ret := _m.Called(ctx, ip, port, masterName, password)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string, string, string) error); ok {
-		r0 = rf(ip, monitor, quorum, password)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string) error); ok {
+		r0 = rf(ctx, ip, port, masterName, password)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -177,13 +350,13 @@ func (_m *Client) MonitorRedis(ip string, monitor string, quorum string, passwor
 	return r0
 }
 
-// MonitorRedisWithPort provides a mock function with given fields: ip, monitor, port, quorum, password
-func (_m *Client) MonitorRedisWithPort(ip string, monitor string, port string, quorum string, password string) error {
-	ret := _m.Called(ip, monitor, port, quorum, password)
+// SentinelFailover provides a mock function with given fields: ctx, ip, port, masterName, password
+func (_m *Client) SentinelFailover(ctx context.Context, ip string, port string, masterName string, password string) error {
+	ret := _m.Called(ctx, ip, port, masterName, password)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string, string, string, string) error); ok {
-		r0 = rf(ip, monitor, port, quorum, password)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string) error); ok {
+		r0 = rf(ctx, ip, port, masterName, password)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -191,13 +364,13 @@ func (_m *Client) MonitorRedisWithPort(ip string, monitor string, port string, q
 	return r0
 }
 
-// ResetSentinel provides a mock function with given fields: ip
-func (_m *Client) ResetSentinel(ip string) error {
-	ret := _m.Called(ip)
+// SetCustomRedisConfig provides a mock function with given fields: ctx, ip, port, configs, password
+func (_m *Client) SetCustomRedisConfig(ctx context.Context, ip string, port string, configs []string, password string) error {
+	ret := _m.Called(ctx, ip, port, configs, password)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string) error); ok {
-		r0 = rf(ip)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, []string, string) error); ok {
+		r0 = rf(ctx, ip, port, configs, password)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -205,13 +378,13 @@ func (_m *Client) ResetSentinel(ip string) error {
 	return r0
 }
 
-// SentinelCheckQuorum provides a mock function with given fields: ip
-func (_m *Client) SentinelCheckQuorum(ip string) error {
-	ret := _m.Called(ip)
+// SetCustomSentinelConfig provides a mock function with given fields: ctx, ip, port, masterName, configs, password
+func (_m *Client) SetCustomSentinelConfig(ctx context.Context, ip string, port string, masterName string, configs []string, password string) error {
+	ret := _m.Called(ctx, ip, port, masterName, configs, password)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string) error); ok {
-		r0 = rf(ip)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, []string, string) error); ok {
+		r0 = rf(ctx, ip, port, masterName, configs, password)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -219,13 +392,13 @@ func (_m *Client) SentinelCheckQuorum(ip string) error {
 	return r0
 }
 
-// SetCustomRedisConfig provides a mock function with given fields: ip, port, configs, password
-func (_m *Client) SetCustomRedisConfig(ip string, port string, configs []string, password string) error {
-	ret := _m.Called(ip, port, configs, password)
+// SetDefaultUserPasswords provides a mock function with given fields: ctx, ip, port, passwords, password
+func (_m *Client) SetDefaultUserPasswords(ctx context.Context, ip string, port string, passwords []string, password string) error {
+	ret := _m.Called(ctx, ip, port, passwords, password)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string, []string, string) error); ok {
-		r0 = rf(ip, port, configs, password)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, []string, string) error); ok {
+		r0 = rf(ctx, ip, port, passwords, password)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -233,13 +406,13 @@ func (_m *Client) SetCustomRedisConfig(ip string, port string, configs []string,
 	return r0
 }
 
-// SetCustomSentinelConfig provides a mock function with given fields: ip, configs
-func (_m *Client) SetCustomSentinelConfig(ip string, configs []string) error {
-	ret := _m.Called(ip, configs)
+// SetSentinelAnnounce provides a mock function with given fields: ctx, ip, port, announceIP, announcePort, password
+func (_m *Client) SetSentinelAnnounce(ctx context.Context, ip string, port string, announceIP string, announcePort string, password string) error {
+	ret := _m.Called(ctx, ip, port, announceIP, announcePort, password)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, []string) error); ok {
-		r0 = rf(ip, configs)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string, string) error); ok {
+		r0 = rf(ctx, ip, port, announceIP, announcePort, password)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -247,20 +420,43 @@ func (_m *Client) SetCustomSentinelConfig(ip string, configs []string) error {
 	return r0
 }
 
-// SlaveIsReady provides a mock function with given fields: ip, port, password
-func (_m *Client) SlaveIsReady(ip string, port string, password string) (bool, error) {
-	ret := _m.Called(ip, port, password)
+// SlaveIsReady provides a mock function with given fields: ctx, ip, port, password
+func (_m *Client) SlaveIsReady(ctx context.Context, ip string, port string, password string) (bool, error) {
+	ret := _m.Called(ctx, ip, port, password)
 
 	var r0 bool
-	if rf, ok := ret.Get(0).(func(string, string, string) bool); ok {
-		r0 = rf(ip, port, password)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) bool); ok {
+		r0 = rf(ctx, ip, port, password)
 	} else {
 		r0 = ret.Get(0).(bool)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string, string, string) error); ok {
-		r1 = rf(ip, port, password)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, ip, port, password)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SubscribeSentinel provides a mock function with given fields: ctx, ip, port, password, patterns
+func (_m *Client) SubscribeSentinel(ctx context.Context, ip string, port string, password string, patterns []string) (<-chan redis.SentinelEvent, error) {
+	ret := _m.Called(ctx, ip, port, password, patterns)
+
+	var r0 <-chan redis.SentinelEvent
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, []string) <-chan redis.SentinelEvent); ok {
+		r0 = rf(ctx, ip, port, password, patterns)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan redis.SentinelEvent)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, []string) error); ok {
+		r1 = rf(ctx, ip, port, password, patterns)
 	} else {
 		r1 = ret.Error(1)
 	}
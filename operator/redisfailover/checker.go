@@ -1,29 +1,35 @@
 package redisfailover
 
 import (
+	"context"
 	"errors"
+	"net"
 	"strconv"
 	"time"
 
 	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
 	"github.com/spotahome/redis-operator/metrics"
+	rfservice "github.com/spotahome/redis-operator/operator/redisfailover/service"
 )
 
 // UpdateRedisesPods if the running version of pods are equal to the statefulset one
-func (r *RedisFailoverHandler) UpdateRedisesPods(rf *redisfailoverv1.RedisFailover) error {
-	redises, err := r.rfChecker.GetRedisesIPs(rf)
+func (r *RedisFailoverHandler) UpdateRedisesPods(ctx context.Context, rf *redisfailoverv1.RedisFailover) error {
+	redises, err := r.rfChecker.GetRedisesIPs(ctx, rf)
 	if err != nil {
 		return err
 	}
 
+	// Bootstrapping and replicating failovers have no master of their own
+	localMaster := !rf.Bootstrapping() && !rf.Replicating()
+
 	masterIP := ""
-	if !rf.Bootstrapping() {
-		masterIP, _ = r.rfChecker.GetMasterIP(rf)
+	if localMaster {
+		masterIP, _ = r.rfChecker.GetMasterIP(ctx, rf)
 	}
 	// No perform updates when nodes are syncing, still not connected, etc.
 	for _, rip := range redises {
 		if rip != masterIP {
-			ready, err := r.rfChecker.CheckRedisSlavesReady(rip, rf)
+			ready, err := r.rfChecker.CheckRedisSlavesReady(ctx, rip, rf)
 			if err != nil {
 				return err
 			}
@@ -33,25 +39,25 @@ func (r *RedisFailoverHandler) UpdateRedisesPods(rf *redisfailoverv1.RedisFailov
 		}
 	}
 
-	ssUR, err := r.rfChecker.GetStatefulSetUpdateRevision(rf)
+	ssURs, err := r.rfChecker.GetStatefulSetUpdateRevisions(ctx, rf)
 	if err != nil {
 		return err
 	}
 
-	redisesPods, err := r.rfChecker.GetRedisesSlavesPods(rf)
+	redisesPods, err := r.rfChecker.GetRedisesSlavesPods(ctx, rf)
 	if err != nil {
 		return err
 	}
 
 	// Update stale pods with slave role
 	for _, pod := range redisesPods {
-		revision, err := r.rfChecker.GetRedisRevisionHash(pod, rf)
+		revision, err := r.rfChecker.GetRedisRevisionHash(ctx, pod, rf)
 		if err != nil {
 			return err
 		}
-		if revision != ssUR {
+		if revision != ssURs[rfservice.GetPodStatefulSetName(pod)] {
 			//Delete pod and wait next round to check if the new one is synced
-			err = r.rfHealer.DeletePod(pod, rf)
+			err = r.rfHealer.DeletePod(ctx, pod, rf)
 			if err != nil {
 				return err
 			}
@@ -59,19 +65,19 @@ func (r *RedisFailoverHandler) UpdateRedisesPods(rf *redisfailoverv1.RedisFailov
 		}
 	}
 
-	if !rf.Bootstrapping() {
+	if localMaster {
 		// Update stale pod with role master
-		master, err := r.rfChecker.GetRedisesMasterPod(rf)
+		master, err := r.rfChecker.GetRedisesMasterPod(ctx, rf)
 		if err != nil {
 			return err
 		}
 
-		masterRevision, err := r.rfChecker.GetRedisRevisionHash(master, rf)
+		masterRevision, err := r.rfChecker.GetRedisRevisionHash(ctx, master, rf)
 		if err != nil {
 			return err
 		}
-		if masterRevision != ssUR {
-			err = r.rfHealer.DeletePod(master, rf)
+		if masterRevision != ssURs[rfservice.GetPodStatefulSetName(master)] {
+			err = r.rfHealer.DeletePod(ctx, master, rf)
 			if err != nil {
 				return err
 			}
@@ -84,9 +90,29 @@ func (r *RedisFailoverHandler) UpdateRedisesPods(rf *redisfailoverv1.RedisFailov
 
 // CheckAndHeal runs verifcation checks to ensure the RedisFailover is in an expected and healthy state.
 // If the checks do not match up to expectations, an attempt will be made to "heal" the RedisFailover into a healthy state.
-func (r *RedisFailoverHandler) CheckAndHeal(rf *redisfailoverv1.RedisFailover) error {
+func (r *RedisFailoverHandler) CheckAndHeal(ctx context.Context, rf *redisfailoverv1.RedisFailover) error {
+	// The operator authenticates with the new password from now on, make the redises accept it before anything else
+	if err := r.phase(ctx, rf, "CheckAndHealPasswordRotation", func(ctx context.Context) error { return r.checkAndHealPasswordRotation(ctx, rf) }); err != nil {
+		return err
+	}
+
 	if rf.Bootstrapping() {
-		return r.checkAndHealBootstrapMode(rf)
+		return r.phase(ctx, rf, "CheckAndHealBootstrapMode", func(ctx context.Context) error { return r.checkAndHealBootstrapMode(ctx, rf) })
+	}
+	if rf.Replicating() {
+		return r.phase(ctx, rf, "CheckAndHealReplicaMode", func(ctx context.Context) error { return r.checkAndHealReplicaMode(ctx, rf) })
+	}
+
+	// The master and its replicas are left to sentinel while it switches them, their replica-priority included
+	if r.switchovers.isRunning(rf.Namespace, rf.Name) || rfservice.SwitchoverInFlight(rf) {
+		rfservice.Logger(ctx, r.logger, rf).Debugf("Switching the master over, leaving it to sentinel")
+		return nil
+	}
+	if rf.Status.Switchover != nil {
+		// left by an operator stopped in the middle of a switchover
+		if err := r.phase(ctx, rf, "EndSwitchover", func(ctx context.Context) error { return r.rfHealer.EndSwitchover(ctx, rf) }); err != nil {
+			return err
+		}
 	}
 
 	// Number of redis is equal as the set on the RF spec
@@ -98,232 +124,479 @@ func (r *RedisFailoverHandler) CheckAndHeal(rf *redisfailoverv1.RedisFailover) e
 	// Sentinel has not death nodes
 	// Sentinel knows the correct slave number
 
-	if !r.rfChecker.IsRedisRunning(rf) {
+	var master string
+	err := r.phase(ctx, rf, "CheckAndHealMaster", func(ctx context.Context) (err error) {
+		master, err = r.checkAndHealMaster(ctx, rf)
+		return err
+	})
+	if err != nil || master == "" {
+		return err
+	}
+
+	// Relabel before anything else, the master and replicas services follow the labels
+	if err := r.phase(ctx, rf, "SetRedisRoleLabels", func(ctx context.Context) error { return r.rfHealer.SetRedisRoleLabels(ctx, master, rf) }); err != nil {
+		return err
+	}
+
+	if err := r.phase(ctx, rf, "CheckAndHealSlaves", func(ctx context.Context) error { return r.checkAndHealSlaves(ctx, rf, master) }); err != nil {
+		return err
+	}
+
+	err = r.phase(ctx, rf, "ApplyRedisCustomConfig", func(ctx context.Context) error { return r.applyRedisCustomConfig(ctx, rf) })
+	setRedisCheckerMetrics(r.mClient, "redis", rf.Namespace, rf.Name, metrics.APPLY_REDIS_CONFIG, metrics.NOT_APPLICABLE, err)
+	if err != nil {
+		return err
+	}
+
+	if err := r.phase(ctx, rf, "UpdateRedisesPods", func(ctx context.Context) error { return r.UpdateRedisesPods(ctx, rf) }); err != nil {
+		return err
+	}
+
+	// info ouput: master0:name=master0,status=ok,address=x.x.x.x:6379,slaves=2,sentinels=3
+	// ensure all sentinels monitor is the correct master
+	sentinels, err := r.rfChecker.GetSentinelsIPs(ctx, rf)
+	if err != nil {
+		return err
+	}
+
+	var ready bool
+	err = r.phase(ctx, rf, "CheckAndHealSentinelMonitors", func(ctx context.Context) (err error) {
+		ready, err = r.checkAndHealSentinelMonitors(ctx, rf, sentinels, master)
+		return err
+	})
+	if err != nil || !ready {
+		return err
+	}
+
+	// The master is switched before its node evicts it, the proxy follows the new one on the next reconcile
+	var switched bool
+	err = r.phase(ctx, rf, "SwitchoverFromDrainingNode", func(ctx context.Context) (err error) {
+		switched, err = r.switchoverFromDrainingNode(ctx, rf, sentinels, master)
+		return err
+	})
+	if err != nil || switched {
+		return err
+	}
+
+	if err := r.phase(ctx, rf, "EnsureProxyFollowsMaster", func(ctx context.Context) error { return r.ensureProxyFollowsMaster(ctx, rf, master) }); err != nil {
+		return err
+	}
+	return r.phase(ctx, rf, "CheckAndHealSentinels", func(ctx context.Context) error { return r.checkAndHealSentinels(ctx, rf, sentinels) })
+}
+
+// checkAndHealSlaves makes every replica follow the given master
+func (r *RedisFailoverHandler) checkAndHealSlaves(ctx context.Context, rf *redisfailoverv1.RedisFailover, master string) error {
+	err := r.rfChecker.CheckAllSlavesFromMaster(ctx, master, rf)
+	setRedisCheckerMetrics(r.mClient, "redis", rf.Namespace, rf.Name, metrics.SLAVE_WRONG_MASTER, metrics.NOT_APPLICABLE, err)
+	if err != nil {
+		rfservice.Logger(ctx, r.logger, rf).Warningf("Slave not associated to master: %s", err.Error())
+		return r.rfHealer.SetMasterOnAll(ctx, master, rf)
+	}
+	return nil
+}
+
+// checkAndHealMaster makes sure there is a single master, electing one when sentinel can't, and returns it. There is no
+// master to go on with while the redises and sentinels aren't running or sentinel is failing over
+func (r *RedisFailoverHandler) checkAndHealMaster(ctx context.Context, rf *redisfailoverv1.RedisFailover) (string, error) {
+	if !r.rfChecker.IsRedisRunning(ctx, rf) {
 		setRedisCheckerMetrics(r.mClient, "redis", rf.Namespace, rf.Name, metrics.REDIS_REPLICA_MISMATCH, metrics.NOT_APPLICABLE, errors.New("not all replicas running"))
-		r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Debugf("Number of redis mismatch, waiting for redis statefulset reconcile")
-		return nil
+		rfservice.Logger(ctx, r.logger, rf).Debugf("Number of redis mismatch, waiting for redis statefulset reconcile")
+		return "", nil
 	}
-	r.logger.Info("Check redis is running")
+	rfservice.Logger(ctx, r.logger, rf).Info("Check redis is running")
 
-	if !r.rfChecker.IsSentinelRunning(rf) {
+	if !r.rfChecker.IsSentinelRunning(ctx, rf) {
 		setRedisCheckerMetrics(r.mClient, "sentinel", rf.Namespace, rf.Name, metrics.SENTINEL_REPLICA_MISMATCH, metrics.NOT_APPLICABLE, errors.New("not all replicas running"))
-		r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Debugf("Number of sentinel mismatch, waiting for sentinel deployment reconcile")
-		return nil
+		rfservice.Logger(ctx, r.logger, rf).Debugf("Number of sentinel mismatch, waiting for sentinel deployment reconcile")
+		return "", nil
 	}
-	r.logger.Info("Check sentinel is running")
+	rfservice.Logger(ctx, r.logger, rf).Info("Check sentinel is running")
 
-	nMasters, err := r.rfChecker.GetNumberMasters(rf)
+	nMasters, err := r.rfChecker.GetNumberMasters(ctx, rf)
 	if err != nil {
-		return err
+		return "", err
 	}
-	r.logger.Infof("Get redis master number: %d", nMasters)
+	rfservice.Logger(ctx, r.logger, rf).Infof("Get redis master number: %d", nMasters)
 
 	switch nMasters {
 	case 0:
 		setRedisCheckerMetrics(r.mClient, "redis", rf.Namespace, rf.Name, metrics.NO_MASTER, metrics.NOT_APPLICABLE, errors.New("no masters detected"))
+		//When the bootstrapNode is removed, or a replica is promoted, the redises still replicate the external master
+		//and the sentinels may still monitor it. Detach in a controlled way, promoting the most up to date redis,
+		//the sentinels are pointed to it below
+		if rf.Status.ExternalMaster != "" {
+			rfservice.Logger(ctx, r.logger, rf).Infof("Bootstrap or replica mode left, detaching from the external master %s", rf.Status.ExternalMaster)
+			err = r.rfHealer.SetMostUpToDateAsMaster(ctx, rf)
+			setRedisCheckerMetrics(r.mClient, "redis", rf.Namespace, rf.Name, metrics.NO_MASTER, metrics.NOT_APPLICABLE, err)
+			if err != nil {
+				return "", err
+			}
+			if err := r.rfHealer.SetExternalMasterStatus(ctx, "", "", rf); err != nil {
+				return "", err
+			}
+			break
+		}
+		// a master is lost when the operator saw one before, the redises have none on their first boot
+		lost := r.masterLosses.lost(rf.Namespace, rf.Name, time.Now(), false)
 		//when number of redis replicas is 1 , the redis is configured for standalone master mode
 		//Configure to master
 		if rf.Spec.Redis.Replicas == 1 {
-			r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Infof("Resource spec with standalone master - operator will set the master")
-			err = r.rfHealer.SetOldestAsMaster(rf)
+			rfservice.Logger(ctx, r.logger, rf).Infof("Resource spec with standalone master - operator will set the master")
+			err = r.rfHealer.SetOldestAsMaster(ctx, rf)
 			setRedisCheckerMetrics(r.mClient, "redis", rf.Namespace, rf.Name, metrics.NO_MASTER, metrics.NOT_APPLICABLE, err)
 			if err != nil {
-				r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Errorf("Error in Setting oldest Pod as master")
-				return err
+				rfservice.Logger(ctx, r.logger, rf).Errorf("Error in Setting oldest Pod as master")
+				return "", err
 			}
-			return nil
+			if lost {
+				r.mClient.RecordFailover(rf.Namespace, rf.Name, metrics.FAILOVER_BY_OPERATOR)
+			}
+			return "", nil
 		}
 		//During the First boot(New deployment or all pods of the statefulsets have restarted),
 		//Sentinesl will not be able to choose the master , so operator should select a master
 		//Also in scenarios where Sentinels is not in a position to choose a master like , No quorum reached
 		//Operator can choose a master , These scenarios can be checked by asking the all the sentinels
 		//if its in a postion to choose a master also check if the redis is configured with local host IP as master.
-		r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Warningf("Number of Masters running is 0")
-		maxUptime, err := r.rfChecker.GetMaxRedisPodTime(rf)
+		rfservice.Logger(ctx, r.logger, rf).Warningf("Number of Masters running is 0")
+		maxUptime, err := r.rfChecker.GetMaxRedisPodTime(ctx, rf)
 		if err != nil {
-			return err
+			return "", err
 		}
 
-		r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Infof("No master avaiable but max pod up time is : %f", maxUptime.Round(time.Second).Seconds())
+		rfservice.Logger(ctx, r.logger, rf).Infof("No master avaiable but max pod up time is : %f", maxUptime.Round(time.Second).Seconds())
 		//Check If Sentinel has quorum to take a failover decision
-		noqrm_cnt, err := r.rfChecker.CheckSentinelQuorum(rf)
+		noqrm_cnt, err := r.rfChecker.CheckSentinelQuorum(ctx, rf)
 		if err != nil {
 			// Sentinels are not in a situation to choose a master we pick one
-			r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Warningf("Quorum not available for sentinel to choose master,estimated unhealthy sentinels :%d , Operator to step-in", noqrm_cnt)
-			err2 := r.rfHealer.SetOldestAsMaster(rf)
+			rfservice.Logger(ctx, r.logger, rf).Warningf("Quorum not available for sentinel to choose master,estimated unhealthy sentinels :%d , Operator to step-in", noqrm_cnt)
+			err2 := r.rfHealer.SetOldestAsMaster(ctx, rf)
 			setRedisCheckerMetrics(r.mClient, "redis", rf.Namespace, rf.Name, metrics.NO_MASTER, metrics.NOT_APPLICABLE, err2)
 			if err2 != nil {
-				r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Errorf("Error in Setting oldest Pod as master")
-				return err2
+				rfservice.Logger(ctx, r.logger, rf).Errorf("Error in Setting oldest Pod as master")
+				return "", err2
+			}
+			if lost {
+				r.mClient.RecordFailover(rf.Namespace, rf.Name, metrics.FAILOVER_BY_OPERATOR)
 			}
 		} else {
 			//sentinels are having a quorum to make a failover , but check if redis are not having local hostip (first boot) as master
-			status, err2 := r.rfChecker.CheckIfMasterLocalhost(rf)
+			status, err2 := r.rfChecker.CheckIfMasterLocalhost(ctx, rf)
 			if err2 != nil {
-				r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Errorf("CheckIfMasterLocalhost failed retry later")
-				return err2
+				rfservice.Logger(ctx, r.logger, rf).Errorf("CheckIfMasterLocalhost failed retry later")
+				return "", err2
 			} else if status {
 				// all avaialable redis pods have local host ip as master
-				r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Errorf("all available redis is having local loop back as master , operator initiates master selection")
-				err3 := r.rfHealer.SetOldestAsMaster(rf)
+				rfservice.Logger(ctx, r.logger, rf).Errorf("all available redis is having local loop back as master , operator initiates master selection")
+				err3 := r.rfHealer.SetOldestAsMaster(ctx, rf)
 				setRedisCheckerMetrics(r.mClient, "redis", rf.Namespace, rf.Name, metrics.NO_MASTER, metrics.NOT_APPLICABLE, err3)
 				if err3 != nil {
-					r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Errorf("Error in Setting oldest Pod as master")
-					return err3
+					rfservice.Logger(ctx, r.logger, rf).Errorf("Error in Setting oldest Pod as master")
+					return "", err3
+				}
+				if lost {
+					r.mClient.RecordFailover(rf.Namespace, rf.Name, metrics.FAILOVER_BY_OPERATOR)
 				}
 
 			} else {
 
 				// We'll wait until failover is done
-				r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Infof("no master found, wait until failover or fix manually")
+				rfservice.Logger(ctx, r.logger, rf).Infof("no master found, wait until failover or fix manually")
 				setRedisCheckerMetrics(r.mClient, "redis", rf.Namespace, rf.Name, metrics.NO_MASTER, metrics.NOT_APPLICABLE, errors.New("no master not fixed, wait until failover or fix manually"))
-				return nil
+				return "", nil
 			}
 
 		}
 
 	case 1:
 		setRedisCheckerMetrics(r.mClient, "redis", rf.Namespace, rf.Name, metrics.NUMBER_OF_MASTERS, metrics.NOT_APPLICABLE, nil)
+		if recovery, ok := r.masterLosses.found(rf.Namespace, rf.Name); ok {
+			rfservice.Logger(ctx, r.logger, rf).Infof("Master found %s after it was lost", recovery.Round(time.Millisecond))
+			r.mClient.RecordMasterRecovery(rf.Namespace, rf.Name, recovery)
+		}
 	default:
 		setRedisCheckerMetrics(r.mClient, "redis", rf.Namespace, rf.Name, metrics.NUMBER_OF_MASTERS, metrics.NOT_APPLICABLE, errors.New("multiple masters detected"))
-		return errors.New("more than one master, fix manually")
+		return "", errors.New("more than one master, fix manually")
 	}
 
-	master, err := r.rfChecker.GetMasterIP(rf)
+	master, err := r.rfChecker.GetMasterIP(ctx, rf)
 	if err != nil {
-		return err
+		return "", err
 	}
-	r.logger.Infof("Get redis master ip: %s", master)
+	rfservice.Logger(ctx, r.logger, rf).Infof("Get redis master ip: %s", master)
+	return master, nil
+}
 
-	err = r.rfChecker.CheckAllSlavesFromMaster(master, rf)
-	setRedisCheckerMetrics(r.mClient, "redis", rf.Namespace, rf.Name, metrics.SLAVE_WRONG_MASTER, metrics.NOT_APPLICABLE, err)
-	if err != nil {
-		r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Warningf("Slave not associated to master: %s", err.Error())
-		if err = r.rfHealer.SetMasterOnAll(master, rf); err != nil {
-			return err
+// checkAndHealSentinelMonitors makes every sentinel monitor the given master, by the address it announces with external
+// access. It reports whether the sentinels could be checked, not before the external addresses are known
+func (r *RedisFailoverHandler) checkAndHealSentinelMonitors(ctx context.Context, rf *redisfailoverv1.RedisFailover, sentinels []string, master string) (bool, error) {
+	// With external access sentinels know the master by the address it announces
+	monitor, port := master, getRedisPort(rf.Spec.Redis.Port)
+	if rf.Spec.ExternalAccess != nil {
+		external, ready, err := r.announceExternalAddresses(ctx, rf, sentinels)
+		if err != nil {
+			return false, err
+		}
+		if !ready {
+			rfservice.Logger(ctx, r.logger, rf).Infof("Waiting for the external access services to get an address")
+			return false, nil
+		}
+		if monitor, port, err = net.SplitHostPort(external[master]); err != nil {
+			return false, err
 		}
 	}
 
-	err = r.applyRedisCustomConfig(rf)
-	setRedisCheckerMetrics(r.mClient, "redis", rf.Namespace, rf.Name, metrics.APPLY_REDIS_CONFIG, metrics.NOT_APPLICABLE, err)
-	if err != nil {
-		return err
+	for _, sip := range sentinels {
+		err := r.rfChecker.CheckSentinelMonitor(ctx, sip, rf, monitor, port)
+		setRedisCheckerMetrics(r.mClient, "sentinel", rf.Namespace, rf.Name, metrics.SENTINEL_WRONG_MASTER, sip, err)
+		if err != nil {
+			rfservice.Logger(ctx, r.logger, rf).Warningf("Fixing sentinel not monitoring expected master: %s", err.Error())
+			if rf.Spec.ExternalAccess != nil {
+				err = r.rfHealer.NewSentinelMonitorWithPort(ctx, sip, monitor, port, rf)
+			} else {
+				err = r.rfHealer.NewSentinelMonitor(ctx, sip, master, rf)
+			}
+			if err != nil {
+				return false, err
+			}
+		}
 	}
+	return true, nil
+}
 
-	err = r.UpdateRedisesPods(rf)
+// announceExternalAddresses makes every redis and sentinel announce the address its external access service
+// exposes. It returns the external addresses of the redises, and false while any of them is still unassigned.
+func (r *RedisFailoverHandler) announceExternalAddresses(ctx context.Context, rf *redisfailoverv1.RedisFailover, sentinels []string) (map[string]string, bool, error) {
+	redises, err := r.rfChecker.GetRedisesIPs(ctx, rf)
 	if err != nil {
-		return err
+		return nil, false, err
 	}
-
-	// info ouput: master0:name=master0,status=ok,address=x.x.x.x:6379,slaves=2,sentinels=3
-	// ensure all sentinels monitor is the correct master
-	sentinels, err := r.rfChecker.GetSentinelsIPs(rf)
+	redisesExternal, err := r.rfChecker.GetRedisesExternalAddresses(ctx, rf)
 	if err != nil {
-		return err
+		return nil, false, err
+	}
+	sentinelsExternal, err := r.rfChecker.GetSentinelsExternalAddresses(ctx, rf)
+	if err != nil {
+		return nil, false, err
+	}
+	for _, rip := range redises {
+		if _, ok := redisesExternal[rip]; !ok {
+			return nil, false, nil
+		}
+	}
+	for _, sip := range sentinels {
+		if _, ok := sentinelsExternal[sip]; !ok {
+			return nil, false, nil
+		}
 	}
 
-	port := getRedisPort(rf.Spec.Redis.Port)
+	for _, rip := range redises {
+		host, port, err := net.SplitHostPort(redisesExternal[rip])
+		if err != nil {
+			return nil, false, err
+		}
+		if err := r.rfHealer.SetRedisAnnounce(ctx, rip, host, port, rf); err != nil {
+			return nil, false, err
+		}
+	}
 	for _, sip := range sentinels {
-		err = r.rfChecker.CheckSentinelMonitor(sip, master, port)
-		setRedisCheckerMetrics(r.mClient, "sentinel", rf.Namespace, rf.Name, metrics.SENTINEL_WRONG_MASTER, sip, err)
+		host, port, err := net.SplitHostPort(sentinelsExternal[sip])
 		if err != nil {
-			r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Warningf("Fixing sentinel not monitoring expected master: %s", err.Error())
-			if err := r.rfHealer.NewSentinelMonitor(sip, master, rf); err != nil {
-				return err
-			}
+			return nil, false, err
+		}
+		if err := r.rfHealer.SetSentinelAnnounce(ctx, sip, host, port, rf); err != nil {
+			return nil, false, err
 		}
 	}
-	return r.checkAndHealSentinels(rf, sentinels)
+	return redisesExternal, true, nil
+}
+
+// ensureProxyFollowsMaster points the proxies that route to the redises directly, instead of
+// asking the sentinels, to the master the sentinels agree on. Their pods are rolled when the master changes.
+func (r *RedisFailoverHandler) ensureProxyFollowsMaster(ctx context.Context, rf *redisfailoverv1.RedisFailover, master string) error {
+	if !rfservice.GetProxy(rf).FollowsMaster() {
+		return nil
+	}
+
+	redises, err := r.rfChecker.GetRedisesIPs(ctx, rf)
+	if err != nil {
+		return err
+	}
+	replicas := []string{}
+	for _, rip := range redises {
+		if rip != master {
+			replicas = append(replicas, rip)
+		}
+	}
+
+	backends := rfservice.ProxyBackends{
+		Master:   master,
+		Replicas: replicas,
+	}
+	labels := r.getLabels(ctx, rf)
+	oRefs := r.createOwnerReferences(rf)
+	if err := r.rfService.EnsureProxyConfigMap(ctx, rf, labels, oRefs, backends); err != nil {
+		return err
+	}
+	return r.rfService.EnsureProxyDeployment(ctx, rf, labels, oRefs)
 }
 
-func (r *RedisFailoverHandler) checkAndHealBootstrapMode(rf *redisfailoverv1.RedisFailover) error {
+func (r *RedisFailoverHandler) checkAndHealBootstrapMode(ctx context.Context, rf *redisfailoverv1.RedisFailover) error {
 
-	if !r.rfChecker.IsRedisRunning(rf) {
+	if !r.rfChecker.IsRedisRunning(ctx, rf) {
 		setRedisCheckerMetrics(r.mClient, "redis", rf.Namespace, rf.Name, metrics.REDIS_REPLICA_MISMATCH, metrics.NOT_APPLICABLE, errors.New("not all replicas running"))
-		r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Debugf("Number of redis mismatch, waiting for redis statefulset reconcile")
+		rfservice.Logger(ctx, r.logger, rf).Debugf("Number of redis mismatch, waiting for redis statefulset reconcile")
 		return nil
 	}
 
-	err := r.UpdateRedisesPods(rf)
+	err := r.UpdateRedisesPods(ctx, rf)
 	if err != nil {
 		return err
 	}
-	err = r.applyRedisCustomConfig(rf)
+	err = r.applyRedisCustomConfig(ctx, rf)
 	setRedisCheckerMetrics(r.mClient, "redis", rf.Namespace, rf.Name, metrics.APPLY_REDIS_CONFIG, metrics.NOT_APPLICABLE, err)
 	if err != nil {
 		return err
 	}
 
-	bootstrapSettings := rf.Spec.BootstrapNode
-	err = r.rfHealer.SetExternalMasterOnAll(bootstrapSettings.Host, bootstrapSettings.Port, rf)
+	// The external master is asked to its sentinels every time, so the redises follow its failovers
+	bootstrapMaster, bootstrapPort, err := r.rfChecker.GetBootstrapMaster(ctx, rf)
+	if err != nil {
+		setRedisCheckerMetrics(r.mClient, "redis", rf.Namespace, rf.Name, metrics.APPLY_EXTERNAL_MASTER, metrics.NOT_APPLICABLE, err)
+		return err
+	}
+	err = r.rfHealer.SetExternalMasterOnAll(ctx, bootstrapMaster, bootstrapPort, rf)
 	setRedisCheckerMetrics(r.mClient, "redis", rf.Namespace, rf.Name, metrics.APPLY_EXTERNAL_MASTER, metrics.NOT_APPLICABLE, err)
 	if err != nil {
 		return err
 	}
+	if err := r.rfHealer.SetExternalMasterStatus(ctx, bootstrapMaster, bootstrapPort, rf); err != nil {
+		return err
+	}
 
 	if rf.SentinelsAllowed() {
-		if !r.rfChecker.IsSentinelRunning(rf) {
+		if !r.rfChecker.IsSentinelRunning(ctx, rf) {
 			setRedisCheckerMetrics(r.mClient, "sentinel", rf.Namespace, rf.Name, metrics.SENTINEL_REPLICA_MISMATCH, metrics.NOT_APPLICABLE, errors.New("not all replicas running"))
-			r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Debugf("Number of sentinel mismatch, waiting for sentinel deployment reconcile")
+			rfservice.Logger(ctx, r.logger, rf).Debugf("Number of sentinel mismatch, waiting for sentinel deployment reconcile")
 			return nil
 		}
 
-		sentinels, err := r.rfChecker.GetSentinelsIPs(rf)
+		sentinels, err := r.rfChecker.GetSentinelsIPs(ctx, rf)
 		if err != nil {
 			return err
 		}
 		for _, sip := range sentinels {
-			err = r.rfChecker.CheckSentinelMonitor(sip, bootstrapSettings.Host, bootstrapSettings.Port)
+			err = r.rfChecker.CheckSentinelMonitor(ctx, sip, rf, bootstrapMaster, bootstrapPort)
 			setRedisCheckerMetrics(r.mClient, "sentinel", rf.Namespace, rf.Name, metrics.SENTINEL_WRONG_MASTER, sip, err)
 			if err != nil {
-				r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Warningf("Fixing sentinel not monitoring expected master: %s", err.Error())
-				if err := r.rfHealer.NewSentinelMonitorWithPort(sip, bootstrapSettings.Host, bootstrapSettings.Port, rf); err != nil {
+				rfservice.Logger(ctx, r.logger, rf).Warningf("Fixing sentinel not monitoring expected master: %s", err.Error())
+				if err := r.rfHealer.NewSentinelMonitorWithPort(ctx, sip, bootstrapMaster, bootstrapPort, rf); err != nil {
 					return err
 				}
 			}
 		}
-		return r.checkAndHealSentinels(rf, sentinels)
+		return r.checkAndHealSentinels(ctx, rf, sentinels)
 	}
 	return nil
 }
 
-func (r *RedisFailoverHandler) applyRedisCustomConfig(rf *redisfailoverv1.RedisFailover) error {
-	redises, err := r.rfChecker.GetRedisesIPs(rf)
+// checkAndHealReplicaMode keeps every redis replicating the current master of the source RedisFailover
+func (r *RedisFailoverHandler) checkAndHealReplicaMode(ctx context.Context, rf *redisfailoverv1.RedisFailover) error {
+	if !r.rfChecker.IsRedisRunning(ctx, rf) {
+		setRedisCheckerMetrics(r.mClient, "redis", rf.Namespace, rf.Name, metrics.REDIS_REPLICA_MISMATCH, metrics.NOT_APPLICABLE, errors.New("not all replicas running"))
+		rfservice.Logger(ctx, r.logger, rf).Debugf("Number of redis mismatch, waiting for redis statefulset reconcile")
+		return nil
+	}
+
+	err := r.UpdateRedisesPods(ctx, rf)
+	if err != nil {
+		return err
+	}
+	err = r.applyRedisCustomConfig(ctx, rf)
+	setRedisCheckerMetrics(r.mClient, "redis", rf.Namespace, rf.Name, metrics.APPLY_REDIS_CONFIG, metrics.NOT_APPLICABLE, err)
+	if err != nil {
+		return err
+	}
+
+	master, port, err := r.rfChecker.GetReplicaOfMaster(ctx, rf)
+	if err != nil {
+		setRedisCheckerMetrics(r.mClient, "redis", rf.Namespace, rf.Name, metrics.APPLY_EXTERNAL_MASTER, metrics.NOT_APPLICABLE, err)
+		return err
+	}
+	rfservice.Logger(ctx, r.logger, rf).Debugf("Source redisfailover master is %s:%s", master, port)
+
+	err = r.rfHealer.SetExternalMasterOnAll(ctx, master, port, rf)
+	setRedisCheckerMetrics(r.mClient, "redis", rf.Namespace, rf.Name, metrics.APPLY_EXTERNAL_MASTER, metrics.NOT_APPLICABLE, err)
+	if err != nil {
+		return err
+	}
+	return r.rfHealer.SetExternalMasterStatus(ctx, master, port, rf)
+}
+
+// checkAndHealPasswordRotation makes the redises accept both passwords while the auth secret holds a previous one,
+// and drops the previous password once it is removed from the secret
+func (r *RedisFailoverHandler) checkAndHealPasswordRotation(ctx context.Context, rf *redisfailoverv1.RedisFailover) error {
+	rotating, err := r.rfChecker.IsPasswordRotating(ctx, rf)
+	if err != nil {
+		return err
+	}
+	inProgress := rf.Status.PasswordRotation != nil && rf.Status.PasswordRotation.Phase == redisfailoverv1.PasswordRotationInProgress
+	if !rotating && !inProgress {
+		return nil
+	}
+
+	err = r.rfHealer.SetRedisPasswords(ctx, rf)
+	setRedisCheckerMetrics(r.mClient, "redis", rf.Namespace, rf.Name, metrics.SET_DEFAULT_USER_PASSWORDS, metrics.NOT_APPLICABLE, err)
+	if err != nil {
+		return err
+	}
+
+	if rotating {
+		if inProgress {
+			return nil
+		}
+		return r.rfHealer.SetPasswordRotationStatus(ctx, redisfailoverv1.PasswordRotationInProgress, "both passwords accepted, remove previousPassword from the secret to drop the previous one", rf)
+	}
+	return r.rfHealer.SetPasswordRotationStatus(ctx, redisfailoverv1.PasswordRotationCompleted, "previous password dropped", rf)
+}
+
+func (r *RedisFailoverHandler) applyRedisCustomConfig(ctx context.Context, rf *redisfailoverv1.RedisFailover) error {
+	redises, err := r.rfChecker.GetRedisesIPs(ctx, rf)
 	if err != nil {
 		return err
 	}
 	for _, rip := range redises {
-		if err := r.rfHealer.SetRedisCustomConfig(rip, rf); err != nil {
+		if err := r.rfHealer.SetRedisCustomConfig(ctx, rip, rf); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func (r *RedisFailoverHandler) checkAndHealSentinels(rf *redisfailoverv1.RedisFailover, sentinels []string) error {
+func (r *RedisFailoverHandler) checkAndHealSentinels(ctx context.Context, rf *redisfailoverv1.RedisFailover, sentinels []string) error {
 	for _, sip := range sentinels {
-		err := r.rfChecker.CheckSentinelNumberInMemory(sip, rf)
+		err := r.rfChecker.CheckSentinelNumberInMemory(ctx, sip, rf)
 		setRedisCheckerMetrics(r.mClient, "sentinel", rf.Namespace, rf.Name, metrics.SENTINEL_NUMBER_IN_MEMORY_MISMATCH, sip, err)
 		if err != nil {
-			r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Warningf("Sentinel %s mismatch number of sentinels in memory. resetting", sip)
-			if err := r.rfHealer.RestoreSentinel(sip); err != nil {
+			rfservice.Logger(ctx, r.logger, rf).Warningf("Sentinel %s mismatch number of sentinels in memory. resetting", sip)
+			if err := r.rfHealer.RestoreSentinel(ctx, sip, rf); err != nil {
 				return err
 			}
 		}
 
 	}
 	for _, sip := range sentinels {
-		err := r.rfChecker.CheckSentinelSlavesNumberInMemory(sip, rf)
+		err := r.rfChecker.CheckSentinelSlavesNumberInMemory(ctx, sip, rf)
 		setRedisCheckerMetrics(r.mClient, "sentinel", rf.Namespace, rf.Name, metrics.REDIS_SLAVES_NUMBER_IN_MEMORY_MISMATCH, sip, err)
 		if err != nil {
-			r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Warningf("Sentinel %s mismatch number of expected slaves in memory. resetting", sip)
-			if err := r.rfHealer.RestoreSentinel(sip); err != nil {
+			rfservice.Logger(ctx, r.logger, rf).Warningf("Sentinel %s mismatch number of expected slaves in memory. resetting", sip)
+			if err := r.rfHealer.RestoreSentinel(ctx, sip, rf); err != nil {
 				return err
 			}
 		}
 	}
 	for _, sip := range sentinels {
-		err := r.rfHealer.SetSentinelCustomConfig(sip, rf)
+		err := r.rfHealer.SetSentinelCustomConfig(ctx, sip, rf)
 		setRedisCheckerMetrics(r.mClient, "sentinel", rf.Namespace, rf.Name, metrics.APPLY_SENTINEL_CONFIG, sip, err)
 		if err != nil {
 			return err
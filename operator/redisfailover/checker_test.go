@@ -1,22 +1,26 @@
 package redisfailover_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
 	"github.com/spotahome/redis-operator/log"
 	"github.com/spotahome/redis-operator/metrics"
 	mRFService "github.com/spotahome/redis-operator/mocks/operator/redisfailover/service"
 	mK8SService "github.com/spotahome/redis-operator/mocks/service/k8s"
 	rfOperator "github.com/spotahome/redis-operator/operator/redisfailover"
+	rfservice "github.com/spotahome/redis-operator/operator/redisfailover/service"
 )
 
 func TestCheckAndHeal(t *testing.T) {
@@ -35,6 +39,10 @@ func TestCheckAndHeal(t *testing.T) {
 		redisSetMasterOnAllOK          bool
 		bootstrapping                  bool
 		allowSentinels                 bool
+		envoyProxy                     bool
+		externalAccess                 bool
+		externalAccessPending          bool
+		externalMaster                 bool
 	}{
 		{
 			name:                           "Everything ok, no need to heal",
@@ -52,6 +60,43 @@ func TestCheckAndHeal(t *testing.T) {
 			bootstrapping:                  false,
 			allowSentinels:                 false,
 		},
+		{
+			name:                           "Everything ok, envoy proxy follows the master",
+			nMasters:                       1,
+			nRedis:                         3,
+			slavesOK:                       true,
+			sentinelMonitorOK:              true,
+			sentinelNumberInMemoryOK:       true,
+			sentinelSlavesNumberInMemoryOK: true,
+			redisCheckNumberOK:             true,
+			redisSetMasterOnAllOK:          true,
+			envoyProxy:                     true,
+		},
+		{
+			name:                           "External access, sentinel monitoring the pod address",
+			nMasters:                       1,
+			nRedis:                         3,
+			slavesOK:                       true,
+			sentinelMonitorOK:              false,
+			sentinelNumberInMemoryOK:       true,
+			sentinelSlavesNumberInMemoryOK: true,
+			redisCheckNumberOK:             true,
+			redisSetMasterOnAllOK:          true,
+			externalAccess:                 true,
+		},
+		{
+			name:                           "External access, waiting for the service addresses",
+			nMasters:                       1,
+			nRedis:                         3,
+			slavesOK:                       true,
+			sentinelMonitorOK:              true,
+			sentinelNumberInMemoryOK:       true,
+			sentinelSlavesNumberInMemoryOK: true,
+			redisCheckNumberOK:             true,
+			redisSetMasterOnAllOK:          true,
+			externalAccess:                 true,
+			externalAccessPending:          true,
+		},
 		{
 			name:                           "Multiple masters",
 			nMasters:                       2,
@@ -84,6 +129,18 @@ func TestCheckAndHeal(t *testing.T) {
 			bootstrapping:                  false,
 			allowSentinels:                 false,
 		},
+		{
+			name:                           "No masters, replicating an external master, detach",
+			nMasters:                       0,
+			nRedis:                         3,
+			externalMaster:                 true,
+			slavesOK:                       true,
+			sentinelMonitorOK:              false,
+			sentinelNumberInMemoryOK:       true,
+			sentinelSlavesNumberInMemoryOK: true,
+			redisCheckNumberOK:             true,
+			redisSetMasterOnAllOK:          true,
+		},
 		{
 			name:                           "No masters, only one redis available, make master",
 			nMasters:                       0,
@@ -288,6 +345,18 @@ func TestCheckAndHeal(t *testing.T) {
 			if test.singleMasterTest {
 				rf.Spec.Redis.Replicas = 1
 			}
+			if test.envoyProxy {
+				rf.Spec.Proxy.Type = redisfailoverv1.ProxyTypeEnvoy
+			}
+			if test.externalAccess {
+				rf.Spec.ExternalAccess = &redisfailoverv1.ExternalAccessSettings{
+					Type:        corev1.ServiceTypeNodePort,
+					HostAddress: "redis.example.com",
+				}
+			}
+			if test.externalMaster {
+				rf.Status.ExternalMaster = "10.0.0.9:6379"
+			}
 
 			expErr := false
 			continueTests := true
@@ -301,56 +370,64 @@ func TestCheckAndHeal(t *testing.T) {
 			mrfc := &mRFService.RedisFailoverCheck{}
 			mrfh := &mRFService.RedisFailoverHeal{}
 
+			mrfc.On("IsPasswordRotating", mock.Anything, rf).Once().Return(false, nil)
 			if test.redisCheckNumberOK {
-				mrfc.On("IsRedisRunning", rf).Once().Return(true)
+				mrfc.On("IsRedisRunning", mock.Anything, rf).Once().Return(true)
 			} else {
 				continueTests = false
-				mrfc.On("IsRedisRunning", rf).Once().Return(false)
+				mrfc.On("IsRedisRunning", mock.Anything, rf).Once().Return(false)
 			}
 
 			if allowSentinels {
-				mrfc.On("IsSentinelRunning", rf).Once().Return(true)
+				mrfc.On("IsSentinelRunning", mock.Anything, rf).Once().Return(true)
 			}
 
 			if bootstrappingTests && continueTests {
 				// once to get ips for config update, once for the UpdateRedisesPods go right
-				mrfc.On("GetRedisesIPs", rf).Twice().Return([]string{"0.0.0.1", "0.0.0.2", "0.0.0.3"}, nil)
-				mrfh.On("SetRedisCustomConfig", "0.0.0.1", rf).Once().Return(nil)
-				mrfh.On("SetRedisCustomConfig", "0.0.0.2", rf).Once().Return(nil)
-				mrfh.On("SetRedisCustomConfig", "0.0.0.3", rf).Once().Return(nil)
-				mrfc.On("CheckRedisSlavesReady", "0.0.0.1", rf).Once().Return(true, nil)
-				mrfc.On("CheckRedisSlavesReady", "0.0.0.2", rf).Once().Return(true, nil)
-				mrfc.On("CheckRedisSlavesReady", "0.0.0.3", rf).Once().Return(true, nil)
-				mrfc.On("GetStatefulSetUpdateRevision", rf).Once().Return("1", nil)
-				mrfc.On("GetRedisesSlavesPods", rf).Once().Return([]string{}, nil)
+				mrfc.On("GetRedisesIPs", mock.Anything, rf).Twice().Return([]string{"0.0.0.1", "0.0.0.2", "0.0.0.3"}, nil)
+				mrfh.On("SetRedisCustomConfig", mock.Anything, "0.0.0.1", rf).Once().Return(nil)
+				mrfh.On("SetRedisCustomConfig", mock.Anything, "0.0.0.2", rf).Once().Return(nil)
+				mrfh.On("SetRedisCustomConfig", mock.Anything, "0.0.0.3", rf).Once().Return(nil)
+				mrfc.On("CheckRedisSlavesReady", mock.Anything, "0.0.0.1", rf).Once().Return(true, nil)
+				mrfc.On("CheckRedisSlavesReady", mock.Anything, "0.0.0.2", rf).Once().Return(true, nil)
+				mrfc.On("CheckRedisSlavesReady", mock.Anything, "0.0.0.3", rf).Once().Return(true, nil)
+				mrfc.On("GetStatefulSetUpdateRevisions", mock.Anything, rf).Once().Return(map[string]string{"rfr-test": "1"}, nil)
+				mrfc.On("GetRedisesSlavesPods", mock.Anything, rf).Once().Return([]string{}, nil)
+				mrfc.On("GetBootstrapMaster", mock.Anything, rf).Once().Return(bootstrapMaster, bootstrapMasterPort, nil)
 
 				if test.redisSetMasterOnAllOK {
-					mrfh.On("SetExternalMasterOnAll", bootstrapMaster, bootstrapMasterPort, rf).Once().Return(nil)
+					mrfh.On("SetExternalMasterOnAll", mock.Anything, bootstrapMaster, bootstrapMasterPort, rf).Once().Return(nil)
+					mrfh.On("SetExternalMasterStatus", mock.Anything, bootstrapMaster, bootstrapMasterPort, rf).Once().Return(nil)
 				} else {
 					expErr = true
-					mrfh.On("SetExternalMasterOnAll", bootstrapMaster, bootstrapMasterPort, rf).Once().Return(errors.New(""))
+					mrfh.On("SetExternalMasterOnAll", mock.Anything, bootstrapMaster, bootstrapMasterPort, rf).Once().Return(errors.New(""))
 				}
 			} else if continueTests {
-				mrfc.On("GetNumberMasters", rf).Once().Return(test.nMasters, nil)
+				mrfc.On("GetNumberMasters", mock.Anything, rf).Once().Return(test.nMasters, nil)
 				switch test.nMasters {
 				case 0:
-					//mrfc.On("GetRedisesIPs", rf).Once().Return(make([]string, test.nRedis), nil)
+					//mrfc.On("GetRedisesIPs", mock.Anything, rf).Once().Return(make([]string, test.nRedis), nil)
+					if test.externalMaster {
+						mrfh.On("SetMostUpToDateAsMaster", mock.Anything, rf).Once().Return(nil)
+						mrfh.On("SetExternalMasterStatus", mock.Anything, "", "", rf).Once().Return(nil)
+						break
+					}
 					if rf.Spec.Redis.Replicas == 1 {
-						mrfh.On("SetOldestAsMaster", rf).Once().Return(nil)
+						mrfh.On("SetOldestAsMaster", mock.Anything, rf).Once().Return(nil)
 						continueTests = false
 						break
 					}
-					mrfc.On("GetMaxRedisPodTime", rf).Once().Return(1*time.Hour, nil)
+					mrfc.On("GetMaxRedisPodTime", mock.Anything, rf).Once().Return(1*time.Hour, nil)
 					if test.forceNewMasterNoQrm {
-						mrfc.On("CheckSentinelQuorum", rf).Once().Return(1, errors.New(""))
-						mrfh.On("SetOldestAsMaster", rf).Once().Return(nil)
+						mrfc.On("CheckSentinelQuorum", mock.Anything, rf).Once().Return(1, errors.New(""))
+						mrfh.On("SetOldestAsMaster", mock.Anything, rf).Once().Return(nil)
 					} else if test.forceNewMasterFirstBoot {
-						mrfc.On("CheckSentinelQuorum", rf).Once().Return(3, nil)
-						mrfc.On("CheckIfMasterLocalhost", rf).Once().Return(true, nil)
-						mrfh.On("SetOldestAsMaster", rf).Once().Return(nil)
+						mrfc.On("CheckSentinelQuorum", mock.Anything, rf).Once().Return(3, nil)
+						mrfc.On("CheckIfMasterLocalhost", mock.Anything, rf).Once().Return(true, nil)
+						mrfh.On("SetOldestAsMaster", mock.Anything, rf).Once().Return(nil)
 					} else {
-						mrfc.On("CheckSentinelQuorum", rf).Once().Return(3, nil)
-						mrfc.On("CheckIfMasterLocalhost", rf).Once().Return(false, nil)
+						mrfc.On("CheckSentinelQuorum", mock.Anything, rf).Once().Return(3, nil)
+						mrfc.On("CheckIfMasterLocalhost", mock.Anything, rf).Once().Return(false, nil)
 						continueTests = false
 					}
 
@@ -361,62 +438,101 @@ func TestCheckAndHeal(t *testing.T) {
 					expErr = true
 				}
 				if !expErr && continueTests {
-					mrfc.On("GetMasterIP", rf).Twice().Return(master, nil)
+					mrfc.On("GetMasterIP", mock.Anything, rf).Twice().Return(master, nil)
+					mrfh.On("SetRedisRoleLabels", mock.Anything, master, rf).Once().Return(nil)
 					if test.slavesOK {
-						mrfc.On("CheckAllSlavesFromMaster", master, rf).Once().Return(nil)
+						mrfc.On("CheckAllSlavesFromMaster", mock.Anything, master, rf).Once().Return(nil)
 					} else {
-						mrfc.On("CheckAllSlavesFromMaster", master, rf).Once().Return(errors.New(""))
+						mrfc.On("CheckAllSlavesFromMaster", mock.Anything, master, rf).Once().Return(errors.New(""))
 						if test.redisSetMasterOnAllOK {
-							mrfh.On("SetMasterOnAll", master, rf).Once().Return(nil)
+							mrfh.On("SetMasterOnAll", mock.Anything, master, rf).Once().Return(nil)
 						} else {
 							expErr = true
-							mrfh.On("SetMasterOnAll", master, rf).Once().Return(errors.New(""))
+							mrfh.On("SetMasterOnAll", mock.Anything, master, rf).Once().Return(errors.New(""))
 						}
 
 					}
-					mrfc.On("GetRedisesIPs", rf).Twice().Return([]string{master}, nil)
-					mrfc.On("GetStatefulSetUpdateRevision", rf).Once().Return("1", nil)
-					mrfc.On("GetRedisesSlavesPods", rf).Once().Return([]string{}, nil)
-					mrfc.On("GetRedisesMasterPod", rf).Once().Return(master, nil)
-					mrfc.On("GetRedisRevisionHash", master, rf).Once().Return("1", nil)
-					mrfh.On("SetRedisCustomConfig", master, rf).Once().Return(nil)
+					redisesIPsCalls := 2
+					if test.envoyProxy {
+						// once more to get the replicas the proxy is configured with
+						redisesIPsCalls++
+					}
+					if test.externalAccess {
+						// once more to check every redis has an external address
+						redisesIPsCalls++
+					}
+					mrfc.On("GetRedisesIPs", mock.Anything, rf).Times(redisesIPsCalls).Return([]string{master}, nil)
+					mrfc.On("GetStatefulSetUpdateRevisions", mock.Anything, rf).Once().Return(map[string]string{"rfr-test": "1"}, nil)
+					mrfc.On("GetRedisesSlavesPods", mock.Anything, rf).Once().Return([]string{}, nil)
+					mrfc.On("GetRedisesMasterPod", mock.Anything, rf).Once().Return("rfr-test-0", nil)
+					mrfc.On("GetRedisRevisionHash", mock.Anything, "rfr-test-0", rf).Once().Return("1", nil)
+					mrfh.On("SetRedisCustomConfig", mock.Anything, master, rf).Once().Return(nil)
 				}
 			}
 
 			if allowSentinels && !expErr && continueTests {
-				mrfc.On("GetSentinelsIPs", rf).Once().Return([]string{sentinel}, nil)
-				if test.sentinelMonitorOK {
+				mrfc.On("GetSentinelsIPs", mock.Anything, rf).Once().Return([]string{sentinel}, nil)
+				if test.externalAccess {
+					mrfc.On("GetRedisesExternalAddresses", mock.Anything, rf).Once().Return(map[string]string{master: "redis.example.com:31000"}, nil)
+					if test.externalAccessPending {
+						mrfc.On("GetSentinelsExternalAddresses", mock.Anything, rf).Once().Return(map[string]string{}, nil)
+						continueTests = false
+					} else {
+						mrfc.On("GetSentinelsExternalAddresses", mock.Anything, rf).Once().Return(map[string]string{sentinel: "redis.example.com:31001"}, nil)
+						mrfh.On("SetRedisAnnounce", mock.Anything, master, "redis.example.com", "31000", rf).Once().Return(nil)
+						mrfh.On("SetSentinelAnnounce", mock.Anything, sentinel, "redis.example.com", "31001", rf).Once().Return(nil)
+					}
+				}
+			}
+
+			if allowSentinels && !expErr && continueTests {
+				if test.externalAccess {
+					if test.sentinelMonitorOK {
+						mrfc.On("CheckSentinelMonitor", mock.Anything, sentinel, rf, "redis.example.com", "31000").Once().Return(nil)
+					} else {
+						mrfc.On("CheckSentinelMonitor", mock.Anything, sentinel, rf, "redis.example.com", "31000").Once().Return(errors.New(""))
+						mrfh.On("NewSentinelMonitorWithPort", mock.Anything, sentinel, "redis.example.com", "31000", rf).Once().Return(nil)
+					}
+				} else if test.sentinelMonitorOK {
 					if test.bootstrapping {
-						mrfc.On("CheckSentinelMonitor", sentinel, bootstrapMaster, bootstrapMasterPort).Once().Return(nil)
+						mrfc.On("CheckSentinelMonitor", mock.Anything, sentinel, rf, bootstrapMaster, bootstrapMasterPort).Once().Return(nil)
 					} else {
-						mrfc.On("CheckSentinelMonitor", sentinel, master, "0").Once().Return(nil)
+						mrfc.On("CheckSentinelMonitor", mock.Anything, sentinel, rf, master, "0").Once().Return(nil)
 					}
 				} else {
 					if test.bootstrapping {
-						mrfc.On("CheckSentinelMonitor", sentinel, bootstrapMaster, bootstrapMasterPort).Once().Return(errors.New(""))
-						mrfh.On("NewSentinelMonitorWithPort", sentinel, bootstrapMaster, bootstrapMasterPort, rf).Once().Return(nil)
+						mrfc.On("CheckSentinelMonitor", mock.Anything, sentinel, rf, bootstrapMaster, bootstrapMasterPort).Once().Return(errors.New(""))
+						mrfh.On("NewSentinelMonitorWithPort", mock.Anything, sentinel, bootstrapMaster, bootstrapMasterPort, rf).Once().Return(nil)
 					} else {
-						mrfc.On("CheckSentinelMonitor", sentinel, master, "0").Once().Return(errors.New(""))
-						mrfh.On("NewSentinelMonitor", sentinel, master, rf).Once().Return(nil)
+						mrfc.On("CheckSentinelMonitor", mock.Anything, sentinel, rf, master, "0").Once().Return(errors.New(""))
+						mrfh.On("NewSentinelMonitor", mock.Anything, sentinel, master, rf).Once().Return(nil)
 					}
 				}
 				if test.sentinelNumberInMemoryOK {
-					mrfc.On("CheckSentinelNumberInMemory", sentinel, rf).Once().Return(nil)
+					mrfc.On("CheckSentinelNumberInMemory", mock.Anything, sentinel, rf).Once().Return(nil)
 				} else {
-					mrfc.On("CheckSentinelNumberInMemory", sentinel, rf).Once().Return(errors.New(""))
-					mrfh.On("RestoreSentinel", sentinel).Once().Return(nil)
+					mrfc.On("CheckSentinelNumberInMemory", mock.Anything, sentinel, rf).Once().Return(errors.New(""))
+					mrfh.On("RestoreSentinel", mock.Anything, sentinel, rf).Once().Return(nil)
 				}
 				if test.sentinelSlavesNumberInMemoryOK {
-					mrfc.On("CheckSentinelSlavesNumberInMemory", sentinel, rf).Once().Return(nil)
+					mrfc.On("CheckSentinelSlavesNumberInMemory", mock.Anything, sentinel, rf).Once().Return(nil)
 				} else {
-					mrfc.On("CheckSentinelSlavesNumberInMemory", sentinel, rf).Once().Return(errors.New(""))
-					mrfh.On("RestoreSentinel", sentinel).Once().Return(nil)
+					mrfc.On("CheckSentinelSlavesNumberInMemory", mock.Anything, sentinel, rf).Once().Return(errors.New(""))
+					mrfh.On("RestoreSentinel", mock.Anything, sentinel, rf).Once().Return(nil)
+				}
+				mrfh.On("SetSentinelCustomConfig", mock.Anything, sentinel, rf).Once().Return(nil)
+				if !test.bootstrapping {
+					mrfc.On("GetRedisesOnDrainingNodes", mock.Anything, rf).Once().Return(map[string]bool{}, nil)
+				}
+				if test.envoyProxy {
+					backends := rfservice.ProxyBackends{Master: master, Replicas: []string{}}
+					mrfs.On("EnsureProxyConfigMap", mock.Anything, rf, mock.Anything, mock.Anything, backends).Once().Return(nil)
+					mrfs.On("EnsureProxyDeployment", mock.Anything, rf, mock.Anything, mock.Anything).Once().Return(nil)
 				}
-				mrfh.On("SetSentinelCustomConfig", sentinel, rf).Once().Return(nil)
 			}
 
 			handler := rfOperator.NewRedisFailoverHandler(config, mrfs, mrfc, mrfh, mk, metrics.Dummy, log.Dummy)
-			err := handler.CheckAndHeal(rf)
+			err := handler.CheckAndHeal(context.Background(), rf)
 
 			if expErr {
 				assert.Error(err)
@@ -425,6 +541,197 @@ func TestCheckAndHeal(t *testing.T) {
 			}
 			mrfc.AssertExpectations(t)
 			mrfh.AssertExpectations(t)
+			mrfs.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCheckAndHealReplicating(t *testing.T) {
+	tests := []struct {
+		name              string
+		sourceMasterError bool
+	}{
+		{
+			name: "replicas follow the source master",
+		},
+		{
+			name:              "source without master",
+			sourceMasterError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateRF(false, false)
+			rf.Spec.ReplicaOf = &redisfailoverv1.ReplicaOfSettings{
+				RedisFailoverRef: redisfailoverv1.RedisFailoverReference{Namespace: "source-ns", Name: "source"},
+			}
+
+			config := generateConfig()
+			mk := &mK8SService.Services{}
+			mrfs := &mRFService.RedisFailoverClient{}
+			mrfc := &mRFService.RedisFailoverCheck{}
+			mrfh := &mRFService.RedisFailoverHeal{}
+
+			mrfc.On("IsPasswordRotating", mock.Anything, rf).Once().Return(false, nil)
+			mrfc.On("IsRedisRunning", mock.Anything, rf).Once().Return(true)
+			// once to get ips for config update, once for the UpdateRedisesPods go right
+			mrfc.On("GetRedisesIPs", mock.Anything, rf).Twice().Return([]string{"0.0.0.1"}, nil)
+			mrfc.On("CheckRedisSlavesReady", mock.Anything, "0.0.0.1", rf).Once().Return(true, nil)
+			mrfc.On("GetStatefulSetUpdateRevisions", mock.Anything, rf).Once().Return(map[string]string{"rfr-test": "1"}, nil)
+			mrfc.On("GetRedisesSlavesPods", mock.Anything, rf).Once().Return([]string{}, nil)
+			mrfh.On("SetRedisCustomConfig", mock.Anything, "0.0.0.1", rf).Once().Return(nil)
+			if test.sourceMasterError {
+				mrfc.On("GetReplicaOfMaster", mock.Anything, rf).Once().Return("", "", errors.New(""))
+			} else {
+				mrfc.On("GetReplicaOfMaster", mock.Anything, rf).Once().Return("5.5.5.5", "6379", nil)
+				mrfh.On("SetExternalMasterOnAll", mock.Anything, "5.5.5.5", "6379", rf).Once().Return(nil)
+				mrfh.On("SetExternalMasterStatus", mock.Anything, "5.5.5.5", "6379", rf).Once().Return(nil)
+			}
+
+			handler := rfOperator.NewRedisFailoverHandler(config, mrfs, mrfc, mrfh, mk, metrics.Dummy, log.Dummy)
+			err := handler.CheckAndHeal(context.Background(), rf)
+
+			if test.sourceMasterError {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+			}
+			mrfc.AssertExpectations(t)
+			mrfh.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCheckAndHealSwitchover(t *testing.T) {
+	tests := []struct {
+		name        string
+		startTime   time.Time
+		expectEnded bool
+	}{
+		{
+			name:      "leaves the master to sentinel while a switchover is in flight",
+			startTime: time.Now(),
+		},
+		{
+			name:        "ends the switchover left by a stopped operator",
+			startTime:   time.Now().Add(-time.Hour),
+			expectEnded: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateRF(false, false)
+			rf.Status.Switchover = &redisfailoverv1.SwitchoverStatus{
+				ReplicaPriorities: map[string]string{"0.0.0.1": "100"},
+				StartTime:         metav1.NewTime(test.startTime),
+			}
+
+			mrfc := &mRFService.RedisFailoverCheck{}
+			mrfh := &mRFService.RedisFailoverHeal{}
+			mrfc.On("IsPasswordRotating", mock.Anything, rf).Once().Return(false, nil)
+			if test.expectEnded {
+				// the heal goes on once ended, failing stops it there
+				mrfh.On("EndSwitchover", mock.Anything, rf).Once().Return(errors.New("wrong"))
+			}
+
+			handler := rfOperator.NewRedisFailoverHandler(generateConfig(), &mRFService.RedisFailoverClient{}, mrfc, mrfh, &mK8SService.Services{}, metrics.Dummy, log.Dummy)
+			err := handler.CheckAndHeal(context.Background(), rf)
+
+			if test.expectEnded {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+			}
+			mrfc.AssertExpectations(t)
+			mrfh.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCheckAndHealPasswordRotation(t *testing.T) {
+	tests := []struct {
+		name              string
+		rotating          bool
+		phase             redisfailoverv1.PasswordRotationPhase
+		setPasswordsError bool
+		expPhase          redisfailoverv1.PasswordRotationPhase
+	}{
+		{
+			name: "no rotation",
+		},
+		{
+			name:     "rotation starts",
+			rotating: true,
+			expPhase: redisfailoverv1.PasswordRotationInProgress,
+		},
+		{
+			name:     "rotation in progress",
+			rotating: true,
+			phase:    redisfailoverv1.PasswordRotationInProgress,
+		},
+		{
+			name:     "previous password removed",
+			phase:    redisfailoverv1.PasswordRotationInProgress,
+			expPhase: redisfailoverv1.PasswordRotationCompleted,
+		},
+		{
+			name:  "rotation already completed",
+			phase: redisfailoverv1.PasswordRotationCompleted,
+		},
+		{
+			name:              "redises can't be updated",
+			rotating:          true,
+			setPasswordsError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateRF(false, false)
+			if test.phase != "" {
+				rf.Status.PasswordRotation = &redisfailoverv1.PasswordRotationStatus{Phase: test.phase}
+			}
+
+			config := generateConfig()
+			mk := &mK8SService.Services{}
+			mrfs := &mRFService.RedisFailoverClient{}
+			mrfc := &mRFService.RedisFailoverCheck{}
+			mrfh := &mRFService.RedisFailoverHeal{}
+
+			mrfc.On("IsPasswordRotating", mock.Anything, rf).Once().Return(test.rotating, nil)
+			if test.rotating || test.phase == redisfailoverv1.PasswordRotationInProgress {
+				if test.setPasswordsError {
+					mrfh.On("SetRedisPasswords", mock.Anything, rf).Once().Return(errors.New(""))
+				} else {
+					mrfh.On("SetRedisPasswords", mock.Anything, rf).Once().Return(nil)
+				}
+			}
+			if test.expPhase != "" {
+				mrfh.On("SetPasswordRotationStatus", mock.Anything, test.expPhase, mock.Anything, rf).Once().Return(nil)
+			}
+			if !test.setPasswordsError {
+				// stop the checks right after the rotation
+				mrfc.On("IsRedisRunning", mock.Anything, rf).Once().Return(false)
+			}
+
+			handler := rfOperator.NewRedisFailoverHandler(config, mrfs, mrfc, mrfh, mk, metrics.Dummy, log.Dummy)
+			err := handler.CheckAndHeal(context.Background(), rf)
+
+			if test.setPasswordsError {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+			}
+			mrfc.AssertExpectations(t)
+			mrfh.AssertExpectations(t)
 		})
 	}
 }
@@ -449,7 +756,7 @@ func TestUpdate(t *testing.T) {
 				{
 					pod: corev1.Pod{
 						ObjectMeta: metav1.ObjectMeta{
-							Name: "slave1",
+							Name: "rfr-test-1",
 							Labels: map[string]string{
 								appsv1.ControllerRevisionHashLabelKey: "10",
 							},
@@ -464,7 +771,7 @@ func TestUpdate(t *testing.T) {
 				{
 					pod: corev1.Pod{
 						ObjectMeta: metav1.ObjectMeta{
-							Name: "slave2",
+							Name: "rfr-test-2",
 							Labels: map[string]string{
 								appsv1.ControllerRevisionHashLabelKey: "10",
 							},
@@ -479,7 +786,7 @@ func TestUpdate(t *testing.T) {
 				{
 					pod: corev1.Pod{
 						ObjectMeta: metav1.ObjectMeta{
-							Name: "master",
+							Name: "rfr-test-0",
 							Labels: map[string]string{
 								appsv1.ControllerRevisionHashLabelKey: "10",
 							},
@@ -502,7 +809,7 @@ func TestUpdate(t *testing.T) {
 				{
 					pod: corev1.Pod{
 						ObjectMeta: metav1.ObjectMeta{
-							Name: "slave1",
+							Name: "rfr-test-1",
 							Labels: map[string]string{
 								appsv1.ControllerRevisionHashLabelKey: "10",
 							},
@@ -517,7 +824,7 @@ func TestUpdate(t *testing.T) {
 				{
 					pod: corev1.Pod{
 						ObjectMeta: metav1.ObjectMeta{
-							Name: "slave2",
+							Name: "rfr-test-2",
 							Labels: map[string]string{
 								appsv1.ControllerRevisionHashLabelKey: "10",
 							},
@@ -532,7 +839,7 @@ func TestUpdate(t *testing.T) {
 				{
 					pod: corev1.Pod{
 						ObjectMeta: metav1.ObjectMeta{
-							Name: "master",
+							Name: "rfr-test-0",
 							Labels: map[string]string{
 								appsv1.ControllerRevisionHashLabelKey: "10",
 							},
@@ -555,7 +862,7 @@ func TestUpdate(t *testing.T) {
 				{
 					pod: corev1.Pod{
 						ObjectMeta: metav1.ObjectMeta{
-							Name: "slave1",
+							Name: "rfr-test-1",
 							Labels: map[string]string{
 								appsv1.ControllerRevisionHashLabelKey: "10",
 							},
@@ -570,7 +877,7 @@ func TestUpdate(t *testing.T) {
 				{
 					pod: corev1.Pod{
 						ObjectMeta: metav1.ObjectMeta{
-							Name: "slave2",
+							Name: "rfr-test-2",
 							Labels: map[string]string{
 								appsv1.ControllerRevisionHashLabelKey: "10",
 							},
@@ -585,7 +892,7 @@ func TestUpdate(t *testing.T) {
 				{
 					pod: corev1.Pod{
 						ObjectMeta: metav1.ObjectMeta{
-							Name: "master",
+							Name: "rfr-test-0",
 							Labels: map[string]string{
 								appsv1.ControllerRevisionHashLabelKey: "10",
 							},
@@ -608,7 +915,7 @@ func TestUpdate(t *testing.T) {
 				{
 					pod: corev1.Pod{
 						ObjectMeta: metav1.ObjectMeta{
-							Name: "slave1",
+							Name: "rfr-test-1",
 							Labels: map[string]string{
 								appsv1.ControllerRevisionHashLabelKey: "10",
 							},
@@ -623,7 +930,7 @@ func TestUpdate(t *testing.T) {
 				{
 					pod: corev1.Pod{
 						ObjectMeta: metav1.ObjectMeta{
-							Name: "slave2",
+							Name: "rfr-test-2",
 							Labels: map[string]string{
 								appsv1.ControllerRevisionHashLabelKey: "10",
 							},
@@ -638,7 +945,7 @@ func TestUpdate(t *testing.T) {
 				{
 					pod: corev1.Pod{
 						ObjectMeta: metav1.ObjectMeta{
-							Name: "master",
+							Name: "rfr-test-0",
 							Labels: map[string]string{
 								appsv1.ControllerRevisionHashLabelKey: "1",
 							},
@@ -661,7 +968,7 @@ func TestUpdate(t *testing.T) {
 				{
 					pod: corev1.Pod{
 						ObjectMeta: metav1.ObjectMeta{
-							Name: "slave1",
+							Name: "rfr-test-1",
 							Labels: map[string]string{
 								appsv1.ControllerRevisionHashLabelKey: "10",
 							},
@@ -676,7 +983,7 @@ func TestUpdate(t *testing.T) {
 				{
 					pod: corev1.Pod{
 						ObjectMeta: metav1.ObjectMeta{
-							Name: "slave2",
+							Name: "rfr-test-2",
 							Labels: map[string]string{
 								appsv1.ControllerRevisionHashLabelKey: "10",
 							},
@@ -691,7 +998,7 @@ func TestUpdate(t *testing.T) {
 				{
 					pod: corev1.Pod{
 						ObjectMeta: metav1.ObjectMeta{
-							Name: "slave3",
+							Name: "rfr-test-3",
 							Labels: map[string]string{
 								appsv1.ControllerRevisionHashLabelKey: "10",
 							},
@@ -714,7 +1021,7 @@ func TestUpdate(t *testing.T) {
 				{
 					pod: corev1.Pod{
 						ObjectMeta: metav1.ObjectMeta{
-							Name: "slave1",
+							Name: "rfr-test-1",
 							Labels: map[string]string{
 								appsv1.ControllerRevisionHashLabelKey: "10",
 							},
@@ -729,7 +1036,7 @@ func TestUpdate(t *testing.T) {
 				{
 					pod: corev1.Pod{
 						ObjectMeta: metav1.ObjectMeta{
-							Name: "slave2",
+							Name: "rfr-test-2",
 							Labels: map[string]string{
 								appsv1.ControllerRevisionHashLabelKey: "10",
 							},
@@ -744,7 +1051,7 @@ func TestUpdate(t *testing.T) {
 				{
 					pod: corev1.Pod{
 						ObjectMeta: metav1.ObjectMeta{
-							Name: "slave3",
+							Name: "rfr-test-3",
 							Labels: map[string]string{
 								appsv1.ControllerRevisionHashLabelKey: "10",
 							},
@@ -767,7 +1074,7 @@ func TestUpdate(t *testing.T) {
 				{
 					pod: corev1.Pod{
 						ObjectMeta: metav1.ObjectMeta{
-							Name: "slave1",
+							Name: "rfr-test-1",
 							Labels: map[string]string{
 								appsv1.ControllerRevisionHashLabelKey: "10",
 							},
@@ -782,7 +1089,7 @@ func TestUpdate(t *testing.T) {
 				{
 					pod: corev1.Pod{
 						ObjectMeta: metav1.ObjectMeta{
-							Name: "slave2",
+							Name: "rfr-test-2",
 							Labels: map[string]string{
 								appsv1.ControllerRevisionHashLabelKey: "10",
 							},
@@ -797,7 +1104,7 @@ func TestUpdate(t *testing.T) {
 				{
 					pod: corev1.Pod{
 						ObjectMeta: metav1.ObjectMeta{
-							Name: "slave3",
+							Name: "rfr-test-3",
 							Labels: map[string]string{
 								appsv1.ControllerRevisionHashLabelKey: "10",
 							},
@@ -820,7 +1127,7 @@ func TestUpdate(t *testing.T) {
 				{
 					pod: corev1.Pod{
 						ObjectMeta: metav1.ObjectMeta{
-							Name: "slave1",
+							Name: "rfr-test-1",
 							Labels: map[string]string{
 								appsv1.ControllerRevisionHashLabelKey: "10",
 							},
@@ -835,7 +1142,7 @@ func TestUpdate(t *testing.T) {
 				{
 					pod: corev1.Pod{
 						ObjectMeta: metav1.ObjectMeta{
-							Name: "slave2",
+							Name: "rfr-test-2",
 							Labels: map[string]string{
 								appsv1.ControllerRevisionHashLabelKey: "10",
 							},
@@ -850,7 +1157,7 @@ func TestUpdate(t *testing.T) {
 				{
 					pod: corev1.Pod{
 						ObjectMeta: metav1.ObjectMeta{
-							Name: "slave3",
+							Name: "rfr-test-3",
 							Labels: map[string]string{
 								appsv1.ControllerRevisionHashLabelKey: "10",
 							},
@@ -879,7 +1186,7 @@ func TestUpdate(t *testing.T) {
 			mrfs := &mRFService.RedisFailoverClient{}
 
 			mrfc := &mRFService.RedisFailoverCheck{}
-			mrfc.On("GetRedisesIPs", rf).Once().Return([]string{"0.0.0.0", "0.0.0.1", "1.1.1.1"}, nil)
+			mrfc.On("GetRedisesIPs", mock.Anything, rf).Once().Return([]string{"0.0.0.0", "0.0.0.1", "1.1.1.1"}, nil)
 
 			next := true
 			if !test.bootstrapping {
@@ -887,12 +1194,12 @@ func TestUpdate(t *testing.T) {
 				if test.noMaster {
 					master = ""
 				}
-				mrfc.On("GetMasterIP", rf).Once().Return(master, nil)
+				mrfc.On("GetMasterIP", mock.Anything, rf).Once().Return(master, nil)
 			}
 
 			for _, pod := range test.pods {
 				if !pod.master {
-					mrfc.On("CheckRedisSlavesReady", pod.pod.Status.PodIP, rf).Once().Return(pod.ready, nil)
+					mrfc.On("CheckRedisSlavesReady", mock.Anything, pod.pod.Status.PodIP, rf).Once().Return(pod.ready, nil)
 				}
 				if !pod.ready {
 					next = false
@@ -902,17 +1209,17 @@ func TestUpdate(t *testing.T) {
 			mrfh := &mRFService.RedisFailoverHeal{}
 
 			if next {
-				replicas := []string{"slave1", "slave2"}
+				replicas := []string{"rfr-test-1", "rfr-test-2"}
 				if test.bootstrapping || test.noMaster {
-					replicas = append(replicas, "slave3")
+					replicas = append(replicas, "rfr-test-3")
 				}
-				mrfc.On("GetStatefulSetUpdateRevision", rf).Once().Return(test.ssVersion, nil)
-				mrfc.On("GetRedisesSlavesPods", rf).Once().Return(replicas, nil)
+				mrfc.On("GetStatefulSetUpdateRevisions", mock.Anything, rf).Once().Return(map[string]string{"rfr-test": test.ssVersion}, nil)
+				mrfc.On("GetRedisesSlavesPods", mock.Anything, rf).Once().Return(replicas, nil)
 
 				for _, pod := range test.pods {
-					mrfc.On("GetRedisRevisionHash", pod.pod.ObjectMeta.Name, rf).Once().Return(pod.pod.ObjectMeta.Labels[appsv1.ControllerRevisionHashLabelKey], nil)
+					mrfc.On("GetRedisRevisionHash", mock.Anything, pod.pod.ObjectMeta.Name, rf).Once().Return(pod.pod.ObjectMeta.Labels[appsv1.ControllerRevisionHashLabelKey], nil)
 					if pod.pod.ObjectMeta.Labels[appsv1.ControllerRevisionHashLabelKey] != test.ssVersion {
-						mrfh.On("DeletePod", pod.pod.ObjectMeta.Name, rf).Once().Return(nil)
+						mrfh.On("DeletePod", mock.Anything, pod.pod.ObjectMeta.Name, rf).Once().Return(nil)
 						if pod.master == false {
 							next = false
 							break
@@ -922,9 +1229,9 @@ func TestUpdate(t *testing.T) {
 				fmt.Printf("%v - %v\n", test.name, next)
 				if next && !test.bootstrapping {
 					if test.noMaster {
-						mrfc.On("GetRedisesMasterPod", rf).Once().Return("", errors.New(""))
+						mrfc.On("GetRedisesMasterPod", mock.Anything, rf).Once().Return("", errors.New(""))
 					} else {
-						mrfc.On("GetRedisesMasterPod", rf).Once().Return("master", nil)
+						mrfc.On("GetRedisesMasterPod", mock.Anything, rf).Once().Return("rfr-test-0", nil)
 					}
 				}
 			}
@@ -932,7 +1239,7 @@ func TestUpdate(t *testing.T) {
 			mk := &mK8SService.Services{}
 
 			handler := rfOperator.NewRedisFailoverHandler(config, mrfs, mrfc, mrfh, mk, metrics.Dummy, log.Dummy)
-			err := handler.UpdateRedisesPods(rf)
+			err := handler.UpdateRedisesPods(context.Background(), rf)
 
 			if test.errExpected {
 				assert.Error(err)
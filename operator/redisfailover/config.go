@@ -1,8 +1,16 @@
 package redisfailover
 
+import "time"
+
 // Config is the configuration for the redis operator.
 type Config struct {
 	ListenAddress string
 	MetricsPath   string
 	Concurrency   int
+	// ResyncInterval is the interval every RedisFailover is reconciled at, even when nothing changed
+	ResyncInterval time.Duration
+	// ReconcileTimeout is the maximum time a reconcile of a RedisFailover runs for, no limit when zero
+	ReconcileTimeout time.Duration
+	// Settings holds the settings of the configuration file, nil when there is none
+	Settings *SettingsStore
 }
@@ -0,0 +1,101 @@
+package redisfailover
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
+	rfservice "github.com/spotahome/redis-operator/operator/redisfailover/service"
+)
+
+// switchovers keeps the RedisFailovers the operator is switching the master of, the status only tells once the
+// informers see it
+type switchovers struct {
+	mutex   sync.Mutex
+	running map[string]bool
+}
+
+func newSwitchovers() *switchovers {
+	return &switchovers{running: map[string]bool{}}
+}
+
+// start records a switchover of the given RedisFailover is running
+func (s *switchovers) start(namespace, name string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.running[fmt.Sprintf("%s/%s", namespace, name)] = true
+}
+
+// done records the switchover of the given RedisFailover ended
+func (s *switchovers) done(namespace, name string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.running, fmt.Sprintf("%s/%s", namespace, name))
+}
+
+// isRunning returns true while the operator switches the master of the given RedisFailover
+func (s *switchovers) isRunning(namespace, name string) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.running[fmt.Sprintf("%s/%s", namespace, name)]
+}
+
+// switchoverFromDrainingNode fails the master over to an in-sync replica on a schedulable node when the node of the
+// master is cordoned or being drained, so the clients see a planned switch instead of the master going down when it
+// is evicted. The switchover runs out of the reconcile, which would otherwise be held for as long as sentinel takes.
+// Returns true when the master is being switched
+func (r *RedisFailoverHandler) switchoverFromDrainingNode(ctx context.Context, rf *redisfailoverv1.RedisFailover, sentinels []string, master string) (bool, error) {
+	if len(sentinels) == 0 {
+		return false, nil
+	}
+	draining, err := r.rfChecker.GetRedisesOnDrainingNodes(ctx, rf)
+	if err != nil || !draining[master] {
+		return false, err
+	}
+
+	redises, err := r.rfChecker.GetRedisesIPs(ctx, rf)
+	if err != nil {
+		return false, err
+	}
+	candidates := 0
+	excluded := []string{}
+	for _, rip := range redises {
+		if rip == master {
+			continue
+		}
+		if !draining[rip] && r.isSwitchoverCandidate(ctx, rip, master, rf) {
+			candidates++
+			continue
+		}
+		excluded = append(excluded, rip)
+	}
+	if candidates == 0 {
+		rfservice.Logger(ctx, r.logger, rf).Warningf("The node of master %s is being drained but no replica in sync on another node can take over", master)
+		return false, nil
+	}
+
+	rfservice.Logger(ctx, r.logger, rf).Infof("The node of master %s is being drained, switching it over", master)
+	r.switchovers.start(rf.Namespace, rf.Name)
+	go r.switchover(rf.DeepCopy(), sentinels[0], excluded)
+	return true, nil
+}
+
+// switchover runs a switchover on its own context, the one of the reconcile is over before it ends
+func (r *RedisFailoverHandler) switchover(rf *redisfailoverv1.RedisFailover, sentinel string, excluded []string) {
+	defer r.switchovers.done(rf.Namespace, rf.Name)
+	ctx := context.Background()
+	if err := r.rfHealer.SwitchoverMaster(ctx, sentinel, excluded, rf); err != nil {
+		rfservice.Logger(ctx, r.logger, rf).Errorf("Unable to switch the master over: %s", err)
+	}
+}
+
+// isSwitchoverCandidate returns true when the given replica is ready, has its link to the master up and is close
+// enough to it to be promoted
+func (r *RedisFailoverHandler) isSwitchoverCandidate(ctx context.Context, rip, master string, rf *redisfailoverv1.RedisFailover) bool {
+	if ready, err := r.rfChecker.CheckRedisSlavesReady(ctx, rip, rf); err != nil || !ready {
+		return false
+	}
+	inSync, err := r.rfChecker.CheckRedisSlaveInSync(ctx, rip, master, rf)
+	return err == nil && inSync
+}
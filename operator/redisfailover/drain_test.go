@@ -0,0 +1,105 @@
+package redisfailover
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
+	"github.com/spotahome/redis-operator/log"
+	"github.com/spotahome/redis-operator/metrics"
+	mRFService "github.com/spotahome/redis-operator/mocks/operator/redisfailover/service"
+)
+
+func TestSwitchoverFromDrainingNode(t *testing.T) {
+	const master = "0.0.0.0"
+	tests := []struct {
+		name             string
+		draining         map[string]bool
+		ready            map[string]bool
+		inSync           map[string]bool
+		expectedExcluded []string
+		expectedSwitched bool
+	}{
+		{
+			name:     "leaves the master on a schedulable node",
+			draining: map[string]bool{"1.1.1.1": true},
+		},
+		{
+			name:             "switches the master over to the replicas in sync on schedulable nodes",
+			draining:         map[string]bool{master: true, "1.1.1.1": true},
+			ready:            map[string]bool{"2.2.2.2": true, "3.3.3.3": false, "4.4.4.4": true},
+			inSync:           map[string]bool{"2.2.2.2": true, "4.4.4.4": false},
+			expectedExcluded: []string{"1.1.1.1", "3.3.3.3", "4.4.4.4"},
+			expectedSwitched: true,
+		},
+		{
+			name:     "leaves the master without a replica to take over",
+			draining: map[string]bool{master: true, "1.1.1.1": true},
+			ready:    map[string]bool{"2.2.2.2": false, "3.3.3.3": false, "4.4.4.4": true},
+			inSync:   map[string]bool{"4.4.4.4": false},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := &redisfailoverv1.RedisFailover{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "testns"}}
+			mrfc := &mRFService.RedisFailoverCheck{}
+			mrfc.On("GetRedisesOnDrainingNodes", mock.Anything, rf).Once().Return(test.draining, nil)
+			if test.draining[master] {
+				mrfc.On("GetRedisesIPs", mock.Anything, rf).Once().Return([]string{master, "1.1.1.1", "2.2.2.2", "3.3.3.3", "4.4.4.4"}, nil)
+			}
+			for ip, ready := range test.ready {
+				mrfc.On("CheckRedisSlavesReady", mock.Anything, ip, rf).Once().Return(ready, nil)
+			}
+			for ip, inSync := range test.inSync {
+				mrfc.On("CheckRedisSlaveInSync", mock.Anything, ip, master, rf).Once().Return(inSync, nil)
+			}
+			mrfh := &mRFService.RedisFailoverHeal{}
+			if test.expectedSwitched {
+				mrfh.On("SwitchoverMaster", mock.Anything, "10.0.0.1", test.expectedExcluded, rf).Once().Return(nil)
+			}
+
+			handler := NewRedisFailoverHandler(Config{}, &mRFService.RedisFailoverClient{}, mrfc, mrfh, nil, metrics.Dummy, log.Dummy)
+			switched, err := handler.switchoverFromDrainingNode(context.Background(), rf, []string{"10.0.0.1"}, master)
+
+			assert.NoError(err)
+			assert.Equal(test.expectedSwitched, switched)
+			assert.Eventually(func() bool { return !handler.switchovers.isRunning(rf.Namespace, rf.Name) }, time.Second, 10*time.Millisecond)
+			mrfc.AssertExpectations(t)
+			mrfh.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSwitchoverFromDrainingNodeError(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := &redisfailoverv1.RedisFailover{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "testns"}}
+	mrfc := &mRFService.RedisFailoverCheck{}
+	mrfc.On("GetRedisesOnDrainingNodes", mock.Anything, rf).Once().Return(map[string]bool{"0.0.0.0": true}, nil)
+	mrfc.On("GetRedisesIPs", mock.Anything, rf).Once().Return([]string{"0.0.0.0", "1.1.1.1"}, nil)
+	mrfc.On("CheckRedisSlavesReady", mock.Anything, "1.1.1.1", rf).Once().Return(true, nil)
+	mrfc.On("CheckRedisSlaveInSync", mock.Anything, "1.1.1.1", "0.0.0.0", rf).Once().Return(true, nil)
+	mrfh := &mRFService.RedisFailoverHeal{}
+	release := make(chan time.Time)
+	mrfh.On("SwitchoverMaster", mock.Anything, "10.0.0.1", mock.Anything, rf).Once().WaitUntil(release).Return(errors.New("wrong"))
+
+	handler := NewRedisFailoverHandler(Config{}, &mRFService.RedisFailoverClient{}, mrfc, mrfh, nil, metrics.Dummy, log.Dummy)
+	switched, err := handler.switchoverFromDrainingNode(context.Background(), rf, []string{"10.0.0.1"}, "0.0.0.0")
+
+	// the reconcile isn't held while sentinel switches the master, nor fails with the switchover
+	assert.NoError(err)
+	assert.True(switched)
+	assert.True(handler.switchovers.isRunning(rf.Namespace, rf.Name))
+	close(release)
+	assert.Eventually(func() bool { return !handler.switchovers.isRunning(rf.Namespace, rf.Name) }, time.Second, 10*time.Millisecond)
+	mrfh.AssertExpectations(t)
+}
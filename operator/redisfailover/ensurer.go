@@ -1,6 +1,8 @@
 package redisfailover
 
 import (
+	"context"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
@@ -8,47 +10,70 @@ import (
 )
 
 // Ensure is called to ensure all of the resources associated with a RedisFailover are created
-func (w *RedisFailoverHandler) Ensure(rf *redisfailoverv1.RedisFailover, labels map[string]string, or []metav1.OwnerReference, metricsClient metrics.Recorder) error {
-	if rf.Spec.Redis.Exporter.Enabled {
-		if err := w.rfService.EnsureRedisService(rf, labels, or); err != nil {
+func (w *RedisFailoverHandler) Ensure(ctx context.Context, rf *redisfailoverv1.RedisFailover, labels map[string]string, or []metav1.OwnerReference, metricsClient metrics.Recorder) error {
+	// The redis service publishes the exporter and gives the pods the hostnames they announce
+	if rf.Spec.Redis.Exporter.Enabled || rf.Spec.AnnounceHostnames {
+		if err := w.phase(ctx, rf, "EnsureRedisService", func(ctx context.Context) error { return w.rfService.EnsureRedisService(ctx, rf, labels, or) }); err != nil {
 			return err
 		}
 	} else {
-		if err := w.rfService.EnsureNotPresentRedisService(rf); err != nil {
+		if err := w.phase(ctx, rf, "EnsureNotPresentRedisService", func(ctx context.Context) error { return w.rfService.EnsureNotPresentRedisService(ctx, rf) }); err != nil {
 			return err
 		}
 	}
 
+	if err := w.phase(ctx, rf, "EnsureRedisMasterService", func(ctx context.Context) error { return w.rfService.EnsureRedisMasterService(ctx, rf, labels, or) }); err != nil {
+		return err
+	}
+	if err := w.phase(ctx, rf, "EnsureRedisReplicasService", func(ctx context.Context) error { return w.rfService.EnsureRedisReplicasService(ctx, rf, labels, or) }); err != nil {
+		return err
+	}
+
+	if err := w.phase(ctx, rf, "EnsureExternalAccessServices", func(ctx context.Context) error { return w.rfService.EnsureExternalAccessServices(ctx, rf, labels, or) }); err != nil {
+		return err
+	}
+
+	if err := w.phase(ctx, rf, "EnsureNetworkPolicies", func(ctx context.Context) error { return w.rfService.EnsureNetworkPolicies(ctx, rf, labels, or) }); err != nil {
+		return err
+	}
+
 	sentinelsAllowed := rf.SentinelsAllowed()
 	if sentinelsAllowed {
-		if err := w.rfService.EnsureSentinelService(rf, labels, or); err != nil {
+		if err := w.phase(ctx, rf, "EnsureSentinelService", func(ctx context.Context) error { return w.rfService.EnsureSentinelService(ctx, rf, labels, or) }); err != nil {
 			return err
 		}
-		if err := w.rfService.EnsureSentinelConfigMap(rf, labels, or); err != nil {
+		if err := w.phase(ctx, rf, "EnsureSentinelConfigMap", func(ctx context.Context) error { return w.rfService.EnsureSentinelConfigMap(ctx, rf, labels, or) }); err != nil {
 			return err
 		}
 	}
 
-	if err := w.rfService.EnsureRedisShutdownConfigMap(rf, labels, or); err != nil {
+	if err := w.phase(ctx, rf, "EnsureRedisShutdownConfigMap", func(ctx context.Context) error { return w.rfService.EnsureRedisShutdownConfigMap(ctx, rf, labels, or) }); err != nil {
+		return err
+	}
+	if err := w.phase(ctx, rf, "EnsureRedisReadinessConfigMap", func(ctx context.Context) error { return w.rfService.EnsureRedisReadinessConfigMap(ctx, rf, labels, or) }); err != nil {
 		return err
 	}
-	if err := w.rfService.EnsureRedisReadinessConfigMap(rf, labels, or); err != nil {
+	if err := w.phase(ctx, rf, "EnsureRedisConfigMap", func(ctx context.Context) error { return w.rfService.EnsureRedisConfigMap(ctx, rf, labels, or) }); err != nil {
 		return err
 	}
-	if err := w.rfService.EnsureRedisConfigMap(rf, labels, or); err != nil {
+	if err := w.phase(ctx, rf, "EnsureRedisZonesUnchanged", func(ctx context.Context) error { return w.rfService.EnsureRedisZonesUnchanged(ctx, rf) }); err != nil {
 		return err
 	}
-	if err := w.rfService.EnsureRedisStatefulset(rf, labels, or); err != nil {
+	if err := w.phase(ctx, rf, "EnsureRedisStatefulset", func(ctx context.Context) error { return w.rfService.EnsureRedisStatefulset(ctx, rf, labels, or) }); err != nil {
 		return err
 	}
 
 	if sentinelsAllowed {
-		if err := w.rfService.EnsureSentinelStatefulset(rf, labels, or); err != nil {
+		if err := w.phase(ctx, rf, "EnsureSentinelStatefulset", func(ctx context.Context) error { return w.rfService.EnsureSentinelStatefulset(ctx, rf, labels, or) }); err != nil {
 			return err
 		}
 	}
 
-	if err := w.rfService.EnsurePredixyAllResources(rf, labels, or); err != nil {
+	if err := w.phase(ctx, rf, "EnsureProxyAllResources", func(ctx context.Context) error { return w.rfService.EnsureProxyAllResources(ctx, rf, labels, or) }); err != nil {
+		return err
+	}
+
+	if err := w.phase(ctx, rf, "EnsureMonitoring", func(ctx context.Context) error { return w.rfService.EnsureMonitoring(ctx, rf, labels, or) }); err != nil {
 		return err
 	}
 
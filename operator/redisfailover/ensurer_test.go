@@ -1,7 +1,10 @@
 package redisfailover_test
 
 import (
+	"context"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -62,6 +65,7 @@ func TestEnsure(t *testing.T) {
 	tests := []struct {
 		name                        string
 		exporter                    bool
+		announceHostnames           bool
 		bootstrapping               bool
 		bootstrappingAllowSentinels bool
 	}{
@@ -77,6 +81,13 @@ func TestEnsure(t *testing.T) {
 			bootstrapping:               false,
 			bootstrappingAllowSentinels: false,
 		},
+		{
+			name:                        "Call everything, announce hostnames without exporter",
+			exporter:                    false,
+			announceHostnames:           true,
+			bootstrapping:               false,
+			bootstrappingAllowSentinels: false,
+		},
 		{
 			name:                        "Only ensure Redis when bootstrapping",
 			exporter:                    false,
@@ -96,6 +107,7 @@ func TestEnsure(t *testing.T) {
 			assert := assert.New(t)
 
 			rf := generateRF(test.exporter, test.bootstrapping)
+			rf.Spec.AnnounceHostnames = test.announceHostnames
 			if test.bootstrapping {
 				rf.Spec.BootstrapNode.AllowSentinels = test.bootstrappingAllowSentinels
 			}
@@ -105,29 +117,80 @@ func TestEnsure(t *testing.T) {
 			mrfc := &mRFService.RedisFailoverCheck{}
 			mrfh := &mRFService.RedisFailoverHeal{}
 			mrfs := &mRFService.RedisFailoverClient{}
-			if test.exporter {
-				mrfs.On("EnsureRedisService", rf, mock.Anything, mock.Anything).Once().Return(nil)
+			if test.exporter || test.announceHostnames {
+				mrfs.On("EnsureRedisService", mock.Anything, rf, mock.Anything, mock.Anything).Once().Return(nil)
 			} else {
-				mrfs.On("EnsureNotPresentRedisService", rf).Once().Return(nil)
+				mrfs.On("EnsureNotPresentRedisService", mock.Anything, rf).Once().Return(nil)
 			}
 
+			mrfs.On("EnsureRedisMasterService", mock.Anything, rf, mock.Anything, mock.Anything).Once().Return(nil)
+			mrfs.On("EnsureRedisReplicasService", mock.Anything, rf, mock.Anything, mock.Anything).Once().Return(nil)
+			mrfs.On("EnsureExternalAccessServices", mock.Anything, rf, mock.Anything, mock.Anything).Once().Return(nil)
+			mrfs.On("EnsureNetworkPolicies", mock.Anything, rf, mock.Anything, mock.Anything).Once().Return(nil)
+
 			if !test.bootstrapping || test.bootstrappingAllowSentinels {
-				mrfs.On("EnsureSentinelService", rf, mock.Anything, mock.Anything).Once().Return(nil)
-				mrfs.On("EnsureSentinelConfigMap", rf, mock.Anything, mock.Anything).Once().Return(nil)
-				mrfs.On("EnsureSentinelStatefulset", rf, mock.Anything, mock.Anything).Once().Return(nil)
+				mrfs.On("EnsureSentinelService", mock.Anything, rf, mock.Anything, mock.Anything).Once().Return(nil)
+				mrfs.On("EnsureSentinelConfigMap", mock.Anything, rf, mock.Anything, mock.Anything).Once().Return(nil)
+				mrfs.On("EnsureSentinelStatefulset", mock.Anything, rf, mock.Anything, mock.Anything).Once().Return(nil)
 			}
 
-			mrfs.On("EnsureRedisConfigMap", rf, mock.Anything, mock.Anything).Once().Return(nil)
-			mrfs.On("EnsureRedisShutdownConfigMap", rf, mock.Anything, mock.Anything).Once().Return(nil)
-			mrfs.On("EnsureRedisReadinessConfigMap", rf, mock.Anything, mock.Anything).Once().Return(nil)
-			mrfs.On("EnsureRedisStatefulset", rf, mock.Anything, mock.Anything).Once().Return(nil)
+			mrfs.On("EnsureRedisConfigMap", mock.Anything, rf, mock.Anything, mock.Anything).Once().Return(nil)
+			mrfs.On("EnsureRedisShutdownConfigMap", mock.Anything, rf, mock.Anything, mock.Anything).Once().Return(nil)
+			mrfs.On("EnsureRedisReadinessConfigMap", mock.Anything, rf, mock.Anything, mock.Anything).Once().Return(nil)
+			mrfs.On("EnsureRedisZonesUnchanged", mock.Anything, rf).Once().Return(nil)
+			mrfs.On("EnsureRedisStatefulset", mock.Anything, rf, mock.Anything, mock.Anything).Once().Return(nil)
+			mrfs.On("EnsureProxyAllResources", mock.Anything, rf, mock.Anything, mock.Anything).Once().Return(nil)
+			mrfs.On("EnsureMonitoring", mock.Anything, rf, mock.Anything, mock.Anything).Once().Return(nil)
 
 			// Create the Kops client and call the valid logic.
 			handler := rfOperator.NewRedisFailoverHandler(config, mrfs, mrfc, mrfh, mk, metrics.Dummy, log.Dummy)
-			err := handler.Ensure(rf, map[string]string{}, []metav1.OwnerReference{}, metrics.Dummy)
+			err := handler.Ensure(context.Background(), rf, map[string]string{}, []metav1.OwnerReference{}, metrics.Dummy)
 
 			assert.NoError(err)
 			mrfs.AssertExpectations(t)
 		})
 	}
 }
+
+type phaseRecorder struct {
+	metrics.Recorder
+	phases []string
+}
+
+func (p *phaseRecorder) RecordReconcilePhase(_ string, _ string, phase string, _ time.Duration) {
+	p.phases = append(p.phases, phase)
+}
+
+func TestEnsurePhases(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF(false, false)
+	mrfs := &mRFService.RedisFailoverClient{}
+	mrfs.On("EnsureNotPresentRedisService", mock.Anything, rf).Once().Return(nil)
+	for _, ensure := range []string{"EnsureRedisMasterService", "EnsureRedisReplicasService", "EnsureExternalAccessServices", "EnsureNetworkPolicies", "EnsureSentinelService", "EnsureSentinelConfigMap", "EnsureRedisShutdownConfigMap", "EnsureRedisReadinessConfigMap", "EnsureRedisConfigMap"} {
+		mrfs.On(ensure, mock.Anything, rf, mock.Anything, mock.Anything).Once().Return(nil)
+	}
+	mrfs.On("EnsureRedisZonesUnchanged", mock.Anything, rf).Once().Return(nil)
+	mrfs.On("EnsureRedisStatefulset", mock.Anything, rf, mock.Anything, mock.Anything).Once().Return(errors.New("wrong"))
+
+	recorder := &phaseRecorder{Recorder: metrics.Dummy}
+	handler := rfOperator.NewRedisFailoverHandler(generateConfig(), mrfs, &mRFService.RedisFailoverCheck{}, &mRFService.RedisFailoverHeal{}, &mK8SService.Services{}, recorder, log.Dummy)
+	err := handler.Ensure(context.Background(), rf, map[string]string{}, []metav1.OwnerReference{}, recorder)
+
+	assert.Error(err)
+	assert.Equal([]string{
+		"EnsureNotPresentRedisService",
+		"EnsureRedisMasterService",
+		"EnsureRedisReplicasService",
+		"EnsureExternalAccessServices",
+		"EnsureNetworkPolicies",
+		"EnsureSentinelService",
+		"EnsureSentinelConfigMap",
+		"EnsureRedisShutdownConfigMap",
+		"EnsureRedisReadinessConfigMap",
+		"EnsureRedisConfigMap",
+		"EnsureRedisZonesUnchanged",
+		"EnsureRedisStatefulset",
+	}, recorder.phases)
+	mrfs.AssertExpectations(t)
+}
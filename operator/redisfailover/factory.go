@@ -13,6 +13,7 @@ import (
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
 
+	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
 	"github.com/spotahome/redis-operator/log"
 	"github.com/spotahome/redis-operator/metrics"
 	rfservice "github.com/spotahome/redis-operator/operator/redisfailover/service"
@@ -28,7 +29,7 @@ const (
 
 // New will create an operator that is responsible of managing all the required stuff
 // to create redis failovers.
-func New(cfg Config, k8sService k8s.Services, k8sClient kubernetes.Interface, lockNamespace string, redisClient redis.Client, kooperMetricsRecorder metrics.Recorder, logger log.Logger) (controller.Controller, error) {
+func New(cfg Config, k8sService k8s.Services, k8sClient kubernetes.Interface, lockNamespace string, redisClient redis.Client, kooperMetricsRecorder metrics.Recorder, status *Status, logger log.Logger) (controller.Controller, error) {
 	// Create internal services.
 	rfService := rfservice.NewRedisFailoverKubeClient(k8sService, logger, kooperMetricsRecorder)
 	rfChecker := rfservice.NewRedisFailoverChecker(k8sService, redisClient, logger, kooperMetricsRecorder)
@@ -36,26 +37,66 @@ func New(cfg Config, k8sService k8s.Services, k8sClient kubernetes.Interface, lo
 
 	// Create the handlers.
 	rfHandler := NewRedisFailoverHandler(cfg, rfService, rfChecker, rfHealer, k8sService, kooperMetricsRecorder, logger)
-	rfRetriever := NewRedisFailoverRetriever(k8sService)
+	rfRetriever := newOwnedResourcesRetriever(NewRedisFailoverRetriever(k8sService), k8sService, k8sClient, ownedResourcesDebounce, logger)
+	rfRetriever.resyncInterval = func() time.Duration {
+		if interval := cfg.Settings.Get().ResyncInterval.Duration; interval > 0 {
+			return interval
+		}
+		if cfg.ResyncInterval > 0 {
+			return cfg.ResyncInterval
+		}
+		return resync
+	}
+	status.waitForSync(rfRetriever.synced...)
+	subscribers := newSentinelSubscribers(k8sService, rfChecker, rfHealer, redisClient, rfRetriever.reconcile, kooperMetricsRecorder, logger)
+	subscribers.masterLosses = rfHandler.masterLosses
+	subscribers.switchovers = rfHandler.switchovers
+	rfHandler.topologies = newTopologies(k8sService, rfChecker, kooperMetricsRecorder, logger)
+	status.serveTopologies(rfHandler.topologies)
+	handler := controller.HandlerFunc(func(ctx context.Context, obj runtime.Object) error {
+		err := rfHandler.Handle(ctx, obj)
+		if rf, ok := obj.(*redisfailoverv1.RedisFailover); ok {
+			subscribers.ensure(rf)
+		}
+		return err
+	})
 
 	kooperLogger := kooperlogger{Logger: logger.WithField("operator", "redisfailover")}
 	// Leader election service.
-	leSVC, err := leaderelection.NewDefault(lockKey, lockNamespace, k8sClient, kooperLogger)
+	leSVC, err := leaderelection.New(lockKey, lockNamespace, cfg.Settings.Get().lockConfig(), k8sClient, kooperLogger)
 	if err != nil {
 		return nil, err
 	}
 
 	// Create our controller.
-	return controller.New(&controller.Config{
-		Handler:           rfHandler,
+	ctrl, err := controller.New(&controller.Config{
+		Handler:           handler,
 		Retriever:         rfRetriever,
-		LeaderElector:     leSVC,
+		LeaderElector:     status.leaderElector(leSVC),
 		MetricsRecorder:   kooperMetricsRecorder,
 		Logger:            kooperLogger,
 		Name:              "redisfailover",
-		ResyncInterval:    resync,
+		DisableResync:     true, // the retriever resyncs, at an interval that can change
 		ConcurrentWorkers: cfg.Concurrency,
 	})
+	if err != nil {
+		return nil, err
+	}
+	return subscribedController{Controller: ctrl, subscribers: subscribers, retriever: rfRetriever}, nil
+}
+
+// subscribedController stops the sentinel subscribers and the informers of the retriever when the controller stops,
+// as it happens when the leadership is lost
+type subscribedController struct {
+	controller.Controller
+	subscribers *sentinelSubscribers
+	retriever   *ownedResourcesRetriever
+}
+
+func (c subscribedController) Run(ctx context.Context) error {
+	defer c.retriever.stop()
+	defer c.subscribers.stopAll()
+	return c.Controller.Run(ctx)
 }
 
 func NewRedisFailoverRetriever(cli k8s.Services) controller.Retriever {
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"regexp"
+	"strings"
+	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -37,56 +39,110 @@ type RedisFailoverHandler struct {
 	rfHealer   rfservice.RedisFailoverHeal
 	mClient    metrics.Recorder
 	logger     log.Logger
+	// masterLosses is shared with the sentinel subscribers, which see the master lost first
+	masterLosses *masterLosses
+	switchovers  *switchovers
+	// topologies keeps the topology got at the end of every reconcile, none are kept when nil
+	topologies *topologies
 }
 
 // NewRedisFailoverHandler returns a new RF handler
 func NewRedisFailoverHandler(config Config, rfService rfservice.RedisFailoverClient, rfChecker rfservice.RedisFailoverCheck, rfHealer rfservice.RedisFailoverHeal, k8sservice k8s.Service, mClient metrics.Recorder, logger log.Logger) *RedisFailoverHandler {
 	return &RedisFailoverHandler{
-		config:     config,
-		rfService:  rfService,
-		rfChecker:  rfChecker,
-		rfHealer:   rfHealer,
-		mClient:    mClient,
-		k8sservice: k8sservice,
-		logger:     logger,
+		config:       config,
+		rfService:    rfService,
+		rfChecker:    rfChecker,
+		rfHealer:     rfHealer,
+		mClient:      mClient,
+		k8sservice:   k8sservice,
+		logger:       logger,
+		masterLosses: newMasterLosses(),
+		switchovers:  newSwitchovers(),
 	}
 }
 
 // Handle will ensure the redis failover is in the expected state.
-func (r *RedisFailoverHandler) Handle(_ context.Context, obj runtime.Object) error {
+func (r *RedisFailoverHandler) Handle(ctx context.Context, obj runtime.Object) (err error) {
 	rf, ok := obj.(*redisfailoverv1.RedisFailover)
 	if !ok {
 		return fmt.Errorf("can't handle the received object: not a redisfailover")
 	}
 
-	if err := rf.Validate(); err != nil {
+	ctx, endReconcile := rfservice.StartReconcile(ctx, rf)
+	defer func() {
+		endReconcile(err)
+	}()
+	if r.config.ReconcileTimeout > 0 {
+		// bounds the calls to the redises and sentinels, a hung one doesn't block the worker for longer
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.config.ReconcileTimeout)
+		defer cancel()
+	}
+	logger := rfservice.Logger(ctx, r.logger, rf)
+	if level := rf.LogLevel(); level != "" {
+		if _, err := log.WithLevel(r.logger, log.Level(strings.ToLower(level))); err != nil {
+			logger.Warningf("Ignoring the %s annotation: %s", redisfailoverv1.LogLevelAnnotation, err)
+		}
+	}
+
+	if rf.Paused() {
+		logger.Infof("Redisfailover paused, skipping it")
+		return nil
+	}
+
+	settings := r.config.Settings.Get()
+	settings.applyDefaults(rf)
+	if err := r.phase(ctx, rf, "Validate", func(context.Context) error { return rf.Validate() }); err != nil {
 		r.mClient.SetClusterError(rf.Namespace, rf.Name)
 		return err
 	}
+	settings.rewriteImages(rf)
 
 	// Create owner refs so the objects manager by this handler have ownership to the
 	// received RF.
 	oRefs := r.createOwnerReferences(rf)
 
 	// Create the labels every object derived from this need to have.
-	labels := r.getLabels(rf)
+	labels := r.getLabels(ctx, rf)
 
-	if err := r.Ensure(rf, labels, oRefs, r.mClient); err != nil {
+	if err := r.phase(ctx, rf, "Ensure", func(ctx context.Context) error { return r.Ensure(ctx, rf, labels, oRefs, r.mClient) }); err != nil {
 		r.mClient.SetClusterError(rf.Namespace, rf.Name)
 		return err
 	}
 
-	if err := r.CheckAndHeal(rf); err != nil {
+	if err := r.phase(ctx, rf, "CheckAndHeal", func(ctx context.Context) error { return r.CheckAndHeal(ctx, rf) }); err != nil {
 		r.mClient.SetClusterError(rf.Namespace, rf.Name)
 		return err
 	}
 
+	// The topology is got within the reconcile, once the redisfailover is checked and healed
+	if r.topologies != nil {
+		err := r.phase(ctx, rf, "RecordTopology", func(ctx context.Context) error {
+			_, err := r.topologies.record(ctx, rf)
+			return err
+		})
+		if err != nil {
+			logger.Debugf("Unable to get the topology: %s", err)
+		}
+	}
+
 	r.mClient.SetClusterOK(rf.Namespace, rf.Name)
 	return nil
 }
 
+// phase runs a phase of the reconcile of the given RedisFailover, recording its duration and tracing it. The phase
+// runs with the context holding its span
+func (r *RedisFailoverHandler) phase(ctx context.Context, rf *redisfailoverv1.RedisFailover, name string, run func(context.Context) error) error {
+	ctx, end := rfservice.StartSpan(ctx, name)
+	start := time.Now()
+	err := run(ctx)
+	r.mClient.RecordReconcilePhase(rf.Namespace, rf.Name, name, time.Since(start))
+	end(err)
+	return err
+}
+
 // getLabels merges the labels (dynamic and operator static ones).
-func (r *RedisFailoverHandler) getLabels(rf *redisfailoverv1.RedisFailover) map[string]string {
+func (r *RedisFailoverHandler) getLabels(ctx context.Context, rf *redisfailoverv1.RedisFailover) map[string]string {
 	dynLabels := map[string]string{
 		rfLabelNameKey: rf.Name,
 	}
@@ -97,7 +153,7 @@ func (r *RedisFailoverHandler) getLabels(rf *redisfailoverv1.RedisFailover) map[
 		for _, regex := range rf.Spec.LabelWhitelist {
 			compiledRegexp, err := regexp.Compile(regex)
 			if err != nil {
-				r.logger.Errorf("Unable to compile label whitelist regex '%s', ignoring it.", regex)
+				rfservice.Logger(ctx, r.logger, rf).Errorf("Unable to compile label whitelist regex '%s', ignoring it.", regex)
 				continue
 			}
 			for labelKey, labelValue := range rf.Labels {
@@ -0,0 +1,31 @@
+package redisfailover_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
+	"github.com/spotahome/redis-operator/log"
+	"github.com/spotahome/redis-operator/metrics"
+	mRFService "github.com/spotahome/redis-operator/mocks/operator/redisfailover/service"
+	mK8SService "github.com/spotahome/redis-operator/mocks/service/k8s"
+	rfOperator "github.com/spotahome/redis-operator/operator/redisfailover"
+)
+
+func TestHandlePaused(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF(false, false)
+	rf.Annotations = map[string]string{redisfailoverv1.PausedAnnotation: "true"}
+
+	// the mocks fail on any call, nothing must be ensured nor healed
+	ms := &mK8SService.Services{}
+	mrfs := &mRFService.RedisFailoverClient{}
+	mrfc := &mRFService.RedisFailoverCheck{}
+	mrfh := &mRFService.RedisFailoverHeal{}
+
+	handler := rfOperator.NewRedisFailoverHandler(generateConfig(), mrfs, mrfc, mrfh, ms, metrics.Dummy, log.DummyLogger{})
+	assert.NoError(handler.Handle(context.TODO(), rf))
+}
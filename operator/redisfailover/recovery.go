@@ -0,0 +1,61 @@
+package redisfailover
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// masterLosses keeps the time every RedisFailover lost its master at, until the operator finds a single master again
+type masterLosses struct {
+	mutex sync.Mutex
+	// since is the time the master was lost at, by namespace and name
+	since map[string]time.Time
+	// seen are the RedisFailovers the operator saw a master of, the ones without never had it, as on their first boot
+	seen map[string]bool
+}
+
+func newMasterLosses() *masterLosses {
+	return &masterLosses{
+		since: map[string]time.Time{},
+		seen:  map[string]bool{},
+	}
+}
+
+// lost records the master of the given RedisFailover was lost at the given time, unless it was lost earlier. A
+// RedisFailover the operator never saw a master of only has one when sentinel saw it
+func (m *masterLosses) lost(namespace, name string, at time.Time, bySentinel bool) bool {
+	key := fmt.Sprintf("%s/%s", namespace, name)
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if !m.seen[key] && !bySentinel {
+		return false
+	}
+	if since, ok := m.since[key]; !ok || at.Before(since) {
+		m.since[key] = at
+	}
+	return true
+}
+
+// found records the given RedisFailover has a single master, and returns the time taken to get it when it was lost
+func (m *masterLosses) found(namespace, name string) (time.Duration, bool) {
+	key := fmt.Sprintf("%s/%s", namespace, name)
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.seen[key] = true
+	since, ok := m.since[key]
+	if !ok {
+		return 0, false
+	}
+	delete(m.since, key)
+	return time.Since(since), true
+}
+
+// forget drops what is kept of the given RedisFailover, once it is deleted
+func (m *masterLosses) forget(namespace, name string) {
+	key := fmt.Sprintf("%s/%s", namespace, name)
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	delete(m.since, key)
+	delete(m.seen, key)
+}
@@ -0,0 +1,40 @@
+package redisfailover
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMasterLosses(t *testing.T) {
+	assert := assert.New(t)
+
+	losses := newMasterLosses()
+
+	// the redises have no master on their first boot
+	assert.False(losses.lost("testns", "test", time.Now(), false))
+	_, ok := losses.found("testns", "test")
+	assert.False(ok)
+
+	// once a master was seen, losing it is recorded from the earliest time
+	assert.True(losses.lost("testns", "test", time.Now().Add(-time.Minute), false))
+	assert.True(losses.lost("testns", "test", time.Now(), false))
+	recovery, ok := losses.found("testns", "test")
+	assert.True(ok)
+	assert.GreaterOrEqual(recovery, time.Minute)
+	_, ok = losses.found("testns", "test")
+	assert.False(ok)
+
+	// sentinel saw a master before the operator did
+	assert.True(losses.lost("testns", "other", time.Now(), true))
+	_, ok = losses.found("testns", "other")
+	assert.True(ok)
+
+	// a deleted redisfailover is forgotten, its master isn't seen anymore
+	assert.True(losses.lost("testns", "other", time.Now(), false))
+	losses.forget("testns", "other")
+	assert.False(losses.lost("testns", "other", time.Now(), false))
+	_, ok = losses.found("testns", "other")
+	assert.False(ok)
+}
@@ -0,0 +1,316 @@
+package redisfailover
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/spotahome/kooper/v2/controller"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+
+	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
+	"github.com/spotahome/redis-operator/log"
+	rfservice "github.com/spotahome/redis-operator/operator/redisfailover/service"
+	"github.com/spotahome/redis-operator/service/k8s"
+)
+
+const (
+	// ownedResourcesDebounce is the time the changes on the resources of a RedisFailover are gathered
+	// before reconciling it, so a rollout doesn't trigger a reconcile for every pod it touches
+	ownedResourcesDebounce = 2 * time.Second
+	// ownedResourcesEventsBuffer is the number of pending reconciles kept while the RedisFailover watch is restarted,
+	// the ones beyond it are left to the resync
+	ownedResourcesEventsBuffer = 100
+	// resyncCheckPeriod is the period the resync interval is checked at, so a change of it applies right away
+	resyncCheckPeriod = time.Second
+	// podNodeIndex indexes the pods by the node they run on
+	podNodeIndex = "nodeName"
+)
+
+// ownedResourcesRetriever retrieves the RedisFailovers, and emits a modification of a RedisFailover when the pods,
+// statefulsets or deployments labelled with its name change in a way it has to react to, so a master going away
+// is handled right away and not on the next resync. The same goes for the nodes of its pods being cordoned or
+// drained, so its master is switched over before being evicted. When resyncInterval is set, every RedisFailover
+// is modified at the interval it returns
+type ownedResourcesRetriever struct {
+	controller.Retriever
+	cli         k8s.Services
+	factory     informers.SharedInformerFactory
+	nodeFactory informers.SharedInformerFactory
+	debounce    time.Duration
+	logger      log.Logger
+	synced      []cache.InformerSynced
+	startOnce   sync.Once
+	stopOnce    sync.Once
+	// stopC stops the informers and the resync, it is closed once the controller stops
+	stopC             chan struct{}
+	resyncInterval    func() time.Duration
+	resyncCheckPeriod time.Duration
+
+	mutex   sync.Mutex
+	pending map[string]bool
+	events  chan watch.Event
+}
+
+// NewOwnedResourcesRetriever wraps the given RedisFailover retriever so the RedisFailovers are also reconciled
+// when the resources they own change
+func NewOwnedResourcesRetriever(rfRetriever controller.Retriever, cli k8s.Services, k8sClient kubernetes.Interface, debounce time.Duration, logger log.Logger) controller.Retriever {
+	return newOwnedResourcesRetriever(rfRetriever, cli, k8sClient, debounce, logger)
+}
+
+func newOwnedResourcesRetriever(rfRetriever controller.Retriever, cli k8s.Services, k8sClient kubernetes.Interface, debounce time.Duration, logger log.Logger) *ownedResourcesRetriever {
+	factory := informers.NewSharedInformerFactoryWithOptions(k8sClient, 0, informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+		options.LabelSelector = rfLabelNameKey
+	}))
+	r := &ownedResourcesRetriever{
+		Retriever:         rfRetriever,
+		cli:               cli,
+		factory:           factory,
+		nodeFactory:       informers.NewSharedInformerFactory(k8sClient, 0),
+		debounce:          debounce,
+		logger:            logger,
+		resyncCheckPeriod: resyncCheckPeriod,
+		pending:           map[string]bool{},
+		events:            make(chan watch.Event, ownedResourcesEventsBuffer),
+		stopC:             make(chan struct{}),
+	}
+
+	podInformer := factory.Core().V1().Pods().Informer()
+	statefulSetInformer := factory.Apps().V1().StatefulSets().Informer()
+	deploymentInformer := factory.Apps().V1().Deployments().Informer()
+	nodeInformer := r.nodeFactory.Core().V1().Nodes().Informer()
+	r.synced = []cache.InformerSynced{podInformer.HasSynced, statefulSetInformer.HasSynced, deploymentInformer.HasSynced, nodeInformer.HasSynced}
+
+	_ = podInformer.AddIndexers(cache.Indexers{podNodeIndex: func(obj interface{}) ([]string, error) {
+		pod, ok := obj.(*corev1.Pod)
+		if !ok || pod.Spec.NodeName == "" {
+			return nil, nil
+		}
+		return []string{pod.Spec.NodeName}, nil
+	}})
+
+	podInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(old, new interface{}) {
+			if podChanged(old.(*corev1.Pod), new.(*corev1.Pod)) {
+				r.enqueue(new)
+			}
+		},
+		DeleteFunc: r.enqueue,
+	})
+	statefulSetInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(old, new interface{}) {
+			if statefulSetChanged(old.(*appsv1.StatefulSet), new.(*appsv1.StatefulSet)) {
+				r.enqueue(new)
+			}
+		},
+		DeleteFunc: r.enqueue,
+	})
+	deploymentInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(old, new interface{}) {
+			if deploymentChanged(old.(*appsv1.Deployment), new.(*appsv1.Deployment)) {
+				r.enqueue(new)
+			}
+		},
+		DeleteFunc: r.enqueue,
+	})
+	nodeInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(old, new interface{}) {
+			node := new.(*corev1.Node)
+			if rfservice.IsNodeDraining(old.(*corev1.Node)) || !rfservice.IsNodeDraining(node) {
+				return
+			}
+			pods, err := podInformer.GetIndexer().ByIndex(podNodeIndex, node.Name)
+			if err != nil {
+				return
+			}
+			for _, pod := range pods {
+				r.enqueue(pod)
+			}
+		},
+	})
+
+	return r
+}
+
+// Watch merges the watch of the RedisFailovers with the modifications triggered by their resources. The informers
+// are started on the first call, so only the leader watches the resources, and run until stop is called
+func (r *ownedResourcesRetriever) Watch(ctx context.Context, options metav1.ListOptions) (watch.Interface, error) {
+	r.startOnce.Do(func() {
+		r.factory.Start(r.stopC)
+		r.nodeFactory.Start(r.stopC)
+		if r.resyncInterval != nil {
+			go r.resync()
+		}
+	})
+
+	rfWatch, err := r.Retriever.Watch(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+	w := &mergedWatch{
+		rfWatch: rfWatch,
+		result:  make(chan watch.Event),
+		stopC:   make(chan struct{}),
+	}
+	go w.run(r.events)
+	return w, nil
+}
+
+// stop stops the informers and the resync, as done when the controller stops
+func (r *ownedResourcesRetriever) stop() {
+	r.stopOnce.Do(func() {
+		close(r.stopC)
+	})
+}
+
+// enqueue schedules the reconcile of the RedisFailover owning the given object
+func (r *ownedResourcesRetriever) enqueue(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	object, ok := obj.(metav1.Object)
+	if !ok {
+		return
+	}
+	name := object.GetLabels()[rfLabelNameKey]
+	if name == "" {
+		return
+	}
+	r.reconcile(object.GetNamespace(), name)
+}
+
+// reconcile schedules the reconcile of the given RedisFailover. The ones requested while it is scheduled are handled by it
+func (r *ownedResourcesRetriever) reconcile(namespace, name string) {
+	key := fmt.Sprintf("%s/%s", namespace, name)
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.pending[key] {
+		return
+	}
+	r.pending[key] = true
+
+	time.AfterFunc(r.debounce, func() {
+		r.mutex.Lock()
+		delete(r.pending, key)
+		r.mutex.Unlock()
+
+		rf, err := r.cli.GetRedisFailover(context.Background(), namespace, name)
+		if err != nil {
+			r.logger.WithField("redisfailover", name).WithField("namespace", namespace).Debugf("Unable to get the redisfailover of a changed resource: %s", err)
+			return
+		}
+		select {
+		case r.events <- watch.Event{Type: watch.Modified, Object: rf}:
+		default:
+			r.logger.WithField("redisfailover", name).WithField("namespace", namespace).Warningf("Too many pending reconciles, leaving it to the resync")
+		}
+	})
+}
+
+// resync modifies every RedisFailover once the resync interval passed since the previous time. The interval is asked
+// for every time, so its changes apply while the operator runs
+func (r *ownedResourcesRetriever) resync() {
+	last := time.Now()
+	ticker := time.NewTicker(r.resyncCheckPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-r.stopC:
+			return
+		}
+		if time.Since(last) < r.resyncInterval() {
+			continue
+		}
+		last = time.Now()
+
+		list, err := r.Retriever.List(context.Background(), metav1.ListOptions{})
+		if err != nil {
+			r.logger.Warningf("Unable to list the redisfailovers to resync them: %s", err)
+			continue
+		}
+		rfs, ok := list.(*redisfailoverv1.RedisFailoverList)
+		if !ok {
+			continue
+		}
+		for i := range rfs.Items {
+			select {
+			case r.events <- watch.Event{Type: watch.Modified, Object: &rfs.Items[i]}:
+			default:
+				r.logger.WithField("redisfailover", rfs.Items[i].Name).WithField("namespace", rfs.Items[i].Namespace).Warningf("Too many pending reconciles, leaving it to the next resync")
+			}
+		}
+	}
+}
+
+// mergedWatch forwards the events of the RedisFailover watch and the ones triggered by their resources
+type mergedWatch struct {
+	rfWatch  watch.Interface
+	result   chan watch.Event
+	stopC    chan struct{}
+	stopOnce sync.Once
+}
+
+func (w *mergedWatch) run(events <-chan watch.Event) {
+	defer close(w.result)
+	for {
+		var event watch.Event
+		select {
+		case e, ok := <-w.rfWatch.ResultChan():
+			if !ok {
+				return
+			}
+			event = e
+		case e := <-events:
+			event = e
+		case <-w.stopC:
+			return
+		}
+		select {
+		case w.result <- event:
+		case <-w.stopC:
+			return
+		}
+	}
+}
+
+// Stop satisfies watch.Interface interface
+func (w *mergedWatch) Stop() {
+	w.stopOnce.Do(func() {
+		close(w.stopC)
+		w.rfWatch.Stop()
+	})
+}
+
+// ResultChan satisfies watch.Interface interface
+func (w *mergedWatch) ResultChan() <-chan watch.Event {
+	return w.result
+}
+
+// podChanged returns true when the pod got a new address, became ready or unready, or started terminating
+func podChanged(old, new *corev1.Pod) bool {
+	return old.Status.PodIP != new.Status.PodIP ||
+		rfservice.IsPodReady(*old) != rfservice.IsPodReady(*new) ||
+		(old.DeletionTimestamp == nil) != (new.DeletionTimestamp == nil)
+}
+
+// statefulSetChanged returns true when a new revision of the statefulset is rolled out or its ready replicas change
+func statefulSetChanged(old, new *appsv1.StatefulSet) bool {
+	return old.Status.UpdateRevision != new.Status.UpdateRevision ||
+		old.Status.CurrentRevision != new.Status.CurrentRevision ||
+		old.Status.ReadyReplicas != new.Status.ReadyReplicas
+}
+
+// deploymentChanged returns true when the ready or updated replicas of the deployment change
+func deploymentChanged(old, new *appsv1.Deployment) bool {
+	return old.Status.ReadyReplicas != new.Status.ReadyReplicas ||
+		old.Status.UpdatedReplicas != new.Status.UpdatedReplicas
+}
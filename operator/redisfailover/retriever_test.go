@@ -0,0 +1,231 @@
+package redisfailover
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/spotahome/kooper/v2/controller"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	kubernetes "k8s.io/client-go/kubernetes/fake"
+
+	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
+	"github.com/spotahome/redis-operator/log"
+	mK8SService "github.com/spotahome/redis-operator/mocks/service/k8s"
+)
+
+type fakeRetriever struct {
+	watcher *watch.FakeWatcher
+	rfs     []redisfailoverv1.RedisFailover
+}
+
+func (f fakeRetriever) List(_ context.Context, _ metav1.ListOptions) (runtime.Object, error) {
+	return &redisfailoverv1.RedisFailoverList{Items: f.rfs}, nil
+}
+
+func (f fakeRetriever) Watch(_ context.Context, _ metav1.ListOptions) (watch.Interface, error) {
+	return f.watcher, nil
+}
+
+var _ controller.Retriever = fakeRetriever{}
+
+func TestOwnedResourcesRetriever(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	rf := &redisfailoverv1.RedisFailover{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "testns"},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "rfr-test-0",
+			Namespace: "testns",
+			Labels:    map[string]string{rfLabelNameKey: "test"},
+		},
+		Status: corev1.PodStatus{PodIP: "0.0.0.0"},
+	}
+
+	k8sClient := kubernetes.NewSimpleClientset(pod)
+	ms := &mK8SService.Services{}
+	ms.On("GetRedisFailover", mock.Anything, "testns", "test").Return(rf, nil)
+	rfWatcher := watch.NewFake()
+
+	retriever := NewOwnedResourcesRetriever(fakeRetriever{watcher: rfWatcher}, ms, k8sClient, 50*time.Millisecond, log.Dummy).(*ownedResourcesRetriever)
+	w, err := retriever.Watch(context.TODO(), metav1.ListOptions{})
+	require.NoError(err)
+	defer w.Stop()
+	retriever.factory.WaitForCacheSync(make(chan struct{}))
+
+	// the redisfailover events are forwarded
+	go rfWatcher.Add(rf)
+	event := <-w.ResultChan()
+	assert.Equal(watch.Added, event.Type)
+
+	// changes of the pods are gathered in a single modification
+	for _, ip := range []string{"1.1.1.1", "2.2.2.2"} {
+		pod.Status.PodIP = ip
+		_, err := k8sClient.CoreV1().Pods("testns").UpdateStatus(context.TODO(), pod, metav1.UpdateOptions{})
+		require.NoError(err)
+	}
+	select {
+	case event := <-w.ResultChan():
+		assert.Equal(watch.Modified, event.Type)
+		assert.Equal(rf, event.Object)
+	case <-time.After(5 * time.Second):
+		assert.Fail("no modification triggered by the pod changes")
+	}
+	select {
+	case <-w.ResultChan():
+		assert.Fail("a single modification is expected")
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	// irrelevant changes are ignored
+	pod.Annotations = map[string]string{"foo": "bar"}
+	_, err = k8sClient.CoreV1().Pods("testns").Update(context.TODO(), pod, metav1.UpdateOptions{})
+	require.NoError(err)
+	select {
+	case <-w.ResultChan():
+		assert.Fail("no modification is expected")
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	// deletions trigger a modification
+	require.NoError(k8sClient.CoreV1().Pods("testns").Delete(context.TODO(), pod.Name, metav1.DeleteOptions{}))
+	select {
+	case event := <-w.ResultChan():
+		assert.Equal(watch.Modified, event.Type)
+	case <-time.After(5 * time.Second):
+		assert.Fail("no modification triggered by the pod deletion")
+	}
+}
+
+func TestOwnedResourcesRetrieverResync(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	rf := redisfailoverv1.RedisFailover{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "testns"},
+	}
+	rfWatcher := watch.NewFake()
+	retriever := newOwnedResourcesRetriever(fakeRetriever{watcher: rfWatcher, rfs: []redisfailoverv1.RedisFailover{rf}}, &mK8SService.Services{}, kubernetes.NewSimpleClientset(), time.Millisecond, log.Dummy)
+	retriever.resyncCheckPeriod = 10 * time.Millisecond
+
+	// the interval is asked for on every check, so a shorter one applies right away
+	interval := int64(time.Hour)
+	retriever.resyncInterval = func() time.Duration {
+		return time.Duration(atomic.LoadInt64(&interval))
+	}
+	w, err := retriever.Watch(context.TODO(), metav1.ListOptions{})
+	require.NoError(err)
+	defer w.Stop()
+
+	select {
+	case <-w.ResultChan():
+		assert.Fail("no resync is expected before the interval")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	atomic.StoreInt64(&interval, int64(50*time.Millisecond))
+	select {
+	case event := <-w.ResultChan():
+		assert.Equal(watch.Modified, event.Type)
+		assert.Equal(&rf, event.Object)
+	case <-time.After(5 * time.Second):
+		assert.Fail("no resync after the interval")
+	}
+}
+
+func TestOwnedResourcesRetrieverResyncWithoutWatch(t *testing.T) {
+	rf := redisfailoverv1.RedisFailover{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "testns"},
+	}
+	retriever := newOwnedResourcesRetriever(fakeRetriever{watcher: watch.NewFake(), rfs: []redisfailoverv1.RedisFailover{rf}}, &mK8SService.Services{}, kubernetes.NewSimpleClientset(), time.Millisecond, log.Dummy)
+	retriever.resyncCheckPeriod = time.Millisecond
+	retriever.resyncInterval = func() time.Duration { return time.Millisecond }
+	// nothing reads the events, as while the watch is restarted
+	retriever.events = make(chan watch.Event)
+
+	done := make(chan struct{})
+	go func() {
+		retriever.resync()
+		close(done)
+	}()
+	time.Sleep(50 * time.Millisecond)
+	retriever.stop()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		assert.Fail(t, "the resync must not block on the events and must stop with the retriever")
+	}
+}
+
+func TestOwnedResourcesRetrieverNodeDrain(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	rf := &redisfailoverv1.RedisFailover{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "testns"},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "rfr-test-0",
+			Namespace: "testns",
+			Labels:    map[string]string{rfLabelNameKey: "test"},
+		},
+		Spec: corev1.PodSpec{NodeName: "node-1"},
+	}
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}}
+	otherNode := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-2"}}
+
+	k8sClient := kubernetes.NewSimpleClientset(pod, node, otherNode)
+	ms := &mK8SService.Services{}
+	ms.On("GetRedisFailover", mock.Anything, "testns", "test").Return(rf, nil)
+
+	retriever := newOwnedResourcesRetriever(fakeRetriever{watcher: watch.NewFake()}, ms, k8sClient, 50*time.Millisecond, log.Dummy)
+	w, err := retriever.Watch(context.TODO(), metav1.ListOptions{})
+	require.NoError(err)
+	defer w.Stop()
+	retriever.factory.WaitForCacheSync(make(chan struct{}))
+	retriever.nodeFactory.WaitForCacheSync(make(chan struct{}))
+
+	// nodes without pods of a redisfailover are ignored
+	otherNode.Spec.Unschedulable = true
+	_, err = k8sClient.CoreV1().Nodes().Update(context.TODO(), otherNode, metav1.UpdateOptions{})
+	require.NoError(err)
+	select {
+	case <-w.ResultChan():
+		assert.Fail("no modification is expected")
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	// the redisfailovers with pods on a node being drained are modified
+	node.Spec.Taints = []corev1.Taint{{Key: "ToBeDeletedByClusterAutoscaler", Effect: corev1.TaintEffectNoSchedule}}
+	_, err = k8sClient.CoreV1().Nodes().Update(context.TODO(), node, metav1.UpdateOptions{})
+	require.NoError(err)
+	select {
+	case event := <-w.ResultChan():
+		assert.Equal(watch.Modified, event.Type)
+		assert.Equal(rf, event.Object)
+	case <-time.After(5 * time.Second):
+		assert.Fail("no modification triggered by the node drain")
+	}
+
+	// and only once, when the drain starts
+	node.Spec.Unschedulable = true
+	_, err = k8sClient.CoreV1().Nodes().Update(context.TODO(), node, metav1.UpdateOptions{})
+	require.NoError(err)
+	select {
+	case <-w.ResultChan():
+		assert.Fail("no modification is expected")
+	case <-time.After(200 * time.Millisecond):
+	}
+}
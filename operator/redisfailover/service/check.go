@@ -1,8 +1,12 @@
 package service
 
 import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"fmt"
+	"net"
 	"strconv"
 	"time"
 
@@ -18,27 +22,35 @@ import (
 
 // RedisFailoverCheck defines the interface able to check the correct status of a redis failover
 type RedisFailoverCheck interface {
-	CheckRedisNumber(rFailover *redisfailoverv1.RedisFailover) error
-	CheckSentinelNumber(rFailover *redisfailoverv1.RedisFailover) error
-	CheckAllSlavesFromMaster(master string, rFailover *redisfailoverv1.RedisFailover) error
-	CheckSentinelNumberInMemory(sentinel string, rFailover *redisfailoverv1.RedisFailover) error
-	CheckSentinelSlavesNumberInMemory(sentinel string, rFailover *redisfailoverv1.RedisFailover) error
-	CheckSentinelQuorum(rFailover *redisfailoverv1.RedisFailover) (int, error)
-	CheckIfMasterLocalhost(rFailover *redisfailoverv1.RedisFailover) (bool, error)
-	CheckSentinelMonitor(sentinel string, monitor ...string) error
-	GetMasterIP(rFailover *redisfailoverv1.RedisFailover) (string, error)
-	GetNumberMasters(rFailover *redisfailoverv1.RedisFailover) (int, error)
-	GetRedisesIPs(rFailover *redisfailoverv1.RedisFailover) ([]string, error)
-	GetSentinelsIPs(rFailover *redisfailoverv1.RedisFailover) ([]string, error)
-	GetMaxRedisPodTime(rFailover *redisfailoverv1.RedisFailover) (time.Duration, error)
-	GetRedisesSlavesPods(rFailover *redisfailoverv1.RedisFailover) ([]string, error)
-	GetRedisesMasterPod(rFailover *redisfailoverv1.RedisFailover) (string, error)
-	GetStatefulSetUpdateRevision(rFailover *redisfailoverv1.RedisFailover) (string, error)
-	GetRedisRevisionHash(podName string, rFailover *redisfailoverv1.RedisFailover) (string, error)
-	CheckRedisSlavesReady(slaveIP string, rFailover *redisfailoverv1.RedisFailover) (bool, error)
-	IsRedisRunning(rFailover *redisfailoverv1.RedisFailover) bool
-	IsSentinelRunning(rFailover *redisfailoverv1.RedisFailover) bool
-	IsClusterRunning(rFailover *redisfailoverv1.RedisFailover) bool
+	CheckRedisNumber(ctx context.Context, rFailover *redisfailoverv1.RedisFailover) error
+	CheckSentinelNumber(ctx context.Context, rFailover *redisfailoverv1.RedisFailover) error
+	CheckAllSlavesFromMaster(ctx context.Context, master string, rFailover *redisfailoverv1.RedisFailover) error
+	CheckSentinelNumberInMemory(ctx context.Context, sentinel string, rFailover *redisfailoverv1.RedisFailover) error
+	CheckSentinelSlavesNumberInMemory(ctx context.Context, sentinel string, rFailover *redisfailoverv1.RedisFailover) error
+	CheckSentinelQuorum(ctx context.Context, rFailover *redisfailoverv1.RedisFailover) (int, error)
+	CheckIfMasterLocalhost(ctx context.Context, rFailover *redisfailoverv1.RedisFailover) (bool, error)
+	CheckSentinelMonitor(ctx context.Context, sentinel string, rFailover *redisfailoverv1.RedisFailover, monitor ...string) error
+	GetMasterIP(ctx context.Context, rFailover *redisfailoverv1.RedisFailover) (string, error)
+	GetReplicaOfMaster(ctx context.Context, rFailover *redisfailoverv1.RedisFailover) (string, string, error)
+	GetBootstrapMaster(ctx context.Context, rFailover *redisfailoverv1.RedisFailover) (string, string, error)
+	GetNumberMasters(ctx context.Context, rFailover *redisfailoverv1.RedisFailover) (int, error)
+	GetRedisesIPs(ctx context.Context, rFailover *redisfailoverv1.RedisFailover) ([]string, error)
+	GetSentinelsIPs(ctx context.Context, rFailover *redisfailoverv1.RedisFailover) ([]string, error)
+	GetRedisesExternalAddresses(ctx context.Context, rFailover *redisfailoverv1.RedisFailover) (map[string]string, error)
+	GetSentinelsExternalAddresses(ctx context.Context, rFailover *redisfailoverv1.RedisFailover) (map[string]string, error)
+	GetMaxRedisPodTime(ctx context.Context, rFailover *redisfailoverv1.RedisFailover) (time.Duration, error)
+	GetRedisesSlavesPods(ctx context.Context, rFailover *redisfailoverv1.RedisFailover) ([]string, error)
+	GetRedisesMasterPod(ctx context.Context, rFailover *redisfailoverv1.RedisFailover) (string, error)
+	GetRedisesOnDrainingNodes(ctx context.Context, rFailover *redisfailoverv1.RedisFailover) (map[string]bool, error)
+	GetStatefulSetUpdateRevisions(ctx context.Context, rFailover *redisfailoverv1.RedisFailover) (map[string]string, error)
+	GetRedisRevisionHash(ctx context.Context, podName string, rFailover *redisfailoverv1.RedisFailover) (string, error)
+	CheckRedisSlavesReady(ctx context.Context, slaveIP string, rFailover *redisfailoverv1.RedisFailover) (bool, error)
+	CheckRedisSlaveInSync(ctx context.Context, slaveIP, masterIP string, rFailover *redisfailoverv1.RedisFailover) (bool, error)
+	IsRedisRunning(ctx context.Context, rFailover *redisfailoverv1.RedisFailover) bool
+	IsSentinelRunning(ctx context.Context, rFailover *redisfailoverv1.RedisFailover) bool
+	IsClusterRunning(ctx context.Context, rFailover *redisfailoverv1.RedisFailover) bool
+	IsPasswordRotating(ctx context.Context, rFailover *redisfailoverv1.RedisFailover) (bool, error)
+	GetTopology(ctx context.Context, rFailover *redisfailoverv1.RedisFailover) (*Topology, error)
 }
 
 // RedisFailoverChecker is our implementation of RedisFailoverCheck interface
@@ -60,20 +72,22 @@ func NewRedisFailoverChecker(k8sService k8s.Services, redisClient redis.Client,
 }
 
 // CheckRedisNumber controlls that the number of deployed redis is the same than the requested on the spec
-func (r *RedisFailoverChecker) CheckRedisNumber(rf *redisfailoverv1.RedisFailover) error {
-	ss, err := r.k8sService.GetStatefulSet(rf.Namespace, GetRedisName(rf))
-	if err != nil {
-		return err
-	}
-	if rf.Spec.Redis.Replicas != *ss.Spec.Replicas {
-		return errors.New("number of redis pods differ from specification")
+func (r *RedisFailoverChecker) CheckRedisNumber(ctx context.Context, rf *redisfailoverv1.RedisFailover) error {
+	for _, set := range getRedisStatefulSets(rf) {
+		ss, err := r.k8sService.GetStatefulSet(ctx, rf.Namespace, set.name)
+		if err != nil {
+			return err
+		}
+		if set.replicas != *ss.Spec.Replicas {
+			return errors.New("number of redis pods differ from specification")
+		}
 	}
 	return nil
 }
 
 // CheckSentinelNumber controlls that the number of deployed sentinel is the same than the requested on the spec
-func (r *RedisFailoverChecker) CheckSentinelNumber(rf *redisfailoverv1.RedisFailover) error {
-	d, err := r.k8sService.GetStatefulSet(rf.Namespace, GetSentinelName(rf))
+func (r *RedisFailoverChecker) CheckSentinelNumber(ctx context.Context, rf *redisfailoverv1.RedisFailover) error {
+	d, err := r.k8sService.GetStatefulSet(ctx, rf.Namespace, GetSentinelName(rf))
 	if err != nil {
 		return err
 	}
@@ -83,65 +97,52 @@ func (r *RedisFailoverChecker) CheckSentinelNumber(rf *redisfailoverv1.RedisFail
 	return nil
 }
 
-func (r *RedisFailoverChecker) setMasterLabelIfNecessary(namespace string, pod corev1.Pod) error {
-	for labelKey, labelValue := range pod.ObjectMeta.Labels {
-		if labelKey == redisRoleLabelKey && labelValue == redisRoleLabelMaster {
-			return nil
-		}
-	}
-	return r.k8sService.UpdatePodLabels(namespace, pod.ObjectMeta.Name, generateRedisMasterRoleLabel())
-}
-
-func (r *RedisFailoverChecker) setSlaveLabelIfNecessary(namespace string, pod corev1.Pod) error {
-	for labelKey, labelValue := range pod.ObjectMeta.Labels {
-		if labelKey == redisRoleLabelKey && labelValue == redisRoleLabelSlave {
-			return nil
-		}
-	}
-	return r.k8sService.UpdatePodLabels(namespace, pod.ObjectMeta.Name, generateRedisSlaveRoleLabel())
-}
-
 // CheckAllSlavesFromMaster controlls that all slaves have the same master (the real one)
-func (r *RedisFailoverChecker) CheckAllSlavesFromMaster(master string, rf *redisfailoverv1.RedisFailover) error {
-	rps, err := r.k8sService.GetStatefulSetPods(rf.Namespace, GetRedisName(rf))
+func (r *RedisFailoverChecker) CheckAllSlavesFromMaster(ctx context.Context, master string, rf *redisfailoverv1.RedisFailover) error {
+	rps, err := getRedisPods(ctx, r.k8sService, rf)
 	if err != nil {
 		return err
 	}
 
-	password, err := k8s.GetRedisPassword(r.k8sService, rf)
+	password, err := k8s.GetRedisPassword(ctx, r.k8sService, rf)
 	if err != nil {
 		return err
 	}
 
-	rport := getRedisPort(rf.Spec.Redis.Port)
-	for _, rp := range rps.Items {
-		if rp.Status.PodIP == master {
-			err = r.setMasterLabelIfNecessary(rf.Namespace, rp)
-			if err != nil {
-				return err
-			}
-		} else {
-			err = r.setSlaveLabelIfNecessary(rf.Namespace, rp)
-			if err != nil {
-				return err
-			}
+	// Once sentinel reconfigures the slaves they follow the address the master announces
+	masters := map[string]bool{master: true}
+	if rf.Spec.ExternalAccess != nil {
+		external, err := r.GetRedisesExternalAddresses(ctx, rf)
+		if err != nil {
+			return err
+		}
+		if host, _, err := net.SplitHostPort(external[master]); err == nil {
+			masters[host] = true
 		}
+	}
 
-		slave, err := r.redisClient.GetSlaveOf(rp.Status.PodIP, rport, password)
+	rport := getRedisPort(rf.Spec.Redis.Port)
+	for _, rp := range rps.Items {
+		address := getRedisAddress(rf, rp)
+		slave, err := r.redisClient.GetSlaveOf(ctx, address, rport, password)
 		if err != nil {
-			r.logger.Errorf("Get slave of master failed, maybe this node is not ready, pod ip: %s", rp.Status.PodIP)
+			Logger(ctx, r.logger, rf).Errorf("Get slave of master failed, maybe this node is not ready, pod address: %s", address)
 			return err
 		}
-		if slave != "" && slave != master {
-			return fmt.Errorf("slave %s don't have the master %s, has %s", rp.Status.PodIP, master, slave)
+		if slave != "" && !masters[slave] {
+			return fmt.Errorf("slave %s don't have the master %s, has %s", address, master, slave)
 		}
 	}
 	return nil
 }
 
 // CheckSentinelNumberInMemory controls that the provided sentinel has only the living sentinels on its memory.
-func (r *RedisFailoverChecker) CheckSentinelNumberInMemory(sentinel string, rf *redisfailoverv1.RedisFailover) error {
-	nSentinels, err := r.redisClient.GetNumberSentinelsInMemory(sentinel)
+func (r *RedisFailoverChecker) CheckSentinelNumberInMemory(ctx context.Context, sentinel string, rf *redisfailoverv1.RedisFailover) error {
+	password, err := k8s.GetSentinelPassword(ctx, r.k8sService, rf)
+	if err != nil {
+		return err
+	}
+	nSentinels, err := r.redisClient.GetNumberSentinelsInMemory(ctx, sentinel, getSentinelPort(rf.Spec.Sentinel.Port), password)
 	if err != nil {
 		return err
 	} else if nSentinels != rf.Spec.Sentinel.Replicas {
@@ -155,27 +156,27 @@ func (r *RedisFailoverChecker) CheckSentinelNumberInMemory(sentinel string, rf *
 // This function returns true if it all available pods have local host ip as master,
 // false if atleast one of the ip is not local hostip
 // false and error if any function fails
-func (r *RedisFailoverChecker) CheckIfMasterLocalhost(rFailover *redisfailoverv1.RedisFailover) (bool, error) {
+func (r *RedisFailoverChecker) CheckIfMasterLocalhost(ctx context.Context, rFailover *redisfailoverv1.RedisFailover) (bool, error) {
 
 	var lhmaster int = 0
-	redisIps, err := r.GetRedisesIPs(rFailover)
+	redisIps, err := r.GetRedisesIPs(ctx, rFailover)
 	if len(redisIps) == 0 || err != nil {
-		r.logger.Warningf("CheckIfMasterLocalhost GetRedisesIPs Failed- unable to fetch any redis Ips Currently")
+		Logger(ctx, r.logger, rFailover).Warningf("CheckIfMasterLocalhost GetRedisesIPs Failed- unable to fetch any redis Ips Currently")
 		return false, errors.New("unable to fetch any redis Ips Currently")
 	}
-	password, err := k8s.GetRedisPassword(r.k8sService, rFailover)
+	password, err := k8s.GetRedisPassword(ctx, r.k8sService, rFailover)
 	if err != nil {
-		r.logger.Errorf("CheckIfMasterLocalhost -- GetRedisPassword Failed")
+		Logger(ctx, r.logger, rFailover).Errorf("CheckIfMasterLocalhost -- GetRedisPassword Failed")
 		return false, err
 	}
 	rport := getRedisPort(rFailover.Spec.Redis.Port)
 	for _, sip := range redisIps {
-		master, err := r.redisClient.GetSlaveOf(sip, rport, password)
+		master, err := r.redisClient.GetSlaveOf(ctx, sip, rport, password)
 		if err != nil {
-			r.logger.Warningf("CheckIfMasterLocalhost -- GetSlaveOf Failed")
+			Logger(ctx, r.logger, rFailover).Warningf("CheckIfMasterLocalhost -- GetSlaveOf Failed")
 			return false, err
 		} else if master == "" {
-			r.logger.Warningf("CheckIfMasterLocalhost -- Master already available ?? check manually")
+			Logger(ctx, r.logger, rFailover).Warningf("CheckIfMasterLocalhost -- Master already available ?? check manually")
 			return false, errors.New("unexpected master state, fix manually")
 		} else {
 			if master == "127.0.0.1" {
@@ -184,33 +185,39 @@ func (r *RedisFailoverChecker) CheckIfMasterLocalhost(rFailover *redisfailoverv1
 		}
 	}
 	if lhmaster == len(redisIps) {
-		r.logger.Infof("all available redis configured localhost as master , opertor must heal")
+		Logger(ctx, r.logger, rFailover).Infof("all available redis configured localhost as master , opertor must heal")
 		return true, nil
 	}
-	r.logger.Infof("atleast one pod does not have localhost as master , opertor should not heal")
+	Logger(ctx, r.logger, rFailover).Infof("atleast one pod does not have localhost as master , opertor should not heal")
 	return false, nil
 }
 
 // This function will call the sentinel client apis to check with sentinel if the sentinel is in a state
 // to heal the redis system
-func (r *RedisFailoverChecker) CheckSentinelQuorum(rFailover *redisfailoverv1.RedisFailover) (int, error) {
+func (r *RedisFailoverChecker) CheckSentinelQuorum(ctx context.Context, rFailover *redisfailoverv1.RedisFailover) (int, error) {
 
 	var unhealthyCnt int = -1
 
-	sentinels, err := r.GetSentinelsIPs(rFailover)
+	sentinels, err := r.GetSentinelsIPs(ctx, rFailover)
 	if err != nil {
-		r.logger.Warningf("CheckSentinelQuorum Error in getting sentinel Ip's")
+		Logger(ctx, r.logger, rFailover).Warningf("CheckSentinelQuorum Error in getting sentinel Ip's")
 		return unhealthyCnt, err
 	}
 	if len(sentinels) < int(getQuorum(rFailover)) {
 		unhealthyCnt = int(getQuorum(rFailover)) - len(sentinels)
-		r.logger.Warningf("insufficnet sentinel to reach Quorum - Unhealthy count: %d", unhealthyCnt)
+		Logger(ctx, r.logger, rFailover).Warningf("insufficnet sentinel to reach Quorum - Unhealthy count: %d", unhealthyCnt)
 		return unhealthyCnt, errors.New("insufficnet sentinel to reach Quorum")
 	}
 
+	password, err := k8s.GetSentinelPassword(ctx, r.k8sService, rFailover)
+	if err != nil {
+		return unhealthyCnt, err
+	}
+	sport := getSentinelPort(rFailover.Spec.Sentinel.Port)
+
 	unhealthyCnt = 0
 	for _, sip := range sentinels {
-		err = r.redisClient.SentinelCheckQuorum(sip)
+		err = r.redisClient.SentinelCheckQuorum(ctx, sip, sport, rFailover.Spec.Sentinel.MasterName, password)
 		if err != nil {
 			unhealthyCnt += 1
 		} else {
@@ -220,14 +227,18 @@ func (r *RedisFailoverChecker) CheckSentinelQuorum(rFailover *redisfailoverv1.Re
 	if unhealthyCnt < int(getQuorum(rFailover)) {
 		return unhealthyCnt, nil
 	} else {
-		r.logger.Errorf("insufficnet sentinel to reach Quorum - Unhealthy count: %d", unhealthyCnt)
+		Logger(ctx, r.logger, rFailover).Errorf("insufficnet sentinel to reach Quorum - Unhealthy count: %d", unhealthyCnt)
 		return unhealthyCnt, errors.New("insufficnet sentinel to reach Quorum")
 	}
 }
 
 // CheckSentinelSlavesNumberInMemory controls that the provided sentinel has only the expected slaves number.
-func (r *RedisFailoverChecker) CheckSentinelSlavesNumberInMemory(sentinel string, rf *redisfailoverv1.RedisFailover) error {
-	nSlaves, err := r.redisClient.GetNumberSentinelSlavesInMemory(sentinel)
+func (r *RedisFailoverChecker) CheckSentinelSlavesNumberInMemory(ctx context.Context, sentinel string, rf *redisfailoverv1.RedisFailover) error {
+	password, err := k8s.GetSentinelPassword(ctx, r.k8sService, rf)
+	if err != nil {
+		return err
+	}
+	nSlaves, err := r.redisClient.GetNumberSentinelSlavesInMemory(ctx, sentinel, getSentinelPort(rf.Spec.Sentinel.Port), password)
 	if err != nil {
 		return err
 	} else {
@@ -246,13 +257,17 @@ func (r *RedisFailoverChecker) CheckSentinelSlavesNumberInMemory(sentinel string
 }
 
 // CheckSentinelMonitor controls if the sentinels are monitoring the expected master
-func (r *RedisFailoverChecker) CheckSentinelMonitor(sentinel string, monitor ...string) error {
+func (r *RedisFailoverChecker) CheckSentinelMonitor(ctx context.Context, sentinel string, rf *redisfailoverv1.RedisFailover, monitor ...string) error {
 	monitorIP := monitor[0]
 	monitorPort := ""
 	if len(monitor) > 1 {
 		monitorPort = monitor[1]
 	}
-	actualMonitorIP, actualMonitorPort, err := r.redisClient.GetSentinelMonitor(sentinel)
+	password, err := k8s.GetSentinelPassword(ctx, r.k8sService, rf)
+	if err != nil {
+		return err
+	}
+	actualMonitorIP, actualMonitorPort, err := r.redisClient.GetSentinelMonitor(ctx, sentinel, getSentinelPort(rf.Spec.Sentinel.Port), rf.Spec.Sentinel.MasterName, password)
 	if err != nil {
 		return err
 	}
@@ -263,13 +278,13 @@ func (r *RedisFailoverChecker) CheckSentinelMonitor(sentinel string, monitor ...
 }
 
 // GetMasterIP connects to all redis and returns the master of the redis failover
-func (r *RedisFailoverChecker) GetMasterIP(rf *redisfailoverv1.RedisFailover) (string, error) {
-	rips, err := r.GetRedisesIPs(rf)
+func (r *RedisFailoverChecker) GetMasterIP(ctx context.Context, rf *redisfailoverv1.RedisFailover) (string, error) {
+	rips, err := r.GetRedisesIPs(ctx, rf)
 	if err != nil {
 		return "", err
 	}
 
-	password, err := k8s.GetRedisPassword(r.k8sService, rf)
+	password, err := k8s.GetRedisPassword(ctx, r.k8sService, rf)
 	if err != nil {
 		return "", err
 	}
@@ -277,9 +292,9 @@ func (r *RedisFailoverChecker) GetMasterIP(rf *redisfailoverv1.RedisFailover) (s
 	masters := []string{}
 	rport := getRedisPort(rf.Spec.Redis.Port)
 	for _, rip := range rips {
-		master, err := r.redisClient.IsMaster(rip, rport, password)
+		master, err := r.redisClient.IsMaster(ctx, rip, rport, password)
 		if err != nil {
-			r.logger.Errorf("Get redis info failed, maybe this node is not ready, pod ip: %s", rip)
+			Logger(ctx, r.logger, rf).Errorf("Get redis info failed, maybe this node is not ready, pod ip: %s", rip)
 			continue
 		}
 		if master {
@@ -293,26 +308,112 @@ func (r *RedisFailoverChecker) GetMasterIP(rf *redisfailoverv1.RedisFailover) (s
 	return masters[0], nil
 }
 
+// GetReplicaOfMaster returns the address and port of the current master of the RedisFailover replicated by the given
+// one. The source is asked for the pod its operator labeled as master, so the replicas follow its failovers
+func (r *RedisFailoverChecker) GetReplicaOfMaster(ctx context.Context, rf *redisfailoverv1.RedisFailover) (string, string, error) {
+	ref := rf.Spec.ReplicaOf.RedisFailoverRef
+	source, err := r.k8sService.GetRedisFailover(ctx, ref.Namespace, ref.Name)
+	if err != nil {
+		return "", "", err
+	}
+	if err := source.Validate(); err != nil {
+		return "", "", err
+	}
+
+	rps, err := getRedisPods(ctx, r.k8sService, source)
+	if err != nil {
+		return "", "", err
+	}
+	for _, rp := range rps.Items {
+		if rp.Status.Phase == corev1.PodRunning && rp.DeletionTimestamp == nil && rp.Labels[redisRoleLabelKey] == redisRoleLabelMaster {
+			return getRedisAddress(source, rp), getRedisPort(source.Spec.Redis.Port), nil
+		}
+	}
+	return "", "", fmt.Errorf("source redisfailover %s/%s has no master", ref.Namespace, ref.Name)
+}
+
+// GetBootstrapMaster returns the address and port of the external master the redises bootstrap from. When the
+// bootstrapNode lists sentinels, the first one answering is asked for the current master
+func (r *RedisFailoverChecker) GetBootstrapMaster(ctx context.Context, rf *redisfailoverv1.RedisFailover) (string, string, error) {
+	bootstrap := rf.Spec.BootstrapNode
+	if len(bootstrap.Sentinels) == 0 {
+		return bootstrap.Host, bootstrap.Port, nil
+	}
+
+	tlsConfig, err := r.getBootstrapTLSConfig(ctx, rf)
+	if err != nil {
+		return "", "", err
+	}
+	password, err := k8s.GetBootstrapSentinelPassword(ctx, r.k8sService, rf)
+	if err != nil {
+		return "", "", err
+	}
+
+	var lastErr error
+	for _, sentinel := range bootstrap.Sentinels {
+		var sentinelTLSConfig *tls.Config
+		if tlsConfig != nil {
+			sentinelTLSConfig = tlsConfig.Clone()
+			sentinelTLSConfig.ServerName, _, _ = net.SplitHostPort(sentinel)
+		}
+		host, port, err := r.redisClient.GetMasterAddrByName(ctx, sentinel, bootstrap.MasterName, password, sentinelTLSConfig)
+		if err != nil {
+			Logger(ctx, r.logger, rf).Warningf("External sentinel %s failed to give master %s: %s", sentinel, bootstrap.MasterName, err)
+			lastErr = err
+			continue
+		}
+		return host, port, nil
+	}
+	return "", "", fmt.Errorf("no external sentinel gave master %s: %w", bootstrap.MasterName, lastErr)
+}
+
+// getBootstrapTLSConfig builds the TLS configuration to reach the external sentinels with, nil when TLS is disabled
+func (r *RedisFailoverChecker) getBootstrapTLSConfig(ctx context.Context, rf *redisfailoverv1.RedisFailover) (*tls.Config, error) {
+	if rf.Spec.BootstrapNode.TLS == nil {
+		return nil, nil
+	}
+	secret, err := r.k8sService.GetSecret(ctx, rf.Namespace, rf.Spec.BootstrapNode.TLS.SecretName)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if ca, ok := secret.Data["ca.crt"]; ok {
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("secret %q has an invalid ca.crt", secret.Name)
+		}
+	}
+	if crt, ok := secret.Data["tls.crt"]; ok {
+		cert, err := tls.X509KeyPair(crt, secret.Data["tls.key"])
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
+
 // GetNumberMasters returns the number of redis nodes that are working as a master
-func (r *RedisFailoverChecker) GetNumberMasters(rf *redisfailoverv1.RedisFailover) (int, error) {
+func (r *RedisFailoverChecker) GetNumberMasters(ctx context.Context, rf *redisfailoverv1.RedisFailover) (int, error) {
 	nMasters := 0
-	rips, err := r.GetRedisesIPs(rf)
+	rips, err := r.GetRedisesIPs(ctx, rf)
 	if err != nil {
-		r.logger.Errorf(err.Error())
+		Logger(ctx, r.logger, rf).Errorf(err.Error())
 		return nMasters, err
 	}
 
-	password, err := k8s.GetRedisPassword(r.k8sService, rf)
+	password, err := k8s.GetRedisPassword(ctx, r.k8sService, rf)
 	if err != nil {
-		r.logger.Errorf("Error getting password: %s", err.Error())
+		Logger(ctx, r.logger, rf).Errorf("Error getting password: %s", err.Error())
 		return nMasters, err
 	}
 
 	rport := getRedisPort(rf.Spec.Redis.Port)
 	for _, rip := range rips {
-		master, err := r.redisClient.IsMaster(rip, rport, password)
+		master, err := r.redisClient.IsMaster(ctx, rip, rport, password)
 		if err != nil {
-			r.logger.Errorf("Get redis info failed, maybe this node is not ready, pod ip: %s", rip)
+			Logger(ctx, r.logger, rf).Errorf("Get redis info failed, maybe this node is not ready, pod ip: %s", rip)
 			continue
 		}
 		if master {
@@ -322,43 +423,109 @@ func (r *RedisFailoverChecker) GetNumberMasters(rf *redisfailoverv1.RedisFailove
 	return nMasters, nil
 }
 
-// GetRedisesIPs returns the IPs of the Redis nodes
-func (r *RedisFailoverChecker) GetRedisesIPs(rf *redisfailoverv1.RedisFailover) ([]string, error) {
+// GetRedisesIPs returns the addresses of the Redis nodes, their hostnames when the failover announces them
+func (r *RedisFailoverChecker) GetRedisesIPs(ctx context.Context, rf *redisfailoverv1.RedisFailover) ([]string, error) {
 	redises := []string{}
-	rps, err := r.k8sService.GetStatefulSetPods(rf.Namespace, GetRedisName(rf))
+	rps, err := getRedisPods(ctx, r.k8sService, rf)
 	if err != nil {
 		return nil, err
 	}
 	for _, rp := range rps.Items {
 		if rp.Status.Phase == corev1.PodRunning && rp.DeletionTimestamp == nil { // Only work with running pods
-			redises = append(redises, rp.Status.PodIP)
+			redises = append(redises, getRedisAddress(rf, rp))
 		}
 	}
 	return redises, nil
 }
 
-// GetSentinelsIPs returns the IPs of the Sentinel nodes
-func (r *RedisFailoverChecker) GetSentinelsIPs(rf *redisfailoverv1.RedisFailover) ([]string, error) {
+// GetSentinelsIPs returns the addresses of the Sentinel nodes, their hostnames when the failover announces them
+func (r *RedisFailoverChecker) GetSentinelsIPs(ctx context.Context, rf *redisfailoverv1.RedisFailover) ([]string, error) {
 	sentinels := []string{}
-	rps, err := r.k8sService.GetStatefulSetPods(rf.Namespace, GetSentinelName(rf))
+	rps, err := r.k8sService.GetStatefulSetPods(ctx, rf.Namespace, GetSentinelName(rf))
 	if err != nil {
 		return nil, err
 	}
 	for _, sp := range rps.Items {
 		if sp.Status.Phase == corev1.PodRunning && sp.DeletionTimestamp == nil { // Only work with running pods
 			if IsPodReady(sp) {
-				fmt.Printf("Check sentinel pod: %s is ready\n", sp.Name)
-				sentinels = append(sentinels, sp.Status.PodIP)
+				sentinels = append(sentinels, getSentinelAddress(rf, sp))
 			}
 		}
 	}
 	return sentinels, nil
 }
 
+// GetRedisesExternalAddresses returns the address, as host:port, every running Redis node is reachable by from outside
+// the cluster, keyed by the address returned by GetRedisesIPs. Nodes whose service has no address yet are left out
+func (r *RedisFailoverChecker) GetRedisesExternalAddresses(ctx context.Context, rf *redisfailoverv1.RedisFailover) (map[string]string, error) {
+	rps, err := getRedisPods(ctx, r.k8sService, rf)
+	if err != nil {
+		return nil, err
+	}
+	return getExternalAddresses(ctx, r.k8sService, rf, rps, getRedisAddress)
+}
+
+// GetSentinelsExternalAddresses returns the address, as host:port, every running Sentinel node is reachable by from
+// outside the cluster, keyed by the address returned by GetSentinelsIPs. Nodes whose service has no address yet are left out
+func (r *RedisFailoverChecker) GetSentinelsExternalAddresses(ctx context.Context, rf *redisfailoverv1.RedisFailover) (map[string]string, error) {
+	sps, err := r.k8sService.GetStatefulSetPods(ctx, rf.Namespace, GetSentinelName(rf))
+	if err != nil {
+		return nil, err
+	}
+	return getExternalAddresses(ctx, r.k8sService, rf, sps, getSentinelAddress)
+}
+
+func getExternalAddresses(ctx context.Context, k8sService k8s.Services, rf *redisfailoverv1.RedisFailover, pods *corev1.PodList, address func(*redisfailoverv1.RedisFailover, corev1.Pod) string) (map[string]string, error) {
+	addresses := map[string]string{}
+	if rf.Spec.ExternalAccess == nil {
+		return addresses, nil
+	}
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != corev1.PodRunning || pod.DeletionTimestamp != nil {
+			continue
+		}
+		svc, err := k8sService.GetService(ctx, rf.Namespace, pod.Name)
+		if err != nil {
+			return nil, err
+		}
+		if external := getExternalAddress(rf, svc); external != "" {
+			addresses[address(rf, pod)] = external
+		}
+	}
+	return addresses, nil
+}
+
+// getExternalAddress returns the host:port a pod's external access service exposes, or empty if it has none yet
+func getExternalAddress(rf *redisfailoverv1.RedisFailover, svc *corev1.Service) string {
+	if len(svc.Spec.Ports) == 0 {
+		return ""
+	}
+	port := svc.Spec.Ports[0]
+
+	switch svc.Spec.Type {
+	case corev1.ServiceTypeNodePort:
+		if port.NodePort == 0 {
+			return ""
+		}
+		return net.JoinHostPort(rf.Spec.ExternalAccess.HostAddress, strconv.Itoa(int(port.NodePort)))
+	case corev1.ServiceTypeLoadBalancer:
+		for _, ingress := range svc.Status.LoadBalancer.Ingress {
+			host := ingress.IP
+			if host == "" {
+				host = ingress.Hostname
+			}
+			if host != "" {
+				return net.JoinHostPort(host, strconv.Itoa(int(port.Port)))
+			}
+		}
+	}
+	return ""
+}
+
 // GetMaxRedisPodTime returns the MAX uptime among the active Pods
-func (r *RedisFailoverChecker) GetMaxRedisPodTime(rf *redisfailoverv1.RedisFailover) (time.Duration, error) {
+func (r *RedisFailoverChecker) GetMaxRedisPodTime(ctx context.Context, rf *redisfailoverv1.RedisFailover) (time.Duration, error) {
 	maxTime := 0 * time.Hour
-	rps, err := r.k8sService.GetStatefulSetPods(rf.Namespace, GetRedisName(rf))
+	rps, err := getRedisPods(ctx, r.k8sService, rf)
 	if err != nil {
 		return maxTime, err
 	}
@@ -368,7 +535,7 @@ func (r *RedisFailoverChecker) GetMaxRedisPodTime(rf *redisfailoverv1.RedisFailo
 		}
 		start := redisNode.Status.StartTime.Round(time.Second)
 		alive := time.Since(start)
-		r.logger.Debugf("Pod %s has been alive for %.f seconds", redisNode.Status.PodIP, alive.Seconds())
+		Logger(ctx, r.logger, rf).Debugf("Pod %s has been alive for %.f seconds", redisNode.Status.PodIP, alive.Seconds())
 		if alive > maxTime {
 			maxTime = alive
 		}
@@ -377,14 +544,14 @@ func (r *RedisFailoverChecker) GetMaxRedisPodTime(rf *redisfailoverv1.RedisFailo
 }
 
 // GetRedisesSlavesPods returns pods names of the Redis slave nodes
-func (r *RedisFailoverChecker) GetRedisesSlavesPods(rf *redisfailoverv1.RedisFailover) ([]string, error) {
+func (r *RedisFailoverChecker) GetRedisesSlavesPods(ctx context.Context, rf *redisfailoverv1.RedisFailover) ([]string, error) {
 	redises := []string{}
-	rps, err := r.k8sService.GetStatefulSetPods(rf.Namespace, GetRedisName(rf))
+	rps, err := getRedisPods(ctx, r.k8sService, rf)
 	if err != nil {
 		return nil, err
 	}
 
-	password, err := k8s.GetRedisPassword(r.k8sService, rf)
+	password, err := k8s.GetRedisPassword(ctx, r.k8sService, rf)
 	if err != nil {
 		return redises, err
 	}
@@ -392,7 +559,7 @@ func (r *RedisFailoverChecker) GetRedisesSlavesPods(rf *redisfailoverv1.RedisFai
 	rport := getRedisPort(rf.Spec.Redis.Port)
 	for _, rp := range rps.Items {
 		if rp.Status.Phase == corev1.PodRunning && rp.DeletionTimestamp == nil { // Only work with running
-			master, err := r.redisClient.IsMaster(rp.Status.PodIP, rport, password)
+			master, err := r.redisClient.IsMaster(ctx, getRedisAddress(rf, rp), rport, password)
 			if err != nil {
 				return []string{}, err
 			}
@@ -405,13 +572,13 @@ func (r *RedisFailoverChecker) GetRedisesSlavesPods(rf *redisfailoverv1.RedisFai
 }
 
 // GetRedisesMasterPod returns pods names of the Redis slave nodes
-func (r *RedisFailoverChecker) GetRedisesMasterPod(rFailover *redisfailoverv1.RedisFailover) (string, error) {
-	rps, err := r.k8sService.GetStatefulSetPods(rFailover.Namespace, GetRedisName(rFailover))
+func (r *RedisFailoverChecker) GetRedisesMasterPod(ctx context.Context, rFailover *redisfailoverv1.RedisFailover) (string, error) {
+	rps, err := getRedisPods(ctx, r.k8sService, rFailover)
 	if err != nil {
 		return "", err
 	}
 
-	password, err := k8s.GetRedisPassword(r.k8sService, rFailover)
+	password, err := k8s.GetRedisPassword(ctx, r.k8sService, rFailover)
 	if err != nil {
 		return "", err
 	}
@@ -419,7 +586,7 @@ func (r *RedisFailoverChecker) GetRedisesMasterPod(rFailover *redisfailoverv1.Re
 	rport := getRedisPort(rFailover.Spec.Redis.Port)
 	for _, rp := range rps.Items {
 		if rp.Status.Phase == corev1.PodRunning && rp.DeletionTimestamp == nil { // Only work with running
-			master, err := r.redisClient.IsMaster(rp.Status.PodIP, rport, password)
+			master, err := r.redisClient.IsMaster(ctx, getRedisAddress(rFailover, rp), rport, password)
 			if err != nil {
 				return "", err
 			}
@@ -431,24 +598,28 @@ func (r *RedisFailoverChecker) GetRedisesMasterPod(rFailover *redisfailoverv1.Re
 	return "", errors.New("redis nodes known as master not found")
 }
 
-// GetStatefulSetUpdateRevision returns current version for the statefulSet
+// GetStatefulSetUpdateRevisions returns current version for every redis statefulSet by its name
 // If the label don't exists, we return an empty value and no error, so previous versions don't break
-func (r *RedisFailoverChecker) GetStatefulSetUpdateRevision(rFailover *redisfailoverv1.RedisFailover) (string, error) {
-	ss, err := r.k8sService.GetStatefulSet(rFailover.Namespace, GetRedisName(rFailover))
-	if err != nil {
-		return "", err
-	}
+func (r *RedisFailoverChecker) GetStatefulSetUpdateRevisions(ctx context.Context, rFailover *redisfailoverv1.RedisFailover) (map[string]string, error) {
+	revisions := map[string]string{}
+	for _, set := range getRedisStatefulSets(rFailover) {
+		ss, err := r.k8sService.GetStatefulSet(ctx, rFailover.Namespace, set.name)
+		if err != nil {
+			return nil, err
+		}
 
-	if ss == nil {
-		return "", errors.New("statefulSet not found")
-	}
+		if ss == nil {
+			return nil, errors.New("statefulSet not found")
+		}
 
-	return ss.Status.UpdateRevision, nil
+		revisions[set.name] = ss.Status.UpdateRevision
+	}
+	return revisions, nil
 }
 
 // GetRedisRevisionHash returns the statefulset uid for the pod
-func (r *RedisFailoverChecker) GetRedisRevisionHash(podName string, rFailover *redisfailoverv1.RedisFailover) (string, error) {
-	pod, err := r.k8sService.GetPod(rFailover.Namespace, podName)
+func (r *RedisFailoverChecker) GetRedisRevisionHash(ctx context.Context, podName string, rFailover *redisfailoverv1.RedisFailover) (string, error) {
+	pod, err := r.k8sService.GetPod(ctx, rFailover.Namespace, podName)
 	if err != nil {
 		return "", err
 	}
@@ -467,38 +638,59 @@ func (r *RedisFailoverChecker) GetRedisRevisionHash(podName string, rFailover *r
 }
 
 // CheckRedisSlavesReady returns true if the slave is ready (sync, connected, etc)
-func (r *RedisFailoverChecker) CheckRedisSlavesReady(ip string, rFailover *redisfailoverv1.RedisFailover) (bool, error) {
-	password, err := k8s.GetRedisPassword(r.k8sService, rFailover)
+func (r *RedisFailoverChecker) CheckRedisSlavesReady(ctx context.Context, ip string, rFailover *redisfailoverv1.RedisFailover) (bool, error) {
+	password, err := k8s.GetRedisPassword(ctx, r.k8sService, rFailover)
 	if err != nil {
 		return false, err
 	}
 
 	port := getRedisPort(rFailover.Spec.Redis.Port)
-	return r.redisClient.SlaveIsReady(ip, port, password)
+	return r.redisClient.SlaveIsReady(ctx, ip, port, password)
 }
 
 // IsRedisRunning returns true if all the pods are Running
-func (r *RedisFailoverChecker) IsRedisRunning(rFailover *redisfailoverv1.RedisFailover) bool {
-	dp, err := r.k8sService.GetStatefulSetPods(rFailover.Namespace, GetRedisName(rFailover))
+func (r *RedisFailoverChecker) IsRedisRunning(ctx context.Context, rFailover *redisfailoverv1.RedisFailover) bool {
+	dp, err := getRedisPods(ctx, r.k8sService, rFailover)
 	return err == nil && len(dp.Items) > int(rFailover.Spec.Redis.Replicas-1) && AreAllRunning(dp)
 }
 
 // IsSentinelRunning returns true if all the pods are Running
-func (r *RedisFailoverChecker) IsSentinelRunning(rFailover *redisfailoverv1.RedisFailover) bool {
-	dp, err := r.k8sService.GetStatefulSetPods(rFailover.Namespace, GetSentinelName(rFailover))
-	r.logger.Infof("Get Sentinel statefulset pods count:%d ", len(dp.Items))
+func (r *RedisFailoverChecker) IsSentinelRunning(ctx context.Context, rFailover *redisfailoverv1.RedisFailover) bool {
+	dp, err := r.k8sService.GetStatefulSetPods(ctx, rFailover.Namespace, GetSentinelName(rFailover))
+	Logger(ctx, r.logger, rFailover).Infof("Get Sentinel statefulset pods count:%d ", len(dp.Items))
 	return err == nil && len(dp.Items) > int(rFailover.Spec.Redis.Replicas-1) && AreAllRunning(dp)
 }
 
 // IsClusterRunning returns true if all the pods in the given redisfailover are Running
-func (r *RedisFailoverChecker) IsClusterRunning(rFailover *redisfailoverv1.RedisFailover) bool {
-	return r.IsSentinelRunning(rFailover) && r.IsRedisRunning(rFailover)
+func (r *RedisFailoverChecker) IsClusterRunning(ctx context.Context, rFailover *redisfailoverv1.RedisFailover) bool {
+	return r.IsSentinelRunning(ctx, rFailover) && r.IsRedisRunning(ctx, rFailover)
 }
 
 func getRedisPort(p int32) string {
 	return strconv.Itoa(int(p))
 }
 
+func getSentinelPort(p int32) string {
+	return strconv.Itoa(int(p))
+}
+
+// getRedisAddress returns the address the operator reaches and identifies a redis pod by,
+// the stable hostname it announces when the failover uses hostnames and its IP otherwise
+func getRedisAddress(rf *redisfailoverv1.RedisFailover, pod corev1.Pod) string {
+	if rf.Spec.AnnounceHostnames {
+		return getPodHostname(pod.Name, GetRedisName(rf), rf.Namespace)
+	}
+	return pod.Status.PodIP
+}
+
+// getSentinelAddress returns the address the operator reaches a sentinel pod by
+func getSentinelAddress(rf *redisfailoverv1.RedisFailover, pod corev1.Pod) string {
+	if rf.Spec.AnnounceHostnames {
+		return getPodHostname(pod.Name, GetSentinelName(rf), rf.Namespace)
+	}
+	return pod.Status.PodIP
+}
+
 func AreAllRunning(pods *corev1.PodList) bool {
 	for _, pod := range pods.Items {
 		if pod.Status.Phase != corev1.PodRunning || pod.DeletionTimestamp != nil {
@@ -507,3 +699,16 @@ func AreAllRunning(pods *corev1.PodList) bool {
 	}
 	return true
 }
+
+// IsPasswordRotating returns true when the auth secret holds a previous password the redises still have to accept
+func (r *RedisFailoverChecker) IsPasswordRotating(ctx context.Context, rf *redisfailoverv1.RedisFailover) (bool, error) {
+	previousPassword, err := k8s.GetRedisPreviousPassword(ctx, r.k8sService, rf)
+	if err != nil || previousPassword == "" {
+		return false, err
+	}
+	password, err := k8s.GetRedisPassword(ctx, r.k8sService, rf)
+	if err != nil {
+		return false, err
+	}
+	return previousPassword != password, nil
+}
@@ -1,13 +1,13 @@
 package service_test
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
 
-	"github.com/stretchr/testify/mock"
-
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -31,7 +31,9 @@ func generateRF() *redisfailoverv1.RedisFailover {
 				Replicas: int32(3),
 			},
 			Sentinel: redisfailoverv1.SentinelSettings{
-				Replicas: int32(3),
+				Replicas:   int32(3),
+				Port:       int32(26379),
+				MasterName: "master0",
 			},
 		},
 	}
@@ -43,12 +45,12 @@ func TestCheckRedisNumberError(t *testing.T) {
 	rf := generateRF()
 
 	ms := &mK8SService.Services{}
-	ms.On("GetStatefulSet", namespace, rfservice.GetRedisName(rf)).Once().Return(nil, errors.New(""))
+	ms.On("GetStatefulSet", mock.Anything, namespace, rfservice.GetRedisName(rf)).Once().Return(nil, errors.New(""))
 	mr := &mRedisService.Client{}
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	err := checker.CheckRedisNumber(rf)
+	err := checker.CheckRedisNumber(context.Background(), rf)
 	assert.Error(err)
 }
 
@@ -64,12 +66,12 @@ func TestCheckRedisNumberFalse(t *testing.T) {
 		},
 	}
 	ms := &mK8SService.Services{}
-	ms.On("GetStatefulSet", namespace, rfservice.GetRedisName(rf)).Once().Return(ss, nil)
+	ms.On("GetStatefulSet", mock.Anything, namespace, rfservice.GetRedisName(rf)).Once().Return(ss, nil)
 	mr := &mRedisService.Client{}
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	err := checker.CheckRedisNumber(rf)
+	err := checker.CheckRedisNumber(context.Background(), rf)
 	assert.Error(err)
 }
 
@@ -85,12 +87,12 @@ func TestCheckRedisNumberTrue(t *testing.T) {
 		},
 	}
 	ms := &mK8SService.Services{}
-	ms.On("GetStatefulSet", namespace, rfservice.GetRedisName(rf)).Once().Return(ss, nil)
+	ms.On("GetStatefulSet", mock.Anything, namespace, rfservice.GetRedisName(rf)).Once().Return(ss, nil)
 	mr := &mRedisService.Client{}
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	err := checker.CheckRedisNumber(rf)
+	err := checker.CheckRedisNumber(context.Background(), rf)
 	assert.NoError(err)
 }
 
@@ -100,12 +102,12 @@ func TestCheckSentinelNumberError(t *testing.T) {
 	rf := generateRF()
 
 	ms := &mK8SService.Services{}
-	ms.On("GetDeployment", namespace, rfservice.GetSentinelName(rf)).Once().Return(nil, errors.New(""))
+	ms.On("GetDeployment", mock.Anything, namespace, rfservice.GetSentinelName(rf)).Once().Return(nil, errors.New(""))
 	mr := &mRedisService.Client{}
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	err := checker.CheckSentinelNumber(rf)
+	err := checker.CheckSentinelNumber(context.Background(), rf)
 	assert.Error(err)
 }
 
@@ -121,12 +123,12 @@ func TestCheckSentinelNumberFalse(t *testing.T) {
 		},
 	}
 	ms := &mK8SService.Services{}
-	ms.On("GetDeployment", namespace, rfservice.GetSentinelName(rf)).Once().Return(ss, nil)
+	ms.On("GetDeployment", mock.Anything, namespace, rfservice.GetSentinelName(rf)).Once().Return(ss, nil)
 	mr := &mRedisService.Client{}
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	err := checker.CheckSentinelNumber(rf)
+	err := checker.CheckSentinelNumber(context.Background(), rf)
 	assert.Error(err)
 }
 
@@ -142,12 +144,12 @@ func TestCheckSentinelNumberTrue(t *testing.T) {
 		},
 	}
 	ms := &mK8SService.Services{}
-	ms.On("GetDeployment", namespace, rfservice.GetSentinelName(rf)).Once().Return(ss, nil)
+	ms.On("GetDeployment", mock.Anything, namespace, rfservice.GetSentinelName(rf)).Once().Return(ss, nil)
 	mr := &mRedisService.Client{}
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	err := checker.CheckSentinelNumber(rf)
+	err := checker.CheckSentinelNumber(context.Background(), rf)
 	assert.NoError(err)
 }
 
@@ -157,13 +159,12 @@ func TestCheckAllSlavesFromMasterGetStatefulSetError(t *testing.T) {
 	rf := generateRF()
 
 	ms := &mK8SService.Services{}
-	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(nil, errors.New(""))
-	ms.On("UpdatePodLabels", namespace, mock.AnythingOfType("string"), mock.Anything).Once().Return(nil)
+	ms.On("GetStatefulSetPods", mock.Anything, namespace, rfservice.GetRedisName(rf)).Once().Return(nil, errors.New(""))
 	mr := &mRedisService.Client{}
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	err := checker.CheckAllSlavesFromMaster("", rf)
+	err := checker.CheckAllSlavesFromMaster(context.Background(), "", rf)
 	assert.Error(err)
 }
 
@@ -184,14 +185,13 @@ func TestCheckAllSlavesFromMasterGetSlaveOfError(t *testing.T) {
 	}
 
 	ms := &mK8SService.Services{}
-	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
-	ms.On("UpdatePodLabels", namespace, mock.AnythingOfType("string"), mock.Anything).Once().Return(nil)
+	ms.On("GetStatefulSetPods", mock.Anything, namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
 	mr := &mRedisService.Client{}
-	mr.On("GetSlaveOf", "", "0", "").Once().Return("", errors.New(""))
+	mr.On("GetSlaveOf", mock.Anything, "", "0", "").Once().Return("", errors.New(""))
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	err := checker.CheckAllSlavesFromMaster("", rf)
+	err := checker.CheckAllSlavesFromMaster(context.Background(), "", rf)
 	assert.Error(err)
 }
 
@@ -212,14 +212,13 @@ func TestCheckAllSlavesFromMasterDifferentMaster(t *testing.T) {
 	}
 
 	ms := &mK8SService.Services{}
-	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
-	ms.On("UpdatePodLabels", namespace, mock.AnythingOfType("string"), mock.Anything).Once().Return(nil)
+	ms.On("GetStatefulSetPods", mock.Anything, namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
 	mr := &mRedisService.Client{}
-	mr.On("GetSlaveOf", "0.0.0.0", "0", "").Once().Return("1.1.1.1", nil)
+	mr.On("GetSlaveOf", mock.Anything, "0.0.0.0", "0", "").Once().Return("1.1.1.1", nil)
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	err := checker.CheckAllSlavesFromMaster("0.0.0.0", rf)
+	err := checker.CheckAllSlavesFromMaster(context.Background(), "0.0.0.0", rf)
 	assert.Error(err)
 }
 
@@ -240,15 +239,57 @@ func TestCheckAllSlavesFromMaster(t *testing.T) {
 	}
 
 	ms := &mK8SService.Services{}
-	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
-	ms.On("UpdatePodLabels", namespace, mock.AnythingOfType("string"), mock.Anything).Once().Return(nil)
+	ms.On("GetStatefulSetPods", mock.Anything, namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
+	mr := &mRedisService.Client{}
+	mr.On("GetSlaveOf", mock.Anything, "0.0.0.0", "0", "").Once().Return("1.1.1.1", nil)
+
+	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
+
+	err := checker.CheckAllSlavesFromMaster(context.Background(), "1.1.1.1", rf)
+	assert.NoError(err)
+}
+
+func TestCheckAllSlavesFromMasterHostnames(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF()
+	rf.Spec.AnnounceHostnames = true
+
+	pods := &corev1.PodList{
+		Items: []corev1.Pod{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "rfr-test-0",
+				},
+				Status: corev1.PodStatus{
+					PodIP: "0.0.0.0",
+					Phase: corev1.PodRunning,
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "rfr-test-1",
+				},
+				Status: corev1.PodStatus{
+					PodIP: "1.1.1.1",
+					Phase: corev1.PodRunning,
+				},
+			},
+		},
+	}
+
+	ms := &mK8SService.Services{}
+	ms.On("GetStatefulSetPods", mock.Anything, namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
 	mr := &mRedisService.Client{}
-	mr.On("GetSlaveOf", "0.0.0.0", "0", "").Once().Return("1.1.1.1", nil)
+	mr.On("GetSlaveOf", mock.Anything, "rfr-test-0.rfr-test.testns.svc", "0", "").Once().Return("", nil)
+	mr.On("GetSlaveOf", mock.Anything, "rfr-test-1.rfr-test.testns.svc", "0", "").Once().Return("rfr-test-0.rfr-test.testns.svc", nil)
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	err := checker.CheckAllSlavesFromMaster("1.1.1.1", rf)
+	err := checker.CheckAllSlavesFromMaster(context.Background(), "rfr-test-0.rfr-test.testns.svc", rf)
 	assert.NoError(err)
+	ms.AssertExpectations(t)
+	mr.AssertExpectations(t)
 }
 
 func TestCheckSentinelNumberInMemoryGetStatefulSetPodsError(t *testing.T) {
@@ -258,11 +299,11 @@ func TestCheckSentinelNumberInMemoryGetStatefulSetPodsError(t *testing.T) {
 
 	ms := &mK8SService.Services{}
 	mr := &mRedisService.Client{}
-	mr.On("GetNumberSentinelsInMemory", "1.1.1.1").Once().Return(int32(0), errors.New("expected error"))
+	mr.On("GetNumberSentinelsInMemory", mock.Anything, "1.1.1.1", "26379", "").Once().Return(int32(0), errors.New("expected error"))
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	err := checker.CheckSentinelNumberInMemory("1.1.1.1", rf)
+	err := checker.CheckSentinelNumberInMemory(context.Background(), "1.1.1.1", rf)
 	assert.Error(err)
 }
 
@@ -273,11 +314,11 @@ func TestCheckSentinelNumberInMemoryGetNumberSentinelInMemoryError(t *testing.T)
 
 	ms := &mK8SService.Services{}
 	mr := &mRedisService.Client{}
-	mr.On("GetNumberSentinelsInMemory", "1.1.1.1").Once().Return(int32(0), errors.New(""))
+	mr.On("GetNumberSentinelsInMemory", mock.Anything, "1.1.1.1", "26379", "").Once().Return(int32(0), errors.New(""))
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	err := checker.CheckSentinelNumberInMemory("1.1.1.1", rf)
+	err := checker.CheckSentinelNumberInMemory(context.Background(), "1.1.1.1", rf)
 	assert.Error(err)
 }
 
@@ -288,11 +329,11 @@ func TestCheckSentinelNumberInMemoryNumberMismatch(t *testing.T) {
 
 	ms := &mK8SService.Services{}
 	mr := &mRedisService.Client{}
-	mr.On("GetNumberSentinelsInMemory", "1.1.1.1").Once().Return(int32(4), nil)
+	mr.On("GetNumberSentinelsInMemory", mock.Anything, "1.1.1.1", "26379", "").Once().Return(int32(4), nil)
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	err := checker.CheckSentinelNumberInMemory("1.1.1.1", rf)
+	err := checker.CheckSentinelNumberInMemory(context.Background(), "1.1.1.1", rf)
 	assert.Error(err)
 }
 
@@ -303,11 +344,11 @@ func TestCheckSentinelNumberInMemory(t *testing.T) {
 
 	ms := &mK8SService.Services{}
 	mr := &mRedisService.Client{}
-	mr.On("GetNumberSentinelsInMemory", "1.1.1.1").Once().Return(int32(3), nil)
+	mr.On("GetNumberSentinelsInMemory", mock.Anything, "1.1.1.1", "26379", "").Once().Return(int32(3), nil)
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	err := checker.CheckSentinelNumberInMemory("1.1.1.1", rf)
+	err := checker.CheckSentinelNumberInMemory(context.Background(), "1.1.1.1", rf)
 	assert.NoError(err)
 }
 
@@ -318,11 +359,11 @@ func TestCheckSentinelSlavesNumberInMemoryGetNumberSentinelSlavesInMemoryError(t
 
 	ms := &mK8SService.Services{}
 	mr := &mRedisService.Client{}
-	mr.On("GetNumberSentinelSlavesInMemory", "1.1.1.1").Once().Return(int32(0), errors.New(""))
+	mr.On("GetNumberSentinelSlavesInMemory", mock.Anything, "1.1.1.1", "26379", "").Once().Return(int32(0), errors.New(""))
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	err := checker.CheckSentinelSlavesNumberInMemory("1.1.1.1", rf)
+	err := checker.CheckSentinelSlavesNumberInMemory(context.Background(), "1.1.1.1", rf)
 	assert.Error(err)
 }
 
@@ -333,11 +374,11 @@ func TestCheckSentinelSlavesNumberInMemoryReplicasMismatch(t *testing.T) {
 
 	ms := &mK8SService.Services{}
 	mr := &mRedisService.Client{}
-	mr.On("GetNumberSentinelSlavesInMemory", "1.1.1.1").Once().Return(int32(3), nil)
+	mr.On("GetNumberSentinelSlavesInMemory", mock.Anything, "1.1.1.1", "26379", "").Once().Return(int32(3), nil)
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	err := checker.CheckSentinelSlavesNumberInMemory("1.1.1.1", rf)
+	err := checker.CheckSentinelSlavesNumberInMemory(context.Background(), "1.1.1.1", rf)
 	assert.Error(err)
 }
 
@@ -349,89 +390,101 @@ func TestCheckSentinelSlavesNumberInMemory(t *testing.T) {
 
 	ms := &mK8SService.Services{}
 	mr := &mRedisService.Client{}
-	mr.On("GetNumberSentinelSlavesInMemory", "1.1.1.1").Once().Return(int32(4), nil)
+	mr.On("GetNumberSentinelSlavesInMemory", mock.Anything, "1.1.1.1", "26379", "").Once().Return(int32(4), nil)
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	err := checker.CheckSentinelSlavesNumberInMemory("1.1.1.1", rf)
+	err := checker.CheckSentinelSlavesNumberInMemory(context.Background(), "1.1.1.1", rf)
 	assert.NoError(err)
 }
 
 func TestCheckSentinelMonitorGetSentinelMonitorError(t *testing.T) {
 	assert := assert.New(t)
 
+	rf := generateRF()
+
 	ms := &mK8SService.Services{}
 	mr := &mRedisService.Client{}
-	mr.On("GetSentinelMonitor", "0.0.0.0").Once().Return("", "", errors.New(""))
+	mr.On("GetSentinelMonitor", mock.Anything, "0.0.0.0", "26379", "master0", "").Once().Return("", "", errors.New(""))
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	err := checker.CheckSentinelMonitor("0.0.0.0", "1.1.1.1")
+	err := checker.CheckSentinelMonitor(context.Background(), "0.0.0.0", rf, "1.1.1.1")
 	assert.Error(err)
 }
 
 func TestCheckSentinelMonitorMismatch(t *testing.T) {
 	assert := assert.New(t)
 
+	rf := generateRF()
+
 	ms := &mK8SService.Services{}
 	mr := &mRedisService.Client{}
-	mr.On("GetSentinelMonitor", "0.0.0.0").Once().Return("2.2.2.2", "6379", nil)
+	mr.On("GetSentinelMonitor", mock.Anything, "0.0.0.0", "26379", "master0", "").Once().Return("2.2.2.2", "6379", nil)
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	err := checker.CheckSentinelMonitor("0.0.0.0", "1.1.1.1")
+	err := checker.CheckSentinelMonitor(context.Background(), "0.0.0.0", rf, "1.1.1.1")
 	assert.Error(err)
 }
 
 func TestCheckSentinelMonitor(t *testing.T) {
 	assert := assert.New(t)
 
+	rf := generateRF()
+
 	ms := &mK8SService.Services{}
 	mr := &mRedisService.Client{}
-	mr.On("GetSentinelMonitor", "0.0.0.0").Once().Return("1.1.1.1", "6379", nil)
+	mr.On("GetSentinelMonitor", mock.Anything, "0.0.0.0", "26379", "master0", "").Once().Return("1.1.1.1", "6379", nil)
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	err := checker.CheckSentinelMonitor("0.0.0.0", "1.1.1.1")
+	err := checker.CheckSentinelMonitor(context.Background(), "0.0.0.0", rf, "1.1.1.1")
 	assert.NoError(err)
 }
 
 func TestCheckSentinelMonitorWithPort(t *testing.T) {
 	assert := assert.New(t)
 
+	rf := generateRF()
+
 	ms := &mK8SService.Services{}
 	mr := &mRedisService.Client{}
-	mr.On("GetSentinelMonitor", "0.0.0.0").Once().Return("1.1.1.1", "6379", nil)
+	mr.On("GetSentinelMonitor", mock.Anything, "0.0.0.0", "26379", "master0", "").Once().Return("1.1.1.1", "6379", nil)
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	err := checker.CheckSentinelMonitor("0.0.0.0", "1.1.1.1", "6379")
+	err := checker.CheckSentinelMonitor(context.Background(), "0.0.0.0", rf, "1.1.1.1", "6379")
 	assert.NoError(err)
 }
 
 func TestCheckSentinelMonitorWithPortMismatch(t *testing.T) {
 	assert := assert.New(t)
 
+	rf := generateRF()
+
 	ms := &mK8SService.Services{}
 	mr := &mRedisService.Client{}
-	mr.On("GetSentinelMonitor", "0.0.0.0").Once().Return("1.1.1.1", "6379", nil)
+	mr.On("GetSentinelMonitor", mock.Anything, "0.0.0.0", "26379", "master0", "").Once().Return("1.1.1.1", "6379", nil)
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	err := checker.CheckSentinelMonitor("0.0.0.0", "0.0.0.0", "6379")
+	err := checker.CheckSentinelMonitor(context.Background(), "0.0.0.0", rf, "0.0.0.0", "6379")
 	assert.Error(err)
 }
 
 func TestCheckSentinelMonitorWithPortIPMismatch(t *testing.T) {
 	assert := assert.New(t)
 
+	rf := generateRF()
+
 	ms := &mK8SService.Services{}
 	mr := &mRedisService.Client{}
-	mr.On("GetSentinelMonitor", "0.0.0.0").Once().Return("1.1.1.1", "6379", nil)
+	mr.On("GetSentinelMonitor", mock.Anything, "0.0.0.0", "26379", "master0", "").Once().Return("1.1.1.1", "6379", nil)
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	err := checker.CheckSentinelMonitor("0.0.0.0", "1.1.1.1", "6380")
+	err := checker.CheckSentinelMonitor(context.Background(), "0.0.0.0", rf, "1.1.1.1", "6380")
 	assert.Error(err)
 }
 
@@ -441,12 +494,12 @@ func TestGetMasterIPGetStatefulSetPodsError(t *testing.T) {
 	rf := generateRF()
 
 	ms := &mK8SService.Services{}
-	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(nil, errors.New(""))
+	ms.On("GetStatefulSetPods", mock.Anything, namespace, rfservice.GetRedisName(rf)).Once().Return(nil, errors.New(""))
 	mr := &mRedisService.Client{}
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	_, err := checker.GetMasterIP(rf)
+	_, err := checker.GetMasterIP(context.Background(), rf)
 	assert.Error(err)
 }
 
@@ -467,13 +520,13 @@ func TestGetMasterIPIsMasterError(t *testing.T) {
 	}
 
 	ms := &mK8SService.Services{}
-	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
+	ms.On("GetStatefulSetPods", mock.Anything, namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
 	mr := &mRedisService.Client{}
-	mr.On("IsMaster", "0.0.0.0", "0", "").Once().Return(false, errors.New(""))
+	mr.On("IsMaster", mock.Anything, "0.0.0.0", "0", "").Once().Return(false, errors.New(""))
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	_, err := checker.GetMasterIP(rf)
+	_, err := checker.GetMasterIP(context.Background(), rf)
 	assert.Error(err)
 }
 
@@ -500,14 +553,14 @@ func TestGetMasterIPMultipleMastersError(t *testing.T) {
 	}
 
 	ms := &mK8SService.Services{}
-	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
+	ms.On("GetStatefulSetPods", mock.Anything, namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
 	mr := &mRedisService.Client{}
-	mr.On("IsMaster", "0.0.0.0", "0", "").Once().Return(true, nil)
-	mr.On("IsMaster", "1.1.1.1", "0", "").Once().Return(true, nil)
+	mr.On("IsMaster", mock.Anything, "0.0.0.0", "0", "").Once().Return(true, nil)
+	mr.On("IsMaster", mock.Anything, "1.1.1.1", "0", "").Once().Return(true, nil)
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	_, err := checker.GetMasterIP(rf)
+	_, err := checker.GetMasterIP(context.Background(), rf)
 	assert.Error(err)
 }
 
@@ -534,30 +587,72 @@ func TestGetMasterIP(t *testing.T) {
 	}
 
 	ms := &mK8SService.Services{}
-	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
+	ms.On("GetStatefulSetPods", mock.Anything, namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
 	mr := &mRedisService.Client{}
-	mr.On("IsMaster", "0.0.0.0", "0", "").Once().Return(true, nil)
-	mr.On("IsMaster", "1.1.1.1", "0", "").Once().Return(false, nil)
+	mr.On("IsMaster", mock.Anything, "0.0.0.0", "0", "").Once().Return(true, nil)
+	mr.On("IsMaster", mock.Anything, "1.1.1.1", "0", "").Once().Return(false, nil)
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	master, err := checker.GetMasterIP(rf)
+	master, err := checker.GetMasterIP(context.Background(), rf)
 	assert.NoError(err)
 	assert.Equal("0.0.0.0", master, "the master should be the expected")
 }
 
+func TestGetMasterIPHostnames(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF()
+	rf.Spec.AnnounceHostnames = true
+
+	pods := &corev1.PodList{
+		Items: []corev1.Pod{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "rfr-test-0",
+				},
+				Status: corev1.PodStatus{
+					PodIP: "0.0.0.0",
+					Phase: corev1.PodRunning,
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "rfr-test-1",
+				},
+				Status: corev1.PodStatus{
+					PodIP: "1.1.1.1",
+					Phase: corev1.PodRunning,
+				},
+			},
+		},
+	}
+
+	ms := &mK8SService.Services{}
+	ms.On("GetStatefulSetPods", mock.Anything, namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
+	mr := &mRedisService.Client{}
+	mr.On("IsMaster", mock.Anything, "rfr-test-0.rfr-test.testns.svc", "0", "").Once().Return(false, nil)
+	mr.On("IsMaster", mock.Anything, "rfr-test-1.rfr-test.testns.svc", "0", "").Once().Return(true, nil)
+
+	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
+
+	master, err := checker.GetMasterIP(context.Background(), rf)
+	assert.NoError(err)
+	assert.Equal("rfr-test-1.rfr-test.testns.svc", master, "the master should be the expected")
+}
+
 func TestGetNumberMastersGetStatefulSetPodsError(t *testing.T) {
 	assert := assert.New(t)
 
 	rf := generateRF()
 
 	ms := &mK8SService.Services{}
-	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(nil, errors.New(""))
+	ms.On("GetStatefulSetPods", mock.Anything, namespace, rfservice.GetRedisName(rf)).Once().Return(nil, errors.New(""))
 	mr := &mRedisService.Client{}
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	_, err := checker.GetNumberMasters(rf)
+	_, err := checker.GetNumberMasters(context.Background(), rf)
 	assert.Error(err)
 }
 
@@ -578,13 +673,13 @@ func TestGetNumberMastersIsMasterError(t *testing.T) {
 	}
 
 	ms := &mK8SService.Services{}
-	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
+	ms.On("GetStatefulSetPods", mock.Anything, namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
 	mr := &mRedisService.Client{}
-	mr.On("IsMaster", "0.0.0.0", "0", "").Once().Return(true, errors.New(""))
+	mr.On("IsMaster", mock.Anything, "0.0.0.0", "0", "").Once().Return(true, errors.New(""))
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	_, err := checker.GetNumberMasters(rf)
+	_, err := checker.GetNumberMasters(context.Background(), rf)
 	assert.NoError(err)
 }
 
@@ -611,14 +706,14 @@ func TestGetNumberMasters(t *testing.T) {
 	}
 
 	ms := &mK8SService.Services{}
-	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
+	ms.On("GetStatefulSetPods", mock.Anything, namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
 	mr := &mRedisService.Client{}
-	mr.On("IsMaster", "0.0.0.0", "0", "").Once().Return(true, nil)
-	mr.On("IsMaster", "1.1.1.1", "0", "").Once().Return(false, nil)
+	mr.On("IsMaster", mock.Anything, "0.0.0.0", "0", "").Once().Return(true, nil)
+	mr.On("IsMaster", mock.Anything, "1.1.1.1", "0", "").Once().Return(false, nil)
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	masterNumber, err := checker.GetNumberMasters(rf)
+	masterNumber, err := checker.GetNumberMasters(context.Background(), rf)
 	assert.NoError(err)
 	assert.Equal(1, masterNumber, "the master number should be ok")
 }
@@ -646,14 +741,14 @@ func TestGetNumberMastersTwo(t *testing.T) {
 	}
 
 	ms := &mK8SService.Services{}
-	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
+	ms.On("GetStatefulSetPods", mock.Anything, namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
 	mr := &mRedisService.Client{}
-	mr.On("IsMaster", "0.0.0.0", "0", "").Once().Return(true, nil)
-	mr.On("IsMaster", "1.1.1.1", "0", "").Once().Return(true, nil)
+	mr.On("IsMaster", mock.Anything, "0.0.0.0", "0", "").Once().Return(true, nil)
+	mr.On("IsMaster", mock.Anything, "1.1.1.1", "0", "").Once().Return(true, nil)
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	masterNumber, err := checker.GetNumberMasters(rf)
+	masterNumber, err := checker.GetNumberMasters(context.Background(), rf)
 	assert.NoError(err)
 	assert.Equal(2, masterNumber, "the master number should be ok")
 }
@@ -664,12 +759,12 @@ func TestGetMaxRedisPodTimeGetStatefulSetPodsError(t *testing.T) {
 	rf := generateRF()
 
 	ms := &mK8SService.Services{}
-	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(nil, errors.New(""))
+	ms.On("GetStatefulSetPods", mock.Anything, namespace, rfservice.GetRedisName(rf)).Once().Return(nil, errors.New(""))
 	mr := &mRedisService.Client{}
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	_, err := checker.GetMaxRedisPodTime(rf)
+	_, err := checker.GetMaxRedisPodTime(context.Background(), rf)
 	assert.Error(err)
 }
 
@@ -702,12 +797,12 @@ func TestGetMaxRedisPodTime(t *testing.T) {
 	}
 
 	ms := &mK8SService.Services{}
-	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
+	ms.On("GetStatefulSetPods", mock.Anything, namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
 	mr := &mRedisService.Client{}
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	maxTime, err := checker.GetMaxRedisPodTime(rf)
+	maxTime, err := checker.GetMaxRedisPodTime(context.Background(), rf)
 	assert.NoError(err)
 
 	expected := now.Sub(oneHour).Round(time.Second)
@@ -751,35 +846,35 @@ func TestGetRedisPodsNames(t *testing.T) {
 	}
 
 	ms := &mK8SService.Services{}
-	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
+	ms.On("GetStatefulSetPods", mock.Anything, namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
 	mr := &mRedisService.Client{}
-	mr.On("IsMaster", "0.0.0.0", "0", "").Twice().Return(false, nil)
-	mr.On("IsMaster", "1.1.1.1", "0", "").Once().Return(true, nil)
+	mr.On("IsMaster", mock.Anything, "0.0.0.0", "0", "").Twice().Return(false, nil)
+	mr.On("IsMaster", mock.Anything, "1.1.1.1", "0", "").Once().Return(true, nil)
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
-	master, err := checker.GetRedisesMasterPod(rf)
+	master, err := checker.GetRedisesMasterPod(context.Background(), rf)
 
 	assert.NoError(err)
 
 	assert.Equal(master, "master")
 
-	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
-	mr.On("IsMaster", "0.0.0.0", "0", "").Twice().Return(false, nil)
-	mr.On("IsMaster", "1.1.1.1", "0", "").Once().Return(true, nil)
+	ms.On("GetStatefulSetPods", mock.Anything, namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
+	mr.On("IsMaster", mock.Anything, "0.0.0.0", "0", "").Twice().Return(false, nil)
+	mr.On("IsMaster", mock.Anything, "1.1.1.1", "0", "").Once().Return(true, nil)
 
-	namePods, err := checker.GetRedisesSlavesPods(rf)
+	namePods, err := checker.GetRedisesSlavesPods(context.Background(), rf)
 
 	assert.NoError(err)
 
 	assert.Equal(namePods, []string{"slave1", "slave2"})
 }
 
-func TestGetStatefulSetUpdateRevision(t *testing.T) {
+func TestGetStatefulSetUpdateRevisions(t *testing.T) {
 	tests := []struct {
-		name             string
-		ss               *appsv1.StatefulSet
-		expectedUVersion string
-		expectedError    error
+		name              string
+		ss                *appsv1.StatefulSet
+		expectedRevisions map[string]string
+		expectedError     error
 	}{
 		{
 			name: "revision ok",
@@ -788,14 +883,14 @@ func TestGetStatefulSetUpdateRevision(t *testing.T) {
 					UpdateRevision: "10",
 				},
 			},
-			expectedUVersion: "10",
-			expectedError:    nil,
+			expectedRevisions: map[string]string{"rfr-test": "10"},
+			expectedError:     nil,
 		},
 		{
-			name:             "no stateful set",
-			ss:               nil,
-			expectedUVersion: "",
-			expectedError:    errors.New("not found"),
+			name:              "no stateful set",
+			ss:                nil,
+			expectedRevisions: nil,
+			expectedError:     errors.New("not found"),
 		},
 	}
 
@@ -804,11 +899,11 @@ func TestGetStatefulSetUpdateRevision(t *testing.T) {
 
 		rf := generateRF()
 		ms := &mK8SService.Services{}
-		ms.On("GetStatefulSet", namespace, rfservice.GetRedisName(rf)).Once().Return(test.ss, nil)
+		ms.On("GetStatefulSet", mock.Anything, namespace, rfservice.GetRedisName(rf)).Once().Return(test.ss, nil)
 		mr := &mRedisService.Client{}
 
 		checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
-		version, err := checker.GetStatefulSetUpdateRevision(rf)
+		revisions, err := checker.GetStatefulSetUpdateRevisions(context.Background(), rf)
 
 		if test.expectedError == nil {
 			assert.NoError(err)
@@ -816,11 +911,29 @@ func TestGetStatefulSetUpdateRevision(t *testing.T) {
 			assert.Error(err)
 		}
 
-		assert.Equal(version, test.expectedUVersion)
+		assert.Equal(test.expectedRevisions, revisions)
 	}
 
 }
 
+func TestGetStatefulSetUpdateRevisionsZones(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF()
+	rf.Spec.Redis.Zones = &redisfailoverv1.ZonesSettings{Names: []string{"a", "b"}}
+	ms := &mK8SService.Services{}
+	ms.On("GetStatefulSet", mock.Anything, namespace, "rfr-test-a").Once().Return(&appsv1.StatefulSet{Status: appsv1.StatefulSetStatus{UpdateRevision: "1"}}, nil)
+	ms.On("GetStatefulSet", mock.Anything, namespace, "rfr-test-b").Once().Return(&appsv1.StatefulSet{Status: appsv1.StatefulSetStatus{UpdateRevision: "2"}}, nil)
+	mr := &mRedisService.Client{}
+
+	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
+	revisions, err := checker.GetStatefulSetUpdateRevisions(context.Background(), rf)
+
+	assert.NoError(err)
+	assert.Equal(map[string]string{"rfr-test-a": "1", "rfr-test-b": "2"}, revisions)
+	ms.AssertExpectations(t)
+}
+
 func TestGetRedisRevisionHash(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -853,11 +966,11 @@ func TestGetRedisRevisionHash(t *testing.T) {
 
 		rf := generateRF()
 		ms := &mK8SService.Services{}
-		ms.On("GetPod", namespace, "namepod").Once().Return(test.pod, nil)
+		ms.On("GetPod", mock.Anything, namespace, "namepod").Once().Return(test.pod, nil)
 		mr := &mRedisService.Client{}
 
 		checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
-		hash, err := checker.GetRedisRevisionHash("namepod", rf)
+		hash, err := checker.GetRedisRevisionHash(context.Background(), "namepod", rf)
 
 		if test.expectedError == nil {
 			assert.NoError(err)
@@ -939,20 +1052,386 @@ func TestClusterRunning(t *testing.T) {
 	}
 
 	ms := &mK8SService.Services{}
-	ms.On("GetStatefulSetPods", namespace, rfservice.GetSentinelName(rf)).Once().Return(allRunning, nil)
-	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(allRunning, nil)
+	ms.On("GetStatefulSetPods", mock.Anything, namespace, rfservice.GetSentinelName(rf)).Once().Return(allRunning, nil)
+	ms.On("GetStatefulSetPods", mock.Anything, namespace, rfservice.GetRedisName(rf)).Once().Return(allRunning, nil)
+	mr := &mRedisService.Client{}
+
+	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
+
+	assert.True(checker.IsClusterRunning(context.Background(), rf))
+
+	ms.On("GetStatefulSetPods", mock.Anything, namespace, rfservice.GetSentinelName(rf)).Once().Return(allRunning, nil)
+	ms.On("GetStatefulSetPods", mock.Anything, namespace, rfservice.GetRedisName(rf)).Once().Return(notAllReplicas, nil)
+	assert.False(checker.IsClusterRunning(context.Background(), rf))
+
+	ms.On("GetStatefulSetPods", mock.Anything, namespace, rfservice.GetSentinelName(rf)).Once().Return(notAllRunning, nil)
+	ms.On("GetStatefulSetPods", mock.Anything, namespace, rfservice.GetRedisName(rf)).Once().Return(allRunning, nil)
+	assert.False(checker.IsClusterRunning(context.Background(), rf))
+
+}
+
+func TestGetRedisesExternalAddresses(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings redisfailoverv1.ExternalAccessSettings
+		service  corev1.Service
+		expected map[string]string
+	}{
+		{
+			name: "node port",
+			settings: redisfailoverv1.ExternalAccessSettings{
+				Type:        corev1.ServiceTypeNodePort,
+				HostAddress: "redis.example.com",
+			},
+			service: corev1.Service{
+				Spec: corev1.ServiceSpec{
+					Type:  corev1.ServiceTypeNodePort,
+					Ports: []corev1.ServicePort{{Port: 6379, NodePort: 31000}},
+				},
+			},
+			expected: map[string]string{"0.0.0.0": "redis.example.com:31000"},
+		},
+		{
+			name: "load balancer ip",
+			settings: redisfailoverv1.ExternalAccessSettings{
+				Type: corev1.ServiceTypeLoadBalancer,
+			},
+			service: corev1.Service{
+				Spec: corev1.ServiceSpec{
+					Type:  corev1.ServiceTypeLoadBalancer,
+					Ports: []corev1.ServicePort{{Port: 6379}},
+				},
+				Status: corev1.ServiceStatus{
+					LoadBalancer: corev1.LoadBalancerStatus{
+						Ingress: []corev1.LoadBalancerIngress{{IP: "10.0.0.1"}},
+					},
+				},
+			},
+			expected: map[string]string{"0.0.0.0": "10.0.0.1:6379"},
+		},
+		{
+			name: "load balancer hostname",
+			settings: redisfailoverv1.ExternalAccessSettings{
+				Type: corev1.ServiceTypeLoadBalancer,
+			},
+			service: corev1.Service{
+				Spec: corev1.ServiceSpec{
+					Type:  corev1.ServiceTypeLoadBalancer,
+					Ports: []corev1.ServicePort{{Port: 6379}},
+				},
+				Status: corev1.ServiceStatus{
+					LoadBalancer: corev1.LoadBalancerStatus{
+						Ingress: []corev1.LoadBalancerIngress{{Hostname: "lb.example.com"}},
+					},
+				},
+			},
+			expected: map[string]string{"0.0.0.0": "lb.example.com:6379"},
+		},
+		{
+			name: "load balancer pending",
+			settings: redisfailoverv1.ExternalAccessSettings{
+				Type: corev1.ServiceTypeLoadBalancer,
+			},
+			service: corev1.Service{
+				Spec: corev1.ServiceSpec{
+					Type:  corev1.ServiceTypeLoadBalancer,
+					Ports: []corev1.ServicePort{{Port: 6379}},
+				},
+			},
+			expected: map[string]string{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateRF()
+			rf.Spec.ExternalAccess = &test.settings
+
+			pods := &corev1.PodList{
+				Items: []corev1.Pod{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "rfr-test-0",
+						},
+						Status: corev1.PodStatus{
+							PodIP: "0.0.0.0",
+							Phase: corev1.PodRunning,
+						},
+					},
+				},
+			}
+
+			ms := &mK8SService.Services{}
+			ms.On("GetStatefulSetPods", mock.Anything, namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
+			ms.On("GetService", mock.Anything, namespace, "rfr-test-0").Once().Return(&test.service, nil)
+			mr := &mRedisService.Client{}
+
+			checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
+
+			addresses, err := checker.GetRedisesExternalAddresses(context.Background(), rf)
+			assert.NoError(err)
+			assert.Equal(test.expected, addresses)
+		})
+	}
+}
+
+func TestCheckAllSlavesFromMasterExternalAccess(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF()
+	rf.Spec.ExternalAccess = &redisfailoverv1.ExternalAccessSettings{
+		Type:        corev1.ServiceTypeNodePort,
+		HostAddress: "redis.example.com",
+	}
+
+	pods := &corev1.PodList{
+		Items: []corev1.Pod{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "rfr-test-0",
+				},
+				Status: corev1.PodStatus{
+					PodIP: "0.0.0.0",
+					Phase: corev1.PodRunning,
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "rfr-test-1",
+				},
+				Status: corev1.PodStatus{
+					PodIP: "1.1.1.1",
+					Phase: corev1.PodRunning,
+				},
+			},
+		},
+	}
+	svc := &corev1.Service{
+		Spec: corev1.ServiceSpec{
+			Type:  corev1.ServiceTypeNodePort,
+			Ports: []corev1.ServicePort{{Port: 6379, NodePort: 31000}},
+		},
+	}
+
+	ms := &mK8SService.Services{}
+	ms.On("GetStatefulSetPods", mock.Anything, namespace, rfservice.GetRedisName(rf)).Return(pods, nil)
+	ms.On("GetService", mock.Anything, namespace, mock.Anything).Return(svc, nil)
 	mr := &mRedisService.Client{}
+	mr.On("GetSlaveOf", mock.Anything, "0.0.0.0", "0", "").Once().Return("redis.example.com", nil)
+	mr.On("GetSlaveOf", mock.Anything, "1.1.1.1", "0", "").Once().Return("", nil)
 
 	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
 
-	assert.True(checker.IsClusterRunning(rf))
+	err := checker.CheckAllSlavesFromMaster(context.Background(), "1.1.1.1", rf)
+	assert.NoError(err)
+}
 
-	ms.On("GetStatefulSetPods", namespace, rfservice.GetSentinelName(rf)).Once().Return(allRunning, nil)
-	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(notAllReplicas, nil)
-	assert.False(checker.IsClusterRunning(rf))
+func TestGetReplicaOfMaster(t *testing.T) {
+	tests := []struct {
+		name           string
+		masterLabeled  bool
+		expectedMaster string
+		expectedError  bool
+	}{
+		{
+			name:           "returns the pod labeled as master by the source operator",
+			masterLabeled:  true,
+			expectedMaster: "1.1.1.1",
+		},
+		{
+			name:          "errors while the source has no master",
+			masterLabeled: false,
+			expectedError: true,
+		},
+	}
 
-	ms.On("GetStatefulSetPods", namespace, rfservice.GetSentinelName(rf)).Once().Return(notAllRunning, nil)
-	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(allRunning, nil)
-	assert.False(checker.IsClusterRunning(rf))
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateRF()
+			rf.Spec.ReplicaOf = &redisfailoverv1.ReplicaOfSettings{
+				RedisFailoverRef: redisfailoverv1.RedisFailoverReference{Namespace: "source-ns", Name: "source"},
+			}
+			source := &redisfailoverv1.RedisFailover{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "source",
+					Namespace: "source-ns",
+				},
+				Spec: redisfailoverv1.RedisFailoverSpec{
+					Redis: redisfailoverv1.RedisSettings{
+						Port: 6380,
+					},
+				},
+			}
+
+			masterLabels := map[string]string{}
+			if test.masterLabeled {
+				masterLabels["redisfailovers-role"] = "master"
+			}
+			pods := &corev1.PodList{
+				Items: []corev1.Pod{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Labels: map[string]string{"redisfailovers-role": "slave"},
+						},
+						Status: corev1.PodStatus{
+							PodIP: "0.0.0.0",
+							Phase: corev1.PodRunning,
+						},
+					},
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Labels: masterLabels,
+						},
+						Status: corev1.PodStatus{
+							PodIP: "1.1.1.1",
+							Phase: corev1.PodRunning,
+						},
+					},
+				},
+			}
+
+			ms := &mK8SService.Services{}
+			ms.On("GetRedisFailover", mock.Anything, "source-ns", "source").Once().Return(source, nil)
+			ms.On("GetStatefulSetPods", mock.Anything, "source-ns", "rfr-source").Once().Return(pods, nil)
+			mr := &mRedisService.Client{}
+
+			checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
+
+			master, port, err := checker.GetReplicaOfMaster(context.Background(), rf)
+			if test.expectedError {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+				assert.Equal(test.expectedMaster, master)
+				assert.Equal("6380", port)
+			}
+			ms.AssertExpectations(t)
+		})
+	}
+}
 
+func TestGetBootstrapMaster(t *testing.T) {
+	tests := []struct {
+		name         string
+		bootstrap    redisfailoverv1.BootstrapSettings
+		sentinelErrs []bool
+		password     string
+		expectedHost string
+		expectedPort string
+		expectError  bool
+	}{
+		{
+			name:         "static host and port",
+			bootstrap:    redisfailoverv1.BootstrapSettings{Host: "127.0.0.1", Port: "6379"},
+			expectedHost: "127.0.0.1",
+			expectedPort: "6379",
+		},
+		{
+			name:         "asks the next sentinel when one fails",
+			bootstrap:    redisfailoverv1.BootstrapSettings{Sentinels: []string{"10.0.0.1:26379", "10.0.0.2:26379"}, MasterName: "mymaster"},
+			sentinelErrs: []bool{true, false},
+			expectedHost: "10.0.1.1",
+			expectedPort: "6380",
+		},
+		{
+			name: "authenticates to the sentinels",
+			bootstrap: redisfailoverv1.BootstrapSettings{
+				Sentinels:    []string{"10.0.0.1:26379"},
+				MasterName:   "mymaster",
+				SentinelAuth: redisfailoverv1.AuthSettings{SecretPath: "sentinel-auth"},
+			},
+			sentinelErrs: []bool{false},
+			password:     "sentinelpass",
+			expectedHost: "10.0.1.1",
+			expectedPort: "6380",
+		},
+		{
+			name:         "errors when no sentinel answers",
+			bootstrap:    redisfailoverv1.BootstrapSettings{Sentinels: []string{"10.0.0.1:26379"}, MasterName: "mymaster"},
+			sentinelErrs: []bool{true},
+			expectError:  true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateRF()
+			rf.Spec.BootstrapNode = &test.bootstrap
+
+			ms := &mK8SService.Services{}
+			mr := &mRedisService.Client{}
+			if test.password != "" {
+				ms.On("GetSecret", mock.Anything, namespace, test.bootstrap.SentinelAuth.SecretPath).Once().Return(&corev1.Secret{Data: map[string][]byte{"password": []byte(test.password)}}, nil)
+			}
+			for i, sentinel := range test.bootstrap.Sentinels {
+				if test.sentinelErrs[i] {
+					mr.On("GetMasterAddrByName", mock.Anything, sentinel, "mymaster", test.password, mock.Anything).Once().Return("", "", errors.New(""))
+				} else {
+					mr.On("GetMasterAddrByName", mock.Anything, sentinel, "mymaster", test.password, mock.Anything).Once().Return("10.0.1.1", "6380", nil)
+				}
+			}
+
+			checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
+
+			host, port, err := checker.GetBootstrapMaster(context.Background(), rf)
+			if test.expectError {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+				assert.Equal(test.expectedHost, host)
+				assert.Equal(test.expectedPort, port)
+			}
+			mr.AssertExpectations(t)
+			ms.AssertExpectations(t)
+		})
+	}
+}
+
+func TestIsPasswordRotating(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     map[string][]byte
+		expected bool
+	}{
+		{
+			name:     "no previous password",
+			data:     map[string][]byte{"password": []byte("newpass")},
+			expected: false,
+		},
+		{
+			name:     "previous password differs",
+			data:     map[string][]byte{"password": []byte("newpass"), "previousPassword": []byte("oldpass")},
+			expected: true,
+		},
+		{
+			name:     "previous password equal to the new one",
+			data:     map[string][]byte{"password": []byte("newpass"), "previousPassword": []byte("newpass")},
+			expected: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateRF()
+			rf.Spec.Auth.SecretPath = "redis-auth"
+
+			ms := &mK8SService.Services{}
+			ms.On("GetSecret", mock.Anything, namespace, "redis-auth").Return(&corev1.Secret{Data: test.data}, nil)
+			mr := &mRedisService.Client{}
+
+			checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
+
+			rotating, err := checker.IsPasswordRotating(context.Background(), rf)
+			assert.NoError(err)
+			assert.Equal(test.expected, rotating)
+		})
+	}
 }
@@ -1,10 +1,15 @@
 package service
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"time"
 
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
@@ -18,16 +23,24 @@ import (
 // RedisFailoverClient has the minimumm methods that a Redis failover controller needs to satisfy
 // in order to talk with K8s
 type RedisFailoverClient interface {
-	EnsureSentinelService(rFailover *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error
-	EnsureSentinelConfigMap(rFailover *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error
-	EnsureSentinelStatefulset(rFailover *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error
-	EnsureRedisStatefulset(rFailover *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error
-	EnsureRedisService(rFailover *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error
-	EnsureRedisShutdownConfigMap(rFailover *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error
-	EnsureRedisReadinessConfigMap(rFailover *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error
-	EnsureRedisConfigMap(rFailover *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error
-	EnsureNotPresentRedisService(rFailover *redisfailoverv1.RedisFailover) error
-	EnsurePredixyAllResources(rFailover *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error
+	EnsureSentinelService(ctx context.Context, rFailover *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error
+	EnsureSentinelConfigMap(ctx context.Context, rFailover *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error
+	EnsureSentinelStatefulset(ctx context.Context, rFailover *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error
+	EnsureRedisStatefulset(ctx context.Context, rFailover *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error
+	EnsureRedisService(ctx context.Context, rFailover *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error
+	EnsureRedisShutdownConfigMap(ctx context.Context, rFailover *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error
+	EnsureRedisReadinessConfigMap(ctx context.Context, rFailover *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error
+	EnsureRedisConfigMap(ctx context.Context, rFailover *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error
+	EnsureNotPresentRedisService(ctx context.Context, rFailover *redisfailoverv1.RedisFailover) error
+	EnsureRedisZonesUnchanged(ctx context.Context, rFailover *redisfailoverv1.RedisFailover) error
+	EnsureRedisMasterService(ctx context.Context, rFailover *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error
+	EnsureRedisReplicasService(ctx context.Context, rFailover *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error
+	EnsureExternalAccessServices(ctx context.Context, rFailover *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error
+	EnsureProxyAllResources(ctx context.Context, rFailover *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error
+	EnsureProxyConfigMap(ctx context.Context, rFailover *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference, backends ProxyBackends) error
+	EnsureProxyDeployment(ctx context.Context, rFailover *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error
+	EnsureMonitoring(ctx context.Context, rFailover *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error
+	EnsureNetworkPolicies(ctx context.Context, rFailover *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error
 }
 
 // RedisFailoverKubeClient implements the required methods to talk with kubernetes
@@ -71,68 +84,131 @@ func generateRedisSlaveRoleLabel() map[string]string {
 }
 
 // EnsureSentinelService makes sure the sentinel service exists
-func (r *RedisFailoverKubeClient) EnsureSentinelService(rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error {
+func (r *RedisFailoverKubeClient) EnsureSentinelService(ctx context.Context, rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error {
 	svc := generateSentinelService(rf, labels, ownerRefs)
-	err := r.K8SService.CreateOrUpdateService(rf.Namespace, svc)
+	err := r.K8SService.CreateOrUpdateService(ctx, rf.Namespace, svc)
 	r.setEnsureOperationMetrics(svc.Namespace, svc.Name, "Service", rf.Name, err)
 	return err
 }
 
 // EnsureSentinelConfigMap makes sure the sentinel configmap exists
-func (r *RedisFailoverKubeClient) EnsureSentinelConfigMap(rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error {
-	cm := generateSentinelConfigMap(rf, labels, ownerRefs)
-	err := r.K8SService.CreateOrUpdateConfigMap(rf.Namespace, cm)
+func (r *RedisFailoverKubeClient) EnsureSentinelConfigMap(ctx context.Context, rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error {
+	password, err := k8s.GetSentinelPassword(ctx, r.K8SService, rf)
+	if err != nil {
+		return err
+	}
+
+	cm := generateSentinelConfigMap(rf, labels, ownerRefs, password)
+	err = r.K8SService.CreateOrUpdateConfigMap(ctx, rf.Namespace, cm)
 	r.setEnsureOperationMetrics(cm.Namespace, cm.Name, "ConfigMap", rf.Name, err)
 	return err
 }
 
 // EnsureSentinelStatefulset makes sure the sentinel statefulset exists in the desired state
-func (r *RedisFailoverKubeClient) EnsureSentinelStatefulset(rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error {
-	if err := r.ensurePodDisruptionBudget(rf, sentinelName, sentinelRoleName, labels, ownerRefs); err != nil {
+func (r *RedisFailoverKubeClient) EnsureSentinelStatefulset(ctx context.Context, rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error {
+	if err := r.ensurePodDisruptionBudget(ctx, rf, GetSentinelName(rf), sentinelRoleName, labels, ownerRefs); err != nil {
 		return err
 	}
 	ss := generateSentinelStatefulSet(rf, labels, ownerRefs)
-	err := r.K8SService.CreateOrUpdateStatefulSet(rf.Namespace, ss)
+	err := r.K8SService.CreateOrUpdateStatefulSet(ctx, rf.Namespace, ss)
 	r.setEnsureOperationMetrics(ss.Namespace, ss.Name, "StatefulSet", rf.Name, err)
 	return err
 }
 
 // EnsureRedisStatefulset makes sure the redis statefulset exists in the desired state
-func (r *RedisFailoverKubeClient) EnsureRedisStatefulset(rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error {
-	if err := r.ensurePodDisruptionBudget(rf, redisName, redisRoleName, labels, ownerRefs); err != nil {
+func (r *RedisFailoverKubeClient) EnsureRedisStatefulset(ctx context.Context, rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error {
+	if err := r.ensurePodDisruptionBudget(ctx, rf, GetRedisName(rf), redisRoleName, labels, ownerRefs); err != nil {
 		return err
 	}
-	ss := generateRedisStatefulSet(rf, labels, ownerRefs)
-	err := r.K8SService.CreateOrUpdateStatefulSet(rf.Namespace, ss)
+	if rf.Spec.Redis.Zones == nil {
+		ss := generateRedisStatefulSet(rf, labels, ownerRefs)
+		if err := r.setRedisPasswordHash(ctx, rf, ss); err != nil {
+			return err
+		}
+		err := r.K8SService.CreateOrUpdateStatefulSet(ctx, rf.Namespace, ss)
 
-	r.setEnsureOperationMetrics(ss.Namespace, ss.Name, "StatefulSet", rf.Name, err)
-	return err
+		r.setEnsureOperationMetrics(ss.Namespace, ss.Name, "StatefulSet", rf.Name, err)
+		return err
+	}
+	for _, set := range getRedisStatefulSets(rf) {
+		ss := generateRedisZoneStatefulSet(rf, set, labels, ownerRefs)
+		if err := r.setRedisPasswordHash(ctx, rf, ss); err != nil {
+			return err
+		}
+		err := r.K8SService.CreateOrUpdateStatefulSet(ctx, rf.Namespace, ss)
+
+		r.setEnsureOperationMetrics(ss.Namespace, ss.Name, "StatefulSet", rf.Name, err)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setRedisPasswordHash annotates the redis pods with a digest of the password they are started with, so that
+// they are rolled when it changes and the exporters, which only read it on start, authenticate with the new one.
+// While a rotation is in progress the digest of the running pods is kept, they are rolled once the previous
+// password is dropped.
+func (r *RedisFailoverKubeClient) setRedisPasswordHash(ctx context.Context, rf *redisfailoverv1.RedisFailover, ss *appsv1.StatefulSet) error {
+	password, err := k8s.GetRedisPassword(ctx, r.K8SService, rf)
+	if err != nil || password == "" {
+		return err
+	}
+	previousPassword, err := k8s.GetRedisPreviousPassword(ctx, r.K8SService, rf)
+	if err != nil {
+		return err
+	}
+
+	passwordHash := hashConfig(map[string]string{"uid": string(rf.UID), "password": password}, []string{"uid", "password"})
+	if previousPassword != "" && previousPassword != password {
+		current, err := r.K8SService.GetStatefulSet(ctx, ss.Namespace, ss.Name)
+		if err == nil {
+			if h, ok := current.Spec.Template.Annotations[passwordHashAnnotationKey]; ok {
+				passwordHash = h
+			}
+		} else if !errors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	ss.Spec.Template.Annotations = util.MergeAnnotations(ss.Spec.Template.Annotations, map[string]string{
+		passwordHashAnnotationKey: passwordHash,
+	})
+	return nil
 }
 
 // EnsureRedisConfigMap makes sure the Redis ConfigMap exists
-func (r *RedisFailoverKubeClient) EnsureRedisConfigMap(rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error {
+func (r *RedisFailoverKubeClient) EnsureRedisConfigMap(ctx context.Context, rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error {
 
-	password, err := k8s.GetRedisPassword(r.K8SService, rf)
+	password, err := k8s.GetRedisPassword(ctx, r.K8SService, rf)
+	if err != nil {
+		return err
+	}
+	masterAuth, err := k8s.GetRedisMasterAuth(ctx, r.K8SService, rf)
+	if err != nil {
+		return err
+	}
+	previousPassword, err := k8s.GetRedisPreviousPassword(ctx, r.K8SService, rf)
 	if err != nil {
 		return err
 	}
 
-	cm := generateRedisConfigMap(rf, labels, ownerRefs, password)
-	err = r.K8SService.CreateOrUpdateConfigMap(rf.Namespace, cm)
+	cm := generateRedisConfigMap(rf, labels, ownerRefs, password, masterAuth, previousPassword)
+	err = r.K8SService.CreateOrUpdateConfigMap(ctx, rf.Namespace, cm)
 
 	r.setEnsureOperationMetrics(cm.Namespace, cm.Name, "ConfigMap", rf.Name, err)
 	return err
 }
 
 // EnsureRedisShutdownConfigMap makes sure the redis configmap with shutdown script exists
-func (r *RedisFailoverKubeClient) EnsureRedisShutdownConfigMap(rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error {
+func (r *RedisFailoverKubeClient) EnsureRedisShutdownConfigMap(ctx context.Context, rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error {
 	if rf.Spec.Redis.ShutdownConfigMap != "" {
-		if _, err := r.K8SService.GetConfigMap(rf.Namespace, rf.Spec.Redis.ShutdownConfigMap); err != nil {
+		if _, err := r.K8SService.GetConfigMap(ctx, rf.Namespace, rf.Spec.Redis.ShutdownConfigMap); err != nil {
 			return err
 		}
 	} else {
 		cm := generateRedisShutdownConfigMap(rf, labels, ownerRefs)
-		err := r.K8SService.CreateOrUpdateConfigMap(rf.Namespace, cm)
+		err := r.K8SService.CreateOrUpdateConfigMap(ctx, rf.Namespace, cm)
 		r.setEnsureOperationMetrics(cm.Namespace, cm.Name, "ConfigMap", rf.Name, err)
 		return err
 	}
@@ -140,51 +216,206 @@ func (r *RedisFailoverKubeClient) EnsureRedisShutdownConfigMap(rf *redisfailover
 }
 
 // EnsureRedisReadinessConfigMap makes sure the redis configmap with shutdown script exists
-func (r *RedisFailoverKubeClient) EnsureRedisReadinessConfigMap(rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error {
+func (r *RedisFailoverKubeClient) EnsureRedisReadinessConfigMap(ctx context.Context, rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error {
 	cm := generateRedisReadinessConfigMap(rf, labels, ownerRefs)
-	err := r.K8SService.CreateOrUpdateConfigMap(rf.Namespace, cm)
+	err := r.K8SService.CreateOrUpdateConfigMap(ctx, rf.Namespace, cm)
 	r.setEnsureOperationMetrics(cm.Namespace, cm.Name, "ConfigMap", rf.Name, err)
 	return err
 }
 
 // EnsureRedisService makes sure the redis statefulset exists
-func (r *RedisFailoverKubeClient) EnsureRedisService(rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error {
+func (r *RedisFailoverKubeClient) EnsureRedisService(ctx context.Context, rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error {
 	svc := generateRedisService(rf, labels, ownerRefs)
-	err := r.K8SService.CreateOrUpdateService(rf.Namespace, svc)
+	err := r.K8SService.CreateOrUpdateService(ctx, rf.Namespace, svc)
 
 	r.setEnsureOperationMetrics(svc.Namespace, svc.Name, "Service", rf.Name, err)
 	return err
 }
 
 // EnsureNotPresentRedisService makes sure the redis service is not present
-func (r *RedisFailoverKubeClient) EnsureNotPresentRedisService(rf *redisfailoverv1.RedisFailover) error {
+func (r *RedisFailoverKubeClient) EnsureNotPresentRedisService(ctx context.Context, rf *redisfailoverv1.RedisFailover) error {
 	name := GetRedisName(rf)
 	namespace := rf.Namespace
 	// If the service exists (no get error), delete it
-	if _, err := r.K8SService.GetService(namespace, name); err == nil {
-		return r.K8SService.DeleteService(namespace, name)
+	if _, err := r.K8SService.GetService(ctx, namespace, name); err == nil {
+		return r.K8SService.DeleteService(ctx, namespace, name)
+	}
+	return nil
+}
+
+// EnsureRedisZonesUnchanged fails when the zones of the redis failover aren't the ones its redis statefulsets were
+// created for. The redises, the master among them, would have to move between statefulsets and the replicas of every
+// zone would change, so zones can't be set, unset or changed on an existing redis failover
+func (r *RedisFailoverKubeClient) EnsureRedisZonesUnchanged(ctx context.Context, rf *redisfailoverv1.RedisFailover) error {
+	sss, err := r.K8SService.ListStatefulSets(ctx, rf.Namespace)
+	if err != nil {
+		return err
+	}
+
+	zones := getZonesAnnotation(rf)
+	for _, ss := range sss.Items {
+		if ss.Labels["app.kubernetes.io/component"] != redisRoleName || ss.Labels["app.kubernetes.io/name"] != rf.Name {
+			continue
+		}
+		_, zoned := ss.Labels[zoneLabelKey]
+		if zoned != (rf.Spec.Redis.Zones != nil) {
+			return fmt.Errorf("redis zones can't be set or unset on an existing redis failover, statefulset %s is in the way", ss.Name)
+		}
+		if !zoned {
+			continue
+		}
+		created, ok := ss.Annotations[zonesAnnotationKey]
+		if !ok {
+			return fmt.Errorf("statefulset %s doesn't record the redis zones it was created for", ss.Name)
+		}
+		if created != zones {
+			return fmt.Errorf("redis zones can't be changed on an existing redis failover, statefulset %s was created for the zones %q", ss.Name, created)
+		}
+	}
+	return nil
+}
+
+// EnsureRedisMasterService makes sure the service pointing to the redis master exists
+func (r *RedisFailoverKubeClient) EnsureRedisMasterService(ctx context.Context, rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error {
+	svc := generateRedisMasterService(rf, labels, ownerRefs)
+	err := r.K8SService.CreateOrUpdateService(ctx, rf.Namespace, svc)
+
+	r.setEnsureOperationMetrics(svc.Namespace, svc.Name, "Service", rf.Name, err)
+	return err
+}
+
+// EnsureRedisReplicasService makes sure the service pointing to the redis replicas exists
+func (r *RedisFailoverKubeClient) EnsureRedisReplicasService(ctx context.Context, rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error {
+	svc := generateRedisReplicasService(rf, labels, ownerRefs)
+	err := r.K8SService.CreateOrUpdateService(ctx, rf.Namespace, svc)
+
+	r.setEnsureOperationMetrics(svc.Namespace, svc.Name, "Service", rf.Name, err)
+	return err
+}
+
+// EnsureExternalAccessServices makes sure every redis and sentinel pod has its own service when the external
+// access is enabled, and removes the services no pod needs anymore
+func (r *RedisFailoverKubeClient) EnsureExternalAccessServices(ctx context.Context, rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error {
+	desired := map[string]bool{}
+	if rf.Spec.ExternalAccess != nil {
+		svcs := []*corev1.Service{}
+		for _, podName := range getRedisPodNames(rf) {
+			svcs = append(svcs, generateExternalAccessService(rf, podName, redisRoleName, rf.Spec.Redis.Port, labels, ownerRefs))
+		}
+		if rf.SentinelsAllowed() {
+			for _, podName := range getPodNames(GetSentinelName(rf), rf.Spec.Sentinel.Replicas) {
+				svcs = append(svcs, generateExternalAccessService(rf, podName, sentinelRoleName, rf.Spec.Sentinel.Port, labels, ownerRefs))
+			}
+		}
+
+		for _, svc := range svcs {
+			err := r.K8SService.CreateOrUpdateService(ctx, rf.Namespace, svc)
+			r.setEnsureOperationMetrics(svc.Namespace, svc.Name, "Service", rf.Name, err)
+			if err != nil {
+				return err
+			}
+			desired[svc.Name] = true
+		}
+	}
+
+	current, err := r.K8SService.ListServices(ctx, rf.Namespace)
+	if err != nil {
+		return err
+	}
+	for _, svc := range current.Items {
+		if svc.Labels[externalAccessLabelKey] != "true" || svc.Labels["app.kubernetes.io/name"] != rf.Name || desired[svc.Name] {
+			continue
+		}
+		Logger(ctx, r.logger, rf).Infof("Removing external access service %s", svc.Name)
+		if err := r.K8SService.DeleteService(ctx, rf.Namespace, svc.Name); err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
-// EnsureRedisStatefulset makes sure the pdb exists in the desired state
-func (r *RedisFailoverKubeClient) ensurePodDisruptionBudget(rf *redisfailoverv1.RedisFailover, name string, component string, labels map[string]string, ownerRefs []metav1.OwnerReference) error {
-	name = generateName(name, rf.Name)
+// ensurePodDisruptionBudget makes sure the pdb of the component exists in the desired state, or is not present when disabled
+func (r *RedisFailoverKubeClient) ensurePodDisruptionBudget(ctx context.Context, rf *redisfailoverv1.RedisFailover, name string, component string, labels map[string]string, ownerRefs []metav1.OwnerReference) error {
 	namespace := rf.Namespace
 
-	minAvailable := intstr.FromInt(2)
-	if rf.Spec.Redis.Replicas <= 2 {
-		minAvailable = intstr.FromInt(1)
+	settings, _, defaultMinAvailable := getPodDisruptionBudgetSettings(rf, component)
+	if settings.Disabled {
+		// If the pdb exists (no get error), delete it
+		if _, err := r.K8SService.GetPodDisruptionBudget(ctx, namespace, name); err == nil {
+			return r.K8SService.DeletePodDisruptionBudget(ctx, namespace, name)
+		}
+		return nil
 	}
 
 	labels = util.MergeLabels(labels, generateSelectorLabels(component, rf.Name))
 
-	pdb := generatePodDisruptionBudget(name, namespace, labels, ownerRefs, minAvailable)
-	err := r.K8SService.CreateOrUpdatePodDisruptionBudget(namespace, pdb)
+	pdb := generatePodDisruptionBudget(name, namespace, labels, ownerRefs, settings, defaultMinAvailable)
+	err := r.K8SService.CreateOrUpdatePodDisruptionBudget(ctx, namespace, pdb)
 	r.setEnsureOperationMetrics(pdb.Namespace, pdb.Name, "PodDisruptionBudget" /* pdb.TypeMeta.Kind isnt working;  pdb.Kind isnt working either */, rf.Name, err)
 	return err
 }
 
+// PodDisruptionBudget is a PodDisruptionBudget the operator manages for a component of a RedisFailover
+type PodDisruptionBudget struct {
+	Name      string
+	Component string
+	Replicas  int32
+	// Disabled is true when the spec removes the budget
+	Disabled bool
+	// DefaultMinAvailable is the minAvailable of the budget when the spec sets neither it nor maxUnavailable
+	DefaultMinAvailable intstr.IntOrString
+}
+
+// GetPodDisruptionBudgets returns the PodDisruptionBudgets the operator manages for the redises, the sentinels when
+// allowed and the proxy of the RedisFailover, the disabled ones included
+func GetPodDisruptionBudgets(rf *redisfailoverv1.RedisFailover) []PodDisruptionBudget {
+	proxy := GetProxy(rf)
+	components := [][2]string{{GetRedisName(rf), redisRoleName}}
+	if rf.SentinelsAllowed() {
+		components = append(components, [2]string{GetSentinelName(rf), sentinelRoleName})
+	}
+	components = append(components, [2]string{proxy.Name(rf), proxy.Component()})
+
+	pdbs := []PodDisruptionBudget{}
+	for _, c := range components {
+		settings, replicas, defaultMinAvailable := getPodDisruptionBudgetSettings(rf, c[1])
+		pdbs = append(pdbs, PodDisruptionBudget{
+			Name:                c[0],
+			Component:           c[1],
+			Replicas:            replicas,
+			Disabled:            settings.Disabled,
+			DefaultMinAvailable: defaultMinAvailable,
+		})
+	}
+	return pdbs
+}
+
+// getPodDisruptionBudgetSettings returns the pdb settings and the replicas of the component, and the minAvailable
+// used when they set none. It lets a single pod be evicted at a time, and keeps the sentinel quorum
+func getPodDisruptionBudgetSettings(rf *redisfailoverv1.RedisFailover, component string) (redisfailoverv1.PodDisruptionBudgetSettings, int32, intstr.IntOrString) {
+	var settings redisfailoverv1.PodDisruptionBudgetSettings
+	var replicas int32
+	switch component {
+	case redisRoleName:
+		settings, replicas = rf.Spec.Redis.PodDisruptionBudget, rf.Spec.Redis.Replicas
+	case sentinelRoleName:
+		settings, replicas = rf.Spec.Sentinel.PodDisruptionBudget, rf.Spec.Sentinel.Replicas
+	case predixyRoleName:
+		settings, replicas = rf.Spec.Predixy.PodDisruptionBudget, rf.Spec.Predixy.Replicas
+	case envoyRoleName:
+		settings, replicas = rf.Spec.Proxy.Envoy.PodDisruptionBudget, rf.Spec.Proxy.Envoy.Replicas
+	}
+
+	minAvailable := replicas - 1
+	if component == sentinelRoleName && getQuorum(rf) < minAvailable {
+		minAvailable = getQuorum(rf)
+	}
+	if minAvailable < 1 {
+		minAvailable = 1
+	}
+	return settings, replicas, intstr.FromInt(int(minAvailable))
+}
+
 func (r *RedisFailoverKubeClient) setEnsureOperationMetrics(objectNamespace string, objectName string, objectKind string, ownerName string, err error) {
 	if nil != err {
 		r.metricsClient.RecordEnsureOperation(objectNamespace, objectName, objectKind, ownerName, metrics.FAIL)
@@ -192,51 +423,62 @@ func (r *RedisFailoverKubeClient) setEnsureOperationMetrics(objectNamespace stri
 	r.metricsClient.RecordEnsureOperation(objectNamespace, objectName, objectKind, ownerName, metrics.SUCCESS)
 }
 
-// EnsurePredixyAllResources make sure the sentries are all ready before starting predixy
-func (r *RedisFailoverKubeClient) EnsurePredixyAllResources(rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error {
-	// GetSentinelsIPs returns the IPs of the Sentinel nodes
-	sentinels := []string{}
-	sps, err := r.K8SService.GetStatefulSetPods(rf.Namespace, GetSentinelName(rf))
+// EnsureProxyAllResources makes sure the redises and sentinels are all ready before starting the proxy
+func (r *RedisFailoverKubeClient) EnsureProxyAllResources(ctx context.Context, rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error {
+	proxy := GetProxy(rf)
+	for _, p := range proxies {
+		if p == proxy {
+			continue
+		}
+		if err := r.ensureNotPresentProxy(ctx, rf, p); err != nil {
+			return err
+		}
+	}
+
+	sps, err := r.K8SService.GetStatefulSetPods(ctx, rf.Namespace, GetSentinelName(rf))
 	if err != nil {
 		return err
 	}
+	sentinels := []string{}
 	for _, pod := range sps.Items {
-		if pod.Status.Phase == corev1.PodRunning && pod.DeletionTimestamp == nil { // Only work with running pods
-			if IsPodReady(pod) {
-				fmt.Printf("Sentinel Pod: %s is ready\n", pod.Name)
-				sentinels = append(sentinels, pod.Status.PodIP)
-			}
+		if pod.Status.Phase == corev1.PodRunning && pod.DeletionTimestamp == nil && IsPodReady(pod) { // Only work with running pods
+			sentinels = append(sentinels, getSentinelAddress(rf, pod))
 		}
 	}
 
-	redisList := []string{}
-	rps, err := r.K8SService.GetStatefulSetPods(rf.Namespace, GetRedisName(rf))
+	rps, err := getRedisPods(ctx, r.K8SService, rf)
 	if err != nil {
 		return err
 	}
+	backends := ProxyBackends{Replicas: []string{}}
+	redises := 0
 	for _, pod := range rps.Items {
-		if pod.Status.Phase == corev1.PodRunning && pod.DeletionTimestamp == nil { // Only work with running pods
-			if IsPodReady(pod) {
-				fmt.Printf("Redis Pod: %s is ready\n", pod.Name)
-				redisList = append(redisList, pod.Status.PodIP)
+		if pod.Status.Phase == corev1.PodRunning && pod.DeletionTimestamp == nil && IsPodReady(pod) { // Only work with running pods
+			redises++
+			// The checker keeps the role label in sync with the master sentinel agrees on
+			if pod.Labels[redisRoleLabelKey] == redisRoleLabelMaster {
+				backends.Master = getRedisAddress(rf, pod)
+			} else {
+				backends.Replicas = append(backends.Replicas, getRedisAddress(rf, pod))
 			}
 		}
 	}
 
-	fmt.Println("Ensure predixy start get sentinels ready pods count: ", len(sentinels))
-	fmt.Println("Ensure predixy start get redis ready pods count: ", len(redisList))
-	if len(sentinels) == int(rf.Spec.Sentinel.Replicas) && len(redisList) == int(rf.Spec.Redis.Replicas) {
-		if err := r.EnsurePredixyConfigMap(rf, labels, ownerRefs, sentinels); err != nil {
+	Logger(ctx, r.logger, rf).Debugf("Ensure %s with %d ready sentinels and %d ready redises", proxy.Component(), len(sentinels), redises)
+	if len(sentinels) == int(rf.Spec.Sentinel.Replicas) && redises == int(rf.Spec.Redis.Replicas) {
+		if err := r.EnsureProxyConfigMap(ctx, rf, labels, ownerRefs, backends); err != nil {
 			return err
 		}
 
-		if err := r.EnsurePredixyService(rf, labels, ownerRefs); err != nil {
+		if err := r.EnsureProxyService(ctx, rf, labels, ownerRefs); err != nil {
 			return err
 		}
 
-		// ensure sentinel bootstrap finished; ensure predixy bootstrap correctly.
+		// ensure sentinel bootstrap finished; ensure proxy bootstrap correctly.
+		_, endWait := StartSpan(ctx, "WaitSentinelBootstrap")
 		time.Sleep(4 * time.Second)
-		if err := r.EnsurePredixyDeployment(rf, labels, ownerRefs); err != nil {
+		endWait(nil)
+		if err := r.EnsureProxyDeployment(ctx, rf, labels, ownerRefs); err != nil {
 			return err
 		}
 	}
@@ -254,35 +496,97 @@ func IsPodReady(pod corev1.Pod) bool {
 	return isReady
 }
 
-// EnsurePredixyConfigMap create predixy configmap
-func (r *RedisFailoverKubeClient) EnsurePredixyConfigMap(rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference, sentinels []string) error {
+// EnsureProxyConfigMap makes sure the proxy configmap holds the given backends
+func (r *RedisFailoverKubeClient) EnsureProxyConfigMap(ctx context.Context, rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference, backends ProxyBackends) error {
 	// redis password
-	password, err := k8s.GetRedisPassword(r.K8SService, rf)
+	password, err := k8s.GetRedisPassword(ctx, r.K8SService, rf)
 	if err != nil {
 		return err
 	}
+	backends.Password = password
 
-	cm := generatePredixyConfigMap(rf, labels, ownerRefs, sentinels, password)
-	err = r.K8SService.CreateOrUpdateConfigMap(rf.Namespace, cm)
+	sentinelPassword, err := k8s.GetSentinelPassword(ctx, r.K8SService, rf)
+	if err != nil {
+		return err
+	}
+	backends.SentinelPassword = sentinelPassword
+
+	cm := GetProxy(rf).ConfigMap(rf, labels, ownerRefs, backends)
+	err = r.K8SService.CreateOrUpdateConfigMap(ctx, rf.Namespace, cm)
 	r.setEnsureOperationMetrics(cm.Namespace, cm.Name, "ConfigMap", rf.Name, err)
 	return err
 }
 
-// EnsurePredixyService create predixy service
-func (r *RedisFailoverKubeClient) EnsurePredixyService(rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error {
-	svc := generatePredixyService(rf, labels, ownerRefs)
-	err := r.K8SService.CreateOrUpdateService(rf.Namespace, svc)
+// EnsureProxyService makes sure the proxy service exists
+func (r *RedisFailoverKubeClient) EnsureProxyService(ctx context.Context, rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error {
+	svc := GetProxy(rf).Service(rf, labels, ownerRefs)
+	err := r.K8SService.CreateOrUpdateService(ctx, rf.Namespace, svc)
 	r.setEnsureOperationMetrics(svc.Namespace, svc.Name, "Service", rf.Name, err)
-	return nil
+	return err
 }
 
-// EnsurePredixyDeployment create predixy
-func (r *RedisFailoverKubeClient) EnsurePredixyDeployment(rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error {
-	if err := r.ensurePodDisruptionBudget(rf, predixyName, predixyRoleName, labels, ownerRefs); err != nil {
+// EnsureProxyDeployment makes sure the proxy deployment exists in the desired state. The pods are
+// rolled when the configuration they read on start changes, e.g. when sentinels are added or removed.
+func (r *RedisFailoverKubeClient) EnsureProxyDeployment(ctx context.Context, rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error {
+	proxy := GetProxy(rf)
+	if err := r.ensurePodDisruptionBudget(ctx, rf, proxy.Name(rf), proxy.Component(), labels, ownerRefs); err != nil {
 		return err
 	}
-	pd := generatePredixyDeployments(rf, labels, ownerRefs)
-	err := r.K8SService.CreateOrUpdateDeployment(rf.Namespace, pd)
+
+	cm, err := r.K8SService.GetConfigMap(ctx, rf.Namespace, proxy.Name(rf))
+	if err != nil {
+		return err
+	}
+	configHash := hashConfig(cm.Data, proxy.StartupConfig())
+
+	if current, err := r.K8SService.GetDeployment(ctx, rf.Namespace, proxy.Name(rf)); err == nil {
+		if current.Spec.Template.Annotations[configHashAnnotationKey] != configHash {
+			Logger(ctx, r.logger, rf).Infof("%s configuration changed, rolling deployment %s", proxy.Component(), current.Name)
+		}
+	}
+
+	pd := proxy.Deployment(rf, labels, ownerRefs)
+	pd.Spec.Template.Annotations = util.MergeAnnotations(pd.Spec.Template.Annotations, map[string]string{
+		configHashAnnotationKey: configHash,
+	})
+	err = r.K8SService.CreateOrUpdateDeployment(ctx, rf.Namespace, pd)
 	r.setEnsureOperationMetrics(pd.Namespace, pd.Name, "Deployment", rf.Name, err)
+	return err
+}
+
+// hashConfig returns a digest of the given keys of a configmap data
+func hashConfig(data map[string]string, keys []string) string {
+	h := sha256.New()
+	for _, k := range keys {
+		fmt.Fprintf(h, "%s\x00%s\x00", k, data[k])
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// ensureNotPresentProxy removes the objects left behind by a proxy that is no longer selected
+func (r *RedisFailoverKubeClient) ensureNotPresentProxy(ctx context.Context, rf *redisfailoverv1.RedisFailover, proxy Proxy) error {
+	name := proxy.Name(rf)
+	namespace := rf.Namespace
+	// If the object exists (no get error), delete it
+	if _, err := r.K8SService.GetDeployment(ctx, namespace, name); err == nil {
+		if err := r.K8SService.DeleteDeployment(ctx, namespace, name); err != nil {
+			return err
+		}
+	}
+	if _, err := r.K8SService.GetPodDisruptionBudget(ctx, namespace, name); err == nil {
+		if err := r.K8SService.DeletePodDisruptionBudget(ctx, namespace, name); err != nil {
+			return err
+		}
+	}
+	if _, err := r.K8SService.GetService(ctx, namespace, name); err == nil {
+		if err := r.K8SService.DeleteService(ctx, namespace, name); err != nil {
+			return err
+		}
+	}
+	if _, err := r.K8SService.GetConfigMap(ctx, namespace, name); err == nil {
+		if err := r.K8SService.DeleteConfigMap(ctx, namespace, name); err != nil {
+			return err
+		}
+	}
 	return nil
 }
@@ -5,7 +5,9 @@ const (
 	exporterPort                  = 9121
 	sentinelExporterPort          = 9355
 	predixyExporterPort           = 9617
+	envoyAdminPort                = 9901
 	exporterPortName              = "http-metrics"
+	exporterContainerPortName     = "metrics"
 	exporterContainerName         = "redis-exporter"
 	sentinelExporterContainerName = "sentinel-exporter"
 	predixyExporterContainerName  = "predixy-exporter"
@@ -24,11 +26,38 @@ const (
 	redisName              = "r"
 	redisShutdownName      = "r-s"
 	redisReadinessName     = "r-readiness"
+	redisMasterName        = "rm"
+	redisReplicasName      = "rs"
 	redisRoleName          = "redis"
 	appLabel               = "redis-failover"
 	hostnameTopologyKey    = "kubernetes.io/hostname"
 	predixyName            = "p"
 	predixyRoleName        = "predixy"
+	envoyName              = "e"
+	envoyRoleName          = "envoy"
+	prometheusRuleName     = "a"
+)
+
+const (
+	configHashAnnotationKey = "redisfailovers.databases.spotahome.com/config-hash"
+	// passwordHashAnnotationKey holds a digest of the password the redis pods were started with
+	passwordHashAnnotationKey = "redisfailovers.databases.spotahome.com/password-hash"
+	externalAccessLabelKey    = "redisfailovers.databases.spotahome.com/external-access"
+	zoneLabelKey              = "redisfailovers.databases.spotahome.com/zone"
+	// zonesAnnotationKey holds the zones of the redis failover when its redis statefulsets were created
+	zonesAnnotationKey = "redisfailovers.databases.spotahome.com/zones"
+)
+
+// variables holding the stable DNS names the pods announce
+const (
+	podNameEnv                  = "POD_NAME"
+	redisAnnounceHostnameEnv    = "REDIS_ANNOUNCE_HOSTNAME"
+	sentinelAnnounceHostnameEnv = "SENTINEL_ANNOUNCE_HOSTNAME"
+)
+
+// variable holding the sentinel password in the redis pods
+const (
+	sentinelPasswordEnv = "SENTINEL_PASSWORD"
 )
 
 const (
@@ -0,0 +1,278 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
+	"github.com/spotahome/redis-operator/service/k8s"
+)
+
+const (
+	replicaPriority = "replica-priority"
+	// switchoverMaxLag is the replication offset, in bytes, a replica can be behind the master by to be switched to
+	switchoverMaxLag = 1024 * 1024
+)
+
+var (
+	// switchoverTimeout bounds every wait of a switchover on sentinel
+	switchoverTimeout = 30 * time.Second
+	// switchoverPollInterval is the time between the checks of sentinel during a switchover
+	switchoverPollInterval = time.Second
+)
+
+// drainTaints are the taints put on the nodes about to be drained: by kubernetes on the cordoned ones, and by the
+// cluster autoscaler and karpenter on the ones they remove
+var drainTaints = []string{
+	corev1.TaintNodeUnschedulable,
+	"ToBeDeletedByClusterAutoscaler",
+	"karpenter.sh/disruption",
+	"karpenter.sh/disrupted",
+}
+
+// IsNodeDraining returns true when the node is cordoned or tainted to be drained, so its pods are about to be evicted
+func IsNodeDraining(node *corev1.Node) bool {
+	if node.Spec.Unschedulable {
+		return true
+	}
+	for _, taint := range node.Spec.Taints {
+		for _, key := range drainTaints {
+			if taint.Key == key {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// GetRedisesOnDrainingNodes returns the addresses of the running redises whose node is cordoned or being drained
+func (r *RedisFailoverChecker) GetRedisesOnDrainingNodes(ctx context.Context, rf *redisfailoverv1.RedisFailover) (map[string]bool, error) {
+	rps, err := getRedisPods(ctx, r.k8sService, rf)
+	if err != nil {
+		return nil, err
+	}
+
+	draining := map[string]bool{}
+	nodes := map[string]bool{}
+	for _, rp := range rps.Items {
+		if rp.Status.Phase != corev1.PodRunning || rp.DeletionTimestamp != nil || rp.Spec.NodeName == "" {
+			continue
+		}
+		nodeDraining, ok := nodes[rp.Spec.NodeName]
+		if !ok {
+			node, err := r.k8sService.GetNode(ctx, rp.Spec.NodeName)
+			if err != nil {
+				return nil, err
+			}
+			nodeDraining = IsNodeDraining(node)
+			nodes[rp.Spec.NodeName] = nodeDraining
+		}
+		if nodeDraining {
+			draining[getRedisAddress(rf, rp)] = true
+		}
+	}
+	return draining, nil
+}
+
+// SwitchoverInFlight returns true while the switchover recorded on the status of the RedisFailover may be running. An
+// older one, past the waits on sentinel and the restore of the priorities, was left by an operator stopped meanwhile
+func SwitchoverInFlight(rf *redisfailoverv1.RedisFailover) bool {
+	switchover := rf.Status.Switchover
+	return switchover != nil && time.Since(switchover.StartTime.Time) < 3*switchoverTimeout
+}
+
+// CheckRedisSlaveInSync returns true when the given replica has its link to the master up and is behind it by no more
+// than switchoverMaxLag, so promoting it loses next to no writes
+func (r *RedisFailoverChecker) CheckRedisSlaveInSync(ctx context.Context, slaveIP, masterIP string, rf *redisfailoverv1.RedisFailover) (bool, error) {
+	password, err := k8s.GetRedisPassword(ctx, r.k8sService, rf)
+	if err != nil {
+		return false, err
+	}
+	port := getRedisPort(rf.Spec.Redis.Port)
+
+	// the replica is read first, a master read before it would make the replica look further behind than it is
+	slave, err := r.redisClient.GetReplicationInfo(ctx, slaveIP, port, password)
+	if err != nil {
+		return false, err
+	}
+	if slave.Role != "slave" || slave.MasterLinkStatus != "up" {
+		return false, nil
+	}
+	master, err := r.redisClient.GetReplicationInfo(ctx, masterIP, port, password)
+	if err != nil {
+		return false, err
+	}
+	return master.Offset-slave.Offset <= switchoverMaxLag, nil
+}
+
+// SwitchoverMaster asks the given sentinel to fail the master over, to any replica but the excluded ones. These get a
+// replica-priority of 0 until the switch completes, and the failover is only asked once the sentinel sees it. The
+// switchover is recorded on the status of the RedisFailover until it ends. Nothing is done while sentinel is failing
+// over
+func (r *RedisFailoverHealer) SwitchoverMaster(ctx context.Context, sentinel string, excluded []string, rf *redisfailoverv1.RedisFailover) error {
+	sentinelPassword, err := k8s.GetSentinelPassword(ctx, r.k8sService, rf)
+	if err != nil {
+		return err
+	}
+	sentinelPort := getSentinelPort(rf.Spec.Sentinel.Port)
+	masterName := rf.Spec.Sentinel.MasterName
+	info, err := r.redisClient.GetSentinelMasterInfo(ctx, sentinel, sentinelPort, masterName, sentinelPassword)
+	if err != nil {
+		return err
+	}
+	if strings.Contains(info.Flags, "failover_in_progress") {
+		Logger(ctx, r.logger, rf).Debugf("Sentinel %s is already failing the master over", sentinel)
+		return nil
+	}
+	master := net.JoinHostPort(info.IP, info.Port)
+
+	password, err := k8s.GetRedisPassword(ctx, r.k8sService, rf)
+	if err != nil {
+		return err
+	}
+	port := getRedisPort(rf.Spec.Redis.Port)
+	priorities := map[string]string{}
+	for _, ip := range excluded {
+		config, err := r.redisClient.GetRedisConfig(ctx, ip, port, []string{replicaPriority}, password)
+		if err != nil {
+			return fmt.Errorf("unable to get the %s of %s: %w", replicaPriority, ip, err)
+		}
+		priorities[ip] = config[replicaPriority]
+	}
+	// the status is read again, the given one may be stale when the switchover runs out of the reconcile
+	current, err := r.k8sService.GetRedisFailover(ctx, rf.Namespace, rf.Name)
+	if err != nil {
+		return err
+	}
+	current.Status.Switchover = &redisfailoverv1.SwitchoverStatus{ReplicaPriorities: priorities, StartTime: metav1.Now()}
+	if err := r.k8sService.UpdateRedisFailoverStatus(ctx, current); err != nil {
+		return err
+	}
+	defer func() {
+		if err := r.EndSwitchover(ctx, rf); err != nil {
+			Logger(ctx, r.logger, rf).Warningf("Unable to clear the switchover from the status: %s", err)
+		}
+	}()
+	for _, ip := range excluded {
+		if err := r.redisClient.SetCustomRedisConfig(ctx, ip, port, []string{replicaPriority + " 0"}, password); err != nil {
+			return fmt.Errorf("unable to keep %s from being promoted: %w", ip, err)
+		}
+	}
+
+	// sentinel picks the replica to promote from what it got on their last INFO, refreshed every few seconds
+	announced, err := r.getAnnouncedRedisAddresses(ctx, excluded, port, rf)
+	if err != nil {
+		return err
+	}
+	err = waitSwitchover(ctx, func() (bool, error) {
+		replicas, err := r.redisClient.GetSentinelReplicas(ctx, sentinel, sentinelPort, masterName, sentinelPassword)
+		if err != nil {
+			return false, err
+		}
+		for _, replica := range replicas {
+			if announced[replica.Address] && replica.Priority != 0 {
+				return false, nil
+			}
+		}
+		return true, nil
+	})
+	if err != nil {
+		return fmt.Errorf("sentinel %s didn't see the excluded replicas can't be promoted: %w", sentinel, err)
+	}
+
+	Logger(ctx, r.logger, rf).Infof("Asking sentinel %s to fail the master over", sentinel)
+	if err := r.redisClient.SentinelFailover(ctx, sentinel, sentinelPort, masterName, sentinelPassword); err != nil {
+		return err
+	}
+	err = waitSwitchover(ctx, func() (bool, error) {
+		info, err := r.redisClient.GetSentinelMasterInfo(ctx, sentinel, sentinelPort, masterName, sentinelPassword)
+		if err != nil {
+			return false, err
+		}
+		return net.JoinHostPort(info.IP, info.Port) != master && !strings.Contains(info.Flags, "failover_in_progress"), nil
+	})
+	if err != nil {
+		return fmt.Errorf("sentinel %s didn't complete the switchover: %w", sentinel, err)
+	}
+	return nil
+}
+
+// getAnnouncedRedisAddresses returns the addresses, as host:port, the given redises are announced to sentinel with
+func (r *RedisFailoverHealer) getAnnouncedRedisAddresses(ctx context.Context, redises []string, port string, rf *redisfailoverv1.RedisFailover) (map[string]bool, error) {
+	externals := map[string]string{}
+	if rf.Spec.ExternalAccess != nil {
+		rps, err := getRedisPods(ctx, r.k8sService, rf)
+		if err != nil {
+			return nil, err
+		}
+		if externals, err = getExternalAddresses(ctx, r.k8sService, rf, rps, getRedisAddress); err != nil {
+			return nil, err
+		}
+	}
+
+	announced := map[string]bool{}
+	for _, redis := range redises {
+		if external, ok := externals[redis]; ok {
+			announced[external] = true
+			continue
+		}
+		announced[net.JoinHostPort(redis, port)] = true
+	}
+	return announced, nil
+}
+
+// EndSwitchover sets back the replica-priority the replicas kept from being promoted by the switchover recorded on
+// the status of the RedisFailover had, and clears it. It runs on its own context, as the one of the switchover may be
+// over by then
+func (r *RedisFailoverHealer) EndSwitchover(ctx context.Context, rf *redisfailoverv1.RedisFailover) error {
+	endCtx, cancel := context.WithTimeout(context.Background(), switchoverTimeout)
+	defer cancel()
+	current, err := r.k8sService.GetRedisFailover(endCtx, rf.Namespace, rf.Name)
+	if err != nil {
+		return err
+	}
+	switchover := current.Status.Switchover
+	if switchover == nil {
+		rf.Status.Switchover = nil
+		return nil
+	}
+
+	password, err := k8s.GetRedisPassword(endCtx, r.k8sService, rf)
+	if err != nil {
+		return err
+	}
+	port := getRedisPort(rf.Spec.Redis.Port)
+	for ip, priority := range switchover.ReplicaPriorities {
+		// a replica gone since keeps no config, its failure mustn't leave the switchover recorded for ever
+		if err := r.redisClient.SetCustomRedisConfig(endCtx, ip, port, []string{replicaPriority + " " + priority}, password); err != nil {
+			Logger(ctx, r.logger, rf).Warningf("Unable to restore the %s of %s to %s: %s", replicaPriority, ip, priority, err)
+		}
+	}
+
+	current.Status.Switchover = nil
+	rf.Status.Switchover = nil
+	return r.k8sService.UpdateRedisFailoverStatus(endCtx, current)
+}
+
+// waitSwitchover polls done until it returns true, for switchoverTimeout at most
+func waitSwitchover(ctx context.Context, done func() (bool, error)) error {
+	ctx, cancel := context.WithTimeout(ctx, switchoverTimeout)
+	defer cancel()
+	for {
+		ok, err := done()
+		if err != nil || ok {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(switchoverPollInterval):
+		}
+	}
+}
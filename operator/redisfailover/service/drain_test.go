@@ -0,0 +1,255 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
+	"github.com/spotahome/redis-operator/log"
+	"github.com/spotahome/redis-operator/metrics"
+	mK8SService "github.com/spotahome/redis-operator/mocks/service/k8s"
+	mRedisService "github.com/spotahome/redis-operator/mocks/service/redis"
+	rfservice "github.com/spotahome/redis-operator/operator/redisfailover/service"
+	"github.com/spotahome/redis-operator/service/redis"
+)
+
+func TestIsNodeDraining(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     corev1.NodeSpec
+		expected bool
+	}{
+		{
+			name: "schedulable node",
+			spec: corev1.NodeSpec{Taints: []corev1.Taint{{Key: "dedicated", Value: "redis", Effect: corev1.TaintEffectNoSchedule}}},
+		},
+		{
+			name:     "cordoned node",
+			spec:     corev1.NodeSpec{Unschedulable: true},
+			expected: true,
+		},
+		{
+			name:     "node tainted as unschedulable",
+			spec:     corev1.NodeSpec{Taints: []corev1.Taint{{Key: "node.kubernetes.io/unschedulable", Effect: corev1.TaintEffectNoSchedule}}},
+			expected: true,
+		},
+		{
+			name:     "node removed by the cluster autoscaler",
+			spec:     corev1.NodeSpec{Taints: []corev1.Taint{{Key: "ToBeDeletedByClusterAutoscaler", Effect: corev1.TaintEffectNoSchedule}}},
+			expected: true,
+		},
+		{
+			name:     "node disrupted by karpenter",
+			spec:     corev1.NodeSpec{Taints: []corev1.Taint{{Key: "karpenter.sh/disrupted", Effect: corev1.TaintEffectNoSchedule}}},
+			expected: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, rfservice.IsNodeDraining(&corev1.Node{Spec: test.spec}))
+		})
+	}
+}
+
+func TestGetRedisesOnDrainingNodes(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF()
+	pod := func(ip, node string) corev1.Pod {
+		return corev1.Pod{
+			Spec:   corev1.PodSpec{NodeName: node},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning, PodIP: ip},
+		}
+	}
+	pods := &corev1.PodList{Items: []corev1.Pod{pod("0.0.0.0", "node-1"), pod("1.1.1.1", "node-1"), pod("2.2.2.2", "node-2")}}
+
+	ms := &mK8SService.Services{}
+	ms.On("GetStatefulSetPods", mock.Anything, namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
+	ms.On("GetNode", mock.Anything, "node-1").Once().Return(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}, Spec: corev1.NodeSpec{Unschedulable: true}}, nil)
+	ms.On("GetNode", mock.Anything, "node-2").Once().Return(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-2"}}, nil)
+	mr := &mRedisService.Client{}
+
+	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
+	draining, err := checker.GetRedisesOnDrainingNodes(context.Background(), rf)
+
+	assert.NoError(err)
+	assert.Equal(map[string]bool{"0.0.0.0": true, "1.1.1.1": true}, draining)
+	ms.AssertExpectations(t)
+}
+
+func TestSwitchoverMaster(t *testing.T) {
+	master := redis.SentinelMasterInfo{IP: "0.0.0.0", Port: "0", Flags: "master"}
+	replicas := func(priority int) []redis.SentinelReplica {
+		return []redis.SentinelReplica{{Address: "1.1.1.1:0", Priority: priority}, {Address: "2.2.2.2:0", Priority: 100}}
+	}
+
+	tests := []struct {
+		name           string
+		flags          string
+		seenPriorities []int
+		failoverErr    error
+		expectFailover bool
+		expectedError  bool
+	}{
+		{
+			name:           "fails the master over keeping the excluded replicas from being promoted",
+			flags:          "master",
+			seenPriorities: []int{0},
+			expectFailover: true,
+		},
+		{
+			name:           "fails the master over once sentinel sees the excluded replicas can't be promoted",
+			flags:          "master",
+			seenPriorities: []int{100, 0},
+			expectFailover: true,
+		},
+		{
+			name:           "restores the priority of the excluded replicas when the failover fails",
+			flags:          "master",
+			seenPriorities: []int{0},
+			failoverErr:    errors.New("wrong"),
+			expectFailover: true,
+			expectedError:  true,
+		},
+		{
+			name:  "waits for the failover in progress",
+			flags: "master,failover_in_progress",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateRF()
+			ms := &mK8SService.Services{}
+			mr := &mRedisService.Client{}
+			info := master
+			info.Flags = test.flags
+			mr.On("GetSentinelMasterInfo", mock.Anything, "10.0.0.1", "26379", "master0", "").Once().Return(info, nil)
+			// the status is kept as the api server would
+			status := redisfailoverv1.RedisFailoverStatus{}
+			recorded := []*redisfailoverv1.SwitchoverStatus{}
+			ms.On("GetRedisFailover", mock.Anything, rf.Namespace, rf.Name).Return(func(context.Context, string, string) *redisfailoverv1.RedisFailover {
+				current := rf.DeepCopy()
+				current.Status = *status.DeepCopy()
+				return current
+			}, nil)
+			ms.On("UpdateRedisFailoverStatus", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+				status = *args.Get(1).(*redisfailoverv1.RedisFailover).Status.DeepCopy()
+				recorded = append(recorded, status.Switchover)
+			}).Return(nil)
+			if test.expectFailover {
+				mr.On("GetRedisConfig", mock.Anything, "1.1.1.1", "0", []string{"replica-priority"}, "").Once().Return(map[string]string{"replica-priority": "100"}, nil)
+				mr.On("SetCustomRedisConfig", mock.Anything, "1.1.1.1", "0", []string{"replica-priority 0"}, "").Once().Return(nil)
+				for _, priority := range test.seenPriorities {
+					mr.On("GetSentinelReplicas", mock.Anything, "10.0.0.1", "26379", "master0", "").Once().Return(replicas(priority), nil)
+				}
+				mr.On("SentinelFailover", mock.Anything, "10.0.0.1", "26379", "master0", "").Once().Return(test.failoverErr)
+				if test.failoverErr == nil {
+					mr.On("GetSentinelMasterInfo", mock.Anything, "10.0.0.1", "26379", "master0", "").Once().Return(redis.SentinelMasterInfo{IP: "2.2.2.2", Port: "0", Flags: "master"}, nil)
+				}
+				mr.On("SetCustomRedisConfig", mock.Anything, "1.1.1.1", "0", []string{"replica-priority 100"}, "").Once().Return(nil)
+			}
+
+			healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
+			err := healer.SwitchoverMaster(context.Background(), "10.0.0.1", []string{"1.1.1.1"}, rf)
+
+			if test.expectedError {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+			}
+			if test.expectFailover {
+				assert.Len(recorded, 2)
+				assert.Equal(map[string]string{"1.1.1.1": "100"}, recorded[0].ReplicaPriorities)
+				assert.Nil(recorded[1], "the switchover must be cleared once ended")
+			}
+			assert.Nil(rf.Status.Switchover)
+			mr.AssertExpectations(t)
+		})
+	}
+}
+
+func TestEndSwitchover(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF()
+	current := rf.DeepCopy()
+	current.Status.Switchover = &redisfailoverv1.SwitchoverStatus{ReplicaPriorities: map[string]string{"1.1.1.1": "100", "2.2.2.2": "50"}}
+
+	ms := &mK8SService.Services{}
+	ms.On("GetRedisFailover", mock.Anything, rf.Namespace, rf.Name).Once().Return(current, nil)
+	ms.On("UpdateRedisFailoverStatus", mock.Anything, mock.MatchedBy(func(rf *redisfailoverv1.RedisFailover) bool {
+		return rf.Status.Switchover == nil
+	})).Once().Return(nil)
+	mr := &mRedisService.Client{}
+	mr.On("SetCustomRedisConfig", mock.Anything, "1.1.1.1", "0", []string{"replica-priority 100"}, "").Once().Return(nil)
+	// a replica gone since doesn't keep the switchover recorded
+	mr.On("SetCustomRedisConfig", mock.Anything, "2.2.2.2", "0", []string{"replica-priority 50"}, "").Once().Return(errors.New("gone"))
+
+	healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
+	assert.NoError(healer.EndSwitchover(context.Background(), rf))
+	ms.AssertExpectations(t)
+	mr.AssertExpectations(t)
+}
+
+func TestSwitchoverInFlight(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF()
+	assert.False(rfservice.SwitchoverInFlight(rf))
+	rf.Status.Switchover = &redisfailoverv1.SwitchoverStatus{StartTime: metav1.Now()}
+	assert.True(rfservice.SwitchoverInFlight(rf))
+	rf.Status.Switchover.StartTime = metav1.NewTime(time.Now().Add(-time.Hour))
+	assert.False(rfservice.SwitchoverInFlight(rf), "a switchover past its waits was left by a stopped operator")
+}
+
+func TestCheckRedisSlaveInSync(t *testing.T) {
+	tests := []struct {
+		name     string
+		slave    redis.ReplicationInfo
+		expected bool
+	}{
+		{
+			name:     "is in sync with the link up and a small lag",
+			slave:    redis.ReplicationInfo{Role: "slave", MasterLinkStatus: "up", Offset: 10000000},
+			expected: true,
+		},
+		{
+			name:  "is out of sync with the link down",
+			slave: redis.ReplicationInfo{Role: "slave", MasterLinkStatus: "down", Offset: 10000000},
+		},
+		{
+			name:  "is out of sync far behind the master",
+			slave: redis.ReplicationInfo{Role: "slave", MasterLinkStatus: "up", Offset: 10},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateRF()
+			ms := &mK8SService.Services{}
+			mr := &mRedisService.Client{}
+			mr.On("GetReplicationInfo", mock.Anything, "1.1.1.1", "0", "").Once().Return(test.slave, nil)
+			mr.On("GetReplicationInfo", mock.Anything, "0.0.0.0", "0", "").Maybe().Return(redis.ReplicationInfo{Role: "master", Offset: 10000100}, nil)
+
+			checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
+			inSync, err := checker.CheckRedisSlaveInSync(context.Background(), "1.1.1.1", "0.0.0.0", rf)
+
+			assert.NoError(err)
+			assert.Equal(test.expected, inSync)
+			mr.AssertExpectations(t)
+		})
+	}
+}
@@ -3,7 +3,6 @@ package service
 import (
 	"bytes"
 	"fmt"
-	"strings"
 	"text/template"
 
 	appsv1 "k8s.io/api/apps/v1"
@@ -98,12 +97,21 @@ rename-command shutdown ""
 {{- range .Spec.Redis.CustomCommandRenames}}
 rename-command "{{.From}}" "{{.To}}"
 {{- end}}
+{{- if and .Spec.BootstrapNode .Spec.BootstrapNode.TLS }}
+tls-replication yes
+tls-cert-file /redis-bootstrap-tls/tls.crt
+tls-key-file /redis-bootstrap-tls/tls.key
+tls-ca-cert-file /redis-bootstrap-tls/ca.crt
+{{- end}}
 `
 
-	sentinelConfigTemplate = `sentinel monitor master0 127.0.0.1 {{.Spec.Redis.Port}} 2
-sentinel down-after-milliseconds master0 5000
-sentinel failover-timeout master0 60000
-sentinel parallel-syncs master0 2
+	sentinelConfigTemplate = `{{- if .Spec.AnnounceHostnames }}sentinel resolve-hostnames yes
+sentinel announce-hostnames yes
+{{ end }}port {{.Spec.Sentinel.Port}}
+sentinel monitor {{.Spec.Sentinel.MasterName}} 127.0.0.1 {{.Spec.Redis.Port}} 2
+sentinel down-after-milliseconds {{.Spec.Sentinel.MasterName}} 5000
+sentinel failover-timeout {{.Spec.Sentinel.MasterName}} 60000
+sentinel parallel-syncs {{.Spec.Sentinel.MasterName}} 2
 logfile /log/sentinel.log`
 
 	predixyConfigurationVolumeName     = "predixy-config"
@@ -139,12 +147,15 @@ Include auth.conf`
     ServerRetryTimeout 1
     KeepAlive 0
     Password {{ .RedisPassword }}
+    {{- if .SentinelPassword }}
+    SentinelPassword {{ .SentinelPassword }}
+    {{- end }}
     Sentinels {
-    {{- range .SentinelIPs }}
-        + {{ . }}:26379
+    {{- range .Sentinels }}
+        + {{ . }}:{{ $.SentinelPort }}
     {{- end }}
     }
-    Group master0 {
+    Group {{ .MasterName }} {
     }
 }`
 
@@ -163,11 +174,87 @@ Include auth.conf`
     }
 }`
 
+	envoyConfigTemplate = `admin:
+  address:
+    socket_address:
+      address: 0.0.0.0
+      port_value: {{ .AdminPort }}
+dynamic_resources:
+  cds_config:
+    resource_api_version: V3
+    path_config_source:
+      path: {{ .ConfigPath }}/cds.yaml
+      watched_directory:
+        path: {{ .ConfigPath }}
+static_resources:
+  listeners:
+  {{- range .Listeners }}
+  - name: {{ .Name }}
+    address:
+      socket_address:
+        address: 0.0.0.0
+        port_value: {{ .Port }}
+    filter_chains:
+    - filters:
+      - name: envoy.filters.network.redis_proxy
+        typed_config:
+          "@type": type.googleapis.com/envoy.extensions.filters.network.redis_proxy.v3.RedisProxy
+          stat_prefix: {{ .Name }}
+          settings:
+            op_timeout: 5s
+          prefix_routes:
+            catch_all_route:
+              cluster: {{ .Cluster }}
+          {{- if $.Password }}
+          downstream_auth_passwords:
+          - inline_string: {{ printf "%q" $.Password }}
+          {{- end }}
+  {{- end }}`
+
+	envoyClustersTemplate = `resources:
+{{- range .Clusters }}
+- "@type": type.googleapis.com/envoy.config.cluster.v3.Cluster
+  name: {{ .Name }}
+  connect_timeout: 1s
+  type: {{ if $.Hostnames }}STRICT_DNS{{ else }}STATIC{{ end }}
+  {{- if $.Password }}
+  typed_extension_protocol_options:
+    envoy.filters.network.redis_proxy:
+      "@type": type.googleapis.com/envoy.extensions.filters.network.redis_proxy.v3.RedisProtocolOptions
+      auth_password:
+        inline_string: {{ printf "%q" $.Password }}
+  {{- end }}
+  load_assignment:
+    cluster_name: {{ .Name }}
+    endpoints:
+    - lb_endpoints:{{ if not .Addresses }} []{{ end }}
+      {{- range .Addresses }}
+      - endpoint:
+          address:
+            socket_address:
+              address: {{ . }}
+              port_value: {{ $.Port }}
+      {{- end }}
+{{- end }}`
+
+	envoyConfigurationVolumeName = "envoy-config"
+	envoyConfigPath              = "/etc/envoy"
+	envoyMasterClusterName       = "redis_master"
+	envoyReplicasClusterName     = "redis_replicas"
+	envoyWritePort               = 6379
+	envoyReadPort                = 6380
+	// envoyMasterDataKey holds the master of cds.yaml in the configmap, the pods are rolled when it changes
+	envoyMasterDataKey = "master"
+
 	redisShutdownConfigurationVolumeName   = "redis-shutdown-config"
 	redisStartupConfigurationVolumeName    = "redis-startup-config"
 	redisReadinessVolumeName               = "redis-readiness-config"
 	redisStorageVolumeName                 = "redis-data"
 	redisLogVolumeName                     = "redis-log"
+	redisBootstrapTLSVolumeName            = "redis-bootstrap-tls"
+	redisAuthVolumeName                    = "redis-auth"
+	redisAuthMountPath                     = "/redis-auth"
+	redisAuthPasswordFile                  = redisAuthMountPath + "/password"
 	sentinelStartupConfigurationVolumeName = "sentinel-startup-config"
 	sentinelLogVolumeName                  = "sentinel-log"
 	predixyLogVolumeName                   = "predixy-log"
@@ -209,11 +296,20 @@ func generateSentinelService(rf *redisfailoverv1.RedisFailover, labels map[strin
 			OwnerReferences: ownerRefs,
 			Annotations:     annotations,
 		},
+		// Headless, so every sentinel pod gets a stable DNS name through the statefulset.
+		// Names are published before the pods are ready so clients can always resolve them.
 		Spec: corev1.ServiceSpec{
-			Type:      corev1.ServiceTypeClusterIP,
-			ClusterIP: corev1.ClusterIPNone,
-			Selector:  selectorLabels,
+			Type:                     corev1.ServiceTypeClusterIP,
+			ClusterIP:                corev1.ClusterIPNone,
+			PublishNotReadyAddresses: true,
+			Selector:                 selectorLabels,
 			Ports: []corev1.ServicePort{
+				{
+					Name:       sentinelRoleName,
+					Port:       rf.Spec.Sentinel.Port,
+					TargetPort: intstr.FromInt(int(rf.Spec.Sentinel.Port)),
+					Protocol:   corev1.ProtocolTCP,
+				},
 				{
 					Name:     exporterPortName,
 					Port:     sentinelExporterPort,
@@ -224,6 +320,21 @@ func generateSentinelService(rf *redisfailoverv1.RedisFailover, labels map[strin
 	}
 }
 
+// getSentinelAddresses returns the stable DNS names the headless sentinel service gives to the sentinel pods
+func getSentinelAddresses(rf *redisfailoverv1.RedisFailover) []string {
+	name := GetSentinelName(rf)
+	addresses := []string{}
+	for _, podName := range getPodNames(name, rf.Spec.Sentinel.Replicas) {
+		addresses = append(addresses, getPodHostname(podName, name, rf.Namespace))
+	}
+	return addresses
+}
+
+// getPodHostname returns the stable DNS name a headless service gives to a pod of the statefulset it governs
+func getPodHostname(podName, serviceName, namespace string) string {
+	return fmt.Sprintf("%s.%s.%s.svc", podName, serviceName, namespace)
+}
+
 func generateRedisService(rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) *corev1.Service {
 	name := GetRedisName(rf)
 	namespace := rf.Namespace
@@ -237,7 +348,7 @@ func generateRedisService(rf *redisfailoverv1.RedisFailover, labels map[string]s
 	}
 	annotations := util.MergeLabels(defaultAnnotations, rf.Spec.Redis.ServiceAnnotations)
 
-	return &corev1.Service{
+	svc := &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:            name,
 			Namespace:       namespace,
@@ -258,9 +369,109 @@ func generateRedisService(rf *redisfailoverv1.RedisFailover, labels map[string]s
 			Selector: selectorLabels,
 		},
 	}
+
+	// The service gives the redis pods the names they announce, they have to resolve
+	// before the pods are ready so the replicas can sync with the master
+	if rf.Spec.AnnounceHostnames {
+		svc.Spec.PublishNotReadyAddresses = true
+		svc.Spec.Ports = append([]corev1.ServicePort{
+			{
+				Port:       rf.Spec.Redis.Port,
+				TargetPort: intstr.FromInt(int(rf.Spec.Redis.Port)),
+				Protocol:   corev1.ProtocolTCP,
+				Name:       redisRoleName,
+			},
+		}, svc.Spec.Ports...)
+	}
+
+	return svc
+}
+
+func generateRedisMasterService(rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) *corev1.Service {
+	return generateRedisRoleService(rf, GetRedisMasterName(rf), generateRedisMasterRoleLabel(), rf.Spec.Redis.MasterService, labels, ownerRefs)
+}
+
+func generateRedisReplicasService(rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) *corev1.Service {
+	return generateRedisRoleService(rf, GetRedisReplicasName(rf), generateRedisSlaveRoleLabel(), rf.Spec.Redis.ReplicasService, labels, ownerRefs)
+}
+
+// generateRedisRoleService generates a service selecting the redis pods by the role label the
+// operator keeps up to date, so it follows the failovers
+func generateRedisRoleService(rf *redisfailoverv1.RedisFailover, name string, roleLabel map[string]string, settings redisfailoverv1.RedisRoleServiceSettings, labels map[string]string, ownerRefs []metav1.OwnerReference) *corev1.Service {
+	selectorLabels := generateSelectorLabels(redisRoleName, rf.Name)
+	labels = util.MergeLabels(labels, selectorLabels)
+
+	serviceType := settings.Type
+	if serviceType == "" {
+		serviceType = corev1.ServiceTypeClusterIP
+	}
+
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       rf.Namespace,
+			Labels:          labels,
+			OwnerReferences: ownerRefs,
+			Annotations:     settings.Annotations,
+		},
+		Spec: corev1.ServiceSpec{
+			Type:     serviceType,
+			Selector: util.MergeLabels(selectorLabels, roleLabel),
+			Ports: []corev1.ServicePort{
+				{
+					Name:       redisRoleName,
+					Port:       rf.Spec.Redis.Port,
+					TargetPort: intstr.FromInt(int(rf.Spec.Redis.Port)),
+					Protocol:   corev1.ProtocolTCP,
+				},
+			},
+		},
+	}
 }
 
-func generateSentinelConfigMap(rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) *corev1.ConfigMap {
+// generateExternalAccessService generates the service exposing a single redis or sentinel pod outside the cluster
+func generateExternalAccessService(rf *redisfailoverv1.RedisFailover, podName string, component string, port int32, labels map[string]string, ownerRefs []metav1.OwnerReference) *corev1.Service {
+	labels = util.MergeLabels(labels, generateSelectorLabels(component, rf.Name), map[string]string{
+		externalAccessLabelKey: "true",
+	})
+
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            podName,
+			Namespace:       rf.Namespace,
+			Labels:          labels,
+			OwnerReferences: ownerRefs,
+			Annotations:     rf.Spec.ExternalAccess.Annotations,
+		},
+		// The pod is reachable before it's ready, replicas sync and sentinels talk to each other through it
+		Spec: corev1.ServiceSpec{
+			Type:                     rf.Spec.ExternalAccess.Type,
+			PublishNotReadyAddresses: true,
+			Selector: map[string]string{
+				appsv1.StatefulSetPodNameLabel: podName,
+			},
+			Ports: []corev1.ServicePort{
+				{
+					Name:       component,
+					Port:       port,
+					TargetPort: intstr.FromInt(int(port)),
+					Protocol:   corev1.ProtocolTCP,
+				},
+			},
+		},
+	}
+}
+
+// getPodNames returns the names of the pods of a statefulset with the given replicas
+func getPodNames(statefulSetName string, replicas int32) []string {
+	names := make([]string, 0, replicas)
+	for i := 0; i < int(replicas); i++ {
+		names = append(names, fmt.Sprintf("%s-%d", statefulSetName, i))
+	}
+	return names
+}
+
+func generateSentinelConfigMap(rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference, password string) *corev1.ConfigMap {
 	name := GetSentinelName(rf)
 	namespace := rf.Namespace
 
@@ -278,6 +489,11 @@ func generateSentinelConfigMap(rf *redisfailoverv1.RedisFailover, labels map[str
 
 	sentinelConfigFileContent := tplOutput.String()
 
+	// Sentinels authenticate to each other with the same password their clients use
+	if password != "" {
+		sentinelConfigFileContent = fmt.Sprintf("%s\nrequirepass %s\nsentinel sentinel-pass %s", sentinelConfigFileContent, password, password)
+	}
+
 	return &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:            name,
@@ -291,7 +507,7 @@ func generateSentinelConfigMap(rf *redisfailoverv1.RedisFailover, labels map[str
 	}
 }
 
-func generateRedisConfigMap(rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference, password string) *corev1.ConfigMap {
+func generateRedisConfigMap(rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference, password string, masterAuth string, previousPassword string) *corev1.ConfigMap {
 	name := GetRedisName(rf)
 	labels = util.MergeLabels(labels, generateSelectorLabels(redisRoleName, rf.Name))
 
@@ -307,8 +523,14 @@ func generateRedisConfigMap(rf *redisfailoverv1.RedisFailover, labels map[string
 
 	redisConfigFileContent := tplOutput.String()
 
-	if password != "" {
-		redisConfigFileContent = fmt.Sprintf("%s\nmasterauth %s\nrequirepass %s", redisConfigFileContent, password, password)
+	if masterAuth != "" {
+		redisConfigFileContent = fmt.Sprintf("%s\nmasterauth %s", redisConfigFileContent, masterAuth)
+	}
+	// While the password is rotated the default user accepts both, so restarted redises keep serving old clients
+	if password != "" && previousPassword != "" && previousPassword != password {
+		redisConfigFileContent = fmt.Sprintf("%s\nuser default on >%s >%s ~* &* +@all", redisConfigFileContent, password, previousPassword)
+	} else if password != "" {
+		redisConfigFileContent = fmt.Sprintf("%s\nrequirepass %s", redisConfigFileContent, password)
 	}
 
 	return &corev1.ConfigMap{
@@ -328,20 +550,34 @@ func generateRedisShutdownConfigMap(rf *redisfailoverv1.RedisFailover, labels ma
 	name := GetRedisShutdownConfigMapName(rf)
 	port := rf.Spec.Redis.Port
 	namespace := rf.Namespace
-	rfName := strings.Replace(strings.ToUpper(rf.Name), "-", "_", -1)
+	// The sentinel service is headless, so kubernetes doesn't inject its environment variables
+	sentinelHost := fmt.Sprintf("%s.%s.svc", GetSentinelName(rf), namespace)
+
+	// Sentinel gives the master by the address it announces
+	self := "$(hostname -i)"
+	if rf.Spec.AnnounceHostnames {
+		self = "${" + redisAnnounceHostnameEnv + "}"
+	}
+
+	sentinelCli := fmt.Sprintf("redis-cli -h %v -p %v", sentinelHost, rf.Spec.Sentinel.Port)
+	if rf.Spec.Sentinel.Auth.SecretPath != "" {
+		sentinelCli = fmt.Sprintf("REDISCLI_AUTH=${%s} %s", sentinelPasswordEnv, sentinelCli)
+	}
 
 	labels = util.MergeLabels(labels, generateSelectorLabels(redisRoleName, rf.Name))
-	shutdownContent := fmt.Sprintf(`master=$(redis-cli -h ${RFS_%[1]v_SERVICE_HOST} -p ${RFS_%[1]v_SERVICE_PORT_SENTINEL} --csv SENTINEL get-master-addr-by-name master0 | tr ',' ' ' | tr -d '\"' |cut -d' ' -f1)
-if [ "$master" = "$(hostname -i)" ]; then
-  redis-cli -h ${RFS_%[1]v_SERVICE_HOST} -p ${RFS_%[1]v_SERVICE_PORT_SENTINEL} SENTINEL failover master0
+	shutdownContent := fmt.Sprintf(`master=$(%[1]v --csv SENTINEL get-master-addr-by-name %[2]v | tr ',' ' ' | tr -d '\"' |cut -d' ' -f1)
+if [ "$master" = "%[4]v" ]; then
+  %[1]v SENTINEL failover %[2]v
   sleep 1
 fi
-cmd="redis-cli -p %[2]v"
-if [ ! -z "${REDIS_PASSWORD}" ]; then
+cmd="redis-cli -p %[3]v"
+if [ -f %[5]v ]; then
+	export REDISCLI_AUTH=$(cat %[5]v)
+elif [ ! -z "${REDIS_PASSWORD}" ]; then
 	export REDISCLI_AUTH=${REDIS_PASSWORD}
 fi
 save_command="${cmd} save"
-eval $save_command`, rfName, port)
+eval $save_command`, sentinelCli, rf.Spec.Sentinel.MasterName, port, self, redisAuthPasswordFile)
 
 	return &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
@@ -369,7 +605,9 @@ IN_SYNC="master_sync_in_progress:1"
 NO_MASTER="master_host:127.0.0.1"
 
 cmd="redis-cli -p %[1]v"
-if [ ! -z "${REDIS_PASSWORD}" ]; then
+if [ -f %[2]v ]; then
+	export REDISCLI_AUTH=$(cat %[2]v)
+elif [ ! -z "${REDIS_PASSWORD}" ]; then
 	export REDISCLI_AUTH=${REDIS_PASSWORD}
 fi
 
@@ -401,7 +639,7 @@ case $role in
 		*)
 				echo "unespected"
 				exit 1
-esac`, port)
+esac`, port, redisAuthPasswordFile)
 
 	return &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
@@ -419,7 +657,6 @@ esac`, port)
 func generateRedisStatefulSet(rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) *appsv1.StatefulSet {
 	name := GetRedisName(rf)
 	namespace := rf.Namespace
-	fmt.Println("Redis    Service Name: ", name)
 
 	redisCommand := getRedisCommand(rf)
 	selectorLabels := generateSelectorLabels(redisRoleName, rf.Name)
@@ -583,7 +820,6 @@ func generateSentinelStatefulSet(rf *redisfailoverv1.RedisFailover, labels map[s
 	name := GetSentinelName(rf)
 	configMapName := GetSentinelName(rf)
 	namespace := rf.Namespace
-	fmt.Println("Sentinel Service Name: ", name)
 
 	sentinelCommand := getSentinelCommand(rf)
 	selectorLabels := generateSelectorLabels(sentinelRoleName, rf.Name)
@@ -641,11 +877,8 @@ func generateSentinelStatefulSet(rf *redisfailoverv1.RedisFailover, labels map[s
 									MountPath: "/redis-writable",
 								},
 							},
-							Command: []string{
-								"cp",
-								fmt.Sprintf("/redis/%s", sentinelConfigFileName),
-								fmt.Sprintf("/redis-writable/%s", sentinelConfigFileName),
-							},
+							Command: getSentinelConfigCopyCommand(rf),
+							Env:     getSentinelEnv(rf),
 							Resources: corev1.ResourceRequirements{
 								Limits: corev1.ResourceList{
 									corev1.ResourceCPU:    resource.MustParse("10m"),
@@ -667,12 +900,13 @@ func generateSentinelStatefulSet(rf *redisfailoverv1.RedisFailover, labels map[s
 							Ports: []corev1.ContainerPort{
 								{
 									Name:          "sentinel",
-									ContainerPort: 26379,
+									ContainerPort: rf.Spec.Sentinel.Port,
 									Protocol:      corev1.ProtocolTCP,
 								},
 							},
 							VolumeMounts: volumeMounts,
 							Command:      sentinelCommand,
+							Env:          getSentinelAuthEnv(rf, "REDISCLI_AUTH"),
 							ReadinessProbe: &corev1.Probe{
 								InitialDelaySeconds: graceTime,
 								TimeoutSeconds:      5,
@@ -681,7 +915,7 @@ func generateSentinelStatefulSet(rf *redisfailoverv1.RedisFailover, labels map[s
 										Command: []string{
 											"sh",
 											"-c",
-											"redis-cli -h $(hostname) -p 26379 ping",
+											fmt.Sprintf("redis-cli -h $(hostname) -p %v ping", rf.Spec.Sentinel.Port),
 										},
 									},
 								},
@@ -694,7 +928,7 @@ func generateSentinelStatefulSet(rf *redisfailoverv1.RedisFailover, labels map[s
 										Command: []string{
 											"sh",
 											"-c",
-											"redis-cli -h $(hostname) -p 26379 ping",
+											fmt.Sprintf("redis-cli -h $(hostname) -p %v ping", rf.Spec.Sentinel.Port),
 										},
 									},
 								},
@@ -734,11 +968,15 @@ func generateSentinelStatefulSet(rf *redisfailoverv1.RedisFailover, labels map[s
 		ss.Spec.Template.Spec.Containers = append(ss.Spec.Template.Spec.Containers, rf.Spec.Sentinel.ExtraContainers...)
 	}
 
+	if rf.Spec.Redis.Zones != nil {
+		spreadSentinelsAcrossZones(rf, ss)
+	}
+
 	return ss
 }
 
-func generatePodDisruptionBudget(name string, namespace string, labels map[string]string, ownerRefs []metav1.OwnerReference, minAvailable intstr.IntOrString) *policyv1.PodDisruptionBudget {
-	return &policyv1.PodDisruptionBudget{
+func generatePodDisruptionBudget(name string, namespace string, labels map[string]string, ownerRefs []metav1.OwnerReference, settings redisfailoverv1.PodDisruptionBudgetSettings, defaultMinAvailable intstr.IntOrString) *policyv1.PodDisruptionBudget {
+	pdb := &policyv1.PodDisruptionBudget{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:            name,
 			Namespace:       namespace,
@@ -746,12 +984,23 @@ func generatePodDisruptionBudget(name string, namespace string, labels map[strin
 			OwnerReferences: ownerRefs,
 		},
 		Spec: policyv1.PodDisruptionBudgetSpec{
-			MinAvailable: &minAvailable,
 			Selector: &metav1.LabelSelector{
 				MatchLabels: labels,
 			},
 		},
 	}
+
+	switch {
+	case settings.MaxUnavailable != nil:
+		maxUnavailable := *settings.MaxUnavailable
+		pdb.Spec.MaxUnavailable = &maxUnavailable
+	case settings.MinAvailable != nil:
+		minAvailable := *settings.MinAvailable
+		pdb.Spec.MinAvailable = &minAvailable
+	default:
+		pdb.Spec.MinAvailable = &defaultMinAvailable
+	}
+	return pdb
 }
 
 var exporterDefaultResourceRequirements = corev1.ResourceRequirements{
@@ -787,7 +1036,7 @@ func createRedisExporterContainer(rf *redisfailoverv1.RedisFailover) corev1.Cont
 		),
 		Ports: []corev1.ContainerPort{
 			{
-				Name:          "metrics",
+				Name:          exporterContainerPortName,
 				ContainerPort: exporterPort,
 				Protocol:      corev1.ProtocolTCP,
 			},
@@ -824,18 +1073,19 @@ func createSentinelExporterContainer(rf *redisfailoverv1.RedisFailover) corev1.C
 			Value: fmt.Sprintf("0.0.0.0:%[1]v", sentinelExporterPort),
 		}, corev1.EnvVar{
 			Name:  "REDIS_ADDR",
-			Value: "redis://127.0.0.1:26379",
+			Value: fmt.Sprintf("redis://127.0.0.1:%[1]v", rf.Spec.Sentinel.Port),
 		},
 		),
 		Ports: []corev1.ContainerPort{
 			{
-				Name:          "metrics",
+				Name:          exporterContainerPortName,
 				ContainerPort: sentinelExporterPort,
 				Protocol:      corev1.ProtocolTCP,
 			},
 		},
 		Resources: resources,
 	}
+	container.Env = append(container.Env, getSentinelAuthEnv(rf, "REDIS_PASSWORD")...)
 
 	return container
 }
@@ -951,6 +1201,23 @@ func getRedisVolumeMounts(rf *redisfailoverv1.RedisFailover) []corev1.VolumeMoun
 		volumeMounts = append(volumeMounts, startupVolumeMount)
 	}
 
+	if rf.Bootstrapping() && rf.Spec.BootstrapNode.TLS != nil {
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      redisBootstrapTLSVolumeName,
+			MountPath: "/redis-bootstrap-tls",
+			ReadOnly:  true,
+		})
+	}
+
+	// The scripts read the password from the secret, env vars keep the one the pod started with
+	if rf.Spec.Auth.SecretPath != "" {
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      redisAuthVolumeName,
+			MountPath: redisAuthMountPath,
+			ReadOnly:  true,
+		})
+	}
+
 	if rf.Spec.Redis.ExtraVolumeMounts != nil {
 		volumeMounts = append(volumeMounts, rf.Spec.Redis.ExtraVolumeMounts...)
 	}
@@ -1057,6 +1324,34 @@ func getRedisVolumes(rf *redisfailoverv1.RedisFailover) []corev1.Volume {
 		volumes = append(volumes, startupVolume)
 	}
 
+	if rf.Bootstrapping() && rf.Spec.BootstrapNode.TLS != nil {
+		volumes = append(volumes, corev1.Volume{
+			Name: redisBootstrapTLSVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: rf.Spec.BootstrapNode.TLS.SecretName,
+				},
+			},
+		})
+	}
+
+	if rf.Spec.Auth.SecretPath != "" {
+		volumes = append(volumes, corev1.Volume{
+			Name: redisAuthVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: rf.Spec.Auth.SecretPath,
+					Items: []corev1.KeyToPath{
+						{
+							Key:  "password",
+							Path: "password",
+						},
+					},
+				},
+			},
+		})
+	}
+
 	if rf.Spec.Redis.ExtraVolumes != nil {
 		volumes = append(volumes, rf.Spec.Redis.ExtraVolumes...)
 	}
@@ -1167,10 +1462,31 @@ func getRedisCommand(rf *redisfailoverv1.RedisFailover) []string {
 	if len(rf.Spec.Redis.Command) > 0 {
 		return rf.Spec.Redis.Command
 	}
+	command := fmt.Sprintf("sleep 15 && redis-server /redis/%s", redisConfigFileName)
+	if rf.Spec.AnnounceHostnames {
+		command = fmt.Sprintf("%s --replica-announce-ip ${%s}", command, redisAnnounceHostnameEnv)
+	}
 	return []string{
 		"/bin/sh",
 		"-c",
-		fmt.Sprintf("sleep 15 && redis-server /redis/%s", redisConfigFileName),
+		command,
+	}
+}
+
+// getSentinelConfigCopyCommand copies the sentinel configuration to a writable volume, adding
+// the hostname the sentinel announces when the failover uses hostnames
+func getSentinelConfigCopyCommand(rf *redisfailoverv1.RedisFailover) []string {
+	if !rf.Spec.AnnounceHostnames {
+		return []string{
+			"cp",
+			fmt.Sprintf("/redis/%s", sentinelConfigFileName),
+			fmt.Sprintf("/redis-writable/%s", sentinelConfigFileName),
+		}
+	}
+	return []string{
+		"/bin/sh",
+		"-c",
+		fmt.Sprintf(`cp /redis/%[1]s /redis-writable/%[1]s && echo "sentinel announce-ip ${%[2]s}" >> /redis-writable/%[1]s`, sentinelConfigFileName, sentinelAnnounceHostnameEnv),
 	}
 }
 
@@ -1230,9 +1546,58 @@ func getContainersWithRedisEnv(cs []corev1.Container, e []corev1.EnvVar) []corev
 	return containers
 }
 
+// getPodHostnameEnv returns the variables holding the stable DNS name of the pod under the given headless service
+func getPodHostnameEnv(name, serviceName, namespace string) []corev1.EnvVar {
+	return []corev1.EnvVar{
+		{
+			Name: podNameEnv,
+			ValueFrom: &corev1.EnvVarSource{
+				FieldRef: &corev1.ObjectFieldSelector{
+					FieldPath: "metadata.name",
+				},
+			},
+		},
+		{
+			Name:  name,
+			Value: getPodHostname(fmt.Sprintf("$(%s)", podNameEnv), serviceName, namespace),
+		},
+	}
+}
+
+// getSentinelAuthEnv returns the variable with the given name holding the sentinel password, if any
+func getSentinelAuthEnv(rf *redisfailoverv1.RedisFailover, name string) []corev1.EnvVar {
+	if rf.Spec.Sentinel.Auth.SecretPath == "" {
+		return nil
+	}
+	return []corev1.EnvVar{
+		{
+			Name: name,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: rf.Spec.Sentinel.Auth.SecretPath,
+					},
+					Key: "password",
+				},
+			},
+		},
+	}
+}
+
+func getSentinelEnv(rf *redisfailoverv1.RedisFailover) []corev1.EnvVar {
+	if !rf.Spec.AnnounceHostnames {
+		return nil
+	}
+	return getPodHostnameEnv(sentinelAnnounceHostnameEnv, GetSentinelName(rf), rf.Namespace)
+}
+
 func getRedisEnv(rf *redisfailoverv1.RedisFailover) []corev1.EnvVar {
 	var env []corev1.EnvVar
 
+	if rf.Spec.AnnounceHostnames {
+		env = append(env, getPodHostnameEnv(redisAnnounceHostnameEnv, GetRedisName(rf), rf.Namespace)...)
+	}
+
 	env = append(env, corev1.EnvVar{
 		Name:  "REDIS_ADDR",
 		Value: fmt.Sprintf("redis://127.0.0.1:%[1]v", rf.Spec.Redis.Port),
@@ -1262,28 +1627,36 @@ func getRedisEnv(rf *redisfailoverv1.RedisFailover) []corev1.EnvVar {
 		})
 	}
 
+	// The shutdown script asks the sentinels for a failover
+	env = append(env, getSentinelAuthEnv(rf, sentinelPasswordEnv)...)
+
 	return env
 }
 
-func generatePredixyConfigMap(rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference, sentinels []string, password string) *corev1.ConfigMap {
+func generatePredixyConfigMap(rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference, password string, sentinelPassword string) *corev1.ConfigMap {
 	name := GetPredixyName(rf)
 	namespace := rf.Namespace
-	fmt.Println("Predixy  Service Name: ", name)
 
 	labels = util.MergeLabels(labels, generateSelectorLabels(predixyRoleName, rf.Name))
 
 	type PredixConf struct {
-		SentinelIPs   []string
-		ReadPassword  string
-		AdminPassword string
-		RedisPassword string
+		Sentinels        []string
+		SentinelPort     int32
+		SentinelPassword string
+		MasterName       string
+		ReadPassword     string
+		AdminPassword    string
+		RedisPassword    string
 	}
 
 	conf := PredixConf{
-		SentinelIPs:   sentinels,
-		ReadPassword:  predixyReadPassword,
-		AdminPassword: predixyAdminPassword,
-		RedisPassword: password,
+		Sentinels:        getSentinelAddresses(rf),
+		SentinelPort:     rf.Spec.Sentinel.Port,
+		SentinelPassword: sentinelPassword,
+		MasterName:       rf.Spec.Sentinel.MasterName,
+		ReadPassword:     predixyReadPassword,
+		AdminPassword:    predixyAdminPassword,
+		RedisPassword:    password,
 	}
 
 	// sentinel.conf
@@ -1618,7 +1991,7 @@ func createPredixyExporterContainer(rf *redisfailoverv1.RedisFailover) corev1.Co
 		Env:             envs,
 		Ports: []corev1.ContainerPort{
 			{
-				Name:          "metrics",
+				Name:          exporterContainerPortName,
 				ContainerPort: predixyExporterPort,
 				Protocol:      corev1.ProtocolTCP,
 			},
@@ -1627,3 +2000,255 @@ func createPredixyExporterContainer(rf *redisfailoverv1.RedisFailover) corev1.Co
 	}
 	return container
 }
+
+func generateEnvoyConfigMap(rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference, backends ProxyBackends) *corev1.ConfigMap {
+	name := GetEnvoyName(rf)
+	namespace := rf.Namespace
+
+	labels = util.MergeLabels(labels, generateSelectorLabels(envoyRoleName, rf.Name))
+
+	type envoyListener struct {
+		Name    string
+		Port    int
+		Cluster string
+	}
+	type envoyCluster struct {
+		Name      string
+		Addresses []string
+	}
+
+	master := []string{}
+	if backends.Master != "" {
+		master = append(master, backends.Master)
+	}
+	// Reads are served by the master while there are no replicas to send them to
+	replicas := backends.Replicas
+	if len(replicas) == 0 {
+		replicas = master
+	}
+
+	conf := struct {
+		AdminPort  int
+		ConfigPath string
+		Port       int32
+		Password   string
+		Hostnames  bool
+		Listeners  []envoyListener
+		Clusters   []envoyCluster
+	}{
+		AdminPort:  envoyAdminPort,
+		ConfigPath: envoyConfigPath,
+		Port:       rf.Spec.Redis.Port,
+		Password:   backends.Password,
+		Hostnames:  rf.Spec.AnnounceHostnames,
+		Listeners: []envoyListener{
+			{Name: "redis_write", Port: envoyWritePort, Cluster: envoyMasterClusterName},
+			{Name: "redis_read", Port: envoyReadPort, Cluster: envoyReplicasClusterName},
+		},
+		Clusters: []envoyCluster{
+			{Name: envoyMasterClusterName, Addresses: master},
+			{Name: envoyReplicasClusterName, Addresses: replicas},
+		},
+	}
+
+	// envoy.yaml holds the listeners, cds.yaml the redis endpoints. Envoy watches the latter, but the kubelet only
+	// refreshes a mounted configmap every minute or so, so the pods are rolled when master changes to stop the writes
+	// to the previous one right away. The changes of the replicas are left to the watch.
+	tmpl, err := template.New("envoyConf").Parse(envoyConfigTemplate)
+	if err != nil {
+		panic(err)
+	}
+
+	var tplOutput bytes.Buffer
+	if err := tmpl.Execute(&tplOutput, conf); err != nil {
+		panic(err)
+	}
+	envoyConfFileContent := tplOutput.String()
+
+	clustersTmpl, err := template.New("envoyClusters").Parse(envoyClustersTemplate)
+	if err != nil {
+		panic(err)
+	}
+
+	var clustersTplOutput bytes.Buffer
+	if err := clustersTmpl.Execute(&clustersTplOutput, conf); err != nil {
+		panic(err)
+	}
+	envoyClustersFileContent := clustersTplOutput.String()
+
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       namespace,
+			Labels:          labels,
+			OwnerReferences: ownerRefs,
+		},
+		Data: map[string]string{
+			"envoy.yaml":       envoyConfFileContent,
+			"cds.yaml":         envoyClustersFileContent,
+			envoyMasterDataKey: backends.Master,
+		},
+	}
+}
+
+func generateEnvoyService(rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) *corev1.Service {
+	name := GetEnvoyName(rf)
+	namespace := rf.Namespace
+
+	selectorLabels := generateSelectorLabels(envoyRoleName, rf.Name)
+	labels = util.MergeLabels(labels, selectorLabels)
+	defaultAnnotations := map[string]string{
+		"prometheus.io/scrape": "true",
+		"prometheus.io/port":   "http",
+		"prometheus.io/path":   "/stats/prometheus",
+	}
+
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       namespace,
+			Labels:          labels,
+			OwnerReferences: ownerRefs,
+			Annotations:     defaultAnnotations,
+		},
+		Spec: corev1.ServiceSpec{
+			Selector: selectorLabels,
+			Ports: []corev1.ServicePort{
+				{
+					Name:       "redis-write",
+					Port:       envoyWritePort,
+					TargetPort: intstr.FromInt(envoyWritePort),
+					Protocol:   corev1.ProtocolTCP,
+				},
+				{
+					Name:       "redis-read",
+					Port:       envoyReadPort,
+					TargetPort: intstr.FromInt(envoyReadPort),
+					Protocol:   corev1.ProtocolTCP,
+				},
+				{
+					Name:       exporterPortName,
+					Port:       envoyAdminPort,
+					TargetPort: intstr.FromInt(envoyAdminPort),
+					Protocol:   corev1.ProtocolTCP,
+				},
+			},
+		},
+	}
+}
+
+func generateEnvoyDeployment(rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) *appsv1.Deployment {
+	name := GetEnvoyName(rf)
+	namespace := rf.Namespace
+
+	selectorLabels := generateSelectorLabels(envoyRoleName, rf.Name)
+	labels = util.MergeLabels(labels, selectorLabels)
+
+	terminationGracePeriodSeconds := int64(graceTime)
+	rate := intstr.FromString("25%")
+
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       namespace,
+			Labels:          labels,
+			OwnerReferences: ownerRefs,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &rf.Spec.Proxy.Envoy.Replicas,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: selectorLabels,
+			},
+			Strategy: appsv1.DeploymentStrategy{
+				Type: appsv1.RollingUpdateDeploymentStrategyType,
+				RollingUpdate: &appsv1.RollingUpdateDeployment{
+					MaxSurge:       &rate,
+					MaxUnavailable: &rate,
+				},
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      labels,
+					Annotations: rf.Spec.Proxy.Envoy.PodAnnotations,
+				},
+				Spec: corev1.PodSpec{
+					Affinity:                      getAffinity(nil, labels),
+					NodeSelector:                  rf.Spec.Proxy.Envoy.NodeSelector,
+					SecurityContext:               getSecurityContext(nil),
+					DNSPolicy:                     getDnsPolicy(""),
+					ImagePullSecrets:              rf.Spec.Proxy.Envoy.ImagePullSecrets,
+					TerminationGracePeriodSeconds: &terminationGracePeriodSeconds,
+					Volumes: []corev1.Volume{
+						{
+							Name: envoyConfigurationVolumeName,
+							VolumeSource: corev1.VolumeSource{
+								ConfigMap: &corev1.ConfigMapVolumeSource{
+									LocalObjectReference: corev1.LocalObjectReference{
+										Name: name,
+									},
+								},
+							},
+						},
+					},
+					Containers: []corev1.Container{
+						{
+							Name:            envoyRoleName,
+							Image:           rf.Spec.Proxy.Envoy.Image,
+							ImagePullPolicy: pullPolicy(rf.Spec.Proxy.Envoy.ImagePullPolicy),
+							SecurityContext: getContainerSecurityContext(nil),
+							Ports: []corev1.ContainerPort{
+								{
+									Name:          "redis-write",
+									ContainerPort: envoyWritePort,
+									Protocol:      corev1.ProtocolTCP,
+								},
+								{
+									Name:          "redis-read",
+									ContainerPort: envoyReadPort,
+									Protocol:      corev1.ProtocolTCP,
+								},
+								{
+									Name:          "admin",
+									ContainerPort: envoyAdminPort,
+									Protocol:      corev1.ProtocolTCP,
+								},
+							},
+							// Mounted without subPath so configmap updates reach the watched directory
+							VolumeMounts: []corev1.VolumeMount{
+								{
+									Name:      envoyConfigurationVolumeName,
+									MountPath: envoyConfigPath,
+								},
+							},
+							Command: []string{
+								"envoy",
+								"-c",
+								fmt.Sprintf("%s/envoy.yaml", envoyConfigPath),
+								"--disable-hot-restart",
+							},
+							Resources: rf.Spec.Proxy.Envoy.Resources,
+							ReadinessProbe: &corev1.Probe{
+								TimeoutSeconds: 5,
+								ProbeHandler: corev1.ProbeHandler{
+									HTTPGet: &corev1.HTTPGetAction{
+										Path: "/ready",
+										Port: intstr.FromInt(envoyAdminPort),
+									},
+								},
+							},
+							LivenessProbe: &corev1.Probe{
+								InitialDelaySeconds: graceTime,
+								TimeoutSeconds:      5,
+								ProbeHandler: corev1.ProbeHandler{
+									TCPSocket: &corev1.TCPSocketAction{
+										Port: intstr.FromInt(envoyWritePort),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
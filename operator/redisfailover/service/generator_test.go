@@ -1,15 +1,20 @@
 package service_test
 
 import (
+	"context"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	kubeerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
 	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
@@ -527,14 +532,14 @@ func TestRedisStatefulSetStorageGeneration(t *testing.T) {
 		generatedStatefulSet := appsv1.StatefulSet{}
 
 		ms := &mK8SService.Services{}
-		ms.On("CreateOrUpdatePodDisruptionBudget", namespace, mock.Anything).Once().Return(nil, nil)
-		ms.On("CreateOrUpdateStatefulSet", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
-			ss := args.Get(1).(*appsv1.StatefulSet)
+		ms.On("CreateOrUpdatePodDisruptionBudget", mock.Anything, namespace, mock.Anything).Once().Return(nil, nil)
+		ms.On("CreateOrUpdateStatefulSet", mock.Anything, namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+			ss := args.Get(2).(*appsv1.StatefulSet)
 			generatedStatefulSet = *ss
 		}).Return(nil)
 
 		client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
-		err := client.EnsureRedisStatefulset(rf, nil, test.ownerRefs)
+		err := client.EnsureRedisStatefulset(context.Background(), rf, nil, test.ownerRefs)
 
 		// Check that the storage-related fields are as spected
 		assert.Equal(test.expectedSS.Spec.Template.Spec.Volumes, generatedStatefulSet.Spec.Template.Spec.Volumes)
@@ -581,14 +586,14 @@ func TestRedisStatefulSetCommands(t *testing.T) {
 		gotCommands := []string{}
 
 		ms := &mK8SService.Services{}
-		ms.On("CreateOrUpdatePodDisruptionBudget", namespace, mock.Anything).Once().Return(nil, nil)
-		ms.On("CreateOrUpdateStatefulSet", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
-			ss := args.Get(1).(*appsv1.StatefulSet)
+		ms.On("CreateOrUpdatePodDisruptionBudget", mock.Anything, namespace, mock.Anything).Once().Return(nil, nil)
+		ms.On("CreateOrUpdateStatefulSet", mock.Anything, namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+			ss := args.Get(2).(*appsv1.StatefulSet)
 			gotCommands = ss.Spec.Template.Spec.Containers[0].Command
 		}).Return(nil)
 
 		client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
-		err := client.EnsureRedisStatefulset(rf, nil, []metav1.OwnerReference{})
+		err := client.EnsureRedisStatefulset(context.Background(), rf, nil, []metav1.OwnerReference{})
 
 		assert.Equal(test.expectedCommands, gotCommands)
 		assert.NoError(err)
@@ -633,14 +638,14 @@ func TestSentinelStatefulsetCommands(t *testing.T) {
 		gotCommands := []string{}
 
 		ms := &mK8SService.Services{}
-		ms.On("CreateOrUpdatePodDisruptionBudget", namespace, mock.Anything).Once().Return(nil, nil)
-		ms.On("CreateOrUpdateStatefulSet", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
-			d := args.Get(1).(*appsv1.StatefulSet)
+		ms.On("CreateOrUpdatePodDisruptionBudget", mock.Anything, namespace, mock.Anything).Once().Return(nil, nil)
+		ms.On("CreateOrUpdateStatefulSet", mock.Anything, namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+			d := args.Get(2).(*appsv1.StatefulSet)
 			gotCommands = d.Spec.Template.Spec.Containers[0].Command
 		}).Return(nil)
 
 		client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
-		err := client.EnsureSentinelStatefulset(rf, nil, []metav1.OwnerReference{})
+		err := client.EnsureSentinelStatefulset(context.Background(), rf, nil, []metav1.OwnerReference{})
 
 		assert.Equal(test.expectedCommands, gotCommands)
 		assert.NoError(err)
@@ -681,14 +686,14 @@ func TestRedisStatefulSetPodAnnotations(t *testing.T) {
 		gotPodAnnotations := map[string]string{}
 
 		ms := &mK8SService.Services{}
-		ms.On("CreateOrUpdatePodDisruptionBudget", namespace, mock.Anything).Once().Return(nil, nil)
-		ms.On("CreateOrUpdateStatefulSet", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
-			ss := args.Get(1).(*appsv1.StatefulSet)
+		ms.On("CreateOrUpdatePodDisruptionBudget", mock.Anything, namespace, mock.Anything).Once().Return(nil, nil)
+		ms.On("CreateOrUpdateStatefulSet", mock.Anything, namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+			ss := args.Get(2).(*appsv1.StatefulSet)
 			gotPodAnnotations = ss.Spec.Template.ObjectMeta.Annotations
 		}).Return(nil)
 
 		client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
-		err := client.EnsureRedisStatefulset(rf, nil, []metav1.OwnerReference{})
+		err := client.EnsureRedisStatefulset(context.Background(), rf, nil, []metav1.OwnerReference{})
 
 		assert.Equal(test.expectedPodAnnotations, gotPodAnnotations)
 		assert.NoError(err)
@@ -729,14 +734,14 @@ func TestSentinelStatefulSetPodAnnotations(t *testing.T) {
 		gotPodAnnotations := map[string]string{}
 
 		ms := &mK8SService.Services{}
-		ms.On("CreateOrUpdatePodDisruptionBudget", namespace, mock.Anything).Once().Return(nil, nil)
-		ms.On("CreateOrUpdateStatefulSet", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
-			d := args.Get(1).(*appsv1.StatefulSet)
+		ms.On("CreateOrUpdatePodDisruptionBudget", mock.Anything, namespace, mock.Anything).Once().Return(nil, nil)
+		ms.On("CreateOrUpdateStatefulSet", mock.Anything, namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+			d := args.Get(2).(*appsv1.StatefulSet)
 			gotPodAnnotations = d.Spec.Template.ObjectMeta.Annotations
 		}).Return(nil)
 
 		client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
-		err := client.EnsureSentinelStatefulset(rf, nil, []metav1.OwnerReference{})
+		err := client.EnsureSentinelStatefulset(context.Background(), rf, nil, []metav1.OwnerReference{})
 
 		assert.Equal(test.expectedPodAnnotations, gotPodAnnotations)
 		assert.NoError(err)
@@ -771,14 +776,14 @@ func TestRedisStatefulSetServiceAccountName(t *testing.T) {
 		gotServiceAccountName := ""
 
 		ms := &mK8SService.Services{}
-		ms.On("CreateOrUpdatePodDisruptionBudget", namespace, mock.Anything).Once().Return(nil, nil)
-		ms.On("CreateOrUpdateStatefulSet", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
-			ss := args.Get(1).(*appsv1.StatefulSet)
+		ms.On("CreateOrUpdatePodDisruptionBudget", mock.Anything, namespace, mock.Anything).Once().Return(nil, nil)
+		ms.On("CreateOrUpdateStatefulSet", mock.Anything, namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+			ss := args.Get(2).(*appsv1.StatefulSet)
 			gotServiceAccountName = ss.Spec.Template.Spec.ServiceAccountName
 		}).Return(nil)
 
 		client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
-		err := client.EnsureRedisStatefulset(rf, nil, []metav1.OwnerReference{})
+		err := client.EnsureRedisStatefulset(context.Background(), rf, nil, []metav1.OwnerReference{})
 
 		assert.Equal(test.expectedServiceAccountName, gotServiceAccountName)
 		assert.NoError(err)
@@ -813,14 +818,14 @@ func TestSentinelStatefulSetServiceAccountName(t *testing.T) {
 		gotServiceAccountName := ""
 
 		ms := &mK8SService.Services{}
-		ms.On("CreateOrUpdatePodDisruptionBudget", namespace, mock.Anything).Once().Return(nil, nil)
-		ms.On("CreateOrUpdateStatefulSet", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
-			d := args.Get(1).(*appsv1.StatefulSet)
+		ms.On("CreateOrUpdatePodDisruptionBudget", mock.Anything, namespace, mock.Anything).Once().Return(nil, nil)
+		ms.On("CreateOrUpdateStatefulSet", mock.Anything, namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+			d := args.Get(2).(*appsv1.StatefulSet)
 			gotServiceAccountName = d.Spec.Template.Spec.ServiceAccountName
 		}).Return(nil)
 
 		client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
-		err := client.EnsureSentinelStatefulset(rf, nil, []metav1.OwnerReference{})
+		err := client.EnsureSentinelStatefulset(context.Background(), rf, nil, []metav1.OwnerReference{})
 
 		assert.Equal(test.expectedServiceAccountName, gotServiceAccountName)
 		assert.NoError(err)
@@ -847,6 +852,11 @@ func TestSentinelService(t *testing.T) {
 						"app.kubernetes.io/name":      name,
 						"app.kubernetes.io/part-of":   "redis-failover",
 					},
+					Annotations: map[string]string{
+						"prometheus.io/scrape": "true",
+						"prometheus.io/port":   "http",
+						"prometheus.io/path":   "/metrics",
+					},
 					OwnerReferences: []metav1.OwnerReference{
 						{
 							Name: "testing",
@@ -854,6 +864,9 @@ func TestSentinelService(t *testing.T) {
 					},
 				},
 				Spec: corev1.ServiceSpec{
+					Type:                     corev1.ServiceTypeClusterIP,
+					ClusterIP:                corev1.ClusterIPNone,
+					PublishNotReadyAddresses: true,
 					Selector: map[string]string{
 						"app.kubernetes.io/component": "sentinel",
 						"app.kubernetes.io/name":      name,
@@ -866,6 +879,11 @@ func TestSentinelService(t *testing.T) {
 							TargetPort: intstr.FromInt(26379),
 							Protocol:   "TCP",
 						},
+						{
+							Name:     "http-metrics",
+							Port:     9355,
+							Protocol: "TCP",
+						},
 					},
 				},
 			},
@@ -882,6 +900,11 @@ func TestSentinelService(t *testing.T) {
 						"app.kubernetes.io/name":      "custom-name",
 						"app.kubernetes.io/part-of":   "redis-failover",
 					},
+					Annotations: map[string]string{
+						"prometheus.io/scrape": "true",
+						"prometheus.io/port":   "http",
+						"prometheus.io/path":   "/metrics",
+					},
 					OwnerReferences: []metav1.OwnerReference{
 						{
 							Name: "testing",
@@ -889,6 +912,9 @@ func TestSentinelService(t *testing.T) {
 					},
 				},
 				Spec: corev1.ServiceSpec{
+					Type:                     corev1.ServiceTypeClusterIP,
+					ClusterIP:                corev1.ClusterIPNone,
+					PublishNotReadyAddresses: true,
 					Selector: map[string]string{
 						"app.kubernetes.io/component": "sentinel",
 						"app.kubernetes.io/name":      "custom-name",
@@ -901,6 +927,11 @@ func TestSentinelService(t *testing.T) {
 							TargetPort: intstr.FromInt(26379),
 							Protocol:   "TCP",
 						},
+						{
+							Name:     "http-metrics",
+							Port:     9355,
+							Protocol: "TCP",
+						},
 					},
 				},
 			},
@@ -917,6 +948,11 @@ func TestSentinelService(t *testing.T) {
 						"app.kubernetes.io/name":      name,
 						"app.kubernetes.io/part-of":   "redis-failover",
 					},
+					Annotations: map[string]string{
+						"prometheus.io/scrape": "true",
+						"prometheus.io/port":   "http",
+						"prometheus.io/path":   "/metrics",
+					},
 					OwnerReferences: []metav1.OwnerReference{
 						{
 							Name: "testing",
@@ -924,6 +960,9 @@ func TestSentinelService(t *testing.T) {
 					},
 				},
 				Spec: corev1.ServiceSpec{
+					Type:                     corev1.ServiceTypeClusterIP,
+					ClusterIP:                corev1.ClusterIPNone,
+					PublishNotReadyAddresses: true,
 					Selector: map[string]string{
 						"app.kubernetes.io/component": "sentinel",
 						"app.kubernetes.io/name":      name,
@@ -936,6 +975,11 @@ func TestSentinelService(t *testing.T) {
 							TargetPort: intstr.FromInt(26379),
 							Protocol:   "TCP",
 						},
+						{
+							Name:     "http-metrics",
+							Port:     9355,
+							Protocol: "TCP",
+						},
 					},
 				},
 			},
@@ -953,6 +997,11 @@ func TestSentinelService(t *testing.T) {
 						"app.kubernetes.io/part-of":   "redis-failover",
 						"some":                        "label",
 					},
+					Annotations: map[string]string{
+						"prometheus.io/scrape": "true",
+						"prometheus.io/port":   "http",
+						"prometheus.io/path":   "/metrics",
+					},
 					OwnerReferences: []metav1.OwnerReference{
 						{
 							Name: "testing",
@@ -960,6 +1009,9 @@ func TestSentinelService(t *testing.T) {
 					},
 				},
 				Spec: corev1.ServiceSpec{
+					Type:                     corev1.ServiceTypeClusterIP,
+					ClusterIP:                corev1.ClusterIPNone,
+					PublishNotReadyAddresses: true,
 					Selector: map[string]string{
 						"app.kubernetes.io/component": "sentinel",
 						"app.kubernetes.io/name":      name,
@@ -972,6 +1024,11 @@ func TestSentinelService(t *testing.T) {
 							TargetPort: intstr.FromInt(26379),
 							Protocol:   "TCP",
 						},
+						{
+							Name:     "http-metrics",
+							Port:     9355,
+							Protocol: "TCP",
+						},
 					},
 				},
 			},
@@ -988,7 +1045,12 @@ func TestSentinelService(t *testing.T) {
 						"app.kubernetes.io/name":      name,
 						"app.kubernetes.io/part-of":   "redis-failover",
 					},
-					Annotations: map[string]string{"some": "annotation"},
+					Annotations: map[string]string{
+						"prometheus.io/scrape": "true",
+						"prometheus.io/port":   "http",
+						"prometheus.io/path":   "/metrics",
+						"some":                 "annotation",
+					},
 					OwnerReferences: []metav1.OwnerReference{
 						{
 							Name: "testing",
@@ -996,6 +1058,9 @@ func TestSentinelService(t *testing.T) {
 					},
 				},
 				Spec: corev1.ServiceSpec{
+					Type:                     corev1.ServiceTypeClusterIP,
+					ClusterIP:                corev1.ClusterIPNone,
+					PublishNotReadyAddresses: true,
 					Selector: map[string]string{
 						"app.kubernetes.io/component": "sentinel",
 						"app.kubernetes.io/name":      name,
@@ -1008,6 +1073,11 @@ func TestSentinelService(t *testing.T) {
 							TargetPort: intstr.FromInt(26379),
 							Protocol:   "TCP",
 						},
+						{
+							Name:     "http-metrics",
+							Port:     9355,
+							Protocol: "TCP",
+						},
 					},
 				},
 			},
@@ -1031,13 +1101,13 @@ func TestSentinelService(t *testing.T) {
 			generatedService := corev1.Service{}
 
 			ms := &mK8SService.Services{}
-			ms.On("CreateOrUpdateService", rf.Namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
-				s := args.Get(1).(*corev1.Service)
+			ms.On("CreateOrUpdateService", mock.Anything, rf.Namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+				s := args.Get(2).(*corev1.Service)
 				generatedService = *s
 			}).Return(nil)
 
 			client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
-			err := client.EnsureSentinelService(rf, test.rfLabels, []metav1.OwnerReference{{Name: "testing"}})
+			err := client.EnsureSentinelService(context.Background(), rf, test.rfLabels, []metav1.OwnerReference{{Name: "testing"}})
 
 			assert.Equal(test.expectedService, generatedService)
 			assert.NoError(err)
@@ -1279,13 +1349,13 @@ func TestRedisService(t *testing.T) {
 			generatedService := corev1.Service{}
 
 			ms := &mK8SService.Services{}
-			ms.On("CreateOrUpdateService", rf.Namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
-				s := args.Get(1).(*corev1.Service)
+			ms.On("CreateOrUpdateService", mock.Anything, rf.Namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+				s := args.Get(2).(*corev1.Service)
 				generatedService = *s
 			}).Return(nil)
 
 			client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
-			err := client.EnsureRedisService(rf, test.rfLabels, []metav1.OwnerReference{{Name: "testing"}})
+			err := client.EnsureRedisService(context.Background(), rf, test.rfLabels, []metav1.OwnerReference{{Name: "testing"}})
 
 			assert.Equal(test.expectedService, generatedService)
 			assert.NoError(err)
@@ -1293,6 +1363,148 @@ func TestRedisService(t *testing.T) {
 	}
 }
 
+func TestRedisRoleServices(t *testing.T) {
+	selectorLabels := map[string]string{
+		"app.kubernetes.io/component": "redis",
+		"app.kubernetes.io/name":      name,
+		"app.kubernetes.io/part-of":   "redis-failover",
+	}
+	ports := []corev1.ServicePort{
+		{
+			Name:       "redis",
+			Port:       6379,
+			TargetPort: intstr.FromInt(6379),
+			Protocol:   corev1.ProtocolTCP,
+		},
+	}
+
+	tests := []struct {
+		name             string
+		settings         redisfailoverv1.RedisRoleServiceSettings
+		expectedServices []corev1.Service
+	}{
+		{
+			name: "Default values",
+			expectedServices: []corev1.Service{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:            "rfrm-test",
+						Namespace:       namespace,
+						Labels:          selectorLabels,
+						OwnerReferences: []metav1.OwnerReference{{Name: "testing"}},
+					},
+					Spec: corev1.ServiceSpec{
+						Type: corev1.ServiceTypeClusterIP,
+						Selector: map[string]string{
+							"app.kubernetes.io/component": "redis",
+							"app.kubernetes.io/name":      name,
+							"app.kubernetes.io/part-of":   "redis-failover",
+							"redisfailovers-role":         "master",
+						},
+						Ports: ports,
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:            "rfrs-test",
+						Namespace:       namespace,
+						Labels:          selectorLabels,
+						OwnerReferences: []metav1.OwnerReference{{Name: "testing"}},
+					},
+					Spec: corev1.ServiceSpec{
+						Type: corev1.ServiceTypeClusterIP,
+						Selector: map[string]string{
+							"app.kubernetes.io/component": "redis",
+							"app.kubernetes.io/name":      name,
+							"app.kubernetes.io/part-of":   "redis-failover",
+							"redisfailovers-role":         "slave",
+						},
+						Ports: ports,
+					},
+				},
+			},
+		},
+		{
+			name: "Custom type and annotations",
+			settings: redisfailoverv1.RedisRoleServiceSettings{
+				Type: corev1.ServiceTypeLoadBalancer,
+				Annotations: map[string]string{
+					"service.beta.kubernetes.io/aws-load-balancer-internal": "true",
+				},
+			},
+			expectedServices: []corev1.Service{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:            "rfrm-test",
+						Namespace:       namespace,
+						Labels:          selectorLabels,
+						OwnerReferences: []metav1.OwnerReference{{Name: "testing"}},
+						Annotations: map[string]string{
+							"service.beta.kubernetes.io/aws-load-balancer-internal": "true",
+						},
+					},
+					Spec: corev1.ServiceSpec{
+						Type: corev1.ServiceTypeLoadBalancer,
+						Selector: map[string]string{
+							"app.kubernetes.io/component": "redis",
+							"app.kubernetes.io/name":      name,
+							"app.kubernetes.io/part-of":   "redis-failover",
+							"redisfailovers-role":         "master",
+						},
+						Ports: ports,
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:            "rfrs-test",
+						Namespace:       namespace,
+						Labels:          selectorLabels,
+						OwnerReferences: []metav1.OwnerReference{{Name: "testing"}},
+						Annotations: map[string]string{
+							"service.beta.kubernetes.io/aws-load-balancer-internal": "true",
+						},
+					},
+					Spec: corev1.ServiceSpec{
+						Type: corev1.ServiceTypeLoadBalancer,
+						Selector: map[string]string{
+							"app.kubernetes.io/component": "redis",
+							"app.kubernetes.io/name":      name,
+							"app.kubernetes.io/part-of":   "redis-failover",
+							"redisfailovers-role":         "slave",
+						},
+						Ports: ports,
+					},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateRF()
+			rf.Spec.Redis.Port = 6379
+			rf.Spec.Redis.MasterService = test.settings
+			rf.Spec.Redis.ReplicasService = test.settings
+
+			generatedServices := []corev1.Service{}
+
+			ms := &mK8SService.Services{}
+			ms.On("CreateOrUpdateService", mock.Anything, rf.Namespace, mock.Anything).Twice().Run(func(args mock.Arguments) {
+				s := args.Get(2).(*corev1.Service)
+				generatedServices = append(generatedServices, *s)
+			}).Return(nil)
+
+			client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+			assert.NoError(client.EnsureRedisMasterService(context.Background(), rf, nil, []metav1.OwnerReference{{Name: "testing"}}))
+			assert.NoError(client.EnsureRedisReplicasService(context.Background(), rf, nil, []metav1.OwnerReference{{Name: "testing"}}))
+
+			assert.Equal(test.expectedServices, generatedServices)
+		})
+	}
+}
+
 func TestRedisHostNetworkAndDnsPolicy(t *testing.T) {
 	tests := []struct {
 		name                string
@@ -1326,15 +1538,15 @@ func TestRedisHostNetworkAndDnsPolicy(t *testing.T) {
 		var actualDnsPolicy corev1.DNSPolicy
 
 		ms := &mK8SService.Services{}
-		ms.On("CreateOrUpdatePodDisruptionBudget", namespace, mock.Anything).Once().Return(nil, nil)
-		ms.On("CreateOrUpdateStatefulSet", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
-			ss := args.Get(1).(*appsv1.StatefulSet)
+		ms.On("CreateOrUpdatePodDisruptionBudget", mock.Anything, namespace, mock.Anything).Once().Return(nil, nil)
+		ms.On("CreateOrUpdateStatefulSet", mock.Anything, namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+			ss := args.Get(2).(*appsv1.StatefulSet)
 			actualHostNetwork = ss.Spec.Template.Spec.HostNetwork
 			actualDnsPolicy = ss.Spec.Template.Spec.DNSPolicy
 		}).Return(nil)
 
 		client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
-		err := client.EnsureRedisStatefulset(rf, nil, []metav1.OwnerReference{})
+		err := client.EnsureRedisStatefulset(context.Background(), rf, nil, []metav1.OwnerReference{})
 		assert.NoError(err)
 
 		assert.Equal(test.expectedHostNetwork, actualHostNetwork)
@@ -1375,15 +1587,15 @@ func TestSentinelHostNetworkAndDnsPolicy(t *testing.T) {
 		var actualDnsPolicy corev1.DNSPolicy
 
 		ms := &mK8SService.Services{}
-		ms.On("CreateOrUpdatePodDisruptionBudget", namespace, mock.Anything).Once().Return(nil, nil)
-		ms.On("CreateOrUpdateStatefulSet", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
-			d := args.Get(1).(*appsv1.StatefulSet)
+		ms.On("CreateOrUpdatePodDisruptionBudget", mock.Anything, namespace, mock.Anything).Once().Return(nil, nil)
+		ms.On("CreateOrUpdateStatefulSet", mock.Anything, namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+			d := args.Get(2).(*appsv1.StatefulSet)
 			actualHostNetwork = d.Spec.Template.Spec.HostNetwork
 			actualDnsPolicy = d.Spec.Template.Spec.DNSPolicy
 		}).Return(nil)
 
 		client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
-		err := client.EnsureSentinelStatefulset(rf, nil, []metav1.OwnerReference{})
+		err := client.EnsureSentinelStatefulset(context.Background(), rf, nil, []metav1.OwnerReference{})
 		assert.NoError(err)
 
 		assert.Equal(test.expectedHostNetwork, actualHostNetwork)
@@ -1425,15 +1637,15 @@ func TestRedisImagePullPolicy(t *testing.T) {
 		rf.Spec.Redis.Exporter.ImagePullPolicy = test.expectedExporterPolicy
 
 		ms := &mK8SService.Services{}
-		ms.On("CreateOrUpdatePodDisruptionBudget", namespace, mock.Anything).Once().Return(nil, nil)
-		ms.On("CreateOrUpdateStatefulSet", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
-			ss := args.Get(1).(*appsv1.StatefulSet)
+		ms.On("CreateOrUpdatePodDisruptionBudget", mock.Anything, namespace, mock.Anything).Once().Return(nil, nil)
+		ms.On("CreateOrUpdateStatefulSet", mock.Anything, namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+			ss := args.Get(2).(*appsv1.StatefulSet)
 			policy = ss.Spec.Template.Spec.Containers[0].ImagePullPolicy
 			exporterPolicy = ss.Spec.Template.Spec.Containers[1].ImagePullPolicy
 		}).Return(nil)
 
 		client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
-		err := client.EnsureRedisStatefulset(rf, nil, []metav1.OwnerReference{})
+		err := client.EnsureRedisStatefulset(context.Background(), rf, nil, []metav1.OwnerReference{})
 
 		assert.NoError(err)
 		assert.Equal(string(test.expectedPolicy), string(policy))
@@ -1471,15 +1683,15 @@ func TestSentinelImagePullPolicy(t *testing.T) {
 		rf.Spec.Sentinel.ImagePullPolicy = test.policy
 
 		ms := &mK8SService.Services{}
-		ms.On("CreateOrUpdatePodDisruptionBudget", namespace, mock.Anything).Once().Return(nil, nil)
-		ms.On("CreateOrUpdateStatefulSet", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
-			d := args.Get(1).(*appsv1.StatefulSet)
+		ms.On("CreateOrUpdatePodDisruptionBudget", mock.Anything, namespace, mock.Anything).Once().Return(nil, nil)
+		ms.On("CreateOrUpdateStatefulSet", mock.Anything, namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+			d := args.Get(2).(*appsv1.StatefulSet)
 			policy = d.Spec.Template.Spec.Containers[0].ImagePullPolicy
 			configPolicy = d.Spec.Template.Spec.InitContainers[0].ImagePullPolicy
 		}).Return(nil)
 
 		client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
-		err := client.EnsureSentinelStatefulset(rf, nil, []metav1.OwnerReference{})
+		err := client.EnsureSentinelStatefulset(context.Background(), rf, nil, []metav1.OwnerReference{})
 
 		assert.NoError(err)
 		assert.Equal(string(test.expectedPolicy), string(policy))
@@ -1546,15 +1758,15 @@ func TestRedisExtraVolumeMounts(t *testing.T) {
 		rf.Spec.Redis.ExtraVolumeMounts = test.expectedVolumeMounts
 
 		ms := &mK8SService.Services{}
-		ms.On("CreateOrUpdatePodDisruptionBudget", namespace, mock.Anything).Once().Return(nil, nil)
-		ms.On("CreateOrUpdateStatefulSet", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
-			s := args.Get(1).(*appsv1.StatefulSet)
+		ms.On("CreateOrUpdatePodDisruptionBudget", mock.Anything, namespace, mock.Anything).Once().Return(nil, nil)
+		ms.On("CreateOrUpdateStatefulSet", mock.Anything, namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+			s := args.Get(2).(*appsv1.StatefulSet)
 			extraVolume = s.Spec.Template.Spec.Volumes[3]
 			extraVolumeMount = s.Spec.Template.Spec.Containers[0].VolumeMounts[4]
 		}).Return(nil)
 
 		client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
-		err := client.EnsureRedisStatefulset(rf, nil, []metav1.OwnerReference{})
+		err := client.EnsureRedisStatefulset(context.Background(), rf, nil, []metav1.OwnerReference{})
 
 		assert.NoError(err)
 		assert.Equal(test.expectedVolumes[0], extraVolume)
@@ -1621,15 +1833,15 @@ func TestSentinelExtraVolumeMounts(t *testing.T) {
 		rf.Spec.Sentinel.ExtraVolumeMounts = test.expectedVolumeMounts
 
 		ms := &mK8SService.Services{}
-		ms.On("CreateOrUpdatePodDisruptionBudget", namespace, mock.Anything).Once().Return(nil, nil)
-		ms.On("CreateOrUpdateStatefulSet", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
-			d := args.Get(1).(*appsv1.StatefulSet)
+		ms.On("CreateOrUpdatePodDisruptionBudget", mock.Anything, namespace, mock.Anything).Once().Return(nil, nil)
+		ms.On("CreateOrUpdateStatefulSet", mock.Anything, namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+			d := args.Get(2).(*appsv1.StatefulSet)
 			extraVolume = d.Spec.Template.Spec.Volumes[2]
 			extraVolumeMount = d.Spec.Template.Spec.Containers[0].VolumeMounts[1]
 		}).Return(nil)
 
 		client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
-		err := client.EnsureSentinelStatefulset(rf, nil, []metav1.OwnerReference{})
+		err := client.EnsureSentinelStatefulset(context.Background(), rf, nil, []metav1.OwnerReference{})
 
 		assert.NoError(err)
 		assert.Equal(test.expectedVolumes[0], extraVolume)
@@ -1678,14 +1890,14 @@ func TestCustomPort(t *testing.T) {
 		rf.Spec.Redis.Port = test.port
 
 		ms := &mK8SService.Services{}
-		ms.On("CreateOrUpdatePodDisruptionBudget", namespace, mock.Anything).Once().Return(nil, nil)
-		ms.On("CreateOrUpdateStatefulSet", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
-			s := args.Get(1).(*appsv1.StatefulSet)
+		ms.On("CreateOrUpdatePodDisruptionBudget", mock.Anything, namespace, mock.Anything).Once().Return(nil, nil)
+		ms.On("CreateOrUpdateStatefulSet", mock.Anything, namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+			s := args.Get(2).(*appsv1.StatefulSet)
 			port = s.Spec.Template.Spec.Containers[0].Ports[0]
 		}).Return(nil)
 
 		client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
-		err := client.EnsureRedisStatefulset(rf, nil, []metav1.OwnerReference{})
+		err := client.EnsureRedisStatefulset(context.Background(), rf, nil, []metav1.OwnerReference{})
 
 		assert.NoError(err)
 		assert.Equal(test.expectedContainerPort[0], port)
@@ -1695,9 +1907,10 @@ func TestCustomPort(t *testing.T) {
 func TestRedisEnv(t *testing.T) {
 	default_port := int32(6379)
 	tests := []struct {
-		name             string
-		auth             string
-		expectedRedisEnv []corev1.EnvVar
+		name              string
+		auth              string
+		announceHostnames bool
+		expectedRedisEnv  []corev1.EnvVar
 	}{
 		{
 			name: "without auth",
@@ -1746,6 +1959,36 @@ func TestRedisEnv(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:              "with announced hostnames",
+			announceHostnames: true,
+			expectedRedisEnv: []corev1.EnvVar{
+				{
+					Name: "POD_NAME",
+					ValueFrom: &corev1.EnvVarSource{
+						FieldRef: &corev1.ObjectFieldSelector{
+							FieldPath: "metadata.name",
+						},
+					},
+				},
+				{
+					Name:  "REDIS_ANNOUNCE_HOSTNAME",
+					Value: "$(POD_NAME).rfr-test.testns.svc",
+				},
+				{
+					Name:  "REDIS_ADDR",
+					Value: fmt.Sprintf("redis://127.0.0.1:%[1]v", default_port),
+				},
+				{
+					Name:  "REDIS_PORT",
+					Value: fmt.Sprintf("%[1]v", default_port),
+				},
+				{
+					Name:  "REDIS_USER",
+					Value: "default",
+				},
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -1758,22 +2001,72 @@ func TestRedisEnv(t *testing.T) {
 		if test.auth != "" {
 			rf.Spec.Auth.SecretPath = test.auth
 		}
+		rf.Spec.AnnounceHostnames = test.announceHostnames
 
 		ms := &mK8SService.Services{}
-		ms.On("CreateOrUpdatePodDisruptionBudget", namespace, mock.Anything).Once().Return(nil, nil)
-		ms.On("CreateOrUpdateStatefulSet", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
-			s := args.Get(1).(*appsv1.StatefulSet)
+		ms.On("GetSecret", mock.Anything, namespace, test.auth).Maybe().Return(&corev1.Secret{Data: map[string][]byte{"password": []byte("password")}}, nil)
+		ms.On("CreateOrUpdatePodDisruptionBudget", mock.Anything, namespace, mock.Anything).Once().Return(nil, nil)
+		ms.On("CreateOrUpdateStatefulSet", mock.Anything, namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+			s := args.Get(2).(*appsv1.StatefulSet)
 			env = s.Spec.Template.Spec.Containers[0].Env
 		}).Return(nil)
 
 		client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
-		err := client.EnsureRedisStatefulset(rf, nil, []metav1.OwnerReference{})
+		err := client.EnsureRedisStatefulset(context.Background(), rf, nil, []metav1.OwnerReference{})
 
 		assert.NoError(err)
 		assert.Equal(test.expectedRedisEnv, env)
 	}
 }
 
+func TestAnnounceHostnames(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF()
+	rf.Spec.AnnounceHostnames = true
+
+	configMaps := map[string]*corev1.ConfigMap{}
+	statefulSets := map[string]*appsv1.StatefulSet{}
+	var redisService *corev1.Service
+
+	ms := &mK8SService.Services{}
+	ms.On("CreateOrUpdateConfigMap", mock.Anything, namespace, mock.Anything).Run(func(args mock.Arguments) {
+		cm := args.Get(2).(*corev1.ConfigMap)
+		configMaps[cm.Name] = cm
+	}).Return(nil)
+	ms.On("CreateOrUpdatePodDisruptionBudget", mock.Anything, namespace, mock.Anything).Return(nil, nil)
+	ms.On("CreateOrUpdateStatefulSet", mock.Anything, namespace, mock.Anything).Run(func(args mock.Arguments) {
+		ss := args.Get(2).(*appsv1.StatefulSet)
+		statefulSets[ss.Name] = ss
+	}).Return(nil)
+	ms.On("CreateOrUpdateService", mock.Anything, namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+		redisService = args.Get(2).(*corev1.Service)
+	}).Return(nil)
+
+	client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+	assert.NoError(client.EnsureSentinelConfigMap(context.Background(), rf, nil, []metav1.OwnerReference{}))
+	assert.NoError(client.EnsureRedisShutdownConfigMap(context.Background(), rf, nil, []metav1.OwnerReference{}))
+	assert.NoError(client.EnsureRedisStatefulset(context.Background(), rf, nil, []metav1.OwnerReference{}))
+	assert.NoError(client.EnsureSentinelStatefulset(context.Background(), rf, nil, []metav1.OwnerReference{}))
+	assert.NoError(client.EnsureRedisService(context.Background(), rf, nil, []metav1.OwnerReference{}))
+
+	sentinelConfig := configMaps[rfservice.GetSentinelName(rf)].Data["sentinel.conf"]
+	assert.True(strings.HasPrefix(sentinelConfig, "sentinel resolve-hostnames yes\nsentinel announce-hostnames yes\nport 26379\nsentinel monitor master0 127.0.0.1 0 2\n"))
+
+	shutdown := configMaps[rfservice.GetRedisShutdownConfigMapName(rf)].Data["shutdown.sh"]
+	assert.Contains(shutdown, `if [ "$master" = "${REDIS_ANNOUNCE_HOSTNAME}" ]; then`)
+
+	redis := statefulSets[rfservice.GetRedisName(rf)].Spec.Template.Spec.Containers[0]
+	assert.Equal([]string{"/bin/sh", "-c", "sleep 15 && redis-server /redis/redis.conf --replica-announce-ip ${REDIS_ANNOUNCE_HOSTNAME}"}, redis.Command)
+
+	configCopy := statefulSets[rfservice.GetSentinelName(rf)].Spec.Template.Spec.InitContainers[0]
+	assert.Equal([]string{"/bin/sh", "-c", `cp /redis/sentinel.conf /redis-writable/sentinel.conf && echo "sentinel announce-ip ${SENTINEL_ANNOUNCE_HOSTNAME}" >> /redis-writable/sentinel.conf`}, configCopy.Command)
+	assert.Contains(configCopy.Env, corev1.EnvVar{Name: "SENTINEL_ANNOUNCE_HOSTNAME", Value: "$(POD_NAME).rfs-test.testns.svc"})
+
+	assert.True(redisService.Spec.PublishNotReadyAddresses)
+	assert.Equal("redis", redisService.Spec.Ports[0].Name)
+}
+
 func TestRedisStartupProbe(t *testing.T) {
 	mode := int32(0744)
 	tests := []struct {
@@ -1811,15 +2104,15 @@ func TestRedisStartupProbe(t *testing.T) {
 		rf.Spec.Redis.StartupConfigMap = test.name
 
 		ms := &mK8SService.Services{}
-		ms.On("CreateOrUpdatePodDisruptionBudget", namespace, mock.Anything).Once().Return(nil, nil)
-		ms.On("CreateOrUpdateStatefulSet", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
-			s := args.Get(1).(*appsv1.StatefulSet)
+		ms.On("CreateOrUpdatePodDisruptionBudget", mock.Anything, namespace, mock.Anything).Once().Return(nil, nil)
+		ms.On("CreateOrUpdateStatefulSet", mock.Anything, namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+			s := args.Get(2).(*appsv1.StatefulSet)
 			startupVolumes = s.Spec.Template.Spec.Volumes
 			startupVolumeMounts = s.Spec.Template.Spec.Containers[0].VolumeMounts
 		}).Return(nil)
 
 		client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
-		err := client.EnsureRedisStatefulset(rf, nil, []metav1.OwnerReference{})
+		err := client.EnsureRedisStatefulset(context.Background(), rf, nil, []metav1.OwnerReference{})
 
 		assert.NoError(err)
 		assert.Contains(startupVolumes, test.expectedVolume)
@@ -1864,18 +2157,755 @@ func TestSentinelStartupProbe(t *testing.T) {
 		rf.Spec.Sentinel.StartupConfigMap = test.name
 
 		ms := &mK8SService.Services{}
-		ms.On("CreateOrUpdatePodDisruptionBudget", namespace, mock.Anything).Once().Return(nil, nil)
-		ms.On("CreateOrUpdateStatefulSet", namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
-			d := args.Get(1).(*appsv1.StatefulSet)
+		ms.On("CreateOrUpdatePodDisruptionBudget", mock.Anything, namespace, mock.Anything).Once().Return(nil, nil)
+		ms.On("CreateOrUpdateStatefulSet", mock.Anything, namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+			d := args.Get(2).(*appsv1.StatefulSet)
 			startupVolumes = d.Spec.Template.Spec.Volumes
 			startupVolumeMounts = d.Spec.Template.Spec.Containers[0].VolumeMounts
 		}).Return(nil)
 
 		client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
-		err := client.EnsureSentinelStatefulset(rf, nil, []metav1.OwnerReference{})
+		err := client.EnsureSentinelStatefulset(context.Background(), rf, nil, []metav1.OwnerReference{})
 
 		assert.NoError(err)
 		assert.Contains(startupVolumes, test.expectedVolume)
 		assert.Contains(startupVolumeMounts, test.expectedVolumeMount)
 	}
 }
+
+func TestEnvoyConfigMap(t *testing.T) {
+	tests := []struct {
+		name             string
+		backends         rfservice.ProxyBackends
+		expectedClusters string
+	}{
+		{
+			name: "Master and replicas",
+			backends: rfservice.ProxyBackends{
+				Master:   "10.0.0.1",
+				Replicas: []string{"10.0.0.2", "10.0.0.3"},
+			},
+			expectedClusters: `resources:
+- "@type": type.googleapis.com/envoy.config.cluster.v3.Cluster
+  name: redis_master
+  connect_timeout: 1s
+  type: STATIC
+  load_assignment:
+    cluster_name: redis_master
+    endpoints:
+    - lb_endpoints:
+      - endpoint:
+          address:
+            socket_address:
+              address: 10.0.0.1
+              port_value: 6379
+- "@type": type.googleapis.com/envoy.config.cluster.v3.Cluster
+  name: redis_replicas
+  connect_timeout: 1s
+  type: STATIC
+  load_assignment:
+    cluster_name: redis_replicas
+    endpoints:
+    - lb_endpoints:
+      - endpoint:
+          address:
+            socket_address:
+              address: 10.0.0.2
+              port_value: 6379
+      - endpoint:
+          address:
+            socket_address:
+              address: 10.0.0.3
+              port_value: 6379`,
+		},
+		{
+			name: "Reads go to the master without replicas",
+			backends: rfservice.ProxyBackends{
+				Master: "10.0.0.1",
+			},
+			expectedClusters: `resources:
+- "@type": type.googleapis.com/envoy.config.cluster.v3.Cluster
+  name: redis_master
+  connect_timeout: 1s
+  type: STATIC
+  load_assignment:
+    cluster_name: redis_master
+    endpoints:
+    - lb_endpoints:
+      - endpoint:
+          address:
+            socket_address:
+              address: 10.0.0.1
+              port_value: 6379
+- "@type": type.googleapis.com/envoy.config.cluster.v3.Cluster
+  name: redis_replicas
+  connect_timeout: 1s
+  type: STATIC
+  load_assignment:
+    cluster_name: redis_replicas
+    endpoints:
+    - lb_endpoints:
+      - endpoint:
+          address:
+            socket_address:
+              address: 10.0.0.1
+              port_value: 6379`,
+		},
+		{
+			name:     "No master known yet",
+			backends: rfservice.ProxyBackends{},
+			expectedClusters: `resources:
+- "@type": type.googleapis.com/envoy.config.cluster.v3.Cluster
+  name: redis_master
+  connect_timeout: 1s
+  type: STATIC
+  load_assignment:
+    cluster_name: redis_master
+    endpoints:
+    - lb_endpoints: []
+- "@type": type.googleapis.com/envoy.config.cluster.v3.Cluster
+  name: redis_replicas
+  connect_timeout: 1s
+  type: STATIC
+  load_assignment:
+    cluster_name: redis_replicas
+    endpoints:
+    - lb_endpoints: []`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateRF()
+			rf.Spec.Redis.Port = 6379
+			rf.Spec.Proxy.Type = redisfailoverv1.ProxyTypeEnvoy
+
+			generatedConfigMap := corev1.ConfigMap{}
+
+			ms := &mK8SService.Services{}
+			ms.On("CreateOrUpdateConfigMap", mock.Anything, namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+				cm := args.Get(2).(*corev1.ConfigMap)
+				generatedConfigMap = *cm
+			}).Return(nil)
+
+			client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+			err := client.EnsureProxyConfigMap(context.Background(), rf, nil, []metav1.OwnerReference{}, test.backends)
+
+			assert.NoError(err)
+			assert.Equal(rfservice.GetEnvoyName(rf), generatedConfigMap.Name)
+			assert.Equal(test.expectedClusters, generatedConfigMap.Data["cds.yaml"])
+			assert.Contains(generatedConfigMap.Data["envoy.yaml"], "cluster: redis_master")
+			assert.Contains(generatedConfigMap.Data["envoy.yaml"], "cluster: redis_replicas")
+		})
+	}
+}
+
+func TestPredixyConfigMapSentinels(t *testing.T) {
+	tests := []struct {
+		name              string
+		sentinelReplicas  int32
+		expectedSentinels string
+	}{
+		{
+			name:             "Default sentinels",
+			sentinelReplicas: 3,
+			expectedSentinels: `    Sentinels {
+        + rfs-test-0.rfs-test.testns.svc:26379
+        + rfs-test-1.rfs-test.testns.svc:26379
+        + rfs-test-2.rfs-test.testns.svc:26379
+    }`,
+		},
+		{
+			name:             "Scaled sentinels",
+			sentinelReplicas: 1,
+			expectedSentinels: `    Sentinels {
+        + rfs-test-0.rfs-test.testns.svc:26379
+    }`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateRF()
+			rf.Spec.Sentinel.Replicas = test.sentinelReplicas
+
+			generatedConfigMap := corev1.ConfigMap{}
+
+			ms := &mK8SService.Services{}
+			ms.On("CreateOrUpdateConfigMap", mock.Anything, namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+				cm := args.Get(2).(*corev1.ConfigMap)
+				generatedConfigMap = *cm
+			}).Return(nil)
+
+			client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+			err := client.EnsureProxyConfigMap(context.Background(), rf, nil, []metav1.OwnerReference{}, rfservice.ProxyBackends{})
+
+			assert.NoError(err)
+			assert.Contains(generatedConfigMap.Data["sentinel.conf"], test.expectedSentinels)
+		})
+	}
+}
+
+func TestProxyDeploymentConfigHash(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF()
+	hashes := []string{}
+	for _, sentinels := range []string{"+ rfs-test-0", "+ rfs-test-0\n+ rfs-test-1", "+ rfs-test-0\n+ rfs-test-1"} {
+		cm := &corev1.ConfigMap{
+			Data: map[string]string{
+				"predixy.conf":  "Include sentinel.conf",
+				"sentinel.conf": sentinels,
+				"auth.conf":     "",
+			},
+		}
+
+		ms := &mK8SService.Services{}
+		ms.On("CreateOrUpdatePodDisruptionBudget", mock.Anything, namespace, mock.Anything).Once().Return(nil)
+		ms.On("GetConfigMap", mock.Anything, namespace, rfservice.GetPredixyName(rf)).Once().Return(cm, nil)
+		ms.On("GetDeployment", mock.Anything, namespace, rfservice.GetPredixyName(rf)).Once().Return(nil, fmt.Errorf("not found"))
+		ms.On("CreateOrUpdateDeployment", mock.Anything, namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+			d := args.Get(2).(*appsv1.Deployment)
+			hashes = append(hashes, d.Spec.Template.Annotations["redisfailovers.databases.spotahome.com/config-hash"])
+		}).Return(nil)
+
+		client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+		err := client.EnsureProxyDeployment(context.Background(), rf, nil, []metav1.OwnerReference{})
+
+		assert.NoError(err)
+		ms.AssertExpectations(t)
+	}
+
+	assert.NotEmpty(hashes[0])
+	assert.NotEqual(hashes[0], hashes[1], "sentinel membership change must roll the proxy")
+	assert.Equal(hashes[1], hashes[2], "unchanged configuration must not roll the proxy")
+}
+
+func TestEnvoyDeploymentConfigHash(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF()
+	rf.Spec.Proxy.Type = redisfailoverv1.ProxyTypeEnvoy
+	hashes := []string{}
+	for _, backends := range []rfservice.ProxyBackends{
+		{Master: "0.0.0.0", Replicas: []string{"1.1.1.1"}},
+		{Master: "0.0.0.0", Replicas: []string{"2.2.2.2"}},
+		{Master: "2.2.2.2", Replicas: []string{"0.0.0.0"}},
+	} {
+		var cm *corev1.ConfigMap
+		ms := &mK8SService.Services{}
+		ms.On("CreateOrUpdateConfigMap", mock.Anything, namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+			cm = args.Get(2).(*corev1.ConfigMap)
+		}).Return(nil)
+		ms.On("CreateOrUpdatePodDisruptionBudget", mock.Anything, namespace, mock.Anything).Once().Return(nil)
+		ms.On("GetConfigMap", mock.Anything, namespace, rfservice.GetEnvoyName(rf)).Once().Return(func(context.Context, string, string) *corev1.ConfigMap { return cm }, nil)
+		ms.On("GetDeployment", mock.Anything, namespace, rfservice.GetEnvoyName(rf)).Once().Return(nil, fmt.Errorf("not found"))
+		ms.On("CreateOrUpdateDeployment", mock.Anything, namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+			d := args.Get(2).(*appsv1.Deployment)
+			hashes = append(hashes, d.Spec.Template.Annotations["redisfailovers.databases.spotahome.com/config-hash"])
+		}).Return(nil)
+
+		client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+		assert.NoError(client.EnsureProxyConfigMap(context.Background(), rf, nil, []metav1.OwnerReference{}, backends))
+		assert.NoError(client.EnsureProxyDeployment(context.Background(), rf, nil, []metav1.OwnerReference{}))
+		ms.AssertExpectations(t)
+	}
+
+	assert.Equal(hashes[0], hashes[1], "the replicas are reloaded by envoy, they must not roll it")
+	assert.NotEqual(hashes[1], hashes[2], "a failover must roll envoy")
+}
+
+func TestRedisStatefulSetPasswordHash(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF()
+	rf.Spec.Auth.SecretPath = "redis-auth"
+	hashes := []string{}
+	for _, secret := range []map[string][]byte{
+		{"password": []byte("old")},
+		{"password": []byte("new"), "previousPassword": []byte("old")},
+		{"password": []byte("new")},
+	} {
+		ms := &mK8SService.Services{}
+		ms.On("CreateOrUpdatePodDisruptionBudget", mock.Anything, namespace, mock.Anything).Once().Return(nil)
+		ms.On("GetSecret", mock.Anything, namespace, "redis-auth").Return(&corev1.Secret{Data: secret}, nil)
+		if len(hashes) > 0 {
+			running := &appsv1.StatefulSet{}
+			running.Spec.Template.Annotations = map[string]string{"redisfailovers.databases.spotahome.com/password-hash": hashes[len(hashes)-1]}
+			ms.On("GetStatefulSet", mock.Anything, namespace, rfservice.GetRedisName(rf)).Return(running, nil)
+		}
+		ms.On("CreateOrUpdateStatefulSet", mock.Anything, namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+			ss := args.Get(2).(*appsv1.StatefulSet)
+			hashes = append(hashes, ss.Spec.Template.Annotations["redisfailovers.databases.spotahome.com/password-hash"])
+		}).Return(nil)
+
+		client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+		assert.NoError(client.EnsureRedisStatefulset(context.Background(), rf, nil, []metav1.OwnerReference{}))
+	}
+
+	assert.NotEmpty(hashes[0])
+	assert.NotContains(hashes[0], "old", "the password must not be exposed")
+	assert.Equal(hashes[0], hashes[1], "the pods must not roll while a rotation is in progress")
+	assert.NotEqual(hashes[1], hashes[2], "the pods must roll once the previous password is dropped")
+}
+
+func TestExternalAccessServices(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF()
+	rf.Spec.Redis.Port = 6379
+	rf.Spec.Redis.Replicas = 2
+	rf.Spec.Sentinel.Replicas = 1
+	rf.Spec.ExternalAccess = &redisfailoverv1.ExternalAccessSettings{
+		Type:        corev1.ServiceTypeNodePort,
+		HostAddress: "redis.example.com",
+		Annotations: map[string]string{
+			"external-dns.alpha.kubernetes.io/hostname": "redis.example.com",
+		},
+	}
+
+	stale := corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "rfr-test-2",
+			Labels: map[string]string{
+				"app.kubernetes.io/name":                                 name,
+				"redisfailovers.databases.spotahome.com/external-access": "true",
+			},
+		},
+	}
+	unrelated := corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "rfr-test",
+			Labels: map[string]string{
+				"app.kubernetes.io/name": name,
+			},
+		},
+	}
+
+	generatedServices := []corev1.Service{}
+	ms := &mK8SService.Services{}
+	ms.On("CreateOrUpdateService", mock.Anything, namespace, mock.Anything).Times(3).Run(func(args mock.Arguments) {
+		s := args.Get(2).(*corev1.Service)
+		generatedServices = append(generatedServices, *s)
+	}).Return(nil)
+	ms.On("ListServices", mock.Anything, namespace).Once().Return(&corev1.ServiceList{Items: []corev1.Service{stale, unrelated}}, nil)
+	ms.On("DeleteService", mock.Anything, namespace, "rfr-test-2").Once().Return(nil)
+
+	client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+	assert.NoError(client.EnsureExternalAccessServices(context.Background(), rf, nil, []metav1.OwnerReference{{Name: "testing"}}))
+	ms.AssertExpectations(t)
+
+	names := []string{}
+	for _, svc := range generatedServices {
+		names = append(names, svc.Name)
+	}
+	assert.Equal([]string{"rfr-test-0", "rfr-test-1", "rfs-test-0"}, names)
+
+	assert.Equal(corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "rfr-test-0",
+			Namespace: namespace,
+			Labels: map[string]string{
+				"app.kubernetes.io/component":                            "redis",
+				"app.kubernetes.io/name":                                 name,
+				"app.kubernetes.io/part-of":                              "redis-failover",
+				"redisfailovers.databases.spotahome.com/external-access": "true",
+			},
+			OwnerReferences: []metav1.OwnerReference{{Name: "testing"}},
+			Annotations: map[string]string{
+				"external-dns.alpha.kubernetes.io/hostname": "redis.example.com",
+			},
+		},
+		Spec: corev1.ServiceSpec{
+			Type:                     corev1.ServiceTypeNodePort,
+			PublishNotReadyAddresses: true,
+			Selector: map[string]string{
+				"statefulset.kubernetes.io/pod-name": "rfr-test-0",
+			},
+			Ports: []corev1.ServicePort{
+				{
+					Name:       "redis",
+					Port:       6379,
+					TargetPort: intstr.FromInt(6379),
+					Protocol:   corev1.ProtocolTCP,
+				},
+			},
+		},
+	}, generatedServices[0])
+	assert.Equal(int32(26379), generatedServices[2].Spec.Ports[0].Port)
+}
+
+func TestExternalAccessServicesDisabled(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF()
+
+	stale := corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "rfs-test-0",
+			Labels: map[string]string{
+				"app.kubernetes.io/name":                                 name,
+				"redisfailovers.databases.spotahome.com/external-access": "true",
+			},
+		},
+	}
+
+	ms := &mK8SService.Services{}
+	ms.On("ListServices", mock.Anything, namespace).Once().Return(&corev1.ServiceList{Items: []corev1.Service{stale}}, nil)
+	ms.On("DeleteService", mock.Anything, namespace, "rfs-test-0").Once().Return(nil)
+
+	client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+	assert.NoError(client.EnsureExternalAccessServices(context.Background(), rf, nil, nil))
+	ms.AssertExpectations(t)
+	ms.AssertNotCalled(t, "CreateOrUpdateService", mock.Anything, mock.Anything)
+}
+
+func TestRedisBootstrapTLS(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF()
+	rf.Spec.BootstrapNode = &redisfailoverv1.BootstrapSettings{
+		Host: "10.0.0.1",
+		Port: "6379",
+		TLS:  &redisfailoverv1.BootstrapTLSSettings{SecretName: "external-tls"},
+	}
+
+	var configMap *corev1.ConfigMap
+	var statefulSet *appsv1.StatefulSet
+	ms := &mK8SService.Services{}
+	ms.On("CreateOrUpdateConfigMap", mock.Anything, namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+		configMap = args.Get(2).(*corev1.ConfigMap)
+	}).Return(nil)
+	ms.On("CreateOrUpdatePodDisruptionBudget", mock.Anything, namespace, mock.Anything).Once().Return(nil, nil)
+	ms.On("CreateOrUpdateStatefulSet", mock.Anything, namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+		statefulSet = args.Get(2).(*appsv1.StatefulSet)
+	}).Return(nil)
+
+	client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+	assert.NoError(client.EnsureRedisConfigMap(context.Background(), rf, nil, []metav1.OwnerReference{}))
+	assert.NoError(client.EnsureRedisStatefulset(context.Background(), rf, nil, []metav1.OwnerReference{}))
+
+	assert.Contains(configMap.Data["redis.conf"], "tls-replication yes\ntls-cert-file /redis-bootstrap-tls/tls.crt")
+	assert.Contains(statefulSet.Spec.Template.Spec.Volumes, corev1.Volume{
+		Name: "redis-bootstrap-tls",
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{SecretName: "external-tls"},
+		},
+	})
+	assert.Contains(statefulSet.Spec.Template.Spec.Containers[0].VolumeMounts, corev1.VolumeMount{
+		Name:      "redis-bootstrap-tls",
+		MountPath: "/redis-bootstrap-tls",
+		ReadOnly:  true,
+	})
+}
+
+func TestSentinelPortMasterNameAndAuth(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF()
+	rf.Spec.Sentinel.Port = 26380
+	rf.Spec.Sentinel.MasterName = "mymaster"
+	rf.Spec.Sentinel.Auth.SecretPath = "sentinel-auth"
+	rf.Spec.Sentinel.Exporter.Enabled = true
+
+	configMaps := map[string]*corev1.ConfigMap{}
+	statefulSets := map[string]*appsv1.StatefulSet{}
+	var sentinelService *corev1.Service
+
+	ms := &mK8SService.Services{}
+	ms.On("GetSecret", mock.Anything, namespace, "sentinel-auth").Return(&corev1.Secret{
+		Data: map[string][]byte{"password": []byte("sentinelpass")},
+	}, nil)
+	ms.On("CreateOrUpdateConfigMap", mock.Anything, namespace, mock.Anything).Run(func(args mock.Arguments) {
+		cm := args.Get(2).(*corev1.ConfigMap)
+		configMaps[cm.Name] = cm
+	}).Return(nil)
+	ms.On("CreateOrUpdatePodDisruptionBudget", mock.Anything, namespace, mock.Anything).Return(nil, nil)
+	ms.On("CreateOrUpdateStatefulSet", mock.Anything, namespace, mock.Anything).Run(func(args mock.Arguments) {
+		ss := args.Get(2).(*appsv1.StatefulSet)
+		statefulSets[ss.Name] = ss
+	}).Return(nil)
+	ms.On("CreateOrUpdateService", mock.Anything, namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+		sentinelService = args.Get(2).(*corev1.Service)
+	}).Return(nil)
+
+	client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+	assert.NoError(client.EnsureSentinelConfigMap(context.Background(), rf, nil, []metav1.OwnerReference{}))
+	assert.NoError(client.EnsureRedisShutdownConfigMap(context.Background(), rf, nil, []metav1.OwnerReference{}))
+	assert.NoError(client.EnsureRedisStatefulset(context.Background(), rf, nil, []metav1.OwnerReference{}))
+	assert.NoError(client.EnsureSentinelStatefulset(context.Background(), rf, nil, []metav1.OwnerReference{}))
+	assert.NoError(client.EnsureSentinelService(context.Background(), rf, nil, []metav1.OwnerReference{}))
+	assert.NoError(client.EnsureProxyConfigMap(context.Background(), rf, nil, []metav1.OwnerReference{}, rfservice.ProxyBackends{}))
+
+	passwordEnv := func(name string) corev1.EnvVar {
+		return corev1.EnvVar{
+			Name: name,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "sentinel-auth"},
+					Key:                  "password",
+				},
+			},
+		}
+	}
+
+	sentinelConfig := configMaps[rfservice.GetSentinelName(rf)].Data["sentinel.conf"]
+	assert.True(strings.HasPrefix(sentinelConfig, "port 26380\nsentinel monitor mymaster 127.0.0.1 0 2\nsentinel down-after-milliseconds mymaster 5000\n"))
+	assert.True(strings.HasSuffix(sentinelConfig, "\nrequirepass sentinelpass\nsentinel sentinel-pass sentinelpass"))
+
+	shutdown := configMaps[rfservice.GetRedisShutdownConfigMapName(rf)].Data["shutdown.sh"]
+	assert.Contains(shutdown, "master=$(REDISCLI_AUTH=${SENTINEL_PASSWORD} redis-cli -h rfs-test.testns.svc -p 26380 --csv SENTINEL get-master-addr-by-name mymaster")
+	assert.Contains(shutdown, "  REDISCLI_AUTH=${SENTINEL_PASSWORD} redis-cli -h rfs-test.testns.svc -p 26380 SENTINEL failover mymaster\n")
+
+	redis := statefulSets[rfservice.GetRedisName(rf)].Spec.Template.Spec.Containers[0]
+	assert.Contains(redis.Env, passwordEnv("SENTINEL_PASSWORD"))
+
+	sentinelPod := statefulSets[rfservice.GetSentinelName(rf)].Spec.Template.Spec
+	sentinel := sentinelPod.Containers[0]
+	assert.Equal(int32(26380), sentinel.Ports[0].ContainerPort)
+	assert.Equal([]string{"sh", "-c", "redis-cli -h $(hostname) -p 26380 ping"}, sentinel.ReadinessProbe.Exec.Command)
+	assert.Equal([]string{"sh", "-c", "redis-cli -h $(hostname) -p 26380 ping"}, sentinel.LivenessProbe.Exec.Command)
+	assert.Contains(sentinel.Env, passwordEnv("REDISCLI_AUTH"))
+	exporter := sentinelPod.Containers[1]
+	assert.Contains(exporter.Env, corev1.EnvVar{Name: "REDIS_ADDR", Value: "redis://127.0.0.1:26380"})
+	assert.Contains(exporter.Env, passwordEnv("REDIS_PASSWORD"))
+
+	assert.Equal(int32(26380), sentinelService.Spec.Ports[0].Port)
+	assert.Equal(intstr.FromInt(26380), sentinelService.Spec.Ports[0].TargetPort)
+
+	predixy := configMaps[rfservice.GetPredixyName(rf)].Data["sentinel.conf"]
+	assert.Contains(predixy, "    SentinelPassword sentinelpass\n")
+	assert.Contains(predixy, "        + rfs-test-0.rfs-test.testns.svc:26380\n")
+	assert.Contains(predixy, "    Group mymaster {\n")
+}
+
+func TestRedisPasswordRotation(t *testing.T) {
+	tests := []struct {
+		name             string
+		previousPassword string
+		expConfigSuffix  string
+	}{
+		{
+			name:            "no rotation",
+			expConfigSuffix: "\nrequirepass newpass",
+		},
+		{
+			name:             "previous password still accepted",
+			previousPassword: "oldpass",
+			expConfigSuffix:  "\nuser default on >newpass >oldpass ~* &* +@all",
+		},
+		{
+			name:             "previous password equal to the new one",
+			previousPassword: "newpass",
+			expConfigSuffix:  "\nrequirepass newpass",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateRF()
+			rf.Spec.Auth.SecretPath = "redis-auth"
+
+			data := map[string][]byte{"password": []byte("newpass")}
+			if test.previousPassword != "" {
+				data["previousPassword"] = []byte(test.previousPassword)
+			}
+
+			configMaps := map[string]*corev1.ConfigMap{}
+			var statefulSet *appsv1.StatefulSet
+
+			ms := &mK8SService.Services{}
+			ms.On("GetSecret", mock.Anything, namespace, "redis-auth").Return(&corev1.Secret{Data: data}, nil)
+			ms.On("CreateOrUpdateConfigMap", mock.Anything, namespace, mock.Anything).Run(func(args mock.Arguments) {
+				cm := args.Get(2).(*corev1.ConfigMap)
+				configMaps[cm.Name] = cm
+			}).Return(nil)
+			ms.On("CreateOrUpdatePodDisruptionBudget", mock.Anything, namespace, mock.Anything).Return(nil, nil)
+			ms.On("GetStatefulSet", mock.Anything, namespace, rfservice.GetRedisName(rf)).Maybe().Return(nil, kubeerrors.NewNotFound(schema.GroupResource{}, ""))
+			ms.On("CreateOrUpdateStatefulSet", mock.Anything, namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+				statefulSet = args.Get(2).(*appsv1.StatefulSet)
+			}).Return(nil)
+
+			client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+			assert.NoError(client.EnsureRedisConfigMap(context.Background(), rf, nil, []metav1.OwnerReference{}))
+			assert.NoError(client.EnsureRedisShutdownConfigMap(context.Background(), rf, nil, []metav1.OwnerReference{}))
+			assert.NoError(client.EnsureRedisReadinessConfigMap(context.Background(), rf, nil, []metav1.OwnerReference{}))
+			assert.NoError(client.EnsureRedisStatefulset(context.Background(), rf, nil, []metav1.OwnerReference{}))
+
+			redisConfig := configMaps[rfservice.GetRedisName(rf)].Data["redis.conf"]
+			assert.True(strings.HasSuffix(redisConfig, test.expConfigSuffix), redisConfig)
+
+			authFromFile := "if [ -f /redis-auth/password ]; then\n\texport REDISCLI_AUTH=$(cat /redis-auth/password)\n"
+			assert.Contains(configMaps[rfservice.GetRedisShutdownConfigMapName(rf)].Data["shutdown.sh"], authFromFile)
+			assert.Contains(configMaps[rfservice.GetRedisReadinessName(rf)].Data["ready.sh"], authFromFile)
+
+			pod := statefulSet.Spec.Template.Spec
+			assert.Contains(pod.Containers[0].VolumeMounts, corev1.VolumeMount{Name: "redis-auth", MountPath: "/redis-auth", ReadOnly: true})
+			assert.Contains(pod.Volumes, corev1.Volume{
+				Name: "redis-auth",
+				VolumeSource: corev1.VolumeSource{
+					Secret: &corev1.SecretVolumeSource{
+						SecretName: "redis-auth",
+						Items:      []corev1.KeyToPath{{Key: "password", Path: "password"}},
+					},
+				},
+			})
+		})
+	}
+}
+
+func TestGetPodDisruptionBudgets(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF()
+	rf.Spec.Redis.Replicas = 3
+	rf.Spec.Sentinel.Replicas = 3
+	rf.Spec.Predixy.Replicas = 2
+	rf.Spec.Predixy.PodDisruptionBudget.Disabled = true
+
+	assert.Equal([]rfservice.PodDisruptionBudget{
+		{Name: rfservice.GetRedisName(rf), Component: "redis", Replicas: 3, DefaultMinAvailable: intstr.FromInt(2)},
+		{Name: rfservice.GetSentinelName(rf), Component: "sentinel", Replicas: 3, DefaultMinAvailable: intstr.FromInt(2)},
+		{Name: rfservice.GetPredixyName(rf), Component: "predixy", Replicas: 2, Disabled: true, DefaultMinAvailable: intstr.FromInt(1)},
+	}, rfservice.GetPodDisruptionBudgets(rf))
+
+	rf.Spec.BootstrapNode = &redisfailoverv1.BootstrapSettings{Host: "127.0.0.1"}
+	assert.Len(rfservice.GetPodDisruptionBudgets(rf), 2, "the sentinels aren't deployed while bootstrapping")
+}
+
+func TestPodDisruptionBudgets(t *testing.T) {
+	one := intstr.FromInt(1)
+	half := intstr.FromString("50%")
+	tests := []struct {
+		name              string
+		component         string
+		rfPDB             redisfailoverv1.PodDisruptionBudgetSettings
+		replicas          int32
+		expMinAvailable   *intstr.IntOrString
+		expMaxUnavailable *intstr.IntOrString
+		expDeleted        bool
+	}{
+		{
+			name:            "Redis lets a single redis be evicted at a time",
+			component:       "redis",
+			replicas:        5,
+			expMinAvailable: intstrPtr(intstr.FromInt(4)),
+		},
+		{
+			name:            "A single redis is never evicted",
+			component:       "redis",
+			replicas:        1,
+			expMinAvailable: intstrPtr(intstr.FromInt(1)),
+		},
+		{
+			name:            "Sentinel keeps the quorum",
+			component:       "sentinel",
+			replicas:        5,
+			expMinAvailable: intstrPtr(intstr.FromInt(3)),
+		},
+		{
+			name:            "Sentinel lets a sentinel be evicted when there are two",
+			component:       "sentinel",
+			replicas:        2,
+			expMinAvailable: intstrPtr(intstr.FromInt(1)),
+		},
+		{
+			name:            "Predixy derives the budget from its own replicas",
+			component:       "predixy",
+			replicas:        2,
+			expMinAvailable: intstrPtr(intstr.FromInt(1)),
+		},
+		{
+			name:              "Uses the given maxUnavailable",
+			component:         "sentinel",
+			rfPDB:             redisfailoverv1.PodDisruptionBudgetSettings{MaxUnavailable: &one},
+			replicas:          3,
+			expMaxUnavailable: &one,
+		},
+		{
+			name:            "Uses the given minAvailable",
+			component:       "predixy",
+			rfPDB:           redisfailoverv1.PodDisruptionBudgetSettings{MinAvailable: &half},
+			replicas:        4,
+			expMinAvailable: &half,
+		},
+		{
+			name:       "Removes the disabled budget",
+			component:  "redis",
+			rfPDB:      redisfailoverv1.PodDisruptionBudgetSettings{Disabled: true},
+			replicas:   3,
+			expDeleted: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateRF()
+			var name string
+			var ensure func(client *rfservice.RedisFailoverKubeClient) error
+			switch test.component {
+			case "redis":
+				rf.Spec.Redis.Replicas = test.replicas
+				rf.Spec.Redis.PodDisruptionBudget = test.rfPDB
+				name = rfservice.GetRedisName(rf)
+				ensure = func(client *rfservice.RedisFailoverKubeClient) error {
+					return client.EnsureRedisStatefulset(context.Background(), rf, nil, []metav1.OwnerReference{})
+				}
+			case "sentinel":
+				rf.Spec.Sentinel.Replicas = test.replicas
+				rf.Spec.Sentinel.PodDisruptionBudget = test.rfPDB
+				name = rfservice.GetSentinelName(rf)
+				ensure = func(client *rfservice.RedisFailoverKubeClient) error {
+					return client.EnsureSentinelStatefulset(context.Background(), rf, nil, []metav1.OwnerReference{})
+				}
+			case "predixy":
+				rf.Spec.Predixy.Replicas = test.replicas
+				rf.Spec.Predixy.PodDisruptionBudget = test.rfPDB
+				name = rfservice.GetPredixyName(rf)
+				ensure = func(client *rfservice.RedisFailoverKubeClient) error {
+					return client.EnsureProxyDeployment(context.Background(), rf, nil, []metav1.OwnerReference{})
+				}
+			}
+
+			var pdb *policyv1.PodDisruptionBudget
+			ms := &mK8SService.Services{}
+			ms.On("CreateOrUpdatePodDisruptionBudget", mock.Anything, namespace, mock.Anything).Maybe().Run(func(args mock.Arguments) {
+				pdb = args.Get(2).(*policyv1.PodDisruptionBudget)
+			}).Return(nil)
+			if test.expDeleted {
+				ms.On("GetPodDisruptionBudget", mock.Anything, namespace, name).Once().Return(&policyv1.PodDisruptionBudget{}, nil)
+				ms.On("DeletePodDisruptionBudget", mock.Anything, namespace, name).Once().Return(nil)
+			}
+			ms.On("CreateOrUpdateStatefulSet", mock.Anything, namespace, mock.Anything).Maybe().Return(nil)
+			ms.On("GetConfigMap", mock.Anything, namespace, name).Maybe().Return(&corev1.ConfigMap{}, nil)
+			ms.On("GetDeployment", mock.Anything, namespace, name).Maybe().Return(nil, fmt.Errorf("not found"))
+			ms.On("CreateOrUpdateDeployment", mock.Anything, namespace, mock.Anything).Maybe().Return(nil)
+
+			client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+			assert.NoError(ensure(client))
+			ms.AssertExpectations(t)
+
+			if test.expDeleted {
+				assert.Nil(pdb)
+				return
+			}
+			if assert.NotNil(pdb) {
+				assert.Equal(name, pdb.Name)
+				assert.Equal(test.expMinAvailable, pdb.Spec.MinAvailable)
+				assert.Equal(test.expMaxUnavailable, pdb.Spec.MaxUnavailable)
+			}
+		})
+	}
+}
+
+func intstrPtr(v intstr.IntOrString) *intstr.IntOrString {
+	return &v
+}
@@ -1,7 +1,10 @@
 package service
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"net"
 	"sort"
 	"strconv"
 
@@ -10,20 +13,30 @@ import (
 	"github.com/spotahome/redis-operator/service/k8s"
 	"github.com/spotahome/redis-operator/service/redis"
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // RedisFailoverHeal defines the interface able to fix the problems on the redis failovers
 type RedisFailoverHeal interface {
-	MakeMaster(ip string, rFailover *redisfailoverv1.RedisFailover) error
-	SetOldestAsMaster(rFailover *redisfailoverv1.RedisFailover) error
-	SetMasterOnAll(masterIP string, rFailover *redisfailoverv1.RedisFailover) error
-	SetExternalMasterOnAll(masterIP string, masterPort string, rFailover *redisfailoverv1.RedisFailover) error
-	NewSentinelMonitor(ip string, monitor string, rFailover *redisfailoverv1.RedisFailover) error
-	NewSentinelMonitorWithPort(ip string, monitor string, port string, rFailover *redisfailoverv1.RedisFailover) error
-	RestoreSentinel(ip string) error
-	SetSentinelCustomConfig(ip string, rFailover *redisfailoverv1.RedisFailover) error
-	SetRedisCustomConfig(ip string, rFailover *redisfailoverv1.RedisFailover) error
-	DeletePod(podName string, rFailover *redisfailoverv1.RedisFailover) error
+	MakeMaster(ctx context.Context, ip string, rFailover *redisfailoverv1.RedisFailover) error
+	SetOldestAsMaster(ctx context.Context, rFailover *redisfailoverv1.RedisFailover) error
+	SetMostUpToDateAsMaster(ctx context.Context, rFailover *redisfailoverv1.RedisFailover) error
+	SetMasterOnAll(ctx context.Context, masterIP string, rFailover *redisfailoverv1.RedisFailover) error
+	SetExternalMasterOnAll(ctx context.Context, masterIP string, masterPort string, rFailover *redisfailoverv1.RedisFailover) error
+	NewSentinelMonitor(ctx context.Context, ip string, monitor string, rFailover *redisfailoverv1.RedisFailover) error
+	NewSentinelMonitorWithPort(ctx context.Context, ip string, monitor string, port string, rFailover *redisfailoverv1.RedisFailover) error
+	RestoreSentinel(ctx context.Context, ip string, rFailover *redisfailoverv1.RedisFailover) error
+	SetSentinelCustomConfig(ctx context.Context, ip string, rFailover *redisfailoverv1.RedisFailover) error
+	SetRedisCustomConfig(ctx context.Context, ip string, rFailover *redisfailoverv1.RedisFailover) error
+	DeletePod(ctx context.Context, podName string, rFailover *redisfailoverv1.RedisFailover) error
+	SetRedisRoleLabels(ctx context.Context, master string, rFailover *redisfailoverv1.RedisFailover) error
+	SetRedisAnnounce(ctx context.Context, ip string, announceIP string, announcePort string, rFailover *redisfailoverv1.RedisFailover) error
+	SetSentinelAnnounce(ctx context.Context, ip string, announceIP string, announcePort string, rFailover *redisfailoverv1.RedisFailover) error
+	SetRedisPasswords(ctx context.Context, rFailover *redisfailoverv1.RedisFailover) error
+	SetPasswordRotationStatus(ctx context.Context, phase redisfailoverv1.PasswordRotationPhase, message string, rFailover *redisfailoverv1.RedisFailover) error
+	SetExternalMasterStatus(ctx context.Context, masterIP string, masterPort string, rFailover *redisfailoverv1.RedisFailover) error
+	SwitchoverMaster(ctx context.Context, sentinel string, excluded []string, rFailover *redisfailoverv1.RedisFailover) error
+	EndSwitchover(ctx context.Context, rFailover *redisfailoverv1.RedisFailover) error
 }
 
 // RedisFailoverHealer is our implementation of RedisFailoverCheck interface
@@ -43,51 +56,51 @@ func NewRedisFailoverHealer(k8sService k8s.Services, redisClient redis.Client, l
 	}
 }
 
-func (r *RedisFailoverHealer) setMasterLabelIfNecessary(namespace string, pod v1.Pod) error {
+func (r *RedisFailoverHealer) setMasterLabelIfNecessary(ctx context.Context, namespace string, pod v1.Pod) error {
 	for labelKey, labelValue := range pod.ObjectMeta.Labels {
 		if labelKey == redisRoleLabelKey && labelValue == redisRoleLabelMaster {
 			return nil
 		}
 	}
-	return r.k8sService.UpdatePodLabels(namespace, pod.ObjectMeta.Name, generateRedisMasterRoleLabel())
+	return r.k8sService.UpdatePodLabels(ctx, namespace, pod.ObjectMeta.Name, generateRedisMasterRoleLabel())
 }
 
-func (r *RedisFailoverHealer) setSlaveLabelIfNecessary(namespace string, pod v1.Pod) error {
+func (r *RedisFailoverHealer) setSlaveLabelIfNecessary(ctx context.Context, namespace string, pod v1.Pod) error {
 	for labelKey, labelValue := range pod.ObjectMeta.Labels {
 		if labelKey == redisRoleLabelKey && labelValue == redisRoleLabelSlave {
 			return nil
 		}
 	}
-	return r.k8sService.UpdatePodLabels(namespace, pod.ObjectMeta.Name, generateRedisSlaveRoleLabel())
+	return r.k8sService.UpdatePodLabels(ctx, namespace, pod.ObjectMeta.Name, generateRedisSlaveRoleLabel())
 }
 
-func (r *RedisFailoverHealer) MakeMaster(ip string, rf *redisfailoverv1.RedisFailover) error {
-	password, err := k8s.GetRedisPassword(r.k8sService, rf)
+func (r *RedisFailoverHealer) MakeMaster(ctx context.Context, ip string, rf *redisfailoverv1.RedisFailover) error {
+	password, err := k8s.GetRedisPassword(ctx, r.k8sService, rf)
 	if err != nil {
 		return err
 	}
 
 	port := getRedisPort(rf.Spec.Redis.Port)
-	err = r.redisClient.MakeMaster(ip, port, password)
+	err = r.redisClient.MakeMaster(ctx, ip, port, password)
 	if err != nil {
 		return err
 	}
 
-	rps, err := r.k8sService.GetStatefulSetPods(rf.Namespace, GetRedisName(rf))
+	rps, err := getRedisPods(ctx, r.k8sService, rf)
 	if err != nil {
 		return err
 	}
 	for _, rp := range rps.Items {
-		if rp.Status.PodIP == ip {
-			return r.setMasterLabelIfNecessary(rf.Namespace, rp)
+		if getRedisAddress(rf, rp) == ip {
+			return r.setMasterLabelIfNecessary(ctx, rf.Namespace, rp)
 		}
 	}
 	return nil
 }
 
 // SetOldestAsMaster puts all redis to the same master, choosen by order of appearance
-func (r *RedisFailoverHealer) SetOldestAsMaster(rf *redisfailoverv1.RedisFailover) error {
-	ssp, err := r.k8sService.GetStatefulSetPods(rf.Namespace, GetRedisName(rf))
+func (r *RedisFailoverHealer) SetOldestAsMaster(ctx context.Context, rf *redisfailoverv1.RedisFailover) error {
+	ssp, err := getRedisPods(ctx, r.k8sService, rf)
 	if err != nil {
 		return err
 	}
@@ -100,7 +113,7 @@ func (r *RedisFailoverHealer) SetOldestAsMaster(rf *redisfailoverv1.RedisFailove
 		return ssp.Items[i].CreationTimestamp.Before(&ssp.Items[j].CreationTimestamp)
 	})
 
-	password, err := k8s.GetRedisPassword(r.k8sService, rf)
+	password, err := k8s.GetRedisPassword(ctx, r.k8sService, rf)
 	if err != nil {
 		return err
 	}
@@ -108,29 +121,30 @@ func (r *RedisFailoverHealer) SetOldestAsMaster(rf *redisfailoverv1.RedisFailove
 	port := getRedisPort(rf.Spec.Redis.Port)
 	newMasterIP := ""
 	for _, pod := range ssp.Items {
+		address := getRedisAddress(rf, pod)
 		if newMasterIP == "" {
-			newMasterIP = pod.Status.PodIP
-			r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Infof("New master is %s with ip %s", pod.Name, newMasterIP)
-			r.logger.Infof("MakeMaster pod %s command: slaveof no one", pod.Name)
-			if err := r.redisClient.MakeMaster(newMasterIP, port, password); err != nil {
+			newMasterIP = address
+			Logger(ctx, r.logger, rf).Infof("New master is %s with ip %s", pod.Name, newMasterIP)
+			Logger(ctx, r.logger, rf).Infof("MakeMaster pod %s command: slaveof no one", pod.Name)
+			if err := r.redisClient.MakeMaster(ctx, newMasterIP, port, password); err != nil {
 				newMasterIP = ""
-				r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Errorf("Make new master failed, master ip: %s, error: %v", pod.Status.PodIP, err)
+				Logger(ctx, r.logger, rf).Errorf("Make new master failed, master ip: %s, error: %v", address, err)
 				continue
 			}
 
-			err = r.setMasterLabelIfNecessary(rf.Namespace, pod)
+			err = r.setMasterLabelIfNecessary(ctx, rf.Namespace, pod)
 			if err != nil {
 				return err
 			}
 
-			newMasterIP = pod.Status.PodIP
+			newMasterIP = address
 		} else {
-			r.logger.Infof("Making pod %s command: slaveof %s %v", pod.Name, newMasterIP, port)
-			if err := r.redisClient.MakeSlaveOfWithPort(pod.Status.PodIP, newMasterIP, port, password); err != nil {
-				r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Errorf("Make slave failed, slave pod ip: %s, master ip: %s, error: %v", pod.Status.PodIP, newMasterIP, err)
+			Logger(ctx, r.logger, rf).Infof("Making pod %s command: slaveof %s %v", pod.Name, newMasterIP, port)
+			if err := r.redisClient.MakeSlaveOfWithPort(ctx, address, newMasterIP, port, password); err != nil {
+				Logger(ctx, r.logger, rf).Errorf("Make slave failed, slave pod ip: %s, master ip: %s, error: %v", address, newMasterIP, err)
 			}
 
-			err = r.setSlaveLabelIfNecessary(rf.Namespace, pod)
+			err = r.setSlaveLabelIfNecessary(ctx, rf.Namespace, pod)
 			if err != nil {
 				return err
 			}
@@ -143,14 +157,67 @@ func (r *RedisFailoverHealer) SetOldestAsMaster(rf *redisfailoverv1.RedisFailove
 	}
 }
 
+// SetMostUpToDateAsMaster detaches the redises from an external master, promoting the one that replicated the most
+// of it and making the rest its slaves. Replication TLS, only used with the external master, is turned off
+func (r *RedisFailoverHealer) SetMostUpToDateAsMaster(ctx context.Context, rf *redisfailoverv1.RedisFailover) error {
+	ssp, err := getRedisPods(ctx, r.k8sService, rf)
+	if err != nil {
+		return err
+	}
+
+	password, err := k8s.GetRedisPassword(ctx, r.k8sService, rf)
+	if err != nil {
+		return err
+	}
+
+	port := getRedisPort(rf.Spec.Redis.Port)
+	newMaster := ""
+	var newMasterOffset int64 = -1
+	addresses := []string{}
+	for _, pod := range ssp.Items {
+		if pod.Status.Phase != v1.PodRunning || pod.DeletionTimestamp != nil {
+			continue
+		}
+		address := getRedisAddress(rf, pod)
+		addresses = append(addresses, address)
+		if err := r.redisClient.DisableReplicationTLS(ctx, address, port, password); err != nil {
+			return err
+		}
+		offset, err := r.redisClient.GetReplicationOffset(ctx, address, port, password)
+		if err != nil {
+			return err
+		}
+		if offset > newMasterOffset {
+			newMaster, newMasterOffset = address, offset
+		}
+	}
+	if newMaster == "" {
+		return errors.New("number of redis pods are 0")
+	}
+
+	Logger(ctx, r.logger, rf).Infof("Detaching from the external master, new master is %s with replication offset %d", newMaster, newMasterOffset)
+	if err := r.redisClient.MakeMaster(ctx, newMaster, port, password); err != nil {
+		return err
+	}
+	for _, address := range addresses {
+		if address == newMaster {
+			continue
+		}
+		if err := r.redisClient.MakeSlaveOfWithPort(ctx, address, newMaster, port, password); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // SetMasterOnAll puts all redis nodes as a slave of a given master
-func (r *RedisFailoverHealer) SetMasterOnAll(masterIP string, rf *redisfailoverv1.RedisFailover) error {
-	ssp, err := r.k8sService.GetStatefulSetPods(rf.Namespace, GetRedisName(rf))
+func (r *RedisFailoverHealer) SetMasterOnAll(ctx context.Context, masterIP string, rf *redisfailoverv1.RedisFailover) error {
+	ssp, err := getRedisPods(ctx, r.k8sService, rf)
 	if err != nil {
 		return err
 	}
 
-	password, err := k8s.GetRedisPassword(r.k8sService, rf)
+	password, err := k8s.GetRedisPassword(ctx, r.k8sService, rf)
 	if err != nil {
 		return err
 	}
@@ -158,21 +225,22 @@ func (r *RedisFailoverHealer) SetMasterOnAll(masterIP string, rf *redisfailoverv
 	port := getRedisPort(rf.Spec.Redis.Port)
 	for _, pod := range ssp.Items {
 		//During this configuration process if there is a new master selected , bailout
-		isMaster, err := r.redisClient.IsMaster(masterIP, port, password)
+		isMaster, err := r.redisClient.IsMaster(ctx, masterIP, port, password)
 		if err != nil || !isMaster {
-			r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Errorf("check master failed maybe this node is not ready(ip changed), or sentinel made a switch: %s", masterIP)
+			Logger(ctx, r.logger, rf).Errorf("check master failed maybe this node is not ready(ip changed), or sentinel made a switch: %s", masterIP)
 			return err
 		} else {
-			if pod.Status.PodIP == masterIP {
+			address := getRedisAddress(rf, pod)
+			if address == masterIP {
 				continue
 			}
-			r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Infof("Making pod %s slave of %s", pod.Name, masterIP)
-			if err := r.redisClient.MakeSlaveOfWithPort(pod.Status.PodIP, masterIP, port, password); err != nil {
-				r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Errorf("Make slave failed, slave ip: %s, master ip: %s, error: %v", pod.Status.PodIP, masterIP, err)
+			Logger(ctx, r.logger, rf).Infof("Making pod %s slave of %s", pod.Name, masterIP)
+			if err := r.redisClient.MakeSlaveOfWithPort(ctx, address, masterIP, port, password); err != nil {
+				Logger(ctx, r.logger, rf).Errorf("Make slave failed, slave ip: %s, master ip: %s, error: %v", address, masterIP, err)
 				return err
 			}
 
-			err = r.setSlaveLabelIfNecessary(rf.Namespace, pod)
+			err = r.setSlaveLabelIfNecessary(ctx, rf.Namespace, pod)
 			if err != nil {
 				return err
 			}
@@ -183,20 +251,20 @@ func (r *RedisFailoverHealer) SetMasterOnAll(masterIP string, rf *redisfailoverv
 
 // SetExternalMasterOnAll puts all redis nodes as a slave of a given master outside of
 // the current RedisFailover instance
-func (r *RedisFailoverHealer) SetExternalMasterOnAll(masterIP, masterPort string, rf *redisfailoverv1.RedisFailover) error {
-	ssp, err := r.k8sService.GetStatefulSetPods(rf.Namespace, GetRedisName(rf))
+func (r *RedisFailoverHealer) SetExternalMasterOnAll(ctx context.Context, masterIP, masterPort string, rf *redisfailoverv1.RedisFailover) error {
+	ssp, err := getRedisPods(ctx, r.k8sService, rf)
 	if err != nil {
 		return err
 	}
 
-	password, err := k8s.GetRedisPassword(r.k8sService, rf)
+	password, err := k8s.GetRedisPassword(ctx, r.k8sService, rf)
 	if err != nil {
 		return err
 	}
 
 	for _, pod := range ssp.Items {
-		r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Infof("Making pod %s slave of %s:%s", pod.Name, masterIP, masterPort)
-		if err := r.redisClient.MakeSlaveOfWithPort(pod.Status.PodIP, masterIP, masterPort, password); err != nil {
+		Logger(ctx, r.logger, rf).Infof("Making pod %s slave of %s:%s", pod.Name, masterIP, masterPort)
+		if err := r.redisClient.MakeSlaveOfWithPort(ctx, getRedisAddress(rf, pod), masterIP, masterPort, password); err != nil {
 			return err
 		}
 
@@ -205,57 +273,260 @@ func (r *RedisFailoverHealer) SetExternalMasterOnAll(masterIP, masterPort string
 }
 
 // NewSentinelMonitor changes the master that Sentinel has to monitor
-func (r *RedisFailoverHealer) NewSentinelMonitor(ip string, monitor string, rf *redisfailoverv1.RedisFailover) error {
+func (r *RedisFailoverHealer) NewSentinelMonitor(ctx context.Context, ip string, monitor string, rf *redisfailoverv1.RedisFailover) error {
 	quorum := strconv.Itoa(int(getQuorum(rf)))
 
-	password, err := k8s.GetRedisPassword(r.k8sService, rf)
+	password, err := k8s.GetRedisPassword(ctx, r.k8sService, rf)
+	if err != nil {
+		return err
+	}
+
+	sentinelPassword, err := k8s.GetSentinelPassword(ctx, r.k8sService, rf)
 	if err != nil {
 		return err
 	}
 
 	port := getRedisPort(rf.Spec.Redis.Port)
-	return r.redisClient.MonitorRedisWithPort(ip, monitor, port, quorum, password)
+	sport := getSentinelPort(rf.Spec.Sentinel.Port)
+	return r.redisClient.MonitorRedisWithPort(ctx, ip, sport, rf.Spec.Sentinel.MasterName, redis.MonitorOptions{
+		IP:       monitor,
+		Port:     port,
+		Quorum:   quorum,
+		AuthPass: password,
+	}, sentinelPassword)
 }
 
 // NewSentinelMonitorWithPort changes the master that Sentinel has to monitor by the provided IP and Port
-func (r *RedisFailoverHealer) NewSentinelMonitorWithPort(ip string, monitor string, monitorPort string, rf *redisfailoverv1.RedisFailover) error {
+func (r *RedisFailoverHealer) NewSentinelMonitorWithPort(ctx context.Context, ip string, monitor string, monitorPort string, rf *redisfailoverv1.RedisFailover) error {
 	quorum := strconv.Itoa(int(getQuorum(rf)))
 
-	password, err := k8s.GetRedisPassword(r.k8sService, rf)
+	password, err := k8s.GetRedisPassword(ctx, r.k8sService, rf)
+	if err != nil {
+		return err
+	}
+
+	sentinelPassword, err := k8s.GetSentinelPassword(ctx, r.k8sService, rf)
 	if err != nil {
 		return err
 	}
 
-	return r.redisClient.MonitorRedisWithPort(ip, monitor, monitorPort, quorum, password)
+	sport := getSentinelPort(rf.Spec.Sentinel.Port)
+	return r.redisClient.MonitorRedisWithPort(ctx, ip, sport, rf.Spec.Sentinel.MasterName, redis.MonitorOptions{
+		IP:       monitor,
+		Port:     monitorPort,
+		Quorum:   quorum,
+		AuthPass: password,
+	}, sentinelPassword)
 }
 
 // RestoreSentinel clear the number of sentinels on memory
-func (r *RedisFailoverHealer) RestoreSentinel(ip string) error {
-	r.logger.Debugf("Restoring sentinel %s", ip)
-	return r.redisClient.ResetSentinel(ip)
+func (r *RedisFailoverHealer) RestoreSentinel(ctx context.Context, ip string, rf *redisfailoverv1.RedisFailover) error {
+	Logger(ctx, r.logger, rf).Debugf("Restoring sentinel %s", ip)
+
+	password, err := k8s.GetSentinelPassword(ctx, r.k8sService, rf)
+	if err != nil {
+		return err
+	}
+
+	return r.redisClient.ResetSentinel(ctx, ip, getSentinelPort(rf.Spec.Sentinel.Port), password)
 }
 
 // SetSentinelCustomConfig will call sentinel to set the configuration given in config
-func (r *RedisFailoverHealer) SetSentinelCustomConfig(ip string, rf *redisfailoverv1.RedisFailover) error {
-	r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Debugf("Setting the custom config on sentinel %s...", ip)
-	return r.redisClient.SetCustomSentinelConfig(ip, rf.Spec.Sentinel.CustomConfig)
+func (r *RedisFailoverHealer) SetSentinelCustomConfig(ctx context.Context, ip string, rf *redisfailoverv1.RedisFailover) error {
+	Logger(ctx, r.logger, rf).Debugf("Setting the custom config on sentinel %s...", ip)
+
+	password, err := k8s.GetSentinelPassword(ctx, r.k8sService, rf)
+	if err != nil {
+		return err
+	}
+
+	sport := getSentinelPort(rf.Spec.Sentinel.Port)
+	return r.redisClient.SetCustomSentinelConfig(ctx, ip, sport, rf.Spec.Sentinel.MasterName, rf.Spec.Sentinel.CustomConfig, password)
 }
 
 // SetRedisCustomConfig will call redis to set the configuration given in config
-func (r *RedisFailoverHealer) SetRedisCustomConfig(ip string, rf *redisfailoverv1.RedisFailover) error {
-	r.logger.WithField("redisfailover", rf.ObjectMeta.Name).WithField("namespace", rf.ObjectMeta.Namespace).Debugf("Setting the custom config on redis %s...", ip)
+func (r *RedisFailoverHealer) SetRedisCustomConfig(ctx context.Context, ip string, rf *redisfailoverv1.RedisFailover) error {
+	Logger(ctx, r.logger, rf).Debugf("Setting the custom config on redis %s...", ip)
+
+	password, err := k8s.GetRedisPassword(ctx, r.k8sService, rf)
+	if err != nil {
+		return err
+	}
 
-	password, err := k8s.GetRedisPassword(r.k8sService, rf)
+	// masterauth changes when replicating another RedisFailover starts or ends, the configmap is only read on start
+	masterAuth, err := k8s.GetRedisMasterAuth(ctx, r.k8sService, rf)
 	if err != nil {
 		return err
 	}
+	configs := rf.Spec.Redis.CustomConfig
+	if masterAuth != "" {
+		configs = append(append([]string{}, configs...), fmt.Sprintf("masterauth %s", masterAuth))
+	}
+	if rf.Spec.Redis.Zones != nil {
+		rps, err := getRedisPods(ctx, r.k8sService, rf)
+		if err != nil {
+			return err
+		}
+		if priority := getZoneReplicaPriority(rf, rps, ip); priority != "" {
+			configs = append(append([]string{}, configs...), priority)
+		}
+	}
 
 	port := getRedisPort(rf.Spec.Redis.Port)
-	return r.redisClient.SetCustomRedisConfig(ip, port, rf.Spec.Redis.CustomConfig, password)
+	return r.redisClient.SetCustomRedisConfig(ctx, ip, port, configs, password)
+}
+
+// SetRedisAnnounce makes redis announce the given address to its master, so sentinel reaches it through it
+func (r *RedisFailoverHealer) SetRedisAnnounce(ctx context.Context, ip string, announceIP string, announcePort string, rf *redisfailoverv1.RedisFailover) error {
+	Logger(ctx, r.logger, rf).Debugf("Setting the announced address on redis %s to %s:%s", ip, announceIP, announcePort)
+
+	password, err := k8s.GetRedisPassword(ctx, r.k8sService, rf)
+	if err != nil {
+		return err
+	}
+
+	port := getRedisPort(rf.Spec.Redis.Port)
+	configs := []string{
+		fmt.Sprintf("replica-announce-ip %s", announceIP),
+		fmt.Sprintf("replica-announce-port %s", announcePort),
+	}
+	return r.redisClient.SetCustomRedisConfig(ctx, ip, port, configs, password)
+}
+
+// SetSentinelAnnounce makes sentinel announce the given address to the other sentinels and its clients
+func (r *RedisFailoverHealer) SetSentinelAnnounce(ctx context.Context, ip string, announceIP string, announcePort string, rf *redisfailoverv1.RedisFailover) error {
+	Logger(ctx, r.logger, rf).Debugf("Setting the announced address on sentinel %s to %s:%s", ip, announceIP, announcePort)
+
+	password, err := k8s.GetSentinelPassword(ctx, r.k8sService, rf)
+	if err != nil {
+		return err
+	}
+
+	return r.redisClient.SetSentinelAnnounce(ctx, ip, getSentinelPort(rf.Spec.Sentinel.Port), announceIP, announcePort, password)
+}
+
+// SetRedisPasswords makes every running redis accept the password of the auth secret, along with the previous one
+// while it is still there, authenticates them to their master and sentinels to them with it. Redises still
+// expecting the previous password are reached with it
+func (r *RedisFailoverHealer) SetRedisPasswords(ctx context.Context, rf *redisfailoverv1.RedisFailover) error {
+	password, err := k8s.GetRedisPassword(ctx, r.k8sService, rf)
+	if err != nil {
+		return err
+	}
+	previousPassword, err := k8s.GetRedisPreviousPassword(ctx, r.k8sService, rf)
+	if err != nil {
+		return err
+	}
+	masterAuth, err := k8s.GetRedisMasterAuth(ctx, r.k8sService, rf)
+	if err != nil {
+		return err
+	}
+
+	passwords := []string{password}
+	if previousPassword != "" && previousPassword != password {
+		passwords = append(passwords, previousPassword)
+	}
+
+	rps, err := getRedisPods(ctx, r.k8sService, rf)
+	if err != nil {
+		return err
+	}
+	port := getRedisPort(rf.Spec.Redis.Port)
+	addresses := []string{}
+	for _, rp := range rps.Items {
+		if rp.Status.Phase != v1.PodRunning || rp.DeletionTimestamp != nil {
+			continue
+		}
+		address := getRedisAddress(rf, rp)
+		addresses = append(addresses, address)
+		if err := r.redisClient.SetDefaultUserPasswords(ctx, address, port, passwords, password); err != nil {
+			if previousPassword == "" {
+				return err
+			}
+			Logger(ctx, r.logger, rf).Debugf("Redis %s rejected the new password, retrying with the previous one", address)
+			if err := r.redisClient.SetDefaultUserPasswords(ctx, address, port, passwords, previousPassword); err != nil {
+				return err
+			}
+		}
+	}
+
+	// masterauth is only switched once every redis accepts the new password, so replication never breaks
+	for _, address := range addresses {
+		if err := r.redisClient.SetCustomRedisConfig(ctx, address, port, []string{fmt.Sprintf("masterauth %s", masterAuth)}, password); err != nil {
+			return err
+		}
+	}
+
+	sentinels, err := r.k8sService.GetStatefulSetPods(ctx, rf.Namespace, GetSentinelName(rf))
+	if err != nil {
+		return err
+	}
+	sentinelPassword, err := k8s.GetSentinelPassword(ctx, r.k8sService, rf)
+	if err != nil {
+		return err
+	}
+	sport := getSentinelPort(rf.Spec.Sentinel.Port)
+	for _, sp := range sentinels.Items {
+		if sp.Status.Phase != v1.PodRunning || sp.DeletionTimestamp != nil {
+			continue
+		}
+		if err := r.redisClient.SetCustomSentinelConfig(ctx, getSentinelAddress(rf, sp), sport, rf.Spec.Sentinel.MasterName, []string{fmt.Sprintf("auth-pass %s", password)}, sentinelPassword); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetPasswordRotationStatus reports the given password rotation phase on the status of the redisfailover
+func (r *RedisFailoverHealer) SetPasswordRotationStatus(ctx context.Context, phase redisfailoverv1.PasswordRotationPhase, message string, rf *redisfailoverv1.RedisFailover) error {
+	Logger(ctx, r.logger, rf).Infof("Password rotation %s: %s", phase, message)
+	rf.Status.PasswordRotation = &redisfailoverv1.PasswordRotationStatus{
+		Phase:              phase,
+		Message:            message,
+		LastTransitionTime: metav1.Now(),
+	}
+	return r.k8sService.UpdateRedisFailoverStatus(ctx, rf)
+}
+
+// SetExternalMasterStatus records the external master the redises replicate on the status of the redisfailover, or
+// clears it when masterIP is empty. The status is only updated when it changes
+func (r *RedisFailoverHealer) SetExternalMasterStatus(ctx context.Context, masterIP string, masterPort string, rf *redisfailoverv1.RedisFailover) error {
+	master := ""
+	if masterIP != "" {
+		master = net.JoinHostPort(masterIP, masterPort)
+	}
+	if rf.Status.ExternalMaster == master {
+		return nil
+	}
+	rf.Status.ExternalMaster = master
+	return r.k8sService.UpdateRedisFailoverStatus(ctx, rf)
+}
+
+// SetRedisRoleLabels labels the given master with the master role and every other redis with the slave one,
+// so the services selecting on the role follow a failover as soon as it is detected
+func (r *RedisFailoverHealer) SetRedisRoleLabels(ctx context.Context, master string, rf *redisfailoverv1.RedisFailover) error {
+	rps, err := getRedisPods(ctx, r.k8sService, rf)
+	if err != nil {
+		return err
+	}
+	for _, rp := range rps.Items {
+		if getRedisAddress(rf, rp) == master {
+			if rp.ObjectMeta.Labels[redisRoleLabelKey] != redisRoleLabelMaster {
+				Logger(ctx, r.logger, rf).Infof("Master moved to pod %s, updating role labels", rp.Name)
+			}
+			err = r.setMasterLabelIfNecessary(ctx, rf.Namespace, rp)
+		} else {
+			err = r.setSlaveLabelIfNecessary(ctx, rf.Namespace, rp)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // DeletePod delete a failing pod so kubernetes relaunch it again
-func (r *RedisFailoverHealer) DeletePod(podName string, rFailover *redisfailoverv1.RedisFailover) error {
-	r.logger.WithField("redisfailover", rFailover.ObjectMeta.Name).WithField("namespace", rFailover.ObjectMeta.Namespace).Infof("Deleting pods %s...", podName)
-	return r.k8sService.DeletePod(rFailover.Namespace, podName)
+func (r *RedisFailoverHealer) DeletePod(ctx context.Context, podName string, rFailover *redisfailoverv1.RedisFailover) error {
+	Logger(ctx, r.logger, rFailover).Infof("Deleting pods %s...", podName)
+	return r.k8sService.DeletePod(ctx, rFailover.Namespace, podName)
 }
@@ -1,6 +1,7 @@
 package service_test
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
@@ -11,10 +12,12 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
 	"github.com/spotahome/redis-operator/log"
 	mK8SService "github.com/spotahome/redis-operator/mocks/service/k8s"
 	mRedisService "github.com/spotahome/redis-operator/mocks/service/redis"
 	rfservice "github.com/spotahome/redis-operator/operator/redisfailover/service"
+	"github.com/spotahome/redis-operator/service/redis"
 )
 
 func TestSetOldestAsMasterNewMasterError(t *testing.T) {
@@ -33,14 +36,14 @@ func TestSetOldestAsMasterNewMasterError(t *testing.T) {
 	}
 
 	ms := &mK8SService.Services{}
-	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
-	ms.On("UpdatePodLabels", namespace, mock.AnythingOfType("string"), mock.Anything).Return(nil)
+	ms.On("GetStatefulSetPods", mock.Anything, namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
+	ms.On("UpdatePodLabels", mock.Anything, namespace, mock.AnythingOfType("string"), mock.Anything).Return(nil)
 	mr := &mRedisService.Client{}
-	mr.On("MakeMaster", "0.0.0.0", "0", "").Once().Return(errors.New(""))
+	mr.On("MakeMaster", mock.Anything, "0.0.0.0", "0", "").Once().Return(errors.New(""))
 
 	healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
 
-	err := healer.SetOldestAsMaster(rf)
+	err := healer.SetOldestAsMaster(context.Background(), rf)
 	assert.Error(err)
 }
 
@@ -60,14 +63,14 @@ func TestSetOldestAsMaster(t *testing.T) {
 	}
 
 	ms := &mK8SService.Services{}
-	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
-	ms.On("UpdatePodLabels", namespace, mock.AnythingOfType("string"), mock.Anything).Once().Return(nil)
+	ms.On("GetStatefulSetPods", mock.Anything, namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
+	ms.On("UpdatePodLabels", mock.Anything, namespace, mock.AnythingOfType("string"), mock.Anything).Once().Return(nil)
 	mr := &mRedisService.Client{}
-	mr.On("MakeMaster", "0.0.0.0", "0", "").Once().Return(nil)
+	mr.On("MakeMaster", mock.Anything, "0.0.0.0", "0", "").Once().Return(nil)
 
 	healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
 
-	err := healer.SetOldestAsMaster(rf)
+	err := healer.SetOldestAsMaster(context.Background(), rf)
 	assert.NoError(err)
 }
 
@@ -92,15 +95,15 @@ func TestSetOldestAsMasterMultiplePodsMakeSlaveOfError(t *testing.T) {
 	}
 
 	ms := &mK8SService.Services{}
-	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
-	ms.On("UpdatePodLabels", namespace, mock.AnythingOfType("string"), mock.Anything).Return(nil)
+	ms.On("GetStatefulSetPods", mock.Anything, namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
+	ms.On("UpdatePodLabels", mock.Anything, namespace, mock.AnythingOfType("string"), mock.Anything).Return(nil)
 	mr := &mRedisService.Client{}
-	mr.On("MakeMaster", "0.0.0.0", "0", "").Once().Return(nil)
-	mr.On("MakeSlaveOfWithPort", "1.1.1.1", "0.0.0.0", "0", "").Once().Return(errors.New(""))
+	mr.On("MakeMaster", mock.Anything, "0.0.0.0", "0", "").Once().Return(nil)
+	mr.On("MakeSlaveOfWithPort", mock.Anything, "1.1.1.1", "0.0.0.0", "0", "").Once().Return(errors.New(""))
 
 	healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
 
-	err := healer.SetOldestAsMaster(rf)
+	err := healer.SetOldestAsMaster(context.Background(), rf)
 	assert.NoError(err)
 }
 
@@ -125,15 +128,15 @@ func TestSetOldestAsMasterMultiplePods(t *testing.T) {
 	}
 
 	ms := &mK8SService.Services{}
-	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
-	ms.On("UpdatePodLabels", namespace, mock.AnythingOfType("string"), mock.Anything).Return(nil)
+	ms.On("GetStatefulSetPods", mock.Anything, namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
+	ms.On("UpdatePodLabels", mock.Anything, namespace, mock.AnythingOfType("string"), mock.Anything).Return(nil)
 	mr := &mRedisService.Client{}
-	mr.On("MakeMaster", "0.0.0.0", "0", "").Once().Return(nil)
-	mr.On("MakeSlaveOfWithPort", "1.1.1.1", "0.0.0.0", "0", "").Once().Return(nil)
+	mr.On("MakeMaster", mock.Anything, "0.0.0.0", "0", "").Once().Return(nil)
+	mr.On("MakeSlaveOfWithPort", mock.Anything, "1.1.1.1", "0.0.0.0", "0", "").Once().Return(nil)
 
 	healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
 
-	err := healer.SetOldestAsMaster(rf)
+	err := healer.SetOldestAsMaster(context.Background(), rf)
 	assert.NoError(err)
 }
 
@@ -168,15 +171,15 @@ func TestSetOldestAsMasterOrdering(t *testing.T) {
 	}
 
 	ms := &mK8SService.Services{}
-	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
-	ms.On("UpdatePodLabels", namespace, mock.AnythingOfType("string"), mock.Anything).Return(nil)
+	ms.On("GetStatefulSetPods", mock.Anything, namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
+	ms.On("UpdatePodLabels", mock.Anything, namespace, mock.AnythingOfType("string"), mock.Anything).Return(nil)
 	mr := &mRedisService.Client{}
-	mr.On("MakeMaster", "1.1.1.1", "0", "").Once().Return(nil)
-	mr.On("MakeSlaveOfWithPort", "0.0.0.0", "1.1.1.1", "0", "").Once().Return(nil)
+	mr.On("MakeMaster", mock.Anything, "1.1.1.1", "0", "").Once().Return(nil)
+	mr.On("MakeSlaveOfWithPort", mock.Anything, "0.0.0.0", "1.1.1.1", "0", "").Once().Return(nil)
 
 	healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
 
-	err := healer.SetOldestAsMaster(rf)
+	err := healer.SetOldestAsMaster(context.Background(), rf)
 	assert.NoError(err)
 }
 
@@ -201,13 +204,13 @@ func TestSetMasterOnAllMakeMasterError(t *testing.T) {
 	}
 
 	ms := &mK8SService.Services{}
-	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
-	ms.On("UpdatePodLabels", namespace, mock.AnythingOfType("string"), mock.Anything).Once().Return(nil)
+	ms.On("GetStatefulSetPods", mock.Anything, namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
+	ms.On("UpdatePodLabels", mock.Anything, namespace, mock.AnythingOfType("string"), mock.Anything).Once().Return(nil)
 	mr := &mRedisService.Client{}
-	mr.On("IsMaster", "0.0.0.0", "0", "").Return(false, errors.New(""))
+	mr.On("IsMaster", mock.Anything, "0.0.0.0", "0", "").Return(false, errors.New(""))
 	healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
 
-	err := healer.SetMasterOnAll("0.0.0.0", rf)
+	err := healer.SetMasterOnAll(context.Background(), "0.0.0.0", rf)
 	assert.Error(err)
 }
 
@@ -232,15 +235,15 @@ func TestSetMasterOnAllMakeSlaveOfError(t *testing.T) {
 	}
 
 	ms := &mK8SService.Services{}
-	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
-	ms.On("UpdatePodLabels", namespace, mock.AnythingOfType("string"), mock.Anything).Return(nil)
+	ms.On("GetStatefulSetPods", mock.Anything, namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
+	ms.On("UpdatePodLabels", mock.Anything, namespace, mock.AnythingOfType("string"), mock.Anything).Return(nil)
 	mr := &mRedisService.Client{}
-	mr.On("IsMaster", "0.0.0.0", "0", "").Return(true, nil)
-	mr.On("MakeSlaveOfWithPort", "1.1.1.1", "0.0.0.0", "0", "").Once().Return(errors.New(""))
+	mr.On("IsMaster", mock.Anything, "0.0.0.0", "0", "").Return(true, nil)
+	mr.On("MakeSlaveOfWithPort", mock.Anything, "1.1.1.1", "0.0.0.0", "0", "").Once().Return(errors.New(""))
 
 	healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
 
-	err := healer.SetMasterOnAll("0.0.0.0", rf)
+	err := healer.SetMasterOnAll(context.Background(), "0.0.0.0", rf)
 	assert.Error(err)
 }
 
@@ -265,18 +268,74 @@ func TestSetMasterOnAll(t *testing.T) {
 	}
 
 	ms := &mK8SService.Services{}
-	ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
-	ms.On("UpdatePodLabels", namespace, mock.AnythingOfType("string"), mock.Anything).Return(nil)
+	ms.On("GetStatefulSetPods", mock.Anything, namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
+	ms.On("UpdatePodLabels", mock.Anything, namespace, mock.AnythingOfType("string"), mock.Anything).Return(nil)
 	mr := &mRedisService.Client{}
-	mr.On("IsMaster", "0.0.0.0", "0", "").Return(true, nil)
-	mr.On("MakeSlaveOfWithPort", "1.1.1.1", "0.0.0.0", "0", "").Once().Return(nil)
+	mr.On("IsMaster", mock.Anything, "0.0.0.0", "0", "").Return(true, nil)
+	mr.On("MakeSlaveOfWithPort", mock.Anything, "1.1.1.1", "0.0.0.0", "0", "").Once().Return(nil)
 
 	healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
 
-	err := healer.SetMasterOnAll("0.0.0.0", rf)
+	err := healer.SetMasterOnAll(context.Background(), "0.0.0.0", rf)
 	assert.NoError(err)
 }
 
+func TestSetRedisRoleLabels(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF()
+
+	pods := &corev1.PodList{
+		Items: []corev1.Pod{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "old-master",
+					Labels: map[string]string{
+						"redisfailovers-role": "master",
+					},
+				},
+				Status: corev1.PodStatus{
+					PodIP: "0.0.0.0",
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "new-master",
+					Labels: map[string]string{
+						"redisfailovers-role": "slave",
+					},
+				},
+				Status: corev1.PodStatus{
+					PodIP: "1.1.1.1",
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "slave",
+					Labels: map[string]string{
+						"redisfailovers-role": "slave",
+					},
+				},
+				Status: corev1.PodStatus{
+					PodIP: "2.2.2.2",
+				},
+			},
+		},
+	}
+
+	ms := &mK8SService.Services{}
+	ms.On("GetStatefulSetPods", mock.Anything, namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
+	ms.On("UpdatePodLabels", mock.Anything, namespace, "old-master", map[string]string{"redisfailovers-role": "slave"}).Once().Return(nil)
+	ms.On("UpdatePodLabels", mock.Anything, namespace, "new-master", map[string]string{"redisfailovers-role": "master"}).Once().Return(nil)
+	mr := &mRedisService.Client{}
+
+	healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
+
+	err := healer.SetRedisRoleLabels(context.Background(), "1.1.1.1", rf)
+	assert.NoError(err)
+	ms.AssertExpectations(t)
+}
+
 func TestSetExternalMasterOnAll(t *testing.T) {
 	tests := []struct {
 		name                  string
@@ -320,25 +379,25 @@ func TestSetExternalMasterOnAll(t *testing.T) {
 
 			if test.errorOnGetStatefulSet {
 				expectError = true
-				ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(nil, errors.New(""))
+				ms.On("GetStatefulSetPods", mock.Anything, namespace, rfservice.GetRedisName(rf)).Once().Return(nil, errors.New(""))
 			} else {
-				ms.On("GetStatefulSetPods", namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
+				ms.On("GetStatefulSetPods", mock.Anything, namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
 			}
 
 			mr := &mRedisService.Client{}
 			if !expectError {
-				mr.On("MakeSlaveOfWithPort", "0.0.0.0", "5.5.5.5", "6379", "").Once().Return(nil)
+				mr.On("MakeSlaveOfWithPort", mock.Anything, "0.0.0.0", "5.5.5.5", "6379", "").Once().Return(nil)
 				if test.errorOnMakeSlaveOf {
 					expectError = true
-					mr.On("MakeSlaveOfWithPort", "1.1.1.1", "5.5.5.5", "6379", "").Once().Return(errors.New(""))
+					mr.On("MakeSlaveOfWithPort", mock.Anything, "1.1.1.1", "5.5.5.5", "6379", "").Once().Return(errors.New(""))
 				} else {
-					mr.On("MakeSlaveOfWithPort", "1.1.1.1", "5.5.5.5", "6379", "").Once().Return(nil)
+					mr.On("MakeSlaveOfWithPort", mock.Anything, "1.1.1.1", "5.5.5.5", "6379", "").Once().Return(nil)
 				}
 			}
 
 			healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
 
-			err := healer.SetExternalMasterOnAll("5.5.5.5", "6379", rf)
+			err := healer.SetExternalMasterOnAll(context.Background(), "5.5.5.5", "6379", rf)
 
 			if expectError {
 				assert.Error(err)
@@ -375,14 +434,14 @@ func TestNewSentinelMonitor(t *testing.T) {
 
 			if test.errorOnMonitorRedis {
 				errorExpected = true
-				mr.On("MonitorRedisWithPort", "0.0.0.0", "1.1.1.1", "0", "2", "").Once().Return(errors.New(""))
+				mr.On("MonitorRedisWithPort", mock.Anything, "0.0.0.0", "26379", "master0", redis.MonitorOptions{IP: "1.1.1.1", Port: "0", Quorum: "2"}, "").Once().Return(errors.New(""))
 			} else {
-				mr.On("MonitorRedisWithPort", "0.0.0.0", "1.1.1.1", "0", "2", "").Once().Return(nil)
+				mr.On("MonitorRedisWithPort", mock.Anything, "0.0.0.0", "26379", "master0", redis.MonitorOptions{IP: "1.1.1.1", Port: "0", Quorum: "2"}, "").Once().Return(nil)
 			}
 
 			healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
 
-			err := healer.NewSentinelMonitor("0.0.0.0", "1.1.1.1", rf)
+			err := healer.NewSentinelMonitor(context.Background(), "0.0.0.0", "1.1.1.1", rf)
 
 			if errorExpected {
 				assert.Error(err)
@@ -419,14 +478,14 @@ func TestNewSentinelMonitorWithPort(t *testing.T) {
 
 			if test.errorOnMonitorRedis {
 				errorExpected = true
-				mr.On("MonitorRedisWithPort", "0.0.0.0", "1.1.1.1", "6379", "2", "").Once().Return(errors.New(""))
+				mr.On("MonitorRedisWithPort", mock.Anything, "0.0.0.0", "26379", "master0", redis.MonitorOptions{IP: "1.1.1.1", Port: "6379", Quorum: "2"}, "").Once().Return(errors.New(""))
 			} else {
-				mr.On("MonitorRedisWithPort", "0.0.0.0", "1.1.1.1", "6379", "2", "").Once().Return(nil)
+				mr.On("MonitorRedisWithPort", mock.Anything, "0.0.0.0", "26379", "master0", redis.MonitorOptions{IP: "1.1.1.1", Port: "6379", Quorum: "2"}, "").Once().Return(nil)
 			}
 
 			healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
 
-			err := healer.NewSentinelMonitorWithPort("0.0.0.0", "1.1.1.1", "6379", rf)
+			err := healer.NewSentinelMonitorWithPort(context.Background(), "0.0.0.0", "1.1.1.1", "6379", rf)
 
 			if errorExpected {
 				assert.Error(err)
@@ -438,3 +497,234 @@ func TestNewSentinelMonitorWithPort(t *testing.T) {
 		})
 	}
 }
+
+func TestSetRedisCustomConfigReplicating(t *testing.T) {
+	tests := []struct {
+		name            string
+		promote         bool
+		expectedConfigs []string
+	}{
+		{
+			name:            "authenticates to the source master while replicating",
+			expectedConfigs: []string{"replica-priority 0", "masterauth source-pass"},
+		},
+		{
+			name:            "authenticates to the local master once promoted",
+			promote:         true,
+			expectedConfigs: []string{"replica-priority 0", "masterauth local-pass"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateRF()
+			rf.Spec.Redis.CustomConfig = []string{"replica-priority 0"}
+			rf.Spec.Auth.SecretPath = "local-auth"
+			rf.Spec.ReplicaOf = &redisfailoverv1.ReplicaOfSettings{
+				RedisFailoverRef: redisfailoverv1.RedisFailoverReference{Namespace: "source-ns", Name: "source"},
+				MasterAuth:       redisfailoverv1.AuthSettings{SecretPath: "source-auth"},
+				Promote:          test.promote,
+			}
+
+			ms := &mK8SService.Services{}
+			ms.On("GetSecret", mock.Anything, namespace, "local-auth").Return(&corev1.Secret{Data: map[string][]byte{"password": []byte("local-pass")}}, nil)
+			ms.On("GetSecret", mock.Anything, namespace, "source-auth").Return(&corev1.Secret{Data: map[string][]byte{"password": []byte("source-pass")}}, nil)
+			mr := &mRedisService.Client{}
+			mr.On("SetCustomRedisConfig", mock.Anything, "0.0.0.0", "0", test.expectedConfigs, "local-pass").Once().Return(nil)
+
+			healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
+
+			assert.NoError(healer.SetRedisCustomConfig(context.Background(), "0.0.0.0", rf))
+			assert.Equal([]string{"replica-priority 0"}, rf.Spec.Redis.CustomConfig, "the spec must not be modified")
+			mr.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSetMostUpToDateAsMaster(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF()
+	pods := &corev1.PodList{
+		Items: []corev1.Pod{
+			{
+				Status: corev1.PodStatus{
+					PodIP: "0.0.0.0",
+					Phase: corev1.PodRunning,
+				},
+			},
+			{
+				Status: corev1.PodStatus{
+					PodIP: "1.1.1.1",
+					Phase: corev1.PodRunning,
+				},
+			},
+			{
+				Status: corev1.PodStatus{
+					PodIP: "2.2.2.2",
+					Phase: corev1.PodRunning,
+				},
+			},
+		},
+	}
+
+	ms := &mK8SService.Services{}
+	ms.On("GetStatefulSetPods", mock.Anything, namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
+	mr := &mRedisService.Client{}
+	mr.On("DisableReplicationTLS", mock.Anything, mock.Anything, "0", "").Times(3).Return(nil)
+	mr.On("GetReplicationOffset", mock.Anything, "0.0.0.0", "0", "").Once().Return(int64(100), nil)
+	mr.On("GetReplicationOffset", mock.Anything, "1.1.1.1", "0", "").Once().Return(int64(300), nil)
+	mr.On("GetReplicationOffset", mock.Anything, "2.2.2.2", "0", "").Once().Return(int64(200), nil)
+	mr.On("MakeMaster", mock.Anything, "1.1.1.1", "0", "").Once().Return(nil)
+	mr.On("MakeSlaveOfWithPort", mock.Anything, "0.0.0.0", "1.1.1.1", "0", "").Once().Return(nil)
+	mr.On("MakeSlaveOfWithPort", mock.Anything, "2.2.2.2", "1.1.1.1", "0", "").Once().Return(nil)
+
+	healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
+
+	assert.NoError(healer.SetMostUpToDateAsMaster(context.Background(), rf))
+	mr.AssertExpectations(t)
+}
+
+func TestNewSentinelMonitorSentinelAuth(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF()
+	rf.Spec.Sentinel.Port = 26380
+	rf.Spec.Sentinel.MasterName = "mymaster"
+	rf.Spec.Sentinel.Auth.SecretPath = "sentinel-auth"
+
+	ms := &mK8SService.Services{}
+	ms.On("GetSecret", mock.Anything, namespace, "sentinel-auth").Once().Return(&corev1.Secret{
+		Data: map[string][]byte{"password": []byte("sentinelpass")},
+	}, nil)
+	mr := &mRedisService.Client{}
+	mr.On("MonitorRedisWithPort", mock.Anything, "0.0.0.0", "26380", "mymaster", redis.MonitorOptions{IP: "1.1.1.1", Port: "0", Quorum: "2"}, "sentinelpass").Once().Return(nil)
+
+	healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
+
+	assert.NoError(healer.NewSentinelMonitor(context.Background(), "0.0.0.0", "1.1.1.1", rf))
+	mr.AssertExpectations(t)
+}
+
+func TestSetRedisPasswords(t *testing.T) {
+	tests := []struct {
+		name              string
+		previousPassword  string
+		expectedPasswords []string
+		pendingRedis      bool
+	}{
+		{
+			name:              "both passwords accepted while rotating",
+			previousPassword:  "oldpass",
+			expectedPasswords: []string{"newpass", "oldpass"},
+		},
+		{
+			name:              "redis still on the previous password",
+			previousPassword:  "oldpass",
+			expectedPasswords: []string{"newpass", "oldpass"},
+			pendingRedis:      true,
+		},
+		{
+			name:              "previous password dropped",
+			expectedPasswords: []string{"newpass"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateRF()
+			rf.Spec.Auth.SecretPath = "redis-auth"
+			data := map[string][]byte{"password": []byte("newpass")}
+			if test.previousPassword != "" {
+				data["previousPassword"] = []byte(test.previousPassword)
+			}
+			pods := &corev1.PodList{
+				Items: []corev1.Pod{
+					{
+						Status: corev1.PodStatus{
+							PodIP: "0.0.0.0",
+							Phase: corev1.PodRunning,
+						},
+					},
+					{
+						Status: corev1.PodStatus{
+							PodIP: "1.1.1.1",
+							Phase: corev1.PodPending,
+						},
+					},
+				},
+			}
+			sentinels := &corev1.PodList{
+				Items: []corev1.Pod{
+					{
+						Status: corev1.PodStatus{
+							PodIP: "2.2.2.2",
+							Phase: corev1.PodRunning,
+						},
+					},
+				},
+			}
+
+			ms := &mK8SService.Services{}
+			ms.On("GetSecret", mock.Anything, namespace, "redis-auth").Return(&corev1.Secret{Data: data}, nil)
+			ms.On("GetStatefulSetPods", mock.Anything, namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
+			ms.On("GetStatefulSetPods", mock.Anything, namespace, rfservice.GetSentinelName(rf)).Once().Return(sentinels, nil)
+			mr := &mRedisService.Client{}
+			if test.pendingRedis {
+				mr.On("SetDefaultUserPasswords", mock.Anything, "0.0.0.0", "0", test.expectedPasswords, "newpass").Once().Return(errors.New("WRONGPASS"))
+				mr.On("SetDefaultUserPasswords", mock.Anything, "0.0.0.0", "0", test.expectedPasswords, "oldpass").Once().Return(nil)
+			} else {
+				mr.On("SetDefaultUserPasswords", mock.Anything, "0.0.0.0", "0", test.expectedPasswords, "newpass").Once().Return(nil)
+			}
+			mr.On("SetCustomRedisConfig", mock.Anything, "0.0.0.0", "0", []string{"masterauth newpass"}, "newpass").Once().Return(nil)
+			mr.On("SetCustomSentinelConfig", mock.Anything, "2.2.2.2", "26379", "master0", []string{"auth-pass newpass"}, "").Once().Return(nil)
+
+			healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
+
+			assert.NoError(healer.SetRedisPasswords(context.Background(), rf))
+			mr.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSetPasswordRotationStatus(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF()
+
+	ms := &mK8SService.Services{}
+	ms.On("UpdateRedisFailoverStatus", mock.Anything, rf).Once().Return(nil)
+	mr := &mRedisService.Client{}
+
+	healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
+
+	assert.NoError(healer.SetPasswordRotationStatus(context.Background(), redisfailoverv1.PasswordRotationInProgress, "rotating", rf))
+	assert.Equal(redisfailoverv1.PasswordRotationInProgress, rf.Status.PasswordRotation.Phase)
+	assert.Equal("rotating", rf.Status.PasswordRotation.Message)
+	assert.False(rf.Status.PasswordRotation.LastTransitionTime.IsZero())
+	ms.AssertExpectations(t)
+}
+
+func TestSetExternalMasterStatus(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF()
+
+	ms := &mK8SService.Services{}
+	ms.On("UpdateRedisFailoverStatus", mock.Anything, rf).Twice().Return(nil)
+	mr := &mRedisService.Client{}
+
+	healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
+
+	assert.NoError(healer.SetExternalMasterStatus(context.Background(), "10.0.0.9", "6379", rf))
+	assert.Equal("10.0.0.9:6379", rf.Status.ExternalMaster)
+	// unchanged, the status isn't updated again
+	assert.NoError(healer.SetExternalMasterStatus(context.Background(), "10.0.0.9", "6379", rf))
+	assert.NoError(healer.SetExternalMasterStatus(context.Background(), "", "", rf))
+	assert.Equal("", rf.Status.ExternalMaster)
+	ms.AssertExpectations(t)
+}
@@ -0,0 +1,22 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
+	"github.com/spotahome/redis-operator/log"
+)
+
+// Logger returns the given logger with the namespace and name of the given RedisFailover and the fields of the
+// reconcile the given context belongs to, logging at the level of its log-level annotation when set
+func Logger(ctx context.Context, logger log.Logger, rf *redisfailoverv1.RedisFailover) log.Logger {
+	logger = log.FromContext(ctx, logger.WithField("redisfailover", rf.Name).WithField("namespace", rf.Namespace))
+	if level := rf.LogLevel(); level != "" {
+		// an unknown level is reported once per reconcile by the handler
+		if leveled, err := log.WithLevel(logger, log.Level(strings.ToLower(level))); err == nil {
+			logger = leveled
+		}
+	}
+	return logger
+}
@@ -0,0 +1,31 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+
+	mLog "github.com/spotahome/redis-operator/mocks/log"
+	rfservice "github.com/spotahome/redis-operator/operator/redisfailover/service"
+)
+
+func TestLoggerReconcileID(t *testing.T) {
+	rf := generateRF()
+
+	ml := &mLog.Logger{}
+	ml.On("WithField", "redisfailover", rf.Name).Return(ml)
+	ml.On("WithField", "namespace", rf.Namespace).Return(ml)
+	rfservice.Logger(context.Background(), ml, rf)
+	ml.AssertNotCalled(t, "WithFields", mock.Anything)
+
+	ctx, done := rfservice.StartReconcile(context.Background(), rf)
+	ml.On("WithFields", mock.MatchedBy(func(fields map[string]interface{}) bool {
+		_, ok := fields["reconcile"].(string)
+		return ok && fields["redisfailover"] == rf.Name
+	})).Once().Return(ml)
+	rfservice.Logger(ctx, ml, rf)
+	done(nil)
+	rfservice.Logger(context.Background(), ml, rf)
+	ml.AssertExpectations(t)
+}
@@ -0,0 +1,237 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
+	"github.com/spotahome/redis-operator/operator/redisfailover/util"
+)
+
+// operatorMetricsPrefix is the prefix of the metrics the operator exports about the redis failovers
+const operatorMetricsPrefix = "redis_operator_controller_"
+
+// Kinds of the Prometheus Operator objects created for the redis failovers
+var (
+	serviceMonitorKind = schema.GroupVersionKind{Group: "monitoring.coreos.com", Version: "v1", Kind: "ServiceMonitor"}
+	podMonitorKind     = schema.GroupVersionKind{Group: "monitoring.coreos.com", Version: "v1", Kind: "PodMonitor"}
+	prometheusRuleKind = schema.GroupVersionKind{Group: "monitoring.coreos.com", Version: "v1", Kind: "PrometheusRule"}
+
+	monitoringKinds = []schema.GroupVersionKind{serviceMonitorKind, podMonitorKind, prometheusRuleKind}
+)
+
+// EnsureMonitoring makes sure the ServiceMonitors or PodMonitors of the enabled exporters and the PrometheusRule
+// exist when the monitoring is enabled, and removes the ones no longer needed. The kinds whose CRD is not installed
+// are skipped
+func (r *RedisFailoverKubeClient) EnsureMonitoring(ctx context.Context, rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error {
+	desired := []*unstructured.Unstructured{}
+	if rf.Spec.Monitoring != nil {
+		desired = generateMonitoringObjects(rf, labels, ownerRefs)
+	}
+	for _, kind := range monitoringKinds {
+		if err := r.ensureMonitoringKind(ctx, rf, kind, desired); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ensureMonitoringKind creates or updates the desired objects of the given kind and deletes the other ones of the redis failover
+func (r *RedisFailoverKubeClient) ensureMonitoringKind(ctx context.Context, rf *redisfailoverv1.RedisFailover, kind schema.GroupVersionKind, desired []*unstructured.Unstructured) error {
+	wanted := []*unstructured.Unstructured{}
+	for _, object := range desired {
+		if object.GroupVersionKind() == kind {
+			wanted = append(wanted, object)
+		}
+	}
+
+	selector := labels.SelectorFromSet(generateMonitoringSelectorLabels(rf)).String()
+	current, err := r.K8SService.ListUnstructured(ctx, kind, rf.Namespace, selector)
+	if errors.IsNotFound(err) {
+		// The CRD is not installed, so there is nothing to remove either
+		if len(wanted) > 0 {
+			Logger(ctx, r.logger, rf).Warningf("The %s CRD of the Prometheus Operator is not installed, skipping the monitoring objects of this kind", kind.Kind)
+		}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	names := map[string]bool{}
+	for _, object := range wanted {
+		err := r.K8SService.CreateOrUpdateUnstructured(ctx, rf.Namespace, object)
+		r.setEnsureOperationMetrics(rf.Namespace, object.GetName(), kind.Kind, rf.Name, err)
+		if err != nil {
+			return err
+		}
+		names[object.GetName()] = true
+	}
+
+	for _, object := range current.Items {
+		if names[object.GetName()] || !metav1.IsControlledBy(&object, rf) {
+			continue
+		}
+		Logger(ctx, r.logger, rf).Infof("Removing %s %s", kind.Kind, object.GetName())
+		if err := r.K8SService.DeleteUnstructured(ctx, kind, rf.Namespace, object.GetName()); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// generateMonitoringSelectorLabels returns the labels every monitoring object of the redis failover has
+func generateMonitoringSelectorLabels(rf *redisfailoverv1.RedisFailover) map[string]string {
+	return map[string]string{
+		"app.kubernetes.io/name":    rf.Name,
+		"app.kubernetes.io/part-of": appLabel,
+	}
+}
+
+// generateMonitoringObjects returns a monitor for every enabled exporter and, unless the alerts are disabled, the PrometheusRule
+func generateMonitoringObjects(rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) []*unstructured.Unstructured {
+	objects := []*unstructured.Unstructured{}
+	if rf.Spec.Redis.Exporter.Enabled {
+		objects = append(objects, generateMonitor(rf, GetRedisName(rf), redisRoleName, labels, ownerRefs))
+	}
+	if rf.SentinelsAllowed() && rf.Spec.Sentinel.Exporter.Enabled {
+		objects = append(objects, generateMonitor(rf, GetSentinelName(rf), sentinelRoleName, labels, ownerRefs))
+	}
+	if GetProxy(rf).Component() == predixyRoleName && rf.Spec.Predixy.Exporter.Enabled {
+		objects = append(objects, generateMonitor(rf, GetPredixyName(rf), predixyRoleName, labels, ownerRefs))
+	}
+	if !rf.Spec.Monitoring.Alerts.Disabled {
+		objects = append(objects, generatePrometheusRule(rf, labels, ownerRefs))
+	}
+	return objects
+}
+
+// generateMonitor returns the ServiceMonitor scraping the exporter port of the service of the component, or the
+// PodMonitor scraping the exporter container of its pods
+func generateMonitor(rf *redisfailoverv1.RedisFailover, name string, component string, labels map[string]string, ownerRefs []metav1.OwnerReference) *unstructured.Unstructured {
+	monitoring := rf.Spec.Monitoring
+	selectorLabels := generateSelectorLabels(component, rf.Name)
+
+	kind := serviceMonitorKind
+	endpointsKey := "endpoints"
+	endpoint := map[string]interface{}{
+		"port": exporterPortName,
+	}
+	if monitoring.Type == redisfailoverv1.MonitorTypePodMonitor {
+		kind = podMonitorKind
+		endpointsKey = "podMetricsEndpoints"
+		endpoint["port"] = exporterContainerPortName
+	}
+	if monitoring.Interval != "" {
+		endpoint["interval"] = monitoring.Interval
+	}
+
+	object := newMonitoringObject(rf, kind, name, util.MergeLabels(monitoring.Labels, labels, selectorLabels), ownerRefs)
+	object.Object["spec"] = map[string]interface{}{
+		"selector": map[string]interface{}{
+			"matchLabels": toUnstructuredMap(selectorLabels),
+		},
+		endpointsKey: []interface{}{endpoint},
+	}
+	return object
+}
+
+// generatePrometheusRule returns the PrometheusRule with the alerts on the redis failover. The ones on the master,
+// the replication and the reconciles use the metrics of the operator, the ones on the sentinel quorum and the
+// memory need the sentinel and redis exporters
+func generatePrometheusRule(rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) *unstructured.Unstructured {
+	alerts := rf.Spec.Monitoring.Alerts
+	operatorSelector := fmt.Sprintf(`namespace=%q,name=%q`, rf.Namespace, rf.Name)
+
+	// the node metrics are missing when no redis of the failover was ever reached
+	rules := []interface{}{
+		generateAlert("RedisFailoverNoMaster", "critical", alerts,
+			fmt.Sprintf(`sum(%[1]sredis_node_role{%[2]s,role="master"}) < 1 or absent(%[1]sredis_node_role{%[2]s,role="master"})`, operatorMetricsPrefix, operatorSelector),
+			alerts.NoMasterFor,
+			fmt.Sprintf("RedisFailover %s/%s has no master", rf.Namespace, rf.Name),
+			"None of the redises reports the master role, the writes are failing."),
+		generateAlert("RedisFailoverReplicaLinkDown", "warning", alerts,
+			fmt.Sprintf(`%sredis_node_master_link_up{%s} == 0`, operatorMetricsPrefix, operatorSelector),
+			alerts.ReplicaLinkDownFor,
+			fmt.Sprintf("A replica of RedisFailover %s/%s is disconnected from its master", rf.Namespace, rf.Name),
+			"The link of {{ $labels.pod }} to its master is down, it is not replicating."),
+	}
+
+	if rf.SentinelsAllowed() && rf.Spec.Sentinel.Exporter.Enabled {
+		rules = append(rules, generateAlert("RedisFailoverSentinelQuorumLost", "critical", alerts,
+			fmt.Sprintf(`redis_sentinel_master_ckquorum_status{namespace=%q,pod=~"%s-[0-9]+"} == 0`, rf.Namespace, GetSentinelName(rf)),
+			alerts.SentinelQuorumLostFor,
+			fmt.Sprintf("The sentinels of RedisFailover %s/%s can't reach the quorum", rf.Namespace, rf.Name),
+			"{{ $labels.pod }} can't reach enough sentinels to authorize a failover: {{ $labels.message }}."))
+	}
+
+	if rf.Spec.Redis.Exporter.Enabled {
+		redisSets := []string{}
+		for _, set := range getRedisStatefulSets(rf) {
+			redisSets = append(redisSets, set.name)
+		}
+		redisSelector := fmt.Sprintf(`namespace=%q,pod=~"(%s)-[0-9]+"`, rf.Namespace, strings.Join(redisSets, "|"))
+		rules = append(rules, generateAlert("RedisFailoverMemoryNearMaxmemory", "warning", alerts,
+			fmt.Sprintf(`100 * redis_memory_used_bytes{%[1]s} / (redis_memory_max_bytes{%[1]s} > 0) > %[2]d`, redisSelector, alerts.MemoryUsagePercent),
+			alerts.MemoryUsageFor,
+			fmt.Sprintf("A redis of RedisFailover %s/%s is near its maxmemory", rf.Namespace, rf.Name),
+			"{{ $labels.pod }} uses {{ $value | humanize }}% of its maxmemory."))
+	}
+
+	rules = append(rules, generateAlert("RedisFailoverClusterError", "warning", alerts,
+		fmt.Sprintf(`%scluster_ok{%s} == 0`, operatorMetricsPrefix, operatorSelector),
+		alerts.ClusterErrorFor,
+		fmt.Sprintf("The operator fails to reconcile RedisFailover %s/%s", rf.Namespace, rf.Name),
+		"The reconciles of the RedisFailover are failing, check the logs and the events of the operator."))
+
+	name := GetPrometheusRuleName(rf)
+	object := newMonitoringObject(rf, prometheusRuleKind, name, util.MergeLabels(rf.Spec.Monitoring.Labels, labels, generateMonitoringSelectorLabels(rf)), ownerRefs)
+	object.Object["spec"] = map[string]interface{}{
+		"groups": []interface{}{
+			map[string]interface{}{
+				"name":  name,
+				"rules": rules,
+			},
+		},
+	}
+	return object
+}
+
+func generateAlert(name string, severity string, alerts redisfailoverv1.AlertsSettings, expr string, duration string, summary string, description string) map[string]interface{} {
+	return map[string]interface{}{
+		"alert":  name,
+		"expr":   expr,
+		"for":    duration,
+		"labels": toUnstructuredMap(util.MergeLabels(map[string]string{"severity": severity}, alerts.Labels)),
+		"annotations": map[string]interface{}{
+			"summary":     summary,
+			"description": description,
+		},
+	}
+}
+
+func newMonitoringObject(rf *redisfailoverv1.RedisFailover, kind schema.GroupVersionKind, name string, labels map[string]string, ownerRefs []metav1.OwnerReference) *unstructured.Unstructured {
+	object := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	object.SetGroupVersionKind(kind)
+	object.SetName(name)
+	object.SetNamespace(rf.Namespace)
+	object.SetLabels(labels)
+	object.SetOwnerReferences(ownerRefs)
+	return object
+}
+
+// toUnstructuredMap converts a map of strings to the type the unstructured objects hold
+func toUnstructuredMap(m map[string]string) map[string]interface{} {
+	out := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
@@ -0,0 +1,236 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	kubeerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
+	"github.com/spotahome/redis-operator/log"
+	"github.com/spotahome/redis-operator/metrics"
+	mK8SService "github.com/spotahome/redis-operator/mocks/service/k8s"
+	rfservice "github.com/spotahome/redis-operator/operator/redisfailover/service"
+)
+
+var (
+	serviceMonitorKind = schema.GroupVersionKind{Group: "monitoring.coreos.com", Version: "v1", Kind: "ServiceMonitor"}
+	podMonitorKind     = schema.GroupVersionKind{Group: "monitoring.coreos.com", Version: "v1", Kind: "PodMonitor"}
+	prometheusRuleKind = schema.GroupVersionKind{Group: "monitoring.coreos.com", Version: "v1", Kind: "PrometheusRule"}
+)
+
+const monitoringSelector = "app.kubernetes.io/name=test,app.kubernetes.io/part-of=redis-failover"
+
+func generateMonitoringRF() *redisfailoverv1.RedisFailover {
+	rf := generateRF()
+	rf.Spec.Redis.Exporter.Enabled = true
+	rf.Spec.Sentinel.Exporter.Enabled = true
+	rf.Spec.Monitoring = &redisfailoverv1.MonitoringSettings{
+		Type:     redisfailoverv1.MonitorTypeServiceMonitor,
+		Interval: "30s",
+		Labels:   map[string]string{"release": "prometheus"},
+		Alerts: redisfailoverv1.AlertsSettings{
+			NoMasterFor:           "1m",
+			ReplicaLinkDownFor:    "5m",
+			SentinelQuorumLostFor: "5m",
+			MemoryUsagePercent:    80,
+			MemoryUsageFor:        "5m",
+			ClusterErrorFor:       "10m",
+		},
+	}
+	return rf
+}
+
+func newMonitoringObject(kind schema.GroupVersionKind, name string, controller bool) unstructured.Unstructured {
+	object := unstructured.Unstructured{}
+	object.SetGroupVersionKind(kind)
+	object.SetName(name)
+	object.SetOwnerReferences([]metav1.OwnerReference{{Name: "testing", Controller: &controller}})
+	return object
+}
+
+func TestEnsureMonitoring(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateMonitoringRF()
+	ownerRefs := []metav1.OwnerReference{{Name: "testing"}}
+
+	created := map[string]*unstructured.Unstructured{}
+	ms := &mK8SService.Services{}
+	ms.On("ListUnstructured", mock.Anything, serviceMonitorKind, namespace, monitoringSelector).Once().Return(&unstructured.UnstructuredList{}, nil)
+	ms.On("ListUnstructured", mock.Anything, podMonitorKind, namespace, monitoringSelector).Once().Return(&unstructured.UnstructuredList{
+		Items: []unstructured.Unstructured{newMonitoringObject(podMonitorKind, "rfr-test", true)},
+	}, nil)
+	ms.On("ListUnstructured", mock.Anything, prometheusRuleKind, namespace, monitoringSelector).Once().Return(&unstructured.UnstructuredList{}, nil)
+	ms.On("CreateOrUpdateUnstructured", mock.Anything, namespace, mock.Anything).Times(3).Run(func(args mock.Arguments) {
+		object := args.Get(2).(*unstructured.Unstructured)
+		created[object.GetKind()+"/"+object.GetName()] = object
+	}).Return(nil)
+	ms.On("DeleteUnstructured", mock.Anything, podMonitorKind, namespace, "rfr-test").Once().Return(nil)
+
+	client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+	assert.NoError(client.EnsureMonitoring(context.Background(), rf, map[string]string{"redisfailovers.databases.spotahome.com/name": name}, ownerRefs))
+	ms.AssertExpectations(t)
+
+	if assert.Len(created, 3) {
+		assert.Contains(created, "ServiceMonitor/rfs-test")
+		assert.Contains(created, "PrometheusRule/rfa-test")
+
+		expMonitor := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "monitoring.coreos.com/v1",
+			"kind":       "ServiceMonitor",
+			"metadata": map[string]interface{}{
+				"name":      "rfr-test",
+				"namespace": namespace,
+				"labels": map[string]interface{}{
+					"app.kubernetes.io/component":                 "redis",
+					"app.kubernetes.io/name":                      name,
+					"app.kubernetes.io/part-of":                   "redis-failover",
+					"redisfailovers.databases.spotahome.com/name": name,
+					"release": "prometheus",
+				},
+				"ownerReferences": []interface{}{
+					map[string]interface{}{"apiVersion": "", "kind": "", "name": "testing", "uid": ""},
+				},
+			},
+			"spec": map[string]interface{}{
+				"selector": map[string]interface{}{
+					"matchLabels": map[string]interface{}{
+						"app.kubernetes.io/component": "redis",
+						"app.kubernetes.io/name":      name,
+						"app.kubernetes.io/part-of":   "redis-failover",
+					},
+				},
+				"endpoints": []interface{}{
+					map[string]interface{}{"port": "http-metrics", "interval": "30s"},
+				},
+			},
+		}}
+		assert.Equal(expMonitor, created["ServiceMonitor/rfr-test"])
+	}
+}
+
+func TestEnsureMonitoringPodMonitors(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateMonitoringRF()
+	rf.Spec.Redis.Exporter.Enabled = false
+	rf.Spec.Sentinel.Exporter.Enabled = false
+	rf.Spec.Predixy.Exporter.Enabled = true
+	rf.Spec.Monitoring.Type = redisfailoverv1.MonitorTypePodMonitor
+	rf.Spec.Monitoring.Interval = ""
+	rf.Spec.Monitoring.Alerts.Disabled = true
+
+	var monitor *unstructured.Unstructured
+	ms := &mK8SService.Services{}
+	ms.On("ListUnstructured", mock.Anything, serviceMonitorKind, namespace, monitoringSelector).Once().Return(&unstructured.UnstructuredList{
+		Items: []unstructured.Unstructured{newMonitoringObject(serviceMonitorKind, "rfp-test", true)},
+	}, nil)
+	ms.On("ListUnstructured", mock.Anything, podMonitorKind, namespace, monitoringSelector).Once().Return(&unstructured.UnstructuredList{}, nil)
+	ms.On("ListUnstructured", mock.Anything, prometheusRuleKind, namespace, monitoringSelector).Once().Return(&unstructured.UnstructuredList{
+		Items: []unstructured.Unstructured{newMonitoringObject(prometheusRuleKind, "rfa-test", true)},
+	}, nil)
+	ms.On("CreateOrUpdateUnstructured", mock.Anything, namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+		monitor = args.Get(2).(*unstructured.Unstructured)
+	}).Return(nil)
+	ms.On("DeleteUnstructured", mock.Anything, serviceMonitorKind, namespace, "rfp-test").Once().Return(nil)
+	ms.On("DeleteUnstructured", mock.Anything, prometheusRuleKind, namespace, "rfa-test").Once().Return(nil)
+
+	client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+	assert.NoError(client.EnsureMonitoring(context.Background(), rf, nil, nil))
+	ms.AssertExpectations(t)
+
+	if assert.NotNil(monitor) {
+		assert.Equal("PodMonitor", monitor.GetKind())
+		assert.Equal("rfp-test", monitor.GetName())
+		endpoints, _, _ := unstructured.NestedSlice(monitor.Object, "spec", "podMetricsEndpoints")
+		assert.Equal([]interface{}{map[string]interface{}{"port": "metrics"}}, endpoints)
+	}
+}
+
+func TestEnsureMonitoringAlerts(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateMonitoringRF()
+	rf.Spec.Sentinel.Exporter.Enabled = false
+	rf.Spec.Monitoring.Alerts.Labels = map[string]string{"severity": "page", "team": "storage"}
+
+	var rule *unstructured.Unstructured
+	ms := &mK8SService.Services{}
+	ms.On("ListUnstructured", mock.Anything, mock.Anything, namespace, monitoringSelector).Times(3).Return(&unstructured.UnstructuredList{}, nil)
+	ms.On("CreateOrUpdateUnstructured", mock.Anything, namespace, mock.Anything).Twice().Run(func(args mock.Arguments) {
+		if object := args.Get(2).(*unstructured.Unstructured); object.GetKind() == "PrometheusRule" {
+			rule = object
+		}
+	}).Return(nil)
+
+	client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+	assert.NoError(client.EnsureMonitoring(context.Background(), rf, nil, nil))
+	ms.AssertExpectations(t)
+
+	if !assert.NotNil(rule) {
+		return
+	}
+	groups, _, _ := unstructured.NestedSlice(rule.Object, "spec", "groups")
+	if !assert.Len(groups, 1) {
+		return
+	}
+	rules := groups[0].(map[string]interface{})["rules"].([]interface{})
+	alerts := map[string]map[string]interface{}{}
+	for _, r := range rules {
+		alert := r.(map[string]interface{})
+		alerts[alert["alert"].(string)] = alert
+	}
+
+	// The sentinel quorum is only known through the sentinel exporter
+	assert.Len(alerts, 4)
+	assert.NotContains(alerts, "RedisFailoverSentinelQuorumLost")
+	assert.Equal(`sum(redis_operator_controller_redis_node_role{namespace="testns",name="test",role="master"}) < 1 or absent(redis_operator_controller_redis_node_role{namespace="testns",name="test",role="master"})`, alerts["RedisFailoverNoMaster"]["expr"])
+	assert.Equal("1m", alerts["RedisFailoverNoMaster"]["for"])
+	assert.Equal(`redis_operator_controller_redis_node_master_link_up{namespace="testns",name="test"} == 0`, alerts["RedisFailoverReplicaLinkDown"]["expr"])
+	assert.Equal(`100 * redis_memory_used_bytes{namespace="testns",pod=~"(rfr-test)-[0-9]+"} / (redis_memory_max_bytes{namespace="testns",pod=~"(rfr-test)-[0-9]+"} > 0) > 80`, alerts["RedisFailoverMemoryNearMaxmemory"]["expr"])
+	assert.Equal(`redis_operator_controller_cluster_ok{namespace="testns",name="test"} == 0`, alerts["RedisFailoverClusterError"]["expr"])
+	assert.Equal("10m", alerts["RedisFailoverClusterError"]["for"])
+	assert.Equal(map[string]interface{}{"severity": "page", "team": "storage"}, alerts["RedisFailoverClusterError"]["labels"])
+}
+
+func TestEnsureMonitoringWithoutCRDs(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateMonitoringRF()
+
+	ms := &mK8SService.Services{}
+	ms.On("ListUnstructured", mock.Anything, mock.Anything, namespace, monitoringSelector).Times(3).Return(nil, kubeerrors.NewNotFound(schema.GroupResource{}, ""))
+
+	client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+	assert.NoError(client.EnsureMonitoring(context.Background(), rf, nil, nil))
+	ms.AssertExpectations(t)
+	ms.AssertNotCalled(t, "CreateOrUpdateUnstructured", mock.Anything, mock.Anything)
+}
+
+func TestEnsureMonitoringDisabled(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateRF()
+
+	ms := &mK8SService.Services{}
+	ms.On("ListUnstructured", mock.Anything, serviceMonitorKind, namespace, monitoringSelector).Once().Return(&unstructured.UnstructuredList{
+		Items: []unstructured.Unstructured{
+			newMonitoringObject(serviceMonitorKind, "rfr-test", true),
+			newMonitoringObject(serviceMonitorKind, "user-monitor", false),
+		},
+	}, nil)
+	ms.On("ListUnstructured", mock.Anything, podMonitorKind, namespace, monitoringSelector).Once().Return(&unstructured.UnstructuredList{}, nil)
+	ms.On("ListUnstructured", mock.Anything, prometheusRuleKind, namespace, monitoringSelector).Once().Return(nil, kubeerrors.NewNotFound(schema.GroupResource{}, ""))
+	ms.On("DeleteUnstructured", mock.Anything, serviceMonitorKind, namespace, "rfr-test").Once().Return(nil)
+
+	client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+	assert.NoError(client.EnsureMonitoring(context.Background(), rf, nil, nil))
+	ms.AssertExpectations(t)
+	ms.AssertNotCalled(t, "CreateOrUpdateUnstructured", mock.Anything, mock.Anything)
+}
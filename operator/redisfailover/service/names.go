@@ -2,6 +2,7 @@ package service
 
 import (
 	"fmt"
+	"strings"
 
 	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
 )
@@ -19,6 +20,19 @@ func GetRedisName(rf *redisfailoverv1.RedisFailover) string {
 	return generateName(redisName, rf.Name)
 }
 
+// GetRedisZoneName returns the name of the redis statefulset of a zone
+func GetRedisZoneName(rf *redisfailoverv1.RedisFailover, zone string) string {
+	return fmt.Sprintf("%s-%s", GetRedisName(rf), zone)
+}
+
+// GetPodStatefulSetName returns the name of the statefulset a pod belongs to
+func GetPodStatefulSetName(podName string) string {
+	if i := strings.LastIndex(podName, "-"); i > 0 {
+		return podName[:i]
+	}
+	return podName
+}
+
 // GetRedisShutdownName returns the name for redis resources
 func GetRedisShutdownName(rf *redisfailoverv1.RedisFailover) string {
 	return generateName(redisShutdownName, rf.Name)
@@ -29,6 +43,16 @@ func GetRedisReadinessName(rf *redisfailoverv1.RedisFailover) string {
 	return generateName(redisReadinessName, rf.Name)
 }
 
+// GetRedisMasterName returns the name of the service pointing to the redis master
+func GetRedisMasterName(rf *redisfailoverv1.RedisFailover) string {
+	return generateName(redisMasterName, rf.Name)
+}
+
+// GetRedisReplicasName returns the name of the service pointing to the redis replicas
+func GetRedisReplicasName(rf *redisfailoverv1.RedisFailover) string {
+	return generateName(redisReplicasName, rf.Name)
+}
+
 // GetSentinelName returns the name for sentinel resources
 func GetSentinelName(rf *redisfailoverv1.RedisFailover) string {
 	return generateName(sentinelName, rf.Name)
@@ -38,6 +62,16 @@ func GetPredixyName(rf *redisfailoverv1.RedisFailover) string {
 	return generateName(predixyName, rf.Name)
 }
 
+// GetEnvoyName returns the name for envoy resources
+func GetEnvoyName(rf *redisfailoverv1.RedisFailover) string {
+	return generateName(envoyName, rf.Name)
+}
+
+// GetPrometheusRuleName returns the name of the PrometheusRule alerting on the redis failover
+func GetPrometheusRuleName(rf *redisfailoverv1.RedisFailover) string {
+	return generateName(prometheusRuleName, rf.Name)
+}
+
 func generateName(typeName, metaName string) string {
 	return fmt.Sprintf("%s%s-%s", baseName, typeName, metaName)
 }
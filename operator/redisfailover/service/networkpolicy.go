@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
+	"github.com/spotahome/redis-operator/operator/redisfailover/util"
+)
+
+// EnsureNetworkPolicies makes sure the NetworkPolicies of redis, sentinel and the proxy exist when the network
+// policy is enabled, and removes the ones no longer needed
+func (r *RedisFailoverKubeClient) EnsureNetworkPolicies(ctx context.Context, rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) error {
+	desired := map[string]*networkingv1.NetworkPolicy{}
+	if rf.Spec.NetworkPolicy != nil {
+		for _, np := range generateNetworkPolicies(rf, labels, ownerRefs) {
+			desired[np.Name] = np
+		}
+	}
+
+	names := []string{GetRedisName(rf), GetSentinelName(rf), GetPredixyName(rf), GetEnvoyName(rf)}
+	for _, name := range names {
+		if np, ok := desired[name]; ok {
+			err := r.K8SService.CreateOrUpdateNetworkPolicy(ctx, rf.Namespace, np)
+			r.setEnsureOperationMetrics(np.Namespace, np.Name, "NetworkPolicy", rf.Name, err)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		// If the network policy exists (no get error), delete it
+		if _, err := r.K8SService.GetNetworkPolicy(ctx, rf.Namespace, name); err == nil {
+			if err := r.K8SService.DeleteNetworkPolicy(ctx, rf.Namespace, name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// generateNetworkPolicies returns the NetworkPolicies letting in the traffic the redises, the sentinels and the proxy
+// need: the replication between the redises, the sentinels monitoring the redises and talking to each other, the proxy
+// reaching its backends, the operator checking and healing them, the scrapes of the exporters and the clients
+func generateNetworkPolicies(rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) []*networkingv1.NetworkPolicy {
+	settings := rf.Spec.NetworkPolicy
+	proxy := GetProxy(rf)
+
+	redisPods := componentPeer(rf, redisRoleName)
+	sentinelPods := componentPeer(rf, sentinelRoleName)
+	proxyPods := componentPeer(rf, proxy.Component())
+	operator := settingsPeer(settings.Operator)
+	metrics := settingsPeer(settings.Metrics)
+	clients := settingsPeer(settings.Clients.NetworkPolicyPeerSettings)
+
+	redisFrom := []networkingv1.NetworkPolicyPeer{redisPods, proxyPods, operator}
+	if rf.SentinelsAllowed() {
+		redisFrom = append(redisFrom, sentinelPods)
+	}
+	if settings.Clients.Redis {
+		redisFrom = append(redisFrom, clients)
+	}
+	redisRules := []networkingv1.NetworkPolicyIngressRule{
+		ingressRule(rf.Spec.Redis.Port, redisFrom, rf.Spec.ExternalAccess != nil),
+	}
+	if rf.Spec.Redis.Exporter.Enabled {
+		redisRules = append(redisRules, ingressRule(exporterPort, []networkingv1.NetworkPolicyPeer{metrics}, false))
+	}
+	policies := []*networkingv1.NetworkPolicy{
+		generateNetworkPolicy(rf, GetRedisName(rf), redisRoleName, labels, ownerRefs, redisRules),
+	}
+
+	if rf.SentinelsAllowed() {
+		// the redises ask the sentinels to fail the master over in their preStop
+		sentinelFrom := []networkingv1.NetworkPolicyPeer{sentinelPods, redisPods, operator, clients}
+		if !proxy.FollowsMaster() {
+			sentinelFrom = append(sentinelFrom, proxyPods)
+		}
+		sentinelRules := []networkingv1.NetworkPolicyIngressRule{
+			ingressRule(rf.Spec.Sentinel.Port, sentinelFrom, rf.Spec.ExternalAccess != nil),
+		}
+		if rf.Spec.Sentinel.Exporter.Enabled {
+			sentinelRules = append(sentinelRules, ingressRule(sentinelExporterPort, []networkingv1.NetworkPolicyPeer{metrics}, false))
+		}
+		policies = append(policies, generateNetworkPolicy(rf, GetSentinelName(rf), sentinelRoleName, labels, ownerRefs, sentinelRules))
+	}
+
+	// The proxy is reached on the ports of its service, the exporter one by the scrapers and the others by the clients
+	// and the operator
+	proxyRules := []networkingv1.NetworkPolicyIngressRule{}
+	for _, port := range proxy.Service(rf, labels, ownerRefs).Spec.Ports {
+		target := port.Port
+		if port.TargetPort.Type == intstr.Int && port.TargetPort.IntVal != 0 {
+			target = port.TargetPort.IntVal
+		}
+		from := []networkingv1.NetworkPolicyPeer{clients, operator}
+		if port.Name == exporterPortName {
+			from = []networkingv1.NetworkPolicyPeer{metrics}
+		}
+		proxyRules = append(proxyRules, ingressRule(target, from, false))
+	}
+	policies = append(policies, generateNetworkPolicy(rf, proxy.Name(rf), proxy.Component(), labels, ownerRefs, proxyRules))
+
+	return policies
+}
+
+func generateNetworkPolicy(rf *redisfailoverv1.RedisFailover, name string, component string, labels map[string]string, ownerRefs []metav1.OwnerReference, rules []networkingv1.NetworkPolicyIngressRule) *networkingv1.NetworkPolicy {
+	selectorLabels := generateSelectorLabels(component, rf.Name)
+	return &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       rf.Namespace,
+			Labels:          util.MergeLabels(labels, selectorLabels),
+			OwnerReferences: ownerRefs,
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{
+				MatchLabels: selectorLabels,
+			},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+			Ingress:     rules,
+		},
+	}
+}
+
+// ingressRule lets the given peers reach the TCP port, or any source when open is set
+func ingressRule(port int32, from []networkingv1.NetworkPolicyPeer, open bool) networkingv1.NetworkPolicyIngressRule {
+	protocol := corev1.ProtocolTCP
+	target := intstr.FromInt(int(port))
+	rule := networkingv1.NetworkPolicyIngressRule{
+		Ports: []networkingv1.NetworkPolicyPort{{Protocol: &protocol, Port: &target}},
+	}
+	if !open {
+		rule.From = from
+	}
+	return rule
+}
+
+// componentPeer selects the pods of a component of the redis failover
+func componentPeer(rf *redisfailoverv1.RedisFailover, component string) networkingv1.NetworkPolicyPeer {
+	return networkingv1.NetworkPolicyPeer{
+		PodSelector: &metav1.LabelSelector{
+			MatchLabels: generateSelectorLabels(component, rf.Name),
+		},
+	}
+}
+
+func settingsPeer(settings redisfailoverv1.NetworkPolicyPeerSettings) networkingv1.NetworkPolicyPeer {
+	return networkingv1.NetworkPolicyPeer{
+		NamespaceSelector: settings.NamespaceSelector.DeepCopy(),
+		PodSelector:       settings.PodSelector.DeepCopy(),
+	}
+}
@@ -0,0 +1,166 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	kubeerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
+	"github.com/spotahome/redis-operator/log"
+	"github.com/spotahome/redis-operator/metrics"
+	mK8SService "github.com/spotahome/redis-operator/mocks/service/k8s"
+	rfservice "github.com/spotahome/redis-operator/operator/redisfailover/service"
+)
+
+var (
+	operatorPeer = networkingv1.NetworkPolicyPeer{
+		NamespaceSelector: &metav1.LabelSelector{},
+		PodSelector:       &metav1.LabelSelector{MatchLabels: map[string]string{"app": "redisoperator"}},
+	}
+	metricsPeer = networkingv1.NetworkPolicyPeer{
+		NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"name": "monitoring"}},
+	}
+	clientsPeer = networkingv1.NetworkPolicyPeer{
+		PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"redis-client": "true"}},
+	}
+)
+
+func generateNetworkPolicyRF() *redisfailoverv1.RedisFailover {
+	rf := generateRF()
+	rf.Spec.Redis.Port = 6379
+	rf.Spec.NetworkPolicy = &redisfailoverv1.NetworkPolicySettings{
+		Operator: redisfailoverv1.NetworkPolicyPeerSettings{NamespaceSelector: operatorPeer.NamespaceSelector, PodSelector: operatorPeer.PodSelector},
+		Metrics:  redisfailoverv1.NetworkPolicyPeerSettings{NamespaceSelector: metricsPeer.NamespaceSelector},
+		Clients: redisfailoverv1.NetworkPolicyClientsSettings{
+			NetworkPolicyPeerSettings: redisfailoverv1.NetworkPolicyPeerSettings{PodSelector: clientsPeer.PodSelector},
+		},
+	}
+	return rf
+}
+
+func componentPeer(component string) networkingv1.NetworkPolicyPeer {
+	return networkingv1.NetworkPolicyPeer{
+		PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{
+			"app.kubernetes.io/name":      name,
+			"app.kubernetes.io/component": component,
+			"app.kubernetes.io/part-of":   "redis-failover",
+		}},
+	}
+}
+
+func tcpRule(port int, from ...networkingv1.NetworkPolicyPeer) networkingv1.NetworkPolicyIngressRule {
+	protocol := corev1.ProtocolTCP
+	target := intstr.FromInt(port)
+	rule := networkingv1.NetworkPolicyIngressRule{
+		Ports: []networkingv1.NetworkPolicyPort{{Protocol: &protocol, Port: &target}},
+	}
+	if len(from) > 0 {
+		rule.From = from
+	}
+	return rule
+}
+
+// ensureNetworkPolicies runs EnsureNetworkPolicies on the given redis failover and returns the ingress rules of the
+// policies it creates or updates by their name
+func ensureNetworkPolicies(t *testing.T, rf *redisfailoverv1.RedisFailover, deleted ...string) map[string][]networkingv1.NetworkPolicyIngressRule {
+	rules := map[string][]networkingv1.NetworkPolicyIngressRule{}
+	ms := &mK8SService.Services{}
+	ms.On("CreateOrUpdateNetworkPolicy", mock.Anything, namespace, mock.Anything).Maybe().Run(func(args mock.Arguments) {
+		np := args.Get(2).(*networkingv1.NetworkPolicy)
+		rules[np.Name] = np.Spec.Ingress
+	}).Return(nil)
+	for _, name := range deleted {
+		ms.On("GetNetworkPolicy", mock.Anything, namespace, name).Once().Return(&networkingv1.NetworkPolicy{}, nil)
+		ms.On("DeleteNetworkPolicy", mock.Anything, namespace, name).Once().Return(nil)
+	}
+	ms.On("GetNetworkPolicy", mock.Anything, namespace, mock.Anything).Maybe().Return(nil, kubeerrors.NewNotFound(schema.GroupResource{}, ""))
+
+	client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+	assert.NoError(t, client.EnsureNetworkPolicies(context.Background(), rf, nil, []metav1.OwnerReference{}))
+	ms.AssertExpectations(t)
+	return rules
+}
+
+func TestEnsureNetworkPolicies(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateNetworkPolicyRF()
+	rf.Spec.Redis.Exporter.Enabled = true
+	rules := ensureNetworkPolicies(t, rf)
+
+	assert.Len(rules, 3)
+	assert.Equal([]networkingv1.NetworkPolicyIngressRule{
+		tcpRule(6379, componentPeer("redis"), componentPeer("predixy"), operatorPeer, componentPeer("sentinel")),
+		tcpRule(9121, metricsPeer),
+	}, rules["rfr-test"])
+	assert.Equal([]networkingv1.NetworkPolicyIngressRule{
+		tcpRule(26379, componentPeer("sentinel"), componentPeer("redis"), operatorPeer, clientsPeer, componentPeer("predixy")),
+	}, rules["rfs-test"])
+	assert.Equal([]networkingv1.NetworkPolicyIngressRule{
+		tcpRule(12120, clientsPeer, operatorPeer),
+	}, rules["rfp-test"])
+}
+
+func TestEnsureNetworkPoliciesEnvoy(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateNetworkPolicyRF()
+	rf.Spec.Proxy.Type = redisfailoverv1.ProxyTypeEnvoy
+	rf.Spec.NetworkPolicy.Clients.Redis = true
+	rules := ensureNetworkPolicies(t, rf)
+
+	assert.Len(rules, 3)
+	assert.Equal([]networkingv1.NetworkPolicyIngressRule{
+		tcpRule(6379, componentPeer("redis"), componentPeer("envoy"), operatorPeer, componentPeer("sentinel"), clientsPeer),
+	}, rules["rfr-test"])
+	// Envoy gets the master from the operator, not from the sentinels
+	assert.Equal([]networkingv1.NetworkPolicyIngressRule{
+		tcpRule(26379, componentPeer("sentinel"), componentPeer("redis"), operatorPeer, clientsPeer),
+	}, rules["rfs-test"])
+	assert.Equal([]networkingv1.NetworkPolicyIngressRule{
+		tcpRule(6379, clientsPeer, operatorPeer),
+		tcpRule(6380, clientsPeer, operatorPeer),
+		tcpRule(9901, metricsPeer),
+	}, rules["rfe-test"])
+}
+
+func TestEnsureNetworkPoliciesNarrowedClients(t *testing.T) {
+	assert := assert.New(t)
+
+	// the clients are narrowed to the pods of an app, the redises still reach the sentinels on their shutdown
+	rf := generateNetworkPolicyRF()
+	rf.Spec.NetworkPolicy.Clients.PodSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"app": "myapp"}}
+	rules := ensureNetworkPolicies(t, rf)
+
+	sentinelRules := rules["rfs-test"]
+	if assert.Len(sentinelRules, 1) {
+		assert.Contains(sentinelRules[0].From, componentPeer("redis"))
+		assert.Contains(sentinelRules[0].From, networkingv1.NetworkPolicyPeer{PodSelector: rf.Spec.NetworkPolicy.Clients.PodSelector})
+	}
+}
+
+func TestEnsureNetworkPoliciesExternalAccess(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateNetworkPolicyRF()
+	rf.Spec.ExternalAccess = &redisfailoverv1.ExternalAccessSettings{Type: corev1.ServiceTypeLoadBalancer}
+	rules := ensureNetworkPolicies(t, rf)
+
+	assert.Equal([]networkingv1.NetworkPolicyIngressRule{tcpRule(6379)}, rules["rfr-test"])
+	assert.Equal([]networkingv1.NetworkPolicyIngressRule{tcpRule(26379)}, rules["rfs-test"])
+}
+
+func TestEnsureNetworkPoliciesDisabled(t *testing.T) {
+	assert := assert.New(t)
+
+	rules := ensureNetworkPolicies(t, generateRF(), "rfr-test", "rfs-test")
+	assert.Empty(rules)
+}
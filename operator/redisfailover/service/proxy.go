@@ -0,0 +1,111 @@
+package service
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
+)
+
+// ProxyBackends is the redis topology a proxy is configured with. Proxies that ask the sentinels
+// for the master reach them through the headless sentinel service instead.
+type ProxyBackends struct {
+	Master   string
+	Replicas []string
+	Password string
+	// SentinelPassword is the password of the sentinels, for the proxies asking them for the master
+	SentinelPassword string
+}
+
+// Proxy generates the kubernetes objects of a proxy implementation placed in front of the redis failover
+type Proxy interface {
+	// Name returns the name of the proxy configmap, service, deployment and pdb
+	Name(rf *redisfailoverv1.RedisFailover) string
+	// Component returns the app.kubernetes.io/component label of the proxy objects
+	Component() string
+	ConfigMap(rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference, backends ProxyBackends) *corev1.ConfigMap
+	Service(rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) *corev1.Service
+	Deployment(rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) *appsv1.Deployment
+	// FollowsMaster returns true when the configuration holds the master address instead of
+	// asking the sentinels for it, so it has to be regenerated on every failover
+	FollowsMaster() bool
+	// StartupConfig returns the configmap keys the proxy only reads when it starts. The
+	// deployment is rolled whenever their content changes.
+	StartupConfig() []string
+}
+
+var proxies = map[redisfailoverv1.ProxyType]Proxy{
+	redisfailoverv1.ProxyTypePredixy: predixyProxy{},
+	redisfailoverv1.ProxyTypeEnvoy:   envoyProxy{},
+}
+
+// GetProxy returns the proxy implementation selected on the RedisFailover, predixy if none is
+func GetProxy(rf *redisfailoverv1.RedisFailover) Proxy {
+	if p, ok := proxies[rf.Spec.Proxy.Type]; ok {
+		return p
+	}
+	return proxies[redisfailoverv1.ProxyTypePredixy]
+}
+
+type predixyProxy struct{}
+
+func (predixyProxy) Name(rf *redisfailoverv1.RedisFailover) string {
+	return GetPredixyName(rf)
+}
+
+func (predixyProxy) Component() string {
+	return predixyRoleName
+}
+
+func (predixyProxy) ConfigMap(rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference, backends ProxyBackends) *corev1.ConfigMap {
+	return generatePredixyConfigMap(rf, labels, ownerRefs, backends.Password, backends.SentinelPassword)
+}
+
+func (predixyProxy) Service(rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) *corev1.Service {
+	return generatePredixyService(rf, labels, ownerRefs)
+}
+
+func (predixyProxy) Deployment(rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) *appsv1.Deployment {
+	return generatePredixyDeployments(rf, labels, ownerRefs)
+}
+
+func (predixyProxy) FollowsMaster() bool {
+	return false
+}
+
+func (predixyProxy) StartupConfig() []string {
+	return []string{"predixy.conf", "sentinel.conf", "auth.conf"}
+}
+
+type envoyProxy struct{}
+
+func (envoyProxy) Name(rf *redisfailoverv1.RedisFailover) string {
+	return GetEnvoyName(rf)
+}
+
+func (envoyProxy) Component() string {
+	return envoyRoleName
+}
+
+func (envoyProxy) ConfigMap(rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference, backends ProxyBackends) *corev1.ConfigMap {
+	return generateEnvoyConfigMap(rf, labels, ownerRefs, backends)
+}
+
+func (envoyProxy) Service(rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) *corev1.Service {
+	return generateEnvoyService(rf, labels, ownerRefs)
+}
+
+func (envoyProxy) Deployment(rf *redisfailoverv1.RedisFailover, labels map[string]string, ownerRefs []metav1.OwnerReference) *appsv1.Deployment {
+	return generateEnvoyDeployment(rf, labels, ownerRefs)
+}
+
+func (envoyProxy) FollowsMaster() bool {
+	return true
+}
+
+// StartupConfig leaves cds.yaml out, envoy watches it and reloads the replicas by itself. The master is in, a
+// failover must not wait for the kubelet to refresh the mounted configmap
+func (envoyProxy) StartupConfig() []string {
+	return []string{"envoy.yaml", envoyMasterDataKey}
+}
@@ -0,0 +1,41 @@
+package service
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"k8s.io/apimachinery/pkg/util/uuid"
+
+	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
+	"github.com/spotahome/redis-operator/log"
+	"github.com/spotahome/redis-operator/tracing"
+)
+
+// StartReconcile gives a new ID to the reconcile of the given RedisFailover and starts its span, returning the
+// context holding the span and the fields logged with every line of the reconcile, the ID and the RedisFailover. The returned function ends the span when the reconcile
+// ends, with its error
+func StartReconcile(ctx context.Context, rf *redisfailoverv1.RedisFailover) (context.Context, func(error)) {
+	id := string(uuid.NewUUID())
+	ctx, span := tracing.Start(ctx, "Handle",
+		attribute.String("redisfailover", rf.Name),
+		attribute.String("namespace", rf.Namespace),
+		attribute.String("reconcile", id),
+	)
+	ctx = log.NewContext(ctx, map[string]interface{}{
+		"redisfailover": rf.Name,
+		"namespace":     rf.Namespace,
+		"reconcile":     id,
+	})
+	return ctx, func(err error) {
+		tracing.End(span, err)
+	}
+}
+
+// StartSpan starts the span of a phase of the reconcile, child of the span of the given context, and returns the
+// context holding it, the parent of the spans of the calls made with it until the returned function ends it
+func StartSpan(ctx context.Context, name string) (context.Context, func(error)) {
+	ctx, span := tracing.Start(ctx, name)
+	return ctx, func(err error) {
+		tracing.End(span, err)
+	}
+}
@@ -0,0 +1,57 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	rfservice "github.com/spotahome/redis-operator/operator/redisfailover/service"
+)
+
+func TestReconcileSpans(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	provider := otel.GetTracerProvider()
+	defer otel.SetTracerProvider(provider)
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	rf := generateRF()
+	ctx, endReconcile := rfservice.StartReconcile(context.Background(), rf)
+	ensureCtx, endEnsure := rfservice.StartSpan(ctx, "Ensure")
+	_, endStep := rfservice.StartSpan(ensureCtx, "EnsureRedisService")
+	endStep(nil)
+	endEnsure(errors.New("wrong"))
+	_, endCheck := rfservice.StartSpan(ctx, "CheckAndHeal")
+	endCheck(nil)
+	endReconcile(errors.New("wrong"))
+
+	spans := recorder.Ended()
+	require.Len(spans, 4)
+	step, ensure, check, handle := spans[0], spans[1], spans[2], spans[3]
+	assert.Equal("EnsureRedisService", step.Name())
+	assert.Equal("Ensure", ensure.Name())
+	assert.Equal("CheckAndHeal", check.Name())
+	assert.Equal("Handle", handle.Name())
+	assert.Equal(ensure.SpanContext().SpanID(), step.Parent().SpanID())
+	assert.Equal(handle.SpanContext().SpanID(), ensure.Parent().SpanID())
+	assert.Equal(handle.SpanContext().SpanID(), check.Parent().SpanID())
+	assert.Equal(codes.Error, ensure.Status().Code)
+	assert.Equal(codes.Error, handle.Status().Code)
+	assert.Contains(handle.Attributes(), attribute.String("redisfailover", rf.Name))
+
+	// Out of a reconcile the spans have no parent
+	_, end := rfservice.StartSpan(context.Background(), "Ensure")
+	end(nil)
+	spans = recorder.Ended()
+	require.Len(spans, 5)
+	assert.False(spans[4].Parent().IsValid())
+}
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"net"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
+	"github.com/spotahome/redis-operator/service/k8s"
+)
+
+// Topology is the state of the redises and sentinels of a RedisFailover, as seen by them
+type Topology struct {
+	Namespace string             `json:"namespace"`
+	Name      string             `json:"name"`
+	Time      metav1.Time        `json:"time"`
+	Redises   []RedisTopology    `json:"redises"`
+	Sentinels []SentinelTopology `json:"sentinels"`
+}
+
+// RedisTopology is the replication state of a redis
+type RedisTopology struct {
+	Pod          string `json:"pod"`
+	Address      string `json:"address"`
+	RevisionHash string `json:"revisionHash,omitempty"`
+	// Zone is the zone the redis is placed in, when the redises are placed by zone
+	Zone string `json:"zone,omitempty"`
+	Role string `json:"role,omitempty"`
+	// Master is the address of the master the redis replicates, empty for a master
+	Master     string `json:"master,omitempty"`
+	LinkStatus string `json:"linkStatus,omitempty"`
+	Offset     int64  `json:"offset"`
+	// Lag is the replication offset the redis is behind its master by, unknown when its master isn't a redis of the failover
+	Lag *int64 `json:"lag,omitempty"`
+	// UsedMemory is the memory allocated by the redis, in bytes
+	UsedMemory       int64  `json:"usedMemory,omitempty"`
+	ConnectedClients int    `json:"connectedClients,omitempty"`
+	Error            string `json:"error,omitempty"`
+}
+
+// SentinelTopology is the view of a sentinel on the master it monitors
+type SentinelTopology struct {
+	Pod     string `json:"pod"`
+	Address string `json:"address"`
+	// Master is the address of the monitored master
+	Master          string   `json:"master,omitempty"`
+	Flags           string   `json:"flags,omitempty"`
+	Sentinels       []string `json:"sentinels,omitempty"`
+	Replicas        []string `json:"replicas,omitempty"`
+	Quorum          int      `json:"quorum,omitempty"`
+	QuorumReachable bool     `json:"quorumReachable"`
+	Error           string   `json:"error,omitempty"`
+}
+
+// GetTopology asks the redises and sentinels of the RedisFailover for their state. Nodes that can't be reached
+// are reported with the error got
+func (r *RedisFailoverChecker) GetTopology(ctx context.Context, rf *redisfailoverv1.RedisFailover) (*Topology, error) {
+	topology := &Topology{
+		Namespace: rf.Namespace,
+		Name:      rf.Name,
+		Time:      metav1.Now(),
+		Redises:   []RedisTopology{},
+		Sentinels: []SentinelTopology{},
+	}
+
+	redisPods, err := getRedisPods(ctx, r.k8sService, rf)
+	if err != nil {
+		return nil, err
+	}
+	password, err := k8s.GetRedisPassword(ctx, r.k8sService, rf)
+	if err != nil {
+		return nil, err
+	}
+	port := getRedisPort(rf.Spec.Redis.Port)
+	offsets := map[string]int64{}
+	for _, pod := range redisPods.Items {
+		redis := RedisTopology{
+			Pod:          pod.Name,
+			Address:      getRedisAddress(rf, pod),
+			RevisionHash: pod.Labels[appsv1.ControllerRevisionHashLabelKey],
+			Zone:         pod.Labels[zoneLabelKey],
+		}
+		if !isPodRunning(pod) {
+			redis.Error = "pod not running"
+			topology.Redises = append(topology.Redises, redis)
+			continue
+		}
+		info, err := r.redisClient.GetReplicationInfo(ctx, redis.Address, port, password)
+		if err != nil {
+			redis.Error = err.Error()
+			topology.Redises = append(topology.Redises, redis)
+			continue
+		}
+		redis.Role = info.Role
+		redis.LinkStatus = info.MasterLinkStatus
+		redis.Offset = info.Offset
+		redis.UsedMemory = info.UsedMemory
+		redis.ConnectedClients = info.ConnectedClients
+		if info.MasterHost != "" {
+			redis.Master = net.JoinHostPort(info.MasterHost, info.MasterPort)
+		} else {
+			offsets[net.JoinHostPort(redis.Address, port)] = info.Offset
+		}
+		topology.Redises = append(topology.Redises, redis)
+	}
+	for i, redis := range topology.Redises {
+		if masterOffset, ok := offsets[redis.Master]; ok {
+			lag := masterOffset - redis.Offset
+			topology.Redises[i].Lag = &lag
+		}
+	}
+
+	sentinelPods, err := r.k8sService.GetStatefulSetPods(ctx, rf.Namespace, GetSentinelName(rf))
+	if err != nil {
+		return nil, err
+	}
+	sentinelPassword, err := k8s.GetSentinelPassword(ctx, r.k8sService, rf)
+	if err != nil {
+		return nil, err
+	}
+	sentinelPort := getSentinelPort(rf.Spec.Sentinel.Port)
+	for _, pod := range sentinelPods.Items {
+		sentinel := SentinelTopology{
+			Pod:     pod.Name,
+			Address: getSentinelAddress(rf, pod),
+		}
+		if !isPodRunning(pod) {
+			sentinel.Error = "pod not running"
+			topology.Sentinels = append(topology.Sentinels, sentinel)
+			continue
+		}
+		info, err := r.redisClient.GetSentinelMasterInfo(ctx, sentinel.Address, sentinelPort, rf.Spec.Sentinel.MasterName, sentinelPassword)
+		if err != nil {
+			sentinel.Error = err.Error()
+			topology.Sentinels = append(topology.Sentinels, sentinel)
+			continue
+		}
+		sentinel.Master = net.JoinHostPort(info.IP, info.Port)
+		sentinel.Flags = info.Flags
+		sentinel.Sentinels = info.Sentinels
+		sentinel.Replicas = info.Replicas
+		sentinel.Quorum = info.Quorum
+		sentinel.QuorumReachable = info.QuorumReachable
+		topology.Sentinels = append(topology.Sentinels, sentinel)
+	}
+
+	return topology, nil
+}
+
+func isPodRunning(pod corev1.Pod) bool {
+	return pod.Status.Phase == corev1.PodRunning && pod.DeletionTimestamp == nil
+}
@@ -0,0 +1,116 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/spotahome/redis-operator/log"
+	"github.com/spotahome/redis-operator/metrics"
+	mK8SService "github.com/spotahome/redis-operator/mocks/service/k8s"
+	mRedisService "github.com/spotahome/redis-operator/mocks/service/redis"
+	rfservice "github.com/spotahome/redis-operator/operator/redisfailover/service"
+	"github.com/spotahome/redis-operator/service/redis"
+)
+
+func TestGetTopology(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	rf := generateRF()
+	rf.Spec.Redis.Port = 6379
+	running := corev1.PodStatus{Phase: corev1.PodRunning}
+	redisPods := &corev1.PodList{
+		Items: []corev1.Pod{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "rfr-0", Labels: map[string]string{appsv1.ControllerRevisionHashLabelKey: "rev1"}},
+				Status:     corev1.PodStatus{Phase: corev1.PodRunning, PodIP: "0.0.0.0"},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "rfr-1", Labels: map[string]string{appsv1.ControllerRevisionHashLabelKey: "rev1"}},
+				Status:     corev1.PodStatus{Phase: corev1.PodRunning, PodIP: "1.1.1.1"},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "rfr-2"},
+				Status:     corev1.PodStatus{Phase: corev1.PodPending},
+			},
+		},
+	}
+	running.PodIP = "2.2.2.2"
+	sentinelPods := &corev1.PodList{
+		Items: []corev1.Pod{
+			{ObjectMeta: metav1.ObjectMeta{Name: "rfs-0"}, Status: running},
+		},
+	}
+
+	ms := &mK8SService.Services{}
+	ms.On("GetStatefulSetPods", mock.Anything, namespace, rfservice.GetRedisName(rf)).Once().Return(redisPods, nil)
+	ms.On("GetStatefulSetPods", mock.Anything, namespace, rfservice.GetSentinelName(rf)).Once().Return(sentinelPods, nil)
+	mr := &mRedisService.Client{}
+	mr.On("GetReplicationInfo", mock.Anything, "0.0.0.0", "6379", "").Once().Return(redis.ReplicationInfo{Role: "master", Offset: 100, UsedMemory: 1024, ConnectedClients: 5}, nil)
+	mr.On("GetReplicationInfo", mock.Anything, "1.1.1.1", "6379", "").Once().Return(redis.ReplicationInfo{Role: "slave", MasterHost: "0.0.0.0", MasterPort: "6379", MasterLinkStatus: "up", Offset: 90}, nil)
+	mr.On("GetSentinelMasterInfo", mock.Anything, "2.2.2.2", "26379", "master0", "").Once().Return(redis.SentinelMasterInfo{
+		IP:              "0.0.0.0",
+		Port:            "6379",
+		Flags:           "master",
+		Quorum:          2,
+		Sentinels:       []string{"3.3.3.3:26379"},
+		Replicas:        []string{"1.1.1.1:6379"},
+		QuorumReachable: true,
+	}, nil)
+
+	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
+	topology, err := checker.GetTopology(context.Background(), rf)
+	require.NoError(err)
+
+	require.Len(topology.Redises, 3)
+	assert.Equal("master", topology.Redises[0].Role)
+	assert.Equal("rev1", topology.Redises[0].RevisionHash)
+	assert.Nil(topology.Redises[0].Lag)
+	assert.Equal(int64(1024), topology.Redises[0].UsedMemory)
+	assert.Equal(5, topology.Redises[0].ConnectedClients)
+	assert.Equal("0.0.0.0:6379", topology.Redises[1].Master)
+	assert.Equal("up", topology.Redises[1].LinkStatus)
+	require.NotNil(topology.Redises[1].Lag)
+	assert.Equal(int64(10), *topology.Redises[1].Lag)
+	assert.Equal("pod not running", topology.Redises[2].Error)
+
+	require.Len(topology.Sentinels, 1)
+	assert.Equal("0.0.0.0:6379", topology.Sentinels[0].Master)
+	assert.Equal([]string{"3.3.3.3:26379"}, topology.Sentinels[0].Sentinels)
+	assert.True(topology.Sentinels[0].QuorumReachable)
+	ms.AssertExpectations(t)
+	mr.AssertExpectations(t)
+}
+
+func TestGetTopologyUnreachableNode(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	rf := generateRF()
+	pods := &corev1.PodList{
+		Items: []corev1.Pod{
+			{ObjectMeta: metav1.ObjectMeta{Name: "rfr-0"}, Status: corev1.PodStatus{Phase: corev1.PodRunning, PodIP: "0.0.0.0"}},
+		},
+	}
+
+	ms := &mK8SService.Services{}
+	ms.On("GetStatefulSetPods", mock.Anything, namespace, rfservice.GetRedisName(rf)).Once().Return(pods, nil)
+	ms.On("GetStatefulSetPods", mock.Anything, namespace, rfservice.GetSentinelName(rf)).Once().Return(&corev1.PodList{}, nil)
+	mr := &mRedisService.Client{}
+	mr.On("GetReplicationInfo", mock.Anything, "0.0.0.0", "0", "").Once().Return(redis.ReplicationInfo{}, errors.New("connection refused"))
+
+	checker := rfservice.NewRedisFailoverChecker(ms, mr, log.DummyLogger{}, metrics.Dummy)
+	topology, err := checker.GetTopology(context.Background(), rf)
+	require.NoError(err)
+	require.Len(topology.Redises, 1)
+	assert.Equal("connection refused", topology.Redises[0].Error)
+	assert.Empty(topology.Sentinels)
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
+	"github.com/spotahome/redis-operator/operator/redisfailover/util"
+	"github.com/spotahome/redis-operator/service/k8s"
+)
+
+const (
+	// defaultReplicaPriority is the replica-priority of redis when the custom config doesn't set it
+	defaultReplicaPriority = 100
+	// secondaryZoneReplicaPriorityOffset is added to the replica-priority of the redises outside the primary zone, so
+	// sentinel promotes the replicas of the primary zone first
+	secondaryZoneReplicaPriorityOffset = 100
+)
+
+// redisStatefulSet is a statefulset of redises, the only one of the redis failover or the one of a zone
+type redisStatefulSet struct {
+	name     string
+	zone     string
+	replicas int32
+}
+
+// getRedisStatefulSets returns the statefulsets the redises of the redis failover are placed in, one per zone
+// spreading the replicas evenly across them, the first zones holding the remainder, when zones are configured
+func getRedisStatefulSets(rf *redisfailoverv1.RedisFailover) []redisStatefulSet {
+	zones := rf.Spec.Redis.Zones
+	if zones == nil || len(zones.Names) == 0 {
+		return []redisStatefulSet{{name: GetRedisName(rf), replicas: rf.Spec.Redis.Replicas}}
+	}
+
+	sets := make([]redisStatefulSet, 0, len(zones.Names))
+	count := int32(len(zones.Names))
+	for i, zone := range zones.Names {
+		replicas := rf.Spec.Redis.Replicas / count
+		if int32(i) < rf.Spec.Redis.Replicas%count {
+			replicas++
+		}
+		sets = append(sets, redisStatefulSet{name: GetRedisZoneName(rf, zone), zone: zone, replicas: replicas})
+	}
+	return sets
+}
+
+// getZonesAnnotation returns the zones of the redis failover, in order, as recorded on its redis statefulsets
+func getZonesAnnotation(rf *redisfailoverv1.RedisFailover) string {
+	if rf.Spec.Redis.Zones == nil {
+		return ""
+	}
+	return strings.Join(rf.Spec.Redis.Zones.Names, ",")
+}
+
+// getRedisPodNames returns the names of the pods of every redis statefulset
+func getRedisPodNames(rf *redisfailoverv1.RedisFailover) []string {
+	names := []string{}
+	for _, set := range getRedisStatefulSets(rf) {
+		names = append(names, getPodNames(set.name, set.replicas)...)
+	}
+	return names
+}
+
+// getRedisPods returns the pods of every redis statefulset of the redis failover
+func getRedisPods(ctx context.Context, k8sService k8s.StatefulSet, rf *redisfailoverv1.RedisFailover) (*corev1.PodList, error) {
+	sets := getRedisStatefulSets(rf)
+	if len(sets) == 1 {
+		return k8sService.GetStatefulSetPods(ctx, rf.Namespace, sets[0].name)
+	}
+
+	pods := &corev1.PodList{}
+	for _, set := range sets {
+		setPods, err := k8sService.GetStatefulSetPods(ctx, rf.Namespace, set.name)
+		if err != nil {
+			return nil, err
+		}
+		pods.Items = append(pods.Items, setPods.Items...)
+	}
+	return pods, nil
+}
+
+// generateRedisZoneStatefulSet returns the statefulset of the redises of a zone, whose pods, and so their volumes,
+// are pinned to the nodes of the zone
+func generateRedisZoneStatefulSet(rf *redisfailoverv1.RedisFailover, set redisStatefulSet, labels map[string]string, ownerRefs []metav1.OwnerReference) *appsv1.StatefulSet {
+	ss := generateRedisStatefulSet(rf, labels, ownerRefs)
+	zoneLabels := map[string]string{zoneLabelKey: set.zone}
+	replicas := set.replicas
+
+	ss.Name = set.name
+	ss.Labels = util.MergeLabels(ss.Labels, zoneLabels)
+	ss.Annotations = util.MergeAnnotations(ss.Annotations, map[string]string{zonesAnnotationKey: getZonesAnnotation(rf)})
+	ss.Spec.Replicas = &replicas
+	ss.Spec.Selector.MatchLabels = util.MergeLabels(ss.Spec.Selector.MatchLabels, zoneLabels)
+	ss.Spec.Template.Labels = util.MergeLabels(ss.Spec.Template.Labels, zoneLabels)
+	ss.Spec.Template.Spec.Affinity = withZonesAffinity(ss.Spec.Template.Spec.Affinity, rf.Spec.Redis.Zones.TopologyKey, []string{set.zone})
+	for i := range ss.Spec.VolumeClaimTemplates {
+		ss.Spec.VolumeClaimTemplates[i].Labels = util.MergeLabels(ss.Spec.VolumeClaimTemplates[i].Labels, zoneLabels)
+	}
+	return ss
+}
+
+// spreadSentinelsAcrossZones places the sentinels in the zones of the redises, evenly unless the user already
+// spreads them by the zone topology key
+func spreadSentinelsAcrossZones(rf *redisfailoverv1.RedisFailover, ss *appsv1.StatefulSet) {
+	zones := rf.Spec.Redis.Zones
+	spec := &ss.Spec.Template.Spec
+	spec.Affinity = withZonesAffinity(spec.Affinity, zones.TopologyKey, zones.Names)
+
+	for _, constraint := range spec.TopologySpreadConstraints {
+		if constraint.TopologyKey == zones.TopologyKey {
+			return
+		}
+	}
+	spec.TopologySpreadConstraints = append(append([]corev1.TopologySpreadConstraint{}, spec.TopologySpreadConstraints...), corev1.TopologySpreadConstraint{
+		MaxSkew:           1,
+		TopologyKey:       zones.TopologyKey,
+		WhenUnsatisfiable: corev1.DoNotSchedule,
+		LabelSelector: &metav1.LabelSelector{
+			MatchLabels: ss.Spec.Selector.MatchLabels,
+		},
+	})
+}
+
+// withZonesAffinity returns a copy of the affinity that also requires the nodes to be in one of the zones
+func withZonesAffinity(affinity *corev1.Affinity, topologyKey string, zones []string) *corev1.Affinity {
+	requirement := corev1.NodeSelectorRequirement{
+		Key:      topologyKey,
+		Operator: corev1.NodeSelectorOpIn,
+		Values:   zones,
+	}
+
+	affinity = affinity.DeepCopy()
+	if affinity == nil {
+		affinity = &corev1.Affinity{}
+	}
+	if affinity.NodeAffinity == nil {
+		affinity.NodeAffinity = &corev1.NodeAffinity{}
+	}
+	required := affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	if required == nil || len(required.NodeSelectorTerms) == 0 {
+		affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = &corev1.NodeSelector{
+			NodeSelectorTerms: []corev1.NodeSelectorTerm{{MatchExpressions: []corev1.NodeSelectorRequirement{requirement}}},
+		}
+		return affinity
+	}
+	// The terms are ORed, every one of them has to require the zones
+	for i := range required.NodeSelectorTerms {
+		required.NodeSelectorTerms[i].MatchExpressions = append(required.NodeSelectorTerms[i].MatchExpressions, requirement)
+	}
+	return affinity
+}
+
+// getZoneReplicaPriority returns the replica-priority config overriding the one of the custom config for the redis
+// with the given address when it is out of the primary zone. Empty when the redis is in the primary zone, must
+// never be promoted or is not found
+func getZoneReplicaPriority(rf *redisfailoverv1.RedisFailover, pods *corev1.PodList, address string) string {
+	priority := defaultReplicaPriority
+	for _, config := range rf.Spec.Redis.CustomConfig {
+		fields := strings.Fields(config)
+		if len(fields) == 2 && strings.EqualFold(fields[0], "replica-priority") {
+			if p, err := strconv.Atoi(fields[1]); err == nil {
+				priority = p
+			}
+		}
+	}
+	if priority == 0 {
+		return ""
+	}
+
+	for _, pod := range pods.Items {
+		if getRedisAddress(rf, pod) != address {
+			continue
+		}
+		if pod.Labels[zoneLabelKey] == rf.Spec.Redis.Zones.Primary {
+			return ""
+		}
+		return fmt.Sprintf("replica-priority %d", priority+secondaryZoneReplicaPriorityOffset)
+	}
+	return ""
+}
@@ -0,0 +1,259 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
+	"github.com/spotahome/redis-operator/log"
+	"github.com/spotahome/redis-operator/metrics"
+	mK8SService "github.com/spotahome/redis-operator/mocks/service/k8s"
+	mRedisService "github.com/spotahome/redis-operator/mocks/service/redis"
+	rfservice "github.com/spotahome/redis-operator/operator/redisfailover/service"
+)
+
+const (
+	zoneLabelKey       = "redisfailovers.databases.spotahome.com/zone"
+	zonesAnnotationKey = "redisfailovers.databases.spotahome.com/zones"
+)
+
+func generateZonesRF() *redisfailoverv1.RedisFailover {
+	rf := generateRF()
+	rf.Spec.Redis.Replicas = 5
+	rf.Spec.Redis.Zones = &redisfailoverv1.ZonesSettings{
+		Names:       []string{"a", "b"},
+		Primary:     "a",
+		TopologyKey: "topology.kubernetes.io/zone",
+	}
+	return rf
+}
+
+func zoneRequirement(zones ...string) corev1.NodeSelectorRequirement {
+	return corev1.NodeSelectorRequirement{
+		Key:      "topology.kubernetes.io/zone",
+		Operator: corev1.NodeSelectorOpIn,
+		Values:   zones,
+	}
+}
+
+func TestEnsureRedisStatefulsetZones(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateZonesRF()
+	rf.Spec.Redis.Affinity = &corev1.Affinity{
+		NodeAffinity: &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+				NodeSelectorTerms: []corev1.NodeSelectorTerm{{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "disk", Operator: corev1.NodeSelectorOpExists}}}},
+			},
+		},
+	}
+
+	sss := map[string]*appsv1.StatefulSet{}
+	ms := &mK8SService.Services{}
+	ms.On("CreateOrUpdatePodDisruptionBudget", mock.Anything, namespace, mock.Anything).Once().Return(nil)
+	ms.On("CreateOrUpdateStatefulSet", mock.Anything, namespace, mock.Anything).Twice().Run(func(args mock.Arguments) {
+		ss := args.Get(2).(*appsv1.StatefulSet)
+		sss[ss.Name] = ss
+	}).Return(nil)
+
+	client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+	assert.NoError(client.EnsureRedisStatefulset(context.Background(), rf, nil, []metav1.OwnerReference{}))
+	ms.AssertExpectations(t)
+
+	if assert.Len(sss, 2) {
+		for zone, replicas := range map[string]int32{"a": 3, "b": 2} {
+			ss := sss["rfr-test-"+zone]
+			if !assert.NotNil(ss, zone) {
+				continue
+			}
+			assert.Equal(replicas, *ss.Spec.Replicas)
+			assert.Equal("rfr-test", ss.Spec.ServiceName)
+			assert.Equal(zone, ss.Labels[zoneLabelKey])
+			assert.Equal("a,b", ss.Annotations[zonesAnnotationKey])
+			assert.Equal(zone, ss.Spec.Selector.MatchLabels[zoneLabelKey])
+			assert.Equal(zone, ss.Spec.Template.Labels[zoneLabelKey])
+			assert.Equal([]corev1.NodeSelectorRequirement{
+				{Key: "disk", Operator: corev1.NodeSelectorOpExists},
+				zoneRequirement(zone),
+			}, ss.Spec.Template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms[0].MatchExpressions)
+		}
+	}
+	assert.Len(rf.Spec.Redis.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms[0].MatchExpressions, 1, "the spec must not be modified")
+}
+
+func TestEnsureSentinelStatefulsetZones(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := generateZonesRF()
+
+	var ss *appsv1.StatefulSet
+	ms := &mK8SService.Services{}
+	ms.On("CreateOrUpdatePodDisruptionBudget", mock.Anything, namespace, mock.Anything).Once().Return(nil)
+	ms.On("CreateOrUpdateStatefulSet", mock.Anything, namespace, mock.Anything).Once().Run(func(args mock.Arguments) {
+		ss = args.Get(2).(*appsv1.StatefulSet)
+	}).Return(nil)
+
+	client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+	assert.NoError(client.EnsureSentinelStatefulset(context.Background(), rf, nil, []metav1.OwnerReference{}))
+
+	assert.Equal([]corev1.NodeSelectorTerm{{MatchExpressions: []corev1.NodeSelectorRequirement{zoneRequirement("a", "b")}}},
+		ss.Spec.Template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms)
+	assert.Equal([]corev1.TopologySpreadConstraint{{
+		MaxSkew:           1,
+		TopologyKey:       "topology.kubernetes.io/zone",
+		WhenUnsatisfiable: corev1.DoNotSchedule,
+		LabelSelector:     &metav1.LabelSelector{MatchLabels: ss.Spec.Selector.MatchLabels},
+	}}, ss.Spec.Template.Spec.TopologySpreadConstraints)
+}
+
+func TestEnsureRedisZonesUnchanged(t *testing.T) {
+	redisLabels := func(zone string) map[string]string {
+		labels := map[string]string{
+			"app.kubernetes.io/name":      name,
+			"app.kubernetes.io/component": "redis",
+		}
+		if zone != "" {
+			labels[zoneLabelKey] = zone
+		}
+		return labels
+	}
+	statefulSet := func(name, zone, zones string) appsv1.StatefulSet {
+		ss := appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: redisLabels(zone)}}
+		if zones != "" {
+			ss.Annotations = map[string]string{zonesAnnotationKey: zones}
+		}
+		return ss
+	}
+
+	tests := []struct {
+		name          string
+		zones         bool
+		current       []appsv1.StatefulSet
+		expectedError error
+	}{
+		{
+			name: "keeps the statefulset without zones",
+			current: []appsv1.StatefulSet{
+				statefulSet("rfr-test", "", ""),
+				{ObjectMeta: metav1.ObjectMeta{Name: "rfs-test", Labels: map[string]string{"app.kubernetes.io/name": name, "app.kubernetes.io/component": "sentinel"}}},
+			},
+		},
+		{
+			name:    "keeps the statefulsets of unchanged zones",
+			zones:   true,
+			current: []appsv1.StatefulSet{statefulSet("rfr-test-a", "a", "a,b"), statefulSet("rfr-test-b", "b", "a,b")},
+		},
+		{
+			name:          "errors when a zone is removed",
+			zones:         true,
+			current:       []appsv1.StatefulSet{statefulSet("rfr-test-a", "a", "a,b,c"), statefulSet("rfr-test-b", "b", "a,b,c"), statefulSet("rfr-test-c", "c", "a,b,c")},
+			expectedError: errors.New(`redis zones can't be changed on an existing redis failover, statefulset rfr-test-a was created for the zones "a,b,c"`),
+		},
+		{
+			name:          "errors when a zone is added",
+			zones:         true,
+			current:       []appsv1.StatefulSet{statefulSet("rfr-test-a", "a", "a")},
+			expectedError: errors.New(`redis zones can't be changed on an existing redis failover, statefulset rfr-test-a was created for the zones "a"`),
+		},
+		{
+			name:          "errors when a statefulset doesn't record its zones",
+			zones:         true,
+			current:       []appsv1.StatefulSet{statefulSet("rfr-test-a", "a", ""), statefulSet("rfr-test-b", "b", "a,b")},
+			expectedError: errors.New("statefulset rfr-test-a doesn't record the redis zones it was created for"),
+		},
+		{
+			name:          "errors when zones are set on an existing redis failover",
+			zones:         true,
+			current:       []appsv1.StatefulSet{statefulSet("rfr-test", "", "")},
+			expectedError: errors.New("redis zones can't be set or unset on an existing redis failover, statefulset rfr-test is in the way"),
+		},
+		{
+			name:          "errors when zones are unset on an existing redis failover",
+			current:       []appsv1.StatefulSet{statefulSet("rfr-test-a", "a", "a,b")},
+			expectedError: errors.New("redis zones can't be set or unset on an existing redis failover, statefulset rfr-test-a is in the way"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateRF()
+			if test.zones {
+				rf = generateZonesRF()
+			}
+			// the mock fails on any other call, no statefulset is ever deleted
+			ms := &mK8SService.Services{}
+			ms.On("ListStatefulSets", mock.Anything, namespace).Once().Return(&appsv1.StatefulSetList{Items: test.current}, nil)
+
+			client := rfservice.NewRedisFailoverKubeClient(ms, log.Dummy, metrics.Dummy)
+			err := client.EnsureRedisZonesUnchanged(context.Background(), rf)
+
+			assert.Equal(test.expectedError, err)
+			ms.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSetRedisCustomConfigZones(t *testing.T) {
+	pod := func(name, ip, zone string) corev1.Pod {
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{zoneLabelKey: zone}},
+			Status:     corev1.PodStatus{PodIP: ip},
+		}
+	}
+
+	tests := []struct {
+		name            string
+		customConfig    []string
+		ip              string
+		expectedConfigs []string
+	}{
+		{
+			name:            "keeps the priority of the redises of the primary zone",
+			customConfig:    []string{"replica-priority 100"},
+			ip:              "0.0.0.0",
+			expectedConfigs: []string{"replica-priority 100"},
+		},
+		{
+			name:            "lowers the priority of the redises out of the primary zone",
+			customConfig:    []string{"replica-priority 100", "replica-priority 10"},
+			ip:              "1.1.1.1",
+			expectedConfigs: []string{"replica-priority 100", "replica-priority 10", "replica-priority 110"},
+		},
+		{
+			name:            "never promotes the redises that must not be promoted",
+			customConfig:    []string{"replica-priority 0"},
+			ip:              "1.1.1.1",
+			expectedConfigs: []string{"replica-priority 0"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			rf := generateZonesRF()
+			rf.Spec.Redis.CustomConfig = test.customConfig
+
+			ms := &mK8SService.Services{}
+			ms.On("GetStatefulSetPods", mock.Anything, namespace, "rfr-test-a").Once().Return(&corev1.PodList{Items: []corev1.Pod{pod("rfr-test-a-0", "0.0.0.0", "a")}}, nil)
+			ms.On("GetStatefulSetPods", mock.Anything, namespace, "rfr-test-b").Once().Return(&corev1.PodList{Items: []corev1.Pod{pod("rfr-test-b-0", "1.1.1.1", "b")}}, nil)
+			mr := &mRedisService.Client{}
+			mr.On("SetCustomRedisConfig", mock.Anything, test.ip, "0", test.expectedConfigs, "").Once().Return(nil)
+
+			healer := rfservice.NewRedisFailoverHealer(ms, mr, log.DummyLogger{})
+
+			assert.NoError(healer.SetRedisCustomConfig(context.Background(), test.ip, rf))
+			assert.Equal(test.customConfig, rf.Spec.Redis.CustomConfig, "the spec must not be modified")
+			mr.AssertExpectations(t)
+		})
+	}
+}
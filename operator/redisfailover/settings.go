@@ -0,0 +1,283 @@
+package redisfailover
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spotahome/kooper/v2/controller/leaderelection"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
+	"github.com/spotahome/redis-operator/log"
+)
+
+// Settings are the settings of the operator read from its configuration file. They are applied while the operator
+// runs when the file changes, except for the leader election ones, read once at start
+type Settings struct {
+	// ResyncInterval overrides the interval every RedisFailover is reconciled at
+	ResyncInterval metav1.Duration        `json:"resyncInterval,omitempty"`
+	LeaderElection LeaderElectionSettings `json:"leaderElection,omitempty"`
+	LogLevel       string                 `json:"logLevel,omitempty"`
+	LogFormat      log.Format             `json:"logFormat,omitempty"`
+	// Images are the images used when the spec of a RedisFailover sets none
+	Images DefaultImages `json:"images,omitempty"`
+	// ImageDigests pins images, as written in the spec or the default images, to the given digests
+	ImageDigests map[string]string `json:"imageDigests,omitempty"`
+	// RegistryMirrors rewrite the registry of the images, the first one matching an image applies
+	RegistryMirrors []RegistryMirror `json:"registryMirrors,omitempty"`
+	// ExporterResources are the resources of the exporters whose spec sets none
+	ExporterResources *corev1.ResourceRequirements `json:"exporterResources,omitempty"`
+}
+
+// LeaderElectionSettings are the timings of the leader election, the defaults of kooper when unset
+type LeaderElectionSettings struct {
+	LeaseDuration metav1.Duration `json:"leaseDuration,omitempty"`
+	RenewDeadline metav1.Duration `json:"renewDeadline,omitempty"`
+	RetryPeriod   metav1.Duration `json:"retryPeriod,omitempty"`
+}
+
+// DefaultImages are the images of the components of a RedisFailover whose spec sets none
+type DefaultImages struct {
+	Redis            string `json:"redis,omitempty"`
+	Sentinel         string `json:"sentinel,omitempty"`
+	Exporter         string `json:"exporter,omitempty"`
+	SentinelExporter string `json:"sentinelExporter,omitempty"`
+	Predixy          string `json:"predixy,omitempty"`
+	PredixyExporter  string `json:"predixyExporter,omitempty"`
+	Envoy            string `json:"envoy,omitempty"`
+}
+
+// RegistryMirror rewrites the images starting with From, a registry optionally followed by a path, to start with To
+type RegistryMirror struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// ParseSettings parses and validates the given configuration file content
+func ParseSettings(content []byte) (Settings, error) {
+	settings := Settings{}
+	if err := yaml.UnmarshalStrict(content, &settings); err != nil {
+		return settings, err
+	}
+
+	switch settings.LogFormat {
+	case "", log.TextFormat, log.JSONFormat:
+	default:
+		return settings, fmt.Errorf("unknown log format %q", settings.LogFormat)
+	}
+	if settings.ResyncInterval.Duration < 0 {
+		return settings, errors.New("resyncInterval can't be negative")
+	}
+	le := settings.LeaderElection
+	if le.LeaseDuration.Duration < 0 || le.RenewDeadline.Duration < 0 || le.RetryPeriod.Duration < 0 {
+		return settings, errors.New("leaderElection timings can't be negative")
+	}
+	for image, digest := range settings.ImageDigests {
+		if strings.Contains(image, "@") {
+			return settings, fmt.Errorf("image %s is already pinned to a digest", image)
+		}
+		if !strings.Contains(digest, ":") {
+			return settings, fmt.Errorf("digest %q of image %s must be written as <algorithm>:<hex>", digest, image)
+		}
+	}
+	for _, mirror := range settings.RegistryMirrors {
+		if mirror.From == "" || mirror.To == "" {
+			return settings, errors.New("registryMirrors must include from and to")
+		}
+	}
+	return settings, nil
+}
+
+// lockConfig returns the leader election timings, nil for the defaults
+func (s Settings) lockConfig() *leaderelection.LockConfig {
+	le := s.LeaderElection
+	if le == (LeaderElectionSettings{}) {
+		return nil
+	}
+	return &leaderelection.LockConfig{
+		LeaseDuration: le.LeaseDuration.Duration,
+		RenewDeadline: le.RenewDeadline.Duration,
+		RetryPeriod:   le.RetryPeriod.Duration,
+	}
+}
+
+// applyDefaults sets the default images and exporter resources on the given RedisFailover where its spec sets none.
+// It has to be called before the validation, which sets the built-in defaults
+func (s Settings) applyDefaults(rf *redisfailoverv1.RedisFailover) {
+	setDefault := func(image *string, defaults ...string) {
+		for _, d := range defaults {
+			if *image == "" {
+				*image = d
+			}
+		}
+	}
+	setDefault(&rf.Spec.Redis.Image, s.Images.Redis)
+	setDefault(&rf.Spec.Sentinel.Image, s.Images.Sentinel, s.Images.Redis)
+	setDefault(&rf.Spec.Redis.Exporter.Image, s.Images.Exporter)
+	setDefault(&rf.Spec.Sentinel.Exporter.Image, s.Images.SentinelExporter, s.Images.Exporter)
+	setDefault(&rf.Spec.Predixy.Image, s.Images.Predixy)
+	setDefault(&rf.Spec.Predixy.Exporter.Image, s.Images.PredixyExporter)
+	setDefault(&rf.Spec.Proxy.Envoy.Image, s.Images.Envoy)
+
+	if s.ExporterResources != nil {
+		for _, exporter := range []*redisfailoverv1.Exporter{&rf.Spec.Redis.Exporter, &rf.Spec.Sentinel.Exporter, &rf.Spec.Predixy.Exporter} {
+			if exporter.Resources == nil {
+				exporter.Resources = s.ExporterResources.DeepCopy()
+			}
+		}
+	}
+}
+
+// rewriteImages pins and rewrites every image of the given RedisFailover
+func (s Settings) rewriteImages(rf *redisfailoverv1.RedisFailover) {
+	images := []*string{
+		&rf.Spec.Redis.Image,
+		&rf.Spec.Sentinel.Image,
+		&rf.Spec.Redis.Exporter.Image,
+		&rf.Spec.Sentinel.Exporter.Image,
+		&rf.Spec.Predixy.Image,
+		&rf.Spec.Predixy.Exporter.Image,
+		&rf.Spec.Proxy.Envoy.Image,
+	}
+	for _, containers := range [][]corev1.Container{rf.Spec.Redis.InitContainers, rf.Spec.Redis.ExtraContainers, rf.Spec.Sentinel.InitContainers, rf.Spec.Sentinel.ExtraContainers} {
+		for i := range containers {
+			images = append(images, &containers[i].Image)
+		}
+	}
+	for _, image := range images {
+		if *image != "" {
+			*image = s.rewriteImage(*image)
+		}
+	}
+}
+
+// rewriteImage pins the given image to its digest, then rewrites its registry with the first matching mirror
+func (s Settings) rewriteImage(image string) string {
+	if digest, ok := s.ImageDigests[image]; ok {
+		image = fmt.Sprintf("%s@%s", image, digest)
+	}
+
+	full := fullImageName(image)
+	for _, mirror := range s.RegistryMirrors {
+		from := strings.TrimSuffix(mirror.From, "/")
+		if strings.HasPrefix(full, from+"/") {
+			return strings.TrimSuffix(mirror.To, "/") + strings.TrimPrefix(full, from)
+		}
+	}
+	return image
+}
+
+// fullImageName returns the given image with the registry it is pulled from, the Docker Hub ones are written without
+func fullImageName(image string) string {
+	registry, _, found := strings.Cut(image, "/")
+	if found && (strings.ContainsAny(registry, ".:") || registry == "localhost") {
+		return image
+	}
+	if !found {
+		return "docker.io/library/" + image
+	}
+	return "docker.io/" + image
+}
+
+// SettingsStore holds the settings of the operator, reloaded from the configuration file when it changes
+type SettingsStore struct {
+	mutex    sync.RWMutex
+	settings Settings
+	content  []byte
+	// running is set once the settings are in use, the leader election ones aren't reloaded from then on
+	running bool
+}
+
+// NewSettingsStore returns a store holding the given settings
+func NewSettingsStore(settings Settings) *SettingsStore {
+	return &SettingsStore{settings: settings, running: true}
+}
+
+// LoadSettingsStore returns a store holding the settings of the given configuration file. A missing file gives the
+// default settings, as it happens when the ConfigMap holding it is optional
+func LoadSettingsStore(path string) (*SettingsStore, error) {
+	s := &SettingsStore{}
+	if _, _, err := s.load(path); err != nil {
+		return nil, err
+	}
+	s.running = true
+	return s, nil
+}
+
+// Get returns the current settings
+func (s *SettingsStore) Get() Settings {
+	if s == nil {
+		return Settings{}
+	}
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.settings
+}
+
+// Watch reloads the settings when the configuration file changes, calling onChange with the previous and the new
+// ones, until the context is done. The file is polled, ConfigMap volumes replace it through a symlink swap. The
+// leader election settings are kept, the elector runs with the ones read at start
+func (s *SettingsStore) Watch(ctx context.Context, path string, interval time.Duration, onChange func(old, new Settings), logger log.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		old := s.Get()
+		changed, leaderElectionChanged, err := s.load(path)
+		if err != nil {
+			logger.WithField("config", path).Errorf("Unable to reload the configuration, keeping the previous one: %s", err)
+			continue
+		}
+		if leaderElectionChanged {
+			logger.WithField("config", path).Warningf("The leaderElection settings changed, they are applied on the next restart of the operator")
+		}
+		if changed {
+			logger.WithField("config", path).Infof("Configuration reloaded")
+			onChange(old, s.Get())
+		}
+	}
+}
+
+// load reads the given configuration file, and returns whether its content changed and whether it changes the leader
+// election settings, left untouched once the settings are in use
+func (s *SettingsStore) load(path string) (bool, bool, error) {
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		content = nil
+	} else if err != nil {
+		return false, false, err
+	}
+
+	s.mutex.RLock()
+	changed := !bytes.Equal(content, s.content)
+	s.mutex.RUnlock()
+	if !changed {
+		return false, false, nil
+	}
+
+	settings, err := ParseSettings(content)
+	if err != nil {
+		return false, false, err
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	leaderElectionChanged := false
+	if s.running && settings.LeaderElection != s.settings.LeaderElection {
+		leaderElectionChanged = true
+		settings.LeaderElection = s.settings.LeaderElection
+	}
+	s.settings, s.content = settings, content
+	return true, leaderElectionChanged, nil
+}
@@ -0,0 +1,169 @@
+package redisfailover
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
+	"github.com/spotahome/redis-operator/log"
+)
+
+func TestParseSettings(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		expErr  bool
+	}{
+		{
+			name: "every setting",
+			content: `
+resyncInterval: 1m
+leaderElection:
+  leaseDuration: 30s
+  renewDeadline: 20s
+  retryPeriod: 5s
+logLevel: debug
+logFormat: json
+images:
+  redis: redis:7.0.8-alpine
+imageDigests:
+  redis:7.0.8-alpine: sha256:0123
+registryMirrors:
+- from: docker.io
+  to: registry.internal/dockerhub
+exporterResources:
+  limits:
+    memory: 64Mi
+`,
+		},
+		{name: "empty file"},
+		{name: "unknown setting", content: "resync: 1m", expErr: true},
+		{name: "unknown log format", content: "logFormat: xml", expErr: true},
+		{name: "negative resync interval", content: "resyncInterval: -1m", expErr: true},
+		{name: "digest without algorithm", content: "imageDigests:\n  redis:7.0.8-alpine: 0123", expErr: true},
+		{name: "mirror without destination", content: "registryMirrors:\n- from: quay.io", expErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ParseSettings([]byte(test.content))
+			if test.expErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSettingsRewriteImage(t *testing.T) {
+	settings := Settings{
+		ImageDigests: map[string]string{"redis:7.0.8-alpine": "sha256:0123"},
+		RegistryMirrors: []RegistryMirror{
+			{From: "quay.io/oliver006", To: "registry.internal/exporters"},
+			{From: "docker.io", To: "registry.internal/dockerhub/"},
+		},
+	}
+
+	tests := []struct {
+		image string
+		exp   string
+	}{
+		{image: "redis:7.0.8-alpine", exp: "registry.internal/dockerhub/library/redis:7.0.8-alpine@sha256:0123"},
+		{image: "envoyproxy/envoy:v1.24.1", exp: "registry.internal/dockerhub/envoyproxy/envoy:v1.24.1"},
+		{image: "docker.io/library/redis:6.2.6-alpine", exp: "registry.internal/dockerhub/library/redis:6.2.6-alpine"},
+		{image: "quay.io/oliver006/redis_exporter:v1.43.0", exp: "registry.internal/exporters/redis_exporter:v1.43.0"},
+		{image: "quay.io/other/image:v1", exp: "quay.io/other/image:v1"},
+		{image: "localhost:5000/redis:7", exp: "localhost:5000/redis:7"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.image, func(t *testing.T) {
+			assert.Equal(t, test.exp, settings.rewriteImage(test.image))
+		})
+	}
+}
+
+func TestSettingsApply(t *testing.T) {
+	assert := assert.New(t)
+
+	exporterResources := &corev1.ResourceRequirements{
+		Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("64Mi")},
+	}
+	specResources := &corev1.ResourceRequirements{
+		Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("128Mi")},
+	}
+	settings := Settings{
+		Images:            DefaultImages{Redis: "redis:7.0.8-alpine", Exporter: "quay.io/oliver006/redis_exporter:v1.45.0"},
+		ExporterResources: exporterResources,
+		RegistryMirrors:   []RegistryMirror{{From: "docker.io", To: "registry.internal"}},
+	}
+	rf := &redisfailoverv1.RedisFailover{}
+	rf.Name = "test"
+	rf.Spec.Sentinel.Exporter.Resources = specResources
+	rf.Spec.Redis.InitContainers = []corev1.Container{{Name: "init", Image: "busybox:1.36"}}
+
+	settings.applyDefaults(rf)
+	assert.NoError(rf.Validate())
+	settings.rewriteImages(rf)
+
+	assert.Equal("registry.internal/library/redis:7.0.8-alpine", rf.Spec.Redis.Image)
+	assert.Equal("registry.internal/library/redis:7.0.8-alpine", rf.Spec.Sentinel.Image)
+	assert.Equal("quay.io/oliver006/redis_exporter:v1.45.0", rf.Spec.Redis.Exporter.Image)
+	assert.Equal("quay.io/oliver006/redis_exporter:v1.45.0", rf.Spec.Sentinel.Exporter.Image)
+	assert.Equal("registry.internal/library/busybox:1.36", rf.Spec.Redis.InitContainers[0].Image)
+	assert.Equal(exporterResources, rf.Spec.Redis.Exporter.Resources)
+	assert.Equal(specResources, rf.Spec.Sentinel.Exporter.Resources)
+}
+
+func TestSettingsStoreWatch(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	store, err := LoadSettingsStore(path)
+	require.NoError(err)
+	assert.Equal(Settings{}, store.Get())
+
+	require.NoError(os.WriteFile(path, []byte("logFormat: json\n"), 0644))
+	changes := make(chan Settings, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go store.Watch(ctx, path, 10*time.Millisecond, func(_, new Settings) { changes <- new }, log.Dummy)
+
+	select {
+	case settings := <-changes:
+		assert.Equal(log.JSONFormat, settings.LogFormat)
+		assert.Equal(log.JSONFormat, store.Get().LogFormat)
+	case <-time.After(5 * time.Second):
+		assert.Fail("the configuration wasn't reloaded")
+	}
+
+	// an invalid configuration is ignored
+	require.NoError(os.WriteFile(path, []byte("logFormat: xml\n"), 0644))
+	select {
+	case <-changes:
+		assert.Fail("an invalid configuration must not be applied")
+	case <-time.After(100 * time.Millisecond):
+	}
+	assert.Equal(log.JSONFormat, store.Get().LogFormat)
+
+	// the leader election settings are kept, the elector runs with the ones read at start
+	require.NoError(os.WriteFile(path, []byte("logFormat: text\nleaderElection:\n  leaseDuration: 30s\n"), 0644))
+	select {
+	case settings := <-changes:
+		assert.Equal(log.TextFormat, settings.LogFormat)
+		assert.Equal(LeaderElectionSettings{}, settings.LeaderElection)
+		assert.Equal(LeaderElectionSettings{}, store.Get().LeaderElection)
+	case <-time.After(5 * time.Second):
+		assert.Fail("the configuration wasn't reloaded")
+	}
+}
@@ -0,0 +1,217 @@
+package redisfailover
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/spotahome/kooper/v2/controller/leaderelection"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+// statusTimeout is the maximum time the health and leader endpoints wait for the Kubernetes API
+const statusTimeout = 5 * time.Second
+
+// LeaderStatus is the state of the leader election of the operator replicas
+type LeaderStatus struct {
+	// Leader is true when this replica holds the lease
+	Leader bool `json:"leader"`
+	// Since is the time this replica took the lead at
+	Since *metav1.Time `json:"since,omitempty"`
+	// Holder is the identity of the replica holding the lease, if any
+	Holder string `json:"holder,omitempty"`
+	// HolderSince is the time the lease was acquired at by its holder
+	HolderSince *metav1.Time `json:"holderSince,omitempty"`
+	// Lease is the namespace and name of the lease
+	Lease string `json:"lease"`
+}
+
+// Status tracks the state of the operator, to expose its health, readiness and leadership
+type Status struct {
+	k8sClient     kubernetes.Interface
+	lockNamespace string
+
+	mutex      sync.Mutex
+	leading    bool
+	since      time.Time
+	synced     []cache.InformerSynced
+	topologies *topologies
+}
+
+// NewStatus returns the status of an operator taking the lead through the lease of the given namespace
+func NewStatus(k8sClient kubernetes.Interface, lockNamespace string) *Status {
+	return &Status{
+		k8sClient:     k8sClient,
+		lockNamespace: lockNamespace,
+	}
+}
+
+// leaderElector wraps the given leader election so the status knows whether this replica holds the lease
+func (s *Status) leaderElector(runner leaderelection.Runner) leaderelection.Runner {
+	return leaderRunner{Runner: runner, status: s}
+}
+
+// waitForSync sets the informers the operator needs synced to be ready
+func (s *Status) waitForSync(synced ...cache.InformerSynced) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.synced = append(s.synced, synced...)
+}
+
+// serveTopologies sets the topologies served by TopologyHandler
+func (s *Status) serveTopologies(topologies *topologies) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.topologies = topologies
+}
+
+func (s *Status) setLeading(leading bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.leading = leading
+	s.since = time.Time{}
+	if leading {
+		s.since = time.Now()
+	}
+}
+
+// Healthy returns an error when the Kubernetes API can't be reached before the given context is done
+func (s *Status) Healthy(ctx context.Context) error {
+	discovery := s.k8sClient.Discovery()
+	// ServerVersion takes no context, the version is got the same way through the REST client of the discovery, the
+	// fake ones have none
+	if discovery.RESTClient() == nil {
+		_, err := discovery.ServerVersion()
+		return err
+	}
+	return discovery.RESTClient().Get().AbsPath("/version").Do(ctx).Error()
+}
+
+// Ready returns an error until the leader election is resolved, and this replica synced its caches once it holds the lease
+func (s *Status) Ready(ctx context.Context) error {
+	s.mutex.Lock()
+	leading, synced := s.leading, s.synced
+	s.mutex.Unlock()
+
+	if leading {
+		for _, hasSynced := range synced {
+			if !hasSynced() {
+				return fmt.Errorf("caches not synced")
+			}
+		}
+		return nil
+	}
+
+	leader, err := s.Leader(ctx)
+	if err != nil {
+		return err
+	}
+	if leader.Holder == "" {
+		return fmt.Errorf("leader election not resolved")
+	}
+	return nil
+}
+
+// Leader returns the state of the leader election
+func (s *Status) Leader(ctx context.Context) (LeaderStatus, error) {
+	s.mutex.Lock()
+	status := LeaderStatus{
+		Leader: s.leading,
+		Lease:  fmt.Sprintf("%s/%s", s.lockNamespace, lockKey),
+	}
+	if s.leading {
+		status.Since = &metav1.Time{Time: s.since}
+	}
+	s.mutex.Unlock()
+
+	lease, err := s.k8sClient.CoordinationV1().Leases(s.lockNamespace).Get(ctx, lockKey, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return status, nil
+	}
+	if err != nil {
+		return status, err
+	}
+	if lease.Spec.HolderIdentity != nil && !leaseExpired(lease.Spec.RenewTime, lease.Spec.LeaseDurationSeconds) {
+		status.Holder = *lease.Spec.HolderIdentity
+		if lease.Spec.AcquireTime != nil {
+			status.HolderSince = &metav1.Time{Time: lease.Spec.AcquireTime.Time}
+		}
+	}
+	return status, nil
+}
+
+// HealthzHandler serves the health of the operator
+func (s *Status) HealthzHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), statusTimeout)
+	defer cancel()
+	writeCheck(w, s.Healthy(ctx))
+}
+
+// ReadyzHandler serves the readiness of the operator
+func (s *Status) ReadyzHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), statusTimeout)
+	defer cancel()
+	writeCheck(w, s.Ready(ctx))
+}
+
+// LeaderHandler serves the state of the leader election as JSON
+func (s *Status) LeaderHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), statusTimeout)
+	defer cancel()
+	status, err := s.Leader(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(status)
+}
+
+// TopologyHandler serves the topology of a RedisFailover as JSON, as got on its last reconcile or right away with
+// ?refresh=true
+func (s *Status) TopologyHandler(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	topologies := s.topologies
+	s.mutex.Unlock()
+	if topologies == nil {
+		http.Error(w, "operator not started", http.StatusServiceUnavailable)
+		return
+	}
+	topologies.ServeHTTP(w, r)
+}
+
+func writeCheck(w http.ResponseWriter, err error) {
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	_, _ = w.Write([]byte("ok"))
+}
+
+func leaseExpired(renewTime *metav1.MicroTime, durationSeconds *int32) bool {
+	if renewTime == nil || durationSeconds == nil {
+		return false
+	}
+	return renewTime.Add(time.Duration(*durationSeconds) * time.Second).Before(time.Now())
+}
+
+// leaderRunner marks the status as leading while the controller runs with the lease
+type leaderRunner struct {
+	leaderelection.Runner
+	status *Status
+}
+
+func (l leaderRunner) Run(f func() error) error {
+	defer l.status.setLeading(false)
+	return l.Runner.Run(func() error {
+		l.status.setLeading(true)
+		defer l.status.setLeading(false)
+		return f()
+	})
+}
@@ -0,0 +1,115 @@
+package redisfailover
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8skubernetes "k8s.io/client-go/kubernetes"
+	kubernetes "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+)
+
+type fakeRunner struct{}
+
+func (fakeRunner) Run(f func() error) error {
+	return f()
+}
+
+func TestStatusReadiness(t *testing.T) {
+	assert := assert.New(t)
+
+	k8sClient := kubernetes.NewSimpleClientset()
+	status := NewStatus(k8sClient, "operatorns")
+	synced := false
+	status.waitForSync(func() bool { return synced })
+
+	// nobody holds the lease
+	assert.Error(status.Ready(context.TODO()))
+
+	// another replica holds the lease
+	holder := "other"
+	duration := int32(15)
+	_, err := k8sClient.CoordinationV1().Leases("operatorns").Create(context.TODO(), &coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{Name: lockKey, Namespace: "operatorns"},
+		Spec: coordinationv1.LeaseSpec{
+			HolderIdentity:       &holder,
+			LeaseDurationSeconds: &duration,
+			AcquireTime:          &metav1.MicroTime{Time: time.Now()},
+			RenewTime:            &metav1.MicroTime{Time: time.Now()},
+		},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err)
+	assert.NoError(status.Ready(context.TODO()))
+
+	// this replica holds the lease, it is ready once its caches are synced
+	err = status.leaderElector(fakeRunner{}).Run(func() error {
+		assert.Error(status.Ready(context.TODO()))
+		synced = true
+		assert.NoError(status.Ready(context.TODO()))
+		return nil
+	})
+	assert.NoError(err)
+}
+
+func TestStatusLeaderHandler(t *testing.T) {
+	assert := assert.New(t)
+
+	status := NewStatus(kubernetes.NewSimpleClientset(), "operatorns")
+
+	get := func() LeaderStatus {
+		rec := httptest.NewRecorder()
+		status.LeaderHandler(rec, httptest.NewRequest(http.MethodGet, "/leader", nil))
+		assert.Equal(http.StatusOK, rec.Code)
+		leader := LeaderStatus{}
+		assert.NoError(json.Unmarshal(rec.Body.Bytes(), &leader))
+		return leader
+	}
+
+	leader := get()
+	assert.False(leader.Leader)
+	assert.Nil(leader.Since)
+	assert.Equal("operatorns/"+lockKey, leader.Lease)
+
+	_ = status.leaderElector(fakeRunner{}).Run(func() error {
+		leader := get()
+		assert.True(leader.Leader)
+		assert.NotNil(leader.Since)
+		return nil
+	})
+
+	// the leadership is dropped once the controller stops
+	assert.False(get().Leader)
+}
+
+func TestStatusHealthzHandler(t *testing.T) {
+	rec := httptest.NewRecorder()
+	NewStatus(kubernetes.NewSimpleClientset(), "operatorns").HealthzHandler(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestStatusHealthyTimeout(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	// the API server never answers
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+	client, err := k8skubernetes.NewForConfig(&rest.Config{Host: server.URL})
+	require.NoError(err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	assert.Error(NewStatus(client, "operatorns").Healthy(ctx))
+	assert.Less(time.Since(start), 5*time.Second)
+}
@@ -0,0 +1,291 @@
+package redisfailover
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
+	"github.com/spotahome/redis-operator/log"
+	"github.com/spotahome/redis-operator/metrics"
+	rfservice "github.com/spotahome/redis-operator/operator/redisfailover/service"
+	"github.com/spotahome/redis-operator/service/k8s"
+	"github.com/spotahome/redis-operator/service/redis"
+)
+
+const (
+	// sentinelResubscribeInterval is the time waited before subscribing again when a sentinel can't be reached
+	sentinelResubscribeInterval = 5 * time.Second
+
+	sentinelSwitchMaster   = "+switch-master"
+	sentinelSubjectiveDown = "+sdown"
+	sentinelObjectiveDown  = "+odown"
+	sentinelObjectiveUp    = "-odown"
+	sentinelFailoverAbort  = "-failover-abort-"
+)
+
+// sentinelEventPatterns are the sentinel channels listened to
+var sentinelEventPatterns = []string{sentinelSwitchMaster, sentinelSubjectiveDown, sentinelObjectiveDown, sentinelObjectiveUp, sentinelFailoverAbort + "*"}
+
+// sentinelSubscribers keeps a subscription to the events of the sentinels of every RedisFailover, so failovers are
+// reacted to as soon as sentinel performs them instead of on the next check
+type sentinelSubscribers struct {
+	k8sService    k8s.Services
+	rfChecker     rfservice.RedisFailoverCheck
+	rfHealer      rfservice.RedisFailoverHeal
+	redisClient   redis.Client
+	reconcile     func(namespace, name string)
+	mClient       metrics.Recorder
+	logger        log.Logger
+	retryInterval time.Duration
+	masterLosses  *masterLosses
+	switchovers   *switchovers
+
+	mutex         sync.Mutex
+	subscriptions map[string]context.CancelFunc
+}
+
+func newSentinelSubscribers(k8sService k8s.Services, rfChecker rfservice.RedisFailoverCheck, rfHealer rfservice.RedisFailoverHeal, redisClient redis.Client, reconcile func(namespace, name string), mClient metrics.Recorder, logger log.Logger) *sentinelSubscribers {
+	return &sentinelSubscribers{
+		k8sService:    k8sService,
+		rfChecker:     rfChecker,
+		rfHealer:      rfHealer,
+		redisClient:   redisClient,
+		reconcile:     reconcile,
+		mClient:       mClient,
+		logger:        logger,
+		retryInterval: sentinelResubscribeInterval,
+		masterLosses:  newMasterLosses(),
+		switchovers:   newSwitchovers(),
+		subscriptions: map[string]context.CancelFunc{},
+	}
+}
+
+// ensure starts the subscriber of the given RedisFailover when it isn't running
+func (s *sentinelSubscribers) ensure(rf *redisfailoverv1.RedisFailover) {
+	key := fmt.Sprintf("%s/%s", rf.Namespace, rf.Name)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if _, ok := s.subscriptions[key]; ok {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	s.subscriptions[key] = cancel
+	go func() {
+		s.run(ctx, rf.Namespace, rf.Name)
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		if ctx.Err() == nil {
+			delete(s.subscriptions, key)
+			cancel()
+		}
+	}()
+}
+
+// stopAll tears down every subscriber, once the operator isn't the leader anymore
+func (s *sentinelSubscribers) stopAll() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for key, cancel := range s.subscriptions {
+		cancel()
+		delete(s.subscriptions, key)
+	}
+}
+
+// run listens to the sentinels of the RedisFailover, subscribing to another one when the connection is lost,
+// until the context is done or the RedisFailover is deleted
+func (s *sentinelSubscribers) run(ctx context.Context, namespace, name string) {
+	ctx = log.NewContext(ctx, map[string]interface{}{"redisfailover": name, "namespace": namespace})
+	logger := log.FromContext(ctx, s.logger)
+	failoverStart := time.Time{}
+	for attempt := 0; ctx.Err() == nil; attempt++ {
+		rf, err := s.k8sService.GetRedisFailover(ctx, namespace, name)
+		if errors.IsNotFound(err) {
+			logger.Debugf("Redisfailover deleted, stopping the sentinel subscriber")
+			s.masterLosses.forget(namespace, name)
+			return
+		}
+		if err == nil {
+			failoverStart, err = s.listen(ctx, rf, attempt, failoverStart)
+		}
+		if err != nil {
+			logger.Debugf("Unable to listen to the sentinels: %s", err)
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(s.retryInterval):
+		}
+	}
+}
+
+// listen handles the events of one of the sentinels of the RedisFailover, the next one on every attempt, given the
+// time the ongoing failover started at. The RedisFailover is read again on every event, the subscription ends when
+// the port or the password of its sentinels change, to subscribe again with them
+func (s *sentinelSubscribers) listen(ctx context.Context, rf *redisfailoverv1.RedisFailover, attempt int, failoverStart time.Time) (time.Time, error) {
+	password, err := k8s.GetSentinelPassword(ctx, s.k8sService, rf)
+	if err != nil {
+		return failoverStart, err
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	events, err := s.subscribe(ctx, rf, password, attempt)
+	if err != nil {
+		return failoverStart, err
+	}
+
+	for event := range events {
+		current, err := s.k8sService.GetRedisFailover(ctx, rf.Namespace, rf.Name)
+		if err != nil {
+			return failoverStart, err
+		}
+		currentPassword, err := k8s.GetSentinelPassword(ctx, s.k8sService, current)
+		if err != nil {
+			return failoverStart, err
+		}
+		if current.Spec.Sentinel.Port != rf.Spec.Sentinel.Port || currentPassword != password {
+			return failoverStart, fmt.Errorf("the port or the password of the sentinels changed")
+		}
+		failoverStart = s.handleEvent(ctx, current, event, failoverStart)
+	}
+	return failoverStart, fmt.Errorf("subscription lost")
+}
+
+// subscribe subscribes to one of the sentinels of the RedisFailover, the next one on every attempt
+func (s *sentinelSubscribers) subscribe(ctx context.Context, rf *redisfailoverv1.RedisFailover, password string, attempt int) (<-chan redis.SentinelEvent, error) {
+	sentinels, err := s.rfChecker.GetSentinelsIPs(ctx, rf)
+	if err != nil {
+		return nil, err
+	}
+	if len(sentinels) == 0 {
+		return nil, fmt.Errorf("no sentinel ready")
+	}
+	sentinel := sentinels[attempt%len(sentinels)]
+	return s.redisClient.SubscribeSentinel(ctx, sentinel, strconv.Itoa(int(rf.Spec.Sentinel.Port)), password, sentinelEventPatterns)
+}
+
+// handleEvent reacts to an event of the sentinels of the RedisFailover, given the time the ongoing failover started at,
+// and returns it updated
+func (s *sentinelSubscribers) handleEvent(ctx context.Context, rf *redisfailoverv1.RedisFailover, event redis.SentinelEvent, failoverStart time.Time) time.Time {
+	logger := rfservice.Logger(ctx, s.logger, rf)
+	fields := strings.Fields(event.Payload)
+
+	switch {
+	case event.Channel == sentinelSubjectiveDown:
+		logger.Infof("Sentinel sees %s as down", event.Payload)
+
+	case event.Channel == sentinelObjectiveDown:
+		// <instance-type> <name> <ip> <port> @ <master-name> <master-ip> <master-port>, the master ones have no @ part
+		if len(fields) < 4 || fields[0] != "master" || fields[1] != rf.Spec.Sentinel.MasterName || !failoverStart.IsZero() {
+			return failoverStart
+		}
+		logger.Warningf("Master %s:%s is down, sentinel starts a failover", fields[2], fields[3])
+		s.masterLosses.lost(rf.Namespace, rf.Name, time.Now(), true)
+		s.recordEvent(ctx, rf, corev1.EventTypeWarning, "FailoverStarted", fmt.Sprintf("Master %s:%s is down", fields[2], fields[3]))
+		return time.Now()
+
+	case event.Channel == sentinelObjectiveUp:
+		if len(fields) < 2 || fields[0] != "master" || fields[1] != rf.Spec.Sentinel.MasterName {
+			return failoverStart
+		}
+		logger.Infof("Master %s is back", event.Payload)
+		// the reconcile finds the master, ending its loss
+		s.reconcile(rf.Namespace, rf.Name)
+		return time.Time{}
+
+	case event.Channel == sentinelSwitchMaster:
+		// <master-name> <old-ip> <old-port> <new-ip> <new-port>
+		if len(fields) < 5 || fields[0] != rf.Spec.Sentinel.MasterName {
+			return failoverStart
+		}
+		logger.Infof("Sentinel switched the master from %s:%s to %s:%s", fields[1], fields[2], fields[3], fields[4])
+		if !rf.Paused() {
+			if master, err := s.masterAddress(ctx, rf, fields[3], fields[4]); err != nil {
+				logger.Warningf("Leaving the role labels to the reconcile: %s", err)
+			} else if err := s.rfHealer.SetRedisRoleLabels(ctx, master, rf); err != nil {
+				logger.Errorf("Unable to update the role labels: %s", err)
+			}
+			s.reconcile(rf.Namespace, rf.Name)
+		}
+		if !failoverStart.IsZero() {
+			s.mClient.RecordSentinelFailover(rf.Namespace, rf.Name, metrics.FAILOVER_SUCCEEDED, time.Since(failoverStart))
+		}
+		// the switchovers of the operator are performed by sentinel too, they are told apart by the status
+		initiator := metrics.FAILOVER_BY_SENTINEL
+		if rf.Status.Switchover != nil || s.switchovers.isRunning(rf.Namespace, rf.Name) {
+			initiator = metrics.FAILOVER_BY_OPERATOR
+		}
+		s.mClient.RecordFailover(rf.Namespace, rf.Name, initiator)
+		s.recordEvent(ctx, rf, corev1.EventTypeNormal, "FailoverCompleted", fmt.Sprintf("Master switched from %s:%s to %s:%s", fields[1], fields[2], fields[3], fields[4]))
+		return time.Time{}
+
+	case strings.HasPrefix(event.Channel, sentinelFailoverAbort):
+		// <instance-type> <name> <ip> <port> of the master
+		if len(fields) < 2 || fields[1] != rf.Spec.Sentinel.MasterName {
+			return failoverStart
+		}
+		logger.Warningf("Sentinel aborted the failover: %s", strings.TrimPrefix(event.Channel, sentinelFailoverAbort))
+		if !failoverStart.IsZero() {
+			s.mClient.RecordSentinelFailover(rf.Namespace, rf.Name, metrics.FAILOVER_ABORTED, time.Since(failoverStart))
+		}
+		s.recordEvent(ctx, rf, corev1.EventTypeWarning, "FailoverAborted", fmt.Sprintf("Failover aborted: %s", strings.TrimPrefix(event.Channel, sentinelFailoverAbort)))
+		return time.Time{}
+	}
+	return failoverStart
+}
+
+// masterAddress returns the address the operator reaches the master sentinel announced with the given ip and port
+// by. With external access sentinel announces the external address of the redises, mapped back to the one of their pod
+func (s *sentinelSubscribers) masterAddress(ctx context.Context, rf *redisfailoverv1.RedisFailover, ip, port string) (string, error) {
+	if rf.Spec.ExternalAccess == nil {
+		return ip, nil
+	}
+	addresses, err := s.rfChecker.GetRedisesExternalAddresses(ctx, rf)
+	if err != nil {
+		return "", err
+	}
+	announced := net.JoinHostPort(ip, port)
+	for address, external := range addresses {
+		if external == announced {
+			return address, nil
+		}
+	}
+	return "", fmt.Errorf("no redis is reachable from outside at %s", announced)
+}
+
+func (s *sentinelSubscribers) recordEvent(ctx context.Context, rf *redisfailoverv1.RedisFailover, eventType, reason, message string) {
+	now := metav1.Now()
+	event := &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: rf.Name + "-",
+			Namespace:    rf.Namespace,
+		},
+		InvolvedObject: corev1.ObjectReference{
+			APIVersion:      redisfailoverv1.SchemeGroupVersion.String(),
+			Kind:            redisfailoverv1.RFKind,
+			Name:            rf.Name,
+			Namespace:       rf.Namespace,
+			UID:             rf.UID,
+			ResourceVersion: rf.ResourceVersion,
+		},
+		Reason:         reason,
+		Message:        message,
+		Type:           eventType,
+		Source:         corev1.EventSource{Component: operatorName},
+		FirstTimestamp: now,
+		LastTimestamp:  now,
+		Count:          1,
+	}
+	if err := s.k8sService.CreateEvent(ctx, rf.Namespace, event); err != nil {
+		rfservice.Logger(ctx, s.logger, rf).Warningf("Unable to record the %s event: %s", reason, err)
+	}
+}
@@ -0,0 +1,283 @@
+package redisfailover
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
+	"github.com/spotahome/redis-operator/log"
+	"github.com/spotahome/redis-operator/metrics"
+	mRFService "github.com/spotahome/redis-operator/mocks/operator/redisfailover/service"
+	mK8SService "github.com/spotahome/redis-operator/mocks/service/k8s"
+	mRedisService "github.com/spotahome/redis-operator/mocks/service/redis"
+	"github.com/spotahome/redis-operator/service/redis"
+)
+
+type failoverRecorder struct {
+	metrics.Recorder
+	results    []string
+	initiators []string
+}
+
+func (f *failoverRecorder) RecordSentinelFailover(_ string, _ string, result string, _ time.Duration) {
+	f.results = append(f.results, result)
+}
+
+func (f *failoverRecorder) RecordFailover(_ string, _ string, initiator string) {
+	f.initiators = append(f.initiators, initiator)
+}
+
+func TestSentinelSubscriberHandleEvents(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := &redisfailoverv1.RedisFailover{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "testns"},
+		Spec: redisfailoverv1.RedisFailoverSpec{
+			Sentinel: redisfailoverv1.SentinelSettings{Port: 26379, MasterName: "mymaster"},
+		},
+	}
+	events := make(chan redis.SentinelEvent, 10)
+
+	ms := &mK8SService.Services{}
+	// read when subscribing and on every event
+	ms.On("GetRedisFailover", mock.Anything, "testns", "test").Times(6).Return(rf, nil)
+	ms.On("GetRedisFailover", mock.Anything, "testns", "test").Return(nil, kerrors.NewNotFound(schema.GroupResource{}, "test"))
+	ms.On("CreateEvent", mock.Anything, "testns", mock.MatchedBy(func(e *corev1.Event) bool { return e.Reason == "FailoverStarted" })).Once().Return(nil)
+	ms.On("CreateEvent", mock.Anything, "testns", mock.MatchedBy(func(e *corev1.Event) bool { return e.Reason == "FailoverCompleted" })).Once().Return(nil)
+	mrfc := &mRFService.RedisFailoverCheck{}
+	mrfc.On("GetSentinelsIPs", mock.Anything, rf).Once().Return([]string{"0.0.0.0"}, nil)
+	mrfh := &mRFService.RedisFailoverHeal{}
+	mrfh.On("SetRedisRoleLabels", mock.Anything, "1.1.1.1", rf).Once().Return(nil)
+	mr := &mRedisService.Client{}
+	mr.On("SubscribeSentinel", mock.Anything, "0.0.0.0", "26379", "", sentinelEventPatterns).Once().Return((<-chan redis.SentinelEvent)(events), nil)
+
+	reconciled := []string{}
+	recorder := &failoverRecorder{Recorder: metrics.Dummy}
+	subscribers := newSentinelSubscribers(ms, mrfc, mrfh, mr, func(namespace, name string) {
+		reconciled = append(reconciled, namespace+"/"+name)
+	}, recorder, log.Dummy)
+	subscribers.retryInterval = time.Millisecond
+
+	// events of other masters are ignored
+	events <- redis.SentinelEvent{Channel: "+odown", Payload: "master othermaster 0.0.0.0 6379 #quorum 2/2"}
+	events <- redis.SentinelEvent{Channel: "+sdown", Payload: "master mymaster 0.0.0.0 6379"}
+	events <- redis.SentinelEvent{Channel: "+odown", Payload: "master mymaster 0.0.0.0 6379 #quorum 2/2"}
+	events <- redis.SentinelEvent{Channel: "+switch-master", Payload: "othermaster 0.0.0.0 6379 2.2.2.2 6379"}
+	events <- redis.SentinelEvent{Channel: "+switch-master", Payload: "mymaster 0.0.0.0 6379 1.1.1.1 6379"}
+	close(events)
+
+	subscribers.ensure(rf)
+	assert.Eventually(func() bool {
+		subscribers.mutex.Lock()
+		defer subscribers.mutex.Unlock()
+		return len(subscribers.subscriptions) == 0
+	}, 5*time.Second, 10*time.Millisecond, "the subscriber stops once the redisfailover is deleted")
+
+	assert.Equal([]string{"testns/test"}, reconciled)
+	assert.Equal([]string{metrics.FAILOVER_SUCCEEDED}, recorder.results)
+	assert.Equal([]string{metrics.FAILOVER_BY_SENTINEL}, recorder.initiators)
+	subscribers.masterLosses.mutex.Lock()
+	assert.Empty(subscribers.masterLosses.since, "the master losses are forgotten once the redisfailover is deleted")
+	assert.Empty(subscribers.masterLosses.seen)
+	subscribers.masterLosses.mutex.Unlock()
+	ms.AssertExpectations(t)
+	mrfc.AssertExpectations(t)
+	mrfh.AssertExpectations(t)
+	mr.AssertExpectations(t)
+}
+
+func TestSentinelSubscriberReadsRedisFailoverOnEvents(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := &redisfailoverv1.RedisFailover{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "testns"},
+		Spec: redisfailoverv1.RedisFailoverSpec{
+			Sentinel: redisfailoverv1.SentinelSettings{Port: 26379, MasterName: "mymaster"},
+		},
+	}
+	renamed := rf.DeepCopy()
+	renamed.Spec.Sentinel.MasterName = "newmaster"
+	moved := rf.DeepCopy()
+	moved.Spec.Sentinel.Port = 26380
+	events := make(chan redis.SentinelEvent, 10)
+
+	ms := &mK8SService.Services{}
+	ms.On("GetRedisFailover", mock.Anything, "testns", "test").Once().Return(rf, nil)
+	ms.On("GetRedisFailover", mock.Anything, "testns", "test").Once().Return(renamed, nil)
+	ms.On("GetRedisFailover", mock.Anything, "testns", "test").Once().Return(moved, nil)
+	ms.On("GetRedisFailover", mock.Anything, "testns", "test").Return(nil, kerrors.NewNotFound(schema.GroupResource{}, "test"))
+	ms.On("CreateEvent", mock.Anything, "testns", mock.Anything).Once().Return(nil)
+	mrfc := &mRFService.RedisFailoverCheck{}
+	mrfc.On("GetSentinelsIPs", mock.Anything, rf).Once().Return([]string{"0.0.0.0"}, nil)
+	mrfh := &mRFService.RedisFailoverHeal{}
+	mrfh.On("SetRedisRoleLabels", mock.Anything, "1.1.1.1", renamed).Once().Return(nil)
+	mr := &mRedisService.Client{}
+	mr.On("SubscribeSentinel", mock.Anything, "0.0.0.0", "26379", "", sentinelEventPatterns).Once().Return((<-chan redis.SentinelEvent)(events), nil)
+
+	reconciled := []string{}
+	subscribers := newSentinelSubscribers(ms, mrfc, mrfh, mr, func(namespace, name string) {
+		reconciled = append(reconciled, namespace+"/"+name)
+	}, metrics.Dummy, log.Dummy)
+	subscribers.retryInterval = time.Millisecond
+
+	// the master was renamed since the subscription started
+	events <- redis.SentinelEvent{Channel: "+switch-master", Payload: "newmaster 0.0.0.0 6379 1.1.1.1 6379"}
+	// the port of the sentinels changed, the event isn't handled and the subscription ends
+	events <- redis.SentinelEvent{Channel: "+switch-master", Payload: "mymaster 1.1.1.1 6379 0.0.0.0 6379"}
+
+	subscribers.ensure(rf)
+	assert.Eventually(func() bool {
+		subscribers.mutex.Lock()
+		defer subscribers.mutex.Unlock()
+		return len(subscribers.subscriptions) == 0
+	}, 5*time.Second, 10*time.Millisecond, "the subscriber stops once the redisfailover is deleted")
+
+	assert.Equal([]string{"testns/test"}, reconciled)
+	ms.AssertExpectations(t)
+	mrfc.AssertExpectations(t)
+	mrfh.AssertExpectations(t)
+	mr.AssertExpectations(t)
+}
+
+func TestSentinelSubscriberExternalAccessSwitchMaster(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := &redisfailoverv1.RedisFailover{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "testns"},
+		Spec: redisfailoverv1.RedisFailoverSpec{
+			Sentinel:       redisfailoverv1.SentinelSettings{MasterName: "mymaster"},
+			ExternalAccess: &redisfailoverv1.ExternalAccessSettings{Type: corev1.ServiceTypeLoadBalancer},
+		},
+	}
+	ms := &mK8SService.Services{}
+	ms.On("CreateEvent", mock.Anything, "testns", mock.Anything).Return(nil)
+	mrfc := &mRFService.RedisFailoverCheck{}
+	mrfc.On("GetRedisesExternalAddresses", mock.Anything, rf).Return(map[string]string{
+		"10.0.0.1": "1.1.1.1:30001",
+		"10.0.0.2": "1.1.1.2:30002",
+	}, nil)
+	mrfh := &mRFService.RedisFailoverHeal{}
+	mrfh.On("SetRedisRoleLabels", mock.Anything, "10.0.0.1", rf).Once().Return(nil)
+
+	reconciled := 0
+	subscribers := newSentinelSubscribers(ms, mrfc, mrfh, nil, func(namespace, name string) {
+		reconciled++
+	}, metrics.Dummy, log.Dummy)
+
+	// sentinel announces the external address of the master, the labels are set on its pod
+	subscribers.handleEvent(context.Background(), rf, redis.SentinelEvent{Channel: "+switch-master", Payload: "mymaster 1.1.1.2 30002 1.1.1.1 30001"}, time.Time{})
+	// an unknown address leaves the labels to the reconcile
+	subscribers.handleEvent(context.Background(), rf, redis.SentinelEvent{Channel: "+switch-master", Payload: "mymaster 1.1.1.1 30001 9.9.9.9 30009"}, time.Time{})
+
+	assert.Equal(2, reconciled)
+	mrfh.AssertExpectations(t)
+}
+
+func TestSentinelSubscriberFailoverAborted(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := &redisfailoverv1.RedisFailover{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "testns"},
+		Spec: redisfailoverv1.RedisFailoverSpec{
+			Sentinel: redisfailoverv1.SentinelSettings{MasterName: "mymaster"},
+		},
+	}
+	ms := &mK8SService.Services{}
+	ms.On("CreateEvent", mock.Anything, "testns", mock.Anything).Return(nil)
+	recorder := &failoverRecorder{Recorder: metrics.Dummy}
+	subscribers := newSentinelSubscribers(ms, nil, nil, nil, nil, recorder, log.Dummy)
+
+	start := subscribers.handleEvent(context.Background(), rf, redis.SentinelEvent{Channel: "+odown", Payload: "master mymaster 0.0.0.0 6379 #quorum 2/2"}, time.Time{})
+	assert.False(start.IsZero())
+	_, lost := subscribers.masterLosses.found("testns", "test")
+	assert.True(lost, "the master is lost from the objective down of sentinel")
+	start = subscribers.handleEvent(context.Background(), rf, redis.SentinelEvent{Channel: "-failover-abort-no-good-slave", Payload: "master mymaster 0.0.0.0 6379"}, start)
+	assert.True(start.IsZero())
+	assert.Equal([]string{metrics.FAILOVER_ABORTED}, recorder.results)
+}
+
+func TestSentinelSubscriberOperatorSwitchover(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := &redisfailoverv1.RedisFailover{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "testns"},
+		Spec: redisfailoverv1.RedisFailoverSpec{
+			Sentinel: redisfailoverv1.SentinelSettings{MasterName: "mymaster"},
+		},
+	}
+	switching := rf.DeepCopy()
+	switching.Status.Switchover = &redisfailoverv1.SwitchoverStatus{StartTime: metav1.Now()}
+	ms := &mK8SService.Services{}
+	ms.On("CreateEvent", mock.Anything, "testns", mock.Anything).Return(nil)
+	mrfh := &mRFService.RedisFailoverHeal{}
+	mrfh.On("SetRedisRoleLabels", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	recorder := &failoverRecorder{Recorder: metrics.Dummy}
+	subscribers := newSentinelSubscribers(ms, nil, mrfh, nil, func(namespace, name string) {}, recorder, log.Dummy)
+
+	event := redis.SentinelEvent{Channel: "+switch-master", Payload: "mymaster 0.0.0.0 6379 1.1.1.1 6379"}
+	// the switchover is recorded in the status, as done by the command line
+	subscribers.handleEvent(context.Background(), switching, event, time.Time{})
+	// the drain switchover the operator runs, before its status is seen
+	subscribers.switchovers.start("testns", "test")
+	subscribers.handleEvent(context.Background(), rf, event, time.Time{})
+	subscribers.switchovers.done("testns", "test")
+	subscribers.handleEvent(context.Background(), rf, event, time.Time{})
+
+	assert.Equal([]string{metrics.FAILOVER_BY_OPERATOR, metrics.FAILOVER_BY_OPERATOR, metrics.FAILOVER_BY_SENTINEL}, recorder.initiators)
+}
+
+func TestSentinelSubscriberStopAll(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := &redisfailoverv1.RedisFailover{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "testns"},
+	}
+	ms := &mK8SService.Services{}
+	ms.On("GetRedisFailover", mock.Anything, "testns", "test").Return(rf, nil)
+	mrfc := &mRFService.RedisFailoverCheck{}
+	mrfc.On("GetSentinelsIPs", mock.Anything, rf).Return(nil, errors.New(""))
+
+	subscribers := newSentinelSubscribers(ms, mrfc, nil, nil, nil, metrics.Dummy, log.Dummy)
+	subscribers.retryInterval = time.Millisecond
+	subscribers.ensure(rf)
+	subscribers.ensure(rf)
+	assert.Len(subscribers.subscriptions, 1)
+
+	subscribers.stopAll()
+	assert.Len(subscribers.subscriptions, 0)
+
+	// the subscriber can be started again, as done when the leadership is taken back
+	subscribers.ensure(rf)
+	assert.Len(subscribers.subscriptions, 1)
+	subscribers.stopAll()
+}
+
+func TestSentinelSubscriberPaused(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := &redisfailoverv1.RedisFailover{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "testns"},
+		Spec: redisfailoverv1.RedisFailoverSpec{
+			Sentinel: redisfailoverv1.SentinelSettings{MasterName: "mymaster"},
+		},
+	}
+	rf.Annotations = map[string]string{redisfailoverv1.PausedAnnotation: "true"}
+	ms := &mK8SService.Services{}
+	ms.On("CreateEvent", mock.Anything, "testns", mock.Anything).Return(nil)
+	mrfh := &mRFService.RedisFailoverHeal{}
+
+	subscribers := newSentinelSubscribers(ms, nil, mrfh, nil, func(namespace, name string) {
+		assert.Fail("paused redisfailovers must not be reconciled")
+	}, metrics.Dummy, log.Dummy)
+	subscribers.handleEvent(context.Background(), rf, redis.SentinelEvent{Channel: "+switch-master", Payload: "mymaster 0.0.0.0 6379 1.1.1.1 6379"}, time.Time{})
+	mrfh.AssertNotCalled(t, "SetRedisRoleLabels", mock.Anything, mock.Anything)
+}
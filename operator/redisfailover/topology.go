@@ -0,0 +1,129 @@
+package redisfailover
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+
+	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
+	"github.com/spotahome/redis-operator/log"
+	"github.com/spotahome/redis-operator/metrics"
+	rfservice "github.com/spotahome/redis-operator/operator/redisfailover/service"
+	"github.com/spotahome/redis-operator/service/k8s"
+)
+
+// TopologyPathPrefix is the path the topologies are served under, as <prefix><namespace>/<name>/topology
+const TopologyPathPrefix = "/api/v1/redisfailovers/"
+
+// topologyRefreshTimeout is the maximum time a refresh of a topology waits for the redises and sentinels
+const topologyRefreshTimeout = 30 * time.Second
+
+// topologies keeps the topology of every RedisFailover got at the end of its last successful reconcile
+type topologies struct {
+	k8sService k8s.Services
+	rfChecker  rfservice.RedisFailoverCheck
+	mClient    metrics.Recorder
+	logger     log.Logger
+
+	mutex      sync.Mutex
+	topologies map[string]*rfservice.Topology
+}
+
+func newTopologies(k8sService k8s.Services, rfChecker rfservice.RedisFailoverCheck, mClient metrics.Recorder, logger log.Logger) *topologies {
+	return &topologies{
+		k8sService: k8sService,
+		rfChecker:  rfChecker,
+		mClient:    mClient,
+		logger:     logger,
+		topologies: map[string]*rfservice.Topology{},
+	}
+}
+
+// record gets and keeps the topology of the given RedisFailover, and sets the metrics of its redises
+func (t *topologies) record(ctx context.Context, rf *redisfailoverv1.RedisFailover) (*rfservice.Topology, error) {
+	topology, err := t.rfChecker.GetTopology(ctx, rf)
+	if err != nil {
+		return nil, err
+	}
+	t.mClient.SetRedisNodes(rf.Namespace, rf.Name, redisNodes(topology))
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.topologies[fmt.Sprintf("%s/%s", rf.Namespace, rf.Name)] = topology
+	return topology, nil
+}
+
+// redisNodes returns the state of the redises of the given topology, as reported in the metrics
+func redisNodes(topology *rfservice.Topology) []metrics.RedisNode {
+	nodes := []metrics.RedisNode{}
+	for _, redis := range topology.Redises {
+		node := metrics.RedisNode{Pod: redis.Pod}
+		if redis.Error == "" {
+			node.Role = redis.Role
+			node.Lag = redis.Lag
+			node.LinkUp = redis.LinkStatus == "up"
+			node.UsedMemory = redis.UsedMemory
+			node.ConnectedClients = redis.ConnectedClients
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
+
+// get returns the topology of the given RedisFailover, as got on its last reconcile or right away when refresh is set
+func (t *topologies) get(ctx context.Context, namespace, name string, refresh bool) (*rfservice.Topology, error) {
+	key := fmt.Sprintf("%s/%s", namespace, name)
+	rf, err := t.k8sService.GetRedisFailover(ctx, namespace, name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			t.mutex.Lock()
+			delete(t.topologies, key)
+			t.mutex.Unlock()
+		}
+		return nil, err
+	}
+	if refresh {
+		return t.record(ctx, rf)
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	topology, ok := t.topologies[key]
+	if !ok {
+		return nil, errors.NewNotFound(redisfailoverv1.SchemeGroupVersion.WithResource("redisfailovers").GroupResource(), name)
+	}
+	return topology, nil
+}
+
+// ServeHTTP serves the topology of a RedisFailover on GET <prefix><namespace>/<name>/topology, refreshed with ?refresh=true
+func (t *topologies) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, TopologyPathPrefix), "/")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] != "topology" {
+		http.NotFound(w, r)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), topologyRefreshTimeout)
+	defer cancel()
+	topology, err := t.get(ctx, parts[0], parts[1], r.URL.Query().Get("refresh") == "true")
+	if errors.IsNotFound(err) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		t.logger.WithField("redisfailover", parts[1]).WithField("namespace", parts[0]).Warningf("Unable to get the topology: %s", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(topology)
+}
@@ -0,0 +1,108 @@
+package redisfailover
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
+	"github.com/spotahome/redis-operator/log"
+	"github.com/spotahome/redis-operator/metrics"
+	mRFService "github.com/spotahome/redis-operator/mocks/operator/redisfailover/service"
+	mK8SService "github.com/spotahome/redis-operator/mocks/service/k8s"
+	rfservice "github.com/spotahome/redis-operator/operator/redisfailover/service"
+)
+
+func TestTopologyHandler(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := &redisfailoverv1.RedisFailover{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "testns"},
+	}
+	reconciled := &rfservice.Topology{Namespace: "testns", Name: "test", Redises: []rfservice.RedisTopology{{Pod: "rfr-test-0", Role: "master"}}}
+	refreshed := &rfservice.Topology{Namespace: "testns", Name: "test", Redises: []rfservice.RedisTopology{{Pod: "rfr-test-0", Role: "slave"}}}
+
+	ms := &mK8SService.Services{}
+	ms.On("GetRedisFailover", mock.Anything, "testns", "test").Return(rf, nil)
+	ms.On("GetRedisFailover", mock.Anything, "testns", "other").Return(nil, errors.NewNotFound(schema.GroupResource{}, "other"))
+	mrfc := &mRFService.RedisFailoverCheck{}
+	mrfc.On("GetTopology", mock.Anything, rf).Once().Return(reconciled, nil)
+	// a refresh waits for the redises and sentinels for a limited time only
+	mrfc.On("GetTopology", mock.MatchedBy(func(ctx context.Context) bool {
+		_, ok := ctx.Deadline()
+		return ok
+	}), rf).Once().Return(refreshed, nil)
+
+	status := NewStatus(nil, "operatorns")
+	get := func(path string) (int, *rfservice.Topology) {
+		rec := httptest.NewRecorder()
+		status.TopologyHandler(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != http.StatusOK {
+			return rec.Code, nil
+		}
+		topology := &rfservice.Topology{}
+		assert.NoError(json.Unmarshal(rec.Body.Bytes(), topology))
+		return rec.Code, topology
+	}
+
+	// the operator isn't started yet
+	code, _ := get("/api/v1/redisfailovers/testns/test/topology")
+	assert.Equal(http.StatusServiceUnavailable, code)
+
+	topologies := newTopologies(ms, mrfc, metrics.Dummy, log.Dummy)
+	status.serveTopologies(topologies)
+
+	// the redisfailover wasn't reconciled yet
+	code, _ = get("/api/v1/redisfailovers/testns/test/topology")
+	assert.Equal(http.StatusNotFound, code)
+
+	_, err := topologies.record(context.Background(), rf)
+	assert.NoError(err)
+	code, topology := get("/api/v1/redisfailovers/testns/test/topology")
+	assert.Equal(http.StatusOK, code)
+	assert.Equal("master", topology.Redises[0].Role)
+
+	code, topology = get("/api/v1/redisfailovers/testns/test/topology?refresh=true")
+	assert.Equal(http.StatusOK, code)
+	assert.Equal("slave", topology.Redises[0].Role)
+
+	// the last topology got is kept
+	_, topology = get("/api/v1/redisfailovers/testns/test/topology")
+	assert.Equal("slave", topology.Redises[0].Role)
+
+	code, _ = get("/api/v1/redisfailovers/testns/other/topology")
+	assert.Equal(http.StatusNotFound, code)
+	code, _ = get("/api/v1/redisfailovers/testns/test")
+	assert.Equal(http.StatusNotFound, code)
+	mrfc.AssertExpectations(t)
+}
+
+func TestHandleRecordsTopologyOnlyOnSuccess(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := &redisfailoverv1.RedisFailover{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "testns"},
+	}
+	paused := rf.DeepCopy()
+	paused.Annotations = map[string]string{redisfailoverv1.PausedAnnotation: "true"}
+
+	// the checker fails on any call, the topology must not be got
+	mrfc := &mRFService.RedisFailoverCheck{}
+	mrfs := &mRFService.RedisFailoverClient{}
+	mrfs.On("EnsureNotPresentRedisService", mock.Anything, mock.Anything).Return(errors.NewServiceUnavailable("wrong"))
+
+	handler := NewRedisFailoverHandler(Config{}, mrfs, mrfc, nil, nil, metrics.Dummy, log.Dummy)
+	handler.topologies = newTopologies(nil, mrfc, metrics.Dummy, log.Dummy)
+	assert.NoError(handler.Handle(context.Background(), paused))
+	assert.Error(handler.Handle(context.Background(), rf))
+	mrfc.AssertNotCalled(t, "GetTopology", mock.Anything, mock.Anything)
+	mrfs.AssertExpectations(t)
+}
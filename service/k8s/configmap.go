@@ -2,7 +2,6 @@ package k8s
 
 import (
 	"context"
-	"fmt"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -15,12 +14,12 @@ import (
 
 // ConfigMap the ServiceAccount service that knows how to interact with k8s to manage them
 type ConfigMap interface {
-	GetConfigMap(namespace string, name string) (*corev1.ConfigMap, error)
-	CreateConfigMap(namespace string, configMap *corev1.ConfigMap) error
-	UpdateConfigMap(namespace string, configMap *corev1.ConfigMap) error
-	CreateOrUpdateConfigMap(namespace string, np *corev1.ConfigMap) error
-	DeleteConfigMap(namespace string, name string) error
-	ListConfigMaps(namespace string) (*corev1.ConfigMapList, error)
+	GetConfigMap(ctx context.Context, namespace string, name string) (*corev1.ConfigMap, error)
+	CreateConfigMap(ctx context.Context, namespace string, configMap *corev1.ConfigMap) error
+	UpdateConfigMap(ctx context.Context, namespace string, configMap *corev1.ConfigMap) error
+	CreateOrUpdateConfigMap(ctx context.Context, namespace string, np *corev1.ConfigMap) error
+	DeleteConfigMap(ctx context.Context, namespace string, name string) error
+	ListConfigMaps(ctx context.Context, namespace string) (*corev1.ConfigMapList, error)
 }
 
 // ConfigMapService is the configMap service implementation using API calls to kubernetes.
@@ -40,39 +39,42 @@ func NewConfigMapService(kubeClient kubernetes.Interface, logger log.Logger, met
 	}
 }
 
-func (p *ConfigMapService) GetConfigMap(namespace string, name string) (*corev1.ConfigMap, error) {
-	configMap, err := p.kubeClient.CoreV1().ConfigMaps(namespace).Get(context.TODO(), name, metav1.GetOptions{})
-	recordMetrics(namespace, "ConfigMap", name, "GET", err, p.metricsRecorder)
+func (p *ConfigMapService) GetConfigMap(ctx context.Context, namespace string, name string) (*corev1.ConfigMap, error) {
+	ctx, done := startOperation(ctx, namespace, "ConfigMap", name, "GET", p.metricsRecorder)
+	configMap, err := p.kubeClient.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+	done(err)
 	if err != nil {
 		return nil, err
 	}
 	return configMap, err
 }
 
-func (p *ConfigMapService) CreateConfigMap(namespace string, configMap *corev1.ConfigMap) error {
-	_, err := p.kubeClient.CoreV1().ConfigMaps(namespace).Create(context.TODO(), configMap, metav1.CreateOptions{})
-	recordMetrics(namespace, "ConfigMap", configMap.GetName(), "CREATE", err, p.metricsRecorder)
+func (p *ConfigMapService) CreateConfigMap(ctx context.Context, namespace string, configMap *corev1.ConfigMap) error {
+	ctx, done := startOperation(ctx, namespace, "ConfigMap", configMap.GetName(), "CREATE", p.metricsRecorder)
+	_, err := p.kubeClient.CoreV1().ConfigMaps(namespace).Create(ctx, configMap, metav1.CreateOptions{})
+	done(err)
 	if err != nil {
 		return err
 	}
-	p.logger.WithField("namespace", namespace).WithField("configMap", configMap.Name).Debugf("configMap created")
+	log.FromContext(ctx, p.logger).WithField("namespace", namespace).WithField("configMap", configMap.Name).Debugf("configMap created")
 	return nil
 }
-func (p *ConfigMapService) UpdateConfigMap(namespace string, configMap *corev1.ConfigMap) error {
-	_, err := p.kubeClient.CoreV1().ConfigMaps(namespace).Update(context.TODO(), configMap, metav1.UpdateOptions{})
-	recordMetrics(namespace, "ConfigMap", configMap.GetName(), "UPDATE", err, p.metricsRecorder)
+func (p *ConfigMapService) UpdateConfigMap(ctx context.Context, namespace string, configMap *corev1.ConfigMap) error {
+	ctx, done := startOperation(ctx, namespace, "ConfigMap", configMap.GetName(), "UPDATE", p.metricsRecorder)
+	_, err := p.kubeClient.CoreV1().ConfigMaps(namespace).Update(ctx, configMap, metav1.UpdateOptions{})
+	done(err)
 	if err != nil {
 		return err
 	}
-	p.logger.WithField("namespace", namespace).WithField("configMap", configMap.Name).Debugf("configMap updated")
+	log.FromContext(ctx, p.logger).WithField("namespace", namespace).WithField("configMap", configMap.Name).Debugf("configMap updated")
 	return nil
 }
-func (p *ConfigMapService) CreateOrUpdateConfigMap(namespace string, configMap *corev1.ConfigMap) error {
-	storedConfigMap, err := p.GetConfigMap(namespace, configMap.Name)
+func (p *ConfigMapService) CreateOrUpdateConfigMap(ctx context.Context, namespace string, configMap *corev1.ConfigMap) error {
+	storedConfigMap, err := p.GetConfigMap(ctx, namespace, configMap.Name)
 	if err != nil {
 		// If no resource we need to create.
 		if errors.IsNotFound(err) {
-			return p.CreateConfigMap(namespace, configMap)
+			return p.CreateConfigMap(ctx, namespace, configMap)
 		}
 		return err
 	}
@@ -82,18 +84,19 @@ func (p *ConfigMapService) CreateOrUpdateConfigMap(namespace string, configMap *
 	// namespace is our spec(https://github.com/kubernetes/community/blob/master/contributors/devel/api-conventions.md#concurrency-control-and-consistency),
 	// we will replace the current namespace state.
 	configMap.ResourceVersion = storedConfigMap.ResourceVersion
-	fmt.Println("update configmap resource version: ", configMap.ResourceVersion)
-	return p.UpdateConfigMap(namespace, configMap)
+	return p.UpdateConfigMap(ctx, namespace, configMap)
 }
 
-func (p *ConfigMapService) DeleteConfigMap(namespace string, name string) error {
-	err := p.kubeClient.CoreV1().ConfigMaps(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{})
-	recordMetrics(namespace, "ConfigMap", name, "DELETE", err, p.metricsRecorder)
+func (p *ConfigMapService) DeleteConfigMap(ctx context.Context, namespace string, name string) error {
+	ctx, done := startOperation(ctx, namespace, "ConfigMap", name, "DELETE", p.metricsRecorder)
+	err := p.kubeClient.CoreV1().ConfigMaps(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	done(err)
 	return err
 }
 
-func (p *ConfigMapService) ListConfigMaps(namespace string) (*corev1.ConfigMapList, error) {
-	objects, err := p.kubeClient.CoreV1().ConfigMaps(namespace).List(context.TODO(), metav1.ListOptions{})
-	recordMetrics(namespace, "ConfigMap", metrics.NOT_APPLICABLE, "LIST", err, p.metricsRecorder)
+func (p *ConfigMapService) ListConfigMaps(ctx context.Context, namespace string) (*corev1.ConfigMapList, error) {
+	ctx, done := startOperation(ctx, namespace, "ConfigMap", metrics.NOT_APPLICABLE, "LIST", p.metricsRecorder)
+	objects, err := p.kubeClient.CoreV1().ConfigMaps(namespace).List(ctx, metav1.ListOptions{})
+	done(err)
 	return objects, err
 }
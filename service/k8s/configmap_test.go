@@ -1,6 +1,7 @@
 package k8s_test
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -105,7 +106,7 @@ func TestConfigMapServiceGetCreateOrUpdate(t *testing.T) {
 			})
 
 			service := k8s.NewConfigMapService(mcli, log.Dummy, metrics.Dummy)
-			err := service.CreateOrUpdateConfigMap(testns, test.configMap)
+			err := service.CreateOrUpdateConfigMap(context.Background(), testns, test.configMap)
 
 			if test.expErr {
 				assert.Error(err)
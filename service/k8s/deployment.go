@@ -17,13 +17,13 @@ import (
 
 // Deployment the Deployment service that knows how to interact with k8s to manage them
 type Deployment interface {
-	GetDeployment(namespace, name string) (*appsv1.Deployment, error)
-	GetDeploymentPods(namespace, name string) (*corev1.PodList, error)
-	CreateDeployment(namespace string, deployment *appsv1.Deployment) error
-	UpdateDeployment(namespace string, deployment *appsv1.Deployment) error
-	CreateOrUpdateDeployment(namespace string, deployment *appsv1.Deployment) error
-	DeleteDeployment(namespace string, name string) error
-	ListDeployments(namespace string) (*appsv1.DeploymentList, error)
+	GetDeployment(ctx context.Context, namespace, name string) (*appsv1.Deployment, error)
+	GetDeploymentPods(ctx context.Context, namespace, name string) (*corev1.PodList, error)
+	CreateDeployment(ctx context.Context, namespace string, deployment *appsv1.Deployment) error
+	UpdateDeployment(ctx context.Context, namespace string, deployment *appsv1.Deployment) error
+	CreateOrUpdateDeployment(ctx context.Context, namespace string, deployment *appsv1.Deployment) error
+	DeleteDeployment(ctx context.Context, namespace string, name string) error
+	ListDeployments(ctx context.Context, namespace string) (*appsv1.DeploymentList, error)
 }
 
 // DeploymentService is the service account service implementation using API calls to kubernetes.
@@ -44,9 +44,10 @@ func NewDeploymentService(kubeClient kubernetes.Interface, logger log.Logger, me
 }
 
 // GetDeployment will retrieve the requested deployment based on namespace and name
-func (d *DeploymentService) GetDeployment(namespace, name string) (*appsv1.Deployment, error) {
-	deployment, err := d.kubeClient.AppsV1().Deployments(namespace).Get(context.TODO(), name, metav1.GetOptions{})
-	recordMetrics(namespace, "Deployment", name, "GET", err, d.metricsRecorder)
+func (d *DeploymentService) GetDeployment(ctx context.Context, namespace, name string) (*appsv1.Deployment, error) {
+	ctx, done := startOperation(ctx, namespace, "Deployment", name, "GET", d.metricsRecorder)
+	deployment, err := d.kubeClient.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+	done(err)
 	if err != nil {
 		return nil, err
 	}
@@ -54,9 +55,10 @@ func (d *DeploymentService) GetDeployment(namespace, name string) (*appsv1.Deplo
 }
 
 // GetDeploymentPods will retrieve the pods managed by a given deployment
-func (d *DeploymentService) GetDeploymentPods(namespace, name string) (*corev1.PodList, error) {
-	deployment, err := d.kubeClient.AppsV1().Deployments(namespace).Get(context.TODO(), name, metav1.GetOptions{})
-	recordMetrics(namespace, "Deployment", name, "GET", err, d.metricsRecorder)
+func (d *DeploymentService) GetDeploymentPods(ctx context.Context, namespace, name string) (*corev1.PodList, error) {
+	ctx, done := startOperation(ctx, namespace, "Deployment", name, "GET", d.metricsRecorder)
+	deployment, err := d.kubeClient.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+	done(err)
 	if err != nil {
 		return nil, err
 	}
@@ -65,38 +67,43 @@ func (d *DeploymentService) GetDeploymentPods(namespace, name string) (*corev1.P
 		labels = append(labels, fmt.Sprintf("%s=%s", k, v))
 	}
 	selector := strings.Join(labels, ",")
-	return d.kubeClient.CoreV1().Pods(namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: selector})
+	ctx, done = startOperation(ctx, namespace, "Pod", metrics.NOT_APPLICABLE, "LIST", d.metricsRecorder)
+	pods, err := d.kubeClient.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	done(err)
+	return pods, err
 }
 
 // CreateDeployment will create the given deployment
-func (d *DeploymentService) CreateDeployment(namespace string, deployment *appsv1.Deployment) error {
-	_, err := d.kubeClient.AppsV1().Deployments(namespace).Create(context.TODO(), deployment, metav1.CreateOptions{})
-	recordMetrics(namespace, "Deployment", deployment.GetName(), "CREATE", err, d.metricsRecorder)
+func (d *DeploymentService) CreateDeployment(ctx context.Context, namespace string, deployment *appsv1.Deployment) error {
+	ctx, done := startOperation(ctx, namespace, "Deployment", deployment.GetName(), "CREATE", d.metricsRecorder)
+	_, err := d.kubeClient.AppsV1().Deployments(namespace).Create(ctx, deployment, metav1.CreateOptions{})
+	done(err)
 	if err != nil {
 		return err
 	}
-	d.logger.WithField("namespace", namespace).WithField("deployment", deployment.ObjectMeta.Name).Debugf("deployment created")
+	log.FromContext(ctx, d.logger).WithField("namespace", namespace).WithField("deployment", deployment.ObjectMeta.Name).Debugf("deployment created")
 	return err
 }
 
 // UpdateDeployment will update the given deployment
-func (d *DeploymentService) UpdateDeployment(namespace string, deployment *appsv1.Deployment) error {
-	_, err := d.kubeClient.AppsV1().Deployments(namespace).Update(context.TODO(), deployment, metav1.UpdateOptions{})
-	recordMetrics(namespace, "Deployment", deployment.GetName(), "UPDATE", err, d.metricsRecorder)
+func (d *DeploymentService) UpdateDeployment(ctx context.Context, namespace string, deployment *appsv1.Deployment) error {
+	ctx, done := startOperation(ctx, namespace, "Deployment", deployment.GetName(), "UPDATE", d.metricsRecorder)
+	_, err := d.kubeClient.AppsV1().Deployments(namespace).Update(ctx, deployment, metav1.UpdateOptions{})
+	done(err)
 	if err != nil {
 		return err
 	}
-	d.logger.WithField("namespace", namespace).WithField("deployment", deployment.ObjectMeta.Name).Debugf("deployment updated")
+	log.FromContext(ctx, d.logger).WithField("namespace", namespace).WithField("deployment", deployment.ObjectMeta.Name).Debugf("deployment updated")
 	return err
 }
 
 // CreateOrUpdateDeployment will update the given deployment or create it if does not exist
-func (d *DeploymentService) CreateOrUpdateDeployment(namespace string, deployment *appsv1.Deployment) error {
-	storedDeployment, err := d.GetDeployment(namespace, deployment.Name)
+func (d *DeploymentService) CreateOrUpdateDeployment(ctx context.Context, namespace string, deployment *appsv1.Deployment) error {
+	storedDeployment, err := d.GetDeployment(ctx, namespace, deployment.Name)
 	if err != nil {
 		// If no resource we need to create.
 		if errors.IsNotFound(err) {
-			return d.CreateDeployment(namespace, deployment)
+			return d.CreateDeployment(ctx, namespace, deployment)
 		}
 		return err
 	}
@@ -106,20 +113,22 @@ func (d *DeploymentService) CreateOrUpdateDeployment(namespace string, deploymen
 	// namespace is our spec(https://github.com/kubernetes/community/blob/master/contributors/devel/api-conventions.md#concurrency-control-and-consistency),
 	// we will replace the current namespace state.
 	deployment.ResourceVersion = storedDeployment.ResourceVersion
-	return d.UpdateDeployment(namespace, deployment)
+	return d.UpdateDeployment(ctx, namespace, deployment)
 }
 
 // DeleteDeployment will delete the given deployment
-func (d *DeploymentService) DeleteDeployment(namespace, name string) error {
+func (d *DeploymentService) DeleteDeployment(ctx context.Context, namespace, name string) error {
 	propagation := metav1.DeletePropagationForeground
-	err := d.kubeClient.AppsV1().Deployments(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{PropagationPolicy: &propagation})
-	recordMetrics(namespace, "Deployment", name, "DELETE", err, d.metricsRecorder)
+	ctx, done := startOperation(ctx, namespace, "Deployment", name, "DELETE", d.metricsRecorder)
+	err := d.kubeClient.AppsV1().Deployments(namespace).Delete(ctx, name, metav1.DeleteOptions{PropagationPolicy: &propagation})
+	done(err)
 	return err
 }
 
 // ListDeployments will give all the deployments on a given namespace
-func (d *DeploymentService) ListDeployments(namespace string) (*appsv1.DeploymentList, error) {
-	deployments, err := d.kubeClient.AppsV1().Deployments(namespace).List(context.TODO(), metav1.ListOptions{})
-	recordMetrics(namespace, "Deployment", metrics.NOT_APPLICABLE, "LIST", err, d.metricsRecorder)
+func (d *DeploymentService) ListDeployments(ctx context.Context, namespace string) (*appsv1.DeploymentList, error) {
+	ctx, done := startOperation(ctx, namespace, "Deployment", metrics.NOT_APPLICABLE, "LIST", d.metricsRecorder)
+	deployments, err := d.kubeClient.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
+	done(err)
 	return deployments, err
 }
@@ -1,6 +1,7 @@
 package k8s_test
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -105,7 +106,7 @@ func TestDeploymentServiceGetCreateOrUpdate(t *testing.T) {
 			})
 
 			service := k8s.NewDeploymentService(mcli, log.Dummy, metrics.Dummy)
-			err := service.CreateOrUpdateDeployment(testns, test.deployment)
+			err := service.CreateOrUpdateDeployment(context.Background(), testns, test.deployment)
 
 			if test.expErr {
 				assert.Error(err)
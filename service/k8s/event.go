@@ -0,0 +1,46 @@
+package k8s
+
+import (
+	"context"
+
+	"github.com/spotahome/redis-operator/log"
+	"github.com/spotahome/redis-operator/metrics"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Event interacts with k8s to record events
+type Event interface {
+	// CreateEvent records the given event
+	CreateEvent(ctx context.Context, namespace string, event *corev1.Event) error
+}
+
+// EventService is the event service implementation using API calls to kubernetes.
+type EventService struct {
+	kubeClient      kubernetes.Interface
+	logger          log.Logger
+	metricsRecorder metrics.Recorder
+}
+
+// NewEventService returns a new Event KubeService.
+func NewEventService(kubeClient kubernetes.Interface, logger log.Logger, metricsRecorder metrics.Recorder) *EventService {
+	logger = logger.With("service", "k8s.event")
+	return &EventService{
+		kubeClient:      kubeClient,
+		logger:          logger,
+		metricsRecorder: metricsRecorder,
+	}
+}
+
+// CreateEvent satisfies the Event interface.
+func (e *EventService) CreateEvent(ctx context.Context, namespace string, event *corev1.Event) error {
+	ctx, done := startOperation(ctx, namespace, "Event", event.InvolvedObject.Name, "CREATE", e.metricsRecorder)
+	_, err := e.kubeClient.CoreV1().Events(namespace).Create(ctx, event, metav1.CreateOptions{})
+	done(err)
+	if err != nil {
+		return err
+	}
+	log.FromContext(ctx, e.logger).WithField("namespace", namespace).WithField("event", event.Reason).Debugf("event created for %s", event.InvolvedObject.Name)
+	return nil
+}
@@ -0,0 +1,37 @@
+package k8s_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubernetes "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/spotahome/redis-operator/log"
+	"github.com/spotahome/redis-operator/metrics"
+	"github.com/spotahome/redis-operator/service/k8s"
+)
+
+func TestEventServiceCreate(t *testing.T) {
+	assert := assert.New(t)
+
+	event := &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-event",
+			Namespace: "testns",
+		},
+		InvolvedObject: corev1.ObjectReference{Kind: "RedisFailover", Name: "test", Namespace: "testns"},
+		Reason:         "FailoverCompleted",
+		Type:           corev1.EventTypeNormal,
+	}
+
+	mcli := kubernetes.NewSimpleClientset()
+	service := k8s.NewEventService(mcli, log.Dummy, metrics.Dummy)
+
+	assert.NoError(service.CreateEvent(context.Background(), "testns", event))
+	got, err := mcli.CoreV1().Events("testns").Get(context.TODO(), "test-event", metav1.GetOptions{})
+	assert.NoError(err)
+	assert.Equal("FailoverCompleted", got.Reason)
+}
@@ -2,6 +2,7 @@ package k8s
 
 import (
 	apiextensionscli "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 
 	redisfailoverclientset "github.com/spotahome/redis-operator/client/k8s/clientset/versioned"
@@ -15,11 +16,15 @@ type Services interface {
 	Secret
 	Pod
 	PodDisruptionBudget
+	NetworkPolicy
 	RedisFailover
 	Service
 	RBAC
 	Deployment
 	StatefulSet
+	Event
+	Unstructured
+	Node
 }
 
 type services struct {
@@ -27,24 +32,32 @@ type services struct {
 	Secret
 	Pod
 	PodDisruptionBudget
+	NetworkPolicy
 	RedisFailover
 	Service
 	RBAC
 	Deployment
 	StatefulSet
+	Event
+	Unstructured
+	Node
 }
 
 // New returns a new Kubernetes service.
-func New(kubecli kubernetes.Interface, crdcli redisfailoverclientset.Interface, apiextcli apiextensionscli.Interface, logger log.Logger, metricsRecorder metrics.Recorder) Services {
+func New(kubecli kubernetes.Interface, crdcli redisfailoverclientset.Interface, apiextcli apiextensionscli.Interface, dynamiccli dynamic.Interface, logger log.Logger, metricsRecorder metrics.Recorder) Services {
 	return &services{
 		ConfigMap:           NewConfigMapService(kubecli, logger, metricsRecorder),
 		Secret:              NewSecretService(kubecli, logger, metricsRecorder),
 		Pod:                 NewPodService(kubecli, logger, metricsRecorder),
 		PodDisruptionBudget: NewPodDisruptionBudgetService(kubecli, logger, metricsRecorder),
+		NetworkPolicy:       NewNetworkPolicyService(kubecli, logger, metricsRecorder),
 		RedisFailover:       NewRedisFailoverService(crdcli, logger, metricsRecorder),
 		Service:             NewServiceService(kubecli, logger, metricsRecorder),
 		RBAC:                NewRBACService(kubecli, logger, metricsRecorder),
 		Deployment:          NewDeploymentService(kubecli, logger, metricsRecorder),
 		StatefulSet:         NewStatefulSetService(kubecli, logger, metricsRecorder),
+		Event:               NewEventService(kubecli, logger, metricsRecorder),
+		Unstructured:        NewUnstructuredService(dynamiccli, logger, metricsRecorder),
+		Node:                NewNodeService(kubecli, logger, metricsRecorder),
 	}
 }
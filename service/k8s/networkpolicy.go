@@ -0,0 +1,96 @@
+package k8s
+
+import (
+	"context"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/spotahome/redis-operator/log"
+	"github.com/spotahome/redis-operator/metrics"
+)
+
+// NetworkPolicy the service that knows how to interact with k8s to manage them
+type NetworkPolicy interface {
+	GetNetworkPolicy(ctx context.Context, namespace string, name string) (*networkingv1.NetworkPolicy, error)
+	CreateNetworkPolicy(ctx context.Context, namespace string, networkPolicy *networkingv1.NetworkPolicy) error
+	UpdateNetworkPolicy(ctx context.Context, namespace string, networkPolicy *networkingv1.NetworkPolicy) error
+	CreateOrUpdateNetworkPolicy(ctx context.Context, namespace string, networkPolicy *networkingv1.NetworkPolicy) error
+	DeleteNetworkPolicy(ctx context.Context, namespace string, name string) error
+}
+
+// NetworkPolicyService is the networkPolicy service implementation using API calls to kubernetes.
+type NetworkPolicyService struct {
+	kubeClient      kubernetes.Interface
+	logger          log.Logger
+	metricsRecorder metrics.Recorder
+}
+
+// NewNetworkPolicyService returns a new NetworkPolicy KubeService.
+func NewNetworkPolicyService(kubeClient kubernetes.Interface, logger log.Logger, metricsRecorder metrics.Recorder) *NetworkPolicyService {
+	logger = logger.With("service", "k8s.networkPolicy")
+	return &NetworkPolicyService{
+		kubeClient:      kubeClient,
+		logger:          logger,
+		metricsRecorder: metricsRecorder,
+	}
+}
+
+func (p *NetworkPolicyService) GetNetworkPolicy(ctx context.Context, namespace string, name string) (*networkingv1.NetworkPolicy, error) {
+	ctx, done := startOperation(ctx, namespace, "NetworkPolicy", name, "GET", p.metricsRecorder)
+	networkPolicy, err := p.kubeClient.NetworkingV1().NetworkPolicies(namespace).Get(ctx, name, metav1.GetOptions{})
+	done(err)
+	if err != nil {
+		return nil, err
+	}
+	return networkPolicy, nil
+}
+
+func (p *NetworkPolicyService) CreateNetworkPolicy(ctx context.Context, namespace string, networkPolicy *networkingv1.NetworkPolicy) error {
+	ctx, done := startOperation(ctx, namespace, "NetworkPolicy", networkPolicy.GetName(), "CREATE", p.metricsRecorder)
+	_, err := p.kubeClient.NetworkingV1().NetworkPolicies(namespace).Create(ctx, networkPolicy, metav1.CreateOptions{})
+	done(err)
+	if err != nil {
+		return err
+	}
+	log.FromContext(ctx, p.logger).WithField("namespace", namespace).WithField("networkPolicy", networkPolicy.Name).Debugf("networkPolicy created")
+	return nil
+}
+
+func (p *NetworkPolicyService) UpdateNetworkPolicy(ctx context.Context, namespace string, networkPolicy *networkingv1.NetworkPolicy) error {
+	ctx, done := startOperation(ctx, namespace, "NetworkPolicy", networkPolicy.GetName(), "UPDATE", p.metricsRecorder)
+	_, err := p.kubeClient.NetworkingV1().NetworkPolicies(namespace).Update(ctx, networkPolicy, metav1.UpdateOptions{})
+	done(err)
+	if err != nil {
+		return err
+	}
+	log.FromContext(ctx, p.logger).WithField("namespace", namespace).WithField("networkPolicy", networkPolicy.Name).Debugf("networkPolicy updated")
+	return nil
+}
+
+func (p *NetworkPolicyService) CreateOrUpdateNetworkPolicy(ctx context.Context, namespace string, networkPolicy *networkingv1.NetworkPolicy) error {
+	storedNetworkPolicy, err := p.GetNetworkPolicy(ctx, namespace, networkPolicy.Name)
+	if err != nil {
+		// If no resource we need to create.
+		if errors.IsNotFound(err) {
+			return p.CreateNetworkPolicy(ctx, namespace, networkPolicy)
+		}
+		return err
+	}
+
+	// Already exists, need to Update.
+	// Set the correct resource version to ensure we are on the latest version. This way the only valid
+	// namespace is our spec(https://github.com/kubernetes/community/blob/master/contributors/devel/api-conventions.md#concurrency-control-and-consistency),
+	// we will replace the current namespace state.
+	networkPolicy.ResourceVersion = storedNetworkPolicy.ResourceVersion
+	return p.UpdateNetworkPolicy(ctx, namespace, networkPolicy)
+}
+
+func (p *NetworkPolicyService) DeleteNetworkPolicy(ctx context.Context, namespace string, name string) error {
+	ctx, done := startOperation(ctx, namespace, "NetworkPolicy", name, "DELETE", p.metricsRecorder)
+	err := p.kubeClient.NetworkingV1().NetworkPolicies(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	done(err)
+	return err
+}
@@ -0,0 +1,118 @@
+package k8s_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	networkingv1 "k8s.io/api/networking/v1"
+	kubeerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	kubernetes "k8s.io/client-go/kubernetes/fake"
+	kubetesting "k8s.io/client-go/testing"
+
+	"github.com/spotahome/redis-operator/log"
+	"github.com/spotahome/redis-operator/metrics"
+	"github.com/spotahome/redis-operator/service/k8s"
+)
+
+var networkPolicysGroup = schema.GroupVersionResource{Group: "networking.k8s.io", Version: "v1", Resource: "networkpolicies"}
+
+func newNetworkPolicyUpdateAction(ns string, networkPolicy *networkingv1.NetworkPolicy) kubetesting.UpdateActionImpl {
+	return kubetesting.NewUpdateAction(networkPolicysGroup, ns, networkPolicy)
+}
+
+func newNetworkPolicyGetAction(ns, name string) kubetesting.GetActionImpl {
+	return kubetesting.NewGetAction(networkPolicysGroup, ns, name)
+}
+
+func newNetworkPolicyCreateAction(ns string, networkPolicy *networkingv1.NetworkPolicy) kubetesting.CreateActionImpl {
+	return kubetesting.NewCreateAction(networkPolicysGroup, ns, networkPolicy)
+}
+
+func TestNetworkPolicyServiceGetCreateOrUpdate(t *testing.T) {
+	testNetworkPolicy := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "testnetworkPolicy1",
+			ResourceVersion: "10",
+		},
+	}
+
+	testns := "testns"
+
+	tests := []struct {
+		name                   string
+		networkPolicy          *networkingv1.NetworkPolicy
+		getNetworkPolicyResult *networkingv1.NetworkPolicy
+		errorOnGet             error
+		errorOnCreation        error
+		expActions             []kubetesting.Action
+		expErr                 bool
+	}{
+		{
+			name:                   "A new networkPolicy should create a new networkPolicy.",
+			networkPolicy:          testNetworkPolicy,
+			getNetworkPolicyResult: nil,
+			errorOnGet:             kubeerrors.NewNotFound(schema.GroupResource{}, ""),
+			errorOnCreation:        nil,
+			expActions: []kubetesting.Action{
+				newNetworkPolicyGetAction(testns, testNetworkPolicy.ObjectMeta.Name),
+				newNetworkPolicyCreateAction(testns, testNetworkPolicy),
+			},
+			expErr: false,
+		},
+		{
+			name:                   "A new networkPolicy should error when create a new networkPolicy fails.",
+			networkPolicy:          testNetworkPolicy,
+			getNetworkPolicyResult: nil,
+			errorOnGet:             kubeerrors.NewNotFound(schema.GroupResource{}, ""),
+			errorOnCreation:        errors.New("wanted error"),
+			expActions: []kubetesting.Action{
+				newNetworkPolicyGetAction(testns, testNetworkPolicy.ObjectMeta.Name),
+				newNetworkPolicyCreateAction(testns, testNetworkPolicy),
+			},
+			expErr: true,
+		},
+		{
+			name:                   "An existent networkPolicy should update the networkPolicy.",
+			networkPolicy:          testNetworkPolicy,
+			getNetworkPolicyResult: testNetworkPolicy,
+			errorOnGet:             nil,
+			errorOnCreation:        nil,
+			expActions: []kubetesting.Action{
+				newNetworkPolicyGetAction(testns, testNetworkPolicy.ObjectMeta.Name),
+				newNetworkPolicyUpdateAction(testns, testNetworkPolicy),
+			},
+			expErr: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			// Mock.
+			mcli := &kubernetes.Clientset{}
+			mcli.AddReactor("get", "networkpolicies", func(action kubetesting.Action) (bool, runtime.Object, error) {
+				return true, test.getNetworkPolicyResult, test.errorOnGet
+			})
+			mcli.AddReactor("create", "networkpolicies", func(action kubetesting.Action) (bool, runtime.Object, error) {
+				return true, nil, test.errorOnCreation
+			})
+
+			service := k8s.NewNetworkPolicyService(mcli, log.Dummy, metrics.Dummy)
+			err := service.CreateOrUpdateNetworkPolicy(context.Background(), testns, test.networkPolicy)
+
+			if test.expErr {
+				assert.Error(err)
+			} else {
+				assert.NoError(err)
+				// Check calls to kubernetes.
+				assert.Equal(test.expActions, mcli.Actions())
+			}
+		})
+	}
+}
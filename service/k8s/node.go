@@ -0,0 +1,45 @@
+package k8s
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/spotahome/redis-operator/log"
+	"github.com/spotahome/redis-operator/metrics"
+)
+
+// Node interacts with k8s to get nodes
+type Node interface {
+	GetNode(ctx context.Context, name string) (*corev1.Node, error)
+}
+
+// NodeService is the node service implementation using API calls to kubernetes.
+type NodeService struct {
+	kubeClient      kubernetes.Interface
+	logger          log.Logger
+	metricsRecorder metrics.Recorder
+}
+
+// NewNodeService returns a new Node KubeService.
+func NewNodeService(kubeClient kubernetes.Interface, logger log.Logger, metricsRecorder metrics.Recorder) *NodeService {
+	logger = logger.With("service", "k8s.node")
+	return &NodeService{
+		kubeClient:      kubeClient,
+		logger:          logger,
+		metricsRecorder: metricsRecorder,
+	}
+}
+
+// GetNode returns the node with the given name, nodes aren't namespaced
+func (n *NodeService) GetNode(ctx context.Context, name string) (*corev1.Node, error) {
+	ctx, done := startOperation(ctx, metav1.NamespaceNone, "Node", name, "GET", n.metricsRecorder)
+	node, err := n.kubeClient.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
+	done(err)
+	if err != nil {
+		return nil, err
+	}
+	return node, nil
+}
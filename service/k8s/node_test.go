@@ -0,0 +1,32 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubernetes "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/spotahome/redis-operator/log"
+	"github.com/spotahome/redis-operator/metrics"
+)
+
+func TestNodeServiceGet(t *testing.T) {
+	assert := assert.New(t)
+
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+		Spec:       corev1.NodeSpec{Unschedulable: true},
+	}
+	service := NewNodeService(kubernetes.NewSimpleClientset(node), log.Dummy, metrics.Dummy)
+
+	got, err := service.GetNode(context.Background(), "node-1")
+	assert.NoError(err)
+	assert.Equal(node, got)
+
+	_, err = service.GetNode(context.Background(), "node-2")
+	assert.True(errors.IsNotFound(err))
+}
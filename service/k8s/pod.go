@@ -17,13 +17,13 @@ import (
 
 // Pod the ServiceAccount service that knows how to interact with k8s to manage them
 type Pod interface {
-	GetPod(namespace string, name string) (*corev1.Pod, error)
-	CreatePod(namespace string, pod *corev1.Pod) error
-	UpdatePod(namespace string, pod *corev1.Pod) error
-	CreateOrUpdatePod(namespace string, pod *corev1.Pod) error
-	DeletePod(namespace string, name string) error
-	ListPods(namespace string) (*corev1.PodList, error)
-	UpdatePodLabels(namespace, podName string, labels map[string]string) error
+	GetPod(ctx context.Context, namespace string, name string) (*corev1.Pod, error)
+	CreatePod(ctx context.Context, namespace string, pod *corev1.Pod) error
+	UpdatePod(ctx context.Context, namespace string, pod *corev1.Pod) error
+	CreateOrUpdatePod(ctx context.Context, namespace string, pod *corev1.Pod) error
+	DeletePod(ctx context.Context, namespace string, name string) error
+	ListPods(ctx context.Context, namespace string) (*corev1.PodList, error)
+	UpdatePodLabels(ctx context.Context, namespace, podName string, labels map[string]string) error
 }
 
 // PodService is the pod service implementation using API calls to kubernetes.
@@ -43,39 +43,42 @@ func NewPodService(kubeClient kubernetes.Interface, logger log.Logger, metricsRe
 	}
 }
 
-func (p *PodService) GetPod(namespace string, name string) (*corev1.Pod, error) {
-	pod, err := p.kubeClient.CoreV1().Pods(namespace).Get(context.TODO(), name, metav1.GetOptions{})
-	recordMetrics(namespace, "Pod", name, "GET", err, p.metricsRecorder)
+func (p *PodService) GetPod(ctx context.Context, namespace string, name string) (*corev1.Pod, error) {
+	ctx, done := startOperation(ctx, namespace, "Pod", name, "GET", p.metricsRecorder)
+	pod, err := p.kubeClient.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+	done(err)
 	if err != nil {
 		return nil, err
 	}
 	return pod, err
 }
 
-func (p *PodService) CreatePod(namespace string, pod *corev1.Pod) error {
-	_, err := p.kubeClient.CoreV1().Pods(namespace).Create(context.TODO(), pod, metav1.CreateOptions{})
-	recordMetrics(namespace, "Pod", pod.GetName(), "CREATE", err, p.metricsRecorder)
+func (p *PodService) CreatePod(ctx context.Context, namespace string, pod *corev1.Pod) error {
+	ctx, done := startOperation(ctx, namespace, "Pod", pod.GetName(), "CREATE", p.metricsRecorder)
+	_, err := p.kubeClient.CoreV1().Pods(namespace).Create(ctx, pod, metav1.CreateOptions{})
+	done(err)
 	if err != nil {
 		return err
 	}
-	p.logger.WithField("namespace", namespace).WithField("pod", pod.Name).Debugf("pod created")
+	log.FromContext(ctx, p.logger).WithField("namespace", namespace).WithField("pod", pod.Name).Debugf("pod created")
 	return nil
 }
-func (p *PodService) UpdatePod(namespace string, pod *corev1.Pod) error {
-	_, err := p.kubeClient.CoreV1().Pods(namespace).Update(context.TODO(), pod, metav1.UpdateOptions{})
-	recordMetrics(namespace, "Pod", pod.GetName(), "UPDATE", err, p.metricsRecorder)
+func (p *PodService) UpdatePod(ctx context.Context, namespace string, pod *corev1.Pod) error {
+	ctx, done := startOperation(ctx, namespace, "Pod", pod.GetName(), "UPDATE", p.metricsRecorder)
+	_, err := p.kubeClient.CoreV1().Pods(namespace).Update(ctx, pod, metav1.UpdateOptions{})
+	done(err)
 	if err != nil {
 		return err
 	}
-	p.logger.WithField("namespace", namespace).WithField("pod", pod.Name).Debugf("pod updated")
+	log.FromContext(ctx, p.logger).WithField("namespace", namespace).WithField("pod", pod.Name).Debugf("pod updated")
 	return nil
 }
-func (p *PodService) CreateOrUpdatePod(namespace string, pod *corev1.Pod) error {
-	storedPod, err := p.GetPod(namespace, pod.Name)
+func (p *PodService) CreateOrUpdatePod(ctx context.Context, namespace string, pod *corev1.Pod) error {
+	storedPod, err := p.GetPod(ctx, namespace, pod.Name)
 	if err != nil {
 		// If no resource we need to create.
 		if errors.IsNotFound(err) {
-			return p.CreatePod(namespace, pod)
+			return p.CreatePod(ctx, namespace, pod)
 		}
 		return err
 	}
@@ -85,18 +88,20 @@ func (p *PodService) CreateOrUpdatePod(namespace string, pod *corev1.Pod) error
 	// namespace is our spec(https://github.com/kubernetes/community/blob/master/contributors/devel/api-conventions.md#concurrency-control-and-consistency),
 	// we will replace the current namespace state.
 	pod.ResourceVersion = storedPod.ResourceVersion
-	return p.UpdatePod(namespace, pod)
+	return p.UpdatePod(ctx, namespace, pod)
 }
 
-func (p *PodService) DeletePod(namespace string, name string) error {
-	err := p.kubeClient.CoreV1().Pods(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{})
-	recordMetrics(namespace, "Pod", name, "DELETE", err, p.metricsRecorder)
+func (p *PodService) DeletePod(ctx context.Context, namespace string, name string) error {
+	ctx, done := startOperation(ctx, namespace, "Pod", name, "DELETE", p.metricsRecorder)
+	err := p.kubeClient.CoreV1().Pods(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	done(err)
 	return err
 }
 
-func (p *PodService) ListPods(namespace string) (*corev1.PodList, error) {
-	pods, err := p.kubeClient.CoreV1().Pods(namespace).List(context.TODO(), metav1.ListOptions{})
-	recordMetrics(namespace, "Pod", metrics.NOT_APPLICABLE, "LIST", err, p.metricsRecorder)
+func (p *PodService) ListPods(ctx context.Context, namespace string) (*corev1.PodList, error) {
+	ctx, done := startOperation(ctx, namespace, "Pod", metrics.NOT_APPLICABLE, "LIST", p.metricsRecorder)
+	pods, err := p.kubeClient.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	done(err)
 	return pods, err
 }
 
@@ -107,8 +112,8 @@ type PatchStringValue struct {
 	Value interface{} `json:"value"`
 }
 
-func (p *PodService) UpdatePodLabels(namespace, podName string, labels map[string]string) error {
-	p.logger.Infof("Update pod label, namespace: %s, pod name: %s, labels: %v", namespace, podName, labels)
+func (p *PodService) UpdatePodLabels(ctx context.Context, namespace, podName string, labels map[string]string) error {
+	log.FromContext(ctx, p.logger).WithField("namespace", namespace).WithField("pod", podName).Infof("Update pod labels: %v", labels)
 
 	var payloads []interface{}
 	for labelKey, labelValue := range labels {
@@ -121,10 +126,11 @@ func (p *PodService) UpdatePodLabels(namespace, podName string, labels map[strin
 	}
 	payloadBytes, _ := json.Marshal(payloads)
 
-	_, err := p.kubeClient.CoreV1().Pods(namespace).Patch(context.TODO(), podName, types.JSONPatchType, payloadBytes, metav1.PatchOptions{})
-	recordMetrics(namespace, "Pod", podName, "PATCH", err, p.metricsRecorder)
+	ctx, done := startOperation(ctx, namespace, "Pod", podName, "PATCH", p.metricsRecorder)
+	_, err := p.kubeClient.CoreV1().Pods(namespace).Patch(ctx, podName, types.JSONPatchType, payloadBytes, metav1.PatchOptions{})
+	done(err)
 	if err != nil {
-		p.logger.Errorf("Update pod labels failed, namespace: %s, pod name: %s, error: %v", namespace, podName, err)
+		log.FromContext(ctx, p.logger).WithField("namespace", namespace).WithField("pod", podName).Errorf("Update pod labels failed: %v", err)
 	}
 	return err
 }
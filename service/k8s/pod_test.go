@@ -1,10 +1,17 @@
 package k8s_test
 
 import (
+	"context"
 	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	corev1 "k8s.io/api/core/v1"
 	kubeerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -105,7 +112,7 @@ func TestPodServiceGetCreateOrUpdate(t *testing.T) {
 			})
 
 			service := k8s.NewPodService(mcli, log.Dummy, metrics.Dummy)
-			err := service.CreateOrUpdatePod(testns, test.pod)
+			err := service.CreateOrUpdatePod(context.Background(), testns, test.pod)
 
 			if test.expErr {
 				assert.Error(err)
@@ -117,3 +124,31 @@ func TestPodServiceGetCreateOrUpdate(t *testing.T) {
 		})
 	}
 }
+
+func TestPodServiceTracing(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	provider := otel.GetTracerProvider()
+	defer otel.SetTracerProvider(provider)
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	mcli := &kubernetes.Clientset{}
+	mcli.AddReactor("get", "pods", func(action kubetesting.Action) (bool, runtime.Object, error) {
+		return true, nil, kubeerrors.NewNotFound(schema.GroupResource{}, "")
+	})
+	service := k8s.NewPodService(mcli, log.Dummy, metrics.Dummy)
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "Handle")
+	_, err := service.GetPod(ctx, "testns", "testpod1")
+	require.Error(err)
+	parent.End()
+
+	spans := recorder.Ended()
+	require.Len(spans, 2)
+	assert.Equal("k8s GET Pod", spans[0].Name())
+	assert.Equal(codes.Error, spans[0].Status().Code)
+	assert.Contains(spans[0].Attributes(), attribute.String("k8s.namespace", "testns"))
+	assert.Contains(spans[0].Attributes(), attribute.String("k8s.name", "testpod1"))
+	// The span of the call is a child of the one of the reconcile making it
+	assert.Equal(spans[1].SpanContext().SpanID(), spans[0].Parent().SpanID())
+}
@@ -14,11 +14,11 @@ import (
 
 // PodDisruptionBudget the ServiceAccount service that knows how to interact with k8s to manage them
 type PodDisruptionBudget interface {
-	GetPodDisruptionBudget(namespace string, name string) (*policyv1.PodDisruptionBudget, error)
-	CreatePodDisruptionBudget(namespace string, podDisruptionBudget *policyv1.PodDisruptionBudget) error
-	UpdatePodDisruptionBudget(namespace string, podDisruptionBudget *policyv1.PodDisruptionBudget) error
-	CreateOrUpdatePodDisruptionBudget(namespace string, podDisruptionBudget *policyv1.PodDisruptionBudget) error
-	DeletePodDisruptionBudget(namespace string, name string) error
+	GetPodDisruptionBudget(ctx context.Context, namespace string, name string) (*policyv1.PodDisruptionBudget, error)
+	CreatePodDisruptionBudget(ctx context.Context, namespace string, podDisruptionBudget *policyv1.PodDisruptionBudget) error
+	UpdatePodDisruptionBudget(ctx context.Context, namespace string, podDisruptionBudget *policyv1.PodDisruptionBudget) error
+	CreateOrUpdatePodDisruptionBudget(ctx context.Context, namespace string, podDisruptionBudget *policyv1.PodDisruptionBudget) error
+	DeletePodDisruptionBudget(ctx context.Context, namespace string, name string) error
 }
 
 // PodDisruptionBudgetService is the podDisruptionBudget service implementation using API calls to kubernetes.
@@ -38,41 +38,44 @@ func NewPodDisruptionBudgetService(kubeClient kubernetes.Interface, logger log.L
 	}
 }
 
-func (p *PodDisruptionBudgetService) GetPodDisruptionBudget(namespace string, name string) (*policyv1.PodDisruptionBudget, error) {
-	podDisruptionBudget, err := p.kubeClient.PolicyV1().PodDisruptionBudgets(namespace).Get(context.TODO(), name, metav1.GetOptions{})
-	recordMetrics(namespace, "PodDisruptionBudget", name, "GET", err, p.metricsRecorder)
+func (p *PodDisruptionBudgetService) GetPodDisruptionBudget(ctx context.Context, namespace string, name string) (*policyv1.PodDisruptionBudget, error) {
+	ctx, done := startOperation(ctx, namespace, "PodDisruptionBudget", name, "GET", p.metricsRecorder)
+	podDisruptionBudget, err := p.kubeClient.PolicyV1().PodDisruptionBudgets(namespace).Get(ctx, name, metav1.GetOptions{})
+	done(err)
 	if err != nil {
 		return nil, err
 	}
 	return podDisruptionBudget, nil
 }
 
-func (p *PodDisruptionBudgetService) CreatePodDisruptionBudget(namespace string, podDisruptionBudget *policyv1.PodDisruptionBudget) error {
-	_, err := p.kubeClient.PolicyV1().PodDisruptionBudgets(namespace).Create(context.TODO(), podDisruptionBudget, metav1.CreateOptions{})
-	recordMetrics(namespace, "PodDisruptionBudget", podDisruptionBudget.GetName(), "CREATE", err, p.metricsRecorder)
+func (p *PodDisruptionBudgetService) CreatePodDisruptionBudget(ctx context.Context, namespace string, podDisruptionBudget *policyv1.PodDisruptionBudget) error {
+	ctx, done := startOperation(ctx, namespace, "PodDisruptionBudget", podDisruptionBudget.GetName(), "CREATE", p.metricsRecorder)
+	_, err := p.kubeClient.PolicyV1().PodDisruptionBudgets(namespace).Create(ctx, podDisruptionBudget, metav1.CreateOptions{})
+	done(err)
 	if err != nil {
 		return err
 	}
-	p.logger.WithField("namespace", namespace).WithField("podDisruptionBudget", podDisruptionBudget.Name).Debugf("podDisruptionBudget created")
+	log.FromContext(ctx, p.logger).WithField("namespace", namespace).WithField("podDisruptionBudget", podDisruptionBudget.Name).Debugf("podDisruptionBudget created")
 	return nil
 }
 
-func (p *PodDisruptionBudgetService) UpdatePodDisruptionBudget(namespace string, podDisruptionBudget *policyv1.PodDisruptionBudget) error {
-	_, err := p.kubeClient.PolicyV1().PodDisruptionBudgets(namespace).Update(context.TODO(), podDisruptionBudget, metav1.UpdateOptions{})
-	recordMetrics(namespace, "PodDisruptionBudget", podDisruptionBudget.GetName(), "UPDATE", err, p.metricsRecorder)
+func (p *PodDisruptionBudgetService) UpdatePodDisruptionBudget(ctx context.Context, namespace string, podDisruptionBudget *policyv1.PodDisruptionBudget) error {
+	ctx, done := startOperation(ctx, namespace, "PodDisruptionBudget", podDisruptionBudget.GetName(), "UPDATE", p.metricsRecorder)
+	_, err := p.kubeClient.PolicyV1().PodDisruptionBudgets(namespace).Update(ctx, podDisruptionBudget, metav1.UpdateOptions{})
+	done(err)
 	if err != nil {
 		return err
 	}
-	p.logger.WithField("namespace", namespace).WithField("podDisruptionBudget", podDisruptionBudget.Name).Debugf("podDisruptionBudget updated")
+	log.FromContext(ctx, p.logger).WithField("namespace", namespace).WithField("podDisruptionBudget", podDisruptionBudget.Name).Debugf("podDisruptionBudget updated")
 	return nil
 }
 
-func (p *PodDisruptionBudgetService) CreateOrUpdatePodDisruptionBudget(namespace string, podDisruptionBudget *policyv1.PodDisruptionBudget) error {
-	storedPodDisruptionBudget, err := p.GetPodDisruptionBudget(namespace, podDisruptionBudget.Name)
+func (p *PodDisruptionBudgetService) CreateOrUpdatePodDisruptionBudget(ctx context.Context, namespace string, podDisruptionBudget *policyv1.PodDisruptionBudget) error {
+	storedPodDisruptionBudget, err := p.GetPodDisruptionBudget(ctx, namespace, podDisruptionBudget.Name)
 	if err != nil {
 		// If no resource we need to create.
 		if errors.IsNotFound(err) {
-			return p.CreatePodDisruptionBudget(namespace, podDisruptionBudget)
+			return p.CreatePodDisruptionBudget(ctx, namespace, podDisruptionBudget)
 		}
 		return err
 	}
@@ -82,11 +85,12 @@ func (p *PodDisruptionBudgetService) CreateOrUpdatePodDisruptionBudget(namespace
 	// namespace is our spec(https://github.com/kubernetes/community/blob/master/contributors/devel/api-conventions.md#concurrency-control-and-consistency),
 	// we will replace the current namespace state.
 	podDisruptionBudget.ResourceVersion = storedPodDisruptionBudget.ResourceVersion
-	return p.UpdatePodDisruptionBudget(namespace, podDisruptionBudget)
+	return p.UpdatePodDisruptionBudget(ctx, namespace, podDisruptionBudget)
 }
 
-func (p *PodDisruptionBudgetService) DeletePodDisruptionBudget(namespace string, name string) error {
-	err := p.kubeClient.PolicyV1().PodDisruptionBudgets(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{})
-	recordMetrics(namespace, "PodDisruptionBudget", name, "DELETE", err, p.metricsRecorder)
+func (p *PodDisruptionBudgetService) DeletePodDisruptionBudget(ctx context.Context, namespace string, name string) error {
+	ctx, done := startOperation(ctx, namespace, "PodDisruptionBudget", name, "DELETE", p.metricsRecorder)
+	err := p.kubeClient.PolicyV1().PodDisruptionBudgets(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	done(err)
 	return err
 }
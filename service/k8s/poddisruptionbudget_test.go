@@ -1,6 +1,7 @@
 package k8s_test
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -103,7 +104,7 @@ func TestPodDisruptionBudgetServiceGetCreateOrUpdate(t *testing.T) {
 			})
 
 			service := k8s.NewPodDisruptionBudgetService(mcli, log.Dummy, metrics.Dummy)
-			err := service.CreateOrUpdatePodDisruptionBudget(testns, test.podDisruptionBudget)
+			err := service.CreateOrUpdatePodDisruptionBudget(context.Background(), testns, test.podDisruptionBudget)
 
 			if test.expErr {
 				assert.Error(err)
@@ -0,0 +1,143 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+)
+
+// PortForwardDialer dials the pods of a namespace, given by their IP or the hostname their headless service gives them,
+// through port forwarding, so they are reachable from outside of the cluster. Other addresses are dialed directly
+type PortForwardDialer struct {
+	config    *rest.Config
+	client    kubernetes.Interface
+	namespace string
+
+	mutex    sync.Mutex
+	forwards map[string]portForward
+}
+
+type portForward struct {
+	localPort uint16
+	stopC     chan struct{}
+}
+
+// NewPortForwardDialer returns a dialer reaching the pods of the given namespace through port forwarding
+func NewPortForwardDialer(config *rest.Config, client kubernetes.Interface, namespace string) *PortForwardDialer {
+	return &PortForwardDialer{
+		config:    config,
+		client:    client,
+		namespace: namespace,
+		forwards:  map[string]portForward{},
+	}
+}
+
+// DialContext connects to the given address, forwarding a local port to the pod it belongs to
+func (d *PortForwardDialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	pod, err := d.getPod(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	var dialer net.Dialer
+	if pod == nil {
+		return dialer.DialContext(ctx, network, addr)
+	}
+	localPort, err := d.forward(ctx, pod, port)
+	if err != nil {
+		return nil, err
+	}
+	return dialer.DialContext(ctx, network, net.JoinHostPort("127.0.0.1", fmt.Sprint(localPort)))
+}
+
+// Close stops every port forwarding
+func (d *PortForwardDialer) Close() {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	for key, forward := range d.forwards {
+		close(forward.stopC)
+		delete(d.forwards, key)
+	}
+}
+
+// getPod returns the pod of the namespace the given host belongs to, nil when it isn't one of them
+func (d *PortForwardDialer) getPod(ctx context.Context, host string) (*corev1.Pod, error) {
+	if net.ParseIP(host) != nil {
+		pods, err := d.client.CoreV1().Pods(d.namespace).List(ctx, metav1.ListOptions{FieldSelector: "status.podIP=" + host})
+		if err != nil {
+			return nil, err
+		}
+		for _, pod := range pods.Items {
+			if pod.Status.PodIP == host {
+				return &pod, nil
+			}
+		}
+		return nil, nil
+	}
+
+	// <pod>.<service>.<namespace>.svc.<cluster domain>
+	labels := strings.Split(host, ".")
+	if len(labels) < 3 || labels[2] != d.namespace {
+		return nil, nil
+	}
+	return d.client.CoreV1().Pods(d.namespace).Get(ctx, labels[0], metav1.GetOptions{})
+}
+
+// forward returns the local port forwarded to the given port of the pod, starting the forwarding when missing
+func (d *PortForwardDialer) forward(ctx context.Context, pod *corev1.Pod, port string) (uint16, error) {
+	key := fmt.Sprintf("%s:%s", pod.Name, port)
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if forward, ok := d.forwards[key]; ok {
+		return forward.localPort, nil
+	}
+
+	transport, upgrader, err := spdy.RoundTripperFor(d.config)
+	if err != nil {
+		return 0, err
+	}
+	url := d.client.CoreV1().RESTClient().Post().Resource("pods").Namespace(pod.Namespace).Name(pod.Name).SubResource("portforward").URL()
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, http.MethodPost, url)
+
+	stopC := make(chan struct{})
+	readyC := make(chan struct{})
+	forwarder, err := portforward.NewOnAddresses(dialer, []string{"127.0.0.1"}, []string{"0:" + port}, stopC, readyC, io.Discard, io.Discard)
+	if err != nil {
+		return 0, err
+	}
+	errC := make(chan error, 1)
+	go func() {
+		errC <- forwarder.ForwardPorts()
+	}()
+
+	select {
+	case <-readyC:
+	case err := <-errC:
+		return 0, fmt.Errorf("port forwarding to %s failed: %w", key, err)
+	case <-ctx.Done():
+		close(stopC)
+		return 0, ctx.Err()
+	}
+	ports, err := forwarder.GetPorts()
+	if err != nil || len(ports) == 0 {
+		close(stopC)
+		return 0, fmt.Errorf("port forwarding to %s failed: %v", key, err)
+	}
+	d.forwards[key] = portForward{localPort: ports[0].Local, stopC: stopC}
+	return ports[0].Local, nil
+}
@@ -0,0 +1,46 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubernetes "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestPortForwardDialerGetPod(t *testing.T) {
+	pods := []corev1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Name: "rfr-test-0", Namespace: "testns"}, Status: corev1.PodStatus{PodIP: "10.0.0.1"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "rfr-test-1", Namespace: "testns"}, Status: corev1.PodStatus{PodIP: "10.0.0.2"}},
+	}
+	mcli := kubernetes.NewSimpleClientset(&pods[0], &pods[1])
+	dialer := NewPortForwardDialer(nil, mcli, "testns")
+
+	tests := []struct {
+		name   string
+		host   string
+		expPod string
+	}{
+		{name: "pod IP", host: "10.0.0.2", expPod: "rfr-test-1"},
+		{name: "unknown IP", host: "10.0.0.3"},
+		{name: "headless service hostname", host: "rfr-test-0.rfr-test.testns.svc.cluster.local", expPod: "rfr-test-0"},
+		{name: "hostname of another namespace", host: "rfr-test-0.rfr-test.otherns.svc"},
+		{name: "external hostname", host: "redis.example.com"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			pod, err := dialer.getPod(context.TODO(), test.host)
+			assert.NoError(err)
+			if test.expPod == "" {
+				assert.Nil(pod)
+			} else if assert.NotNil(pod) {
+				assert.Equal(test.expPod, pod.Name)
+			}
+		})
+	}
+}
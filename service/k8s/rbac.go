@@ -14,15 +14,15 @@ import (
 
 // RBAC is the service that knows how to interact with k8s to manage RBAC related resources.
 type RBAC interface {
-	GetClusterRole(name string) (*rbacv1.ClusterRole, error)
-	GetRole(namespace, name string) (*rbacv1.Role, error)
-	GetRoleBinding(namespace, name string) (*rbacv1.RoleBinding, error)
-	CreateRole(namespace string, role *rbacv1.Role) error
-	UpdateRole(namespace string, role *rbacv1.Role) error
-	CreateOrUpdateRole(namespace string, binding *rbacv1.Role) error
-	CreateRoleBinding(namespace string, binding *rbacv1.RoleBinding) error
-	UpdateRoleBinding(namespace string, binding *rbacv1.RoleBinding) error
-	CreateOrUpdateRoleBinding(namespace string, binding *rbacv1.RoleBinding) error
+	GetClusterRole(ctx context.Context, name string) (*rbacv1.ClusterRole, error)
+	GetRole(ctx context.Context, namespace, name string) (*rbacv1.Role, error)
+	GetRoleBinding(ctx context.Context, namespace, name string) (*rbacv1.RoleBinding, error)
+	CreateRole(ctx context.Context, namespace string, role *rbacv1.Role) error
+	UpdateRole(ctx context.Context, namespace string, role *rbacv1.Role) error
+	CreateOrUpdateRole(ctx context.Context, namespace string, binding *rbacv1.Role) error
+	CreateRoleBinding(ctx context.Context, namespace string, binding *rbacv1.RoleBinding) error
+	UpdateRoleBinding(ctx context.Context, namespace string, binding *rbacv1.RoleBinding) error
+	CreateOrUpdateRoleBinding(ctx context.Context, namespace string, binding *rbacv1.RoleBinding) error
 }
 
 // NamespaceService is the Namespace service implementation using API calls to kubernetes.
@@ -42,60 +42,66 @@ func NewRBACService(kubeClient kubernetes.Interface, logger log.Logger, metricsR
 	}
 }
 
-func (r *RBACService) GetClusterRole(name string) (*rbacv1.ClusterRole, error) {
-	clusterRole, err := r.kubeClient.RbacV1().ClusterRoles().Get(context.TODO(), name, metav1.GetOptions{})
-	recordMetrics(metrics.NOT_APPLICABLE, "ClusterRole", name, "GET", err, r.metricsRecorder)
+func (r *RBACService) GetClusterRole(ctx context.Context, name string) (*rbacv1.ClusterRole, error) {
+	ctx, done := startOperation(ctx, metrics.NOT_APPLICABLE, "ClusterRole", name, "GET", r.metricsRecorder)
+	clusterRole, err := r.kubeClient.RbacV1().ClusterRoles().Get(ctx, name, metav1.GetOptions{})
+	done(err)
 	return clusterRole, err
 }
 
-func (r *RBACService) GetRole(namespace, name string) (*rbacv1.Role, error) {
-	role, err := r.kubeClient.RbacV1().Roles(namespace).Get(context.TODO(), name, metav1.GetOptions{})
-	recordMetrics(namespace, "Role", name, "GET", err, r.metricsRecorder)
+func (r *RBACService) GetRole(ctx context.Context, namespace, name string) (*rbacv1.Role, error) {
+	ctx, done := startOperation(ctx, namespace, "Role", name, "GET", r.metricsRecorder)
+	role, err := r.kubeClient.RbacV1().Roles(namespace).Get(ctx, name, metav1.GetOptions{})
+	done(err)
 	return role, err
 }
 
-func (r *RBACService) GetRoleBinding(namespace, name string) (*rbacv1.RoleBinding, error) {
-	rolbinding, err := r.kubeClient.RbacV1().RoleBindings(namespace).Get(context.TODO(), name, metav1.GetOptions{})
-	recordMetrics(namespace, "RoleBinding", name, "GET", err, r.metricsRecorder)
+func (r *RBACService) GetRoleBinding(ctx context.Context, namespace, name string) (*rbacv1.RoleBinding, error) {
+	ctx, done := startOperation(ctx, namespace, "RoleBinding", name, "GET", r.metricsRecorder)
+	rolbinding, err := r.kubeClient.RbacV1().RoleBindings(namespace).Get(ctx, name, metav1.GetOptions{})
+	done(err)
 	return rolbinding, err
 }
 
-func (r *RBACService) DeleteRole(namespace, name string) error {
-	err := r.kubeClient.RbacV1().Roles(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{})
-	recordMetrics(namespace, "Role", name, "DELETE", err, r.metricsRecorder)
+func (r *RBACService) DeleteRole(ctx context.Context, namespace, name string) error {
+	ctx, done := startOperation(ctx, namespace, "Role", name, "DELETE", r.metricsRecorder)
+	err := r.kubeClient.RbacV1().Roles(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	done(err)
 	if err != nil {
 		return err
 	}
-	r.logger.WithField("namespace", namespace).WithField("role", name).Debugf("role deleted")
+	log.FromContext(ctx, r.logger).WithField("namespace", namespace).WithField("role", name).Debugf("role deleted")
 	return nil
 }
 
-func (r *RBACService) CreateRole(namespace string, role *rbacv1.Role) error {
-	_, err := r.kubeClient.RbacV1().Roles(namespace).Create(context.TODO(), role, metav1.CreateOptions{})
-	recordMetrics(namespace, "Role", role.GetName(), "CREATE", err, r.metricsRecorder)
+func (r *RBACService) CreateRole(ctx context.Context, namespace string, role *rbacv1.Role) error {
+	ctx, done := startOperation(ctx, namespace, "Role", role.GetName(), "CREATE", r.metricsRecorder)
+	_, err := r.kubeClient.RbacV1().Roles(namespace).Create(ctx, role, metav1.CreateOptions{})
+	done(err)
 	if err != nil {
 		return err
 	}
-	r.logger.WithField("namespace", namespace).WithField("role", role.Name).Debugf("role created")
+	log.FromContext(ctx, r.logger).WithField("namespace", namespace).WithField("role", role.Name).Debugf("role created")
 	return nil
 }
 
-func (s *RBACService) UpdateRole(namespace string, role *rbacv1.Role) error {
-	_, err := s.kubeClient.RbacV1().Roles(namespace).Update(context.TODO(), role, metav1.UpdateOptions{})
-	recordMetrics(namespace, "Role", role.GetName(), "UPDATE", err, s.metricsRecorder)
+func (s *RBACService) UpdateRole(ctx context.Context, namespace string, role *rbacv1.Role) error {
+	ctx, done := startOperation(ctx, namespace, "Role", role.GetName(), "UPDATE", s.metricsRecorder)
+	_, err := s.kubeClient.RbacV1().Roles(namespace).Update(ctx, role, metav1.UpdateOptions{})
+	done(err)
 	if err != nil {
 		return err
 	}
-	s.logger.WithField("namespace", namespace).WithField("role", role.ObjectMeta.Name).Debugf("role updated")
+	log.FromContext(ctx, s.logger).WithField("namespace", namespace).WithField("role", role.ObjectMeta.Name).Debugf("role updated")
 	return err
 }
 
-func (r *RBACService) CreateOrUpdateRole(namespace string, role *rbacv1.Role) error {
-	storedRole, err := r.GetRole(namespace, role.Name)
+func (r *RBACService) CreateOrUpdateRole(ctx context.Context, namespace string, role *rbacv1.Role) error {
+	storedRole, err := r.GetRole(ctx, namespace, role.Name)
 	if err != nil {
 		// If no resource we need to create.
 		if errors.IsNotFound(err) {
-			return r.CreateRole(namespace, role)
+			return r.CreateRole(ctx, namespace, role)
 		}
 		return err
 	}
@@ -105,45 +111,48 @@ func (r *RBACService) CreateOrUpdateRole(namespace string, role *rbacv1.Role) er
 	// namespace is our spec(https://github.com/kubernetes/community/blob/master/contributors/devel/api-conventions.md#concurrency-control-and-consistency),
 	// we will replace the current namespace state.
 	role.ResourceVersion = storedRole.ResourceVersion
-	return r.UpdateRole(namespace, role)
+	return r.UpdateRole(ctx, namespace, role)
 }
 
-func (r *RBACService) DeleteRoleBinding(namespace, name string) error {
-	err := r.kubeClient.RbacV1().RoleBindings(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{})
-	recordMetrics(namespace, "RoleBinding", name, "DELETE", err, r.metricsRecorder)
+func (r *RBACService) DeleteRoleBinding(ctx context.Context, namespace, name string) error {
+	ctx, done := startOperation(ctx, namespace, "RoleBinding", name, "DELETE", r.metricsRecorder)
+	err := r.kubeClient.RbacV1().RoleBindings(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	done(err)
 	if err != nil {
 		return err
 	}
-	r.logger.WithField("namespace", namespace).WithField("binding", name).Debugf("role binding deleted")
+	log.FromContext(ctx, r.logger).WithField("namespace", namespace).WithField("binding", name).Debugf("role binding deleted")
 	return nil
 }
 
-func (r *RBACService) CreateRoleBinding(namespace string, binding *rbacv1.RoleBinding) error {
-	_, err := r.kubeClient.RbacV1().RoleBindings(namespace).Create(context.TODO(), binding, metav1.CreateOptions{})
-	recordMetrics(namespace, "RoleBinding", binding.GetName(), "CREATE", err, r.metricsRecorder)
+func (r *RBACService) CreateRoleBinding(ctx context.Context, namespace string, binding *rbacv1.RoleBinding) error {
+	ctx, done := startOperation(ctx, namespace, "RoleBinding", binding.GetName(), "CREATE", r.metricsRecorder)
+	_, err := r.kubeClient.RbacV1().RoleBindings(namespace).Create(ctx, binding, metav1.CreateOptions{})
+	done(err)
 	if err != nil {
 		return err
 	}
-	r.logger.WithField("namespace", namespace).WithField("binding", binding.Name).Debugf("role binding created")
+	log.FromContext(ctx, r.logger).WithField("namespace", namespace).WithField("binding", binding.Name).Debugf("role binding created")
 	return nil
 }
 
-func (r *RBACService) UpdateRoleBinding(namespace string, binding *rbacv1.RoleBinding) error {
-	_, err := r.kubeClient.RbacV1().RoleBindings(namespace).Update(context.TODO(), binding, metav1.UpdateOptions{})
-	recordMetrics(namespace, "Role", binding.GetName(), "UPDATE", err, r.metricsRecorder)
+func (r *RBACService) UpdateRoleBinding(ctx context.Context, namespace string, binding *rbacv1.RoleBinding) error {
+	ctx, done := startOperation(ctx, namespace, "Role", binding.GetName(), "UPDATE", r.metricsRecorder)
+	_, err := r.kubeClient.RbacV1().RoleBindings(namespace).Update(ctx, binding, metav1.UpdateOptions{})
+	done(err)
 	if err != nil {
 		return err
 	}
-	r.logger.WithField("namespace", namespace).WithField("binding", binding.Name).Debugf("role binding updated")
+	log.FromContext(ctx, r.logger).WithField("namespace", namespace).WithField("binding", binding.Name).Debugf("role binding updated")
 	return nil
 }
 
-func (r *RBACService) CreateOrUpdateRoleBinding(namespace string, binding *rbacv1.RoleBinding) error {
-	storedBinding, err := r.GetRoleBinding(namespace, binding.Name)
+func (r *RBACService) CreateOrUpdateRoleBinding(ctx context.Context, namespace string, binding *rbacv1.RoleBinding) error {
+	storedBinding, err := r.GetRoleBinding(ctx, namespace, binding.Name)
 	if err != nil {
 		// If no resource we need to create.
 		if errors.IsNotFound(err) {
-			return r.CreateRoleBinding(namespace, binding)
+			return r.CreateRoleBinding(ctx, namespace, binding)
 		}
 		return err
 	}
@@ -151,11 +160,11 @@ func (r *RBACService) CreateOrUpdateRoleBinding(namespace string, binding *rbacv
 	// Check if the role ref has changed, roleref updates are not allowed, if changed then delete and create again the role binding.
 	// https://github.com/kubernetes/kubernetes/blob/0f0a5223dfc75337d03c9b80ae552ae8ef138eeb/pkg/apis/rbac/validation/validation.go#L157-L159
 	if storedBinding.RoleRef != binding.RoleRef {
-		r.logger.WithField("namespace", namespace).WithField("binding", binding.Name).Infof("roleref changed, need to recreate role binding resource")
-		if err := r.DeleteRoleBinding(namespace, binding.Name); err != nil {
+		log.FromContext(ctx, r.logger).WithField("namespace", namespace).WithField("binding", binding.Name).Infof("roleref changed, need to recreate role binding resource")
+		if err := r.DeleteRoleBinding(ctx, namespace, binding.Name); err != nil {
 			return err
 		}
-		return r.CreateRoleBinding(namespace, binding)
+		return r.CreateRoleBinding(ctx, namespace, binding)
 	}
 
 	// Already exists, need to Update.
@@ -163,5 +172,5 @@ func (r *RBACService) CreateOrUpdateRoleBinding(namespace string, binding *rbacv
 	// namespace is our spec(https://github.com/kubernetes/community/blob/master/contributors/devel/api-conventions.md#concurrency-control-and-consistency),
 	// we will replace the current namespace state.
 	binding.ResourceVersion = storedBinding.ResourceVersion
-	return r.UpdateRoleBinding(namespace, binding)
+	return r.UpdateRoleBinding(ctx, namespace, binding)
 }
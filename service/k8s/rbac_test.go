@@ -1,6 +1,7 @@
 package k8s_test
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -132,7 +133,7 @@ func TestRBACServiceGetCreateOrUpdateRoleBinding(t *testing.T) {
 			})
 
 			service := k8s.NewRBACService(mcli, log.Dummy, metrics.Dummy)
-			err := service.CreateOrUpdateRoleBinding(testns, test.rb)
+			err := service.CreateOrUpdateRoleBinding(context.Background(), testns, test.rb)
 
 			if test.expErr {
 				assert.Error(err)
@@ -2,8 +2,10 @@ package k8s
 
 import (
 	"context"
+	"encoding/json"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/watch"
 
 	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
@@ -14,10 +16,14 @@ import (
 
 // RedisFailover the RF service that knows how to interact with k8s to get them
 type RedisFailover interface {
+	// GetRedisFailover gets a redisfailover.
+	GetRedisFailover(ctx context.Context, namespace, name string) (*redisfailoverv1.RedisFailover, error)
 	// ListRedisFailovers lists the redisfailovers on a cluster.
 	ListRedisFailovers(ctx context.Context, namespace string, opts metav1.ListOptions) (*redisfailoverv1.RedisFailoverList, error)
 	// WatchRedisFailovers watches the redisfailovers on a cluster.
 	WatchRedisFailovers(ctx context.Context, namespace string, opts metav1.ListOptions) (watch.Interface, error)
+	// UpdateRedisFailoverStatus updates the status of a redisfailover.
+	UpdateRedisFailoverStatus(ctx context.Context, rFailover *redisfailoverv1.RedisFailover) error
 }
 
 // RedisFailoverService is the RedisFailover service implementation using API calls to kubernetes.
@@ -37,16 +43,42 @@ func NewRedisFailoverService(k8scli redisfailoverclientset.Interface, logger log
 	}
 }
 
+// GetRedisFailover satisfies redisfailover.Service interface.
+func (r *RedisFailoverService) GetRedisFailover(ctx context.Context, namespace, name string) (*redisfailoverv1.RedisFailover, error) {
+	ctx, done := startOperation(ctx, namespace, "RedisFailover", name, "GET", r.metricsRecorder)
+	redisFailover, err := r.k8sCli.DatabasesV1().RedisFailovers(namespace).Get(ctx, name, metav1.GetOptions{})
+	done(err)
+	if err != nil {
+		return nil, err
+	}
+	return redisFailover, nil
+}
+
 // ListRedisFailovers satisfies redisfailover.Service interface.
 func (r *RedisFailoverService) ListRedisFailovers(ctx context.Context, namespace string, opts metav1.ListOptions) (*redisfailoverv1.RedisFailoverList, error) {
+	ctx, done := startOperation(ctx, namespace, "RedisFailover", metrics.NOT_APPLICABLE, "LIST", r.metricsRecorder)
 	redisFailoverList, err := r.k8sCli.DatabasesV1().RedisFailovers(namespace).List(ctx, opts)
-	recordMetrics(namespace, "RedisFailover", metrics.NOT_APPLICABLE, "LIST", err, r.metricsRecorder)
+	done(err)
 	return redisFailoverList, err
 }
 
 // WatchRedisFailovers satisfies redisfailover.Service interface.
 func (r *RedisFailoverService) WatchRedisFailovers(ctx context.Context, namespace string, opts metav1.ListOptions) (watch.Interface, error) {
+	ctx, done := startOperation(ctx, namespace, "RedisFailover", metrics.NOT_APPLICABLE, "WATCH", r.metricsRecorder)
 	watcher, err := r.k8sCli.DatabasesV1().RedisFailovers(namespace).Watch(ctx, opts)
-	recordMetrics(namespace, "RedisFailover", metrics.NOT_APPLICABLE, "WATCH", err, r.metricsRecorder)
+	done(err)
 	return watcher, err
 }
+
+// UpdateRedisFailoverStatus satisfies redisfailover.Service interface. The status is replaced as a whole, a merge
+// patch would leave the fields cleared on the given one, omitted when empty, untouched
+func (r *RedisFailoverService) UpdateRedisFailoverStatus(ctx context.Context, rf *redisfailoverv1.RedisFailover) error {
+	payload, err := json.Marshal([]map[string]interface{}{{"op": "add", "path": "/status", "value": rf.Status}})
+	if err != nil {
+		return err
+	}
+	ctx, done := startOperation(ctx, rf.Namespace, "RedisFailover", rf.Name, "PATCH", r.metricsRecorder)
+	_, err = r.k8sCli.DatabasesV1().RedisFailovers(rf.Namespace).Patch(ctx, rf.Name, types.JSONPatchType, payload, metav1.PatchOptions{}, "status")
+	done(err)
+	return err
+}
@@ -0,0 +1,31 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
+	"github.com/spotahome/redis-operator/client/k8s/clientset/versioned/fake"
+	"github.com/spotahome/redis-operator/log"
+	"github.com/spotahome/redis-operator/metrics"
+)
+
+func TestUpdateRedisFailoverStatusClearsFields(t *testing.T) {
+	assert := assert.New(t)
+
+	rf := &redisfailoverv1.RedisFailover{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "testns"},
+		Status:     redisfailoverv1.RedisFailoverStatus{ExternalMaster: "10.0.0.1:6379"},
+	}
+	service := NewRedisFailoverService(fake.NewSimpleClientset(rf.DeepCopy()), log.Dummy, metrics.Dummy)
+
+	rf.Status.ExternalMaster = ""
+	assert.NoError(service.UpdateRedisFailoverStatus(context.Background(), rf))
+
+	got, err := service.GetRedisFailover(context.Background(), "testns", "test")
+	assert.NoError(err)
+	assert.Empty(got.Status.ExternalMaster)
+}
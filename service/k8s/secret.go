@@ -12,7 +12,7 @@ import (
 
 // Secret interacts with k8s to get secrets
 type Secret interface {
-	GetSecret(namespace, name string) (*corev1.Secret, error)
+	GetSecret(ctx context.Context, namespace, name string) (*corev1.Secret, error)
 }
 
 // SecretService is the secret service implementation using API calls to kubernetes.
@@ -32,10 +32,11 @@ func NewSecretService(kubeClient kubernetes.Interface, logger log.Logger, metric
 	}
 }
 
-func (s *SecretService) GetSecret(namespace, name string) (*corev1.Secret, error) {
+func (s *SecretService) GetSecret(ctx context.Context, namespace, name string) (*corev1.Secret, error) {
 
-	secret, err := s.kubeClient.CoreV1().Secrets(namespace).Get(context.TODO(), name, metav1.GetOptions{})
-	recordMetrics(namespace, "Secret", name, "GET", err, s.metricsRecorder)
+	ctx, done := startOperation(ctx, namespace, "Secret", name, "GET", s.metricsRecorder)
+	secret, err := s.kubeClient.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+	done(err)
 	if err != nil {
 		return nil, err
 	}
@@ -48,12 +48,12 @@ func TestSecretServiceGet(t *testing.T) {
 
 		// test getting the secret
 		service := NewSecretService(mcli, log.Dummy, metrics.Dummy)
-		ss, err := service.GetSecret(secret.ObjectMeta.Namespace, secret.ObjectMeta.Name)
+		ss, err := service.GetSecret(context.Background(), secret.ObjectMeta.Namespace, secret.ObjectMeta.Name)
 		assert.NotNil(ss)
 		assert.NoError(err)
 
 		// test getting a nonexistent secret
-		_, err = service.GetSecret(secret.ObjectMeta.Namespace, secret.ObjectMeta.Name+"nonexistent")
+		_, err = service.GetSecret(context.Background(), secret.ObjectMeta.Namespace, secret.ObjectMeta.Name+"nonexistent")
 		assert.Error(err)
 		assert.True(errors.IsNotFound(err))
 	})
@@ -14,13 +14,13 @@ import (
 
 // Service the ServiceAccount service that knows how to interact with k8s to manage them
 type Service interface {
-	GetService(namespace string, name string) (*corev1.Service, error)
-	CreateService(namespace string, service *corev1.Service) error
-	CreateIfNotExistsService(namespace string, service *corev1.Service) error
-	UpdateService(namespace string, service *corev1.Service) error
-	CreateOrUpdateService(namespace string, service *corev1.Service) error
-	DeleteService(namespace string, name string) error
-	ListServices(namespace string) (*corev1.ServiceList, error)
+	GetService(ctx context.Context, namespace string, name string) (*corev1.Service, error)
+	CreateService(ctx context.Context, namespace string, service *corev1.Service) error
+	CreateIfNotExistsService(ctx context.Context, namespace string, service *corev1.Service) error
+	UpdateService(ctx context.Context, namespace string, service *corev1.Service) error
+	CreateOrUpdateService(ctx context.Context, namespace string, service *corev1.Service) error
+	DeleteService(ctx context.Context, namespace string, name string) error
+	ListServices(ctx context.Context, namespace string) (*corev1.ServiceList, error)
 }
 
 // ServiceService is the service service implementation using API calls to kubernetes.
@@ -40,53 +40,56 @@ func NewServiceService(kubeClient kubernetes.Interface, logger log.Logger, metri
 	}
 }
 
-func (s *ServiceService) GetService(namespace string, name string) (*corev1.Service, error) {
-	service, err := s.kubeClient.CoreV1().Services(namespace).Get(context.TODO(), name, metav1.GetOptions{})
-	recordMetrics(namespace, "Service", name, "GET", err, s.metricsRecorder)
+func (s *ServiceService) GetService(ctx context.Context, namespace string, name string) (*corev1.Service, error) {
+	ctx, done := startOperation(ctx, namespace, "Service", name, "GET", s.metricsRecorder)
+	service, err := s.kubeClient.CoreV1().Services(namespace).Get(ctx, name, metav1.GetOptions{})
+	done(err)
 	if err != nil {
 		return nil, err
 	}
 	return service, err
 }
 
-func (s *ServiceService) CreateService(namespace string, service *corev1.Service) error {
-	_, err := s.kubeClient.CoreV1().Services(namespace).Create(context.TODO(), service, metav1.CreateOptions{})
-	recordMetrics(namespace, "Service", service.GetName(), "CREATE", err, s.metricsRecorder)
+func (s *ServiceService) CreateService(ctx context.Context, namespace string, service *corev1.Service) error {
+	ctx, done := startOperation(ctx, namespace, "Service", service.GetName(), "CREATE", s.metricsRecorder)
+	_, err := s.kubeClient.CoreV1().Services(namespace).Create(ctx, service, metav1.CreateOptions{})
+	done(err)
 	if err != nil {
 		return err
 	}
-	s.logger.WithField("namespace", namespace).WithField("serviceName", service.Name).Debugf("service created")
+	log.FromContext(ctx, s.logger).WithField("namespace", namespace).WithField("serviceName", service.Name).Debugf("service created")
 	return nil
 }
 
-func (s *ServiceService) CreateIfNotExistsService(namespace string, service *corev1.Service) error {
-	if _, err := s.GetService(namespace, service.Name); err != nil {
+func (s *ServiceService) CreateIfNotExistsService(ctx context.Context, namespace string, service *corev1.Service) error {
+	if _, err := s.GetService(ctx, namespace, service.Name); err != nil {
 		// If no resource we need to create.
 		if errors.IsNotFound(err) {
-			return s.CreateService(namespace, service)
+			return s.CreateService(ctx, namespace, service)
 		}
 		return err
 	}
 	return nil
 }
 
-func (s *ServiceService) UpdateService(namespace string, service *corev1.Service) error {
-	_, err := s.kubeClient.CoreV1().Services(namespace).Update(context.TODO(), service, metav1.UpdateOptions{})
-	recordMetrics(namespace, "Service", service.GetName(), "UPDATE", err, s.metricsRecorder)
+func (s *ServiceService) UpdateService(ctx context.Context, namespace string, service *corev1.Service) error {
+	ctx, done := startOperation(ctx, namespace, "Service", service.GetName(), "UPDATE", s.metricsRecorder)
+	_, err := s.kubeClient.CoreV1().Services(namespace).Update(ctx, service, metav1.UpdateOptions{})
+	done(err)
 	if err != nil {
 		return err
 	}
-	s.logger.WithField("namespace", namespace).WithField("serviceName", service.Name).Debugf("service updated")
+	log.FromContext(ctx, s.logger).WithField("namespace", namespace).WithField("serviceName", service.Name).Debugf("service updated")
 	return nil
 }
-func (s *ServiceService) CreateOrUpdateService(namespace string, service *corev1.Service) error {
-	storedService, err := s.GetService(namespace, service.Name)
+func (s *ServiceService) CreateOrUpdateService(ctx context.Context, namespace string, service *corev1.Service) error {
+	storedService, err := s.GetService(ctx, namespace, service.Name)
 	if err != nil {
 		// If no resource we need to create.
 		if errors.IsNotFound(err) {
-			return s.CreateService(namespace, service)
+			return s.CreateService(ctx, namespace, service)
 		}
-		log.Errorf("Error while updating service %v in %v namespace : %v", service.GetName(), namespace, err)
+		log.FromContext(ctx, s.logger).WithField("namespace", namespace).WithField("serviceName", service.Name).Errorf("Error while updating service: %v", err)
 		return err
 	}
 
@@ -95,18 +98,20 @@ func (s *ServiceService) CreateOrUpdateService(namespace string, service *corev1
 	// namespace is our spec(https://github.com/kubernetes/community/blob/master/contributors/devel/api-conventions.md#concurrency-control-and-consistency),
 	// we will replace the current namespace state.
 	service.ResourceVersion = storedService.ResourceVersion
-	return s.UpdateService(namespace, service)
+	return s.UpdateService(ctx, namespace, service)
 }
 
-func (s *ServiceService) DeleteService(namespace string, name string) error {
+func (s *ServiceService) DeleteService(ctx context.Context, namespace string, name string) error {
 	propagation := metav1.DeletePropagationForeground
-	err := s.kubeClient.CoreV1().Services(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{PropagationPolicy: &propagation})
-	recordMetrics(namespace, "Service", name, "DELETE", err, s.metricsRecorder)
+	ctx, done := startOperation(ctx, namespace, "Service", name, "DELETE", s.metricsRecorder)
+	err := s.kubeClient.CoreV1().Services(namespace).Delete(ctx, name, metav1.DeleteOptions{PropagationPolicy: &propagation})
+	done(err)
 	return err
 }
 
-func (s *ServiceService) ListServices(namespace string) (*corev1.ServiceList, error) {
-	serviceList, err := s.kubeClient.CoreV1().Services(namespace).List(context.TODO(), metav1.ListOptions{})
-	recordMetrics(namespace, "Service", metrics.NOT_APPLICABLE, "LIST", err, s.metricsRecorder)
+func (s *ServiceService) ListServices(ctx context.Context, namespace string) (*corev1.ServiceList, error) {
+	ctx, done := startOperation(ctx, namespace, "Service", metrics.NOT_APPLICABLE, "LIST", s.metricsRecorder)
+	serviceList, err := s.kubeClient.CoreV1().Services(namespace).List(ctx, metav1.ListOptions{})
+	done(err)
 	return serviceList, err
 }
@@ -1,6 +1,7 @@
 package k8s_test
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -105,7 +106,7 @@ func TestServiceServiceGetCreateOrUpdate(t *testing.T) {
 			})
 
 			service := k8s.NewServiceService(mcli, log.Dummy, metrics.Dummy)
-			err := service.CreateOrUpdateService(testns, test.service)
+			err := service.CreateOrUpdateService(context.Background(), testns, test.service)
 
 			if test.expErr {
 				assert.Error(err)
@@ -22,13 +22,13 @@ import (
 
 // StatefulSet the StatefulSet service that knows how to interact with k8s to manage them
 type StatefulSet interface {
-	GetStatefulSet(namespace, name string) (*appsv1.StatefulSet, error)
-	GetStatefulSetPods(namespace, name string) (*corev1.PodList, error)
-	CreateStatefulSet(namespace string, statefulSet *appsv1.StatefulSet) error
-	UpdateStatefulSet(namespace string, statefulSet *appsv1.StatefulSet) error
-	CreateOrUpdateStatefulSet(namespace string, statefulSet *appsv1.StatefulSet) error
-	DeleteStatefulSet(namespace string, name string) error
-	ListStatefulSets(namespace string) (*appsv1.StatefulSetList, error)
+	GetStatefulSet(ctx context.Context, namespace, name string) (*appsv1.StatefulSet, error)
+	GetStatefulSetPods(ctx context.Context, namespace, name string) (*corev1.PodList, error)
+	CreateStatefulSet(ctx context.Context, namespace string, statefulSet *appsv1.StatefulSet) error
+	UpdateStatefulSet(ctx context.Context, namespace string, statefulSet *appsv1.StatefulSet) error
+	CreateOrUpdateStatefulSet(ctx context.Context, namespace string, statefulSet *appsv1.StatefulSet) error
+	DeleteStatefulSet(ctx context.Context, namespace string, name string) error
+	ListStatefulSets(ctx context.Context, namespace string) (*appsv1.StatefulSetList, error)
 }
 
 // StatefulSetService is the service account service implementation using API calls to kubernetes.
@@ -49,9 +49,10 @@ func NewStatefulSetService(kubeClient kubernetes.Interface, logger log.Logger, m
 }
 
 // GetStatefulSet will retrieve the requested statefulset based on namespace and name
-func (s *StatefulSetService) GetStatefulSet(namespace, name string) (*appsv1.StatefulSet, error) {
-	statefulSet, err := s.kubeClient.AppsV1().StatefulSets(namespace).Get(context.TODO(), name, metav1.GetOptions{})
-	recordMetrics(namespace, "StatefulSet", name, "GET", err, s.metricsRecorder)
+func (s *StatefulSetService) GetStatefulSet(ctx context.Context, namespace, name string) (*appsv1.StatefulSet, error) {
+	ctx, done := startOperation(ctx, namespace, "StatefulSet", name, "GET", s.metricsRecorder)
+	statefulSet, err := s.kubeClient.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+	done(err)
 	if err != nil {
 		return nil, err
 	}
@@ -59,8 +60,8 @@ func (s *StatefulSetService) GetStatefulSet(namespace, name string) (*appsv1.Sta
 }
 
 // GetStatefulSetPods will give a list of pods that are managed by the statefulset
-func (s *StatefulSetService) GetStatefulSetPods(namespace, name string) (*corev1.PodList, error) {
-	statefulSet, err := s.GetStatefulSet(namespace, name)
+func (s *StatefulSetService) GetStatefulSetPods(ctx context.Context, namespace, name string) (*corev1.PodList, error) {
+	statefulSet, err := s.GetStatefulSet(ctx, namespace, name)
 	if err != nil {
 		return nil, err
 	}
@@ -69,38 +70,43 @@ func (s *StatefulSetService) GetStatefulSetPods(namespace, name string) (*corev1
 		labels = append(labels, fmt.Sprintf("%s=%s", k, v))
 	}
 	selector := strings.Join(labels, ",")
-	return s.kubeClient.CoreV1().Pods(namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: selector})
+	ctx, done := startOperation(ctx, namespace, "Pod", metrics.NOT_APPLICABLE, "LIST", s.metricsRecorder)
+	pods, err := s.kubeClient.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	done(err)
+	return pods, err
 }
 
 // CreateStatefulSet will create the given statefulset
-func (s *StatefulSetService) CreateStatefulSet(namespace string, statefulSet *appsv1.StatefulSet) error {
-	_, err := s.kubeClient.AppsV1().StatefulSets(namespace).Create(context.TODO(), statefulSet, metav1.CreateOptions{})
-	recordMetrics(namespace, "StatefulSet", statefulSet.GetName(), "CREATE", err, s.metricsRecorder)
+func (s *StatefulSetService) CreateStatefulSet(ctx context.Context, namespace string, statefulSet *appsv1.StatefulSet) error {
+	ctx, done := startOperation(ctx, namespace, "StatefulSet", statefulSet.GetName(), "CREATE", s.metricsRecorder)
+	_, err := s.kubeClient.AppsV1().StatefulSets(namespace).Create(ctx, statefulSet, metav1.CreateOptions{})
+	done(err)
 	if err != nil {
 		return err
 	}
-	s.logger.WithField("namespace", namespace).WithField("statefulSet", statefulSet.ObjectMeta.Name).Debugf("statefulSet created")
+	log.FromContext(ctx, s.logger).WithField("namespace", namespace).WithField("statefulSet", statefulSet.ObjectMeta.Name).Debugf("statefulSet created")
 	return err
 }
 
 // UpdateStatefulSet will update the given statefulset
-func (s *StatefulSetService) UpdateStatefulSet(namespace string, statefulSet *appsv1.StatefulSet) error {
-	_, err := s.kubeClient.AppsV1().StatefulSets(namespace).Update(context.TODO(), statefulSet, metav1.UpdateOptions{})
-	recordMetrics(namespace, "StatefulSet", statefulSet.GetName(), "UPDATE", err, s.metricsRecorder)
+func (s *StatefulSetService) UpdateStatefulSet(ctx context.Context, namespace string, statefulSet *appsv1.StatefulSet) error {
+	ctx, done := startOperation(ctx, namespace, "StatefulSet", statefulSet.GetName(), "UPDATE", s.metricsRecorder)
+	_, err := s.kubeClient.AppsV1().StatefulSets(namespace).Update(ctx, statefulSet, metav1.UpdateOptions{})
+	done(err)
 	if err != nil {
 		return err
 	}
-	s.logger.WithField("namespace", namespace).WithField("statefulSet", statefulSet.ObjectMeta.Name).Debugf("statefulSet updated")
+	log.FromContext(ctx, s.logger).WithField("namespace", namespace).WithField("statefulSet", statefulSet.ObjectMeta.Name).Debugf("statefulSet updated")
 	return err
 }
 
 // CreateOrUpdateStatefulSet will update the statefulset or create it if does not exist
-func (s *StatefulSetService) CreateOrUpdateStatefulSet(namespace string, statefulSet *appsv1.StatefulSet) error {
-	storedStatefulSet, err := s.GetStatefulSet(namespace, statefulSet.Name)
+func (s *StatefulSetService) CreateOrUpdateStatefulSet(ctx context.Context, namespace string, statefulSet *appsv1.StatefulSet) error {
+	storedStatefulSet, err := s.GetStatefulSet(ctx, namespace, statefulSet.Name)
 	if err != nil {
 		// If no resource we need to create.
 		if errors.IsNotFound(err) {
-			return s.CreateStatefulSet(namespace, statefulSet)
+			return s.CreateStatefulSet(ctx, namespace, statefulSet)
 		}
 		return err
 	}
@@ -153,7 +159,7 @@ func (s *StatefulSetService) CreateOrUpdateStatefulSet(namespace string, statefu
 					_, err = s.kubeClient.CoreV1().PersistentVolumeClaims(storedStatefulSet.Namespace).Update(context.Background(), &pvc, metav1.UpdateOptions{})
 					if err != nil {
 						updateFailed = true
-						s.logger.WithField("namespace", namespace).WithField("pvc", pvc.Name).Warningf("resize pvc failed:%s", err.Error())
+						log.FromContext(ctx, s.logger).WithField("namespace", namespace).WithField("pvc", pvc.Name).Warningf("resize pvc failed:%s", err.Error())
 					}
 				}
 			}
@@ -161,9 +167,9 @@ func (s *StatefulSetService) CreateOrUpdateStatefulSet(namespace string, statefu
 				annotations["storageCapacity"] = fmt.Sprintf("%d", stateCapacity)
 				storedStatefulSet.Annotations = annotations
 				if realUpdate {
-					s.logger.WithField("namespace", namespace).WithField("statefulSet", statefulSet.Name).Infof("resize statefulset pvcs from %d to %d Success", storedCapacity, stateCapacity)
+					log.FromContext(ctx, s.logger).WithField("namespace", namespace).WithField("statefulSet", statefulSet.Name).Infof("resize statefulset pvcs from %d to %d Success", storedCapacity, stateCapacity)
 				} else {
-					s.logger.WithField("namespace", namespace).WithField("pvc", rfName).Warningf("set annotations,resize nothing")
+					log.FromContext(ctx, s.logger).WithField("namespace", namespace).WithField("pvc", rfName).Warningf("set annotations,resize nothing")
 				}
 			}
 		}
@@ -171,20 +177,22 @@ func (s *StatefulSetService) CreateOrUpdateStatefulSet(namespace string, statefu
 	// set stored.volumeClaimTemplates
 	statefulSet.Spec.VolumeClaimTemplates = storedStatefulSet.Spec.VolumeClaimTemplates
 	statefulSet.Annotations = util.MergeAnnotations(storedStatefulSet.Annotations, statefulSet.Annotations)
-	return s.UpdateStatefulSet(namespace, statefulSet)
+	return s.UpdateStatefulSet(ctx, namespace, statefulSet)
 }
 
 // DeleteStatefulSet will delete the statefulset
-func (s *StatefulSetService) DeleteStatefulSet(namespace, name string) error {
+func (s *StatefulSetService) DeleteStatefulSet(ctx context.Context, namespace, name string) error {
 	propagation := metav1.DeletePropagationForeground
-	err := s.kubeClient.AppsV1().StatefulSets(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{PropagationPolicy: &propagation})
-	recordMetrics(namespace, "StatefulSet", name, "DELETE", err, s.metricsRecorder)
+	ctx, done := startOperation(ctx, namespace, "StatefulSet", name, "DELETE", s.metricsRecorder)
+	err := s.kubeClient.AppsV1().StatefulSets(namespace).Delete(ctx, name, metav1.DeleteOptions{PropagationPolicy: &propagation})
+	done(err)
 	return err
 }
 
 // ListStatefulSets will retrieve a list of statefulset in the given namespace
-func (s *StatefulSetService) ListStatefulSets(namespace string) (*appsv1.StatefulSetList, error) {
-	stsList, err := s.kubeClient.AppsV1().StatefulSets(namespace).List(context.TODO(), metav1.ListOptions{})
-	recordMetrics(namespace, "StatefulSet", metrics.NOT_APPLICABLE, "LIST", err, s.metricsRecorder)
+func (s *StatefulSetService) ListStatefulSets(ctx context.Context, namespace string) (*appsv1.StatefulSetList, error) {
+	ctx, done := startOperation(ctx, namespace, "StatefulSet", metrics.NOT_APPLICABLE, "LIST", s.metricsRecorder)
+	stsList, err := s.kubeClient.AppsV1().StatefulSets(namespace).List(ctx, metav1.ListOptions{})
+	done(err)
 	return stsList, err
 }
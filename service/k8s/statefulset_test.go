@@ -1,6 +1,7 @@
 package k8s_test
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -109,7 +110,7 @@ func TestStatefulSetServiceGetCreateOrUpdate(t *testing.T) {
 			})
 
 			service := k8s.NewStatefulSetService(mcli, log.Dummy, metrics.Dummy)
-			err := service.CreateOrUpdateStatefulSet(testns, test.statefulSet)
+			err := service.CreateOrUpdateStatefulSet(context.Background(), testns, test.statefulSet)
 
 			if test.expErr {
 				assert.Error(err)
@@ -208,7 +209,7 @@ func TestStatefulSetServiceGetCreateOrUpdate(t *testing.T) {
 				return true, action.(kubetesting.UpdateActionImpl).Object, nil
 			})
 			service := k8s.NewStatefulSetService(mcli, log.Dummy, metrics.Dummy)
-			err := service.CreateOrUpdateStatefulSet(testns, afterSts)
+			err := service.CreateOrUpdateStatefulSet(context.Background(), testns, afterSts)
 			assert.NoError(err)
 			assert.Equal(pvcList.Items[0].Spec.Resources, pvcList.Items[1].Spec.Resources)
 			// should not call update
@@ -216,7 +217,7 @@ func TestStatefulSetServiceGetCreateOrUpdate(t *testing.T) {
 				panic("shouldn't call update")
 			})
 			service = k8s.NewStatefulSetService(mcli, log.Dummy, metrics.Dummy)
-			err = service.CreateOrUpdateStatefulSet(testns, afterSts)
+			err = service.CreateOrUpdateStatefulSet(context.Background(), testns, afterSts)
 			assert.NoError(err)
 		})
 	}
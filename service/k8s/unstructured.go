@@ -0,0 +1,115 @@
+package k8s
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/spotahome/redis-operator/log"
+	"github.com/spotahome/redis-operator/metrics"
+)
+
+// Unstructured the service that knows how to interact with k8s to manage the objects the operator has no
+// typed client for, as the ones of CRDs that may not be installed in the cluster. Their calls fail with a
+// NotFound error while the CRD of the kind is missing
+type Unstructured interface {
+	GetUnstructured(ctx context.Context, gvk schema.GroupVersionKind, namespace string, name string) (*unstructured.Unstructured, error)
+	CreateUnstructured(ctx context.Context, namespace string, object *unstructured.Unstructured) error
+	UpdateUnstructured(ctx context.Context, namespace string, object *unstructured.Unstructured) error
+	CreateOrUpdateUnstructured(ctx context.Context, namespace string, object *unstructured.Unstructured) error
+	DeleteUnstructured(ctx context.Context, gvk schema.GroupVersionKind, namespace string, name string) error
+	ListUnstructured(ctx context.Context, gvk schema.GroupVersionKind, namespace string, labelSelector string) (*unstructured.UnstructuredList, error)
+}
+
+// UnstructuredService is the unstructured service implementation using API calls to kubernetes.
+type UnstructuredService struct {
+	dynamicClient   dynamic.Interface
+	logger          log.Logger
+	metricsRecorder metrics.Recorder
+}
+
+// NewUnstructuredService returns a new Unstructured KubeService.
+func NewUnstructuredService(dynamicClient dynamic.Interface, logger log.Logger, metricsRecorder metrics.Recorder) *UnstructuredService {
+	logger = logger.With("service", "k8s.unstructured")
+	return &UnstructuredService{
+		dynamicClient:   dynamicClient,
+		logger:          logger,
+		metricsRecorder: metricsRecorder,
+	}
+}
+
+func (u *UnstructuredService) resource(gvk schema.GroupVersionKind, namespace string) dynamic.ResourceInterface {
+	gvr, _ := meta.UnsafeGuessKindToResource(gvk)
+	return u.dynamicClient.Resource(gvr).Namespace(namespace)
+}
+
+func (u *UnstructuredService) GetUnstructured(ctx context.Context, gvk schema.GroupVersionKind, namespace string, name string) (*unstructured.Unstructured, error) {
+	ctx, done := startOperation(ctx, namespace, gvk.Kind, name, "GET", u.metricsRecorder)
+	object, err := u.resource(gvk, namespace).Get(ctx, name, metav1.GetOptions{})
+	done(err)
+	if err != nil {
+		return nil, err
+	}
+	return object, err
+}
+
+func (u *UnstructuredService) CreateUnstructured(ctx context.Context, namespace string, object *unstructured.Unstructured) error {
+	gvk := object.GroupVersionKind()
+	ctx, done := startOperation(ctx, namespace, gvk.Kind, object.GetName(), "CREATE", u.metricsRecorder)
+	_, err := u.resource(gvk, namespace).Create(ctx, object, metav1.CreateOptions{})
+	done(err)
+	if err != nil {
+		return err
+	}
+	log.FromContext(ctx, u.logger).WithField("namespace", namespace).WithField("kind", gvk.Kind).WithField("name", object.GetName()).Debugf("object created")
+	return nil
+}
+
+func (u *UnstructuredService) UpdateUnstructured(ctx context.Context, namespace string, object *unstructured.Unstructured) error {
+	gvk := object.GroupVersionKind()
+	ctx, done := startOperation(ctx, namespace, gvk.Kind, object.GetName(), "UPDATE", u.metricsRecorder)
+	_, err := u.resource(gvk, namespace).Update(ctx, object, metav1.UpdateOptions{})
+	done(err)
+	if err != nil {
+		return err
+	}
+	log.FromContext(ctx, u.logger).WithField("namespace", namespace).WithField("kind", gvk.Kind).WithField("name", object.GetName()).Debugf("object updated")
+	return nil
+}
+
+func (u *UnstructuredService) CreateOrUpdateUnstructured(ctx context.Context, namespace string, object *unstructured.Unstructured) error {
+	storedObject, err := u.GetUnstructured(ctx, object.GroupVersionKind(), namespace, object.GetName())
+	if err != nil {
+		// If no resource we need to create.
+		if errors.IsNotFound(err) {
+			return u.CreateUnstructured(ctx, namespace, object)
+		}
+		return err
+	}
+
+	// Already exists, need to Update.
+	// Set the correct resource version to ensure we are on the latest version. This way the only valid
+	// namespace is our spec(https://github.com/kubernetes/community/blob/master/contributors/devel/api-conventions.md#concurrency-control-and-consistency),
+	// we will replace the current namespace state.
+	object.SetResourceVersion(storedObject.GetResourceVersion())
+	return u.UpdateUnstructured(ctx, namespace, object)
+}
+
+func (u *UnstructuredService) DeleteUnstructured(ctx context.Context, gvk schema.GroupVersionKind, namespace string, name string) error {
+	ctx, done := startOperation(ctx, namespace, gvk.Kind, name, "DELETE", u.metricsRecorder)
+	err := u.resource(gvk, namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	done(err)
+	return err
+}
+
+func (u *UnstructuredService) ListUnstructured(ctx context.Context, gvk schema.GroupVersionKind, namespace string, labelSelector string) (*unstructured.UnstructuredList, error) {
+	ctx, done := startOperation(ctx, namespace, gvk.Kind, metrics.NOT_APPLICABLE, "LIST", u.metricsRecorder)
+	objects, err := u.resource(gvk, namespace).List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
+	done(err)
+	return objects, err
+}
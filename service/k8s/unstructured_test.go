@@ -0,0 +1,75 @@
+package k8s_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubetesting "k8s.io/client-go/testing"
+
+	"github.com/spotahome/redis-operator/log"
+	"github.com/spotahome/redis-operator/metrics"
+	"github.com/spotahome/redis-operator/service/k8s"
+)
+
+var (
+	serviceMonitorsGroup = schema.GroupVersionResource{Group: "monitoring.coreos.com", Version: "v1", Resource: "servicemonitors"}
+	serviceMonitorKind   = schema.GroupVersionKind{Group: "monitoring.coreos.com", Version: "v1", Kind: "ServiceMonitor"}
+)
+
+func newServiceMonitor(name string, labels map[string]string, interval string) *unstructured.Unstructured {
+	object := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"endpoints": []interface{}{
+				map[string]interface{}{"port": "http-metrics", "interval": interval},
+			},
+		},
+	}}
+	object.SetGroupVersionKind(serviceMonitorKind)
+	object.SetName(name)
+	object.SetNamespace("testns")
+	object.SetLabels(labels)
+	return object
+}
+
+func TestUnstructuredServiceCreateOrUpdate(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	mcli := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		serviceMonitorsGroup: "ServiceMonitorList",
+	})
+	service := k8s.NewUnstructuredService(mcli, log.Dummy, metrics.Dummy)
+
+	labels := map[string]string{"app.kubernetes.io/name": "test"}
+	require.NoError(service.CreateOrUpdateUnstructured(context.Background(), "testns", newServiceMonitor("rfr-test", labels, "30s")))
+	require.NoError(service.CreateOrUpdateUnstructured(context.Background(), "testns", newServiceMonitor("rfr-test", labels, "15s")))
+	require.NoError(service.CreateOrUpdateUnstructured(context.Background(), "testns", newServiceMonitor("other", nil, "30s")))
+
+	verbs := []string{}
+	for _, action := range mcli.Actions() {
+		verbs = append(verbs, action.GetVerb())
+		assert.Equal(serviceMonitorsGroup, action.GetResource())
+	}
+	assert.Equal([]string{"get", "create", "get", "update", "get", "create"}, verbs)
+
+	stored, err := service.GetUnstructured(context.Background(), serviceMonitorKind, "testns", "rfr-test")
+	require.NoError(err)
+	endpoints, _, _ := unstructured.NestedSlice(stored.Object, "spec", "endpoints")
+	assert.Equal([]interface{}{map[string]interface{}{"port": "http-metrics", "interval": "15s"}}, endpoints)
+
+	list, err := service.ListUnstructured(context.Background(), serviceMonitorKind, "testns", "app.kubernetes.io/name=test")
+	require.NoError(err)
+	if assert.Len(list.Items, 1) {
+		assert.Equal("rfr-test", list.Items[0].GetName())
+	}
+
+	mcli.ClearActions()
+	require.NoError(service.DeleteUnstructured(context.Background(), serviceMonitorKind, "testns", "rfr-test"))
+	assert.Equal([]kubetesting.Action{kubetesting.NewDeleteAction(serviceMonitorsGroup, "testns", "rfr-test")}, mcli.Actions())
+}
@@ -1,23 +1,69 @@
 package k8s
 
 import (
+	"context"
 	"fmt"
 
 	redisfailoverv1 "github.com/spotahome/redis-operator/api/redisfailover/v1"
 	"github.com/spotahome/redis-operator/metrics"
+	"github.com/spotahome/redis-operator/tracing"
+	"go.opentelemetry.io/otel/attribute"
 	"k8s.io/apimachinery/pkg/api/errors"
 )
 
 // GetRedisPassword retreives password from kubernetes secret or, if
 // unspecified, returns a blank string
-func GetRedisPassword(s Services, rf *redisfailoverv1.RedisFailover) (string, error) {
+func GetRedisPassword(ctx context.Context, s Services, rf *redisfailoverv1.RedisFailover) (string, error) {
+	return getPassword(ctx, s, rf.ObjectMeta.Namespace, rf.Spec.Auth)
+}
 
+// GetRedisPreviousPassword returns the password being rotated out, kept in the auth secret along
+// with the new one while the clients move to it, or a blank string when no rotation is in progress
+func GetRedisPreviousPassword(ctx context.Context, s Services, rf *redisfailoverv1.RedisFailover) (string, error) {
 	if rf.Spec.Auth.SecretPath == "" {
+		return "", nil
+	}
+
+	secret, err := s.GetSecret(ctx, rf.ObjectMeta.Namespace, rf.Spec.Auth.SecretPath)
+	if err != nil {
+		return "", err
+	}
+
+	return string(secret.Data["previousPassword"]), nil
+}
+
+// GetSentinelPassword retreives the sentinel password from kubernetes secret or, if
+// unspecified, returns a blank string
+func GetSentinelPassword(ctx context.Context, s Services, rf *redisfailoverv1.RedisFailover) (string, error) {
+	return getPassword(ctx, s, rf.ObjectMeta.Namespace, rf.Spec.Sentinel.Auth)
+}
+
+// GetBootstrapSentinelPassword returns the password of the external sentinels the redises bootstrap from or, if
+// unspecified, a blank string
+func GetBootstrapSentinelPassword(ctx context.Context, s Services, rf *redisfailoverv1.RedisFailover) (string, error) {
+	return getPassword(ctx, s, rf.ObjectMeta.Namespace, rf.Spec.BootstrapNode.SentinelAuth)
+}
+
+// GetRedisMasterAuth returns the password the redises authenticate to their master with, the one of the
+// source RedisFailover while replicating it, the bootstrap one if any while bootstrapping and their own otherwise
+func GetRedisMasterAuth(ctx context.Context, s Services, rf *redisfailoverv1.RedisFailover) (string, error) {
+	if rf.Replicating() {
+		return getPassword(ctx, s, rf.ObjectMeta.Namespace, rf.Spec.ReplicaOf.MasterAuth)
+	}
+	if rf.Bootstrapping() && rf.Spec.BootstrapNode.MasterAuth.SecretPath != "" {
+		return getPassword(ctx, s, rf.ObjectMeta.Namespace, rf.Spec.BootstrapNode.MasterAuth)
+	}
+	return GetRedisPassword(ctx, s, rf)
+}
+
+func getPassword(ctx context.Context, s Services, namespace string, auth redisfailoverv1.AuthSettings) (string, error) {
+
+	if auth.SecretPath == "" {
 		// no auth settings specified, return blank password
 		return "", nil
 	}
 
-	secret, err := s.GetSecret(rf.ObjectMeta.Namespace, rf.Spec.Auth.SecretPath)
+	secret, err := s.GetSecret(ctx, namespace, auth.SecretPath)
 	if err != nil {
 		return "", err
 	}
@@ -26,7 +72,22 @@ func GetRedisPassword(s Services, rf *redisfailoverv1.RedisFailover) (string, er
 		return string(password), nil
 	}
 
-	return "", fmt.Errorf("secret \"%s\" does not have a password field", rf.Spec.Auth.SecretPath)
+	return "", fmt.Errorf("secret \"%s\" does not have a password field", auth.SecretPath)
+}
+
+// startOperation starts the span of an operation on the Kubernetes API, the returned function ends it with the
+// error of the operation and records its metrics
+func startOperation(ctx context.Context, namespace string, kind string, object string, operation string, metricsRecorder metrics.Recorder) (context.Context, func(error)) {
+	ctx, span := tracing.Start(ctx, "k8s "+operation+" "+kind,
+		attribute.String("k8s.namespace", namespace),
+		attribute.String("k8s.kind", kind),
+		attribute.String("k8s.name", object),
+		attribute.String("k8s.operation", operation),
+	)
+	return ctx, func(err error) {
+		tracing.End(span, err)
+		recordMetrics(namespace, kind, object, operation, err, metricsRecorder)
+	}
 }
 
 func recordMetrics(namespace string, kind string, object string, operation string, err error, metricsRecorder metrics.Recorder) {
@@ -2,6 +2,7 @@ package redis
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"net"
@@ -16,31 +17,98 @@ import (
 
 // Client defines the functions neccesary to connect to redis and sentinel to get or set what we nned
 type Client interface {
-	GetNumberSentinelsInMemory(ip string) (int32, error)
-	GetNumberSentinelSlavesInMemory(ip string) (int32, error)
-	ResetSentinel(ip string) error
-	GetSlaveOf(ip, port, password string) (string, error)
-	IsMaster(ip, port, password string) (bool, error)
-	MonitorRedis(ip, monitor, quorum, password string) error
-	MonitorRedisWithPort(ip, monitor, port, quorum, password string) error
-	MakeMaster(ip, port, password string) error
-	MakeSlaveOf(ip, masterIP, password string) error
-	MakeSlaveOfWithPort(ip, masterIP, masterPort, password string) error
-	GetSentinelMonitor(ip string) (string, string, error)
-	SetCustomSentinelConfig(ip string, configs []string) error
-	SetCustomRedisConfig(ip string, port string, configs []string, password string) error
-	SlaveIsReady(ip, port, password string) (bool, error)
-	SentinelCheckQuorum(ip string) error
+	GetNumberSentinelsInMemory(ctx context.Context, ip, port, password string) (int32, error)
+	GetNumberSentinelSlavesInMemory(ctx context.Context, ip, port, password string) (int32, error)
+	ResetSentinel(ctx context.Context, ip, port, password string) error
+	GetSlaveOf(ctx context.Context, ip, port, password string) (string, error)
+	IsMaster(ctx context.Context, ip, port, password string) (bool, error)
+	MonitorRedisWithPort(ctx context.Context, ip, port, masterName string, monitor MonitorOptions, password string) error
+	MakeMaster(ctx context.Context, ip, port, password string) error
+	MakeSlaveOfWithPort(ctx context.Context, ip, masterIP, masterPort, password string) error
+	GetSentinelMonitor(ctx context.Context, ip, port, masterName, password string) (string, string, error)
+	SetCustomSentinelConfig(ctx context.Context, ip, port, masterName string, configs []string, password string) error
+	SetCustomRedisConfig(ctx context.Context, ip string, port string, configs []string, password string) error
+	SetSentinelAnnounce(ctx context.Context, ip, port, announceIP, announcePort, password string) error
+	GetMasterAddrByName(ctx context.Context, sentinel, masterName, password string, tlsConfig *tls.Config) (string, string, error)
+	GetReplicationOffset(ctx context.Context, ip, port, password string) (int64, error)
+	DisableReplicationTLS(ctx context.Context, ip, port, password string) error
+	SetDefaultUserPasswords(ctx context.Context, ip, port string, passwords []string, password string) error
+	SubscribeSentinel(ctx context.Context, ip, port, password string, patterns []string) (<-chan SentinelEvent, error)
+	GetReplicationInfo(ctx context.Context, ip, port, password string) (ReplicationInfo, error)
+	GetSentinelMasterInfo(ctx context.Context, ip, port, masterName, password string) (SentinelMasterInfo, error)
+	SentinelFailover(ctx context.Context, ip, port, masterName, password string) error
+	GetSentinelReplicas(ctx context.Context, ip, port, masterName, password string) ([]SentinelReplica, error)
+	GetRedisConfig(ctx context.Context, ip, port string, parameters []string, password string) (map[string]string, error)
+	SlaveIsReady(ctx context.Context, ip, port, password string) (bool, error)
+	SentinelCheckQuorum(ctx context.Context, ip, port, masterName, password string) error
+}
+
+// MonitorOptions is the master a sentinel is told to monitor and how it authenticates to it
+type MonitorOptions struct {
+	IP     string
+	Port   string
+	Quorum string
+	// AuthUser and AuthPass are the credentials of the master, left unset when it requires none
+	AuthUser string
+	AuthPass string
+}
+
+// SentinelEvent is an event published by sentinel on its pub/sub channels
+type SentinelEvent struct {
+	Channel string
+	Payload string
+}
+
+// ReplicationInfo is the replication state of a redis, along with its memory and clients, as given by `INFO`
+type ReplicationInfo struct {
+	Role             string
+	MasterHost       string
+	MasterPort       string
+	MasterLinkStatus string
+	// Offset is the replication offset of the master, or the one processed by the replica
+	Offset int64
+	// UsedMemory is the memory allocated by redis, in bytes
+	UsedMemory       int64
+	ConnectedClients int
+}
+
+// SentinelMasterInfo is the view of a sentinel on the master it monitors
+type SentinelMasterInfo struct {
+	IP     string
+	Port   string
+	Flags  string
+	Quorum int
+	// Sentinels are the addresses of the other sentinels monitoring the master
+	Sentinels []string
+	// Replicas are the addresses of the replicas of the master
+	Replicas []string
+	// QuorumReachable is false when the sentinels able to authorize a failover aren't enough
+	QuorumReachable bool
+}
+
+// SentinelReplica is the view of a sentinel on a replica of the master it monitors
+type SentinelReplica struct {
+	// Address is the address the replica announces, as host:port
+	Address          string
+	Flags            string
+	Priority         int
+	MasterLinkStatus string
+	// Offset is the replication offset the replica last reported
+	Offset int64
 }
 
 type client struct {
 	metricsRecorder metrics.Recorder
+	config          Config
+	pools           *pools
 }
 
-// New returns a redis client
-func New(metricsRecorder metrics.Recorder) Client {
+// New returns a redis client, keeping a pool of connections for every redis and sentinel it talks to
+func New(metricsRecorder metrics.Recorder, config Config) Client {
 	return &client{
 		metricsRecorder: metricsRecorder,
+		config:          config,
+		pools:           newPools(config.PoolIdleTimeout),
 	}
 }
 
@@ -48,14 +116,12 @@ const (
 	sentinelsNumberREString = "sentinels=([0-9]+)"
 	slaveNumberREString     = "slaves=([0-9]+)"
 	sentinelStatusREString  = "status=([a-z]+)"
-	redisMasterHostREString = "master_host:([0-9.]+)"
+	redisMasterHostREString = "master_host:(\\S+)"
+	redisReplOffsetREString = "master_repl_offset:([0-9]+)"
 	redisRoleMaster         = "role:master"
 	redisSyncing            = "master_sync_in_progress:1"
 	redisMasterSillPending  = "master_host:127.0.0.1"
 	redisLinkUp             = "master_link_status:up"
-	redisPort               = "6379"
-	sentinelPort            = "26379"
-	masterName              = "master0"
 )
 
 var (
@@ -63,18 +129,13 @@ var (
 	sentinelStatusRE  = regexp.MustCompile(sentinelStatusREString)
 	slaveNumberRE     = regexp.MustCompile(slaveNumberREString)
 	redisMasterHostRE = regexp.MustCompile(redisMasterHostREString)
+	redisReplOffsetRE = regexp.MustCompile(redisReplOffsetREString)
 )
 
 // GetNumberSentinelsInMemory return the number of sentinels that the requested sentinel has
-func (c *client) GetNumberSentinelsInMemory(ip string) (int32, error) {
-	options := &rediscli.Options{
-		Addr:     net.JoinHostPort(ip, sentinelPort),
-		Password: "",
-		DB:       0,
-	}
-	rClient := rediscli.NewClient(options)
-	defer rClient.Close()
-	info, err := rClient.Info(context.TODO(), "sentinel").Result()
+func (c *client) GetNumberSentinelsInMemory(ctx context.Context, ip, port, password string) (int32, error) {
+	rClient := c.getClient(net.JoinHostPort(ip, port), password)
+	info, err := rClient.Info(ctx, "sentinel").Result()
 	if err != nil {
 		c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, ip, metrics.GET_NUM_SENTINELS_IN_MEM, metrics.FAIL, getRedisError(err))
 		return 0, err
@@ -98,15 +159,9 @@ func (c *client) GetNumberSentinelsInMemory(ip string) (int32, error) {
 }
 
 // GetNumberSentinelsInMemory return the number of sentinels that the requested sentinel has
-func (c *client) GetNumberSentinelSlavesInMemory(ip string) (int32, error) {
-	options := &rediscli.Options{
-		Addr:     net.JoinHostPort(ip, sentinelPort),
-		Password: "",
-		DB:       0,
-	}
-	rClient := rediscli.NewClient(options)
-	defer rClient.Close()
-	info, err := rClient.Info(context.TODO(), "sentinel").Result()
+func (c *client) GetNumberSentinelSlavesInMemory(ctx context.Context, ip, port, password string) (int32, error) {
+	rClient := c.getClient(net.JoinHostPort(ip, port), password)
+	info, err := rClient.Info(ctx, "sentinel").Result()
 	if err != nil {
 		c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, ip, metrics.GET_NUM_REDIS_SLAVES_IN_MEM, metrics.FAIL, getRedisError(err))
 		return 0, err
@@ -138,16 +193,10 @@ func isSentinelReady(info string) error {
 }
 
 // ResetSentinel sends a sentinel reset * for the given sentinel
-func (c *client) ResetSentinel(ip string) error {
-	options := &rediscli.Options{
-		Addr:     net.JoinHostPort(ip, sentinelPort),
-		Password: "",
-		DB:       0,
-	}
-	rClient := rediscli.NewClient(options)
-	defer rClient.Close()
-	cmd := rediscli.NewIntCmd(context.TODO(), "SENTINEL", "reset", "*")
-	err := rClient.Process(context.TODO(), cmd)
+func (c *client) ResetSentinel(ctx context.Context, ip, port, password string) error {
+	rClient := c.getClient(net.JoinHostPort(ip, port), password)
+	cmd := rediscli.NewIntCmd(ctx, "SENTINEL", "reset", "*")
+	err := rClient.Process(ctx, cmd)
 	if err != nil {
 		c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, ip, metrics.RESET_SENTINEL, metrics.FAIL, getRedisError(err))
 		return err
@@ -162,16 +211,10 @@ func (c *client) ResetSentinel(ip string) error {
 }
 
 // GetSlaveOf returns the master of the given redis, or nil if it's master
-func (c *client) GetSlaveOf(ip, port, password string) (string, error) {
+func (c *client) GetSlaveOf(ctx context.Context, ip, port, password string) (string, error) {
 
-	options := &rediscli.Options{
-		Addr:     net.JoinHostPort(ip, port),
-		Password: password,
-		DB:       0,
-	}
-	rClient := rediscli.NewClient(options)
-	defer rClient.Close()
-	info, err := rClient.Info(context.TODO(), "replication").Result()
+	rClient := c.getClient(net.JoinHostPort(ip, port), password)
+	info, err := rClient.Info(ctx, "replication").Result()
 	if err != nil {
 		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.GET_SLAVE_OF, metrics.FAIL, getRedisError(err))
 		log.Errorf("error while getting masterIP : Failed to get info replication while querying redis instance %v", ip)
@@ -186,15 +229,273 @@ func (c *client) GetSlaveOf(ip, port, password string) (string, error) {
 	return match[1], nil
 }
 
-func (c *client) IsMaster(ip, port, password string) (bool, error) {
-	options := &rediscli.Options{
-		Addr:     net.JoinHostPort(ip, port),
-		Password: password,
-		DB:       0,
+// GetMasterAddrByName asks the sentinel, given as host:port, for the address of the master it monitors by the given name
+func (c *client) GetMasterAddrByName(ctx context.Context, sentinel, masterName, password string, tlsConfig *tls.Config) (string, string, error) {
+	// sentinels reached through TLS are outside of the cluster and asked once per check, they aren't pooled
+	rClient := c.getClient(sentinel, password)
+	if tlsConfig != nil {
+		rClient = c.newClient(sentinel, password, tlsConfig)
+		defer rClient.Close()
+	}
+	cmd := rediscli.NewStringSliceCmd(ctx, "SENTINEL", "get-master-addr-by-name", masterName)
+	_ = rClient.Process(ctx, cmd)
+	res, err := cmd.Result()
+	if err != nil {
+		c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, sentinel, metrics.GET_MASTER_ADDR_BY_NAME, metrics.FAIL, getRedisError(err))
+		return "", "", err
+	}
+	if len(res) != 2 {
+		c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, sentinel, metrics.GET_MASTER_ADDR_BY_NAME, metrics.FAIL, metrics.NOT_APPLICABLE)
+		return "", "", fmt.Errorf("sentinel %s doesn't know master %s", sentinel, masterName)
+	}
+	c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, sentinel, metrics.GET_MASTER_ADDR_BY_NAME, metrics.SUCCESS, metrics.NOT_APPLICABLE)
+	return res[0], res[1], nil
+}
+
+// GetReplicationOffset returns the replication offset of the given redis, how much of the master stream it has processed
+func (c *client) GetReplicationOffset(ctx context.Context, ip, port, password string) (int64, error) {
+	rClient := c.getClient(net.JoinHostPort(ip, port), password)
+	info, err := rClient.Info(ctx, "replication").Result()
+	if err != nil {
+		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.GET_REPLICATION_OFFSET, metrics.FAIL, getRedisError(err))
+		return 0, err
+	}
+	match := redisReplOffsetRE.FindStringSubmatch(info)
+	if len(match) == 0 {
+		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.GET_REPLICATION_OFFSET, metrics.FAIL, metrics.NOT_APPLICABLE)
+		return 0, errors.New("replication offset not found")
 	}
-	rClient := rediscli.NewClient(options)
-	defer rClient.Close()
-	info, err := rClient.Info(context.TODO(), "replication").Result()
+	c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.GET_REPLICATION_OFFSET, metrics.SUCCESS, metrics.NOT_APPLICABLE)
+	return strconv.ParseInt(match[1], 10, 64)
+}
+
+// DisableReplicationTLS turns TLS replication off on the given redis, redises built without TLS support are left untouched
+func (c *client) DisableReplicationTLS(ctx context.Context, ip, port, password string) error {
+	rClient := c.getClient(net.JoinHostPort(ip, port), password)
+	res, err := rClient.ConfigGet(ctx, "tls-replication").Result()
+	if err != nil {
+		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.DISABLE_REPLICATION_TLS, metrics.FAIL, getRedisError(err))
+		return err
+	}
+	if len(res) != 2 || res[1] != "yes" {
+		return nil
+	}
+	if err := rClient.ConfigSet(ctx, "tls-replication", "no").Err(); err != nil {
+		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.DISABLE_REPLICATION_TLS, metrics.FAIL, getRedisError(err))
+		return err
+	}
+	c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.DISABLE_REPLICATION_TLS, metrics.SUCCESS, metrics.NOT_APPLICABLE)
+	return nil
+}
+
+// SetDefaultUserPasswords replaces the passwords accepted by the default user of the given redis with the given ones
+func (c *client) SetDefaultUserPasswords(ctx context.Context, ip, port string, passwords []string, password string) error {
+	rClient := c.getClient(net.JoinHostPort(ip, port), password)
+	args := []interface{}{"ACL", "SETUSER", "default", "on", "resetpass"}
+	for _, p := range passwords {
+		args = append(args, ">"+p)
+	}
+	if err := rClient.Do(ctx, args...).Err(); err != nil {
+		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.SET_DEFAULT_USER_PASSWORDS, metrics.FAIL, getRedisError(err))
+		return err
+	}
+	c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.SET_DEFAULT_USER_PASSWORDS, metrics.SUCCESS, metrics.NOT_APPLICABLE)
+	return nil
+}
+
+// SubscribeSentinel subscribes to the channels of the given sentinel matching the given patterns. Its events are sent on the
+// returned channel, closed once the subscription ends, because the context is done or the connection is lost
+func (c *client) SubscribeSentinel(ctx context.Context, ip, port, password string, patterns []string) (<-chan SentinelEvent, error) {
+	rClient := c.getClient(net.JoinHostPort(ip, port), password)
+	pubsub := rClient.PSubscribe(ctx, patterns...)
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, ip, metrics.SUBSCRIBE_SENTINEL, metrics.FAIL, getRedisError(err))
+		return nil, err
+	}
+	c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, ip, metrics.SUBSCRIBE_SENTINEL, metrics.SUCCESS, metrics.NOT_APPLICABLE)
+
+	events := make(chan SentinelEvent)
+	done := make(chan struct{})
+	go func() {
+		// reads on the subscription don't honour the context, closing it unblocks them
+		select {
+		case <-ctx.Done():
+			pubsub.Close()
+		case <-done:
+		}
+	}()
+	go func() {
+		defer close(events)
+		defer close(done)
+		defer pubsub.Close()
+		for {
+			msg, err := pubsub.ReceiveMessage(ctx)
+			if err != nil {
+				return
+			}
+			select {
+			case events <- SentinelEvent{Channel: msg.Channel, Payload: msg.Payload}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return events, nil
+}
+
+// GetReplicationInfo returns the replication state of the given redis, along with its memory and clients
+func (c *client) GetReplicationInfo(ctx context.Context, ip, port, password string) (ReplicationInfo, error) {
+	rClient := c.getClient(net.JoinHostPort(ip, port), password)
+	// the default sections hold the replication, memory and clients ones
+	info, err := rClient.Info(ctx).Result()
+	if err != nil {
+		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.GET_REPLICATION_INFO, metrics.FAIL, getRedisError(err))
+		return ReplicationInfo{}, err
+	}
+	c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.GET_REPLICATION_INFO, metrics.SUCCESS, metrics.NOT_APPLICABLE)
+	return parseReplicationInfo(info), nil
+}
+
+// GetSentinelMasterInfo returns what the given sentinel knows about the master it monitors by the given name
+func (c *client) GetSentinelMasterInfo(ctx context.Context, ip, port, masterName, password string) (SentinelMasterInfo, error) {
+	rClient := c.getClient(net.JoinHostPort(ip, port), password)
+
+	masterCmd := rediscli.NewSliceCmd(ctx, "SENTINEL", "master", masterName)
+	sentinelsCmd := rediscli.NewSliceCmd(ctx, "SENTINEL", "sentinels", masterName)
+	replicasCmd := rediscli.NewSliceCmd(ctx, "SENTINEL", "slaves", masterName)
+	for _, cmd := range []*rediscli.SliceCmd{masterCmd, sentinelsCmd, replicasCmd} {
+		if err := rClient.Process(ctx, cmd); err != nil {
+			c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, ip, metrics.GET_SENTINEL_MASTER_INFO, metrics.FAIL, getRedisError(err))
+			return SentinelMasterInfo{}, err
+		}
+	}
+
+	master := sentinelFields(masterCmd.Val())
+	quorum, _ := strconv.Atoi(master["quorum"])
+	info := SentinelMasterInfo{
+		IP:     master["ip"],
+		Port:   master["port"],
+		Flags:  master["flags"],
+		Quorum: quorum,
+	}
+	for _, sentinel := range sentinelsCmd.Val() {
+		fields := sentinelFields(sentinel)
+		info.Sentinels = append(info.Sentinels, net.JoinHostPort(fields["ip"], fields["port"]))
+	}
+	for _, replica := range replicasCmd.Val() {
+		fields := sentinelFields(replica)
+		info.Replicas = append(info.Replicas, net.JoinHostPort(fields["ip"], fields["port"]))
+	}
+
+	quorumCmd := rediscli.NewStringCmd(ctx, "SENTINEL", "ckquorum", masterName)
+	_ = rClient.Process(ctx, quorumCmd)
+	info.QuorumReachable = quorumCmd.Err() == nil
+
+	c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, ip, metrics.GET_SENTINEL_MASTER_INFO, metrics.SUCCESS, metrics.NOT_APPLICABLE)
+	return info, nil
+}
+
+// GetSentinelReplicas returns the view of the given sentinel on the replicas of the master it monitors by the given
+// name, as refreshed from their INFO every few seconds
+func (c *client) GetSentinelReplicas(ctx context.Context, ip, port, masterName, password string) ([]SentinelReplica, error) {
+	rClient := c.getClient(net.JoinHostPort(ip, port), password)
+	cmd := rediscli.NewSliceCmd(ctx, "SENTINEL", "slaves", masterName)
+	if err := rClient.Process(ctx, cmd); err != nil {
+		c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, ip, metrics.GET_SENTINEL_REPLICAS, metrics.FAIL, getRedisError(err))
+		return nil, err
+	}
+
+	replicas := []SentinelReplica{}
+	for _, replica := range cmd.Val() {
+		fields := sentinelFields(replica)
+		r := SentinelReplica{
+			Address:          net.JoinHostPort(fields["ip"], fields["port"]),
+			Flags:            fields["flags"],
+			MasterLinkStatus: fields["master-link-status"],
+		}
+		r.Priority, _ = strconv.Atoi(fields["slave-priority"])
+		r.Offset, _ = strconv.ParseInt(fields["slave-repl-offset"], 10, 64)
+		replicas = append(replicas, r)
+	}
+	c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, ip, metrics.GET_SENTINEL_REPLICAS, metrics.SUCCESS, metrics.NOT_APPLICABLE)
+	return replicas, nil
+}
+
+// SentinelFailover asks the given sentinel to fail the master it monitors by the given name over, without the
+// agreement of the other sentinels
+func (c *client) SentinelFailover(ctx context.Context, ip, port, masterName, password string) error {
+	rClient := c.getClient(net.JoinHostPort(ip, port), password)
+	cmd := rediscli.NewStatusCmd(ctx, "SENTINEL", "failover", masterName)
+	if err := rClient.Process(ctx, cmd); err != nil {
+		c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, ip, metrics.SENTINEL_FAILOVER, metrics.FAIL, getRedisError(err))
+		return err
+	}
+	c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, ip, metrics.SENTINEL_FAILOVER, metrics.SUCCESS, metrics.NOT_APPLICABLE)
+	return nil
+}
+
+// GetRedisConfig returns the values of the given configuration parameters of the redis, as given by `CONFIG GET`
+func (c *client) GetRedisConfig(ctx context.Context, ip, port string, parameters []string, password string) (map[string]string, error) {
+	rClient := c.getClient(net.JoinHostPort(ip, port), password)
+	config := map[string]string{}
+	for _, parameter := range parameters {
+		res, err := rClient.ConfigGet(ctx, parameter).Result()
+		if err != nil {
+			c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.GET_REDIS_CONFIG, metrics.FAIL, getRedisError(err))
+			return nil, err
+		}
+		for i := 0; i+1 < len(res); i += 2 {
+			key, _ := res[i].(string)
+			value, _ := res[i+1].(string)
+			config[key] = value
+		}
+	}
+	c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.GET_REDIS_CONFIG, metrics.SUCCESS, metrics.NOT_APPLICABLE)
+	return config, nil
+}
+
+// parseReplicationInfo parses the replication, memory and clients sections of the output of `INFO`
+func parseReplicationInfo(info string) ReplicationInfo {
+	fields := map[string]string{}
+	for _, line := range strings.Split(info, "\n") {
+		if key, value, ok := strings.Cut(strings.TrimSpace(line), ":"); ok {
+			fields[key] = value
+		}
+	}
+	replication := ReplicationInfo{
+		Role:             fields["role"],
+		MasterHost:       fields["master_host"],
+		MasterPort:       fields["master_port"],
+		MasterLinkStatus: fields["master_link_status"],
+	}
+	replication.Offset, _ = strconv.ParseInt(fields["master_repl_offset"], 10, 64)
+	if offset, ok := fields["slave_repl_offset"]; ok {
+		replication.Offset, _ = strconv.ParseInt(offset, 10, 64)
+	}
+	replication.UsedMemory, _ = strconv.ParseInt(fields["used_memory"], 10, 64)
+	replication.ConnectedClients, _ = strconv.Atoi(fields["connected_clients"])
+	return replication
+}
+
+// sentinelFields turns a reply of sentinel, a flat list of keys and values, into a map
+func sentinelFields(reply interface{}) map[string]string {
+	fields := map[string]string{}
+	values, ok := reply.([]interface{})
+	if !ok {
+		return fields
+	}
+	for i := 0; i+1 < len(values); i += 2 {
+		key, _ := values[i].(string)
+		value, _ := values[i+1].(string)
+		fields[key] = value
+	}
+	return fields
+}
+
+func (c *client) IsMaster(ctx context.Context, ip, port, password string) (bool, error) {
+	rClient := c.getClient(net.JoinHostPort(ip, port), password)
+	info, err := rClient.Info(ctx, "replication").Result()
 	if err != nil {
 		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.IS_MASTER, metrics.FAIL, getRedisError(err))
 		return false, err
@@ -203,23 +504,15 @@ func (c *client) IsMaster(ip, port, password string) (bool, error) {
 	return strings.Contains(info, redisRoleMaster), nil
 }
 
-func (c *client) MonitorRedis(ip, monitor, quorum, password string) error {
-	return c.MonitorRedisWithPort(ip, monitor, redisPort, quorum, password)
-}
-
-func (c *client) MonitorRedisWithPort(ip, monitor, port, quorum, password string) error {
-	options := &rediscli.Options{
-		Addr:     net.JoinHostPort(ip, sentinelPort),
-		Password: "",
-		DB:       0,
-	}
-	rClient := rediscli.NewClient(options)
-	defer rClient.Close()
-	cmd := rediscli.NewBoolCmd(context.TODO(), "SENTINEL", "REMOVE", masterName)
-	_ = rClient.Process(context.TODO(), cmd)
+// MonitorRedisWithPort makes the sentinel monitor the given master under masterName, authenticating to it with
+// the credentials of the monitor options
+func (c *client) MonitorRedisWithPort(ctx context.Context, ip, port, masterName string, monitor MonitorOptions, password string) error {
+	rClient := c.getClient(net.JoinHostPort(ip, port), password)
+	cmd := rediscli.NewBoolCmd(ctx, "SENTINEL", "REMOVE", masterName)
+	_ = rClient.Process(ctx, cmd)
 	// We'll continue even if it fails, the priority is to have the redises monitored
-	cmd = rediscli.NewBoolCmd(context.TODO(), "SENTINEL", "MONITOR", masterName, monitor, port, quorum)
-	err := rClient.Process(context.TODO(), cmd)
+	cmd = rediscli.NewBoolCmd(ctx, "SENTINEL", "MONITOR", masterName, monitor.IP, monitor.Port, monitor.Quorum)
+	err := rClient.Process(ctx, cmd)
 	if err != nil {
 		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.MONITOR_REDIS_WITH_PORT, metrics.FAIL, getRedisError(err))
 		return err
@@ -230,9 +523,12 @@ func (c *client) MonitorRedisWithPort(ip, monitor, port, quorum, password string
 		return err
 	}
 
-	if password != "" {
-		cmd = rediscli.NewBoolCmd(context.TODO(), "SENTINEL", "SET", masterName, "auth-pass", password)
-		err := rClient.Process(context.TODO(), cmd)
+	for _, auth := range [][2]string{{"auth-user", monitor.AuthUser}, {"auth-pass", monitor.AuthPass}} {
+		if auth[1] == "" {
+			continue
+		}
+		cmd = rediscli.NewBoolCmd(ctx, "SENTINEL", "SET", masterName, auth[0], auth[1])
+		err := rClient.Process(ctx, cmd)
 		if err != nil {
 			c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.MONITOR_REDIS_WITH_PORT, metrics.FAIL, getRedisError(err))
 			return err
@@ -248,15 +544,9 @@ func (c *client) MonitorRedisWithPort(ip, monitor, port, quorum, password string
 }
 
 // MakeMaster execute command: slaveof no one
-func (c *client) MakeMaster(ip string, port string, password string) error {
-	options := &rediscli.Options{
-		Addr:     net.JoinHostPort(ip, port),
-		Password: password,
-		DB:       0,
-	}
-	rClient := rediscli.NewClient(options)
-	defer rClient.Close()
-	if res := rClient.SlaveOf(context.TODO(), "NO", "ONE"); res.Err() != nil {
+func (c *client) MakeMaster(ctx context.Context, ip string, port string, password string) error {
+	rClient := c.getClient(net.JoinHostPort(ip, port), password)
+	if res := rClient.SlaveOf(ctx, "NO", "ONE"); res.Err() != nil {
 		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.MAKE_MASTER, metrics.FAIL, getRedisError(res.Err()))
 		return res.Err()
 	}
@@ -264,20 +554,10 @@ func (c *client) MakeMaster(ip string, port string, password string) error {
 	return nil
 }
 
-func (c *client) MakeSlaveOf(ip, masterIP, password string) error {
-	return c.MakeSlaveOfWithPort(ip, masterIP, redisPort, password)
-}
-
 // MakeSlaveOfWithPort execute command: slaveof [ip] [port]
-func (c *client) MakeSlaveOfWithPort(ip, masterIP, masterPort, password string) error {
-	options := &rediscli.Options{
-		Addr:     net.JoinHostPort(ip, masterPort), // this is IP and Port for the RedisFailover redis
-		Password: password,
-		DB:       0,
-	}
-	rClient := rediscli.NewClient(options)
-	defer rClient.Close()
-	if res := rClient.SlaveOf(context.TODO(), masterIP, masterPort); res.Err() != nil {
+func (c *client) MakeSlaveOfWithPort(ctx context.Context, ip, masterIP, masterPort, password string) error {
+	rClient := c.getClient(net.JoinHostPort(ip, masterPort), password) // this is IP and Port for the RedisFailover redis
+	if res := rClient.SlaveOf(ctx, masterIP, masterPort); res.Err() != nil {
 		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, ip, metrics.MAKE_SLAVE_OF, metrics.FAIL, getRedisError(res.Err()))
 		return res.Err()
 	}
@@ -285,16 +565,10 @@ func (c *client) MakeSlaveOfWithPort(ip, masterIP, masterPort, password string)
 	return nil
 }
 
-func (c *client) GetSentinelMonitor(ip string) (string, string, error) {
-	options := &rediscli.Options{
-		Addr:     net.JoinHostPort(ip, sentinelPort),
-		Password: "",
-		DB:       0,
-	}
-	rClient := rediscli.NewClient(options)
-	defer rClient.Close()
-	cmd := rediscli.NewSliceCmd(context.TODO(), "SENTINEL", "master", masterName)
-	err := rClient.Process(context.TODO(), cmd)
+func (c *client) GetSentinelMonitor(ctx context.Context, ip, port, masterName, password string) (string, string, error) {
+	rClient := c.getClient(net.JoinHostPort(ip, port), password)
+	cmd := rediscli.NewSliceCmd(ctx, "SENTINEL", "master", masterName)
+	err := rClient.Process(ctx, cmd)
 	if err != nil {
 		c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, ip, metrics.GET_SENTINEL_MONITOR, metrics.FAIL, getRedisError(err))
 		return "", "", err
@@ -310,37 +584,41 @@ func (c *client) GetSentinelMonitor(ip string) (string, string, error) {
 	return masterIP, masterPort, nil
 }
 
-func (c *client) SetCustomSentinelConfig(ip string, configs []string) error {
-	options := &rediscli.Options{
-		Addr:     net.JoinHostPort(ip, sentinelPort),
-		Password: "",
-		DB:       0,
-	}
-	rClient := rediscli.NewClient(options)
-	defer rClient.Close()
+func (c *client) SetCustomSentinelConfig(ctx context.Context, ip, port, masterName string, configs []string, password string) error {
+	rClient := c.getClient(net.JoinHostPort(ip, port), password)
 
 	for _, config := range configs {
 		param, value, err := c.getConfigParameters(config)
 		if err != nil {
 			return err
 		}
-		if err := c.applySentinelConfig(param, value, rClient); err != nil {
+		if err := c.applySentinelConfig(ctx, masterName, param, value, rClient); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func (c *client) SentinelCheckQuorum(ip string) error {
+// SetSentinelAnnounce sets the address the sentinel announces to the other sentinels and its clients
+func (c *client) SetSentinelAnnounce(ctx context.Context, ip, port, announceIP, announcePort, password string) error {
+	rClient := c.getClient(net.JoinHostPort(ip, port), password)
 
-	options := &rediscli.Options{
-		Addr:     net.JoinHostPort(ip, sentinelPort),
-		Password: "",
-		DB:       0,
+	for parameter, value := range map[string]string{"announce-ip": announceIP, "announce-port": announcePort} {
+		cmd := rediscli.NewStatusCmd(ctx, "SENTINEL", "config", "set", parameter, value)
+		if err := rClient.Process(ctx, cmd); err != nil {
+			c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, ip, metrics.APPLY_SENTINEL_CONFIG, metrics.FAIL, getRedisError(err))
+			return err
+		}
 	}
-	rClient := rediscli.NewSentinelClient(options)
-	defer rClient.Close()
-	cmd := rClient.CkQuorum(context.TODO(), masterName)
+	c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, ip, metrics.APPLY_SENTINEL_CONFIG, metrics.SUCCESS, metrics.NOT_APPLICABLE)
+	return nil
+}
+
+func (c *client) SentinelCheckQuorum(ctx context.Context, ip, port, masterName, password string) error {
+
+	rClient := c.getClient(net.JoinHostPort(ip, port), password)
+	cmd := rediscli.NewStringCmd(ctx, "SENTINEL", "ckquorum", masterName)
+	_ = rClient.Process(ctx, cmd)
 	res, err := cmd.Result()
 
 	if err != nil {
@@ -372,14 +650,8 @@ func (c *client) SentinelCheckQuorum(ip string) error {
 	}
 
 }
-func (c *client) SetCustomRedisConfig(ip string, port string, configs []string, password string) error {
-	options := &rediscli.Options{
-		Addr:     net.JoinHostPort(ip, port),
-		Password: password,
-		DB:       0,
-	}
-	rClient := rediscli.NewClient(options)
-	defer rClient.Close()
+func (c *client) SetCustomRedisConfig(ctx context.Context, ip string, port string, configs []string, password string) error {
+	rClient := c.getClient(net.JoinHostPort(ip, port), password)
 
 	for _, config := range configs {
 		param, value, err := c.getConfigParameters(config)
@@ -391,15 +663,15 @@ func (c *client) SetCustomRedisConfig(ip string, port string, configs []string,
 		if strings.TrimSpace(param) == "" {
 			continue
 		}
-		if err := c.applyRedisConfig(param, value, rClient); err != nil {
+		if err := c.applyRedisConfig(ctx, param, value, rClient); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func (c *client) applyRedisConfig(parameter string, value string, rClient *rediscli.Client) error {
-	result := rClient.ConfigSet(context.TODO(), parameter, value)
+func (c *client) applyRedisConfig(ctx context.Context, parameter string, value string, rClient *rediscli.Client) error {
+	result := rClient.ConfigSet(ctx, parameter, value)
 	if nil != result.Err() {
 		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, strings.Split(rClient.Options().Addr, ":")[0], metrics.APPLY_REDIS_CONFIG, metrics.FAIL, getRedisError(result.Err()))
 		return result.Err()
@@ -408,9 +680,9 @@ func (c *client) applyRedisConfig(parameter string, value string, rClient *redis
 	return result.Err()
 }
 
-func (c *client) applySentinelConfig(parameter string, value string, rClient *rediscli.Client) error {
-	cmd := rediscli.NewStatusCmd(context.TODO(), "SENTINEL", "set", masterName, parameter, value)
-	err := rClient.Process(context.TODO(), cmd)
+func (c *client) applySentinelConfig(ctx context.Context, masterName string, parameter string, value string, rClient *rediscli.Client) error {
+	cmd := rediscli.NewStatusCmd(ctx, "SENTINEL", "set", masterName, parameter, value)
+	err := rClient.Process(ctx, cmd)
 	if err != nil {
 		c.metricsRecorder.RecordRedisOperation(metrics.KIND_SENTINEL, strings.Split(rClient.Options().Addr, ":")[0], metrics.APPLY_SENTINEL_CONFIG, metrics.FAIL, getRedisError(err))
 		return err
@@ -430,15 +702,9 @@ func (c *client) getConfigParameters(config string) (parameter string, value str
 	return s[0], strings.Join(s[1:], " "), nil
 }
 
-func (c *client) SlaveIsReady(ip, port, password string) (bool, error) {
-	options := &rediscli.Options{
-		Addr:     net.JoinHostPort(ip, port),
-		Password: password,
-		DB:       0,
-	}
-	rClient := rediscli.NewClient(options)
-	defer rClient.Close()
-	info, err := rClient.Info(context.TODO(), "replication").Result()
+func (c *client) SlaveIsReady(ctx context.Context, ip, port, password string) (bool, error) {
+	rClient := c.getClient(net.JoinHostPort(ip, port), password)
+	info, err := rClient.Info(ctx, "replication").Result()
 	if err != nil {
 		c.metricsRecorder.RecordRedisOperation(metrics.KIND_REDIS, strings.Split(rClient.Options().Addr, ":")[0], metrics.SLAVE_IS_READY, metrics.FAIL, getRedisError(err))
 		return false, err
@@ -0,0 +1,47 @@
+package redis
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseReplicationInfo(t *testing.T) {
+	tests := []struct {
+		name     string
+		info     string
+		expected ReplicationInfo
+	}{
+		{
+			name:     "master",
+			info:     "# Replication\r\nrole:master\r\nconnected_slaves:1\r\nslave0:ip=1.1.1.1,port=6379,state=online,offset=90,lag=0\r\nmaster_repl_offset:100\r\n",
+			expected: ReplicationInfo{Role: "master", Offset: 100},
+		},
+		{
+			name: "replica",
+			info: "# Clients\r\nconnected_clients:12\r\n\r\n# Memory\r\nused_memory:1048576\r\nused_memory_human:1.00M\r\n\r\n# Replication\r\nrole:slave\r\nmaster_host:0.0.0.0\r\nmaster_port:6379\r\nmaster_link_status:up\r\nslave_repl_offset:90\r\nmaster_repl_offset:90\r\n",
+			expected: ReplicationInfo{
+				Role:             "slave",
+				MasterHost:       "0.0.0.0",
+				MasterPort:       "6379",
+				MasterLinkStatus: "up",
+				Offset:           90,
+				UsedMemory:       1048576,
+				ConnectedClients: 12,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, parseReplicationInfo(test.info))
+		})
+	}
+}
+
+func TestSentinelFields(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(map[string]string{"ip": "0.0.0.0", "port": "6379"}, sentinelFields([]interface{}{"ip", "0.0.0.0", "port", "6379"}))
+	assert.Empty(sentinelFields("unexpected"))
+}
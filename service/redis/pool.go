@@ -0,0 +1,159 @@
+package redis
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"sync"
+	"time"
+
+	rediscli "github.com/go-redis/redis/v8"
+)
+
+// Config is the configuration of the connections to redis and sentinel
+type Config struct {
+	// DialTimeout is the maximum time to establish a connection
+	DialTimeout time.Duration
+	// ReadTimeout is the maximum time to wait for the reply of a command
+	ReadTimeout time.Duration
+	// WriteTimeout is the maximum time to send a command
+	WriteTimeout time.Duration
+	// PoolSize is the maximum number of connections kept for every redis and sentinel
+	PoolSize int
+	// PoolIdleTimeout is the time after which an unused connection, or the pool of a redis
+	// or sentinel no longer used, are closed
+	PoolIdleTimeout time.Duration
+	// Dialer, when set, establishes the connections instead of dialing the addresses directly, as
+	// needed to reach the pods from outside of the cluster. It isn't used for TLS connections
+	Dialer func(ctx context.Context, network, addr string) (net.Conn, error)
+}
+
+// DefaultConfig is the configuration used when none is given
+var DefaultConfig = Config{
+	DialTimeout:     5 * time.Second,
+	ReadTimeout:     3 * time.Second,
+	WriteTimeout:    3 * time.Second,
+	PoolSize:        2,
+	PoolIdleTimeout: 5 * time.Minute,
+}
+
+type pool struct {
+	client   *rediscli.Client
+	lastUsed time.Time
+}
+
+// pools caches a connection pool by address and password, so the clients of a password being rotated and of the new
+// one are used side by side. Pools not used for the idle timeout are closed, so the ones of deleted pods and of
+// previous passwords don't pile up
+type pools struct {
+	mutex       sync.Mutex
+	idleTimeout time.Duration
+	lastEvicted time.Time
+	// byAddr holds the pools of every address by password
+	byAddr map[string]map[string]*pool
+}
+
+func newPools(idleTimeout time.Duration) *pools {
+	return &pools{
+		idleTimeout: idleTimeout,
+		lastEvicted: time.Now(),
+		byAddr:      map[string]map[string]*pool{},
+	}
+}
+
+// get returns the pool for the given address and password, creating it when missing, along with the stats of all the
+// pools of the address and the addresses left without pools by the eviction
+func (p *pools) get(addr, password string, create func() *rediscli.Client) (*rediscli.Client, rediscli.PoolStats, []string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	now := time.Now()
+	evicted := []string{}
+	if p.idleTimeout > 0 && now.Sub(p.lastEvicted) > p.idleTimeout {
+		for a, byPassword := range p.byAddr {
+			for pw, pl := range byPassword {
+				if now.Sub(pl.lastUsed) > p.idleTimeout {
+					pl.client.Close()
+					delete(byPassword, pw)
+				}
+			}
+			if len(byPassword) == 0 {
+				evicted = append(evicted, a)
+				delete(p.byAddr, a)
+			}
+		}
+		p.lastEvicted = now
+	}
+
+	byPassword, ok := p.byAddr[addr]
+	if !ok {
+		byPassword = map[string]*pool{}
+		p.byAddr[addr] = byPassword
+	}
+	pl, ok := byPassword[password]
+	if !ok {
+		pl = &pool{client: create()}
+		byPassword[password] = pl
+	}
+	pl.lastUsed = now
+
+	stats := rediscli.PoolStats{}
+	for _, other := range byPassword {
+		s := other.client.PoolStats()
+		stats.Hits += s.Hits
+		stats.Misses += s.Misses
+		stats.Timeouts += s.Timeouts
+		stats.TotalConns += s.TotalConns
+		stats.IdleConns += s.IdleConns
+		stats.StaleConns += s.StaleConns
+	}
+	return pl.client, stats, evicted
+}
+
+// newClient returns a client of the given redis or sentinel, tracing the commands sent to it
+func (c *client) newClient(addr, password string, tlsConfig *tls.Config) *rediscli.Client {
+	rClient := rediscli.NewClient(c.newOptions(addr, password, tlsConfig))
+	rClient.AddHook(newTracingHook(addr))
+	return rClient
+}
+
+func (c *client) newOptions(addr, password string, tlsConfig *tls.Config) *rediscli.Options {
+	options := &rediscli.Options{
+		Addr:         addr,
+		Password:     password,
+		DB:           0,
+		TLSConfig:    tlsConfig,
+		DialTimeout:  c.config.DialTimeout,
+		ReadTimeout:  c.config.ReadTimeout,
+		WriteTimeout: c.config.WriteTimeout,
+		PoolSize:     c.config.PoolSize,
+		IdleTimeout:  c.config.PoolIdleTimeout,
+	}
+	if tlsConfig == nil {
+		options.Dialer = c.config.Dialer
+	}
+	return options
+}
+
+// getClient returns the pooled client of the given redis or sentinel. Clients are shared, they must not be closed
+func (c *client) getClient(addr, password string) *rediscli.Client {
+	// the metrics of the pool are labelled by address, they add up the pools of every password
+	rClient, stats, evicted := c.pools.get(addr, password, func() *rediscli.Client {
+		return c.newClient(addr, password, nil)
+	})
+	for _, e := range evicted {
+		ip, port := splitAddr(e)
+		c.metricsRecorder.DeleteRedisPoolStats(ip, port)
+	}
+	ip, port := splitAddr(addr)
+	c.metricsRecorder.SetRedisPoolStats(ip, port, stats.Hits, stats.Misses, stats.Timeouts, stats.TotalConns, stats.IdleConns, stats.StaleConns)
+	return rClient
+}
+
+func splitAddr(addr string) (string, string) {
+	ip, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr, ""
+	}
+	return ip, port
+}
@@ -0,0 +1,53 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/spotahome/redis-operator/metrics"
+)
+
+func TestGetClient(t *testing.T) {
+	assert := assert.New(t)
+
+	c := New(metrics.Dummy, DefaultConfig).(*client)
+
+	first := c.getClient("0.0.0.0:6379", "pass")
+	assert.Same(first, c.getClient("0.0.0.0:6379", "pass"), "the pool of an address must be reused")
+	assert.NotSame(first, c.getClient("0.0.0.0:26379", "pass"))
+
+	rotated := c.getClient("0.0.0.0:6379", "newpass")
+	assert.NotSame(first, rotated, "a new password must get new connections")
+	assert.Same(first, c.getClient("0.0.0.0:6379", "pass"), "the pool of the previous password must be kept while in use")
+	assert.Same(rotated, c.getClient("0.0.0.0:6379", "newpass"))
+	assert.Len(c.pools.byAddr, 2)
+	assert.Len(c.pools.byAddr["0.0.0.0:6379"], 2)
+	assert.NotEqual("redis: client is closed", first.Ping(context.Background()).Err().Error(), "the pool of the previous password must not be closed while in use")
+
+	options := first.Options()
+	assert.Equal(DefaultConfig.DialTimeout, options.DialTimeout)
+	assert.Equal(DefaultConfig.ReadTimeout, options.ReadTimeout)
+	assert.Equal(DefaultConfig.WriteTimeout, options.WriteTimeout)
+	assert.Equal(DefaultConfig.PoolSize, options.PoolSize)
+}
+
+func TestGetClientEvictsIdlePools(t *testing.T) {
+	assert := assert.New(t)
+
+	config := DefaultConfig
+	config.PoolIdleTimeout = 10 * time.Millisecond
+	c := New(metrics.Dummy, config).(*client)
+
+	idle := c.getClient("0.0.0.0:6379", "")
+	previous := c.getClient("1.1.1.1:6379", "previous")
+	time.Sleep(20 * time.Millisecond)
+	c.getClient("1.1.1.1:6379", "")
+
+	assert.Len(c.pools.byAddr, 1)
+	assert.Len(c.pools.byAddr["1.1.1.1:6379"], 1, "the idle pool of a previous password must be evicted")
+	assert.Equal("redis: client is closed", previous.Ping(context.Background()).Err().Error())
+	assert.NotSame(idle, c.getClient("0.0.0.0:6379", ""), "an evicted pool must be recreated")
+}
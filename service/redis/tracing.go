@@ -0,0 +1,90 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+
+	rediscli "github.com/go-redis/redis/v8"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/spotahome/redis-operator/tracing"
+)
+
+// subcommands are the commands whose first argument is part of their name
+var subcommands = map[string]bool{
+	"acl":      true,
+	"client":   true,
+	"cluster":  true,
+	"command":  true,
+	"config":   true,
+	"sentinel": true,
+}
+
+// tracingHook traces every command sent to a redis or sentinel. Only the names of the commands are recorded,
+// their arguments may hold passwords
+type tracingHook struct {
+	attributes []attribute.KeyValue
+}
+
+func newTracingHook(addr string) *tracingHook {
+	ip, port := splitAddr(addr)
+	attributes := []attribute.KeyValue{semconv.DBSystemRedis, semconv.ServerAddress(ip)}
+	if p, err := strconv.Atoi(port); err == nil {
+		attributes = append(attributes, semconv.ServerPort(p))
+	}
+	return &tracingHook{attributes: attributes}
+}
+
+func (h *tracingHook) BeforeProcess(ctx context.Context, cmd rediscli.Cmder) (context.Context, error) {
+	name := commandName(cmd)
+	ctx, _ = tracing.Start(ctx, "redis "+name, append(h.attributes, semconv.DBOperation(name))...)
+	return ctx, nil
+}
+
+func (h *tracingHook) AfterProcess(ctx context.Context, cmd rediscli.Cmder) error {
+	tracing.End(trace.SpanFromContext(ctx), commandError(cmd))
+	return nil
+}
+
+func (h *tracingHook) BeforeProcessPipeline(ctx context.Context, cmds []rediscli.Cmder) (context.Context, error) {
+	names := []string{}
+	for _, cmd := range cmds {
+		names = append(names, commandName(cmd))
+	}
+	ctx, _ = tracing.Start(ctx, "redis pipeline", append(h.attributes, semconv.DBOperation(strings.Join(names, " ")))...)
+	return ctx, nil
+}
+
+func (h *tracingHook) AfterProcessPipeline(ctx context.Context, cmds []rediscli.Cmder) error {
+	var err error
+	for _, cmd := range cmds {
+		if err = commandError(cmd); err != nil {
+			break
+		}
+	}
+	tracing.End(trace.SpanFromContext(ctx), err)
+	return nil
+}
+
+// commandName returns the name of the given command, along with its subcommand
+func commandName(cmd rediscli.Cmder) string {
+	name := cmd.Name()
+	if args := cmd.Args(); subcommands[name] && len(args) > 1 {
+		if subcommand, ok := args[1].(string); ok {
+			return name + " " + strings.ToLower(subcommand)
+		}
+	}
+	return name
+}
+
+// commandError returns the error of the given command, but the missing key or field
+func commandError(cmd rediscli.Cmder) error {
+	if err := cmd.Err(); err != nil && !errors.Is(err, rediscli.Nil) {
+		return err
+	}
+	return nil
+}
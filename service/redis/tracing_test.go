@@ -0,0 +1,70 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	rediscli "github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/spotahome/redis-operator/metrics"
+)
+
+func TestCommandName(t *testing.T) {
+	tests := []struct {
+		name     string
+		cmd      rediscli.Cmder
+		expected string
+	}{
+		{
+			name:     "command",
+			cmd:      rediscli.NewStringCmd(context.Background(), "info", "replication"),
+			expected: "info",
+		},
+		{
+			name:     "subcommand",
+			cmd:      rediscli.NewStringSliceCmd(context.Background(), "SENTINEL", "get-master-addr-by-name", "mymaster"),
+			expected: "sentinel get-master-addr-by-name",
+		},
+		{
+			name:     "subcommand missing",
+			cmd:      rediscli.NewStatusCmd(context.Background(), "config"),
+			expected: "config",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, commandName(test.cmd))
+		})
+	}
+}
+
+func TestTracingHook(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	provider := otel.GetTracerProvider()
+	defer otel.SetTracerProvider(provider)
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	c := New(metrics.Dummy, Config{DialTimeout: 100 * time.Millisecond}).(*client)
+	rClient := c.newClient("127.0.0.1:1", "", nil)
+	defer rClient.Close()
+	err := rClient.Do(context.Background(), "CONFIG", "SET", "masterauth", "secret").Err()
+	require.Error(err)
+
+	spans := recorder.Ended()
+	require.Len(spans, 1)
+	assert.Equal("redis config set", spans[0].Name())
+	assert.Equal(codes.Error, spans[0].Status().Code)
+	for _, attribute := range spans[0].Attributes() {
+		assert.NotContains(attribute.Value.Emit(), "secret")
+	}
+}
@@ -80,11 +80,11 @@ func TestRedisFailover(t *testing.T) {
 	}
 
 	// Kubernetes clients.
-	k8sClient, customClient, aeClientset, err := utils.CreateKubernetesClients(flags)
+	k8sClient, customClient, aeClientset, dynamicClient, err := utils.CreateKubernetesClients(flags)
 	require.NoError(err)
 
 	// Create the redis clients
-	redisClient := redis.New(metrics.Dummy)
+	redisClient := redis.New(metrics.Dummy, redis.DefaultConfig)
 
 	clients := clients{
 		k8sClient:   k8sClient,
@@ -94,7 +94,7 @@ func TestRedisFailover(t *testing.T) {
 	}
 
 	// Create kubernetes service.
-	k8sservice := k8s.New(k8sClient, customClient, aeClientset, log.Dummy, metrics.Dummy)
+	k8sservice := k8s.New(k8sClient, customClient, aeClientset, dynamicClient, log.Dummy, metrics.Dummy)
 
 	// Prepare namespace
 	prepErr := clients.prepareNS()
@@ -104,7 +104,7 @@ func TestRedisFailover(t *testing.T) {
 	time.Sleep(15 * time.Second)
 
 	// Create operator and run.
-	redisfailoverOperator, err := redisfailover.New(redisfailover.Config{}, k8sservice, k8sClient, namespace, redisClient, metrics.Dummy, log.Dummy)
+	redisfailoverOperator, err := redisfailover.New(redisfailover.Config{}, k8sservice, k8sClient, namespace, redisClient, metrics.Dummy, redisfailover.NewStatus(k8sClient, namespace), log.Dummy)
 	require.NoError(err)
 
 	go func() {
@@ -223,7 +223,7 @@ func (c *clients) testRedisMaster(t *testing.T) {
 
 	for _, pod := range redisPodList.Items {
 		ip := pod.Status.PodIP
-		if ok, _ := c.redisClient.IsMaster(ip, "6379", testPass); ok {
+		if ok, _ := c.redisClient.IsMaster(context.TODO(), ip, "6379", testPass); ok {
 			masters = append(masters, ip)
 		}
 	}
@@ -246,7 +246,7 @@ func (c *clients) testSentinelMonitoring(t *testing.T) {
 
 	for _, pod := range sentinelPodList.Items {
 		ip := pod.Status.PodIP
-		master, _, _ := c.redisClient.GetSentinelMonitor(ip)
+		master, _, _ := c.redisClient.GetSentinelMonitor(context.TODO(), ip, "26379", "master0", "")
 		masters = append(masters, master)
 	}
 
@@ -254,7 +254,7 @@ func (c *clients) testSentinelMonitoring(t *testing.T) {
 		assert.Equal(masters[0], masterIP, "all master ip monitoring should equal")
 	}
 
-	isMaster, err := c.redisClient.IsMaster(masters[0], "6379", testPass)
+	isMaster, err := c.redisClient.IsMaster(context.TODO(), masters[0], "6379", testPass)
 	assert.NoError(err)
 	assert.True(isMaster, "Sentinel should monitor the Redis master")
 }
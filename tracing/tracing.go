@@ -0,0 +1,93 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	serviceName = "redis-operator"
+	tracerName  = "github.com/spotahome/redis-operator"
+)
+
+// Exporter is where the spans are sent to
+type Exporter string
+
+const (
+	// NoExporter disables the tracing
+	NoExporter Exporter = "none"
+	// StdoutExporter writes the spans to the standard output, to trace locally
+	StdoutExporter Exporter = "stdout"
+	// OTLPExporter sends the spans to an OpenTelemetry collector over gRPC
+	OTLPExporter Exporter = "otlp"
+)
+
+// Config is the configuration of the tracing
+type Config struct {
+	Exporter Exporter
+	// Endpoint is the address of the collector of the OTLP exporter. When empty, the OTEL_EXPORTER_OTLP_*
+	// environment variables apply, localhost:4317 by default
+	Endpoint string
+	// Insecure disables the TLS of the connection to the collector
+	Insecure bool
+}
+
+// Setup installs the tracer provider of the given configuration. The returned function flushes the spans
+// not exported yet and stops the exporter
+func Setup(ctx context.Context, config Config) (func(context.Context) error, error) {
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch config.Exporter {
+	case NoExporter, "":
+		return func(context.Context) error { return nil }, nil
+	case StdoutExporter:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	case OTLPExporter:
+		options := []otlptracegrpc.Option{}
+		if config.Endpoint != "" {
+			options = append(options, otlptracegrpc.WithEndpoint(config.Endpoint))
+		}
+		if config.Insecure {
+			options = append(options, otlptracegrpc.WithInsecure())
+		}
+		exporter, err = otlptracegrpc.New(ctx, options...)
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter %q, expected none, stdout or otlp", config.Exporter)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not create the %s tracing exporter: %w", config.Exporter, err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(serviceName)))
+	if err != nil {
+		return nil, err
+	}
+	provider := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter), sdktrace.WithResource(res))
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// Start starts a span as a child of the one of the given context, a no-op one while the tracing is disabled
+func Start(ctx context.Context, name string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attributes...))
+}
+
+// End ends the given span, marking it as failed when there is an error
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,82 @@
+package tracing_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/spotahome/redis-operator/tracing"
+)
+
+func TestSetup(t *testing.T) {
+	tests := []struct {
+		name     string
+		exporter tracing.Exporter
+		expErr   bool
+	}{
+		{
+			name:     "No exporter",
+			exporter: "",
+		},
+		{
+			name:     "Disabled",
+			exporter: tracing.NoExporter,
+		},
+		{
+			name:     "Stdout",
+			exporter: tracing.StdoutExporter,
+		},
+		{
+			name:     "Unknown exporter",
+			exporter: "zipkin",
+			expErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+			provider := otel.GetTracerProvider()
+			defer otel.SetTracerProvider(provider)
+
+			shutdown, err := tracing.Setup(context.Background(), tracing.Config{Exporter: test.exporter})
+			if test.expErr {
+				assert.Error(err)
+				return
+			}
+			assert.NoError(err)
+			assert.NoError(shutdown(context.Background()))
+		})
+	}
+}
+
+func TestStartEnd(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	provider := otel.GetTracerProvider()
+	defer otel.SetTracerProvider(provider)
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	ctx, parent := tracing.Start(context.Background(), "parent", attribute.String("namespace", "test"))
+	_, child := tracing.Start(ctx, "child")
+	tracing.End(child, errors.New("wrong"))
+	tracing.End(parent, nil)
+
+	spans := recorder.Ended()
+	require.Len(spans, 2)
+	assert.Equal("child", spans[0].Name())
+	assert.Equal(codes.Error, spans[0].Status().Code)
+	assert.Equal(parent.SpanContext().SpanID(), spans[0].Parent().SpanID())
+	assert.Equal("parent", spans[1].Name())
+	assert.Equal(codes.Unset, spans[1].Status().Code)
+	assert.Contains(spans[1].Attributes(), attribute.String("namespace", "test"))
+}